@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"gateway/config"
 	api "gateway/internal/api"
@@ -8,8 +9,14 @@ import (
 	"gateway/internal/clients/todos"
 	"gateway/internal/clients/users"
 	"gateway/internal/service"
+	"gateway/pkg/avatar"
+	"gateway/pkg/blobstore"
 	"gateway/pkg/jaeger"
+	"gateway/pkg/jwtutil"
+	"gateway/pkg/loginguard"
+	"gateway/pkg/oidcauth"
 	"gateway/pkg/redis"
+	"gateway/pkg/validator"
 	"github.com/opentracing/opentracing-go"
 	"golang.org/x/sync/errgroup"
 
@@ -21,6 +28,7 @@ type App struct {
 	cfg            *config.Config
 	logger         *zerolog.Logger
 	gatewayService api.GatewayService
+	jwtUtil        *jwtutil.JWTUtil
 	redisManager   *redis.RedisManager
 }
 
@@ -42,12 +50,33 @@ func NewApp(cfg *config.Config) (*App, error) {
 		return nil, fmt.Errorf("[NewApp] redis manager: %w", err)
 	}
 
-	gatewayService := service.NewGatewayService(&cfg.JWT, todosClient, usersClient, redisManager)
+	jwtUtil, err := jwtutil.New(&cfg.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("[NewApp] jwt util: %w", err)
+	}
+
+	blobStore, err := blobstore.New(&cfg.BlobStore)
+	if err != nil {
+		return nil, fmt.Errorf("[NewApp] blob store: %w", err)
+	}
+
+	gatewayService := service.NewGatewayService(
+		jwtUtil,
+		todosClient,
+		usersClient,
+		redisManager,
+		validator.New(&cfg.Validation),
+		loginguard.New(&cfg.LoginGuard, redisManager),
+		oidcauth.New(&cfg.OIDC),
+		blobStore,
+		avatar.New(&cfg.Avatar),
+	)
 
 	return &App{
 		cfg:            cfg,
 		logger:         logger,
 		gatewayService: gatewayService,
+		jwtUtil:        jwtUtil,
 		redisManager:   redisManager,
 	}, nil
 }
@@ -66,7 +95,11 @@ func (a *App) RunAPI() error {
 	group := new(errgroup.Group)
 
 	group.Go(func() error {
-		return rest.RunREST(a.cfg, a.logger, a.gatewayService, a.redisManager)
+		return rest.RunREST(a.cfg, a.logger, a.gatewayService, a.jwtUtil, a.redisManager)
+	})
+
+	group.Go(func() error {
+		return a.jwtUtil.RunRotation(context.Background(), a.logger)
 	})
 
 	if err := group.Wait(); err != nil {
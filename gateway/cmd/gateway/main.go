@@ -3,6 +3,8 @@ package main
 import (
 	"gateway/app"
 	"gateway/config"
+	// база часовых поясов для проверки timezone повторений задач, в образе ее может не быть
+	_ "time/tzdata"
 )
 
 func main() {
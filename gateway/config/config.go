@@ -1,21 +1,35 @@
 package config
 
 import (
+	"gateway/pkg/avatar"
+	"gateway/pkg/blobstore"
+	"gateway/pkg/clientip"
 	"gateway/pkg/jaeger"
 	"gateway/pkg/jwtutil"
 	"gateway/pkg/logging"
+	"gateway/pkg/loginguard"
+	"gateway/pkg/oidcauth"
+	"gateway/pkg/ratelimit"
 	"gateway/pkg/redis"
+	"gateway/pkg/validator"
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
 	App         App                  `envconfig:"APP"`
-	JWT         jwtutil.JWTUtil      `envconfig:"JWT"`
+	JWT         jwtutil.Config       `envconfig:"JWT"`
 	Logging     logging.LoggerConfig `envconfig:"LOG"`
 	Jaeger      jaeger.JaegerConfig  `envconfig:"JAEGER"`
 	UsersClient UsersClient          `envconfig:"USERS"`
 	TodosClient TodosClient          `envconfig:"TODOS"`
 	RedisConfig redis.Config         `envconfig:"REDIS"`
+	Validation  validator.Config     `envconfig:"VALIDATION"`
+	LoginGuard  loginguard.Config    `envconfig:"LOGIN_GUARD"`
+	RateLimit   ratelimit.Config     `envconfig:"RATE_LIMIT"`
+	OIDC        oidcauth.Config      `envconfig:"OIDC"`
+	BlobStore   blobstore.Config     `envconfig:"BLOB"`
+	Avatar      avatar.Config        `envconfig:"AVATAR"`
+	ClientIP    clientip.Config      `envconfig:"CLIENT_IP"`
 }
 
 type App struct {
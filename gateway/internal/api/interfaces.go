@@ -3,22 +3,77 @@ package rest
 import (
 	"context"
 	"gateway/internal/models"
+	"gateway/pkg/blobstore"
+	"gateway/pkg/jwtutil"
+	"github.com/google/uuid"
+	"io"
 )
 
 type GatewayService interface {
 	RegisterUser(ctx context.Context, newUser *models.CreateUserDTO) (int, error)
 	UpdateUser(ctx context.Context, updatedUser *models.UserDTO) (*models.UserDTO, error)
 	UpdatePassword(ctx context.Context, updatePassword *models.UpdateUserPasswordDTO) error
-	DeleteUser(ctx context.Context, userID int) error
+	DeleteUser(ctx context.Context, userID, version int) (*models.UserDTO, error)
+	DeactivateUser(ctx context.Context, userID int) error
+	ExportAccount(ctx context.Context) (*models.AccountExportDTO, error)
 	GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error)
+	UploadAvatar(ctx context.Context, userID int, image io.Reader) (*models.UserDTO, error)
+	GetAvatar(ctx context.Context, userID int, size string) (*blobstore.Object, error)
+	DeleteAvatar(ctx context.Context, userID int) error
+	RequestEmailChange(ctx context.Context, request *models.EmailChangeRequestDTO) error
+	ConfirmEmailChange(ctx context.Context, token string) (*models.UserDTO, error)
 	Login(ctx context.Context, login *models.UserLoginDTO) (*models.UserTokens, error)
-	Refresh(ctx context.Context, refresh string, access string) (*models.UserTokens, error)
+	LoginMFA(ctx context.Context, request *models.MFALoginDTO) (*models.UserTokens, error)
+	StartOIDCLogin(ctx context.Context, providerName string) (*models.OIDCLoginDTO, error)
+	CompleteOIDCLogin(ctx context.Context, providerName, code, state string) (*models.UserTokens, error)
+	CreateAPIKey(ctx context.Context, request *models.CreateAPIKeyDTO) (*models.APIKeyDTO, error)
+	ListAPIKeys(ctx context.Context) ([]*models.APIKeyDTO, error)
+	RevokeAPIKey(ctx context.Context, keyID int) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKeyPrincipalDTO, error)
+	Refresh(ctx context.Context, refresh string) (*models.UserTokens, error)
 	InvalidateTokensForUser(ctx context.Context, userID int) error
 	InvalidateToken(ctx context.Context, userID int, access, refresh string) error
+	ListSessions(ctx context.Context) ([]*models.SessionDTO, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	EnrollTOTP(ctx context.Context) (*models.TOTPEnrollmentDTO, error)
+	ConfirmTOTP(ctx context.Context, request *models.MFACodeDTO) (*models.RecoveryCodesDTO, error)
+	DisableTOTP(ctx context.Context, request *models.MFACodeDTO) error
+	ListUsers(ctx context.Context, limit, offset int) ([]*models.UserDTO, error)
+	UpdateUserRole(ctx context.Context, request *models.UpdateUserRoleDTO) error
+	UnlockUser(ctx context.Context, userID int) error
+	GetJWKS(ctx context.Context) jwtutil.JSONWebKeySet
 
-	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (int, error)
-	UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (int, error)
-	GetToDos(ctx context.Context, todoID int) (*models.TodoDTO, error)
-	GetToDo(ctx context.Context, todoID int) (*models.TodoDTO, error)
-	DeleteToDo(ctx context.Context, todoID int) error
+	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (uuid.UUID, error)
+	UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (uuid.UUID, error)
+	GetToDos(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error)
+	GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error)
+	DeleteToDo(ctx context.Context, todoID uuid.UUID, version int) error
+	AddComment(ctx context.Context, todoID uuid.UUID, comment *models.CommentRequestDTO) (*models.CommentDTO, error)
+	EditComment(ctx context.Context, todoID, commentID uuid.UUID, comment *models.CommentRequestDTO) (*models.CommentDTO, error)
+	DeleteComment(ctx context.Context, todoID, commentID uuid.UUID) error
+	ListComments(ctx context.Context, todoID uuid.UUID, limit, offset int) (*models.CommentListDTO, error)
+	ListTodoHistory(ctx context.Context, todoID uuid.UUID, limit, offset int) (*models.TodoEventListDTO, error)
+	ListToDos(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoDTO, error)
+	BulkUpdateTodos(ctx context.Context, request *models.BulkUpdateTodosDTO) (*models.BulkUpdateTodosResultDTO, error)
+	ExportTodos(ctx context.Context, filter *models.ListTodosDTO, send func(todo *models.TodoExportDTO) error) error
+	ImportTodos(ctx context.Context, request *models.ImportTodosDTO) (*models.ImportTodosResultDTO, error)
+	CreateLabel(ctx context.Context, workspaceID *uuid.UUID, label *models.LabelRequestDTO) (*models.LabelDTO, error)
+	UpdateLabel(ctx context.Context, labelID uuid.UUID, label *models.LabelRequestDTO) (*models.LabelDTO, error)
+	DeleteLabel(ctx context.Context, labelID uuid.UUID) error
+	ListLabels(ctx context.Context, workspaceID *uuid.UUID) ([]*models.LabelDTO, error)
+	AddTodoLabel(ctx context.Context, todoID, labelID uuid.UUID) error
+	RemoveTodoLabel(ctx context.Context, todoID, labelID uuid.UUID) error
+	SearchTodos(ctx context.Context, request *models.SearchTodosDTO) ([]*models.SearchResultDTO, error)
+	ListTodoTree(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoNodeDTO, error)
+	AddTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) error
+	RemoveTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) error
+	CreateWorkspace(ctx context.Context, workspace *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error)
+	UpdateWorkspace(ctx context.Context, workspaceID uuid.UUID, workspace *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error)
+	DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) error
+	ListWorkspaces(ctx context.Context) ([]*models.WorkspaceDTO, error)
+	ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMemberDTO, error)
+	UpdateWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int, member *models.UpdateWorkspaceMemberRequestDTO) (*models.WorkspaceMemberDTO, error)
+	RemoveWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) error
+	InviteWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, invitation *models.InviteWorkspaceMemberRequestDTO) (*models.WorkspaceInvitationDTO, error)
+	AcceptWorkspaceInvitation(ctx context.Context, accept *models.AcceptWorkspaceInvitationRequestDTO) (*models.WorkspaceMemberDTO, error)
 }
@@ -0,0 +1,181 @@
+package rest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	appErrors "gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strconv"
+)
+
+// DeactivateUser godoc
+// @Summary Deactivate a user
+// @Description Disables the account and ends all of its sessions. The next successful login reactivates it.
+// @Tags users, v1
+// @Param id path int true "User ID"
+// @Success 200
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/{id}/deactivate [post]
+func (h *GatewayHandler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DeactivateUser")
+	defer span.Finish()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeactivateUser] get id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.DeactivateUser(ctx, userID); err != nil {
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeactivateUser] deactivate user: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// ExportAccount godoc
+// @Summary Export account data
+// @Description Returns the profile, todos, sessions and API keys of the current user as a downloadable file.
+// @Description format=zip returns an archive with one JSON file per section.
+// @Tags users, v1
+// @Produce json
+// @Produce application/zip
+// @Param format query string false "json (default) or zip"
+// @Success 200 {object} models.AccountExportDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/me/export [get]
+func (h *GatewayHandler) ExportAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ExportAccount")
+	defer span.Finish()
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = models.ExportFormatJSON
+	}
+
+	if format != models.ExportFormatJSON && format != models.ExportFormatZIP {
+		h.ErrorValidation(w, validator.NewValidationError(validator.FieldViolation{
+			Field:   "format",
+			Message: "must be one of: json, zip",
+		}))
+		return
+	}
+
+	export, err := h.gatewayService.ExportAccount(ctx)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ExportAccount] export: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	var (
+		body        []byte
+		contentType string
+	)
+	if format == models.ExportFormatZIP {
+		body, err = exportArchive(export)
+		contentType = "application/zip"
+	} else {
+		body, err = json.MarshalIndent(export, "", "  ")
+		contentType = "application/json"
+	}
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ExportAccount] encode %s: %s", format, err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	fileName := fmt.Sprintf("account-%d-%s.%s", export.Profile.ID, export.ExportedAt.Format("20060102"), format)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(body); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ExportAccount] write: %s", err)
+	}
+}
+
+// exportArchive упаковывает каждый раздел выгрузки в отдельный json файл архива
+func exportArchive(export *models.AccountExportDTO) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{name: "profile.json", data: export.Profile},
+		{name: "todos.json", data: export.Todos},
+		{name: "sessions.json", data: export.Sessions},
+		{name: "api_keys.json", data: export.APIKeys},
+	}
+
+	buf := new(bytes.Buffer)
+	archive := zip.NewWriter(buf)
+
+	for _, file := range files {
+		f, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create %s: %w", file.name, err)
+		}
+
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, fmt.Errorf("encode %s: %w", file.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
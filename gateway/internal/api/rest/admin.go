@@ -0,0 +1,200 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	appErrors "gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strconv"
+)
+
+// значения пагинации по умолчанию для списка пользователей
+const (
+	defaultUsersLimit = 50
+	maxUsersLimit     = 500
+)
+
+// ListUsers godoc
+// @Summary List users
+// @Description Returns a page of users. Available to admins only.
+// @Tags admin, v1
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size" default(50)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {array} models.UserDTO
+// @Security BearerAuth
+// @Router /v1/admin/users [get]
+func (h *GatewayHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListUsers")
+	defer span.Finish()
+
+	limit, offset, err := parsePagination(r, defaultUsersLimit, maxUsersLimit)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListUsers] parse pagination: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	users, err := h.gatewayService.ListUsers(ctx, limit, offset)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListUsers] list users: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, users)
+}
+
+// UpdateUserRole godoc
+// @Summary Change user's role
+// @Description Changes the role of a user and revokes their tokens. Available to admins only.
+// @Tags admin, v1
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body models.UpdateUserRoleDTO true "New role"
+// @Success 200
+// @Security BearerAuth
+// @Router /v1/admin/users/{id}/role [put]
+func (h *GatewayHandler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UpdateUserRole")
+	defer span.Finish()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateUserRole] get id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	var request = new(models.UpdateUserRoleDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateUserRole] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+	request.ID = userID
+
+	if err := h.gatewayService.UpdateUserRole(ctx, request); err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateUserRole] update role: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// UnlockUser godoc
+// @Summary Unlock user's login
+// @Description Removes the temporary login lock set after failed login attempts. Available to admins only.
+// @Tags admin, v1
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/admin/users/{id}/unlock [post]
+func (h *GatewayHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UnlockUser")
+	defer span.Finish()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UnlockUser] get id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.UnlockUser(ctx, userID); err != nil {
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UnlockUser] unlock: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// parsePagination читает limit и offset из query-параметров
+func parsePagination(r *http.Request, defaultLimit, maxLimit int) (int, int, error) {
+	limit, offset := defaultLimit, 0
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			return 0, 0, errors.New("invalid limit")
+		}
+		limit = value
+	}
+
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return 0, 0, errors.New("invalid offset")
+		}
+		offset = value
+	}
+
+	return limit, offset, nil
+}
@@ -0,0 +1,144 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	appErrors "gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strconv"
+)
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Creates a personal API key for scripts and CI. Send it as "Authorization: ApiKey <key>".
+// @Description The key is returned only in this response, only its hash is stored.
+// @Description Allowed scopes: todos:read, todos:write, users:read.
+// @Tags users, v1
+// @Accept json
+// @Produce json
+// @Param request body models.CreateAPIKeyDTO true "Key name, scopes and optional expiry"
+// @Success 200 {object} models.APIKeyDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/me/api-keys [post]
+func (h *GatewayHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.CreateAPIKey")
+	defer span.Finish()
+
+	var request = new(models.CreateAPIKeyDTO)
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateAPIKey] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	key, err := h.gatewayService.CreateAPIKey(ctx, request)
+	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateAPIKey] create key: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, key)
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Returns the API keys of the current user without the keys themselves.
+// @Tags users, v1
+// @Produce json
+// @Success 200 {array} models.APIKeyDTO
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/me/api-keys [get]
+func (h *GatewayHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListAPIKeys")
+	defer span.Finish()
+
+	keys, err := h.gatewayService.ListAPIKeys(ctx)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListAPIKeys] list keys: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, keys)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Deletes the API key of the current user. Requests with the key are rejected immediately.
+// @Tags users, v1
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/me/api-keys/{id} [delete]
+func (h *GatewayHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RevokeAPIKey")
+	defer span.Finish()
+
+	keyID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RevokeAPIKey] get id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	err = h.gatewayService.RevokeAPIKey(ctx, keyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrForbidden):
+			h.ErrorForbidden(w)
+		case errors.Is(err, appErrors.ErrNotFound):
+			h.ErrorNotFound(w)
+		default:
+			h.logger.Error().
+				Str("requestId", requestId).
+				Msgf("[RevokeAPIKey] revoke key: %s", err)
+			h.ErrorInternalApi(w)
+		}
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
@@ -0,0 +1,211 @@
+package rest
+
+import (
+	"errors"
+	appErrors "gateway/internal/app_errors"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// avatarFormField - поле multipart формы с изображением
+const avatarFormField = "avatar"
+
+// UploadAvatar godoc
+// @Summary Upload an avatar
+// @Description Uploads a PNG, JPEG or GIF image as multipart/form-data in the "avatar" field.
+// @Description The image is cropped to a square and stored as large and small PNG thumbnails.
+// @Tags users, v1
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "User ID"
+// @Param avatar formData file true "Avatar image"
+// @Success 200 {object} models.UserDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/{id}/avatar [put]
+func (h *GatewayHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UploadAvatar")
+	defer span.Finish()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UploadAvatar] get id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// форма читается потоком, размер файла ограничивает сервис, поэтому весь запрос в память не попадает
+	image, err := avatarPart(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UploadAvatar] read form: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	user, err := h.gatewayService.UploadAvatar(ctx, userID, image)
+	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UploadAvatar] upload: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, user)
+}
+
+// GetAvatar godoc
+// @Summary Get an avatar
+// @Description Returns the avatar image of a user.
+// @Tags users, v1
+// @Produce png
+// @Param id path int true "User ID"
+// @Param size query string false "Avatar size: large (default) or small"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/{id}/avatar [get]
+func (h *GatewayHandler) GetAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetAvatar")
+	defer span.Finish()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetAvatar] get id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	object, err := h.gatewayService.GetAvatar(ctx, userID, r.URL.Query().Get("size"))
+	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetAvatar] get avatar: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+	defer object.Close()
+
+	w.Header().Set("Content-Type", object.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	// адрес аватара в профиле меняется при загрузке нового, поэтому ответ можно кэшировать
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, object); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetAvatar] write: %s", err)
+	}
+}
+
+// DeleteAvatar godoc
+// @Summary Delete an avatar
+// @Description Removes the avatar of a user.
+// @Tags users, v1
+// @Param id path int true "User ID"
+// @Success 200
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/{id}/avatar [delete]
+func (h *GatewayHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DeleteAvatar")
+	defer span.Finish()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteAvatar] get id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.DeleteAvatar(ctx, userID); err != nil {
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteAvatar] delete avatar: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// avatarPart находит в multipart форме часть с изображением
+func avatarPart(r *http.Request) (io.Reader, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("no avatar field in form")
+			}
+			return nil, err
+		}
+
+		if part.FormName() == avatarFormField {
+			return part, nil
+		}
+	}
+}
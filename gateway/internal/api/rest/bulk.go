@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"encoding/json"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+// BulkUpdateTodos godoc
+// @Summary Update many todos at once
+// @Description Applies one operation to the todos listed in ids or, instead, to all caller's todos matching filter (at most 500). Operations: reassign to assignee, relabel with add_label_ids/remove_label_ids (personal labels of the caller or workspace labels), complete and delete. Every todo is checked separately: the response holds a result per todo, error is empty on success and is one of not_found, forbidden, assignee_not_member, label_not_in_workspace, version_conflict or blocked otherwise. Every affected assignee gets one email listing all their changed todos.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param request body models.BulkUpdateTodosDTO true "Bulk operation"
+// @Success 200 {object} models.BulkUpdateTodosResultDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/bulk [post]
+func (h *GatewayHandler) BulkUpdateTodos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.BulkUpdateTodos")
+	defer span.Finish()
+
+	request := new(models.BulkUpdateTodosDTO)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	result, err := h.gatewayService.BulkUpdateTodos(ctx, request)
+	if err != nil {
+		h.commentError(w, requestId, "[BulkUpdateTodos] bulk update", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, result)
+}
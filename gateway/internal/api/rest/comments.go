@@ -0,0 +1,225 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+const (
+	defaultCommentsLimit = 50
+	maxCommentsLimit     = 200
+)
+
+// ListComments godoc
+// @Summary List todo comments
+// @Description Returns a page of comments on the todo, oldest first.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param limit query int false "Page size" default(50)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.CommentListDTO
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/{id}/comments [get]
+func (h *GatewayHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListComments")
+	defer span.Finish()
+
+	todoID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListComments] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	limit, offset, err := parsePagination(r, defaultCommentsLimit, maxCommentsLimit)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListComments] parse pagination: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	comments, err := h.gatewayService.ListComments(ctx, todoID, limit, offset)
+	if err != nil {
+		h.commentError(w, requestId, "[ListComments] list comments", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, comments)
+}
+
+// AddComment godoc
+// @Summary Comment a todo
+// @Description Adds a comment to the todo. The creator and the assignee get an email, except the author of the comment.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param comment body models.CommentRequestDTO true "Comment"
+// @Success 200 {object} models.CommentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/{id}/comments [post]
+func (h *GatewayHandler) AddComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.AddComment")
+	defer span.Finish()
+
+	todoID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AddComment] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	comment := new(models.CommentRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(comment); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	created, err := h.gatewayService.AddComment(ctx, todoID, comment)
+	if err != nil {
+		h.commentError(w, requestId, "[AddComment] add comment", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, created)
+}
+
+// EditComment godoc
+// @Summary Edit a comment
+// @Description Changes the text of a comment. Only the author can edit it.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param comment_id path string true "Comment ID"
+// @Param comment body models.CommentRequestDTO true "Comment"
+// @Success 200 {object} models.CommentDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/{id}/comments/{comment_id} [put]
+func (h *GatewayHandler) EditComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.EditComment")
+	defer span.Finish()
+
+	todoID, commentID, err := parseCommentPath(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[EditComment] parse ids from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	comment := new(models.CommentRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(comment); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	edited, err := h.gatewayService.EditComment(ctx, todoID, commentID, comment)
+	if err != nil {
+		h.commentError(w, requestId, "[EditComment] edit comment", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, edited)
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description Deletes a comment. The author can delete own comments, the todo creator and admins can delete any.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 200
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/{id}/comments/{comment_id} [delete]
+func (h *GatewayHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DeleteComment")
+	defer span.Finish()
+
+	todoID, commentID, err := parseCommentPath(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteComment] parse ids from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.DeleteComment(ctx, todoID, commentID); err != nil {
+		h.commentError(w, requestId, "[DeleteComment] delete comment", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// commentError отвечает клиенту по ошибке сервиса комментариев
+func (h *GatewayHandler) commentError(w http.ResponseWriter, requestId, operation string, err error) {
+	var validationErr *validator.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.ErrorValidation(w, validationErr)
+	case errors.Is(err, app_errors.ErrForbidden):
+		h.ErrorForbidden(w)
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+	default:
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("%s: %s", operation, err)
+		h.ErrorInternalApi(w)
+	}
+}
+
+func parseCommentPath(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	vars := mux.Vars(r)
+
+	todoID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	commentID, err := uuid.Parse(vars["comment_id"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return todoID, commentID, nil
+}
@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	appErrors "gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+// RequestEmailChange godoc
+// @Summary Request an email change
+// @Description Stores the new address as pending and sends a confirmation link to it and a notice to the current address.
+// @Description The email of the account changes only after the link is followed.
+// @Tags users, v1
+// @Accept json
+// @Produce json
+// @Param request body models.EmailChangeRequestDTO true "New email"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/me/email [post]
+func (h *GatewayHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RequestEmailChange")
+	defer span.Finish()
+
+	var request = new(models.EmailChangeRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestEmailChange] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.RequestEmailChange(ctx, request); err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed) {
+			h.ErrorUsernameOrEmailAlreadyUsed(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestEmailChange] request change: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm an email change
+// @Description Applies the pending email using the token from the confirmation link. Does not require authentication.
+// @Tags users, v1
+// @Produce json
+// @Param token query string true "Confirmation token"
+// @Success 200 {object} models.UserDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /v1/users/email/confirm [get]
+func (h *GatewayHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ConfirmEmailChange")
+	defer span.Finish()
+
+	user, err := h.gatewayService.ConfirmEmailChange(ctx, r.URL.Query().Get("token"))
+	if err != nil {
+		// неизвестный, использованный и просроченный токены выглядят одинаково
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed) {
+			h.ErrorUsernameOrEmailAlreadyUsed(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmEmailChange] confirm change: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, user)
+}
@@ -1,5 +1,7 @@
 package rest
 
+import "gateway/pkg/validator"
+
 type ErrorCode string
 
 const (
@@ -7,12 +9,19 @@ const (
 	ErrCodeRequestValidationError ErrorCode = "USER_REQUEST_VALIDATION_ERROR"
 	ErrCodeBadRequest             ErrorCode = "BAD_REQUEST"
 	ErrCodeNotFound               ErrorCode = "NOT_FOUND"
+	ErrCodeForbidden              ErrorCode = "FORBIDDEN"
+	ErrCodeUnauthorized           ErrorCode = "UNAUTHORIZED"
+	ErrCodeConflict               ErrorCode = "CONFLICT"
+	ErrCodeTooManyRequests        ErrorCode = "TOO_MANY_REQUESTS"
 	ErrCodeInvalidJsonFormat      ErrorCode = "JSON_FORMAT_ERROR"
+	ErrCodePreconditionFailed     ErrorCode = "PRECONDITION_FAILED"
+	ErrCodePreconditionRequired   ErrorCode = "PRECONDITION_REQUIRED"
 )
 
 type ApiError struct {
-	Message string
-	ErrCode ErrorCode
+	Message    string
+	ErrCode    ErrorCode
+	Violations []validator.FieldViolation
 }
 
 func NewApiError(message string, errCode ErrorCode) *ApiError {
@@ -43,6 +52,15 @@ var (
 	ErrUsernameOrEmailAlreadyUsed = NewApiError("username or email already used", ErrCodeBadRequest)
 	ErrWrongCredentials           = NewApiError("wrong credentials", ErrCodeBadRequest)
 	ErrNotFound                   = NewApiError("not found", ErrCodeNotFound)
+	ErrForbidden                  = NewApiError("access denied", ErrCodeForbidden)
+	ErrUnauthorized               = NewApiError("invalid or expired token", ErrCodeUnauthorized)
+	ErrInvalidMFACode             = NewApiError("invalid mfa code", ErrCodeUnauthorized)
+	ErrMFAStateConflict           = NewApiError("mfa is not enrolled or already enabled", ErrCodeConflict)
+	ErrLoginLocked                = NewApiError("too many failed login attempts, try again later", ErrCodeTooManyRequests)
+	ErrLabelAlreadyExists         = NewApiError("label with this name already exists", ErrCodeConflict)
+	ErrTodoBlocked                = NewApiError("todo has open blockers", ErrCodeConflict)
+	ErrVersionConflict            = NewApiError("resource was modified by another request", ErrCodePreconditionFailed)
+	ErrPreconditionRequired       = NewApiError("if-match header with resource version is required", ErrCodePreconditionRequired)
 )
 
 func (e *ApiError) IsRequestValidationError() bool {
@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"errors"
+	"gateway/internal/app_errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var errInvalidIfMatch = errors.New("invalid if-match header")
+
+// setETag отдает версию ресурса в заголовке ETag, клиент возвращает ее в If-Match при изменении
+func setETag(w http.ResponseWriter, version int) {
+	if version > 0 {
+		w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+	}
+}
+
+// ifMatchVersion читает версию из заголовка If-Match. Слабый ETag (W/"3") принимается так же, как сильный
+func ifMatchVersion(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, app_errors.ErrPreconditionRequired
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil || version < 1 {
+		return 0, errInvalidIfMatch
+	}
+
+	return version, nil
+}
+
+// ifMatchError отвечает 428 без заголовка и 400 на версию, которую не удалось разобрать
+func (h *GatewayHandler) ifMatchError(w http.ResponseWriter, err error) {
+	if errors.Is(err, app_errors.ErrPreconditionRequired) {
+		h.ErrorPreconditionRequired(w)
+		return
+	}
+
+	h.ErrorBadRequest(w)
+}
@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"gateway/pkg/ctxutil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// ListTodoHistory godoc
+// @Summary List todo history
+// @Description Returns a page of field-level changes of the todo, newest first. Every event holds the actor, the time and the old and new value of the field.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param limit query int false "Page size" default(50)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {object} models.TodoEventListDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/{id}/history [get]
+func (h *GatewayHandler) ListTodoHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListTodoHistory")
+	defer span.Finish()
+
+	todoID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListTodoHistory] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	limit, offset, err := parsePagination(r, defaultHistoryLimit, maxHistoryLimit)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListTodoHistory] parse pagination: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	events, err := h.gatewayService.ListTodoHistory(ctx, todoID, limit, offset)
+	if err != nil {
+		h.commentError(w, requestId, "[ListTodoHistory] list history", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, events)
+}
@@ -0,0 +1,199 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// importMaxBytes - наибольший размер импортируемого файла
+const importMaxBytes = 5 << 20
+
+// ExportTodos godoc
+// @Summary Export my todos
+// @Description Streams all todos created by or assigned to the caller as a downloadable file: format=csv (default), jsonl (one models.TodoExportDTO per line) or ical (iCalendar VTODO components). Users are exported by username, so csv and jsonl files can be imported back. Accepts the same labels, match and workspace_id filters as the todo list; paging does not apply.
+// @Tags todo, v1
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce text/calendar
+// @Param format query string false "Export format" Enums(csv, jsonl, ical) default(csv)
+// @Param labels query string false "Comma separated label IDs"
+// @Param match query string false "Label match mode" Enums(any, all) default(any)
+// @Param workspace_id query string false "Workspace ID"
+// @Success 200 {array} models.TodoExportDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/export [get]
+func (h *GatewayHandler) ExportTodos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ExportTodos")
+	defer span.Finish()
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = models.TodoFormatCSV
+	}
+
+	exportFormat, ok := todoExportFormats[format]
+	if !ok {
+		h.ErrorValidation(w, validator.NewValidationError(validator.FieldViolation{
+			Field:   "format",
+			Message: "must be one of: csv, jsonl, ical",
+		}))
+		return
+	}
+
+	filter := &models.ListTodosDTO{}
+	if err := parseTodosFilter(r, filter); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ExportTodos] parse filter: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// ответ начинается с первой задачи, чтобы до нее ошибки доступа можно было вернуть обычным ответом
+	var encoder todoEncoder
+	begin := func() error {
+		now := time.Now().UTC()
+		fileName := fmt.Sprintf("todos-%s.%s", now.Format("20060102"), exportFormat.extension)
+
+		w.Header().Set("Content-Type", exportFormat.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+
+		encoder = newTodoEncoder(format, w, now)
+		return encoder.Begin()
+	}
+
+	err := h.gatewayService.ExportTodos(ctx, filter, func(todo *models.TodoExportDTO) error {
+		if encoder == nil {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+
+		return encoder.Encode(todo)
+	})
+	if err != nil {
+		if encoder == nil {
+			h.todoListError(w, requestId, "[ExportTodos] export todos", err)
+			return
+		}
+
+		// клиент уже получает файл, поэтому выгрузка просто обрывается
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ExportTodos] export todos: %s", err)
+		return
+	}
+
+	if encoder == nil {
+		err = begin()
+	}
+	if err == nil {
+		err = encoder.End()
+	}
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ExportTodos] write: %s", err)
+	}
+}
+
+// ImportTodos godoc
+// @Summary Import todos
+// @Description Creates todos of the caller from a file. format=csv (default) expects a header row; columns description, assignee, done, due_at, recurrence and timezone are read by name, others are ignored, so an exported csv file can be imported as is. format=json expects an array of todos or one todo per line (JSON Lines). assignee is a username, empty assigns the todo to the caller. Rows are numbered from the csv header or from the first json todo.
+// @Description Todos are created in one transaction only if every row is valid; otherwise nothing is created and rows hold their errors. dry_run=true only checks the rows. With workspace_id todos are created in the workspace; assignees must be its members.
+// @Tags todo, v1
+// @Accept text/csv
+// @Accept json
+// @Produce json
+// @Param format query string false "Import format" Enums(csv, json) default(csv)
+// @Param dry_run query bool false "Only check the rows" default(false)
+// @Param workspace_id query string false "Workspace ID"
+// @Param file body string true "CSV or JSON file"
+// @Success 200 {object} models.ImportTodosResultDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/import [post]
+func (h *GatewayHandler) ImportTodos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ImportTodos")
+	defer span.Finish()
+
+	request := &models.ImportTodosDTO{}
+
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			h.logger.Error().
+				Str("requestId", requestId).
+				Msgf("[ImportTodos] parse dry_run: %s", err)
+			h.ErrorBadRequest(w)
+			return
+		}
+		request.DryRun = dryRun
+	}
+
+	if raw := r.URL.Query().Get("workspace_id"); raw != "" {
+		workspaceID, err := uuid.Parse(raw)
+		if err != nil {
+			h.logger.Error().
+				Str("requestId", requestId).
+				Msgf("[ImportTodos] parse workspace id: %s", err)
+			h.ErrorBadRequest(w)
+			return
+		}
+		request.WorkspaceID = &workspaceID
+	}
+
+	body := http.MaxBytesReader(w, r.Body, importMaxBytes)
+
+	var err error
+	switch format := r.URL.Query().Get("format"); format {
+	case "", models.TodoFormatCSV:
+		request.Rows, err = parseImportCSV(body)
+	case models.TodoFormatJSON:
+		request.Rows, err = parseImportJSON(body)
+	default:
+		h.ErrorValidation(w, validator.NewValidationError(validator.FieldViolation{
+			Field:   "format",
+			Message: "must be one of: csv, json",
+		}))
+		return
+	}
+	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ImportTodos] parse file: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	result, err := h.gatewayService.ImportTodos(ctx, request)
+	if err != nil {
+		h.labelError(w, requestId, "[ImportTodos] import todos", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, result)
+}
@@ -0,0 +1,303 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strings"
+)
+
+// ListLabels godoc
+// @Summary List my labels
+// @Description Returns personal labels owned by the caller. With workspace_id returns labels of the workspace instead; the caller must be its member.
+// @Tags labels, v1
+// @Accept json
+// @Produce json
+// @Param workspace_id query string false "Workspace ID"
+// @Success 200 {array} models.LabelDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/labels [get]
+func (h *GatewayHandler) ListLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListLabels")
+	defer span.Finish()
+
+	workspaceID, err := parseWorkspaceQuery(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListLabels] parse workspace id: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	labels, err := h.gatewayService.ListLabels(ctx, workspaceID)
+	if err != nil {
+		h.labelError(w, requestId, "[ListLabels] list labels", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, labels)
+}
+
+// CreateLabel godoc
+// @Summary Create a label
+// @Description Creates a personal label owned by the caller. With workspace_id creates a label shared by the workspace members; only its owners and editors can do this. Label names are unique per owner or per workspace, case-insensitive.
+// @Tags labels, v1
+// @Accept json
+// @Produce json
+// @Param workspace_id query string false "Workspace ID"
+// @Param label body models.LabelRequestDTO true "Label"
+// @Success 200 {object} models.LabelDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/labels [post]
+func (h *GatewayHandler) CreateLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.CreateLabel")
+	defer span.Finish()
+
+	workspaceID, err := parseWorkspaceQuery(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateLabel] parse workspace id: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	label := new(models.LabelRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(label); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	created, err := h.gatewayService.CreateLabel(ctx, workspaceID, label)
+	if err != nil {
+		h.labelError(w, requestId, "[CreateLabel] create label", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, created)
+}
+
+// UpdateLabel godoc
+// @Summary Update a label
+// @Description Renames or recolors a personal label owned by the caller or a label of a workspace where the caller is an owner or editor.
+// @Tags labels, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Label ID"
+// @Param label body models.LabelRequestDTO true "Label"
+// @Success 200 {object} models.LabelDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/labels/{id} [put]
+func (h *GatewayHandler) UpdateLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UpdateLabel")
+	defer span.Finish()
+
+	labelID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateLabel] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	label := new(models.LabelRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(label); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	updated, err := h.gatewayService.UpdateLabel(ctx, labelID, label)
+	if err != nil {
+		h.labelError(w, requestId, "[UpdateLabel] update label", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, updated)
+}
+
+// DeleteLabel godoc
+// @Summary Delete a label
+// @Description Deletes a personal label owned by the caller or a label of a workspace where the caller is an owner or editor, and removes it from all todos.
+// @Tags labels, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Label ID"
+// @Success 200
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/labels/{id} [delete]
+func (h *GatewayHandler) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DeleteLabel")
+	defer span.Finish()
+
+	labelID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteLabel] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.DeleteLabel(ctx, labelID); err != nil {
+		h.labelError(w, requestId, "[DeleteLabel] delete label", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// AddTodoLabel godoc
+// @Summary Label a todo
+// @Description Puts a label on a todo the caller can update: one of the caller's personal labels on a personal todo, a label of the todo's workspace on a workspace todo.
+// @Tags todo, labels, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param label_id path string true "Label ID"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/{id}/labels/{label_id} [post]
+func (h *GatewayHandler) AddTodoLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.AddTodoLabel")
+	defer span.Finish()
+
+	todoID, labelID, err := parseTodoLabelPath(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AddTodoLabel] parse ids from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.AddTodoLabel(ctx, todoID, labelID); err != nil {
+		h.labelError(w, requestId, "[AddTodoLabel] add label", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// RemoveTodoLabel godoc
+// @Summary Remove a label from a todo
+// @Description Removes one of the caller's personal labels or a workspace label from a todo the caller can update.
+// @Tags todo, labels, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param label_id path string true "Label ID"
+// @Success 200
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/{id}/labels/{label_id} [delete]
+func (h *GatewayHandler) RemoveTodoLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RemoveTodoLabel")
+	defer span.Finish()
+
+	todoID, labelID, err := parseTodoLabelPath(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RemoveTodoLabel] parse ids from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.RemoveTodoLabel(ctx, todoID, labelID); err != nil {
+		h.labelError(w, requestId, "[RemoveTodoLabel] remove label", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// labelError отвечает клиенту по ошибке сервиса меток
+func (h *GatewayHandler) labelError(w http.ResponseWriter, requestId, operation string, err error) {
+	var validationErr *validator.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.ErrorValidation(w, validationErr)
+	case errors.Is(err, app_errors.ErrAlreadyExists):
+		h.ErrorLabelAlreadyExists(w)
+	case errors.Is(err, app_errors.ErrForbidden):
+		h.ErrorForbidden(w)
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+	default:
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("%s: %s", operation, err)
+		h.ErrorInternalApi(w)
+	}
+}
+
+func parseTodoLabelPath(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	vars := mux.Vars(r)
+
+	todoID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	labelID, err := uuid.Parse(vars["label_id"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return todoID, labelID, nil
+}
+
+// parseLabelFilter читает из запроса фильтр задач по меткам: labels - идентификаторы через запятую,
+// match - any или all
+func parseLabelFilter(r *http.Request, filter *models.ListTodosDTO) error {
+	filter.LabelMatch = r.URL.Query().Get("match")
+
+	if raw := r.URL.Query().Get("labels"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			labelID, err := uuid.Parse(strings.TrimSpace(part))
+			if err != nil {
+				return fmt.Errorf("parse label id: %w", err)
+			}
+			filter.LabelIDs = append(filter.LabelIDs, labelID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	appErrors "gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+// UserLoginMFA godoc
+// @Summary Second step of login
+// @Description Exchanges the mfa_token returned by /v1/users/login and a TOTP or recovery code for access and refresh tokens.
+// @Description The mfa_token is single use and allows a limited number of attempts.
+// @Description Wrong codes count as failed logins of the account and lock login the same way as wrong passwords.
+// @Tags users, v1
+// @Accept json
+// @Produce json
+// @Param request body models.MFALoginDTO true "MFA token and code"
+// @Success 200 {object} models.UserTokens
+// @Failure 401 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Header 429 {integer} Retry-After "Seconds until login is allowed again"
+// @Router /v1/users/login/mfa [post]
+func (h *GatewayHandler) UserLoginMFA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UserLoginMFA")
+	defer span.Finish()
+
+	var request = new(models.MFALoginDTO)
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UserLoginMFA] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	response, err := h.gatewayService.LoginMFA(ctx, request)
+	if err != nil {
+		var lockedErr *appErrors.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[UserLoginMFA] login: %s", err)
+			h.ErrorLoginLocked(w, lockedErr.RetryAfter)
+			return
+		}
+
+		if h.handleMFAError(w, err) {
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrInvalidToken) {
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[UserLoginMFA] login: %s", err)
+			h.ErrorUnauthorized(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UserLoginMFA] login: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+// EnrollTOTP godoc
+// @Summary Start TOTP enrollment
+// @Description Generates a TOTP secret for the current user. MFA is enabled only after the first code is confirmed.
+// @Tags users, v1
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TOTPEnrollmentDTO
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/me/mfa/totp [post]
+func (h *GatewayHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.EnrollTOTP")
+	defer span.Finish()
+
+	enrollment, err := h.gatewayService.EnrollTOTP(ctx)
+	if err != nil {
+		if h.handleMFAError(w, err) {
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[EnrollTOTP] enroll: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, enrollment)
+}
+
+// ConfirmTOTP godoc
+// @Summary Confirm TOTP enrollment
+// @Description Enables MFA with the first code from the authenticator app and returns one-time recovery codes. The codes are shown only once.
+// @Tags users, v1
+// @Accept json
+// @Produce json
+// @Param request body models.MFACodeDTO true "TOTP code"
+// @Success 200 {object} models.RecoveryCodesDTO
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/me/mfa/totp/confirm [post]
+func (h *GatewayHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ConfirmTOTP")
+	defer span.Finish()
+
+	var request = new(models.MFACodeDTO)
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmTOTP] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	codes, err := h.gatewayService.ConfirmTOTP(ctx, request)
+	if err != nil {
+		if h.handleMFAError(w, err) {
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmTOTP] confirm: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, codes)
+}
+
+// DisableTOTP godoc
+// @Summary Disable TOTP
+// @Description Disables MFA for the current user. Requires a valid TOTP or recovery code.
+// @Tags users, v1
+// @Accept json
+// @Produce json
+// @Param request body models.MFACodeDTO true "TOTP or recovery code"
+// @Success 200
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/me/mfa/totp [delete]
+func (h *GatewayHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DisableTOTP")
+	defer span.Finish()
+
+	var request = new(models.MFACodeDTO)
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DisableTOTP] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	err = h.gatewayService.DisableTOTP(ctx, request)
+	if err != nil {
+		if h.handleMFAError(w, err) {
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DisableTOTP] disable: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// handleMFAError отвечает на ошибки, общие для MFA ручек. Возвращает false, если ошибка не распознана
+func (h *GatewayHandler) handleMFAError(w http.ResponseWriter, err error) bool {
+	var validationErr *validator.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.ErrorValidation(w, validationErr)
+	case errors.Is(err, appErrors.ErrInvalidMFACode):
+		h.ErrorInvalidMFACode(w)
+	case errors.Is(err, appErrors.ErrMFAStateConflict):
+		h.ErrorMFAStateConflict(w)
+	case errors.Is(err, appErrors.ErrNotFound):
+		h.ErrorNotFound(w)
+	default:
+		return false
+	}
+
+	return true
+}
@@ -1,23 +1,50 @@
 package rest
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"gateway/pkg/clientip"
 	"gateway/pkg/ctxutil"
 	"gateway/pkg/jwtutil"
+	"gateway/pkg/ratelimit"
 	"gateway/pkg/redis"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// схемы заголовка Authorization
+const (
+	bearerScheme = "Bearer "
+	apiKeyScheme = "ApiKey "
+)
+
+// APIKeyAuthenticator проверяет API ключи машинных клиентов
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKeyPrincipalDTO, error)
+}
+
 func ValidateTokenMiddleware(
 	jwtUtil *jwtutil.JWTUtil,
+	apiKeys APIKeyAuthenticator,
 	excludedPaths []string,
 	manager *redis.RedisManager,
+	clientIPs *clientip.Resolver,
+	logger *zerolog.Logger,
 ) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Generate a UUID for each request
 			ctx := ctxutil.SetRequestIDToContext(r.Context(), uuid.New().String())
+			ctx = ctxutil.SetClientInfoToContext(ctx, clientInfo(r, clientIPs))
 
 			// Check if the route is excluded from validation
 			for _, path := range excludedPaths {
@@ -34,23 +61,177 @@ func ValidateTokenMiddleware(
 				return
 			}
 
+			// API ключ - альтернатива JWT для скриптов и CI
+			if strings.HasPrefix(authHeader, apiKeyScheme) {
+				principal, err := apiKeys.AuthenticateAPIKey(ctx, strings.TrimPrefix(authHeader, apiKeyScheme))
+				if err != nil {
+					if errors.Is(err, app_errors.ErrWrongCredentials) {
+						http.Error(w, "Invalid or expired API key", http.StatusUnauthorized)
+						return
+					}
+
+					requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+					logger.Error().
+						Str("requestId", requestId).
+						Msgf("[ValidateTokenMiddleware] authenticate api key: %s", err)
+					http.Error(w, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+
+				role := principal.Role
+				if role == "" {
+					role = models.RoleUser
+				}
+
+				ctx = ctxutil.SetUserIDToContext(ctx, principal.UserID)
+				ctx = ctxutil.SetUserRoleToContext(ctx, role)
+				// непустой срез отличает запрос по ключу от запроса с JWT
+				ctx = ctxutil.SetAPIKeyScopesToContext(ctx, append(make([]string, 0, len(principal.Scopes)), principal.Scopes...))
+
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			// Validate the token
-			token := strings.TrimPrefix(authHeader, "Bearer ")
-			userID, exp, err := jwtUtil.VerifyToken(token)
+			token := strings.TrimPrefix(authHeader, bearerScheme)
+			claims, err := jwtUtil.VerifyAccessToken(token)
 			if err != nil {
 				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
 				return
 			}
-			if !manager.IsTokenValid(ctx, userID, exp) {
+			if !manager.IsTokenValid(ctx, claims.ID) {
 				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
 				return
 			}
 
-			// Add the user ID to the context
-			ctx = ctxutil.SetUserIDToContext(ctx, userID)
+			// время последней активности нужно только для списка сессий, ошибку не считаем критичной
+			_ = manager.TouchSession(ctx, claims.FamilyID, time.Now())
+
+			// токены, выпущенные до появления ролей, считаются токенами обычного пользователя
+			role := claims.Role
+			if role == "" {
+				role = models.RoleUser
+			}
+
+			// Add the user ID and role to the context
+			ctx = ctxutil.SetUserIDToContext(ctx, claims.UserID)
+			ctx = ctxutil.SetUserRoleToContext(ctx, role)
+			ctx = ctxutil.SetSessionIDToContext(ctx, claims.FamilyID)
 
 			// Token is valid, proceed with the request
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// clientInfo собирает данные об устройстве клиента для списка сессий.
+// По IP из него считаются лимиты запросов и блокировка входа
+func clientInfo(r *http.Request, clientIPs *clientip.Resolver) ctxutil.ClientInfo {
+	return ctxutil.ClientInfo{
+		Device:    r.Header.Get("X-Device-Name"),
+		UserAgent: r.UserAgent(),
+		IP:        clientIPs.ClientIP(r),
+	}
+}
+
+// RateLimitMiddleware ограничивает частоту запросов по правилам для маршрутов:
+// авторизованные запросы считаются по пользователю, публичные - по IP клиента
+func RateLimitMiddleware(limiter *ratelimit.Limiter, logger *zerolog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+
+			rule := limiter.Rule(r.Method, routeTemplate(r))
+			result, err := limiter.Allow(ctx, rule, rateLimitClient(r), requestId)
+			if err != nil {
+				// недоступность Redis не должна останавливать весь API
+				logger.Error().
+					Str("requestId", requestId).
+					Msgf("[RateLimitMiddleware] rule %s: %s", rule.Name(), err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if result == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("X-RateLimit-Reset", durationSeconds(result.Reset))
+
+			if !result.Allowed {
+				w.Header().Set("Retry-After", durationSeconds(result.Reset))
+				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeTemplate возвращает шаблон маршрута mux, чтобы /todos/1 и /todos/2 попадали под одно правило
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+
+	return r.URL.Path
+}
+
+// rateLimitClient - по кому считается лимит: пользователь из токена или IP клиента
+func rateLimitClient(r *http.Request) string {
+	if userID, ok := ctxutil.GetUserIDFromContext(r.Context()); ok {
+		return fmt.Sprintf("user:%d", userID)
+	}
+
+	client, _ := ctxutil.GetClientInfoFromContext(r.Context())
+	return fmt.Sprintf("ip:%s", client.IP)
+}
+
+// RequireAPIKeyScopeMiddleware пропускает запросы по API ключу только на маршруты из scopes
+// ("METHOD шаблон маршрута" -> нужное право) и только если ключу выдано это право.
+// Запросы с JWT токеном проходят без проверки
+func RequireAPIKeyScopeMiddleware(scopes map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject, err := policy.SubjectFromContext(r.Context())
+			if err != nil || subject.APIKeyScopes == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scope, ok := scopes[r.Method+" "+routeTemplate(r)]
+			if !ok || !subject.HasScope(scope) {
+				http.Error(w, "Access denied", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRoleMiddleware пропускает запрос дальше только если у пользователя есть одна из ролей
+func RequireRoleMiddleware(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject, err := policy.SubjectFromContext(r.Context())
+			if err != nil {
+				http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+				return
+			}
+
+			if !subject.HasRole(roles...) {
+				http.Error(w, "Access denied", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
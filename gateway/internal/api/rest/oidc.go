@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"errors"
+	appErrors "gateway/internal/app_errors"
+	"gateway/pkg/ctxutil"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+// OIDCLogin godoc
+// @Summary Start login with an external provider
+// @Description Redirects to the provider login page. The authorization code flow uses PKCE, state and nonce.
+// @Tags auth, v1
+// @Param provider path string true "Provider name"
+// @Success 302
+// @Failure 404 {object} ErrorResponse
+// @Router /v1/auth/oidc/{provider}/login [get]
+func (h *GatewayHandler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.OIDCLogin")
+	defer span.Finish()
+
+	login, err := h.gatewayService.StartOIDCLogin(ctx, mux.Vars(r)["provider"])
+	if err != nil {
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[OIDCLogin] start login: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	http.Redirect(w, r, login.AuthURL, http.StatusFound)
+}
+
+// OIDCCallback godoc
+// @Summary Finish login with an external provider
+// @Description Exchanges the authorization code for access and refresh tokens. On the first login the provider account
+// @Description is linked to the user with the same verified email or a new user is created.
+// @Description If the user has MFA enabled, an mfa_token for /v1/users/login/mfa is returned instead of tokens.
+// @Tags auth, v1
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State"
+// @Success 200 {object} models.UserTokens
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /v1/auth/oidc/{provider}/callback [get]
+func (h *GatewayHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.OIDCCallback")
+	defer span.Finish()
+
+	query := r.URL.Query()
+	// пользователь отказался от входа или провайдер вернул ошибку
+	if providerErr := query.Get("error"); providerErr != "" {
+		h.logger.Warn().
+			Str("requestId", requestId).
+			Msgf("[OIDCCallback] provider error: %s", providerErr)
+		h.ErrorUnauthorized(w)
+		return
+	}
+
+	tokens, err := h.gatewayService.CompleteOIDCLogin(ctx, mux.Vars(r)["provider"], query.Get("code"), query.Get("state"))
+	if err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrNotFound):
+			h.ErrorNotFound(w)
+		case errors.Is(err, appErrors.ErrInvalidToken):
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[OIDCCallback] login: %s", err)
+			h.ErrorUnauthorized(w)
+		case errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed):
+			h.ErrorUsernameOrEmailAlreadyUsed(w)
+		default:
+			h.logger.Error().
+				Str("requestId", requestId).
+				Msgf("[OIDCCallback] login: %s", err)
+			h.ErrorInternalApi(w)
+		}
+		return
+	}
+
+	h.JSONSuccessRespond(w, tokens)
+}
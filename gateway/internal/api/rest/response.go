@@ -2,12 +2,17 @@ package rest
 
 import (
 	"encoding/json"
+	"gateway/pkg/validator"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type ErrorResponse struct {
-	ErrorMessage string `json:"errorMessage"`
-	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string                     `json:"errorMessage"`
+	ErrorCode    string                     `json:"errorCode"`
+	Violations   []validator.FieldViolation `json:"violations,omitempty"`
 }
 
 func (h *GatewayHandler) ErrorBadRequest(w http.ResponseWriter) {
@@ -26,6 +31,59 @@ func (h *GatewayHandler) ErrorNotFound(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusNotFound, ErrNotFound)
 }
 
+func (h *GatewayHandler) ErrorUnauthorized(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusUnauthorized, ErrUnauthorized)
+}
+
+func (h *GatewayHandler) ErrorForbidden(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusForbidden, ErrForbidden)
+}
+
+func (h *GatewayHandler) ErrorInvalidMFACode(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusUnauthorized, ErrInvalidMFACode)
+}
+
+func (h *GatewayHandler) ErrorMFAStateConflict(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusConflict, ErrMFAStateConflict)
+}
+
+func (h *GatewayHandler) ErrorLabelAlreadyExists(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusConflict, ErrLabelAlreadyExists)
+}
+
+// ErrorTodoBlocked отвечает 409, если задачу пытаются выполнить раньше блокирующих ее задач
+func (h *GatewayHandler) ErrorTodoBlocked(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusConflict, ErrTodoBlocked)
+}
+
+// ErrorVersionConflict отвечает 412, если версия из If-Match устарела
+func (h *GatewayHandler) ErrorVersionConflict(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusPreconditionFailed, ErrVersionConflict)
+}
+
+// ErrorPreconditionRequired отвечает 428, если изменение пришло без If-Match
+func (h *GatewayHandler) ErrorPreconditionRequired(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusPreconditionRequired, ErrPreconditionRequired)
+}
+
+// ErrorLoginLocked отвечает 429, Retry-After - через сколько секунд можно повторить вход
+func (h *GatewayHandler) ErrorLoginLocked(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", durationSeconds(retryAfter))
+	h.JSONErrorRespond(w, http.StatusTooManyRequests, ErrLoginLocked)
+}
+
+// durationSeconds - длительность в целых секундах с округлением вверх для заголовков Retry-After и X-RateLimit-Reset
+func durationSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+func (h *GatewayHandler) ErrorValidation(w http.ResponseWriter, validationErr *validator.ValidationError) {
+	err := NewApiError("request validation failed", ErrCodeRequestValidationError)
+	err.Violations = validationErr.Violations
+
+	h.JSONErrorRespond(w, http.StatusBadRequest, err)
+}
+
 func (h *GatewayHandler) ErrorInternalApi(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusInternalServerError, ErrInternalApi)
 }
@@ -42,6 +100,7 @@ func (h *GatewayHandler) JSONErrorRespond(w http.ResponseWriter, httpCode int, e
 	data := ErrorResponse{
 		ErrorCode:    string(err.ErrCode),
 		ErrorMessage: err.Error(),
+		Violations:   err.Violations,
 	}
 
 	rawData, marshalErr := json.Marshal(data)
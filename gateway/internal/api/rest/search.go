@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchTodos godoc
+// @Summary Search my todos
+// @Description Full-text search over descriptions and comments of todos created by or assigned to the caller. Supports quoted phrases, OR and -exclusion. Best matches come first; snippets are html-escaped with matches wrapped in <mark>.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Param limit query int false "Page size" default(20)
+// @Param offset query int false "Page offset" default(0)
+// @Success 200 {array} models.SearchResultDTO
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/search [get]
+func (h *GatewayHandler) SearchTodos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.SearchTodos")
+	defer span.Finish()
+
+	limit, offset, err := parsePagination(r, defaultSearchLimit, maxSearchLimit)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SearchTodos] parse pagination: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	results, err := h.gatewayService.SearchTodos(ctx, &models.SearchTodosDTO{
+		Query:  r.URL.Query().Get("q"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SearchTodos] search: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, results)
+}
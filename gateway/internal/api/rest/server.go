@@ -5,6 +5,10 @@ import (
 	"gateway/config"
 	rest "gateway/internal/api"
 	_ "gateway/internal/api/docs"
+	"gateway/internal/models"
+	"gateway/pkg/clientip"
+	"gateway/pkg/jwtutil"
+	"gateway/pkg/ratelimit"
 	"gateway/pkg/redis"
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
@@ -13,6 +17,37 @@ import (
 	"net/http/pprof"
 )
 
+// apiKeyScopes - маршруты, доступные по API ключу, и нужные для них права. Остальные маршруты доступны только с JWT токеном
+var apiKeyScopes = map[string]string{
+	"GET /api/v1/users/{id:[0-9]+}":                       models.ScopeUsersRead,
+	"GET /api/v1/users/{id:[0-9]+}/avatar":                models.ScopeUsersRead,
+	"POST /api/v1/todos/":                                 models.ScopeTodosWrite,
+	"GET /api/v1/todos/":                                  models.ScopeTodosRead,
+	"GET /api/v1/todos/batch":                             models.ScopeTodosRead,
+	"POST /api/v1/todos/bulk":                             models.ScopeTodosWrite,
+	"GET /api/v1/todos/export":                            models.ScopeTodosRead,
+	"POST /api/v1/todos/import":                           models.ScopeTodosWrite,
+	"GET /api/v1/todos/search":                            models.ScopeTodosRead,
+	"GET /api/v1/todos/{id}":                              models.ScopeTodosRead,
+	"PUT /api/v1/todos/{id}":                              models.ScopeTodosWrite,
+	"DELETE /api/v1/todos/{id}":                           models.ScopeTodosWrite,
+	"GET /api/v1/todos/{id}/comments":                     models.ScopeTodosRead,
+	"POST /api/v1/todos/{id}/comments":                    models.ScopeTodosWrite,
+	"PUT /api/v1/todos/{id}/comments/{comment_id}":        models.ScopeTodosWrite,
+	"DELETE /api/v1/todos/{id}/comments/{comment_id}":     models.ScopeTodosWrite,
+	"GET /api/v1/todos/{id}/history":                      models.ScopeTodosRead,
+	"POST /api/v1/todos/{id}/labels/{label_id}":           models.ScopeTodosWrite,
+	"DELETE /api/v1/todos/{id}/labels/{label_id}":         models.ScopeTodosWrite,
+	"POST /api/v1/todos/{id}/dependencies/{blocker_id}":   models.ScopeTodosWrite,
+	"DELETE /api/v1/todos/{id}/dependencies/{blocker_id}": models.ScopeTodosWrite,
+	"GET /api/v1/labels":                                  models.ScopeTodosRead,
+	"POST /api/v1/labels":                                 models.ScopeTodosWrite,
+	"PUT /api/v1/labels/{id}":                             models.ScopeTodosWrite,
+	"DELETE /api/v1/labels/{id}":                          models.ScopeTodosWrite,
+	"GET /api/v1/workspaces":                              models.ScopeTodosRead,
+	"GET /api/v1/workspaces/{id}/members":                 models.ScopeTodosRead,
+}
+
 // @title           ToDo Gateway API
 // @version         1.0
 // @description     This service is Gateway API for all microservices of ToDo service
@@ -26,25 +61,41 @@ func RunREST(
 	cfg *config.Config,
 	logger *zerolog.Logger,
 	gatewayService rest.GatewayService,
+	jwtUtil *jwtutil.JWTUtil,
 	redisManager *redis.RedisManager,
 ) error {
 	gatewayHandler := NewGatewayHandler(logger, gatewayService)
 
+	clientIPs, err := clientip.New(&cfg.ClientIP)
+	if err != nil {
+		return fmt.Errorf("[RunREST] client ip resolver: %w", err)
+	}
+
 	router := mux.NewRouter()
 	router.Use(
 		ValidateTokenMiddleware(
-			&cfg.JWT,
+			jwtUtil,
+			gatewayService,
 			[]string{
+				"/.well-known/jwks.json",
 				"/debug/pprof",
 				"/docs/swagger",
 				"/api/v1/users/login",
 				"/api/v1/users/refresh",
 				"/api/v1/users/register",
+				"/api/v1/users/email/confirm",
+				"/api/v1/auth/oidc",
 			},
 			redisManager,
+			clientIPs,
+			logger,
 		),
+		RequireAPIKeyScopeMiddleware(apiKeyScopes),
+		RateLimitMiddleware(ratelimit.New(&cfg.RateLimit, redisManager), logger),
 	)
 
+	router.HandleFunc("/.well-known/jwks.json", gatewayHandler.JWKS).Methods(http.MethodGet)
+
 	router.PathPrefix("/docs/swagger").Handler(httpSwagger.WrapHandler)
 
 	debugRouter := router.PathPrefix("/debug/pprof").Subrouter()
@@ -62,25 +113,82 @@ func RunREST(
 	usersV1Router := router.PathPrefix("/api/v1/users").Subrouter()
 	usersV1Router.HandleFunc("/register", gatewayHandler.RegisterUser).Methods(http.MethodPost)
 	usersV1Router.HandleFunc("/{id:[0-9]+}", gatewayHandler.GetUserById).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/{id:[0-9]+}/avatar", gatewayHandler.UploadAvatar).Methods(http.MethodPut)
+	usersV1Router.HandleFunc("/{id:[0-9]+}/avatar", gatewayHandler.GetAvatar).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/{id:[0-9]+}/avatar", gatewayHandler.DeleteAvatar).Methods(http.MethodDelete)
 	usersV1Router.HandleFunc("/update", gatewayHandler.UpdateUser).Methods(http.MethodPut)
 	usersV1Router.HandleFunc("/update-password", gatewayHandler.UpdatePassword).Methods(http.MethodPut)
 	usersV1Router.HandleFunc("/delete/{id:[0-9]+}", gatewayHandler.DeleteUser).Methods(http.MethodDelete)
+	usersV1Router.HandleFunc("/{id:[0-9]+}/deactivate", gatewayHandler.DeactivateUser).Methods(http.MethodPost)
 	usersV1Router.HandleFunc("/login", gatewayHandler.UserLogin).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/login/mfa", gatewayHandler.UserLoginMFA).Methods(http.MethodPost)
 	usersV1Router.HandleFunc("/refresh", gatewayHandler.Refresh).Methods(http.MethodPost)
 	usersV1Router.HandleFunc("/invalidate-tokens/{user_id}", gatewayHandler.InvalidateTokensForUser).Methods(http.MethodPost)
 	usersV1Router.HandleFunc("/invalidate-token/{user_id}", gatewayHandler.InvalidateToken).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/me/sessions", gatewayHandler.ListSessions).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/me/sessions/{id}", gatewayHandler.RevokeSession).Methods(http.MethodDelete)
+	usersV1Router.HandleFunc("/me/mfa/totp", gatewayHandler.EnrollTOTP).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/me/mfa/totp/confirm", gatewayHandler.ConfirmTOTP).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/me/mfa/totp", gatewayHandler.DisableTOTP).Methods(http.MethodDelete)
+	usersV1Router.HandleFunc("/me/api-keys", gatewayHandler.ListAPIKeys).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/me/api-keys", gatewayHandler.CreateAPIKey).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/me/api-keys/{id:[0-9]+}", gatewayHandler.RevokeAPIKey).Methods(http.MethodDelete)
+	usersV1Router.HandleFunc("/me/email", gatewayHandler.RequestEmailChange).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/email/confirm", gatewayHandler.ConfirmEmailChange).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/me/export", gatewayHandler.ExportAccount).Methods(http.MethodGet)
+
+	authV1Router := router.PathPrefix("/api/v1/auth").Subrouter()
+	authV1Router.HandleFunc("/oidc/{provider}/login", gatewayHandler.OIDCLogin).Methods(http.MethodGet)
+	authV1Router.HandleFunc("/oidc/{provider}/callback", gatewayHandler.OIDCCallback).Methods(http.MethodGet)
 
 	todosV1Router := router.PathPrefix("/api/v1/todos").Subrouter()
 	todosV1Router.HandleFunc("/", gatewayHandler.CreateToDoHandler).Methods(http.MethodPost)
+	todosV1Router.HandleFunc("/", gatewayHandler.ListToDos).Methods(http.MethodGet)
 	todosV1Router.HandleFunc("/batch", gatewayHandler.GetToDosHandler).Methods(http.MethodGet)
+	todosV1Router.HandleFunc("/bulk", gatewayHandler.BulkUpdateTodos).Methods(http.MethodPost)
+	todosV1Router.HandleFunc("/export", gatewayHandler.ExportTodos).Methods(http.MethodGet)
+	todosV1Router.HandleFunc("/import", gatewayHandler.ImportTodos).Methods(http.MethodPost)
+	todosV1Router.HandleFunc("/search", gatewayHandler.SearchTodos).Methods(http.MethodGet)
 	todosV1Router.HandleFunc("/{id}", gatewayHandler.GetToDoHandler).Methods(http.MethodGet)
 	todosV1Router.HandleFunc("/{id}", gatewayHandler.UpdateToDoHandler).Methods(http.MethodPut)
 	todosV1Router.HandleFunc("/{id}", gatewayHandler.DeleteToDoHandler).Methods(http.MethodDelete)
+	todosV1Router.HandleFunc("/{id}/comments", gatewayHandler.ListComments).Methods(http.MethodGet)
+	todosV1Router.HandleFunc("/{id}/comments", gatewayHandler.AddComment).Methods(http.MethodPost)
+	todosV1Router.HandleFunc("/{id}/comments/{comment_id}", gatewayHandler.EditComment).Methods(http.MethodPut)
+	todosV1Router.HandleFunc("/{id}/comments/{comment_id}", gatewayHandler.DeleteComment).Methods(http.MethodDelete)
+	todosV1Router.HandleFunc("/{id}/history", gatewayHandler.ListTodoHistory).Methods(http.MethodGet)
+	todosV1Router.HandleFunc("/{id}/labels/{label_id}", gatewayHandler.AddTodoLabel).Methods(http.MethodPost)
+	todosV1Router.HandleFunc("/{id}/labels/{label_id}", gatewayHandler.RemoveTodoLabel).Methods(http.MethodDelete)
+	todosV1Router.HandleFunc("/{id}/dependencies/{blocker_id}", gatewayHandler.AddTodoDependency).Methods(http.MethodPost)
+	todosV1Router.HandleFunc("/{id}/dependencies/{blocker_id}", gatewayHandler.RemoveTodoDependency).Methods(http.MethodDelete)
+
+	labelsV1Router := router.PathPrefix("/api/v1/labels").Subrouter()
+	labelsV1Router.HandleFunc("", gatewayHandler.ListLabels).Methods(http.MethodGet)
+	labelsV1Router.HandleFunc("", gatewayHandler.CreateLabel).Methods(http.MethodPost)
+	labelsV1Router.HandleFunc("/{id}", gatewayHandler.UpdateLabel).Methods(http.MethodPut)
+	labelsV1Router.HandleFunc("/{id}", gatewayHandler.DeleteLabel).Methods(http.MethodDelete)
+
+	workspacesV1Router := router.PathPrefix("/api/v1/workspaces").Subrouter()
+	workspacesV1Router.HandleFunc("", gatewayHandler.ListWorkspaces).Methods(http.MethodGet)
+	workspacesV1Router.HandleFunc("", gatewayHandler.CreateWorkspace).Methods(http.MethodPost)
+	workspacesV1Router.HandleFunc("/invitations/accept", gatewayHandler.AcceptWorkspaceInvitation).Methods(http.MethodPost)
+	workspacesV1Router.HandleFunc("/{id}", gatewayHandler.UpdateWorkspace).Methods(http.MethodPut)
+	workspacesV1Router.HandleFunc("/{id}", gatewayHandler.DeleteWorkspace).Methods(http.MethodDelete)
+	workspacesV1Router.HandleFunc("/{id}/members", gatewayHandler.ListWorkspaceMembers).Methods(http.MethodGet)
+	workspacesV1Router.HandleFunc("/{id}/members/{user_id:[0-9]+}", gatewayHandler.UpdateWorkspaceMember).Methods(http.MethodPut)
+	workspacesV1Router.HandleFunc("/{id}/members/{user_id:[0-9]+}", gatewayHandler.RemoveWorkspaceMember).Methods(http.MethodDelete)
+	workspacesV1Router.HandleFunc("/{id}/invitations", gatewayHandler.InviteWorkspaceMember).Methods(http.MethodPost)
+
+	adminV1Router := router.PathPrefix("/api/v1/admin").Subrouter()
+	adminV1Router.Use(RequireRoleMiddleware(models.RoleAdmin))
+	adminV1Router.HandleFunc("/users", gatewayHandler.ListUsers).Methods(http.MethodGet)
+	adminV1Router.HandleFunc("/users/{id:[0-9]+}/role", gatewayHandler.UpdateUserRole).Methods(http.MethodPut)
+	adminV1Router.HandleFunc("/users/{id:[0-9]+}/unlock", gatewayHandler.UnlockUser).Methods(http.MethodPost)
 
 	// запустить вебсервер по адресу, передать в него роутер
 	appAddr := fmt.Sprintf("%s:%s", cfg.App.AppHost, cfg.App.AppPort)
 	logger.Info().Msgf("running server at '%s'", appAddr)
-	err := http.ListenAndServe(appAddr, router)
+	err = http.ListenAndServe(appAddr, router)
 	if err != nil {
 		return fmt.Errorf("[RunREST] listen and serve: %w", err)
 	}
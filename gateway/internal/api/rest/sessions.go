@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"errors"
+	appErrors "gateway/internal/app_errors"
+	"gateway/pkg/ctxutil"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+// ListSessions godoc
+// @Summary List active sessions
+// @Description Returns the devices where the current user is logged in. The session of the current request is marked as current.
+// @Tags users, v1
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.SessionDTO
+// @Security BearerAuth
+// @Router /v1/users/me/sessions [get]
+func (h *GatewayHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListSessions")
+	defer span.Finish()
+
+	sessions, err := h.gatewayService.ListSessions(ctx)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListSessions] list sessions: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, sessions)
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description Logs the current user out on one device: all tokens of the session are revoked.
+// @Tags users, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/users/me/sessions/{id} [delete]
+func (h *GatewayHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RevokeSession")
+	defer span.Finish()
+
+	err := h.gatewayService.RevokeSession(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RevokeSession] revoke session: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
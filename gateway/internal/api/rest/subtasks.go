@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+// AddTodoDependency godoc
+// @Summary Mark a todo as blocked by another todo
+// @Description The todo cannot be marked done until the blocking todo is done. Dependencies that would form a cycle are rejected with a validation error.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param blocker_id path string true "Blocking todo ID"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/{id}/dependencies/{blocker_id} [post]
+func (h *GatewayHandler) AddTodoDependency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.AddTodoDependency")
+	defer span.Finish()
+
+	todoID, blockerID, err := parseTodoDependencyPath(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AddTodoDependency] parse ids from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.AddTodoDependency(ctx, todoID, blockerID); err != nil {
+		h.dependencyError(w, requestId, "[AddTodoDependency] add dependency", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// RemoveTodoDependency godoc
+// @Summary Remove a blocking todo
+// @Description Removes the dependency of a todo on a blocking todo.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param blocker_id path string true "Blocking todo ID"
+// @Success 200
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos/{id}/dependencies/{blocker_id} [delete]
+func (h *GatewayHandler) RemoveTodoDependency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RemoveTodoDependency")
+	defer span.Finish()
+
+	todoID, blockerID, err := parseTodoDependencyPath(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RemoveTodoDependency] parse ids from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.RemoveTodoDependency(ctx, todoID, blockerID); err != nil {
+		h.dependencyError(w, requestId, "[RemoveTodoDependency] remove dependency", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) dependencyError(w http.ResponseWriter, requestId, operation string, err error) {
+	var validationErr *validator.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.ErrorValidation(w, validationErr)
+	case errors.Is(err, app_errors.ErrForbidden):
+		h.ErrorForbidden(w)
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+	default:
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("%s: %s", operation, err)
+		h.ErrorInternalApi(w)
+	}
+}
+
+func parseTodoDependencyPath(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	vars := mux.Vars(r)
+
+	todoID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	blockerID, err := uuid.Parse(vars["blocker_id"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return todoID, blockerID, nil
+}
@@ -3,16 +3,86 @@ package rest
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"gateway/internal/app_errors"
 	"gateway/internal/models"
 	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
 	"net/http"
-	"strconv"
 )
 
+const (
+	defaultTodosLimit = 50
+	maxTodosLimit     = 200
+)
+
+// ListToDos godoc
+// @Summary List my todos
+// @Description Returns todos created by or assigned to the caller, newest first. With workspace_id returns all todos of the workspace instead; the caller must be its member. With labels, match=any keeps todos having at least one of them, match=all - todos having all of them. With tree=true returns root todos (models.TodoNodeDTO) with visible subtasks nested under "subtasks"; filters and paging then apply to the roots only.
+// @Tags todo, v1
+// @Accept json
+// @Produce json
+// @Param labels query string false "Comma separated label IDs"
+// @Param match query string false "Label match mode" Enums(any, all) default(any)
+// @Param limit query int false "Page size" default(50)
+// @Param offset query int false "Page offset" default(0)
+// @Param tree query bool false "Return todos as a tree of subtasks" default(false)
+// @Param workspace_id query string false "Workspace ID"
+// @Success 200 {array} models.TodoDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/todos [get]
+func (h *GatewayHandler) ListToDos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListToDos")
+	defer span.Finish()
+
+	limit, offset, err := parsePagination(r, defaultTodosLimit, maxTodosLimit)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListToDos] parse pagination: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	filter := &models.ListTodosDTO{
+		Limit:  limit,
+		Offset: offset,
+	}
+	if err := parseTodosFilter(r, filter); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListToDos] parse filter: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if r.URL.Query().Get("tree") == "true" {
+		tree, err := h.gatewayService.ListTodoTree(ctx, filter)
+		if err != nil {
+			h.todoListError(w, requestId, "[ListToDos] list todo tree", err)
+			return
+		}
+
+		h.JSONSuccessRespond(w, tree)
+		return
+	}
+
+	todos, err := h.gatewayService.ListToDos(ctx, filter)
+	if err != nil {
+		h.todoListError(w, requestId, "[ListToDos] list todos", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, todos)
+}
+
 // CreateToDoHandler godoc
 // @Summary Create a new todo
 // @Description This endpoint creates a new todo in the system.
@@ -20,7 +90,7 @@ import (
 // @Accept json
 // @Produce json
 // @Param newTodo body models.CreateTodoDTO true "New Todo"
-// @Success 200 {object} map[string]int "todo_id"
+// @Success 200 {string} string "todo_id"
 // @Router /v1/todos [post]
 func (h *GatewayHandler) CreateToDoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -39,6 +109,17 @@ func (h *GatewayHandler) CreateToDoHandler(w http.ResponseWriter, r *http.Reques
 
 	createdTodo, err := h.gatewayService.CreateToDo(ctx, newTodo)
 	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, app_errors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[CreateToDoHandler] create todo: %s", err)
@@ -55,8 +136,9 @@ func (h *GatewayHandler) CreateToDoHandler(w http.ResponseWriter, r *http.Reques
 // @Tags todo, v1
 // @Accept json
 // @Produce json
-// @Param id path int true "Todo ID"
+// @Param id path string true "Todo ID"
 // @Success 200 {object} models.TodoDTO
+// @Header 200 {string} ETag "Todo version, to be sent back in If-Match"
 // @Route /v1/todos/{id} [get]
 func (h *GatewayHandler) GetToDoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -64,7 +146,7 @@ func (h *GatewayHandler) GetToDoHandler(w http.ResponseWriter, r *http.Request)
 	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetTodo")
 	defer span.Finish()
 
-	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	id, err := uuid.Parse(mux.Vars(r)["id"])
 	if err != nil {
 		h.logger.Error().
 			Str("requestId", requestId).
@@ -75,6 +157,11 @@ func (h *GatewayHandler) GetToDoHandler(w http.ResponseWriter, r *http.Request)
 
 	todo, err := h.gatewayService.GetToDo(ctx, id)
 	if err != nil {
+		if errors.Is(err, app_errors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
 		if errors.Is(err, app_errors.ErrNotFound) {
 			h.ErrorNotFound(w)
 			return
@@ -87,6 +174,7 @@ func (h *GatewayHandler) GetToDoHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	setETag(w, todo.Version)
 	h.JSONSuccessRespond(w, todo)
 }
 
@@ -96,7 +184,7 @@ func (h *GatewayHandler) GetToDoHandler(w http.ResponseWriter, r *http.Request)
 // @Tags todo, v1
 // @Accept json
 // @Produce json
-// @Param id path int true "Todos ID"
+// @Param id path string true "Todos ID"
 // @Success 200 {object} models.TodoDTO
 // @Route /v1/todos/batch [get]
 func (h *GatewayHandler) GetToDosHandler(w http.ResponseWriter, r *http.Request) {
@@ -105,7 +193,7 @@ func (h *GatewayHandler) GetToDosHandler(w http.ResponseWriter, r *http.Request)
 	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetTodos")
 	defer span.Finish()
 
-	getTodos, err := strconv.Atoi(mux.Vars(r)["id"])
+	getTodos, err := uuid.Parse(mux.Vars(r)["id"])
 	if err != nil {
 		h.logger.Error().
 			Str("requestId", requestId).
@@ -116,6 +204,11 @@ func (h *GatewayHandler) GetToDosHandler(w http.ResponseWriter, r *http.Request)
 
 	todos, err := h.gatewayService.GetToDos(ctx, getTodos)
 	if err != nil {
+		if errors.Is(err, app_errors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[GetToDosHandler] get todos: %s", err)
@@ -133,7 +226,11 @@ func (h *GatewayHandler) GetToDosHandler(w http.ResponseWriter, r *http.Request)
 // @Accept json
 // @Produce json
 // @Param updateTodo body models.UpdateTodoDTO true "Update ToDo"
+// @Param If-Match header string true "Todo version from ETag"
 // @Success 200
+// @Failure 409 {object} ErrorResponse "todo has open blockers"
+// @Failure 412 {object} ErrorResponse "todo was modified by another request"
+// @Failure 428 {object} ErrorResponse "If-Match header is missing"
 // @Router /v1/todos/{id} [put]
 func (h *GatewayHandler) UpdateToDoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -150,20 +247,48 @@ func (h *GatewayHandler) UpdateToDoHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.ifMatchError(w, err)
+		return
+	}
+
 	todo := new(models.UpdateTodoDTO)
 	if err := json.NewDecoder(r.Body).Decode(&todo); err != nil {
 		h.ErrorBadRequest(w)
 		return
 	}
 	todo.ID = id
+	todo.Version = version
 
 	updatedTodo, err := h.gatewayService.UpdateToDo(ctx, todo)
 	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, app_errors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
 		if errors.Is(err, app_errors.ErrNotFound) {
 			h.ErrorNotFound(w)
 			return
 		}
 
+		if errors.Is(err, app_errors.ErrTodoBlocked) {
+			h.ErrorTodoBlocked(w)
+			return
+		}
+
+		if errors.Is(err, app_errors.ErrVersionConflict) {
+			h.ErrorVersionConflict(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[UpdateToDoHandler] update todo: %s", err)
@@ -180,8 +305,11 @@ func (h *GatewayHandler) UpdateToDoHandler(w http.ResponseWriter, r *http.Reques
 // @Tags todo, v1
 // @Accept json
 // @Produce json
-// @Param id path int true "Todo ID"
+// @Param id path string true "Todo ID"
+// @Param If-Match header string true "Todo version from ETag"
 // @Success 200
+// @Failure 412 {object} ErrorResponse "todo was modified by another request"
+// @Failure 428 {object} ErrorResponse "If-Match header is missing"
 // @Router /v1/todos/{id} [delete]
 func (h *GatewayHandler) DeleteToDoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -189,7 +317,7 @@ func (h *GatewayHandler) DeleteToDoHandler(w http.ResponseWriter, r *http.Reques
 	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DeleteTodo")
 	defer span.Finish()
 
-	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	id, err := uuid.Parse(mux.Vars(r)["id"])
 	if err != nil {
 		h.logger.Error().
 			Str("requestId", requestId).
@@ -198,13 +326,29 @@ func (h *GatewayHandler) DeleteToDoHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = h.gatewayService.DeleteToDo(ctx, id)
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.ifMatchError(w, err)
+		return
+	}
+
+	err = h.gatewayService.DeleteToDo(ctx, id, version)
 	if err != nil {
+		if errors.Is(err, app_errors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
 		if errors.Is(err, app_errors.ErrNotFound) {
 			h.ErrorNotFound(w)
 			return
 		}
 
+		if errors.Is(err, app_errors.ErrVersionConflict) {
+			h.ErrorVersionConflict(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[DeleteToDoHandler] delete todo: %s", err)
@@ -214,3 +358,36 @@ func (h *GatewayHandler) DeleteToDoHandler(w http.ResponseWriter, r *http.Reques
 
 	h.JSONSuccessRespond(w, nil)
 }
+
+// parseTodosFilter читает из запроса фильтр задач по меткам и рабочему пространству
+func parseTodosFilter(r *http.Request, filter *models.ListTodosDTO) error {
+	if err := parseLabelFilter(r, filter); err != nil {
+		return err
+	}
+
+	workspaceID, err := parseWorkspaceQuery(r)
+	if err != nil {
+		return fmt.Errorf("parse workspace id: %w", err)
+	}
+	filter.WorkspaceID = workspaceID
+
+	return nil
+}
+
+// todoListError отвечает клиенту по ошибке выборки задач: неверный фильтр или чужое пространство
+func (h *GatewayHandler) todoListError(w http.ResponseWriter, requestId, operation string, err error) {
+	var validationErr *validator.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.ErrorValidation(w, validationErr)
+	case errors.Is(err, app_errors.ErrForbidden):
+		h.ErrorForbidden(w)
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+	default:
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("%s: %s", operation, err)
+		h.ErrorInternalApi(w)
+	}
+}
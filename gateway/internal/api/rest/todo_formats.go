@@ -0,0 +1,331 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gateway/internal/models"
+	"gateway/pkg/validator"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// todoExportFormat - заголовки ответа для формата выгрузки задач
+type todoExportFormat struct {
+	contentType string
+	extension   string
+}
+
+var todoExportFormats = map[string]todoExportFormat{
+	models.TodoFormatCSV:   {contentType: "text/csv; charset=utf-8", extension: "csv"},
+	models.TodoFormatJSONL: {contentType: "application/x-ndjson", extension: "jsonl"},
+	models.TodoFormatICal:  {contentType: "text/calendar; charset=utf-8", extension: "ics"},
+}
+
+// todoEncoder пишет задачи выгрузки в ответ по мере их получения
+type todoEncoder interface {
+	Begin() error
+	Encode(todo *models.TodoExportDTO) error
+	End() error
+}
+
+func newTodoEncoder(format string, w io.Writer, now time.Time) todoEncoder {
+	switch format {
+	case models.TodoFormatJSONL:
+		return &jsonlTodoEncoder{encoder: json.NewEncoder(w)}
+	case models.TodoFormatICal:
+		return &icalTodoEncoder{w: w, stamp: icalTime(now)}
+	default:
+		return &csvTodoEncoder{w: csv.NewWriter(w)}
+	}
+}
+
+// csvTodoColumns - колонки выгрузки в CSV. При импорте читаются description, assignee, done, due_at, recurrence и timezone
+var csvTodoColumns = []string{
+	"id", "description", "assignee", "created_by", "done", "due_at", "recurrence", "timezone", "created_at", "updated_at",
+}
+
+type csvTodoEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvTodoEncoder) Begin() error {
+	return e.w.Write(csvTodoColumns)
+}
+
+func (e *csvTodoEncoder) Encode(todo *models.TodoExportDTO) error {
+	var dueAt string
+	if todo.DueAt != nil {
+		dueAt = todo.DueAt.UTC().Format(time.RFC3339)
+	}
+
+	return e.w.Write([]string{
+		todo.ID.String(),
+		csvEscape(todo.Description),
+		csvEscape(todo.Assignee),
+		csvEscape(todo.CreatedBy),
+		strconv.FormatBool(todo.Done),
+		dueAt,
+		todo.Recurrence,
+		todo.Timezone,
+		todo.CreatedAt.UTC().Format(time.RFC3339),
+		todo.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvTodoEncoder) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvEscape не дает табличным редакторам выполнить значение как формулу
+func csvEscape(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// csvUnescape убирает экранирование, добавленное csvEscape при выгрузке
+func csvUnescape(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@", rune(value[1])) {
+		return value[1:]
+	}
+
+	return value
+}
+
+type jsonlTodoEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *jsonlTodoEncoder) Begin() error {
+	return nil
+}
+
+func (e *jsonlTodoEncoder) Encode(todo *models.TodoExportDTO) error {
+	return e.encoder.Encode(todo)
+}
+
+func (e *jsonlTodoEncoder) End() error {
+	return nil
+}
+
+// icalTodoEncoder пишет задачи компонентами VTODO календаря iCalendar (RFC 5545)
+type icalTodoEncoder struct {
+	w io.Writer
+	// stamp - время выгрузки, DTSTAMP всех задач
+	stamp string
+}
+
+func (e *icalTodoEncoder) Begin() error {
+	return e.writeLines("BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//ToDo Gateway//Todo export//EN")
+}
+
+func (e *icalTodoEncoder) Encode(todo *models.TodoExportDTO) error {
+	status := "NEEDS-ACTION"
+	if todo.Done {
+		status = "COMPLETED"
+	}
+
+	lines := []string{
+		"BEGIN:VTODO",
+		"UID:" + todo.ID.String(),
+		"DTSTAMP:" + e.stamp,
+		"CREATED:" + icalTime(todo.CreatedAt),
+		"LAST-MODIFIED:" + icalTime(todo.UpdatedAt),
+		"SUMMARY:" + icalEscape(todo.Description),
+		"STATUS:" + status,
+	}
+	if todo.DueAt != nil {
+		lines = append(lines, "DUE:"+icalTime(*todo.DueAt))
+	}
+	if todo.Recurrence != "" {
+		lines = append(lines, "RRULE:"+todo.Recurrence)
+	}
+	lines = append(lines, "END:VTODO")
+
+	return e.writeLines(lines...)
+}
+
+func (e *icalTodoEncoder) End() error {
+	return e.writeLines("END:VCALENDAR")
+}
+
+func (e *icalTodoEncoder) writeLines(lines ...string) error {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(icalFold(line))
+		b.WriteString("\r\n")
+	}
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func icalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icalEscape(value string) string {
+	return icalEscaper.Replace(value)
+}
+
+// icalFold переносит строки длиннее 75 байт, не разрывая символы UTF-8.
+// Строка продолжения начинается с пробела, который тоже входит в ее длину
+func icalFold(line string) string {
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line)
+
+	return b.String()
+}
+
+// parseImportCSV читает задачи из CSV файла с заголовком. Колонки ищутся по имени, лишние пропускаются.
+// Номер строки считается с заголовка, ошибки в значениях записываются в строку
+func parseImportCSV(r io.Reader) ([]*models.ImportTodoRowDTO, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["description"]; !ok {
+		return nil, validator.NewValidationError(validator.FieldViolation{
+			Field:   "file",
+			Message: "csv header must have a description column",
+		})
+	}
+
+	rows := make([]*models.ImportTodoRowDTO, 0)
+	for rowNum := 2; ; rowNum++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		row := &models.ImportTodoRowDTO{Row: rowNum}
+		rows = append(rows, row)
+
+		if errors.Is(err, csv.ErrFieldCount) {
+			row.Errors = append(row.Errors, validator.FieldViolation{
+				Field:   "row",
+				Message: fmt.Sprintf("must have %d fields", len(header)),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read row %d: %w", rowNum, err)
+		}
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok {
+				return ""
+			}
+			return csvUnescape(strings.TrimSpace(record[i]))
+		}
+
+		row.Description = value("description")
+		row.Assignee = value("assignee")
+		row.Recurrence = value("recurrence")
+		row.Timezone = value("timezone")
+
+		if raw := value("done"); raw != "" {
+			if row.Done, err = strconv.ParseBool(raw); err != nil {
+				row.Errors = append(row.Errors, validator.FieldViolation{Field: "done", Message: "must be true or false"})
+			}
+		}
+
+		if raw := value("due_at"); raw != "" {
+			dueAt, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				row.Errors = append(row.Errors, validator.FieldViolation{Field: "due_at", Message: "must be an RFC 3339 time"})
+			} else {
+				row.DueAt = &dueAt
+			}
+		}
+	}
+}
+
+// parseImportJSON читает задачи из JSON массива или из JSON Lines, по задаче на строку.
+// Номер строки - номер задачи в файле, задача с полями неверного типа записывается с ошибкой
+func parseImportJSON(r io.Reader) ([]*models.ImportTodoRowDTO, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	array := bytes.HasPrefix(data, []byte("["))
+	if array {
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("read array: %w", err)
+		}
+	}
+
+	rows := make([]*models.ImportTodoRowDTO, 0)
+	for rowNum := 1; !array || decoder.More(); rowNum++ {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if !array && errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read row %d: %w", rowNum, err)
+		}
+
+		row := &models.ImportTodoRowDTO{}
+		if err := json.Unmarshal(raw, row); err != nil {
+			row = &models.ImportTodoRowDTO{Errors: []validator.FieldViolation{importJSONViolation(err)}}
+		}
+		row.Row = rowNum
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// importJSONViolation - ошибка в поле задачи, если json указывает поле, иначе ошибка всей строки
+func importJSONViolation(err error) validator.FieldViolation {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return validator.FieldViolation{Field: typeErr.Field, Message: "must be a " + typeErr.Type.String()}
+	}
+
+	var timeErr *time.ParseError
+	if errors.As(err, &timeErr) {
+		return validator.FieldViolation{Field: "due_at", Message: "must be an RFC 3339 time"}
+	}
+
+	return validator.FieldViolation{Field: "row", Message: "must be a todo object"}
+}
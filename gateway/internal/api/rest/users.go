@@ -6,6 +6,7 @@ import (
 	appErrors "gateway/internal/app_errors"
 	"gateway/internal/models"
 	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
 	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
 	"net/http"
@@ -40,6 +41,12 @@ func (h *GatewayHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	// передаем данные в слой сервиса
 	userID, err := h.gatewayService.RegisterUser(ctx, newUser)
 	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
 		if errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed) {
 			h.ErrorUsernameOrEmailAlreadyUsed(w)
 			return
@@ -68,6 +75,7 @@ func (h *GatewayHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "User ID"
 // @Success 200 {object} models.UserDTO
+// @Header 200 {string} ETag "User version, to be sent back in If-Match"
 // @Router /v1/users/{id} [get]
 func (h *GatewayHandler) GetUserById(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -101,6 +109,7 @@ func (h *GatewayHandler) GetUserById(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// возвращаем данные пользователю
+	setETag(w, user.Version)
 	h.JSONSuccessRespond(w, user)
 }
 
@@ -111,7 +120,12 @@ func (h *GatewayHandler) GetUserById(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param updatedUser body models.UserDTO true "Updated User"
-// @Success 200
+// @Param If-Match header string true "User version from ETag"
+// @Success 200 {object} models.UserDTO
+// @Header 200 {string} ETag "New user version"
+// @Failure 400 {object} ErrorResponse
+// @Failure 412 {object} ErrorResponse "user was modified by another request"
+// @Failure 428 {object} ErrorResponse "If-Match header is missing"
 // @Router /v1/users/update [put]
 func (h *GatewayHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -120,6 +134,12 @@ func (h *GatewayHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	defer span.Finish()
 
 	// Обработка запроса на обновление информации о пользователе.
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.ifMatchError(w, err)
+		return
+	}
+
 	var updatedUser = new(models.UserDTO)
 	if err := json.NewDecoder(r.Body).Decode(&updatedUser); err != nil {
 		h.logger.Error().
@@ -128,10 +148,32 @@ func (h *GatewayHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		h.ErrorBadRequest(w)
 		return
 	}
+	updatedUser.Version = version
 
 	// передаем данные в слой сервиса
 	response, err := h.gatewayService.UpdateUser(ctx, updatedUser)
 	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed) {
+			h.ErrorUsernameOrEmailAlreadyUsed(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrVersionConflict) {
+			h.ErrorVersionConflict(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[UpdateUser] update user: %s", err)
@@ -140,6 +182,7 @@ func (h *GatewayHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// передаем ответ пользователю
+	setETag(w, response.Version)
 	h.JSONSuccessRespond(w, response)
 }
 
@@ -170,6 +213,17 @@ func (h *GatewayHandler) UpdatePassword(w http.ResponseWriter, r *http.Request)
 
 	// передаем данные в слой сервиса
 	if err := h.gatewayService.UpdatePassword(ctx, passwordRequest); err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[UpdatePassword] update password: %s", err)
@@ -183,12 +237,19 @@ func (h *GatewayHandler) UpdatePassword(w http.ResponseWriter, r *http.Request)
 
 // DeleteUser godoc
 // @Summary Delete a user
-// @Description Deletes a user from the system based on their ID.
+// @Description Schedules deletion of a user and ends all of their sessions.
+// @Description The account is anonymized after a grace period; logging in before that cancels the deletion.
 // @Tags users, v1
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
-// @Success 200
+// @Param If-Match header string true "User version from ETag"
+// @Success 200 {object} models.UserDTO
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 412 {object} ErrorResponse "user was modified by another request"
+// @Failure 428 {object} ErrorResponse "If-Match header is missing"
+// @Security BearerAuth
 // @Router /v1/users/delete/{id} [delete]
 func (h *GatewayHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -206,8 +267,30 @@ func (h *GatewayHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.ifMatchError(w, err)
+		return
+	}
+
 	// передаем данные в слой сервиса
-	if err := h.gatewayService.DeleteUser(ctx, userID); err != nil {
+	user, err := h.gatewayService.DeleteUser(ctx, userID, version)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrVersionConflict) {
+			h.ErrorVersionConflict(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[DeleteUser] delete user: %s", err)
@@ -215,18 +298,22 @@ func (h *GatewayHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// возвращаем пользователю ответ - в данном случе просто status 200
-	h.JSONSuccessRespond(w, nil)
+	// в ответе дата, после которой учетная запись будет обезличена
+	h.JSONSuccessRespond(w, user)
 }
 
 // UserLogin godoc
 // @Summary User login
 // @Description Authenticates a user and returns access and refresh tokens.
+// @Description If the user has MFA enabled, returns mfa_required and a short-lived mfa_token instead, to be exchanged via /v1/users/login/mfa.
 // @Tags users, v1
 // @Accept json
 // @Produce json
+// @Description Repeated failures slow down and then temporarily lock login for the account and the client IP.
 // @Param request body models.UserLoginDTO true "Login Credentials"
-// @Success 200 {object} map[string]string "Token Info"
+// @Success 200 {object} models.UserTokens
+// @Failure 429 {object} ErrorResponse
+// @Header 429 {integer} Retry-After "Seconds until login is allowed again"
 // @Router /v1/users/login [post]
 func (h *GatewayHandler) UserLogin(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -247,7 +334,25 @@ func (h *GatewayHandler) UserLogin(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.gatewayService.Login(ctx, request)
 	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		var lockedErr *appErrors.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[UserLogin] login: %s", err)
+			h.ErrorLoginLocked(w, lockedErr.RetryAfter)
+			return
+		}
+
 		if errors.Is(err, appErrors.ErrWrongCredentials) {
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[UserLogin] login: %s", err)
 			h.ErrorWrongCredentials(w)
 			return
 		}
@@ -269,8 +374,10 @@ func (h *GatewayHandler) UserLogin(w http.ResponseWriter, r *http.Request) {
 // @Tags users, v1
 // @Accept json
 // @Produce json
+// @Description Refresh tokens are single use: presenting an already used refresh token revokes the whole session.
 // @Param request body models.UserTokens true "Token Refresh Request"
-// @Success 200 {object} map[string]string "New Token Info"
+// @Success 200 {object} models.UserTokens
+// @Failure 401 {object} ErrorResponse
 // @Router /v1/users/refresh [post]
 func (h *GatewayHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -290,8 +397,22 @@ func (h *GatewayHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// передаем данные слою бизнес-логики
-	response, err := h.gatewayService.Refresh(ctx, request.RefreshToken, request.AccessToken)
+	response, err := h.gatewayService.Refresh(ctx, request.RefreshToken)
 	if err != nil {
+		if errors.Is(err, appErrors.ErrTokenReused) {
+			// повторное использование refresh токена - признак его кражи
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[Refresh] refresh token reuse detected, token family revoked: %s", err)
+			h.ErrorUnauthorized(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrInvalidToken) {
+			h.ErrorUnauthorized(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[Refresh] refresh: %s", err)
@@ -323,6 +444,11 @@ func (h *GatewayHandler) InvalidateTokensForUser(w http.ResponseWriter, r *http.
 
 	err = h.gatewayService.InvalidateTokensForUser(ctx, userID)
 	if err != nil {
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Int("user_id", userID).
@@ -366,6 +492,16 @@ func (h *GatewayHandler) InvalidateToken(w http.ResponseWriter, r *http.Request)
 
 	err = h.gatewayService.InvalidateToken(ctx, userID, request.AccessToken, request.RefreshToken)
 	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidToken) {
+			h.ErrorUnauthorized(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Int("user_id", userID).
@@ -376,3 +512,21 @@ func (h *GatewayHandler) InvalidateToken(w http.ResponseWriter, r *http.Request)
 
 	h.JSONSuccessRespond(w, "Token invalidated successfully")
 }
+
+// JWKS godoc
+// @Summary JSON Web Key Set
+// @Description Returns public keys that can be used to verify tokens issued by the gateway.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} jwtutil.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (h *GatewayHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.JWKS")
+	defer span.Finish()
+
+	// ключи меняются только при ротации, клиентам можно ненадолго их закэшировать
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	h.JSONSuccessRespond(w, h.gatewayService.GetJWKS(ctx))
+}
@@ -0,0 +1,395 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strconv"
+)
+
+// ListWorkspaces godoc
+// @Summary List my workspaces
+// @Description Returns workspaces the caller is a member of together with the caller's role.
+// @Tags workspaces, v1
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.WorkspaceDTO
+// @Security BearerAuth
+// @Router /v1/workspaces [get]
+func (h *GatewayHandler) ListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListWorkspaces")
+	defer span.Finish()
+
+	workspaces, err := h.gatewayService.ListWorkspaces(ctx)
+	if err != nil {
+		h.workspaceError(w, requestId, "[ListWorkspaces] list workspaces", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, workspaces)
+}
+
+// CreateWorkspace godoc
+// @Summary Create a workspace
+// @Description Creates a shared workspace. The caller becomes its owner.
+// @Tags workspaces, v1
+// @Accept json
+// @Produce json
+// @Param workspace body models.WorkspaceRequestDTO true "Workspace"
+// @Success 200 {object} models.WorkspaceDTO
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/workspaces [post]
+func (h *GatewayHandler) CreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.CreateWorkspace")
+	defer span.Finish()
+
+	workspace := new(models.WorkspaceRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(workspace); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	created, err := h.gatewayService.CreateWorkspace(ctx, workspace)
+	if err != nil {
+		h.workspaceError(w, requestId, "[CreateWorkspace] create workspace", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, created)
+}
+
+// UpdateWorkspace godoc
+// @Summary Rename a workspace
+// @Description Renames a workspace. Only workspace owners can do it.
+// @Tags workspaces, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param workspace body models.WorkspaceRequestDTO true "Workspace"
+// @Success 200 {object} models.WorkspaceDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/workspaces/{id} [put]
+func (h *GatewayHandler) UpdateWorkspace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UpdateWorkspace")
+	defer span.Finish()
+
+	workspaceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateWorkspace] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	workspace := new(models.WorkspaceRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(workspace); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	updated, err := h.gatewayService.UpdateWorkspace(ctx, workspaceID, workspace)
+	if err != nil {
+		h.workspaceError(w, requestId, "[UpdateWorkspace] update workspace", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, updated)
+}
+
+// DeleteWorkspace godoc
+// @Summary Delete a workspace
+// @Description Deletes a workspace with all its todos. Only workspace owners can do it.
+// @Tags workspaces, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/workspaces/{id} [delete]
+func (h *GatewayHandler) DeleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DeleteWorkspace")
+	defer span.Finish()
+
+	workspaceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteWorkspace] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.DeleteWorkspace(ctx, workspaceID); err != nil {
+		h.workspaceError(w, requestId, "[DeleteWorkspace] delete workspace", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// ListWorkspaceMembers godoc
+// @Summary List workspace members
+// @Description Returns members of a workspace the caller belongs to.
+// @Tags workspaces, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {array} models.WorkspaceMemberDTO
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/workspaces/{id}/members [get]
+func (h *GatewayHandler) ListWorkspaceMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListWorkspaceMembers")
+	defer span.Finish()
+
+	workspaceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListWorkspaceMembers] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	members, err := h.gatewayService.ListWorkspaceMembers(ctx, workspaceID)
+	if err != nil {
+		h.workspaceError(w, requestId, "[ListWorkspaceMembers] list members", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, members)
+}
+
+// UpdateWorkspaceMember godoc
+// @Summary Change a member's role
+// @Description Changes the role of a workspace member. Only workspace owners can do it; the last owner cannot be demoted.
+// @Tags workspaces, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param user_id path int true "User ID"
+// @Param member body models.UpdateWorkspaceMemberRequestDTO true "Role"
+// @Success 200 {object} models.WorkspaceMemberDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/workspaces/{id}/members/{user_id} [put]
+func (h *GatewayHandler) UpdateWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UpdateWorkspaceMember")
+	defer span.Finish()
+
+	workspaceID, userID, err := parseWorkspaceMemberPath(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateWorkspaceMember] parse ids from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	member := new(models.UpdateWorkspaceMemberRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(member); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	updated, err := h.gatewayService.UpdateWorkspaceMember(ctx, workspaceID, userID, member)
+	if err != nil {
+		h.workspaceError(w, requestId, "[UpdateWorkspaceMember] update member", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, updated)
+}
+
+// RemoveWorkspaceMember godoc
+// @Summary Remove a member
+// @Description Removes a member from a workspace. Owners can remove anyone, other members can only leave; the last owner cannot leave.
+// @Tags workspaces, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param user_id path int true "User ID"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/workspaces/{id}/members/{user_id} [delete]
+func (h *GatewayHandler) RemoveWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RemoveWorkspaceMember")
+	defer span.Finish()
+
+	workspaceID, userID, err := parseWorkspaceMemberPath(r)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RemoveWorkspaceMember] parse ids from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.gatewayService.RemoveWorkspaceMember(ctx, workspaceID, userID); err != nil {
+		h.workspaceError(w, requestId, "[RemoveWorkspaceMember] remove member", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// InviteWorkspaceMember godoc
+// @Summary Invite to a workspace
+// @Description Sends an invitation link to the email. Only workspace owners can invite; a new invitation to the same email replaces the previous one.
+// @Tags workspaces, v1
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param invitation body models.InviteWorkspaceMemberRequestDTO true "Invitation"
+// @Success 200 {object} models.WorkspaceInvitationDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/workspaces/{id}/invitations [post]
+func (h *GatewayHandler) InviteWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.InviteWorkspaceMember")
+	defer span.Finish()
+
+	workspaceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[InviteWorkspaceMember] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	invitation := new(models.InviteWorkspaceMemberRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(invitation); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	created, err := h.gatewayService.InviteWorkspaceMember(ctx, workspaceID, invitation)
+	if err != nil {
+		h.workspaceError(w, requestId, "[InviteWorkspaceMember] invite member", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, created)
+}
+
+// AcceptWorkspaceInvitation godoc
+// @Summary Accept a workspace invitation
+// @Description Joins the workspace with the token from the invitation email. The caller's email must match the invited one.
+// @Tags workspaces, v1
+// @Accept json
+// @Produce json
+// @Param invitation body models.AcceptWorkspaceInvitationRequestDTO true "Invitation token"
+// @Success 200 {object} models.WorkspaceMemberDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /v1/workspaces/invitations/accept [post]
+func (h *GatewayHandler) AcceptWorkspaceInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.AcceptWorkspaceInvitation")
+	defer span.Finish()
+
+	accept := new(models.AcceptWorkspaceInvitationRequestDTO)
+	if err := json.NewDecoder(r.Body).Decode(accept); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	member, err := h.gatewayService.AcceptWorkspaceInvitation(ctx, accept)
+	if err != nil {
+		h.workspaceError(w, requestId, "[AcceptWorkspaceInvitation] accept invitation", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, member)
+}
+
+// workspaceError отвечает клиенту по ошибке сервиса рабочих пространств
+func (h *GatewayHandler) workspaceError(w http.ResponseWriter, requestId, operation string, err error) {
+	var validationErr *validator.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.ErrorValidation(w, validationErr)
+	case errors.Is(err, app_errors.ErrForbidden):
+		h.ErrorForbidden(w)
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+	default:
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("%s: %s", operation, err)
+		h.ErrorInternalApi(w)
+	}
+}
+
+// parseWorkspaceQuery читает необязательный параметр workspace_id
+func parseWorkspaceQuery(r *http.Request) (*uuid.UUID, error) {
+	raw := r.URL.Query().Get("workspace_id")
+	if raw == "" {
+		return nil, nil
+	}
+
+	workspaceID, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workspaceID, nil
+}
+
+func parseWorkspaceMemberPath(r *http.Request) (uuid.UUID, int, error) {
+	vars := mux.Vars(r)
+
+	workspaceID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+
+	userID, err := strconv.Atoi(vars["user_id"])
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+
+	return workspaceID, userID, nil
+}
@@ -3,6 +3,7 @@ package app_errors
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -12,6 +13,16 @@ var (
 	ErrIncorrectOldPassword            = errors.New("incorrect old password")
 	ErrPassAndConfirmationDoesNotMatch = errors.New("password and confirmation does not match")
 	ErrNoUserInContext                 = errors.New("no user in context")
+	ErrForbidden                       = errors.New("forbidden")
+	ErrInvalidToken                    = errors.New("invalid or expired token")
+	ErrTokenReused                     = errors.New("refresh token reused")
+	ErrInvalidMFACode                  = errors.New("invalid mfa code")
+	ErrMFAStateConflict                = errors.New("mfa is not enrolled or already enabled")
+	ErrLoginLocked                     = errors.New("too many failed login attempts")
+	ErrAlreadyExists                   = errors.New("already exists")
+	ErrTodoBlocked                     = errors.New("todo has open blockers")
+	ErrVersionConflict                 = errors.New("resource was modified by another request")
+	ErrPreconditionRequired            = errors.New("if-match header is required")
 )
 
 type UserIDMismatchError struct {
@@ -25,6 +36,25 @@ func (e *UserIDMismatchError) Error() string {
 	return fmt.Sprintf("%s context ID [%d], DTO ID [%d]", e.Operation, e.ContextID, e.UserDTOID)
 }
 
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrForbidden)
+func (e *UserIDMismatchError) Unwrap() error {
+	return ErrForbidden
+}
+
+// LoginLockedError - вход временно заблокирован после неудачных попыток
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLoginLocked, e.RetryAfter)
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrLoginLocked)
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
+
 func NewUserIDMismatchError(
 	Operation string,
 	ContextID int,
@@ -2,14 +2,21 @@ package todos
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"gateway/config"
+	"gateway/internal/app_errors"
 	"gateway/internal/models"
 	"gateway/pkg/ctxutil"
 	"gateway/pkg/grpc_stubs/todo"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
 )
 
 type TodosClient struct {
@@ -17,7 +24,7 @@ type TodosClient struct {
 }
 
 func NewTodosClient(cfg *config.Config, logger *zerolog.Logger) (*TodosClient, error) {
-	appAddr := fmt.Sprintf("%s:%s", cfg.UsersClient.AppHost, cfg.TodosClient.AppGrpcPort)
+	appAddr := fmt.Sprintf("%s:%s", cfg.TodosClient.AppHost, cfg.TodosClient.AppGrpcPort)
 
 	logger.Info().Msgf("[NewTodosClient] connecting via GRPC to todo at %s", appAddr)
 
@@ -35,67 +42,607 @@ func NewTodosClient(cfg *config.Config, logger *zerolog.Logger) (*TodosClient, e
 	}, nil
 }
 
-func (c *TodosClient) CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (int, error) {
+func (c *TodosClient) CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (uuid.UUID, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateToDo")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	todoID, err := c.CreateToDo(ctx, newTodo)
+	res, err := c.client.CreateToDo(ctx, newTodo.ToGRPC())
 	if err != nil {
-		return 0, fmt.Errorf("[CreateToDo] store user:%w", err)
+		return uuid.Nil, convertError(err)
 	}
 
-	return todoID, nil
+	return uuid.Parse(res.Id)
 }
 
-func (c *TodosClient) UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (int, error) {
+func (c *TodosClient) UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (uuid.UUID, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateTodo")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	todoID, err := c.UpdateToDo(ctx, updateTodo)
+	res, err := c.client.UpdateToDo(ctx, updateTodo.ToGRPC())
 	if err != nil {
-		return 0, fmt.Errorf("[UpdateToDo] update todo:%w", err)
+		return uuid.Nil, convertError(err)
 	}
 
-	return todoID, nil
+	return uuid.Parse(res.Id)
 }
 
-func (c *TodosClient) GetToDos(ctx context.Context, todoID int) (*models.TodoDTO, error) {
+func (c *TodosClient) GetToDos(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetToDos")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	todo, err := c.GetToDos(ctx, todoID)
+	res, err := c.client.GetToDos(ctx, &todo.TodoID{Id: todoID.String()})
 	if err != nil {
-		return nil, fmt.Errorf("[GetToDo] get todo:%w", err)
+		return nil, convertError(err)
 	}
 
-	return todo, nil
+	return models.NewEmptyTodoDTO().FromGRPC(res), nil
 }
 
-func (c *TodosClient) GetToDo(ctx context.Context, todoID int) (*models.TodoDTO, error) {
+func (c *TodosClient) GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetTodo")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	todo, err := c.GetToDo(ctx, todoID)
+	res, err := c.client.GetToDo(ctx, &todo.TodoID{Id: todoID.String()})
 	if err != nil {
-		return nil, fmt.Errorf("[GetToDo] get todo:%w", err)
+		return nil, convertError(err)
 	}
 
-	return todo, nil
+	return models.NewEmptyTodoDTO().FromGRPC(res), nil
 }
 
-func (c *TodosClient) DeleteToDo(ctx context.Context, todoID int) error {
+func (c *TodosClient) DeleteToDo(ctx context.Context, todoID uuid.UUID, version int) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DeleteToDo")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	err := c.DeleteToDo(ctx, todoID)
+	_, err := c.client.DeleteToDo(ctx, &todo.DeleteTodoRequest{
+		Id:      todoID.String(),
+		Version: int32(version),
+	})
 	if err != nil {
-		return fmt.Errorf("[DeleteToDo] delete todo:%w", err)
+		return convertError(err)
 	}
 
 	return nil
 }
+
+func (c *TodosClient) GetUserToDos(ctx context.Context, userID int) ([]*models.TodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetUserToDos")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.GetUserToDos(ctx, &todo.UserID{Id: int32(userID)})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	todos := make([]*models.TodoDTO, 0, len(res.Todos))
+	for _, t := range res.Todos {
+		todos = append(todos, models.NewEmptyTodoDTO().FromGRPC(t))
+	}
+
+	return todos, nil
+}
+
+func (c *TodosClient) AddComment(ctx context.Context, todoID uuid.UUID, author int, body string) (*models.CommentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.AddComment")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.AddComment(ctx, &todo.CreateCommentDTO{
+		TodoId: todoID.String(),
+		Author: int32(author),
+		Body:   body,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyCommentDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) EditComment(ctx context.Context, todoID, commentID uuid.UUID, author int, body string) (*models.CommentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.EditComment")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.EditComment(ctx, &todo.UpdateCommentDTO{
+		Id:     commentID.String(),
+		TodoId: todoID.String(),
+		Author: int32(author),
+		Body:   body,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyCommentDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) DeleteComment(ctx context.Context, todoID, commentID uuid.UUID, deletedBy int, moderator bool) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DeleteComment")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.DeleteComment(ctx, &todo.DeleteCommentDTO{
+		Id:        commentID.String(),
+		TodoId:    todoID.String(),
+		DeletedBy: int32(deletedBy),
+		Moderator: moderator,
+	})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *TodosClient) ListComments(ctx context.Context, todoID uuid.UUID, limit, offset int) (*models.CommentListDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListComments")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ListComments(ctx, &todo.ListCommentsRequest{
+		TodoId: todoID.String(),
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return new(models.CommentListDTO).FromGRPC(res), nil
+}
+
+func (c *TodosClient) ListTodoHistory(ctx context.Context, todoID uuid.UUID, limit, offset int) (*models.TodoEventListDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListTodoHistory")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ListTodoHistory(ctx, &todo.ListTodoHistoryRequest{
+		TodoId: todoID.String(),
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return new(models.TodoEventListDTO).FromGRPC(res), nil
+}
+
+func (c *TodosClient) BulkUpdateTodos(ctx context.Context, actor int, ids []uuid.UUID, request *models.BulkUpdateTodosDTO) (*models.BulkUpdateTodosResultDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.BulkUpdateTodos")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.BulkUpdateTodos(ctx, request.ToGRPC(actor, ids))
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return new(models.BulkUpdateTodosResultDTO).FromGRPC(res), nil
+}
+
+func (c *TodosClient) ExportTodos(ctx context.Context, filter *models.ListTodosDTO, send func(todo *models.TodoDTO) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ExportTodos")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	stream, err := c.client.ExportTodos(ctx, filter.ToGRPC())
+	if err != nil {
+		return convertError(err)
+	}
+
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return convertError(err)
+		}
+
+		if err := send(models.NewEmptyTodoDTO().FromGRPC(res)); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *TodosClient) ImportTodos(ctx context.Context, request *models.ImportTodosRequestDTO) (*models.ImportTodosResultDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ImportTodos")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ImportTodos(ctx, request.ToGRPC())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return new(models.ImportTodosResultDTO).FromGRPC(res), nil
+}
+
+func (c *TodosClient) ListToDos(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListToDos")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ListToDos(ctx, filter.ToGRPC())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	todos := make([]*models.TodoDTO, 0, len(res.Todos))
+	for _, t := range res.Todos {
+		todos = append(todos, models.NewEmptyTodoDTO().FromGRPC(t))
+	}
+
+	return todos, nil
+}
+
+func (c *TodosClient) CreateLabel(ctx context.Context, label *models.CreateLabelDTO) (*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateLabel")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.CreateLabel(ctx, label.ToGRPC())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyLabelDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) UpdateLabel(ctx context.Context, labelID uuid.UUID, owner int, label *models.LabelRequestDTO) (*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateLabel")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.UpdateLabel(ctx, &todo.UpdateLabelDTO{
+		Id:    labelID.String(),
+		Owner: int32(owner),
+		Name:  label.Name,
+		Color: label.Color,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyLabelDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) DeleteLabel(ctx context.Context, labelID uuid.UUID, owner int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DeleteLabel")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.DeleteLabel(ctx, &todo.LabelRef{Id: labelID.String(), Owner: int32(owner)})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *TodosClient) GetLabel(ctx context.Context, labelID uuid.UUID) (*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetLabel")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.GetLabel(ctx, &todo.LabelID{Id: labelID.String()})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyLabelDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) ListLabels(ctx context.Context, request *models.ListLabelsDTO) ([]*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListLabels")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ListLabels(ctx, request.ToGRPC())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	labels := make([]*models.LabelDTO, 0, len(res.Labels))
+	for _, l := range res.Labels {
+		labels = append(labels, models.NewEmptyLabelDTO().FromGRPC(l))
+	}
+
+	return labels, nil
+}
+
+func (c *TodosClient) AddTodoLabel(ctx context.Context, todoID, labelID uuid.UUID, owner int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.AddTodoLabel")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.AddTodoLabel(ctx, &todo.TodoLabelRequest{
+		TodoId:  todoID.String(),
+		LabelId: labelID.String(),
+		Owner:   int32(owner),
+	})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *TodosClient) RemoveTodoLabel(ctx context.Context, todoID, labelID uuid.UUID, owner int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RemoveTodoLabel")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RemoveTodoLabel(ctx, &todo.TodoLabelRequest{
+		TodoId:  todoID.String(),
+		LabelId: labelID.String(),
+		Owner:   int32(owner),
+	})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *TodosClient) SearchTodos(ctx context.Context, request *models.SearchTodosDTO) ([]*models.SearchResultDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.SearchTodos")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.SearchTodos(ctx, request.ToGRPC())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	results := make([]*models.SearchResultDTO, 0, len(res.Results))
+	for _, r := range res.Results {
+		results = append(results, models.NewEmptySearchResultDTO().FromGRPC(r))
+	}
+
+	return results, nil
+}
+
+func (c *TodosClient) ListTodoTree(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoNodeDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListTodoTree")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ListTodoTree(ctx, filter.ToGRPC())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	nodes := make([]*models.TodoNodeDTO, 0, len(res.Nodes))
+	for _, n := range res.Nodes {
+		nodes = append(nodes, models.NewEmptyTodoNodeDTO().FromGRPC(n))
+	}
+
+	return nodes, nil
+}
+
+func (c *TodosClient) AddTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.AddTodoDependency")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.AddTodoDependency(ctx, &todo.TodoDependencyRequest{
+		TodoId:    todoID.String(),
+		BlockedBy: blockedBy.String(),
+	})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *TodosClient) RemoveTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RemoveTodoDependency")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RemoveTodoDependency(ctx, &todo.TodoDependencyRequest{
+		TodoId:    todoID.String(),
+		BlockedBy: blockedBy.String(),
+	})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *TodosClient) CreateWorkspace(ctx context.Context, createdBy int, workspace *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateWorkspace")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.CreateWorkspace(ctx, &todo.CreateWorkspaceDTO{
+		CreatedBy: int32(createdBy),
+		Name:      workspace.Name,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyWorkspaceDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) UpdateWorkspace(ctx context.Context, workspaceID uuid.UUID, workspace *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateWorkspace")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.UpdateWorkspace(ctx, &todo.UpdateWorkspaceDTO{
+		Id:   workspaceID.String(),
+		Name: workspace.Name,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyWorkspaceDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DeleteWorkspace")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.DeleteWorkspace(ctx, &todo.WorkspaceRef{Id: workspaceID.String()})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *TodosClient) ListWorkspaces(ctx context.Context, userID int) ([]*models.WorkspaceDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListWorkspaces")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ListWorkspaces(ctx, &todo.UserID{Id: int32(userID)})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	workspaces := make([]*models.WorkspaceDTO, 0, len(res.Workspaces))
+	for _, w := range res.Workspaces {
+		workspaces = append(workspaces, models.NewEmptyWorkspaceDTO().FromGRPC(w))
+	}
+
+	return workspaces, nil
+}
+
+func (c *TodosClient) GetWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) (*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetWorkspaceMember")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.GetWorkspaceMember(ctx, &todo.WorkspaceMemberRef{
+		WorkspaceId: workspaceID.String(),
+		UserId:      int32(userID),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyWorkspaceMemberDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListWorkspaceMembers")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ListWorkspaceMembers(ctx, &todo.WorkspaceRef{Id: workspaceID.String()})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	members := make([]*models.WorkspaceMemberDTO, 0, len(res.Members))
+	for _, m := range res.Members {
+		members = append(members, models.NewEmptyWorkspaceMemberDTO().FromGRPC(m))
+	}
+
+	return members, nil
+}
+
+func (c *TodosClient) UpdateWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int, role string) (*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateWorkspaceMember")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.UpdateWorkspaceMember(ctx, &todo.WorkspaceMemberDTO{
+		WorkspaceId: workspaceID.String(),
+		UserId:      int32(userID),
+		Role:        role,
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyWorkspaceMemberDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) RemoveWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RemoveWorkspaceMember")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RemoveWorkspaceMember(ctx, &todo.WorkspaceMemberRef{
+		WorkspaceId: workspaceID.String(),
+		UserId:      int32(userID),
+	})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *TodosClient) InviteWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, invitedBy int, invitation *models.InviteWorkspaceMemberRequestDTO) (*models.WorkspaceInvitationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.InviteWorkspaceMember")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.InviteWorkspaceMember(ctx, &todo.InviteWorkspaceMemberDTO{
+		WorkspaceId: workspaceID.String(),
+		Email:       invitation.Email,
+		Role:        invitation.Role,
+		InvitedBy:   int32(invitedBy),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyWorkspaceInvitationDTO().FromGRPC(res), nil
+}
+
+func (c *TodosClient) AcceptWorkspaceInvitation(ctx context.Context, token string, userID int) (*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.AcceptWorkspaceInvitation")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.AcceptWorkspaceInvitation(ctx, &todo.AcceptWorkspaceInvitationDTO{
+		Token:  token,
+		UserId: int32(userID),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyWorkspaceMemberDTO().FromGRPC(res), nil
+}
+
+// convertError переводит статусы grpc-ответа сервиса задач в ошибки приложения
+func convertError(err error) error {
+	if validationErr, ok := validator.FromGRPCError(err); ok {
+		return validationErr
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound:
+		return app_errors.ErrNotFound
+	case codes.PermissionDenied:
+		return app_errors.ErrForbidden
+	case codes.AlreadyExists:
+		return app_errors.ErrAlreadyExists
+	case codes.FailedPrecondition:
+		return app_errors.ErrTodoBlocked
+	case codes.Aborted:
+		return app_errors.ErrVersionConflict
+	}
+
+	return err
+}
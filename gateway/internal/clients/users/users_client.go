@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"gateway/config"
+	"gateway/internal/app_errors"
 	"gateway/internal/models"
 	"gateway/pkg/ctxutil"
 	"gateway/pkg/grpc_stubs/users"
+	"gateway/pkg/validator"
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type UsersClient struct {
@@ -42,27 +46,31 @@ func (c *UsersClient) CreateUser(ctx context.Context, user *models.CreateUserDTO
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
 	res, err := c.client.RegisterUser(ctx, user.ToGRPC())
 	if err != nil {
-		return 0, err
+		return 0, convertError(err)
 	}
 
 	return int(res.Id), nil
 }
 
-func (c *UsersClient) UpdateUser(ctx context.Context, user *models.UserDTO) error {
+func (c *UsersClient) UpdateUser(ctx context.Context, user *models.UserDTO) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateUser")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	_, err := c.client.UpdateUser(ctx, &users.UserDTO{
-		Id:       int32(user.ID),
-		Username: user.Username,
-		Email:    user.Email,
+	updated, err := c.client.UpdateUser(ctx, &users.UserDTO{
+		Id:          int32(user.ID),
+		Username:    user.Username,
+		Email:       user.Email,
+		DisplayName: user.DisplayName,
+		Timezone:    user.Timezone,
+		Locale:      user.Locale,
+		Version:     int32(user.Version),
 	})
 	if err != nil {
-		return err
+		return nil, convertError(err)
 	}
 
-	return nil
+	return models.NewEmptyUserDTO().FromGRPC(updated), nil
 }
 
 func (c *UsersClient) UpdatePassword(ctx context.Context, data *models.UpdateUserPasswordDTO) error {
@@ -72,22 +80,38 @@ func (c *UsersClient) UpdatePassword(ctx context.Context, data *models.UpdateUse
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
 	_, err := c.client.UpdatePassword(ctx, data.ToGRPC())
 	if err != nil {
-		return err
+		return convertError(err)
 	}
 
 	return nil
 }
 
-func (c *UsersClient) DeleteUser(ctx context.Context, userID int) error {
+func (c *UsersClient) DeleteUser(ctx context.Context, userID, version int) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DeleteUser")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	_, err := c.client.DeleteUser(ctx, &users.UserID{
+	user, err := c.client.DeleteUser(ctx, &users.DeleteUserRequest{
+		Id:      int32(userID),
+		Version: int32(version),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+func (c *UsersClient) DeactivateUser(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DeactivateUser")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.DeactivateUser(ctx, &users.UserID{
 		Id: int32(userID),
 	})
 	if err != nil {
-		return err
+		return convertError(err)
 	}
 
 	return nil
@@ -102,7 +126,7 @@ func (c *UsersClient) GetUserByID(ctx context.Context, userID int) (*models.User
 		Id: int32(userID),
 	})
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return models.NewEmptyUserDTO().FromGRPC(user), nil
@@ -118,7 +142,7 @@ func (c *UsersClient) GetUserByUsernameOrEmail(ctx context.Context, username, em
 		Email:    email,
 	})
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return models.NewEmptyUserDTO().FromGRPC(user), nil
@@ -133,7 +157,7 @@ func (c *UsersClient) GetUserByUsername(ctx context.Context, username string) (*
 		Username: username,
 	})
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return models.NewEmptyUserDTO().FromGRPC(user), nil
@@ -146,8 +170,266 @@ func (c *UsersClient) UserLogin(ctx context.Context, data *models.UserLoginDTO)
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
 	user, err := c.client.Login(ctx, data.ToGRPC())
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return models.NewEmptyUserDTO().FromGRPC(user), nil
 }
+
+func (c *UsersClient) ListUsers(ctx context.Context, limit, offset int) ([]*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListUsers")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ListUsers(ctx, &users.ListUsersRequest{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	result := make([]*models.UserDTO, 0, len(res.Users))
+	for _, user := range res.Users {
+		result = append(result, models.NewEmptyUserDTO().FromGRPC(user))
+	}
+
+	return result, nil
+}
+
+func (c *UsersClient) UpdateUserRole(ctx context.Context, request *models.UpdateUserRoleDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateUserRole")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.UpdateUserRole(ctx, request.ToGRPC())
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) EnrollTOTP(ctx context.Context, userID int) (*models.TOTPEnrollmentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.EnrollTOTP")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.EnrollTOTP(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, convertMFAError(err)
+	}
+
+	return new(models.TOTPEnrollmentDTO).FromGRPC(res), nil
+}
+
+func (c *UsersClient) ConfirmTOTP(ctx context.Context, request *models.MFACodeDTO) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ConfirmTOTP")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ConfirmTOTP(ctx, request.ToGRPC())
+	if err != nil {
+		return nil, convertMFAError(err)
+	}
+
+	return res.Codes, nil
+}
+
+func (c *UsersClient) DisableTOTP(ctx context.Context, request *models.MFACodeDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DisableTOTP")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.DisableTOTP(ctx, request.ToGRPC())
+	if err != nil {
+		return convertMFAError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) VerifyMFA(ctx context.Context, request *models.MFACodeDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.VerifyMFA")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	user, err := c.client.VerifyMFA(ctx, request.ToGRPC())
+	if err != nil {
+		return nil, convertMFAError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+func (c *UsersClient) ReportAccountLocked(ctx context.Context, event *models.AccountLockedDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ReportAccountLocked")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.ReportAccountLocked(ctx, event.ToGRPC())
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) GetUserByExternalIdentity(ctx context.Context, identity *models.ExternalIdentityDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetUserByExternalIdentity")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	user, err := c.client.GetUserByExternalIdentity(ctx, identity.ToGRPC())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+func (c *UsersClient) LinkExternalIdentity(ctx context.Context, identity *models.ExternalIdentityDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.LinkExternalIdentity")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	user, err := c.client.LinkExternalIdentity(ctx, identity.ToGRPC())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+func (c *UsersClient) CreateAPIKey(ctx context.Context, request *models.CreateAPIKeyDTO) (*models.APIKeyDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateAPIKey")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	key, err := c.client.CreateAPIKey(ctx, request.ToGRPC())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyAPIKeyDTO().FromGRPC(key), nil
+}
+
+func (c *UsersClient) ListAPIKeys(ctx context.Context, userID int) ([]*models.APIKeyDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListAPIKeys")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	res, err := c.client.ListAPIKeys(ctx, &users.UserID{Id: int32(userID)})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	result := make([]*models.APIKeyDTO, 0, len(res.Keys))
+	for _, key := range res.Keys {
+		result = append(result, models.NewEmptyAPIKeyDTO().FromGRPC(key))
+	}
+
+	return result, nil
+}
+
+func (c *UsersClient) RevokeAPIKey(ctx context.Context, keyID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RevokeAPIKey")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RevokeAPIKey(ctx, &users.APIKeyID{Id: int32(keyID), UserId: int32(userID)})
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKeyPrincipalDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.AuthenticateAPIKey")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	principal, err := c.client.AuthenticateAPIKey(ctx, &users.APIKeySecret{Key: key})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyAPIKeyPrincipalDTO().FromGRPC(principal), nil
+}
+
+// UpdateAvatar сохраняет ключ нового аватара и возвращает прежний
+func (c *UsersClient) UpdateAvatar(ctx context.Context, userID int, avatarKey string) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateAvatar")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	previous, err := c.client.UpdateAvatar(ctx, &users.AvatarDTO{Id: int32(userID), AvatarKey: avatarKey})
+	if err != nil {
+		return "", convertError(err)
+	}
+
+	return previous.AvatarKey, nil
+}
+
+// RequestEmailChange сохраняет новый email до подтверждения и отправляет письма
+func (c *UsersClient) RequestEmailChange(ctx context.Context, request *models.EmailChangeRequestDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RequestEmailChange")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RequestEmailChange(ctx, request.ToGRPC())
+	if err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange применяет новый email по токену из письма
+func (c *UsersClient) ConfirmEmailChange(ctx context.Context, token string) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ConfirmEmailChange")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	user, err := c.client.ConfirmEmailChange(ctx, &users.EmailChangeTokenDTO{Token: token})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+// convertError переводит статусы grpc-ответа сервиса пользователей в ошибки приложения
+func convertError(err error) error {
+	if validationErr, ok := validator.FromGRPCError(err); ok {
+		return validationErr
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound:
+		return app_errors.ErrNotFound
+	case codes.AlreadyExists:
+		return app_errors.ErrUsernameOrEmailIsUsed
+	case codes.Unauthenticated:
+		return app_errors.ErrWrongCredentials
+	case codes.Aborted:
+		return app_errors.ErrVersionConflict
+	}
+
+	return err
+}
+
+// convertMFAError - то же, что convertError, но Unauthenticated здесь означает неверный код второго фактора
+func convertMFAError(err error) error {
+	switch status.Code(err) {
+	case codes.Unauthenticated:
+		return app_errors.ErrInvalidMFACode
+	case codes.FailedPrecondition:
+		return app_errors.ErrMFAStateConflict
+	}
+
+	return convertError(err)
+}
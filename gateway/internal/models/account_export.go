@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// форматы выгрузки данных учетной записи
+const (
+	ExportFormatJSON = "json"
+	ExportFormatZIP  = "zip"
+)
+
+// AccountExportDTO - все данные пользователя, которые хранит сервис (GDPR выгрузка)
+type AccountExportDTO struct {
+	ExportedAt time.Time     `json:"exported_at"`
+	Profile    *UserDTO      `json:"profile"`
+	Todos      []*TodoDTO    `json:"todos"`
+	Sessions   []*SessionDTO `json:"sessions"`
+	APIKeys    []*APIKeyDTO  `json:"api_keys"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"gateway/pkg/grpc_stubs/users"
+	"time"
+)
+
+// права API ключей
+const (
+	ScopeTodosRead  = "todos:read"
+	ScopeTodosWrite = "todos:write"
+	ScopeUsersRead  = "users:read"
+)
+
+// CreateAPIKeyDTO - data transfer object - струтктура для создания API ключа
+type CreateAPIKeyDTO struct {
+	UserID    int        `json:"-"`
+	Name      string     `json:"name" example:"ci" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" example:"todos:read,todos:write" validate:"required,min=1,dive,oneof=todos:read todos:write users:read"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" validate:"omitempty,gt"`
+}
+
+func (d *CreateAPIKeyDTO) ToGRPC() *users.CreateAPIKeyDTO {
+	var expiresAt int64
+	if d.ExpiresAt != nil {
+		expiresAt = d.ExpiresAt.Unix()
+	}
+
+	return &users.CreateAPIKeyDTO{
+		UserId:    int32(d.UserID),
+		Name:      d.Name,
+		Scopes:    d.Scopes,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// APIKeyDTO - data transfer object - API ключ пользователя. Key возвращается только при создании
+type APIKeyDTO struct {
+	ID         int        `json:"id" example:"1"`
+	Name       string     `json:"name" example:"ci"`
+	Prefix     string     `json:"prefix" example:"tdk_Xq3Zb1Ae"`
+	Scopes     []string   `json:"scopes" example:"todos:read"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Key        string     `json:"key,omitempty" example:"tdk_Xq3Zb1Ae..."`
+}
+
+func NewEmptyAPIKeyDTO() *APIKeyDTO {
+	return &APIKeyDTO{}
+}
+
+func (d *APIKeyDTO) FromGRPC(in *users.APIKeyDTO) *APIKeyDTO {
+	d.ID = int(in.Id)
+	d.Name = in.Name
+	d.Prefix = in.Prefix
+	d.Scopes = in.Scopes
+	d.ExpiresAt = timeOrNil(in.ExpiresAt)
+	d.LastUsedAt = timeOrNil(in.LastUsedAt)
+	d.CreatedAt = time.Unix(in.CreatedAt, 0).UTC()
+	d.Key = in.Key
+	return d
+}
+
+// APIKeyPrincipalDTO - пользователь и права, которые дает предъявленный API ключ
+type APIKeyPrincipalDTO struct {
+	KeyID  int
+	UserID int
+	Role   string
+	Scopes []string
+}
+
+func NewEmptyAPIKeyPrincipalDTO() *APIKeyPrincipalDTO {
+	return &APIKeyPrincipalDTO{}
+}
+
+func (d *APIKeyPrincipalDTO) FromGRPC(in *users.APIKeyPrincipal) *APIKeyPrincipalDTO {
+	d.KeyID = int(in.KeyId)
+	d.UserID = int(in.UserId)
+	d.Role = in.Role
+	d.Scopes = in.Scopes
+	return d
+}
+
+func timeOrNil(unix int64) *time.Time {
+	if unix == 0 {
+		return nil
+	}
+
+	t := time.Unix(unix, 0).UTC()
+	return &t
+}
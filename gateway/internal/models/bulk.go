@@ -0,0 +1,96 @@
+package models
+
+import (
+	"gateway/pkg/grpc_stubs/todo"
+	"github.com/google/uuid"
+)
+
+// операции над несколькими задачами
+const (
+	BulkOperationReassign = "reassign"
+	BulkOperationRelabel  = "relabel"
+	BulkOperationComplete = "complete"
+	BulkOperationDelete   = "delete"
+)
+
+// BulkMaxItems - сколько задач можно изменить одним запросом
+const BulkMaxItems = 500
+
+// причины, по которым операция не применилась к задаче. Остальные причины приходят из сервиса задач
+const (
+	BulkErrorNotFound          = "not_found"
+	BulkErrorForbidden         = "forbidden"
+	BulkErrorAssigneeNotMember = "assignee_not_member"
+)
+
+// BulkTodosFilterDTO - задачи, к которым применяется операция, если идентификаторы не переданы
+type BulkTodosFilterDTO struct {
+	LabelIDs   []uuid.UUID `json:"label_ids"`
+	LabelMatch string      `json:"label_match" validate:"omitempty,oneof=any all" example:"any"`
+	// WorkspaceID - задачи рабочего пространства вместо задач пользователя
+	WorkspaceID *uuid.UUID `json:"workspace_id"`
+}
+
+// ToListTodosDTO - фильтр списка задач отправителя запроса
+func (f *BulkTodosFilterDTO) ToListTodosDTO(userID int) *ListTodosDTO {
+	return &ListTodosDTO{
+		UserID:      userID,
+		LabelIDs:    f.LabelIDs,
+		LabelMatch:  f.LabelMatch,
+		WorkspaceID: f.WorkspaceID,
+	}
+}
+
+type BulkUpdateTodosDTO struct {
+	IDs       []uuid.UUID         `json:"ids" validate:"required_without=Filter,excluded_with=Filter,max=500"`
+	Filter    *BulkTodosFilterDTO `json:"filter" validate:"required_without=IDs"`
+	Operation string              `json:"operation" validate:"required,oneof=reassign relabel complete delete" example:"reassign"`
+	Assignee  int                 `json:"assignee" validate:"required_if=Operation reassign,min=0" example:"2"`
+	// AddLabelIDs и RemoveLabelIDs - метки отправителя для relabel
+	AddLabelIDs    []uuid.UUID `json:"add_label_ids" validate:"max=50"`
+	RemoveLabelIDs []uuid.UUID `json:"remove_label_ids" validate:"max=50"`
+}
+
+func (b *BulkUpdateTodosDTO) ToGRPC(actor int, ids []uuid.UUID) *todo.BulkUpdateTodosRequest {
+	return &todo.BulkUpdateTodosRequest{
+		Actor:          int32(actor),
+		Ids:            uuidsToStrings(ids),
+		Operation:      b.Operation,
+		Assignee:       int32(b.Assignee),
+		AddLabelIds:    uuidsToStrings(b.AddLabelIDs),
+		RemoveLabelIds: uuidsToStrings(b.RemoveLabelIDs),
+	}
+}
+
+// BulkItemResultDTO - результат операции над одной задачей, Error пустой при успехе
+type BulkItemResultDTO struct {
+	ID    uuid.UUID `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	Error string    `json:"error,omitempty" example:"version_conflict"`
+}
+
+type BulkUpdateTodosResultDTO struct {
+	Results   []BulkItemResultDTO `json:"results"`
+	Succeeded int                 `json:"succeeded" example:"1"`
+	Failed    int                 `json:"failed" example:"0"`
+}
+
+func (r *BulkUpdateTodosResultDTO) FromGRPC(in *todo.BulkUpdateTodosResult) *BulkUpdateTodosResultDTO {
+	r.Results = make([]BulkItemResultDTO, 0, len(in.Results))
+	for _, item := range in.Results {
+		id, _ := uuid.Parse(item.Id)
+		r.Results = append(r.Results, BulkItemResultDTO{ID: id, Error: item.Error})
+	}
+	r.Succeeded = int(in.Succeeded)
+	r.Failed = int(in.Failed)
+	return r
+}
+
+// Add добавляет результат по задаче и обновляет счетчики
+func (r *BulkUpdateTodosResultDTO) Add(id uuid.UUID, errCode string) {
+	r.Results = append(r.Results, BulkItemResultDTO{ID: id, Error: errCode})
+	if errCode == "" {
+		r.Succeeded++
+	} else {
+		r.Failed++
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"gateway/pkg/grpc_stubs/todo"
+	"github.com/google/uuid"
+	"time"
+)
+
+type CommentDTO struct {
+	ID        uuid.UUID  `json:"id" example:"0b6f1c9e-3c2a-4f5e-9d7b-8a1e2f3c4d5e"`
+	TodoID    uuid.UUID  `json:"todo_id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	Author    int        `json:"author" example:"1"`
+	Body      string     `json:"body" example:"comment text"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+}
+
+func NewEmptyCommentDTO() *CommentDTO {
+	return &CommentDTO{}
+}
+
+func (c *CommentDTO) FromGRPC(in *todo.CommentDTO) *CommentDTO {
+	c.ID, _ = uuid.Parse(in.Id)
+	c.TodoID, _ = uuid.Parse(in.TodoId)
+	c.Author = int(in.Author)
+	c.Body = in.Body
+	c.CreatedAt = in.GetCreatedAt().AsTime()
+	if in.EditedAt != nil {
+		editedAt := in.EditedAt.AsTime()
+		c.EditedAt = &editedAt
+	}
+	return c
+}
+
+// CommentRequestDTO - текст нового или отредактированного комментария
+type CommentRequestDTO struct {
+	Body string `json:"body" example:"comment text" validate:"comment"`
+}
+
+type CommentListDTO struct {
+	Comments []*CommentDTO `json:"comments"`
+	Total    int           `json:"total" example:"1"`
+}
+
+func (c *CommentListDTO) FromGRPC(in *todo.CommentList) *CommentListDTO {
+	c.Comments = make([]*CommentDTO, 0, len(in.Comments))
+	for _, comment := range in.Comments {
+		c.Comments = append(c.Comments, NewEmptyCommentDTO().FromGRPC(comment))
+	}
+	c.Total = int(in.Total)
+	return c
+}
@@ -0,0 +1,16 @@
+package models
+
+import "gateway/pkg/grpc_stubs/users"
+
+// EmailChangeRequestDTO - data transfer object - струтктура для запроса смены email
+type EmailChangeRequestDTO struct {
+	UserID   int    `json:"-"`
+	NewEmail string `json:"new_email" example:"new@example.com" validate:"required,email_address"`
+}
+
+func (d *EmailChangeRequestDTO) ToGRPC() *users.EmailChangeRequestDTO {
+	return &users.EmailChangeRequestDTO{
+		UserId:   int32(d.UserID),
+		NewEmail: d.NewEmail,
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"gateway/pkg/grpc_stubs/todo"
+	"github.com/google/uuid"
+	"time"
+)
+
+// TodoEventDTO - изменение одного поля задачи. Пустое значение означает, что поле не было задано
+type TodoEventDTO struct {
+	ID        uuid.UUID `json:"id" example:"3f1e2d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"`
+	TodoID    uuid.UUID `json:"todo_id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	Actor     int       `json:"actor" example:"1"`
+	Field     string    `json:"field" example:"assignee"`
+	OldValue  string    `json:"old_value" example:"2"`
+	NewValue  string    `json:"new_value" example:"3"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewEmptyTodoEventDTO() *TodoEventDTO {
+	return &TodoEventDTO{}
+}
+
+func (e *TodoEventDTO) FromGRPC(in *todo.TodoEventDTO) *TodoEventDTO {
+	e.ID, _ = uuid.Parse(in.Id)
+	e.TodoID, _ = uuid.Parse(in.TodoId)
+	e.Actor = int(in.Actor)
+	e.Field = in.Field
+	e.OldValue = in.OldValue
+	e.NewValue = in.NewValue
+	e.CreatedAt = in.GetCreatedAt().AsTime()
+	return e
+}
+
+type TodoEventListDTO struct {
+	Events []*TodoEventDTO `json:"events"`
+	Total  int             `json:"total" example:"1"`
+}
+
+func (l *TodoEventListDTO) FromGRPC(in *todo.TodoEventList) *TodoEventListDTO {
+	l.Events = make([]*TodoEventDTO, 0, len(in.Events))
+	for _, event := range in.Events {
+		l.Events = append(l.Events, NewEmptyTodoEventDTO().FromGRPC(event))
+	}
+	l.Total = int(in.Total)
+	return l
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"gateway/pkg/grpc_stubs/todo"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"time"
+)
+
+// форматы выгрузки и загрузки задач
+const (
+	TodoFormatCSV   = "csv"
+	TodoFormatJSON  = "json"
+	TodoFormatJSONL = "jsonl"
+	TodoFormatICal  = "ical"
+)
+
+// ImportMaxRows - сколько задач можно импортировать одним запросом
+const ImportMaxRows = 1000
+
+// TodoExportDTO - задача в выгрузке. Пользователи указаны именами, чтобы выгрузку можно было загрузить обратно
+type TodoExportDTO struct {
+	ID          uuid.UUID  `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	Description string     `json:"description" example:"todo description"`
+	Assignee    string     `json:"assignee" example:"john"`
+	CreatedBy   string     `json:"created_by" example:"jane"`
+	Done        bool       `json:"done" example:"false"`
+	DueAt       *time.Time `json:"due_at,omitempty" example:"2024-03-04T09:00:00Z"`
+	Recurrence  string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	Timezone    string     `json:"timezone,omitempty" example:"Europe/Berlin"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ImportTodoRowDTO - строка импортируемого файла. Пустой Assignee назначает задачу на отправителя запроса
+type ImportTodoRowDTO struct {
+	// Row - номер строки в файле, с которым строка попадает в результат
+	Row         int        `json:"-"`
+	Description string     `json:"description" example:"todo description"`
+	Assignee    string     `json:"assignee" example:"john"`
+	Done        bool       `json:"done" example:"false"`
+	DueAt       *time.Time `json:"due_at,omitempty" example:"2024-03-04T09:00:00Z"`
+	Recurrence  string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	Timezone    string     `json:"timezone,omitempty" example:"Europe/Berlin"`
+	// Errors - ошибки разбора строки, такая строка не импортируется
+	Errors []validator.FieldViolation `json:"-"`
+}
+
+type ImportTodosDTO struct {
+	// WorkspaceID - рабочее пространство, в котором создаются задачи
+	WorkspaceID *uuid.UUID          `json:"workspace_id"`
+	DryRun      bool                `json:"dry_run"`
+	Rows        []*ImportTodoRowDTO `json:"rows" validate:"min=1,max=1000"`
+}
+
+// ImportTodoDTO - строка импорта, в которой имя исполнителя уже заменено на его идентификатор
+type ImportTodoDTO struct {
+	Row  int
+	Todo *CreateTodoDTO
+	Done bool
+}
+
+// ImportTodosRequestDTO - запрос к сервису задач со строками, которые прошли проверку в gateway
+type ImportTodosRequestDTO struct {
+	CreatedBy   int
+	WorkspaceID *uuid.UUID
+	DryRun      bool
+	Rows        []*ImportTodoDTO
+}
+
+func (i *ImportTodosRequestDTO) ToGRPC() *todo.ImportTodosRequest {
+	res := &todo.ImportTodosRequest{
+		CreatedBy:   int32(i.CreatedBy),
+		WorkspaceId: optionalUUIDToString(i.WorkspaceID),
+		DryRun:      i.DryRun,
+		Rows:        make([]*todo.ImportTodoRow, 0, len(i.Rows)),
+	}
+	for _, row := range i.Rows {
+		res.Rows = append(res.Rows, &todo.ImportTodoRow{Row: int32(row.Row), Todo: row.Todo.ToGRPC(), Done: row.Done})
+	}
+
+	return res
+}
+
+// ImportRowResultDTO - результат импорта строки: созданная задача или ошибки в ее полях
+type ImportRowResultDTO struct {
+	Row    int                        `json:"row" example:"2"`
+	ID     *uuid.UUID                 `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	Errors []validator.FieldViolation `json:"errors,omitempty"`
+}
+
+type ImportTodosResultDTO struct {
+	DryRun   bool                 `json:"dry_run" example:"false"`
+	Rows     []ImportRowResultDTO `json:"rows"`
+	Imported int                  `json:"imported" example:"1"`
+	Failed   int                  `json:"failed" example:"0"`
+}
+
+func (r *ImportTodosResultDTO) FromGRPC(in *todo.ImportTodosResult) *ImportTodosResultDTO {
+	r.Rows = make([]ImportRowResultDTO, 0, len(in.Rows))
+	for _, row := range in.Rows {
+		item := ImportRowResultDTO{Row: int(row.Row), ID: stringToOptionalUUID(row.Id)}
+		for _, e := range row.Errors {
+			item.Errors = append(item.Errors, validator.FieldViolation{Field: e.Field, Message: e.Message})
+		}
+		r.Rows = append(r.Rows, item)
+	}
+	r.Imported = int(in.Imported)
+	r.Failed = int(in.Failed)
+	return r
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"gateway/pkg/grpc_stubs/todo"
+	"github.com/google/uuid"
+	"time"
+)
+
+// режимы фильтрации задач по меткам
+const (
+	LabelMatchAny = "any"
+	LabelMatchAll = "all"
+)
+
+type LabelDTO struct {
+	ID        uuid.UUID `json:"id" example:"5d1c3b0e-8f4a-4b6e-9c2d-7a8b9c0d1e2f"`
+	Owner     int       `json:"owner" example:"1"`
+	Name      string    `json:"name" example:"urgent"`
+	Color     string    `json:"color" example:"#ff0000"`
+	CreatedAt time.Time `json:"created_at"`
+	// WorkspaceID - метка рабочего пространства, у личных меток не задано
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty" example:"0b9d7c1e-4f3a-4c2b-9e8d-1a2b3c4d5e6f"`
+}
+
+func NewEmptyLabelDTO() *LabelDTO {
+	return &LabelDTO{}
+}
+
+func (l *LabelDTO) FromGRPC(in *todo.LabelDTO) *LabelDTO {
+	l.ID, _ = uuid.Parse(in.Id)
+	l.Owner = int(in.Owner)
+	l.Name = in.Name
+	l.Color = in.Color
+	l.CreatedAt = in.GetCreatedAt().AsTime()
+	l.WorkspaceID = stringToOptionalUUID(in.WorkspaceId)
+	return l
+}
+
+// LabelRequestDTO - имя и цвет новой или изменяемой метки
+type LabelRequestDTO struct {
+	Name  string `json:"name" example:"urgent" validate:"label_name"`
+	Color string `json:"color" example:"#ff0000" validate:"required,hexcolor"`
+}
+
+// CreateLabelDTO - новая метка отправителя запроса, личная или рабочего пространства
+type CreateLabelDTO struct {
+	Owner       int
+	WorkspaceID *uuid.UUID
+	*LabelRequestDTO
+}
+
+func (l *CreateLabelDTO) ToGRPC() *todo.CreateLabelDTO {
+	return &todo.CreateLabelDTO{
+		Owner:       int32(l.Owner),
+		Name:        l.Name,
+		Color:       l.Color,
+		WorkspaceId: optionalUUIDToString(l.WorkspaceID),
+	}
+}
+
+// ListLabelsDTO - личные метки пользователя или метки рабочего пространства
+type ListLabelsDTO struct {
+	UserID      int
+	WorkspaceID *uuid.UUID
+}
+
+func (l *ListLabelsDTO) ToGRPC() *todo.ListLabelsRequest {
+	return &todo.ListLabelsRequest{
+		UserId:      int32(l.UserID),
+		WorkspaceId: optionalUUIDToString(l.WorkspaceID),
+	}
+}
+
+// ListTodosDTO - фильтр списка задач пользователя
+type ListTodosDTO struct {
+	UserID     int         `json:"user_id"`
+	LabelIDs   []uuid.UUID `json:"labels"`
+	LabelMatch string      `json:"match" validate:"omitempty,oneof=any all"`
+	Limit      int         `json:"limit"`
+	Offset     int         `json:"offset"`
+	// WorkspaceID - вместо задач пользователя вернуть задачи рабочего пространства
+	WorkspaceID *uuid.UUID `json:"workspace_id"`
+}
+
+func (l *ListTodosDTO) ToGRPC() *todo.ListTodosRequest {
+	return &todo.ListTodosRequest{
+		UserId:      int32(l.UserID),
+		LabelIds:    uuidsToStrings(l.LabelIDs),
+		LabelMatch:  l.LabelMatch,
+		Limit:       int32(l.Limit),
+		Offset:      int32(l.Offset),
+		WorkspaceId: optionalUUIDToString(l.WorkspaceID),
+	}
+}
+
+func uuidsToStrings(ids []uuid.UUID) []string {
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, id.String())
+	}
+	return res
+}
+
+// stringsToUUIDs пропускает строки, которые не являются uuid
+func stringsToUUIDs(ids []string) []uuid.UUID {
+	res := make([]uuid.UUID, 0, len(ids))
+	for _, raw := range ids {
+		if id, err := uuid.Parse(raw); err == nil {
+			res = append(res, id)
+		}
+	}
+	return res
+}
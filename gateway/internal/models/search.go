@@ -0,0 +1,40 @@
+package models
+
+import "gateway/pkg/grpc_stubs/todo"
+
+type SearchResultDTO struct {
+	Todo *TodoDTO `json:"todo"`
+	Rank float32  `json:"rank" example:"0.0759"`
+	// фрагменты экранированы, найденные слова выделены тегом <mark>
+	DescriptionSnippet string `json:"description_snippet,omitempty" example:"<mark>deploy</mark> to production"`
+	CommentSnippet     string `json:"comment_snippet,omitempty" example:"after the <mark>deploy</mark>"`
+}
+
+func NewEmptySearchResultDTO() *SearchResultDTO {
+	return &SearchResultDTO{}
+}
+
+func (r *SearchResultDTO) FromGRPC(in *todo.SearchResult) *SearchResultDTO {
+	r.Todo = NewEmptyTodoDTO().FromGRPC(in.GetTodo())
+	r.Rank = in.Rank
+	r.DescriptionSnippet = in.DescriptionSnippet
+	r.CommentSnippet = in.CommentSnippet
+	return r
+}
+
+// SearchTodosDTO - поисковый запрос по задачам пользователя
+type SearchTodosDTO struct {
+	UserID int    `json:"user_id"`
+	Query  string `json:"q" validate:"required,max=200"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+func (s *SearchTodosDTO) ToGRPC() *todo.SearchTodosRequest {
+	return &todo.SearchTodosRequest{
+		UserId: int32(s.UserID),
+		Query:  s.Query,
+		Limit:  int32(s.Limit),
+		Offset: int32(s.Offset),
+	}
+}
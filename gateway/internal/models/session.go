@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// SessionDTO - data transfer object - струтктура для передачи данных об активной сессии пользователя
+type SessionDTO struct {
+	ID        string    `json:"id" example:"5f0c6a1e-7a8b-4c2d-9e3f-1a2b3c4d5e6f"`
+	Device    string    `json:"device,omitempty" example:"iPhone"`
+	UserAgent string    `json:"user_agent,omitempty" example:"Mozilla/5.0"`
+	IP        string    `json:"ip,omitempty" example:"203.0.113.10"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	Current   bool      `json:"current" example:"true"`
+}
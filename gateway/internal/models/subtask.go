@@ -0,0 +1,41 @@
+package models
+
+import (
+	"gateway/pkg/grpc_stubs/todo"
+	"github.com/google/uuid"
+)
+
+// TodoNodeDTO - задача вместе с подзадачами, которые видит пользователь
+type TodoNodeDTO struct {
+	TodoDTO
+	Subtasks []*TodoNodeDTO `json:"subtasks"`
+}
+
+func NewEmptyTodoNodeDTO() *TodoNodeDTO {
+	return &TodoNodeDTO{}
+}
+
+func (n *TodoNodeDTO) FromGRPC(in *todo.TodoNode) *TodoNodeDTO {
+	n.TodoDTO = *NewEmptyTodoDTO().FromGRPC(in.GetTodo())
+	n.Subtasks = make([]*TodoNodeDTO, 0, len(in.Subtasks))
+	for _, sub := range in.Subtasks {
+		n.Subtasks = append(n.Subtasks, NewEmptyTodoNodeDTO().FromGRPC(sub))
+	}
+	return n
+}
+
+func optionalUUIDToString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// stringToOptionalUUID возвращает nil для пустой строки и строки, которая не является uuid
+func stringToOptionalUUID(raw string) *uuid.UUID {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
@@ -1,13 +1,40 @@
 package models
 
-import "gateway/pkg/grpc_stubs/users"
+import (
+	"fmt"
+	"gateway/pkg/grpc_stubs/users"
+	"path"
+	"time"
+)
+
+// роли пользователей
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
 
 // UserDTO - data transfer object - общая струтктура для передачи данных пользователя
 type UserDTO struct {
-	ID       int    `json:"id,omitempty" example:"1"`
-	Username string `json:"username" example:"username"`
-	Password string `json:"password,omitempty" example:"password"`
-	Email    string `json:"email,omitempty" example:"user@example.com"`
+	ID          int    `json:"id,omitempty" example:"1" validate:"required,min=1"`
+	Username    string `json:"username" example:"username" validate:"required,username"`
+	Password    string `json:"password,omitempty" example:"password"`
+	Email       string `json:"email,omitempty" example:"user@example.com" validate:"omitempty,email_address"`
+	Role        string `json:"role,omitempty" example:"user"`
+	MFAEnabled  bool   `json:"mfa_enabled,omitempty" example:"false"`
+	DisplayName string `json:"display_name,omitempty" example:"John Doe" validate:"omitempty,max=64"`
+	Timezone    string `json:"timezone,omitempty" example:"Europe/Moscow" validate:"omitempty,timezone"`
+	Locale      string `json:"locale,omitempty" example:"ru-RU" validate:"omitempty,bcp47_language_tag"`
+	// AvatarURL - адрес аватара в gateway, меняется при каждой загрузке нового аватара
+	AvatarURL string `json:"avatar_url,omitempty" example:"/api/v1/users/1/avatar?v=3f2a9c"`
+	// AvatarKey - префикс файлов аватара в хранилище, клиенту не отдается
+	AvatarKey string `json:"-" swaggerignore:"true"`
+	// Status - active, deactivated или pending_deletion
+	Status string `json:"status,omitempty" example:"active"`
+	// DeleteAfter - момент, после которого учетная запись будет обезличена
+	DeleteAfter *time.Time `json:"delete_after,omitempty"`
+	// Version увеличивается при каждом изменении пользователя и отдается в заголовке ETag.
+	// При изменении берется из заголовка If-Match
+	Version int `json:"version,omitempty" example:"1"`
 }
 
 func NewEmptyUserDTO() *UserDTO {
@@ -16,10 +43,17 @@ func NewEmptyUserDTO() *UserDTO {
 
 func (d *UserDTO) ToGRPC() *users.UserDTO {
 	return &users.UserDTO{
-		Id:       int32(d.ID),
-		Username: d.Username,
-		Password: d.Password,
-		Email:    d.Email,
+		Id:          int32(d.ID),
+		Username:    d.Username,
+		Password:    d.Password,
+		Email:       d.Email,
+		Role:        d.Role,
+		MfaEnabled:  d.MFAEnabled,
+		DisplayName: d.DisplayName,
+		Timezone:    d.Timezone,
+		Locale:      d.Locale,
+		AvatarKey:   d.AvatarKey,
+		Version:     int32(d.Version),
 	}
 }
 
@@ -28,16 +62,35 @@ func (d *UserDTO) FromGRPC(in *users.UserDTO) *UserDTO {
 	d.Email = in.Email
 	d.Username = in.Username
 	d.Password = in.Password
+	d.Role = in.Role
+	d.MFAEnabled = in.MfaEnabled
+	d.DisplayName = in.DisplayName
+	d.Timezone = in.Timezone
+	d.Locale = in.Locale
+	d.AvatarKey = in.AvatarKey
+	d.AvatarURL = avatarURL(d.ID, d.AvatarKey)
+	d.Status = in.Status
+	d.DeleteAfter = timeOrNil(in.DeleteAfter)
+	d.Version = int(in.Version)
 	return d
 }
 
+// avatarURL - адрес аватара с версией из ключа, чтобы кэш браузера не показывал старый аватар
+func avatarURL(userID int, avatarKey string) string {
+	if avatarKey == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("/api/v1/users/%d/avatar?v=%s", userID, path.Base(avatarKey))
+}
+
 // CreateUserDTO - data transfer object - струтктура для передачи данных пользователя при создании
 type CreateUserDTO struct {
 	ID                   int    `json:"id,omitempty" example:"1"`
-	Username             string `json:"username" example:"username"`
-	Password             string `json:"password,omitempty" example:"password"`
-	PasswordConfirmation string `json:"password_confirmation,omitempty" example:"password"`
-	Email                string `json:"email,omitempty" example:"user@example.com"`
+	Username             string `json:"username" example:"username" validate:"required,username"`
+	Password             string `json:"password,omitempty" example:"Passw0rd" validate:"required,password"`
+	PasswordConfirmation string `json:"password_confirmation,omitempty" example:"Passw0rd" validate:"required,eqfield=Password"`
+	Email                string `json:"email,omitempty" example:"user@example.com" validate:"required,email_address"`
 }
 
 func NewEmptyCreateUserDTO() *CreateUserDTO {
@@ -65,10 +118,10 @@ func (d *CreateUserDTO) FromGRPC(in *users.CreateUserDTO) *CreateUserDTO {
 
 // UpdateUserPasswordDTO - data transfer object - струтктура для передачи данных пользователя при обновлении пароля
 type UpdateUserPasswordDTO struct {
-	ID                   int    `json:"id,omitempty" example:"1"`
-	OldPassword          string `json:"old_password" example:"password"`
-	Password             string `json:"password" example:"password"`
-	PasswordConfirmation string `json:"password_confirmation" example:"password"`
+	ID                   int    `json:"id,omitempty" example:"1" validate:"required,min=1"`
+	OldPassword          string `json:"old_password" example:"password" validate:"required"`
+	Password             string `json:"password" example:"Passw0rd" validate:"required,password"`
+	PasswordConfirmation string `json:"password_confirmation" example:"Passw0rd" validate:"required,eqfield=Password"`
 }
 
 func NewEmptyUpdateUserPasswordDTO() *UpdateUserPasswordDTO {
@@ -94,9 +147,9 @@ func (d *UpdateUserPasswordDTO) FromGRPC(in *users.UpdateUserPasswordDTO) *Updat
 
 // UserLoginDTO - data transfer object - струтктура для передачи данных пользователя при логине
 type UserLoginDTO struct {
-	Username string `db:"username,omitempty"`
-	Password string `db:"password"`
-	Email    string `db:"email,omitempty"`
+	Username string `db:"username,omitempty" validate:"required_without=Email"`
+	Password string `db:"password" validate:"required"`
+	Email    string `db:"email,omitempty" validate:"required_without=Username,omitempty,email_address"`
 }
 
 func NewEmptyUserLoginDTO() *UserLoginDTO {
@@ -118,8 +171,101 @@ func (d *UserLoginDTO) FromGRPC(in *users.UserLoginDTO) *UserLoginDTO {
 	return d
 }
 
-// UserTokens - струтктура для передачи токенов пользователя
+// UpdateUserRoleDTO - data transfer object - струтктура для смены роли пользователя
+type UpdateUserRoleDTO struct {
+	ID   int    `json:"id,omitempty" example:"1" validate:"required,min=1"`
+	Role string `json:"role" example:"admin" validate:"required,oneof=user admin"`
+}
+
+func (d *UpdateUserRoleDTO) ToGRPC() *users.UpdateUserRoleDTO {
+	return &users.UpdateUserRoleDTO{
+		Id:   int32(d.ID),
+		Role: d.Role,
+	}
+}
+
+// UserTokens - струтктура для передачи токенов пользователя.
+// При включенной MFA логин вместо токенов возвращает MFARequired и короткоживущий MFAToken
 type UserTokens struct {
 	AccessToken  string `json:"access_token,omitempty"`
 	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+// MFACodeDTO - data transfer object - код второго фактора: TOTP код или код восстановления
+type MFACodeDTO struct {
+	ID   int    `json:"id,omitempty" example:"1" validate:"required,min=1"`
+	Code string `json:"code" example:"123456" validate:"required,min=6,max=32"`
+}
+
+func (d *MFACodeDTO) ToGRPC() *users.MFACodeDTO {
+	return &users.MFACodeDTO{
+		Id:   int32(d.ID),
+		Code: d.Code,
+	}
+}
+
+// MFALoginDTO - data transfer object - второй шаг логина
+type MFALoginDTO struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" example:"123456" validate:"required,min=6,max=32"`
+}
+
+// TOTPEnrollmentDTO - секрет для приложения-аутентификатора
+type TOTPEnrollmentDTO struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	OtpauthURI string `json:"otpauth_uri" example:"otpauth://totp/ToDo:username?secret=JBSWY3DPEHPK3PXP&issuer=ToDo"`
+}
+
+func (d *TOTPEnrollmentDTO) FromGRPC(in *users.TOTPEnrollment) *TOTPEnrollmentDTO {
+	d.Secret = in.Secret
+	d.OtpauthURI = in.OtpauthUri
+	return d
+}
+
+// RecoveryCodesDTO - одноразовые коды восстановления, показываются пользователю один раз
+type RecoveryCodesDTO struct {
+	Codes []string `json:"codes"`
+}
+
+// AccountLockedDTO - data transfer object - событие блокировки входа после серии неудачных попыток
+type AccountLockedDTO struct {
+	Username    string
+	Email       string
+	IP          string
+	LockedUntil time.Time
+}
+
+func (d *AccountLockedDTO) ToGRPC() *users.AccountLockedDTO {
+	return &users.AccountLockedDTO{
+		Username:    d.Username,
+		Email:       d.Email,
+		Ip:          d.IP,
+		LockedUntil: d.LockedUntil.Unix(),
+	}
+}
+
+// ExternalIdentityDTO - data transfer object - учетная запись пользователя у внешнего OIDC провайдера
+type ExternalIdentityDTO struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+func (d *ExternalIdentityDTO) ToGRPC() *users.ExternalIdentityDTO {
+	return &users.ExternalIdentityDTO{
+		Provider:      d.Provider,
+		Subject:       d.Subject,
+		Email:         d.Email,
+		EmailVerified: d.EmailVerified,
+		Username:      d.Username,
+	}
+}
+
+// OIDCLoginDTO - адрес страницы входа у провайдера
+type OIDCLoginDTO struct {
+	AuthURL string `json:"auth_url"`
 }
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"gateway/pkg/grpc_stubs/todo"
+	"github.com/google/uuid"
+	"time"
+)
+
+// роли участников рабочего пространства
+const (
+	// WorkspaceRoleOwner управляет пространством и его участниками
+	WorkspaceRoleOwner = "owner"
+	// WorkspaceRoleEditor создает и меняет задачи пространства
+	WorkspaceRoleEditor = "editor"
+	// WorkspaceRoleViewer видит и комментирует задачи пространства
+	WorkspaceRoleViewer = "viewer"
+)
+
+type WorkspaceDTO struct {
+	ID        uuid.UUID `json:"id" example:"0b9d7c1e-4f3a-4c2b-9e8d-1a2b3c4d5e6f"`
+	Name      string    `json:"name" example:"household"`
+	CreatedBy int       `json:"created_by" example:"1"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Role - роль текущего пользователя в пространстве
+	Role string `json:"role,omitempty" example:"owner"`
+}
+
+func NewEmptyWorkspaceDTO() *WorkspaceDTO {
+	return &WorkspaceDTO{}
+}
+
+func (w *WorkspaceDTO) FromGRPC(in *todo.WorkspaceDTO) *WorkspaceDTO {
+	w.ID, _ = uuid.Parse(in.Id)
+	w.Name = in.Name
+	w.CreatedBy = int(in.CreatedBy)
+	w.CreatedAt = in.GetCreatedAt().AsTime()
+	w.UpdatedAt = in.GetUpdatedAt().AsTime()
+	w.Role = in.Role
+	return w
+}
+
+// WorkspaceRequestDTO - имя нового или переименованного пространства
+type WorkspaceRequestDTO struct {
+	Name string `json:"name" example:"household" validate:"workspace_name"`
+}
+
+type WorkspaceMemberDTO struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" example:"0b9d7c1e-4f3a-4c2b-9e8d-1a2b3c4d5e6f"`
+	UserID      int       `json:"user_id" example:"2"`
+	Role        string    `json:"role" example:"editor"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewEmptyWorkspaceMemberDTO() *WorkspaceMemberDTO {
+	return &WorkspaceMemberDTO{}
+}
+
+func (m *WorkspaceMemberDTO) FromGRPC(in *todo.WorkspaceMemberDTO) *WorkspaceMemberDTO {
+	m.WorkspaceID, _ = uuid.Parse(in.WorkspaceId)
+	m.UserID = int(in.UserId)
+	m.Role = in.Role
+	m.CreatedAt = in.GetCreatedAt().AsTime()
+	return m
+}
+
+// UpdateWorkspaceMemberRequestDTO - новая роль участника
+type UpdateWorkspaceMemberRequestDTO struct {
+	Role string `json:"role" example:"viewer" validate:"required,oneof=owner editor viewer"`
+}
+
+// InviteWorkspaceMemberRequestDTO - владельцем можно сделать только уже вступившего участника
+type InviteWorkspaceMemberRequestDTO struct {
+	Email string `json:"email" example:"friend@example.com" validate:"email_address"`
+	Role  string `json:"role" example:"editor" validate:"required,oneof=editor viewer"`
+}
+
+type WorkspaceInvitationDTO struct {
+	ID          uuid.UUID `json:"id" example:"6a0f0b52-3c0e-4c5e-9a43-6c1f0f0a2f55"`
+	WorkspaceID uuid.UUID `json:"workspace_id" example:"0b9d7c1e-4f3a-4c2b-9e8d-1a2b3c4d5e6f"`
+	Email       string    `json:"email" example:"friend@example.com"`
+	Role        string    `json:"role" example:"editor"`
+	InvitedBy   int       `json:"invited_by" example:"1"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func NewEmptyWorkspaceInvitationDTO() *WorkspaceInvitationDTO {
+	return &WorkspaceInvitationDTO{}
+}
+
+func (i *WorkspaceInvitationDTO) FromGRPC(in *todo.WorkspaceInvitationDTO) *WorkspaceInvitationDTO {
+	i.ID, _ = uuid.Parse(in.Id)
+	i.WorkspaceID, _ = uuid.Parse(in.WorkspaceId)
+	i.Email = in.Email
+	i.Role = in.Role
+	i.InvitedBy = int(in.InvitedBy)
+	i.CreatedAt = in.GetCreatedAt().AsTime()
+	i.ExpiresAt = in.GetExpiresAt().AsTime()
+	return i
+}
+
+// AcceptWorkspaceInvitationRequestDTO - токен из письма с приглашением
+type AcceptWorkspaceInvitationRequestDTO struct {
+	Token string `json:"token" example:"q1w2e3..." validate:"required"`
+}
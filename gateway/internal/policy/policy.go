@@ -0,0 +1,155 @@
+package policy
+
+import (
+	"context"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+)
+
+// Subject - пользователь, от имени которого выполняется запрос
+type Subject struct {
+	UserID int
+	Role   string
+	// APIKeyScopes - права API ключа, nil если запрос авторизован JWT токеном
+	APIKeyScopes []string
+}
+
+// SubjectFromContext достает пользователя, которого middleware положил в контекст после проверки токена
+func SubjectFromContext(ctx context.Context) (Subject, error) {
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return Subject{}, app_errors.ErrNoUserInContext
+	}
+
+	role, _ := ctxutil.GetUserRoleFromContext(ctx)
+	scopes, _ := ctxutil.GetAPIKeyScopesFromContext(ctx)
+
+	return Subject{UserID: userID, Role: role, APIKeyScopes: scopes}, nil
+}
+
+func (s Subject) IsAdmin() bool {
+	return s.Role == models.RoleAdmin
+}
+
+// HasRole проверяет доступ на уровне маршрута
+func (s Subject) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if s.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasScope - JWT токен дает все права пользователя, API ключ - только выданные при создании
+func (s Subject) HasScope(scope string) bool {
+	if s.APIKeyScopes == nil {
+		return true
+	}
+
+	for _, granted := range s.APIKeyScopes {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanManageUser - изменять, удалять пользователя и отзывать его токены может он сам или администратор
+func (s Subject) CanManageUser(userID int) bool {
+	return s.IsAdmin() || s.UserID == userID
+}
+
+// CanCreateTodoFor - создавать задачи от имени другого пользователя может только администратор
+func (s Subject) CanCreateTodoFor(createdBy int) bool {
+	return s.IsAdmin() || s.UserID == createdBy
+}
+
+// CanReadTodo - личную задачу видят автор, исполнитель и администратор,
+// задачу рабочего пространства - его участники. workspaceRole - роль пользователя в пространстве задачи
+func (s Subject) CanReadTodo(todo *models.TodoDTO, workspaceRole string) bool {
+	if todo.WorkspaceID != nil {
+		return s.IsAdmin() || workspaceRole != ""
+	}
+
+	return s.IsAdmin() || s.UserID == todo.CreatedBy || s.UserID == todo.Assignee
+}
+
+// CanUpdateTodo - менять личную задачу могут автор, исполнитель и администратор,
+// задачу рабочего пространства - его владельцы и редакторы
+func (s Subject) CanUpdateTodo(todo *models.TodoDTO, workspaceRole string) bool {
+	if todo.WorkspaceID != nil {
+		return s.IsAdmin() || canEditWorkspaceTodos(workspaceRole)
+	}
+
+	return s.CanReadTodo(todo, workspaceRole)
+}
+
+// CanReassignTodo - назначать личную задачу на другого пользователя могут автор и администратор,
+// задачу рабочего пространства - его владельцы и редакторы
+func (s Subject) CanReassignTodo(todo *models.TodoDTO, workspaceRole string) bool {
+	if todo.WorkspaceID != nil {
+		return s.IsAdmin() || canEditWorkspaceTodos(workspaceRole)
+	}
+
+	return s.IsAdmin() || s.UserID == todo.CreatedBy
+}
+
+// CanDeleteTodo - удалять личную задачу могут автор и администратор, задачу рабочего пространства -
+// его владельцы и редактор, который ее создал
+func (s Subject) CanDeleteTodo(todo *models.TodoDTO, workspaceRole string) bool {
+	if todo.WorkspaceID != nil {
+		return s.IsAdmin() || workspaceRole == models.WorkspaceRoleOwner ||
+			(workspaceRole == models.WorkspaceRoleEditor && s.UserID == todo.CreatedBy)
+	}
+
+	return s.IsAdmin() || s.UserID == todo.CreatedBy
+}
+
+// CanModerateComments - удалять чужие комментарии к личной задаче могут ее автор и администратор,
+// к задаче рабочего пространства - его владельцы
+func (s Subject) CanModerateComments(todo *models.TodoDTO, workspaceRole string) bool {
+	if todo.WorkspaceID != nil {
+		return s.IsAdmin() || workspaceRole == models.WorkspaceRoleOwner
+	}
+
+	return s.IsAdmin() || s.UserID == todo.CreatedBy
+}
+
+// CanReadWorkspace - задачи и участников пространства видят его участники и администратор
+func (s Subject) CanReadWorkspace(workspaceRole string) bool {
+	return s.IsAdmin() || workspaceRole != ""
+}
+
+// CanCreateTodoIn - создавать задачи в пространстве могут его владельцы и редакторы
+func (s Subject) CanCreateTodoIn(workspaceRole string) bool {
+	return s.IsAdmin() || canEditWorkspaceTodos(workspaceRole)
+}
+
+// CanManageWorkspace - переименовывать и удалять пространство, приглашать и исключать участников
+// и менять их роли могут его владельцы и администратор
+func (s Subject) CanManageWorkspace(workspaceRole string) bool {
+	return s.IsAdmin() || workspaceRole == models.WorkspaceRoleOwner
+}
+
+// CanManageLabelsIn - создавать, менять и удалять метки пространства могут его владельцы и редакторы
+func (s Subject) CanManageLabelsIn(workspaceRole string) bool {
+	return s.IsAdmin() || canEditWorkspaceTodos(workspaceRole)
+}
+
+// CanEditLabel - менять и удалять личную метку может только ее владелец,
+// метку рабочего пространства - его владельцы и редакторы
+func (s Subject) CanEditLabel(label *models.LabelDTO, workspaceRole string) bool {
+	if label.WorkspaceID != nil {
+		return s.CanManageLabelsIn(workspaceRole)
+	}
+
+	return s.UserID == label.Owner
+}
+
+func canEditWorkspaceTodos(workspaceRole string) bool {
+	return workspaceRole == models.WorkspaceRoleOwner || workspaceRole == models.WorkspaceRoleEditor
+}
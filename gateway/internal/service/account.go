@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"github.com/opentracing/opentracing-go"
+	"time"
+)
+
+// DeactivateUser отключает учетную запись до следующего входа и завершает все ее сессии
+func (s *GatewayService) DeactivateUser(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeactivateUser")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !subject.CanManageUser(userID) {
+		return app_errors.NewUserIDMismatchError("DeactivateUser", subject.UserID, userID)
+	}
+
+	err = s.usersServiceClient.DeactivateUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[DeactivateUser] deactivate user:%w", err)
+	}
+
+	err = s.redisManager.InvalidateTokensForUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[DeactivateUser] invalidate tokens:%w", err)
+	}
+
+	return nil
+}
+
+// ExportAccount собирает профиль, задачи, сессии и API ключи текущего пользователя
+func (s *GatewayService) ExportAccount(ctx context.Context) (*models.AccountExportDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ExportAccount")
+	defer span.Finish()
+
+	subject, err := s.requireInteractiveSubject(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[ExportAccount] %w", err)
+	}
+
+	profile, err := s.usersServiceClient.GetUserByID(ctx, subject.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[ExportAccount] get user:%w", err)
+	}
+
+	todos, err := s.todoServiceClient.GetUserToDos(ctx, subject.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[ExportAccount] get todos:%w", err)
+	}
+
+	sessions, err := s.ListSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[ExportAccount] list sessions:%w", err)
+	}
+
+	keys, err := s.usersServiceClient.ListAPIKeys(ctx, subject.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[ExportAccount] list api keys:%w", err)
+	}
+
+	return &models.AccountExportDTO{
+		ExportedAt: time.Now().UTC(),
+		Profile:    profile,
+		Todos:      todos,
+		Sessions:   sessions,
+		APIKeys:    keys,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *GatewayService) ListUsers(ctx context.Context, limit, offset int) ([]*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListUsers")
+	defer span.Finish()
+
+	if err := requireAdmin(ctx); err != nil {
+		return nil, fmt.Errorf("[ListUsers] %w", err)
+	}
+
+	result, err := s.usersServiceClient.ListUsers(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("[ListUsers] list users:%w", err)
+	}
+
+	return result, nil
+}
+
+func (s *GatewayService) UpdateUserRole(ctx context.Context, request *models.UpdateUserRoleDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateUserRole")
+	defer span.Finish()
+
+	if err := requireAdmin(ctx); err != nil {
+		return fmt.Errorf("[UpdateUserRole] %w", err)
+	}
+
+	if err := s.validator.Validate(request); err != nil {
+		return fmt.Errorf("[UpdateUserRole] validate:%w", err)
+	}
+
+	err := s.usersServiceClient.UpdateUserRole(ctx, request)
+	if err != nil {
+		return fmt.Errorf("[UpdateUserRole] update role:%w", err)
+	}
+
+	// роль хранится в токенах, поэтому выданные ранее токены отзываем
+	err = s.redisManager.InvalidateTokensForUser(ctx, request.ID)
+	if err != nil {
+		return fmt.Errorf("[UpdateUserRole] invalidate tokens:%w", err)
+	}
+
+	return nil
+}
+
+// UnlockUser снимает блокировку входа после неудачных попыток
+func (s *GatewayService) UnlockUser(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UnlockUser")
+	defer span.Finish()
+
+	if err := requireAdmin(ctx); err != nil {
+		return fmt.Errorf("[UnlockUser] %w", err)
+	}
+
+	user, err := s.usersServiceClient.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[UnlockUser] get user:%w", err)
+	}
+
+	// войти можно и по имени, и по email, блокировки считаются для каждого отдельно
+	err = s.loginGuard.Unlock(ctx, user.Username, user.Email)
+	if err != nil {
+		return fmt.Errorf("[UnlockUser] unlock:%w", err)
+	}
+
+	return nil
+}
+
+// requireAdmin проверяет, что запрос выполняет администратор
+func requireAdmin(ctx context.Context) error {
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !subject.IsAdmin() {
+		return app_errors.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"github.com/opentracing/opentracing-go"
+)
+
+// CreateAPIKey создает API ключ текущему пользователю. Ключ показывается только в этом ответе
+func (s *GatewayService) CreateAPIKey(ctx context.Context, request *models.CreateAPIKeyDTO) (*models.APIKeyDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateAPIKey")
+	defer span.Finish()
+
+	subject, err := s.requireInteractiveSubject(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateAPIKey] %w", err)
+	}
+
+	request.UserID = subject.UserID
+
+	if err := s.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[CreateAPIKey] validate:%w", err)
+	}
+
+	key, err := s.usersServiceClient.CreateAPIKey(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateAPIKey] create key:%w", err)
+	}
+
+	return key, nil
+}
+
+// ListAPIKeys возвращает API ключи текущего пользователя
+func (s *GatewayService) ListAPIKeys(ctx context.Context) ([]*models.APIKeyDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListAPIKeys")
+	defer span.Finish()
+
+	subject, err := s.requireInteractiveSubject(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[ListAPIKeys] %w", err)
+	}
+
+	keys, err := s.usersServiceClient.ListAPIKeys(ctx, subject.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListAPIKeys] list keys:%w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает API ключ текущего пользователя
+func (s *GatewayService) RevokeAPIKey(ctx context.Context, keyID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RevokeAPIKey")
+	defer span.Finish()
+
+	subject, err := s.requireInteractiveSubject(ctx)
+	if err != nil {
+		return fmt.Errorf("[RevokeAPIKey] %w", err)
+	}
+
+	err = s.usersServiceClient.RevokeAPIKey(ctx, keyID, subject.UserID)
+	if err != nil {
+		return fmt.Errorf("[RevokeAPIKey] revoke key:%w", err)
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey проверяет API ключ из заголовка Authorization
+func (s *GatewayService) AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKeyPrincipalDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AuthenticateAPIKey")
+	defer span.Finish()
+
+	principal, err := s.usersServiceClient.AuthenticateAPIKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("[AuthenticateAPIKey] %w", err)
+	}
+
+	return principal, nil
+}
+
+// requireInteractiveSubject - ключами управляет только сам пользователь, утекший API ключ не должен выпускать новые
+func (s *GatewayService) requireInteractiveSubject(ctx context.Context) (policy.Subject, error) {
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+
+	if subject.APIKeyScopes != nil {
+		return policy.Subject{}, fmt.Errorf("api key can not manage api keys: %w", app_errors.ErrForbidden)
+	}
+
+	return subject, nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"gateway/pkg/avatar"
+	"gateway/pkg/blobstore"
+	"gateway/pkg/validator"
+	"github.com/opentracing/opentracing-go"
+	"io"
+)
+
+// UploadAvatar проверяет изображение, сохраняет аватар всех размеров и заменяет им прежний аватар пользователя
+func (s *GatewayService) UploadAvatar(ctx context.Context, userID int, image io.Reader) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UploadAvatar")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// менять аватар может сам пользователь или администратор
+	if !subject.CanManageUser(userID) {
+		return nil, app_errors.NewUserIDMismatchError("UploadAvatar", subject.UserID, userID)
+	}
+
+	images, err := s.avatars.Process(image)
+	if err != nil {
+		if errors.Is(err, avatar.ErrUnsupportedType) || errors.Is(err, avatar.ErrTooLarge) {
+			return nil, fmt.Errorf("[UploadAvatar] process:%w", avatarValidationError(err))
+		}
+		return nil, fmt.Errorf("[UploadAvatar] process:%w", err)
+	}
+
+	// каждый аватар получает новый ключ, поэтому старые ссылки не показывают новое изображение из кэша
+	avatarKey, err := newAvatarKey(userID)
+	if err != nil {
+		return nil, fmt.Errorf("[UploadAvatar] generate key:%w", err)
+	}
+
+	for _, img := range images {
+		err := s.blobStore.Put(ctx, avatarBlobKey(avatarKey, img.Size), bytes.NewReader(img.Data), int64(len(img.Data)), avatar.ContentType)
+		if err != nil {
+			s.deleteAvatarBlobs(ctx, avatarKey)
+			return nil, fmt.Errorf("[UploadAvatar] store %s:%w", img.Size, err)
+		}
+	}
+
+	previousKey, err := s.usersServiceClient.UpdateAvatar(ctx, userID, avatarKey)
+	if err != nil {
+		s.deleteAvatarBlobs(ctx, avatarKey)
+		return nil, fmt.Errorf("[UploadAvatar] update user:%w", err)
+	}
+	s.deleteAvatarBlobs(ctx, previousKey)
+
+	user, err := s.usersServiceClient.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[UploadAvatar] get user:%w", err)
+	}
+
+	return user, nil
+}
+
+// GetAvatar возвращает файл аватара пользователя нужного размера
+func (s *GatewayService) GetAvatar(ctx context.Context, userID int, size string) (*blobstore.Object, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetAvatar")
+	defer span.Finish()
+
+	if size == "" {
+		size = avatar.SizeLarge
+	}
+	if size != avatar.SizeLarge && size != avatar.SizeSmall {
+		return nil, fmt.Errorf("[GetAvatar] %w", validator.NewValidationError(validator.FieldViolation{
+			Field:   "size",
+			Message: fmt.Sprintf("must be one of: %s, %s", avatar.SizeLarge, avatar.SizeSmall),
+		}))
+	}
+
+	user, err := s.usersServiceClient.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetAvatar] get user:%w", err)
+	}
+	if user.AvatarKey == "" {
+		return nil, fmt.Errorf("[GetAvatar] no avatar:%w", app_errors.ErrNotFound)
+	}
+
+	object, err := s.blobStore.Get(ctx, avatarBlobKey(user.AvatarKey, size))
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return nil, fmt.Errorf("[GetAvatar] get blob:%w", app_errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("[GetAvatar] get blob:%w", err)
+	}
+
+	return object, nil
+}
+
+// DeleteAvatar удаляет аватар пользователя
+func (s *GatewayService) DeleteAvatar(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteAvatar")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !subject.CanManageUser(userID) {
+		return app_errors.NewUserIDMismatchError("DeleteAvatar", subject.UserID, userID)
+	}
+
+	previousKey, err := s.usersServiceClient.UpdateAvatar(ctx, userID, "")
+	if err != nil {
+		return fmt.Errorf("[DeleteAvatar] update user:%w", err)
+	}
+	s.deleteAvatarBlobs(ctx, previousKey)
+
+	return nil
+}
+
+// deleteAvatarBlobs удаляет файлы аватара всех размеров. Ошибки не возвращаются:
+// оставшийся файл занимает место, но ни на что не влияет, ключ на него уже не ссылается
+func (s *GatewayService) deleteAvatarBlobs(ctx context.Context, avatarKey string) {
+	if avatarKey == "" {
+		return
+	}
+
+	for _, size := range s.avatars.Sizes() {
+		_ = s.blobStore.Delete(ctx, avatarBlobKey(avatarKey, size))
+	}
+}
+
+func newAvatarKey(userID int) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("avatars/%d/%s", userID, hex.EncodeToString(buf)), nil
+}
+
+func avatarBlobKey(avatarKey, size string) string {
+	return avatarKey + "/" + size + ".png"
+}
+
+func avatarValidationError(err error) *validator.ValidationError {
+	message := "must be a PNG, JPEG or GIF image"
+	if errors.Is(err, avatar.ErrTooLarge) {
+		message = err.Error()
+	}
+
+	return validator.NewValidationError(validator.FieldViolation{Field: "avatar", Message: message})
+}
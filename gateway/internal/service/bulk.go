@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// bulkFilterPageSize - по сколько задач читается фильтр массовой операции
+const bulkFilterPageSize = 200
+
+// BulkUpdateTodos применяет операцию к задачам из списка или подходящим под фильтр.
+// Права проверяются по каждой задаче: задачи, которые отправитель не может изменить, попадают
+// в результат с причиной и не передаются в сервис задач
+func (s *GatewayService) BulkUpdateTodos(ctx context.Context, request *models.BulkUpdateTodosDTO) (*models.BulkUpdateTodosResultDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.BulkUpdateTodos")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[BulkUpdateTodos] validate:%w", err)
+	}
+
+	if request.Operation == models.BulkOperationReassign {
+		if err := s.checkAssigneeExists(ctx, request.Assignee); err != nil {
+			return nil, fmt.Errorf("[BulkUpdateTodos] check assignee:%w", err)
+		}
+	}
+
+	// order - задачи в порядке запроса или фильтра, failed - причины отказа по ним
+	var (
+		order  []uuid.UUID
+		todos  []*models.TodoDTO
+		failed = make(map[uuid.UUID]string)
+	)
+	if request.Filter != nil {
+		todos, err = s.filterBulkTodos(ctx, subject, request.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("[BulkUpdateTodos] %w", err)
+		}
+
+		for _, todo := range todos {
+			order = append(order, todo.ID)
+		}
+	} else {
+		order, todos, err = s.getBulkTodos(ctx, request.IDs, failed)
+		if err != nil {
+			return nil, fmt.Errorf("[BulkUpdateTodos] %w", err)
+		}
+	}
+
+	roles := make(map[uuid.UUID]string)
+	members := make(map[uuid.UUID]bool)
+	permitted := make([]uuid.UUID, 0, len(todos))
+	for _, todo := range todos {
+		errCode, err := s.bulkAccessError(ctx, subject, request, todo, roles, members)
+		if err != nil {
+			return nil, fmt.Errorf("[BulkUpdateTodos] %w", err)
+		}
+
+		if errCode != "" {
+			failed[todo.ID] = errCode
+			continue
+		}
+
+		permitted = append(permitted, todo.ID)
+	}
+
+	if len(permitted) > 0 {
+		applied, err := s.todoServiceClient.BulkUpdateTodos(ctx, subject.UserID, permitted, request)
+		if err != nil {
+			return nil, fmt.Errorf("[BulkUpdateTodos] bulk update:%w", err)
+		}
+
+		for _, item := range applied.Results {
+			if item.Error != "" {
+				failed[item.ID] = item.Error
+			}
+		}
+	}
+
+	result := &models.BulkUpdateTodosResultDTO{Results: make([]models.BulkItemResultDTO, 0, len(order))}
+	for _, id := range order {
+		result.Add(id, failed[id])
+	}
+
+	return result, nil
+}
+
+// getBulkTodos читает задачи по идентификаторам без повторов. Несуществующие задачи записываются в failed
+func (s *GatewayService) getBulkTodos(ctx context.Context, ids []uuid.UUID, failed map[uuid.UUID]string) ([]uuid.UUID, []*models.TodoDTO, error) {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	order := make([]uuid.UUID, 0, len(ids))
+	todos := make([]*models.TodoDTO, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		order = append(order, id)
+
+		todo, err := s.todoServiceClient.GetToDo(ctx, id)
+		if err != nil {
+			if errors.Is(err, app_errors.ErrNotFound) {
+				failed[id] = models.BulkErrorNotFound
+				continue
+			}
+			return nil, nil, fmt.Errorf("get todo:%w", err)
+		}
+
+		todos = append(todos, todo)
+	}
+
+	return order, todos, nil
+}
+
+// filterBulkTodos читает все задачи отправителя, подходящие под фильтр, но не больше BulkMaxItems
+func (s *GatewayService) filterBulkTodos(ctx context.Context, subject policy.Subject, filter *models.BulkTodosFilterDTO) ([]*models.TodoDTO, error) {
+	if err := s.checkWorkspaceRead(ctx, subject, filter.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	list := filter.ToListTodosDTO(subject.UserID)
+	list.Limit = bulkFilterPageSize
+
+	todos := make([]*models.TodoDTO, 0)
+	for {
+		page, err := s.todoServiceClient.ListToDos(ctx, list)
+		if err != nil {
+			return nil, fmt.Errorf("list todos:%w", err)
+		}
+
+		todos = append(todos, page...)
+		if len(todos) > models.BulkMaxItems {
+			return nil, validator.NewValidationError(validator.FieldViolation{
+				Field:   "filter",
+				Message: fmt.Sprintf("filter matches more than %d todos", models.BulkMaxItems),
+			})
+		}
+
+		if len(page) < list.Limit {
+			return todos, nil
+		}
+		list.Offset += list.Limit
+	}
+}
+
+// bulkAccessError возвращает причину, по которой отправитель не может применить операцию к задаче.
+// Роли отправителя и членство нового исполнителя в пространствах запоминаются в roles и members
+func (s *GatewayService) bulkAccessError(
+	ctx context.Context,
+	subject policy.Subject,
+	request *models.BulkUpdateTodosDTO,
+	todo *models.TodoDTO,
+	roles map[uuid.UUID]string,
+	members map[uuid.UUID]bool,
+) (string, error) {
+	var role string
+	if todo.WorkspaceID != nil {
+		var ok bool
+		if role, ok = roles[*todo.WorkspaceID]; !ok {
+			var err error
+			if role, err = s.workspaceRole(ctx, subject, todo.WorkspaceID); err != nil {
+				return "", err
+			}
+			roles[*todo.WorkspaceID] = role
+		}
+	}
+
+	switch request.Operation {
+	case models.BulkOperationReassign:
+		if !subject.CanReassignTodo(todo, role) {
+			return models.BulkErrorForbidden, nil
+		}
+
+		if todo.WorkspaceID == nil {
+			return "", nil
+		}
+
+		member, ok := members[*todo.WorkspaceID]
+		if !ok {
+			err := s.checkAssigneeIsMember(ctx, *todo.WorkspaceID, request.Assignee)
+			var validationErr *validator.ValidationError
+			if err != nil && !errors.As(err, &validationErr) {
+				return "", fmt.Errorf("check assignee:%w", err)
+			}
+
+			member = err == nil
+			members[*todo.WorkspaceID] = member
+		}
+
+		if !member {
+			return models.BulkErrorAssigneeNotMember, nil
+		}
+
+	case models.BulkOperationDelete:
+		if !subject.CanDeleteTodo(todo, role) {
+			return models.BulkErrorForbidden, nil
+		}
+
+	default:
+		if !subject.CanUpdateTodo(todo, role) {
+			return models.BulkErrorForbidden, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// AddComment добавляет комментарий к задаче. Комментировать могут все, кто видит задачу
+func (s *GatewayService) AddComment(ctx context.Context, todoID uuid.UUID, comment *models.CommentRequestDTO) (*models.CommentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AddComment")
+	defer span.Finish()
+
+	subject, err := s.readableTodoSubject(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("[AddComment] %w", err)
+	}
+
+	if err := s.validator.Validate(comment); err != nil {
+		return nil, fmt.Errorf("[AddComment] validate:%w", err)
+	}
+
+	created, err := s.todoServiceClient.AddComment(ctx, todoID, subject.UserID, comment.Body)
+	if err != nil {
+		return nil, fmt.Errorf("[AddComment] add comment:%w", err)
+	}
+
+	return created, nil
+}
+
+// EditComment меняет текст комментария. Проверку авторства выполняет сервис задач
+func (s *GatewayService) EditComment(ctx context.Context, todoID, commentID uuid.UUID, comment *models.CommentRequestDTO) (*models.CommentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.EditComment")
+	defer span.Finish()
+
+	subject, err := s.readableTodoSubject(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("[EditComment] %w", err)
+	}
+
+	if err := s.validator.Validate(comment); err != nil {
+		return nil, fmt.Errorf("[EditComment] validate:%w", err)
+	}
+
+	edited, err := s.todoServiceClient.EditComment(ctx, todoID, commentID, subject.UserID, comment.Body)
+	if err != nil {
+		return nil, fmt.Errorf("[EditComment] edit comment:%w", err)
+	}
+
+	return edited, nil
+}
+
+// DeleteComment удаляет комментарий. Свой комментарий может удалить автор,
+// любой - автор задачи, владелец ее рабочего пространства и администратор
+func (s *GatewayService) DeleteComment(ctx context.Context, todoID, commentID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteComment")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	todo, err := s.todoServiceClient.GetToDo(ctx, todoID)
+	if err != nil {
+		return fmt.Errorf("[DeleteComment] get todo:%w", err)
+	}
+
+	role, err := s.workspaceRole(ctx, subject, todo.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("[DeleteComment] %w", err)
+	}
+
+	if !subject.CanReadTodo(todo, role) {
+		return fmt.Errorf("[DeleteComment] %w", app_errors.ErrForbidden)
+	}
+
+	err = s.todoServiceClient.DeleteComment(ctx, todoID, commentID, subject.UserID, subject.CanModerateComments(todo, role))
+	if err != nil {
+		return fmt.Errorf("[DeleteComment] delete comment:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) ListComments(ctx context.Context, todoID uuid.UUID, limit, offset int) (*models.CommentListDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListComments")
+	defer span.Finish()
+
+	if _, err := s.readableTodoSubject(ctx, todoID); err != nil {
+		return nil, fmt.Errorf("[ListComments] %w", err)
+	}
+
+	comments, err := s.todoServiceClient.ListComments(ctx, todoID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("[ListComments] list comments:%w", err)
+	}
+
+	return comments, nil
+}
+
+// readableTodoSubject возвращает отправителя запроса, если ему видна задача
+func (s *GatewayService) readableTodoSubject(ctx context.Context, todoID uuid.UUID) (policy.Subject, error) {
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+
+	todo, err := s.todoServiceClient.GetToDo(ctx, todoID)
+	if err != nil {
+		return policy.Subject{}, fmt.Errorf("get todo:%w", err)
+	}
+
+	role, err := s.workspaceRole(ctx, subject, todo.WorkspaceID)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+
+	if !subject.CanReadTodo(todo, role) {
+		return policy.Subject{}, app_errors.ErrForbidden
+	}
+
+	return subject, nil
+}
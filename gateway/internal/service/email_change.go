@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/models"
+	"github.com/opentracing/opentracing-go"
+)
+
+// RequestEmailChange запрашивает смену email текущего пользователя.
+// Адрес меняется только после перехода по ссылке из письма на новый адрес
+func (s *GatewayService) RequestEmailChange(ctx context.Context, request *models.EmailChangeRequestDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestEmailChange")
+	defer span.Finish()
+
+	subject, err := s.requireInteractiveSubject(ctx)
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] %w", err)
+	}
+
+	request.UserID = subject.UserID
+
+	if err := s.validator.Validate(request); err != nil {
+		return fmt.Errorf("[RequestEmailChange] validate:%w", err)
+	}
+
+	if err := s.usersServiceClient.RequestEmailChange(ctx, request); err != nil {
+		return fmt.Errorf("[RequestEmailChange] request change:%w", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange применяет новый email. Вход не нужен: токен из письма сам подтверждает владение адресом
+func (s *GatewayService) ConfirmEmailChange(ctx context.Context, token string) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmEmailChange")
+	defer span.Finish()
+
+	user, err := s.usersServiceClient.ConfirmEmailChange(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmEmailChange] confirm change:%w", err)
+	}
+
+	return user, nil
+}
@@ -1,8 +1,13 @@
 package service
 
 import (
+	"gateway/pkg/avatar"
+	"gateway/pkg/blobstore"
 	"gateway/pkg/jwtutil"
+	"gateway/pkg/loginguard"
+	"gateway/pkg/oidcauth"
 	"gateway/pkg/redis"
+	"gateway/pkg/validator"
 )
 
 type GatewayService struct {
@@ -10,6 +15,11 @@ type GatewayService struct {
 	todoServiceClient  TodoServiceClient
 	usersServiceClient UsersServiceClient
 	redisManager       *redis.RedisManager
+	validator          *validator.Validator
+	loginGuard         *loginguard.Guard
+	oidc               *oidcauth.Registry
+	blobStore          blobstore.Store
+	avatars            *avatar.Processor
 }
 
 func NewGatewayService(
@@ -17,11 +27,21 @@ func NewGatewayService(
 	todoServiceClient TodoServiceClient,
 	usersServiceClient UsersServiceClient,
 	redisManager *redis.RedisManager,
+	validator *validator.Validator,
+	loginGuard *loginguard.Guard,
+	oidc *oidcauth.Registry,
+	blobStore blobstore.Store,
+	avatars *avatar.Processor,
 ) *GatewayService {
 	return &GatewayService{
 		jwtUtil:            jwtUtil,
 		todoServiceClient:  todoServiceClient,
 		usersServiceClient: usersServiceClient,
 		redisManager:       redisManager,
+		validator:          validator,
+		loginGuard:         loginGuard,
+		oidc:               oidc,
+		blobStore:          blobStore,
+		avatars:            avatars,
 	}
 }
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/models"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// ListTodoHistory возвращает изменения задачи тем, кому она видна
+func (s *GatewayService) ListTodoHistory(ctx context.Context, todoID uuid.UUID, limit, offset int) (*models.TodoEventListDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListTodoHistory")
+	defer span.Finish()
+
+	if _, err := s.readableTodoSubject(ctx, todoID); err != nil {
+		return nil, fmt.Errorf("[ListTodoHistory] %w", err)
+	}
+
+	events, err := s.todoServiceClient.ListTodoHistory(ctx, todoID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("[ListTodoHistory] list history:%w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// ExportTodos передает в send все задачи отправителя запроса, подходящие под фильтр.
+// Идентификаторы пользователей в выгрузке заменяются их именами
+func (s *GatewayService) ExportTodos(ctx context.Context, filter *models.ListTodosDTO, send func(todo *models.TodoExportDTO) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ExportTodos")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validator.Validate(filter); err != nil {
+		return fmt.Errorf("[ExportTodos] validate:%w", err)
+	}
+
+	if err := s.checkWorkspaceRead(ctx, subject, filter.WorkspaceID); err != nil {
+		return fmt.Errorf("[ExportTodos] %w", err)
+	}
+
+	filter.UserID = subject.UserID
+
+	usernames := make(map[int]string)
+	err = s.todoServiceClient.ExportTodos(ctx, filter, func(todo *models.TodoDTO) error {
+		assignee, err := s.exportUsername(ctx, todo.Assignee, usernames)
+		if err != nil {
+			return err
+		}
+
+		createdBy, err := s.exportUsername(ctx, todo.CreatedBy, usernames)
+		if err != nil {
+			return err
+		}
+
+		return send(&models.TodoExportDTO{
+			ID:          todo.ID,
+			Description: todo.Description,
+			Assignee:    assignee,
+			CreatedBy:   createdBy,
+			Done:        todo.Done,
+			DueAt:       todo.DueAt,
+			Recurrence:  todo.Recurrence,
+			Timezone:    todo.Timezone,
+			CreatedAt:   todo.CreatedAt,
+			UpdatedAt:   todo.UpdatedAt,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("[ExportTodos] export todos:%w", err)
+	}
+
+	return nil
+}
+
+// exportUsername возвращает имя пользователя, запоминая его в usernames. У удаленных пользователей имя пустое
+func (s *GatewayService) exportUsername(ctx context.Context, userID int, usernames map[int]string) (string, error) {
+	if userID == 0 {
+		return "", nil
+	}
+
+	if username, ok := usernames[userID]; ok {
+		return username, nil
+	}
+
+	user, err := s.usersServiceClient.GetUserByID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, app_errors.ErrNotFound) {
+			return "", fmt.Errorf("get user:%w", err)
+		}
+		user = &models.UserDTO{}
+	}
+
+	usernames[userID] = user.Username
+	return user.Username, nil
+}
+
+// importAssignee - исполнитель задач импорта или причина, по которой на него нельзя назначить задачу
+type importAssignee struct {
+	id        int
+	violation *validator.FieldViolation
+}
+
+// ImportTodos создает задачи отправителя запроса из строк файла. Имена исполнителей заменяются их
+// идентификаторами через сервис пользователей. Задачи создаются, только если все строки прошли проверку,
+// в режиме DryRun строки только проверяются
+func (s *GatewayService) ImportTodos(ctx context.Context, request *models.ImportTodosDTO) (*models.ImportTodosResultDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ImportTodos")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[ImportTodos] validate:%w", err)
+	}
+
+	if request.WorkspaceID != nil {
+		role, err := s.workspaceRole(ctx, subject, request.WorkspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("[ImportTodos] %w", err)
+		}
+
+		if !subject.CanCreateTodoIn(role) {
+			return nil, fmt.Errorf("[ImportTodos] %w", app_errors.ErrForbidden)
+		}
+	}
+
+	assignees := make(map[string]importAssignee)
+	failed := make(map[int][]validator.FieldViolation)
+	rows := make([]*models.ImportTodoDTO, 0, len(request.Rows))
+	for _, row := range request.Rows {
+		violations := row.Errors
+
+		assignee := importAssignee{id: subject.UserID}
+		if row.Assignee != "" {
+			assignee, err = s.importAssignee(ctx, row.Assignee, request.WorkspaceID, assignees)
+			if err != nil {
+				return nil, fmt.Errorf("[ImportTodos] row %d:%w", row.Row, err)
+			}
+		}
+		if assignee.violation != nil {
+			violations = append(violations, *assignee.violation)
+		}
+
+		if len(violations) > 0 {
+			failed[row.Row] = violations
+			continue
+		}
+
+		rows = append(rows, &models.ImportTodoDTO{
+			Row:  row.Row,
+			Done: row.Done,
+			Todo: &models.CreateTodoDTO{
+				CreatedBy:   subject.UserID,
+				Assignee:    assignee.id,
+				Description: row.Description,
+				DueAt:       row.DueAt,
+				Recurrence:  row.Recurrence,
+				Timezone:    row.Timezone,
+				WorkspaceID: request.WorkspaceID,
+			},
+		})
+	}
+
+	// строки, которые прошли проверку в gateway, проверяет сервис задач, но сохраняет их, только если ошибок нет нигде
+	checked := &models.ImportTodosResultDTO{}
+	if len(rows) > 0 {
+		checked, err = s.todoServiceClient.ImportTodos(ctx, &models.ImportTodosRequestDTO{
+			CreatedBy:   subject.UserID,
+			WorkspaceID: request.WorkspaceID,
+			DryRun:      request.DryRun || len(failed) > 0,
+			Rows:        rows,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[ImportTodos] import todos:%w", err)
+		}
+	}
+
+	byRow := make(map[int]models.ImportRowResultDTO, len(checked.Rows))
+	for _, row := range checked.Rows {
+		byRow[row.Row] = row
+	}
+
+	result := &models.ImportTodosResultDTO{
+		DryRun:   request.DryRun,
+		Rows:     make([]models.ImportRowResultDTO, 0, len(request.Rows)),
+		Imported: checked.Imported,
+	}
+	for _, row := range request.Rows {
+		item, ok := byRow[row.Row]
+		if !ok {
+			item = models.ImportRowResultDTO{Row: row.Row, Errors: failed[row.Row]}
+		}
+
+		if len(item.Errors) > 0 {
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, item)
+	}
+
+	return result, nil
+}
+
+// importAssignee находит исполнителя по имени и проверяет, что он участник пространства workspaceID.
+// Результат запоминается в assignees
+func (s *GatewayService) importAssignee(
+	ctx context.Context,
+	username string,
+	workspaceID *uuid.UUID,
+	assignees map[string]importAssignee,
+) (importAssignee, error) {
+	if assignee, ok := assignees[username]; ok {
+		return assignee, nil
+	}
+
+	var assignee importAssignee
+	user, err := s.usersServiceClient.GetUserByUsername(ctx, username)
+	switch {
+	case errors.Is(err, app_errors.ErrNotFound):
+		assignee.violation = &validator.FieldViolation{Field: "assignee", Message: "user does not exist"}
+	case err != nil:
+		return importAssignee{}, fmt.Errorf("get user:%w", err)
+	default:
+		assignee.id = user.ID
+	}
+
+	if assignee.violation == nil && workspaceID != nil {
+		err := s.checkAssigneeIsMember(ctx, *workspaceID, assignee.id)
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			assignee.violation = &validationErr.Violations[0]
+		} else if err != nil {
+			return importAssignee{}, fmt.Errorf("check assignee:%w", err)
+		}
+	}
+
+	assignees[username] = assignee
+	return assignee, nil
+}
@@ -3,25 +3,74 @@ package service
 import (
 	"context"
 	"gateway/internal/models"
+	"github.com/google/uuid"
 )
 
 //go:generate mockgen --build_flags=-mod=mod -destination=./mocks/todos_client.go -package=mocks gateway/internal/service TodoServiceClient
 type TodoServiceClient interface {
-	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (int, error)
-	UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (int, error)
-	GetToDos(ctx context.Context, todoID int) (*models.TodoDTO, error)
-	GetToDo(ctx context.Context, todoID int) (*models.TodoDTO, error)
-	DeleteToDo(ctx context.Context, todoID int) error
+	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (uuid.UUID, error)
+	UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (uuid.UUID, error)
+	GetToDos(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error)
+	GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error)
+	DeleteToDo(ctx context.Context, todoID uuid.UUID, version int) error
+	GetUserToDos(ctx context.Context, userID int) ([]*models.TodoDTO, error)
+	AddComment(ctx context.Context, todoID uuid.UUID, author int, body string) (*models.CommentDTO, error)
+	EditComment(ctx context.Context, todoID, commentID uuid.UUID, author int, body string) (*models.CommentDTO, error)
+	DeleteComment(ctx context.Context, todoID, commentID uuid.UUID, deletedBy int, moderator bool) error
+	ListComments(ctx context.Context, todoID uuid.UUID, limit, offset int) (*models.CommentListDTO, error)
+	ListTodoHistory(ctx context.Context, todoID uuid.UUID, limit, offset int) (*models.TodoEventListDTO, error)
+	ListToDos(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoDTO, error)
+	BulkUpdateTodos(ctx context.Context, actor int, ids []uuid.UUID, request *models.BulkUpdateTodosDTO) (*models.BulkUpdateTodosResultDTO, error)
+	ExportTodos(ctx context.Context, filter *models.ListTodosDTO, send func(todo *models.TodoDTO) error) error
+	ImportTodos(ctx context.Context, request *models.ImportTodosRequestDTO) (*models.ImportTodosResultDTO, error)
+	CreateLabel(ctx context.Context, label *models.CreateLabelDTO) (*models.LabelDTO, error)
+	GetLabel(ctx context.Context, labelID uuid.UUID) (*models.LabelDTO, error)
+	UpdateLabel(ctx context.Context, labelID uuid.UUID, owner int, label *models.LabelRequestDTO) (*models.LabelDTO, error)
+	DeleteLabel(ctx context.Context, labelID uuid.UUID, owner int) error
+	ListLabels(ctx context.Context, request *models.ListLabelsDTO) ([]*models.LabelDTO, error)
+	AddTodoLabel(ctx context.Context, todoID, labelID uuid.UUID, owner int) error
+	RemoveTodoLabel(ctx context.Context, todoID, labelID uuid.UUID, owner int) error
+	SearchTodos(ctx context.Context, request *models.SearchTodosDTO) ([]*models.SearchResultDTO, error)
+	ListTodoTree(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoNodeDTO, error)
+	AddTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) error
+	RemoveTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) error
+	CreateWorkspace(ctx context.Context, createdBy int, workspace *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error)
+	UpdateWorkspace(ctx context.Context, workspaceID uuid.UUID, workspace *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error)
+	DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) error
+	ListWorkspaces(ctx context.Context, userID int) ([]*models.WorkspaceDTO, error)
+	GetWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) (*models.WorkspaceMemberDTO, error)
+	ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMemberDTO, error)
+	UpdateWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int, role string) (*models.WorkspaceMemberDTO, error)
+	RemoveWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) error
+	InviteWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, invitedBy int, invitation *models.InviteWorkspaceMemberRequestDTO) (*models.WorkspaceInvitationDTO, error)
+	AcceptWorkspaceInvitation(ctx context.Context, token string, userID int) (*models.WorkspaceMemberDTO, error)
 }
 
 //go:generate mockgen --build_flags=-mod=mod -destination=./mocks/users_client.go -package=mocks gateway/internal/service UsersServiceClient
 type UsersServiceClient interface {
 	CreateUser(ctx context.Context, user *models.CreateUserDTO) (int, error)
-	UpdateUser(ctx context.Context, user *models.UserDTO) error
+	UpdateUser(ctx context.Context, user *models.UserDTO) (*models.UserDTO, error)
 	UpdatePassword(ctx context.Context, data *models.UpdateUserPasswordDTO) error
-	DeleteUser(ctx context.Context, userID int) error
+	DeleteUser(ctx context.Context, userID, version int) (*models.UserDTO, error)
+	DeactivateUser(ctx context.Context, userID int) error
 	GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error)
 	GetUserByUsernameOrEmail(ctx context.Context, username, email string) (*models.UserDTO, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.UserDTO, error)
 	UserLogin(ctx context.Context, user *models.UserLoginDTO) (*models.UserDTO, error)
+	ListUsers(ctx context.Context, limit, offset int) ([]*models.UserDTO, error)
+	UpdateUserRole(ctx context.Context, request *models.UpdateUserRoleDTO) error
+	EnrollTOTP(ctx context.Context, userID int) (*models.TOTPEnrollmentDTO, error)
+	ConfirmTOTP(ctx context.Context, request *models.MFACodeDTO) ([]string, error)
+	DisableTOTP(ctx context.Context, request *models.MFACodeDTO) error
+	VerifyMFA(ctx context.Context, request *models.MFACodeDTO) (*models.UserDTO, error)
+	ReportAccountLocked(ctx context.Context, event *models.AccountLockedDTO) error
+	GetUserByExternalIdentity(ctx context.Context, identity *models.ExternalIdentityDTO) (*models.UserDTO, error)
+	LinkExternalIdentity(ctx context.Context, identity *models.ExternalIdentityDTO) (*models.UserDTO, error)
+	CreateAPIKey(ctx context.Context, request *models.CreateAPIKeyDTO) (*models.APIKeyDTO, error)
+	ListAPIKeys(ctx context.Context, userID int) ([]*models.APIKeyDTO, error)
+	RevokeAPIKey(ctx context.Context, keyID, userID int) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKeyPrincipalDTO, error)
+	UpdateAvatar(ctx context.Context, userID int, avatarKey string) (string, error)
+	RequestEmailChange(ctx context.Context, request *models.EmailChangeRequestDTO) error
+	ConfirmEmailChange(ctx context.Context, token string) (*models.UserDTO, error)
 }
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// CreateLabel создает метку, владельцем которой становится отправитель запроса.
+// Метку рабочего пространства могут создать его владельцы и редакторы
+func (s *GatewayService) CreateLabel(ctx context.Context, workspaceID *uuid.UUID, label *models.LabelRequestDTO) (*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateLabel")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(label); err != nil {
+		return nil, fmt.Errorf("[CreateLabel] validate:%w", err)
+	}
+
+	if workspaceID != nil {
+		role, err := s.workspaceRole(ctx, subject, workspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("[CreateLabel] %w", err)
+		}
+
+		if !subject.CanManageLabelsIn(role) {
+			return nil, fmt.Errorf("[CreateLabel] %w", app_errors.ErrForbidden)
+		}
+	}
+
+	created, err := s.todoServiceClient.CreateLabel(ctx, &models.CreateLabelDTO{
+		Owner:           subject.UserID,
+		WorkspaceID:     workspaceID,
+		LabelRequestDTO: label,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[CreateLabel] create label:%w", err)
+	}
+
+	return created, nil
+}
+
+func (s *GatewayService) UpdateLabel(ctx context.Context, labelID uuid.UUID, label *models.LabelRequestDTO) (*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateLabel")
+	defer span.Finish()
+
+	if err := s.validator.Validate(label); err != nil {
+		return nil, fmt.Errorf("[UpdateLabel] validate:%w", err)
+	}
+
+	subject, err := s.editableLabelSubject(ctx, labelID)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateLabel] %w", err)
+	}
+
+	updated, err := s.todoServiceClient.UpdateLabel(ctx, labelID, subject.UserID, label)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateLabel] update label:%w", err)
+	}
+
+	return updated, nil
+}
+
+func (s *GatewayService) DeleteLabel(ctx context.Context, labelID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteLabel")
+	defer span.Finish()
+
+	subject, err := s.editableLabelSubject(ctx, labelID)
+	if err != nil {
+		return fmt.Errorf("[DeleteLabel] %w", err)
+	}
+
+	if err := s.todoServiceClient.DeleteLabel(ctx, labelID, subject.UserID); err != nil {
+		return fmt.Errorf("[DeleteLabel] delete label:%w", err)
+	}
+
+	return nil
+}
+
+// ListLabels возвращает личные метки отправителя запроса или метки рабочего пространства, если он его участник
+func (s *GatewayService) ListLabels(ctx context.Context, workspaceID *uuid.UUID) ([]*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListLabels")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkWorkspaceRead(ctx, subject, workspaceID); err != nil {
+		return nil, fmt.Errorf("[ListLabels] %w", err)
+	}
+
+	labels, err := s.todoServiceClient.ListLabels(ctx, &models.ListLabelsDTO{
+		UserID:      subject.UserID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[ListLabels] list labels:%w", err)
+	}
+
+	return labels, nil
+}
+
+// AddTodoLabel вешает свою метку или метку пространства задачи на задачу, которую отправитель запроса может менять
+func (s *GatewayService) AddTodoLabel(ctx context.Context, todoID, labelID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AddTodoLabel")
+	defer span.Finish()
+
+	subject, err := s.updatableTodoSubject(ctx, todoID)
+	if err != nil {
+		return fmt.Errorf("[AddTodoLabel] %w", err)
+	}
+
+	if err := s.todoServiceClient.AddTodoLabel(ctx, todoID, labelID, subject.UserID); err != nil {
+		return fmt.Errorf("[AddTodoLabel] add label:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) RemoveTodoLabel(ctx context.Context, todoID, labelID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveTodoLabel")
+	defer span.Finish()
+
+	subject, err := s.updatableTodoSubject(ctx, todoID)
+	if err != nil {
+		return fmt.Errorf("[RemoveTodoLabel] %w", err)
+	}
+
+	if err := s.todoServiceClient.RemoveTodoLabel(ctx, todoID, labelID, subject.UserID); err != nil {
+		return fmt.Errorf("[RemoveTodoLabel] remove label:%w", err)
+	}
+
+	return nil
+}
+
+// editableLabelSubject возвращает отправителя запроса, если он может менять метку.
+// Чужая личная метка и метка пространства, в котором он не состоит, неотличимы от несуществующих
+func (s *GatewayService) editableLabelSubject(ctx context.Context, labelID uuid.UUID) (policy.Subject, error) {
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+
+	label, err := s.todoServiceClient.GetLabel(ctx, labelID)
+	if err != nil {
+		return policy.Subject{}, fmt.Errorf("get label:%w", err)
+	}
+
+	role, err := s.workspaceRole(ctx, subject, label.WorkspaceID)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+
+	if label.WorkspaceID == nil && label.Owner != subject.UserID ||
+		label.WorkspaceID != nil && !subject.CanReadWorkspace(role) {
+		return policy.Subject{}, app_errors.ErrNotFound
+	}
+
+	if !subject.CanEditLabel(label, role) {
+		return policy.Subject{}, app_errors.ErrForbidden
+	}
+
+	return subject, nil
+}
+
+// updatableTodoSubject возвращает отправителя запроса, если он может менять задачу
+func (s *GatewayService) updatableTodoSubject(ctx context.Context, todoID uuid.UUID) (policy.Subject, error) {
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+
+	todo, err := s.todoServiceClient.GetToDo(ctx, todoID)
+	if err != nil {
+		return policy.Subject{}, fmt.Errorf("get todo:%w", err)
+	}
+
+	role, err := s.workspaceRole(ctx, subject, todo.WorkspaceID)
+	if err != nil {
+		return policy.Subject{}, err
+	}
+
+	if !subject.CanUpdateTodo(todo, role) {
+		return policy.Subject{}, app_errors.ErrForbidden
+	}
+
+	return subject, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/redis"
+	"github.com/opentracing/opentracing-go"
+)
+
+// maxMFAAttempts - сколько кодов можно проверить по одному MFA challenge токену
+const maxMFAAttempts = 5
+
+// LoginMFA - второй шаг логина: обменивает MFA challenge токен и код второго фактора на пару токенов
+func (s *GatewayService) LoginMFA(ctx context.Context, request *models.MFALoginDTO) (*models.UserTokens, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.LoginMFA")
+	defer span.Finish()
+
+	if err := s.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[LoginMFA] validate:%w", err)
+	}
+
+	challenge, err := s.jwtUtil.VerifyMFAChallengeToken(request.MFAToken)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginMFA] verify mfa token:%s: %w", err, app_errors.ErrInvalidToken)
+	}
+
+	pending, err := s.redisManager.GetMFAChallenge(ctx, challenge.ID)
+	if err != nil {
+		if errors.Is(err, redis.ErrTokenRevoked) {
+			return nil, fmt.Errorf("[LoginMFA] mfa token used or expired: %w", app_errors.ErrInvalidToken)
+		}
+		return nil, fmt.Errorf("[LoginMFA] get mfa token:%w", err)
+	}
+
+	// неверные коды считаются неудачными попытками входа того же логина, иначе перебор кодов
+	// обходил бы блокировку: за каждым новым challenge достаточно снова ввести пароль
+	login := &models.UserLoginDTO{Username: pending.Username, Email: pending.Email}
+	client, _ := ctxutil.GetClientInfoFromContext(ctx)
+	retryAfter, err := s.loginGuard.Check(ctx, loginName(login), client.IP)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginMFA] check lock:%w", err)
+	}
+	if retryAfter > 0 {
+		return nil, fmt.Errorf("[LoginMFA] %w", &app_errors.LoginLockedError{RetryAfter: retryAfter})
+	}
+
+	attempts, err := s.redisManager.RegisterMFAAttempt(ctx, challenge.ID)
+	if err != nil {
+		if errors.Is(err, redis.ErrTokenRevoked) {
+			return nil, fmt.Errorf("[LoginMFA] mfa token used or expired: %w", app_errors.ErrInvalidToken)
+		}
+		return nil, fmt.Errorf("[LoginMFA] register attempt:%w", err)
+	}
+
+	// перебор кодов: после нескольких неудачных попыток нужно заново ввести пароль
+	if attempts > maxMFAAttempts {
+		if _, err := s.redisManager.ConsumeMFAChallenge(ctx, challenge.ID); err != nil {
+			return nil, fmt.Errorf("[LoginMFA] consume mfa token:%w", err)
+		}
+		return nil, fmt.Errorf("[LoginMFA] too many attempts: %w", app_errors.ErrInvalidToken)
+	}
+
+	user, err := s.usersServiceClient.VerifyMFA(ctx, &models.MFACodeDTO{
+		ID:   challenge.UserID,
+		Code: request.Code,
+	})
+	if err != nil {
+		if errors.Is(err, app_errors.ErrInvalidMFACode) {
+			if guardErr := s.registerLoginFailure(ctx, login, client.IP); guardErr != nil {
+				return nil, fmt.Errorf("[LoginMFA] %s: %w", guardErr, err)
+			}
+		}
+		return nil, fmt.Errorf("[LoginMFA] verify code:%w", err)
+	}
+
+	// challenge одноразовый: при параллельных запросах токены получит только один
+	consumed, err := s.redisManager.ConsumeMFAChallenge(ctx, challenge.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginMFA] consume mfa token:%w", err)
+	}
+	if !consumed {
+		return nil, fmt.Errorf("[LoginMFA] mfa token already used: %w", app_errors.ErrInvalidToken)
+	}
+
+	// неудачные попытки сбрасываются, только когда пройдены оба фактора
+	err = s.loginGuard.RegisterSuccess(ctx, loginName(login))
+	if err != nil {
+		return nil, fmt.Errorf("[LoginMFA] reset failures:%w", err)
+	}
+
+	tokens, err := s.startSession(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginMFA] %w", err)
+	}
+
+	return tokens, nil
+}
+
+// EnrollTOTP создает TOTP секрет текущему пользователю. MFA включится после ConfirmTOTP
+func (s *GatewayService) EnrollTOTP(ctx context.Context) (*models.TOTPEnrollmentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.EnrollTOTP")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := s.usersServiceClient.EnrollTOTP(ctx, subject.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[EnrollTOTP] enroll:%w", err)
+	}
+
+	return enrollment, nil
+}
+
+// ConfirmTOTP включает MFA текущему пользователю и возвращает коды восстановления
+func (s *GatewayService) ConfirmTOTP(ctx context.Context, request *models.MFACodeDTO) (*models.RecoveryCodesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmTOTP")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// второй фактор настраивается только для себя
+	request.ID = subject.UserID
+
+	if err := s.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[ConfirmTOTP] validate:%w", err)
+	}
+
+	codes, err := s.usersServiceClient.ConfirmTOTP(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmTOTP] confirm:%w", err)
+	}
+
+	return &models.RecoveryCodesDTO{Codes: codes}, nil
+}
+
+// DisableTOTP выключает MFA текущему пользователю по действующему коду
+func (s *GatewayService) DisableTOTP(ctx context.Context, request *models.MFACodeDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DisableTOTP")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	request.ID = subject.UserID
+
+	if err := s.validator.Validate(request); err != nil {
+		return fmt.Errorf("[DisableTOTP] validate:%w", err)
+	}
+
+	err = s.usersServiceClient.DisableTOTP(ctx, request)
+	if err != nil {
+		return fmt.Errorf("[DisableTOTP] disable:%w", err)
+	}
+
+	return nil
+}
+
+// issueMFAChallenge выпускает MFA challenge токен вместо пары токенов. login - логин, по которому
+// начат вход, по нему считаются неверные коды
+func (s *GatewayService) issueMFAChallenge(ctx context.Context, userID int, login *models.UserLoginDTO) (*models.UserTokens, error) {
+	token, claims, err := s.jwtUtil.GenerateMFAChallengeToken(userID)
+	if err != nil {
+		return nil, fmt.Errorf("generate mfa token:%w", err)
+	}
+
+	err = s.redisManager.StoreMFAChallenge(ctx, claims.ID, &redis.MFAChallenge{
+		UserID:   userID,
+		Username: login.Username,
+		Email:    login.Email,
+	}, claims.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("store mfa token:%w", err)
+	}
+
+	return &models.UserTokens{
+		MFARequired: true,
+		MFAToken:    token,
+	}, nil
+}
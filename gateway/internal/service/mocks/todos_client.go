@@ -10,6 +10,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockTodoServiceClient is a mock of TodoServiceClient interface.
@@ -35,11 +36,99 @@ func (m *MockTodoServiceClient) EXPECT() *MockTodoServiceClientMockRecorder {
 	return m.recorder
 }
 
+// AcceptWorkspaceInvitation mocks base method.
+func (m *MockTodoServiceClient) AcceptWorkspaceInvitation(arg0 context.Context, arg1 string, arg2 int) (*models.WorkspaceMemberDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptWorkspaceInvitation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.WorkspaceMemberDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptWorkspaceInvitation indicates an expected call of AcceptWorkspaceInvitation.
+func (mr *MockTodoServiceClientMockRecorder) AcceptWorkspaceInvitation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptWorkspaceInvitation", reflect.TypeOf((*MockTodoServiceClient)(nil).AcceptWorkspaceInvitation), arg0, arg1, arg2)
+}
+
+// AddComment mocks base method.
+func (m *MockTodoServiceClient) AddComment(arg0 context.Context, arg1 uuid.UUID, arg2 int, arg3 string) (*models.CommentDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddComment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.CommentDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddComment indicates an expected call of AddComment.
+func (mr *MockTodoServiceClientMockRecorder) AddComment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockTodoServiceClient)(nil).AddComment), arg0, arg1, arg2, arg3)
+}
+
+// AddTodoDependency mocks base method.
+func (m *MockTodoServiceClient) AddTodoDependency(arg0 context.Context, arg1, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTodoDependency", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTodoDependency indicates an expected call of AddTodoDependency.
+func (mr *MockTodoServiceClientMockRecorder) AddTodoDependency(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTodoDependency", reflect.TypeOf((*MockTodoServiceClient)(nil).AddTodoDependency), arg0, arg1, arg2)
+}
+
+// AddTodoLabel mocks base method.
+func (m *MockTodoServiceClient) AddTodoLabel(arg0 context.Context, arg1, arg2 uuid.UUID, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTodoLabel", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTodoLabel indicates an expected call of AddTodoLabel.
+func (mr *MockTodoServiceClientMockRecorder) AddTodoLabel(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTodoLabel", reflect.TypeOf((*MockTodoServiceClient)(nil).AddTodoLabel), arg0, arg1, arg2, arg3)
+}
+
+// BulkUpdateTodos mocks base method.
+func (m *MockTodoServiceClient) BulkUpdateTodos(arg0 context.Context, arg1 int, arg2 []uuid.UUID, arg3 *models.BulkUpdateTodosDTO) (*models.BulkUpdateTodosResultDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateTodos", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.BulkUpdateTodosResultDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateTodos indicates an expected call of BulkUpdateTodos.
+func (mr *MockTodoServiceClientMockRecorder) BulkUpdateTodos(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateTodos", reflect.TypeOf((*MockTodoServiceClient)(nil).BulkUpdateTodos), arg0, arg1, arg2, arg3)
+}
+
+// CreateLabel mocks base method.
+func (m *MockTodoServiceClient) CreateLabel(arg0 context.Context, arg1 *models.CreateLabelDTO) (*models.LabelDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLabel", arg0, arg1)
+	ret0, _ := ret[0].(*models.LabelDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockTodoServiceClientMockRecorder) CreateLabel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockTodoServiceClient)(nil).CreateLabel), arg0, arg1)
+}
+
 // CreateToDo mocks base method.
-func (m *MockTodoServiceClient) CreateToDo(arg0 context.Context, arg1 *models.CreateTodoDTO) (int, error) {
+func (m *MockTodoServiceClient) CreateToDo(arg0 context.Context, arg1 *models.CreateTodoDTO) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateToDo", arg0, arg1)
-	ret0, _ := ret[0].(int)
+	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -50,22 +139,123 @@ func (mr *MockTodoServiceClientMockRecorder) CreateToDo(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToDo", reflect.TypeOf((*MockTodoServiceClient)(nil).CreateToDo), arg0, arg1)
 }
 
+// CreateWorkspace mocks base method.
+func (m *MockTodoServiceClient) CreateWorkspace(arg0 context.Context, arg1 int, arg2 *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspace", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.WorkspaceDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkspace indicates an expected call of CreateWorkspace.
+func (mr *MockTodoServiceClientMockRecorder) CreateWorkspace(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspace", reflect.TypeOf((*MockTodoServiceClient)(nil).CreateWorkspace), arg0, arg1, arg2)
+}
+
+// DeleteComment mocks base method.
+func (m *MockTodoServiceClient) DeleteComment(arg0 context.Context, arg1, arg2 uuid.UUID, arg3 int, arg4 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockTodoServiceClientMockRecorder) DeleteComment(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockTodoServiceClient)(nil).DeleteComment), arg0, arg1, arg2, arg3, arg4)
+}
+
+// DeleteLabel mocks base method.
+func (m *MockTodoServiceClient) DeleteLabel(arg0 context.Context, arg1 uuid.UUID, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabel", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockTodoServiceClientMockRecorder) DeleteLabel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockTodoServiceClient)(nil).DeleteLabel), arg0, arg1, arg2)
+}
+
 // DeleteToDo mocks base method.
-func (m *MockTodoServiceClient) DeleteToDo(arg0 context.Context, arg1 int) error {
+func (m *MockTodoServiceClient) DeleteToDo(arg0 context.Context, arg1 uuid.UUID, arg2 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteToDo", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteToDo", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteToDo indicates an expected call of DeleteToDo.
-func (mr *MockTodoServiceClientMockRecorder) DeleteToDo(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockTodoServiceClientMockRecorder) DeleteToDo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToDo", reflect.TypeOf((*MockTodoServiceClient)(nil).DeleteToDo), arg0, arg1, arg2)
+}
+
+// DeleteWorkspace mocks base method.
+func (m *MockTodoServiceClient) DeleteWorkspace(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspace indicates an expected call of DeleteWorkspace.
+func (mr *MockTodoServiceClientMockRecorder) DeleteWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspace", reflect.TypeOf((*MockTodoServiceClient)(nil).DeleteWorkspace), arg0, arg1)
+}
+
+// EditComment mocks base method.
+func (m *MockTodoServiceClient) EditComment(arg0 context.Context, arg1, arg2 uuid.UUID, arg3 int, arg4 string) (*models.CommentDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditComment", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.CommentDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditComment indicates an expected call of EditComment.
+func (mr *MockTodoServiceClientMockRecorder) EditComment(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditComment", reflect.TypeOf((*MockTodoServiceClient)(nil).EditComment), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ExportTodos mocks base method.
+func (m *MockTodoServiceClient) ExportTodos(arg0 context.Context, arg1 *models.ListTodosDTO, arg2 func(*models.TodoDTO) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTodos", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportTodos indicates an expected call of ExportTodos.
+func (mr *MockTodoServiceClientMockRecorder) ExportTodos(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTodos", reflect.TypeOf((*MockTodoServiceClient)(nil).ExportTodos), arg0, arg1, arg2)
+}
+
+// GetLabel mocks base method.
+func (m *MockTodoServiceClient) GetLabel(arg0 context.Context, arg1 uuid.UUID) (*models.LabelDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabel", arg0, arg1)
+	ret0, _ := ret[0].(*models.LabelDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabel indicates an expected call of GetLabel.
+func (mr *MockTodoServiceClientMockRecorder) GetLabel(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToDo", reflect.TypeOf((*MockTodoServiceClient)(nil).DeleteToDo), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabel", reflect.TypeOf((*MockTodoServiceClient)(nil).GetLabel), arg0, arg1)
 }
 
 // GetToDo mocks base method.
-func (m *MockTodoServiceClient) GetToDo(arg0 context.Context, arg1 int) (*models.TodoDTO, error) {
+func (m *MockTodoServiceClient) GetToDo(arg0 context.Context, arg1 uuid.UUID) (*models.TodoDTO, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetToDo", arg0, arg1)
 	ret0, _ := ret[0].(*models.TodoDTO)
@@ -80,7 +270,7 @@ func (mr *MockTodoServiceClientMockRecorder) GetToDo(arg0, arg1 interface{}) *go
 }
 
 // GetToDos mocks base method.
-func (m *MockTodoServiceClient) GetToDos(arg0 context.Context, arg1 int) (*models.TodoDTO, error) {
+func (m *MockTodoServiceClient) GetToDos(arg0 context.Context, arg1 uuid.UUID) (*models.TodoDTO, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetToDos", arg0, arg1)
 	ret0, _ := ret[0].(*models.TodoDTO)
@@ -94,11 +284,248 @@ func (mr *MockTodoServiceClientMockRecorder) GetToDos(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToDos", reflect.TypeOf((*MockTodoServiceClient)(nil).GetToDos), arg0, arg1)
 }
 
+// GetUserToDos mocks base method.
+func (m *MockTodoServiceClient) GetUserToDos(arg0 context.Context, arg1 int) ([]*models.TodoDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserToDos", arg0, arg1)
+	ret0, _ := ret[0].([]*models.TodoDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserToDos indicates an expected call of GetUserToDos.
+func (mr *MockTodoServiceClientMockRecorder) GetUserToDos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserToDos", reflect.TypeOf((*MockTodoServiceClient)(nil).GetUserToDos), arg0, arg1)
+}
+
+// GetWorkspaceMember mocks base method.
+func (m *MockTodoServiceClient) GetWorkspaceMember(arg0 context.Context, arg1 uuid.UUID, arg2 int) (*models.WorkspaceMemberDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.WorkspaceMemberDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceMember indicates an expected call of GetWorkspaceMember.
+func (mr *MockTodoServiceClientMockRecorder) GetWorkspaceMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceMember", reflect.TypeOf((*MockTodoServiceClient)(nil).GetWorkspaceMember), arg0, arg1, arg2)
+}
+
+// ImportTodos mocks base method.
+func (m *MockTodoServiceClient) ImportTodos(arg0 context.Context, arg1 *models.ImportTodosRequestDTO) (*models.ImportTodosResultDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTodos", arg0, arg1)
+	ret0, _ := ret[0].(*models.ImportTodosResultDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTodos indicates an expected call of ImportTodos.
+func (mr *MockTodoServiceClientMockRecorder) ImportTodos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTodos", reflect.TypeOf((*MockTodoServiceClient)(nil).ImportTodos), arg0, arg1)
+}
+
+// InviteWorkspaceMember mocks base method.
+func (m *MockTodoServiceClient) InviteWorkspaceMember(arg0 context.Context, arg1 uuid.UUID, arg2 int, arg3 *models.InviteWorkspaceMemberRequestDTO) (*models.WorkspaceInvitationDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteWorkspaceMember", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.WorkspaceInvitationDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InviteWorkspaceMember indicates an expected call of InviteWorkspaceMember.
+func (mr *MockTodoServiceClientMockRecorder) InviteWorkspaceMember(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteWorkspaceMember", reflect.TypeOf((*MockTodoServiceClient)(nil).InviteWorkspaceMember), arg0, arg1, arg2, arg3)
+}
+
+// ListComments mocks base method.
+func (m *MockTodoServiceClient) ListComments(arg0 context.Context, arg1 uuid.UUID, arg2, arg3 int) (*models.CommentListDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComments", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.CommentListDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComments indicates an expected call of ListComments.
+func (mr *MockTodoServiceClientMockRecorder) ListComments(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockTodoServiceClient)(nil).ListComments), arg0, arg1, arg2, arg3)
+}
+
+// ListLabels mocks base method.
+func (m *MockTodoServiceClient) ListLabels(arg0 context.Context, arg1 *models.ListLabelsDTO) ([]*models.LabelDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLabels", arg0, arg1)
+	ret0, _ := ret[0].([]*models.LabelDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLabels indicates an expected call of ListLabels.
+func (mr *MockTodoServiceClientMockRecorder) ListLabels(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLabels", reflect.TypeOf((*MockTodoServiceClient)(nil).ListLabels), arg0, arg1)
+}
+
+// ListToDos mocks base method.
+func (m *MockTodoServiceClient) ListToDos(arg0 context.Context, arg1 *models.ListTodosDTO) ([]*models.TodoDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToDos", arg0, arg1)
+	ret0, _ := ret[0].([]*models.TodoDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToDos indicates an expected call of ListToDos.
+func (mr *MockTodoServiceClientMockRecorder) ListToDos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToDos", reflect.TypeOf((*MockTodoServiceClient)(nil).ListToDos), arg0, arg1)
+}
+
+// ListTodoHistory mocks base method.
+func (m *MockTodoServiceClient) ListTodoHistory(arg0 context.Context, arg1 uuid.UUID, arg2, arg3 int) (*models.TodoEventListDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.TodoEventListDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoHistory indicates an expected call of ListTodoHistory.
+func (mr *MockTodoServiceClientMockRecorder) ListTodoHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoHistory", reflect.TypeOf((*MockTodoServiceClient)(nil).ListTodoHistory), arg0, arg1, arg2, arg3)
+}
+
+// ListTodoTree mocks base method.
+func (m *MockTodoServiceClient) ListTodoTree(arg0 context.Context, arg1 *models.ListTodosDTO) ([]*models.TodoNodeDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoTree", arg0, arg1)
+	ret0, _ := ret[0].([]*models.TodoNodeDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoTree indicates an expected call of ListTodoTree.
+func (mr *MockTodoServiceClientMockRecorder) ListTodoTree(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoTree", reflect.TypeOf((*MockTodoServiceClient)(nil).ListTodoTree), arg0, arg1)
+}
+
+// ListWorkspaceMembers mocks base method.
+func (m *MockTodoServiceClient) ListWorkspaceMembers(arg0 context.Context, arg1 uuid.UUID) ([]*models.WorkspaceMemberDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaceMembers", arg0, arg1)
+	ret0, _ := ret[0].([]*models.WorkspaceMemberDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaceMembers indicates an expected call of ListWorkspaceMembers.
+func (mr *MockTodoServiceClientMockRecorder) ListWorkspaceMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaceMembers", reflect.TypeOf((*MockTodoServiceClient)(nil).ListWorkspaceMembers), arg0, arg1)
+}
+
+// ListWorkspaces mocks base method.
+func (m *MockTodoServiceClient) ListWorkspaces(arg0 context.Context, arg1 int) ([]*models.WorkspaceDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaces", arg0, arg1)
+	ret0, _ := ret[0].([]*models.WorkspaceDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaces indicates an expected call of ListWorkspaces.
+func (mr *MockTodoServiceClientMockRecorder) ListWorkspaces(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaces", reflect.TypeOf((*MockTodoServiceClient)(nil).ListWorkspaces), arg0, arg1)
+}
+
+// RemoveTodoDependency mocks base method.
+func (m *MockTodoServiceClient) RemoveTodoDependency(arg0 context.Context, arg1, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTodoDependency", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTodoDependency indicates an expected call of RemoveTodoDependency.
+func (mr *MockTodoServiceClientMockRecorder) RemoveTodoDependency(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTodoDependency", reflect.TypeOf((*MockTodoServiceClient)(nil).RemoveTodoDependency), arg0, arg1, arg2)
+}
+
+// RemoveTodoLabel mocks base method.
+func (m *MockTodoServiceClient) RemoveTodoLabel(arg0 context.Context, arg1, arg2 uuid.UUID, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTodoLabel", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTodoLabel indicates an expected call of RemoveTodoLabel.
+func (mr *MockTodoServiceClientMockRecorder) RemoveTodoLabel(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTodoLabel", reflect.TypeOf((*MockTodoServiceClient)(nil).RemoveTodoLabel), arg0, arg1, arg2, arg3)
+}
+
+// RemoveWorkspaceMember mocks base method.
+func (m *MockTodoServiceClient) RemoveWorkspaceMember(arg0 context.Context, arg1 uuid.UUID, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWorkspaceMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWorkspaceMember indicates an expected call of RemoveWorkspaceMember.
+func (mr *MockTodoServiceClientMockRecorder) RemoveWorkspaceMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWorkspaceMember", reflect.TypeOf((*MockTodoServiceClient)(nil).RemoveWorkspaceMember), arg0, arg1, arg2)
+}
+
+// SearchTodos mocks base method.
+func (m *MockTodoServiceClient) SearchTodos(arg0 context.Context, arg1 *models.SearchTodosDTO) ([]*models.SearchResultDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTodos", arg0, arg1)
+	ret0, _ := ret[0].([]*models.SearchResultDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTodos indicates an expected call of SearchTodos.
+func (mr *MockTodoServiceClientMockRecorder) SearchTodos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTodos", reflect.TypeOf((*MockTodoServiceClient)(nil).SearchTodos), arg0, arg1)
+}
+
+// UpdateLabel mocks base method.
+func (m *MockTodoServiceClient) UpdateLabel(arg0 context.Context, arg1 uuid.UUID, arg2 int, arg3 *models.LabelRequestDTO) (*models.LabelDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabel", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.LabelDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLabel indicates an expected call of UpdateLabel.
+func (mr *MockTodoServiceClientMockRecorder) UpdateLabel(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabel", reflect.TypeOf((*MockTodoServiceClient)(nil).UpdateLabel), arg0, arg1, arg2, arg3)
+}
+
 // UpdateToDo mocks base method.
-func (m *MockTodoServiceClient) UpdateToDo(arg0 context.Context, arg1 *models.UpdateTodoDTO) (int, error) {
+func (m *MockTodoServiceClient) UpdateToDo(arg0 context.Context, arg1 *models.UpdateTodoDTO) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateToDo", arg0, arg1)
-	ret0, _ := ret[0].(int)
+	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -108,3 +535,33 @@ func (mr *MockTodoServiceClientMockRecorder) UpdateToDo(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToDo", reflect.TypeOf((*MockTodoServiceClient)(nil).UpdateToDo), arg0, arg1)
 }
+
+// UpdateWorkspace mocks base method.
+func (m *MockTodoServiceClient) UpdateWorkspace(arg0 context.Context, arg1 uuid.UUID, arg2 *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspace", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.WorkspaceDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspace indicates an expected call of UpdateWorkspace.
+func (mr *MockTodoServiceClientMockRecorder) UpdateWorkspace(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockTodoServiceClient)(nil).UpdateWorkspace), arg0, arg1, arg2)
+}
+
+// UpdateWorkspaceMember mocks base method.
+func (m *MockTodoServiceClient) UpdateWorkspaceMember(arg0 context.Context, arg1 uuid.UUID, arg2 int, arg3 string) (*models.WorkspaceMemberDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceMember", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.WorkspaceMemberDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceMember indicates an expected call of UpdateWorkspaceMember.
+func (mr *MockTodoServiceClientMockRecorder) UpdateWorkspaceMember(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceMember", reflect.TypeOf((*MockTodoServiceClient)(nil).UpdateWorkspaceMember), arg0, arg1, arg2, arg3)
+}
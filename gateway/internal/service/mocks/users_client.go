@@ -35,6 +35,66 @@ func (m *MockUsersServiceClient) EXPECT() *MockUsersServiceClientMockRecorder {
 	return m.recorder
 }
 
+// AuthenticateAPIKey mocks base method.
+func (m *MockUsersServiceClient) AuthenticateAPIKey(arg0 context.Context, arg1 string) (*models.APIKeyPrincipalDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(*models.APIKeyPrincipalDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockUsersServiceClientMockRecorder) AuthenticateAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockUsersServiceClient)(nil).AuthenticateAPIKey), arg0, arg1)
+}
+
+// ConfirmEmailChange mocks base method.
+func (m *MockUsersServiceClient) ConfirmEmailChange(arg0 context.Context, arg1 string) (*models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEmailChange", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmEmailChange indicates an expected call of ConfirmEmailChange.
+func (mr *MockUsersServiceClientMockRecorder) ConfirmEmailChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEmailChange", reflect.TypeOf((*MockUsersServiceClient)(nil).ConfirmEmailChange), arg0, arg1)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockUsersServiceClient) ConfirmTOTP(arg0 context.Context, arg1 *models.MFACodeDTO) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockUsersServiceClientMockRecorder) ConfirmTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockUsersServiceClient)(nil).ConfirmTOTP), arg0, arg1)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockUsersServiceClient) CreateAPIKey(arg0 context.Context, arg1 *models.CreateAPIKeyDTO) (*models.APIKeyDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(*models.APIKeyDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockUsersServiceClientMockRecorder) CreateAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockUsersServiceClient)(nil).CreateAPIKey), arg0, arg1)
+}
+
 // CreateUser mocks base method.
 func (m *MockUsersServiceClient) CreateUser(arg0 context.Context, arg1 *models.CreateUserDTO) (int, error) {
 	m.ctrl.T.Helper()
@@ -50,18 +110,77 @@ func (mr *MockUsersServiceClientMockRecorder) CreateUser(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUsersServiceClient)(nil).CreateUser), arg0, arg1)
 }
 
-// DeleteUser mocks base method.
-func (m *MockUsersServiceClient) DeleteUser(arg0 context.Context, arg1 int) error {
+// DeactivateUser mocks base method.
+func (m *MockUsersServiceClient) DeactivateUser(arg0 context.Context, arg1 int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeactivateUser", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// DeactivateUser indicates an expected call of DeactivateUser.
+func (mr *MockUsersServiceClientMockRecorder) DeactivateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateUser", reflect.TypeOf((*MockUsersServiceClient)(nil).DeactivateUser), arg0, arg1)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUsersServiceClient) DeleteUser(arg0 context.Context, arg1, arg2 int) (*models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockUsersServiceClientMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockUsersServiceClientMockRecorder) DeleteUser(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUsersServiceClient)(nil).DeleteUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUsersServiceClient)(nil).DeleteUser), arg0, arg1, arg2)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockUsersServiceClient) DisableTOTP(arg0 context.Context, arg1 *models.MFACodeDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockUsersServiceClientMockRecorder) DisableTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockUsersServiceClient)(nil).DisableTOTP), arg0, arg1)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockUsersServiceClient) EnrollTOTP(arg0 context.Context, arg1 int) (*models.TOTPEnrollmentDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", arg0, arg1)
+	ret0, _ := ret[0].(*models.TOTPEnrollmentDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockUsersServiceClientMockRecorder) EnrollTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockUsersServiceClient)(nil).EnrollTOTP), arg0, arg1)
+}
+
+// GetUserByExternalIdentity mocks base method.
+func (m *MockUsersServiceClient) GetUserByExternalIdentity(arg0 context.Context, arg1 *models.ExternalIdentityDTO) (*models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByExternalIdentity", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByExternalIdentity indicates an expected call of GetUserByExternalIdentity.
+func (mr *MockUsersServiceClientMockRecorder) GetUserByExternalIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByExternalIdentity", reflect.TypeOf((*MockUsersServiceClient)(nil).GetUserByExternalIdentity), arg0, arg1)
 }
 
 // GetUserByID mocks base method.
@@ -109,6 +228,108 @@ func (mr *MockUsersServiceClientMockRecorder) GetUserByUsernameOrEmail(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsernameOrEmail", reflect.TypeOf((*MockUsersServiceClient)(nil).GetUserByUsernameOrEmail), arg0, arg1, arg2)
 }
 
+// LinkExternalIdentity mocks base method.
+func (m *MockUsersServiceClient) LinkExternalIdentity(arg0 context.Context, arg1 *models.ExternalIdentityDTO) (*models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkExternalIdentity", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkExternalIdentity indicates an expected call of LinkExternalIdentity.
+func (mr *MockUsersServiceClientMockRecorder) LinkExternalIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkExternalIdentity", reflect.TypeOf((*MockUsersServiceClient)(nil).LinkExternalIdentity), arg0, arg1)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockUsersServiceClient) ListAPIKeys(arg0 context.Context, arg1 int) ([]*models.APIKeyDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", arg0, arg1)
+	ret0, _ := ret[0].([]*models.APIKeyDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockUsersServiceClientMockRecorder) ListAPIKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockUsersServiceClient)(nil).ListAPIKeys), arg0, arg1)
+}
+
+// ListUsers mocks base method.
+func (m *MockUsersServiceClient) ListUsers(arg0 context.Context, arg1, arg2 int) ([]*models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockUsersServiceClientMockRecorder) ListUsers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUsersServiceClient)(nil).ListUsers), arg0, arg1, arg2)
+}
+
+// ReportAccountLocked mocks base method.
+func (m *MockUsersServiceClient) ReportAccountLocked(arg0 context.Context, arg1 *models.AccountLockedDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportAccountLocked", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportAccountLocked indicates an expected call of ReportAccountLocked.
+func (mr *MockUsersServiceClientMockRecorder) ReportAccountLocked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportAccountLocked", reflect.TypeOf((*MockUsersServiceClient)(nil).ReportAccountLocked), arg0, arg1)
+}
+
+// RequestEmailChange mocks base method.
+func (m *MockUsersServiceClient) RequestEmailChange(arg0 context.Context, arg1 *models.EmailChangeRequestDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestEmailChange", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestEmailChange indicates an expected call of RequestEmailChange.
+func (mr *MockUsersServiceClientMockRecorder) RequestEmailChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestEmailChange", reflect.TypeOf((*MockUsersServiceClient)(nil).RequestEmailChange), arg0, arg1)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockUsersServiceClient) RevokeAPIKey(arg0 context.Context, arg1, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockUsersServiceClientMockRecorder) RevokeAPIKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockUsersServiceClient)(nil).RevokeAPIKey), arg0, arg1, arg2)
+}
+
+// UpdateAvatar mocks base method.
+func (m *MockUsersServiceClient) UpdateAvatar(arg0 context.Context, arg1 int, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAvatar", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAvatar indicates an expected call of UpdateAvatar.
+func (mr *MockUsersServiceClientMockRecorder) UpdateAvatar(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAvatar", reflect.TypeOf((*MockUsersServiceClient)(nil).UpdateAvatar), arg0, arg1, arg2)
+}
+
 // UpdatePassword mocks base method.
 func (m *MockUsersServiceClient) UpdatePassword(arg0 context.Context, arg1 *models.UpdateUserPasswordDTO) error {
 	m.ctrl.T.Helper()
@@ -124,11 +345,12 @@ func (mr *MockUsersServiceClientMockRecorder) UpdatePassword(arg0, arg1 interfac
 }
 
 // UpdateUser mocks base method.
-func (m *MockUsersServiceClient) UpdateUser(arg0 context.Context, arg1 *models.UserDTO) error {
+func (m *MockUsersServiceClient) UpdateUser(arg0 context.Context, arg1 *models.UserDTO) (*models.UserDTO, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
@@ -137,6 +359,20 @@ func (mr *MockUsersServiceClientMockRecorder) UpdateUser(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUsersServiceClient)(nil).UpdateUser), arg0, arg1)
 }
 
+// UpdateUserRole mocks base method.
+func (m *MockUsersServiceClient) UpdateUserRole(arg0 context.Context, arg1 *models.UpdateUserRoleDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockUsersServiceClientMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockUsersServiceClient)(nil).UpdateUserRole), arg0, arg1)
+}
+
 // UserLogin mocks base method.
 func (m *MockUsersServiceClient) UserLogin(arg0 context.Context, arg1 *models.UserLoginDTO) (*models.UserDTO, error) {
 	m.ctrl.T.Helper()
@@ -151,3 +387,18 @@ func (mr *MockUsersServiceClientMockRecorder) UserLogin(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserLogin", reflect.TypeOf((*MockUsersServiceClient)(nil).UserLogin), arg0, arg1)
 }
+
+// VerifyMFA mocks base method.
+func (m *MockUsersServiceClient) VerifyMFA(arg0 context.Context, arg1 *models.MFACodeDTO) (*models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockUsersServiceClientMockRecorder) VerifyMFA(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockUsersServiceClient)(nil).VerifyMFA), arg0, arg1)
+}
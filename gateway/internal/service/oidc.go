@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/oidcauth"
+	"gateway/pkg/redis"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// StartOIDCLogin начинает вход через внешнего провайдера: сохраняет state, nonce и PKCE verifier
+// и возвращает адрес страницы входа провайдера
+func (s *GatewayService) StartOIDCLogin(ctx context.Context, providerName string) (*models.OIDCLoginDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.StartOIDCLogin")
+	defer span.Finish()
+
+	provider, err := s.oidcProvider(ctx, providerName)
+	if err != nil {
+		return nil, fmt.Errorf("[StartOIDCLogin] %w", err)
+	}
+
+	state := uuid.NewString()
+	loginState := &redis.OIDCState{
+		Provider:     providerName,
+		Nonce:        uuid.NewString(),
+		CodeVerifier: oidcauth.NewVerifier(),
+	}
+
+	err = s.redisManager.StoreOIDCState(ctx, state, loginState, s.oidc.StateTTL())
+	if err != nil {
+		return nil, fmt.Errorf("[StartOIDCLogin] store state:%w", err)
+	}
+
+	return &models.OIDCLoginDTO{
+		AuthURL: provider.AuthCodeURL(state, loginState.Nonce, loginState.CodeVerifier),
+	}, nil
+}
+
+// CompleteOIDCLogin обменивает код авторизации провайдера на пару токенов.
+// При первом входе учетная запись провайдера привязывается к пользователю или создает нового
+func (s *GatewayService) CompleteOIDCLogin(ctx context.Context, providerName, code, state string) (*models.UserTokens, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CompleteOIDCLogin")
+	defer span.Finish()
+
+	// state одноразовый и привязан к провайдеру: так callback нельзя подделать или повторить
+	loginState, err := s.redisManager.ConsumeOIDCState(ctx, state)
+	if err != nil {
+		if errors.Is(err, redis.ErrOIDCStateNotFound) {
+			return nil, fmt.Errorf("[CompleteOIDCLogin] %s: %w", err, app_errors.ErrInvalidToken)
+		}
+		return nil, fmt.Errorf("[CompleteOIDCLogin] consume state:%w", err)
+	}
+	if loginState.Provider != providerName {
+		return nil, fmt.Errorf("[CompleteOIDCLogin] state issued for another provider: %w", app_errors.ErrInvalidToken)
+	}
+
+	provider, err := s.oidcProvider(ctx, providerName)
+	if err != nil {
+		return nil, fmt.Errorf("[CompleteOIDCLogin] %w", err)
+	}
+
+	identity, err := provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("[CompleteOIDCLogin] %s: %w", err, app_errors.ErrInvalidToken)
+	}
+
+	externalIdentity := &models.ExternalIdentityDTO{
+		Provider:      identity.Provider,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Username:      identity.Username,
+	}
+
+	user, err := s.usersServiceClient.GetUserByExternalIdentity(ctx, externalIdentity)
+	if errors.Is(err, app_errors.ErrNotFound) {
+		user, err = s.usersServiceClient.LinkExternalIdentity(ctx, externalIdentity)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[CompleteOIDCLogin] get user:%w", err)
+	}
+
+	// провайдер заменяет только пароль, второй фактор по-прежнему нужен.
+	// Неверные коды считаются попытками входа по имени пользователя
+	if user.MFAEnabled {
+		challenge, err := s.issueMFAChallenge(ctx, user.ID, &models.UserLoginDTO{Username: user.Username})
+		if err != nil {
+			return nil, fmt.Errorf("[CompleteOIDCLogin] %w", err)
+		}
+
+		return challenge, nil
+	}
+
+	tokens, err := s.startSession(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("[CompleteOIDCLogin] %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (s *GatewayService) oidcProvider(ctx context.Context, name string) (*oidcauth.Provider, error) {
+	provider, err := s.oidc.Provider(ctx, name)
+	if err != nil {
+		if errors.Is(err, oidcauth.ErrUnknownProvider) {
+			return nil, fmt.Errorf("provider %q: %w", name, app_errors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get provider:%w", err)
+	}
+
+	return provider, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+)
+
+// SearchTodos ищет среди задач, созданных отправителем запроса или назначенных на него
+func (s *GatewayService) SearchTodos(ctx context.Context, request *models.SearchTodosDTO) ([]*models.SearchResultDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.SearchTodos")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Query = strings.TrimSpace(request.Query)
+	if err := s.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[SearchTodos] validate:%w", err)
+	}
+
+	request.UserID = subject.UserID
+
+	results, err := s.todoServiceClient.SearchTodos(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("[SearchTodos] search:%w", err)
+	}
+
+	return results, nil
+}
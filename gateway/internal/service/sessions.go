@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/redis"
+	"github.com/opentracing/opentracing-go"
+	"sort"
+	"time"
+)
+
+func (s *GatewayService) ListSessions(ctx context.Context) ([]*models.SessionDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListSessions")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.redisManager.ListSessions(ctx, subject.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListSessions] list sessions:%w", err)
+	}
+
+	currentSessionID, _ := ctxutil.GetSessionIDFromContext(ctx)
+
+	result := make([]*models.SessionDTO, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &models.SessionDTO{
+			ID:        session.ID,
+			Device:    session.Device,
+			UserAgent: session.UserAgent,
+			IP:        session.IP,
+			CreatedAt: session.CreatedAt,
+			LastSeen:  session.LastSeen,
+			Current:   session.ID == currentSessionID,
+		})
+	}
+
+	// последние активные сессии - первыми
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+
+	return result, nil
+}
+
+func (s *GatewayService) RevokeSession(ctx context.Context, sessionID string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RevokeSession")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	session, err := s.redisManager.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, redis.ErrSessionNotFound) {
+			return fmt.Errorf("[RevokeSession] %w", app_errors.ErrNotFound)
+		}
+		return fmt.Errorf("[RevokeSession] get session:%w", err)
+	}
+
+	// чужие сессии не раскрываем, отвечаем так же, как на несуществующую
+	if session.UserID != subject.UserID {
+		return fmt.Errorf("[RevokeSession] %w", app_errors.ErrNotFound)
+	}
+
+	err = s.redisManager.RevokeSession(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("[RevokeSession] revoke session:%w", err)
+	}
+
+	return nil
+}
+
+// newSession собирает сессию для нового логина из данных об устройстве, которые положил middleware
+func newSession(ctx context.Context, userID int, sessionID string) *redis.Session {
+	client, _ := ctxutil.GetClientInfoFromContext(ctx)
+	now := time.Now()
+
+	return &redis.Session{
+		ID:        sessionID,
+		UserID:    userID,
+		Device:    client.Device,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		CreatedAt: now,
+		LastSeen:  now,
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// ListTodoTree возвращает задачи отправителя запроса деревом: корни с вложенными подзадачами
+func (s *GatewayService) ListTodoTree(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoNodeDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListTodoTree")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(filter); err != nil {
+		return nil, fmt.Errorf("[ListTodoTree] validate:%w", err)
+	}
+
+	if err := s.checkWorkspaceRead(ctx, subject, filter.WorkspaceID); err != nil {
+		return nil, fmt.Errorf("[ListTodoTree] %w", err)
+	}
+
+	filter.UserID = subject.UserID
+
+	tree, err := s.todoServiceClient.ListTodoTree(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("[ListTodoTree] list todos:%w", err)
+	}
+
+	return tree, nil
+}
+
+// AddTodoDependency отмечает, что задача todoID ждет выполнения задачи blockedBy.
+// Менять зависимости может тот, кто может менять задачу, а блокирующую задачу он должен видеть
+func (s *GatewayService) AddTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AddTodoDependency")
+	defer span.Finish()
+
+	subject, err := s.updatableTodoSubject(ctx, todoID)
+	if err != nil {
+		return fmt.Errorf("[AddTodoDependency] %w", err)
+	}
+
+	blocker, err := s.todoServiceClient.GetToDo(ctx, blockedBy)
+	if err != nil {
+		return fmt.Errorf("[AddTodoDependency] get blocker:%w", err)
+	}
+
+	role, err := s.workspaceRole(ctx, subject, blocker.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("[AddTodoDependency] blocker: %w", err)
+	}
+
+	if !subject.CanReadTodo(blocker, role) {
+		return fmt.Errorf("[AddTodoDependency] blocker: %w", app_errors.ErrForbidden)
+	}
+
+	if err := s.todoServiceClient.AddTodoDependency(ctx, todoID, blockedBy); err != nil {
+		return fmt.Errorf("[AddTodoDependency] add dependency:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) RemoveTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveTodoDependency")
+	defer span.Finish()
+
+	if _, err := s.updatableTodoSubject(ctx, todoID); err != nil {
+		return fmt.Errorf("[RemoveTodoDependency] %w", err)
+	}
+
+	if err := s.todoServiceClient.RemoveTodoDependency(ctx, todoID, blockedBy); err != nil {
+		return fmt.Errorf("[RemoveTodoDependency] remove dependency:%w", err)
+	}
+
+	return nil
+}
+
+// checkParentAccess - добавлять подзадачи может тот, кто может менять родительскую задачу
+func (s *GatewayService) checkParentAccess(ctx context.Context, subject policy.Subject, parentID uuid.UUID) error {
+	parent, err := s.todoServiceClient.GetToDo(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, app_errors.ErrNotFound) {
+			return validator.NewValidationError(validator.FieldViolation{
+				Field:   "parent_id",
+				Message: "todo does not exist",
+			})
+		}
+
+		return fmt.Errorf("get parent:%w", err)
+	}
+
+	role, err := s.workspaceRole(ctx, subject, parent.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	if !subject.CanUpdateTodo(parent, role) {
+		return fmt.Errorf("parent: %w", app_errors.ErrForbidden)
+	}
+
+	return nil
+}
@@ -3,38 +3,134 @@ package service
 import (
 	"errors"
 	"gateway/internal/service/mocks"
+	"gateway/pkg/avatar"
+	"gateway/pkg/blobstore"
 	"gateway/pkg/jwtutil"
+	"gateway/pkg/loginguard"
+	"gateway/pkg/oidcauth"
+	"gateway/pkg/oidcauth/oidctest"
+	"gateway/pkg/redis"
+	"gateway/pkg/validator"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"os"
 	"testing"
+	"time"
 )
 
 type Mocks struct {
 	UsersServiceClient *mocks.MockUsersServiceClient
 	TodoServiceClient  *mocks.MockTodoServiceClient
+	Redis              *miniredis.Miniredis
+	IdP                *oidctest.Server
+	BlobStore          *blobstore.LocalStore
 }
 
 func getMocks(ctrl *gomock.Controller) *Mocks {
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+
+	idp := oidctest.NewServer()
+
+	blobDir, err := os.MkdirTemp("", "gateway-blobs-*")
+	if err != nil {
+		panic(err)
+	}
+	blobStore, err := blobstore.NewLocalStore(blobDir)
+	if err != nil {
+		panic(err)
+	}
+
+	// серверы redis и провайдера живут до конца теста
+	if cleaner, ok := ctrl.T.(interface{ Cleanup(func()) }); ok {
+		cleaner.Cleanup(redisServer.Close)
+		cleaner.Cleanup(idp.Close)
+		cleaner.Cleanup(func() { os.RemoveAll(blobDir) })
+	}
+
 	return &Mocks{
 		UsersServiceClient: mocks.NewMockUsersServiceClient(ctrl),
 		TodoServiceClient:  mocks.NewMockTodoServiceClient(ctrl),
+		Redis:              redisServer,
+		IdP:                idp,
+		BlobStore:          blobStore,
 	}
 }
 
 func buildTestService(m *Mocks) *GatewayService {
+	jwtUtil, err := jwtutil.New(&jwtutil.Config{
+		Algorithm:       jwtutil.AlgorithmEdDSA,
+		Issuer:          "test-gateway",
+		Audience:        "test-api",
+		AccessTokenExp:  time.Minute,
+		RefreshTokenExp: time.Hour,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	redisManager, err := redis.NewRedisManager(redis.Config{Address: m.Redis.Addr()})
+	if err != nil {
+		panic(err)
+	}
+
 	return NewGatewayService(
-		&jwtutil.JWTUtil{
-			SecretKey:       "key",
-			AccessTokenExp:  100000,
-			RefreshTokenExp: 100000,
-		},
+		jwtUtil,
 		m.TodoServiceClient,
 		m.UsersServiceClient,
-		nil,
+		redisManager,
+		validator.New(&validator.Config{
+			UsernameMinLen:       3,
+			UsernameMaxLen:       32,
+			EmailMaxLen:          254,
+			PasswordMinLen:       8,
+			PasswordMaxLen:       72,
+			PasswordRequireUpper: true,
+			PasswordRequireLower: true,
+			PasswordRequireDigit: true,
+			DescriptionMaxLen:    1000,
+			CommentMaxLen:        4000,
+			LabelNameMaxLen:      50,
+			WorkspaceNameMaxLen:  100,
+		}),
+		loginguard.New(&loginguard.Config{
+			DelayAfterFailures: 3,
+			BaseDelay:          time.Second,
+			MaxDelay:           time.Minute,
+			MaxAccountFailures: 5,
+			MaxIPFailures:      20,
+			FailureWindow:      15 * time.Minute,
+			LockoutDuration:    15 * time.Minute,
+		}, redisManager),
+		oidcauth.New(&oidcauth.Config{
+			Providers: oidcauth.Providers{{
+				Name:         "test",
+				Issuer:       m.IdP.URL,
+				ClientID:     oidctest.ClientID,
+				ClientSecret: oidctest.ClientSecret,
+				RedirectURL:  "http://localhost:8000/api/v1/auth/oidc/test/callback",
+			}},
+			StateTTL: 10 * time.Minute,
+		}),
+		m.BlobStore,
+		avatar.New(&avatar.Config{
+			MaxSize:      64 * 1024,
+			MaxDimension: 1024,
+			LargeSize:    32,
+			SmallSize:    8,
+		}),
 	)
 }
 
 func requireEqualError(t *testing.T, actualErr, expectedErr error) {
+	if _, ok := expectedErr.(*validator.ValidationError); ok {
+		requireValidationError(t, actualErr)
+		return
+	}
+
 	if expectedErr == nil {
 		require.NoError(t, actualErr)
 	} else {
@@ -44,3 +140,11 @@ func requireEqualError(t *testing.T, actualErr, expectedErr error) {
 		}
 	}
 }
+
+func requireValidationError(t *testing.T, actualErr error) *validator.ValidationError {
+	var validationErr *validator.ValidationError
+	require.True(t, errors.As(actualErr, &validationErr), "expected validation error, got: %v", actualErr)
+	require.NotEmpty(t, validationErr.Violations)
+
+	return validationErr
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"testing"
@@ -14,9 +15,10 @@ func BenchmarkTodoService_CreateToDo(b *testing.B) {
 
 	mocks := getMocks(ctrl)
 	service := buildTestService(mocks)
-	ctx := context.Background()
-	newTodo := &models.CreateTodoDTO{}
+	ctx := ctxutil.SetUserIDToContext(context.Background(), 1)
+	newTodo := &models.CreateTodoDTO{Assignee: 1, Description: "bench desc"}
 
+	mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Return(&models.UserDTO{ID: 1}, nil).AnyTimes()
 	mocks.TodoServiceClient.EXPECT().CreateToDo(gomock.Any(), gomock.Any()).Return(uuid.New(), nil).AnyTimes()
 
 	b.ResetTimer()
@@ -2,14 +2,24 @@ package service
 
 import (
 	"context"
+	"errors"
 	appErrors "gateway/internal/app_errors"
 	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"gateway/pkg/validator"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
 )
 
+// contextWithUser возвращает контекст, как его заполняет middleware после проверки токена
+func contextWithUser(userID int, role string) context.Context {
+	ctx := ctxutil.SetUserIDToContext(context.Background(), userID)
+	return ctxutil.SetUserRoleToContext(ctx, role)
+}
+
 func TestTodoService_CreateToDo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -26,40 +36,901 @@ func TestTodoService_CreateToDo(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 
-	existingTodo := &models.TodoDAO{
-		ID: uuid.MustParse("c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"),
-	}
+	errClient := errors.New("client error")
 
 	tests := []struct {
 		name        string
+		ctx         context.Context
 		setup       func()
 		wantErr     bool
 		expectedErr error
 	}{
 		{
 			name: "Success",
+			ctx:  contextWithUser(1, models.RoleUser),
+			setup: func() {
+				mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), newTodo.Assignee).Return(&models.UserDTO{ID: newTodo.Assignee}, nil)
+				mocks.TodoServiceClient.EXPECT().CreateToDo(gomock.Any(), newTodo).Return(newTodo.ID, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:        "OnBehalfOfAnotherUser",
+			ctx:         contextWithUser(3, models.RoleUser),
+			setup:       func() {},
+			wantErr:     true,
+			expectedErr: appErrors.ErrForbidden,
+		},
+		{
+			name: "AdminOnBehalfOfAnotherUser",
+			ctx:  contextWithUser(3, models.RoleAdmin),
 			setup: func() {
-				mocks.TodoServiceClient.EXPECT().CreateToDo(gomock.Any(), newTodo.ID).Return(nil, nil)
+				mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), newTodo.Assignee).Return(&models.UserDTO{ID: newTodo.Assignee}, nil)
+				mocks.TodoServiceClient.EXPECT().CreateToDo(gomock.Any(), newTodo).Return(newTodo.ID, nil)
 			},
 			wantErr: false,
 		},
 		{
-			name: "TodoExistsError",
+			name: "AssigneeNotFound",
+			ctx:  contextWithUser(1, models.RoleUser),
 			setup: func() {
-				mocks.TodoServiceClient.EXPECT().CreateToDo(gomock.Any(), newTodo).Return(existingTodo, nil)
+				mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), newTodo.Assignee).Return(nil, appErrors.ErrNotFound)
 			},
 			wantErr:     true,
-			expectedErr: appErrors.ErrUsernameOrEmailIsUsed,
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name: "ClientError",
+			ctx:  contextWithUser(1, models.RoleUser),
+			setup: func() {
+				mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), newTodo.Assignee).Return(&models.UserDTO{ID: newTodo.Assignee}, nil)
+				mocks.TodoServiceClient.EXPECT().CreateToDo(gomock.Any(), newTodo).Return(uuid.Nil, errClient)
+			},
+			wantErr:     true,
+			expectedErr: errClient,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			_, err := svc.CreateToDo(context.Background(), newTodo)
+			_, err := svc.CreateToDo(tt.ctx, newTodo)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_CreateToDo_DefaultsAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	newTodo := &models.CreateTodoDTO{Assignee: 2, Description: "example desc"}
+
+	mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.UserDTO{ID: 2}, nil)
+	mocks.TodoServiceClient.EXPECT().CreateToDo(gomock.Any(), newTodo).Return(uuid.New(), nil)
+
+	_, err := svc.CreateToDo(contextWithUser(5, models.RoleUser), newTodo)
+
+	require.NoError(t, err)
+	require.Equal(t, 5, newTodo.CreatedBy)
+}
+
+func TestTodoService_GetToDo(t *testing.T) {
+	todo := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 2, Description: "example desc"}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		expectedErr error
+	}{
+		{"Creator", contextWithUser(1, models.RoleUser), nil},
+		{"Assignee", contextWithUser(2, models.RoleUser), nil},
+		{"Admin", contextWithUser(3, models.RoleAdmin), nil},
+		{"Stranger", contextWithUser(3, models.RoleUser), appErrors.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := getMocks(gomock.NewController(t))
+			svc := buildTestService(mocks)
+
+			mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+
+			_, err := svc.GetToDo(tt.ctx, todo.ID)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_UpdateToDo(t *testing.T) {
+	existingTodo := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 2, Description: "example desc", Version: 3}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		assignee    int
+		version     int
+		setup       func(m *Mocks)
+		expectedErr error
+	}{
+		{
+			name:     "AssigneeUpdatesDescription",
+			ctx:      contextWithUser(2, models.RoleUser),
+			assignee: 2,
+			version:  3,
+			setup: func(m *Mocks) {
+				m.TodoServiceClient.EXPECT().UpdateToDo(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *models.UpdateTodoDTO) (uuid.UUID, error) {
+						require.Equal(t, 3, todo.Version)
+						return todo.ID, nil
+					})
+			},
+		},
+		{
+			name:        "StaleVersion",
+			ctx:         contextWithUser(2, models.RoleUser),
+			assignee:    2,
+			version:     2,
+			setup:       func(m *Mocks) {},
+			expectedErr: appErrors.ErrVersionConflict,
+		},
+		{
+			name:        "AssigneeReassigns",
+			ctx:         contextWithUser(2, models.RoleUser),
+			assignee:    3,
+			version:     3,
+			setup:       func(m *Mocks) {},
+			expectedErr: appErrors.ErrForbidden,
+		},
+		{
+			name:     "CreatorReassigns",
+			ctx:      contextWithUser(1, models.RoleUser),
+			assignee: 3,
+			version:  3,
+			setup: func(m *Mocks) {
+				m.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 3).Return(&models.UserDTO{ID: 3}, nil)
+				m.TodoServiceClient.EXPECT().UpdateToDo(gomock.Any(), gomock.Any()).Return(existingTodo.ID, nil)
+			},
+		},
+		{
+			name:        "Stranger",
+			ctx:         contextWithUser(4, models.RoleUser),
+			assignee:    2,
+			version:     3,
+			setup:       func(m *Mocks) {},
+			expectedErr: appErrors.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := getMocks(gomock.NewController(t))
+			svc := buildTestService(mocks)
+
+			mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), existingTodo.ID).Return(existingTodo, nil)
+			tt.setup(mocks)
+
+			request := &models.UpdateTodoDTO{ID: existingTodo.ID, Assignee: tt.assignee, Description: "new desc", Version: tt.version}
+			_, err := svc.UpdateToDo(tt.ctx, request)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_DeleteToDo(t *testing.T) {
+	todo := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 2, Description: "example desc", Version: 3}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		version     int
+		setup       func(m *Mocks)
+		expectedErr error
+	}{
+		{
+			name:    "Creator",
+			ctx:     contextWithUser(1, models.RoleUser),
+			version: 3,
+			setup: func(m *Mocks) {
+				m.TodoServiceClient.EXPECT().DeleteToDo(gomock.Any(), todo.ID, 3).Return(nil)
+			},
+		},
+		{
+			name:        "StaleVersion",
+			ctx:         contextWithUser(1, models.RoleUser),
+			version:     2,
+			setup:       func(m *Mocks) {},
+			expectedErr: appErrors.ErrVersionConflict,
+		},
+		{
+			name:        "Assignee",
+			ctx:         contextWithUser(2, models.RoleUser),
+			version:     3,
+			setup:       func(m *Mocks) {},
+			expectedErr: appErrors.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := getMocks(gomock.NewController(t))
+			svc := buildTestService(mocks)
+
+			mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+			tt.setup(mocks)
+
+			err := svc.DeleteToDo(tt.ctx, todo.ID, tt.version)
 
 			requireEqualError(t, err, tt.expectedErr)
 		})
 	}
+}
+
+func TestTodoService_BulkUpdateTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	workspaceID := uuid.New()
+	own := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 1}
+	foreign := &models.TodoDTO{ID: uuid.New(), CreatedBy: 3, Assignee: 4}
+	assigned := &models.TodoDTO{ID: uuid.New(), CreatedBy: 3, Assignee: 1}
+	shared := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 1, WorkspaceID: &workspaceID}
+	missingID := uuid.New()
+
+	t.Run("ReassignChecksEachTodo", func(t *testing.T) {
+		request := &models.BulkUpdateTodosDTO{
+			IDs:       []uuid.UUID{own.ID, foreign.ID, missingID, own.ID},
+			Operation: models.BulkOperationReassign,
+			Assignee:  5,
+		}
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 5).Return(&models.UserDTO{ID: 5}, nil)
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), own.ID).Return(own, nil)
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), foreign.ID).Return(foreign, nil)
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), missingID).Return(nil, appErrors.ErrNotFound)
+		mocks.TodoServiceClient.EXPECT().BulkUpdateTodos(gomock.Any(), 1, []uuid.UUID{own.ID}, request).
+			Return(&models.BulkUpdateTodosResultDTO{Results: []models.BulkItemResultDTO{{ID: own.ID}}, Succeeded: 1}, nil)
+
+		result, err := svc.BulkUpdateTodos(contextWithUser(1, models.RoleUser), request)
+		require.NoError(t, err)
+		require.Equal(t, []models.BulkItemResultDTO{
+			{ID: own.ID},
+			{ID: foreign.ID, Error: models.BulkErrorForbidden},
+			{ID: missingID, Error: models.BulkErrorNotFound},
+		}, result.Results)
+		require.Equal(t, 1, result.Succeeded)
+		require.Equal(t, 2, result.Failed)
+	})
+
+	t.Run("AssigneeCompletesByFilter", func(t *testing.T) {
+		labelID := uuid.New()
+		request := &models.BulkUpdateTodosDTO{
+			Filter:    &models.BulkTodosFilterDTO{LabelIDs: []uuid.UUID{labelID}},
+			Operation: models.BulkOperationComplete,
+		}
+		mocks.TodoServiceClient.EXPECT().ListToDos(gomock.Any(), &models.ListTodosDTO{UserID: 1, LabelIDs: []uuid.UUID{labelID}, Limit: 200}).
+			Return([]*models.TodoDTO{assigned, own}, nil)
+		mocks.TodoServiceClient.EXPECT().BulkUpdateTodos(gomock.Any(), 1, []uuid.UUID{assigned.ID, own.ID}, request).
+			Return(&models.BulkUpdateTodosResultDTO{
+				Results:   []models.BulkItemResultDTO{{ID: assigned.ID}, {ID: own.ID, Error: "blocked"}},
+				Succeeded: 1,
+				Failed:    1,
+			}, nil)
+
+		result, err := svc.BulkUpdateTodos(contextWithUser(1, models.RoleUser), request)
+		require.NoError(t, err)
+		require.Equal(t, []models.BulkItemResultDTO{{ID: assigned.ID}, {ID: own.ID, Error: "blocked"}}, result.Results)
+	})
+
+	t.Run("AssigneeCannotDelete", func(t *testing.T) {
+		request := &models.BulkUpdateTodosDTO{IDs: []uuid.UUID{assigned.ID}, Operation: models.BulkOperationDelete}
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), assigned.ID).Return(assigned, nil)
+
+		result, err := svc.BulkUpdateTodos(contextWithUser(1, models.RoleUser), request)
+		require.NoError(t, err)
+		require.Equal(t, []models.BulkItemResultDTO{{ID: assigned.ID, Error: models.BulkErrorForbidden}}, result.Results)
+	})
+
+	t.Run("AssigneeOutsideWorkspace", func(t *testing.T) {
+		request := &models.BulkUpdateTodosDTO{IDs: []uuid.UUID{shared.ID}, Operation: models.BulkOperationReassign, Assignee: 5}
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 5).Return(&models.UserDTO{ID: 5}, nil)
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), shared.ID).Return(shared, nil)
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 1).
+			Return(&models.WorkspaceMemberDTO{WorkspaceID: workspaceID, UserID: 1, Role: models.WorkspaceRoleOwner}, nil)
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 5).Return(nil, appErrors.ErrNotFound)
+
+		result, err := svc.BulkUpdateTodos(contextWithUser(1, models.RoleUser), request)
+		require.NoError(t, err)
+		require.Equal(t, []models.BulkItemResultDTO{{ID: shared.ID, Error: models.BulkErrorAssigneeNotMember}}, result.Results)
+	})
+
+	t.Run("IDsWithFilter", func(t *testing.T) {
+		_, err := svc.BulkUpdateTodos(contextWithUser(1, models.RoleUser), &models.BulkUpdateTodosDTO{
+			IDs:       []uuid.UUID{own.ID},
+			Filter:    &models.BulkTodosFilterDTO{},
+			Operation: models.BulkOperationComplete,
+		})
+		requireValidationError(t, err)
+	})
+
+	t.Run("NoTargets", func(t *testing.T) {
+		_, err := svc.BulkUpdateTodos(contextWithUser(1, models.RoleUser), &models.BulkUpdateTodosDTO{
+			Operation: models.BulkOperationComplete,
+		})
+		requireValidationError(t, err)
+	})
+}
+
+func TestTodoService_ExportTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	t.Run("UsernamesInsteadOfIDs", func(t *testing.T) {
+		first := &models.TodoDTO{ID: uuid.New(), Description: "first", CreatedBy: 1, Assignee: 2}
+		second := &models.TodoDTO{ID: uuid.New(), Description: "second", CreatedBy: 2, Assignee: 3}
+		mocks.TodoServiceClient.EXPECT().ExportTodos(gomock.Any(), &models.ListTodosDTO{UserID: 1}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *models.ListTodosDTO, send func(todo *models.TodoDTO) error) error {
+				if err := send(first); err != nil {
+					return err
+				}
+				return send(second)
+			})
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 1).Return(&models.UserDTO{ID: 1, Username: "jane"}, nil)
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.UserDTO{ID: 2, Username: "john"}, nil)
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 3).Return(nil, appErrors.ErrNotFound)
+
+		exported := make([]*models.TodoExportDTO, 0)
+		err := svc.ExportTodos(contextWithUser(1, models.RoleUser), &models.ListTodosDTO{}, func(todo *models.TodoExportDTO) error {
+			exported = append(exported, todo)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*models.TodoExportDTO{
+			{ID: first.ID, Description: "first", CreatedBy: "jane", Assignee: "john"},
+			{ID: second.ID, Description: "second", CreatedBy: "john", Assignee: ""},
+		}, exported)
+	})
+
+	t.Run("ForeignWorkspace", func(t *testing.T) {
+		workspaceID := uuid.New()
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 1).Return(nil, appErrors.ErrNotFound)
+
+		err := svc.ExportTodos(contextWithUser(1, models.RoleUser), &models.ListTodosDTO{WorkspaceID: &workspaceID},
+			func(todo *models.TodoExportDTO) error { return nil })
+		require.Error(t, err)
+	})
+}
+
+func TestTodoService_ImportTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	t.Run("AssigneesByUsername", func(t *testing.T) {
+		request := &models.ImportTodosDTO{Rows: []*models.ImportTodoRowDTO{
+			{Row: 2, Description: "mine"},
+			{Row: 3, Description: "for john", Assignee: "john", Done: true},
+		}}
+		firstID, secondID := uuid.New(), uuid.New()
+		mocks.UsersServiceClient.EXPECT().GetUserByUsername(gomock.Any(), "john").Return(&models.UserDTO{ID: 2, Username: "john"}, nil)
+		mocks.TodoServiceClient.EXPECT().ImportTodos(gomock.Any(), &models.ImportTodosRequestDTO{
+			CreatedBy: 1,
+			Rows: []*models.ImportTodoDTO{
+				{Row: 2, Todo: &models.CreateTodoDTO{CreatedBy: 1, Assignee: 1, Description: "mine"}},
+				{Row: 3, Todo: &models.CreateTodoDTO{CreatedBy: 1, Assignee: 2, Description: "for john"}, Done: true},
+			},
+		}).Return(&models.ImportTodosResultDTO{
+			Rows:     []models.ImportRowResultDTO{{Row: 2, ID: &firstID}, {Row: 3, ID: &secondID}},
+			Imported: 2,
+		}, nil)
+
+		result, err := svc.ImportTodos(contextWithUser(1, models.RoleUser), request)
+		require.NoError(t, err)
+		require.Equal(t, &models.ImportTodosResultDTO{
+			Rows:     []models.ImportRowResultDTO{{Row: 2, ID: &firstID}, {Row: 3, ID: &secondID}},
+			Imported: 2,
+		}, result)
+	})
+
+	t.Run("UnknownAssigneeCancelsImport", func(t *testing.T) {
+		request := &models.ImportTodosDTO{Rows: []*models.ImportTodoRowDTO{
+			{Row: 1, Description: "mine"},
+			{Row: 2, Description: "for nobody", Assignee: "nobody"},
+			{Row: 3, Description: "bad date", Errors: []validator.FieldViolation{{Field: "due_at", Message: "must be an RFC 3339 time"}}},
+		}}
+		mocks.UsersServiceClient.EXPECT().GetUserByUsername(gomock.Any(), "nobody").Return(nil, appErrors.ErrNotFound)
+		mocks.TodoServiceClient.EXPECT().ImportTodos(gomock.Any(), &models.ImportTodosRequestDTO{
+			CreatedBy: 1,
+			DryRun:    true,
+			Rows: []*models.ImportTodoDTO{
+				{Row: 1, Todo: &models.CreateTodoDTO{CreatedBy: 1, Assignee: 1, Description: "mine"}},
+			},
+		}).Return(&models.ImportTodosResultDTO{Rows: []models.ImportRowResultDTO{{Row: 1}}}, nil)
+
+		result, err := svc.ImportTodos(contextWithUser(1, models.RoleUser), request)
+		require.NoError(t, err)
+		require.Equal(t, &models.ImportTodosResultDTO{
+			Rows: []models.ImportRowResultDTO{
+				{Row: 1},
+				{Row: 2, Errors: []validator.FieldViolation{{Field: "assignee", Message: "user does not exist"}}},
+				{Row: 3, Errors: []validator.FieldViolation{{Field: "due_at", Message: "must be an RFC 3339 time"}}},
+			},
+			Failed: 2,
+		}, result)
+	})
+
+	t.Run("ViewerCannotImportToWorkspace", func(t *testing.T) {
+		workspaceID := uuid.New()
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 1).
+			Return(&models.WorkspaceMemberDTO{WorkspaceID: workspaceID, UserID: 1, Role: models.WorkspaceRoleViewer}, nil)
+
+		_, err := svc.ImportTodos(contextWithUser(1, models.RoleUser), &models.ImportTodosDTO{
+			WorkspaceID: &workspaceID,
+			Rows:        []*models.ImportTodoRowDTO{{Row: 1, Description: "shared"}},
+		})
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("NoRows", func(t *testing.T) {
+		_, err := svc.ImportTodos(contextWithUser(1, models.RoleUser), &models.ImportTodosDTO{})
+		requireValidationError(t, err)
+	})
+}
+
+func TestTodoService_Comments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	todo := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 2}
+	commentID := uuid.New()
+	comment := &models.CommentRequestDTO{Body: "comment"}
+
+	t.Run("AssigneeComments", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		mocks.TodoServiceClient.EXPECT().AddComment(gomock.Any(), todo.ID, 2, "comment").Return(&models.CommentDTO{ID: commentID}, nil)
+
+		created, err := svc.AddComment(contextWithUser(2, models.RoleUser), todo.ID, comment)
+		require.NoError(t, err)
+		require.Equal(t, commentID, created.ID)
+	})
+
+	t.Run("StrangerCannotComment", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+
+		_, err := svc.AddComment(contextWithUser(3, models.RoleUser), todo.ID, comment)
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("EmptyComment", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+
+		_, err := svc.AddComment(contextWithUser(1, models.RoleUser), todo.ID, &models.CommentRequestDTO{Body: " "})
+		requireValidationError(t, err)
+	})
+
+	t.Run("EditByOtherUserForbidden", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		mocks.TodoServiceClient.EXPECT().EditComment(gomock.Any(), todo.ID, commentID, 1, "comment").Return(nil, appErrors.ErrForbidden)
+
+		_, err := svc.EditComment(contextWithUser(1, models.RoleUser), todo.ID, commentID, comment)
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("CreatorModeratesComments", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		mocks.TodoServiceClient.EXPECT().DeleteComment(gomock.Any(), todo.ID, commentID, 1, true).Return(nil)
+
+		require.NoError(t, svc.DeleteComment(contextWithUser(1, models.RoleUser), todo.ID, commentID))
+	})
+
+	t.Run("AssigneeDeletesOnlyOwnComments", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		mocks.TodoServiceClient.EXPECT().DeleteComment(gomock.Any(), todo.ID, commentID, 2, false).Return(nil)
+
+		require.NoError(t, svc.DeleteComment(contextWithUser(2, models.RoleUser), todo.ID, commentID))
+	})
+
+	t.Run("ListNotFound", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(nil, appErrors.ErrNotFound)
+
+		_, err := svc.ListComments(contextWithUser(1, models.RoleUser), todo.ID, 50, 0)
+		requireEqualError(t, err, appErrors.ErrNotFound)
+	})
+}
+
+func TestTodoService_ListTodoHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	todo := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 2}
+
+	t.Run("AssigneeReadsHistory", func(t *testing.T) {
+		history := &models.TodoEventListDTO{
+			Events: []*models.TodoEventDTO{{TodoID: todo.ID, Actor: 1, Field: "assignee", OldValue: "1", NewValue: "2"}},
+			Total:  1,
+		}
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		mocks.TodoServiceClient.EXPECT().ListTodoHistory(gomock.Any(), todo.ID, 50, 0).Return(history, nil)
+
+		res, err := svc.ListTodoHistory(contextWithUser(2, models.RoleUser), todo.ID, 50, 0)
+		require.NoError(t, err)
+		require.Equal(t, history, res)
+	})
+
+	t.Run("StrangerForbidden", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+
+		_, err := svc.ListTodoHistory(contextWithUser(3, models.RoleUser), todo.ID, 50, 0)
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+}
+
+func TestTodoService_Labels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	todo := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 2}
+	labelID := uuid.New()
+	workspaceID := uuid.New()
+	workspaceLabel := &models.LabelDTO{ID: uuid.New(), Owner: 1, WorkspaceID: &workspaceID}
+
+	t.Run("CreateOwnedByCaller", func(t *testing.T) {
+		label := &models.LabelRequestDTO{Name: "urgent", Color: "#ff0000"}
+		mocks.TodoServiceClient.EXPECT().CreateLabel(gomock.Any(), &models.CreateLabelDTO{Owner: 1, LabelRequestDTO: label}).
+			Return(&models.LabelDTO{ID: labelID, Owner: 1}, nil)
+
+		created, err := svc.CreateLabel(contextWithUser(1, models.RoleUser), nil, label)
+		require.NoError(t, err)
+		require.Equal(t, labelID, created.ID)
+	})
+
+	t.Run("CreateInvalidColor", func(t *testing.T) {
+		_, err := svc.CreateLabel(contextWithUser(1, models.RoleUser), nil, &models.LabelRequestDTO{Name: "urgent", Color: "red"})
+		requireValidationError(t, err)
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		label := &models.LabelRequestDTO{Name: "Urgent", Color: "#ff0000"}
+		mocks.TodoServiceClient.EXPECT().CreateLabel(gomock.Any(), gomock.Any()).Return(nil, appErrors.ErrAlreadyExists)
+
+		_, err := svc.CreateLabel(contextWithUser(1, models.RoleUser), nil, label)
+		requireEqualError(t, err, appErrors.ErrAlreadyExists)
+	})
+
+	t.Run("CreateInWorkspaceByEditor", func(t *testing.T) {
+		label := &models.LabelRequestDTO{Name: "release", Color: "#00ff00"}
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 2).
+			Return(&models.WorkspaceMemberDTO{UserID: 2, Role: models.WorkspaceRoleEditor}, nil)
+		mocks.TodoServiceClient.EXPECT().CreateLabel(gomock.Any(), &models.CreateLabelDTO{Owner: 2, WorkspaceID: &workspaceID, LabelRequestDTO: label}).
+			Return(workspaceLabel, nil)
+
+		created, err := svc.CreateLabel(contextWithUser(2, models.RoleUser), &workspaceID, label)
+		require.NoError(t, err)
+		require.Equal(t, &workspaceID, created.WorkspaceID)
+	})
+
+	t.Run("CreateInWorkspaceByViewer", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 3).
+			Return(&models.WorkspaceMemberDTO{UserID: 3, Role: models.WorkspaceRoleViewer}, nil)
+
+		_, err := svc.CreateLabel(contextWithUser(3, models.RoleUser), &workspaceID, &models.LabelRequestDTO{Name: "release", Color: "#00ff00"})
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("ListWorkspaceLabelsByMember", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 3).
+			Return(&models.WorkspaceMemberDTO{UserID: 3, Role: models.WorkspaceRoleViewer}, nil)
+		mocks.TodoServiceClient.EXPECT().ListLabels(gomock.Any(), &models.ListLabelsDTO{UserID: 3, WorkspaceID: &workspaceID}).
+			Return([]*models.LabelDTO{workspaceLabel}, nil)
+
+		labels, err := svc.ListLabels(contextWithUser(3, models.RoleUser), &workspaceID)
+		require.NoError(t, err)
+		require.Len(t, labels, 1)
+	})
+
+	t.Run("ListWorkspaceLabelsByStranger", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 4).Return(nil, appErrors.ErrNotFound)
+
+		_, err := svc.ListLabels(contextWithUser(4, models.RoleUser), &workspaceID)
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("UpdateWorkspaceLabelByViewer", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetLabel(gomock.Any(), workspaceLabel.ID).Return(workspaceLabel, nil)
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 3).
+			Return(&models.WorkspaceMemberDTO{UserID: 3, Role: models.WorkspaceRoleViewer}, nil)
+
+		_, err := svc.UpdateLabel(contextWithUser(3, models.RoleUser), workspaceLabel.ID, &models.LabelRequestDTO{Name: "hotfix", Color: "#00ff00"})
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("DeleteWorkspaceLabelByStranger", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetLabel(gomock.Any(), workspaceLabel.ID).Return(workspaceLabel, nil)
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 4).Return(nil, appErrors.ErrNotFound)
+
+		err := svc.DeleteLabel(contextWithUser(4, models.RoleUser), workspaceLabel.ID)
+		requireEqualError(t, err, appErrors.ErrNotFound)
+	})
+
+	t.Run("DeleteWorkspaceLabelByEditor", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetLabel(gomock.Any(), workspaceLabel.ID).Return(workspaceLabel, nil)
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 2).
+			Return(&models.WorkspaceMemberDTO{UserID: 2, Role: models.WorkspaceRoleEditor}, nil)
+		mocks.TodoServiceClient.EXPECT().DeleteLabel(gomock.Any(), workspaceLabel.ID, 2).Return(nil)
+
+		require.NoError(t, svc.DeleteLabel(contextWithUser(2, models.RoleUser), workspaceLabel.ID))
+	})
+
+	t.Run("DeleteForeignPersonalLabel", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetLabel(gomock.Any(), labelID).Return(&models.LabelDTO{ID: labelID, Owner: 1}, nil)
+
+		err := svc.DeleteLabel(contextWithUser(2, models.RoleUser), labelID)
+		requireEqualError(t, err, appErrors.ErrNotFound)
+	})
+
+	t.Run("AssigneeLabelsTodo", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		mocks.TodoServiceClient.EXPECT().AddTodoLabel(gomock.Any(), todo.ID, labelID, 2).Return(nil)
+
+		require.NoError(t, svc.AddTodoLabel(contextWithUser(2, models.RoleUser), todo.ID, labelID))
+	})
+
+	t.Run("StrangerCannotLabelTodo", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+
+		err := svc.RemoveTodoLabel(contextWithUser(3, models.RoleUser), todo.ID, labelID)
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("ListScopedToCaller", func(t *testing.T) {
+		filter := &models.ListTodosDTO{UserID: 7, LabelIDs: []uuid.UUID{labelID}, LabelMatch: models.LabelMatchAll}
+		mocks.TodoServiceClient.EXPECT().ListToDos(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f *models.ListTodosDTO) ([]*models.TodoDTO, error) {
+			require.Equal(t, 2, f.UserID)
+			return []*models.TodoDTO{todo}, nil
+		})
+
+		todos, err := svc.ListToDos(contextWithUser(2, models.RoleUser), filter)
+		require.NoError(t, err)
+		require.Len(t, todos, 1)
+	})
+
+	t.Run("ListInvalidMatch", func(t *testing.T) {
+		_, err := svc.ListToDos(contextWithUser(2, models.RoleUser), &models.ListTodosDTO{LabelMatch: "some"})
+		requireValidationError(t, err)
+	})
+}
+
+func TestTodoService_SearchTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	t.Run("ScopedToCaller", func(t *testing.T) {
+		request := &models.SearchTodosDTO{UserID: 7, Query: "  deploy  ", Limit: 20}
+		mocks.TodoServiceClient.EXPECT().SearchTodos(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r *models.SearchTodosDTO) ([]*models.SearchResultDTO, error) {
+			require.Equal(t, 2, r.UserID)
+			require.Equal(t, "deploy", r.Query)
+			return []*models.SearchResultDTO{{Todo: &models.TodoDTO{ID: uuid.New()}, Rank: 0.1}}, nil
+		})
+
+		results, err := svc.SearchTodos(contextWithUser(2, models.RoleUser), request)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+	})
+
+	t.Run("EmptyQuery", func(t *testing.T) {
+		_, err := svc.SearchTodos(contextWithUser(2, models.RoleUser), &models.SearchTodosDTO{Query: "   "})
+		requireValidationError(t, err)
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		_, err := svc.SearchTodos(context.Background(), &models.SearchTodosDTO{Query: "deploy"})
+		require.Error(t, err)
+	})
+}
+
+func TestTodoService_Subtasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	parent := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 2}
+	foreign := &models.TodoDTO{ID: uuid.New(), CreatedBy: 5, Assignee: 5}
+
+	t.Run("CreateSubtaskOfForeignTodo", func(t *testing.T) {
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 3).Return(&models.UserDTO{ID: 3}, nil)
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), foreign.ID).Return(foreign, nil)
+
+		_, err := svc.CreateToDo(contextWithUser(3, models.RoleUser), &models.CreateTodoDTO{
+			Assignee: 3, Description: "subtask", ParentID: &foreign.ID,
+		})
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("CreateSubtaskOfMissingTodo", func(t *testing.T) {
+		missingID := uuid.New()
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.UserDTO{ID: 2}, nil)
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), missingID).Return(nil, appErrors.ErrNotFound)
+
+		_, err := svc.CreateToDo(contextWithUser(2, models.RoleUser), &models.CreateTodoDTO{
+			Assignee: 2, Description: "subtask", ParentID: &missingID,
+		})
+		requireValidationError(t, err)
+	})
+
+	t.Run("AssigneeAddsSubtask", func(t *testing.T) {
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.UserDTO{ID: 2}, nil)
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), parent.ID).Return(parent, nil)
+		mocks.TodoServiceClient.EXPECT().CreateToDo(gomock.Any(), gomock.Any()).Return(uuid.New(), nil)
+
+		_, err := svc.CreateToDo(contextWithUser(2, models.RoleUser), &models.CreateTodoDTO{
+			Assignee: 2, Description: "subtask", ParentID: &parent.ID,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("BlockedByUnreadableTodo", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), parent.ID).Return(parent, nil)
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), foreign.ID).Return(foreign, nil)
+
+		err := svc.AddTodoDependency(contextWithUser(1, models.RoleUser), parent.ID, foreign.ID)
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("CompleteBlockedTodo", func(t *testing.T) {
+		update := &models.UpdateTodoDTO{ID: parent.ID, Assignee: 2, Description: "parent", Done: true}
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), parent.ID).Return(parent, nil)
+		mocks.TodoServiceClient.EXPECT().UpdateToDo(gomock.Any(), update).Return(uuid.Nil, appErrors.ErrTodoBlocked)
+
+		_, err := svc.UpdateToDo(contextWithUser(2, models.RoleUser), update)
+		requireEqualError(t, err, appErrors.ErrTodoBlocked)
+	})
+
+	t.Run("TreeScopedToCaller", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().ListTodoTree(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f *models.ListTodosDTO) ([]*models.TodoNodeDTO, error) {
+			require.Equal(t, 2, f.UserID)
+			return []*models.TodoNodeDTO{{TodoDTO: *parent}}, nil
+		})
+
+		tree, err := svc.ListTodoTree(contextWithUser(2, models.RoleUser), &models.ListTodosDTO{UserID: 9})
+		require.NoError(t, err)
+		require.Len(t, tree, 1)
+	})
+}
+
+func TestTodoService_Workspaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	workspaceID := uuid.New()
+	todo := &models.TodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 2, WorkspaceID: &workspaceID}
+	commentID := uuid.New()
+
+	member := func(userID int, role string) {
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, userID).
+			Return(&models.WorkspaceMemberDTO{WorkspaceID: workspaceID, UserID: userID, Role: role}, nil)
+	}
+	stranger := func(userID int) {
+		mocks.TodoServiceClient.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, userID).
+			Return(nil, appErrors.ErrNotFound)
+	}
+
+	t.Run("ViewerReadsTodo", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		member(3, models.WorkspaceRoleViewer)
+
+		got, err := svc.GetToDo(contextWithUser(3, models.RoleUser), todo.ID)
+		require.NoError(t, err)
+		require.Equal(t, todo.ID, got.ID)
+	})
+
+	t.Run("ViewerCannotUpdate", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		member(3, models.WorkspaceRoleViewer)
+
+		_, err := svc.UpdateToDo(contextWithUser(3, models.RoleUser), &models.UpdateTodoDTO{
+			ID: todo.ID, Assignee: 2, Description: "changed",
+		})
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("FormerMemberCannotRead", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		stranger(2)
+
+		_, err := svc.GetToDo(contextWithUser(2, models.RoleUser), todo.ID)
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("EditorReassignsToMember", func(t *testing.T) {
+		update := &models.UpdateTodoDTO{ID: todo.ID, Assignee: 3, Description: "changed"}
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		member(4, models.WorkspaceRoleEditor)
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 3).Return(&models.UserDTO{ID: 3}, nil)
+		member(3, models.WorkspaceRoleViewer)
+		mocks.TodoServiceClient.EXPECT().UpdateToDo(gomock.Any(), update).Return(todo.ID, nil)
+
+		_, err := svc.UpdateToDo(contextWithUser(4, models.RoleUser), update)
+		require.NoError(t, err)
+	})
+
+	t.Run("AssigneeOutsideWorkspace", func(t *testing.T) {
+		member(1, models.WorkspaceRoleOwner)
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 5).Return(&models.UserDTO{ID: 5}, nil)
+		stranger(5)
+
+		_, err := svc.CreateToDo(contextWithUser(1, models.RoleUser), &models.CreateTodoDTO{
+			Assignee: 5, Description: "todo", WorkspaceID: &workspaceID,
+		})
+		violation := requireValidationError(t, err)
+		require.Equal(t, "assignee", violation.Violations[0].Field)
+	})
+
+	t.Run("ViewerCannotCreate", func(t *testing.T) {
+		mocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 3).Return(&models.UserDTO{ID: 3}, nil)
+		member(3, models.WorkspaceRoleViewer)
+
+		_, err := svc.CreateToDo(contextWithUser(3, models.RoleUser), &models.CreateTodoDTO{
+			Assignee: 3, Description: "todo", WorkspaceID: &workspaceID,
+		})
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("OwnerModeratesComments", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().GetToDo(gomock.Any(), todo.ID).Return(todo, nil)
+		member(6, models.WorkspaceRoleOwner)
+		mocks.TodoServiceClient.EXPECT().DeleteComment(gomock.Any(), todo.ID, commentID, 6, true).Return(nil)
+
+		require.NoError(t, svc.DeleteComment(contextWithUser(6, models.RoleUser), todo.ID, commentID))
+	})
+
+	t.Run("ListForeignWorkspace", func(t *testing.T) {
+		stranger(5)
+
+		_, err := svc.ListToDos(contextWithUser(5, models.RoleUser), &models.ListTodosDTO{WorkspaceID: &workspaceID})
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("EditorCannotInvite", func(t *testing.T) {
+		member(4, models.WorkspaceRoleEditor)
+
+		_, err := svc.InviteWorkspaceMember(contextWithUser(4, models.RoleUser), workspaceID,
+			&models.InviteWorkspaceMemberRequestDTO{Email: "friend@example.com", Role: models.WorkspaceRoleViewer})
+		requireEqualError(t, err, appErrors.ErrForbidden)
+	})
+
+	t.Run("MemberLeaves", func(t *testing.T) {
+		mocks.TodoServiceClient.EXPECT().RemoveWorkspaceMember(gomock.Any(), workspaceID, 3).Return(nil)
 
+		require.NoError(t, svc.RemoveWorkspaceMember(contextWithUser(3, models.RoleUser), workspaceID, 3))
+	})
 }
@@ -2,128 +2,257 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"gateway/internal/app_errors"
 	"gateway/internal/models"
+	"gateway/internal/policy"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
-	"time"
 )
 
-func (s *GatewayService) CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (int, error) {
+func (s *GatewayService) CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (uuid.UUID, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateToDo")
 	defer span.Finish()
 
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// если автор не указан, им становится отправитель запроса
+	if newTodo.CreatedBy == 0 {
+		newTodo.CreatedBy = subject.UserID
+	}
+
+	if !subject.CanCreateTodoFor(newTodo.CreatedBy) {
+		return uuid.Nil, fmt.Errorf("[CreateToDo] %w", app_errors.ErrForbidden)
+	}
+
+	if err := s.validator.Validate(newTodo); err != nil {
+		return uuid.Nil, fmt.Errorf("[CreateToDo] validate:%w", err)
+	}
+
+	if err := s.checkAssigneeExists(ctx, newTodo.Assignee); err != nil {
+		return uuid.Nil, fmt.Errorf("[CreateToDo] check assignee:%w", err)
+	}
+
+	if newTodo.WorkspaceID != nil {
+		if err := s.checkWorkspaceTodoCreate(ctx, subject, newTodo); err != nil {
+			return uuid.Nil, fmt.Errorf("[CreateToDo] %w", err)
+		}
+	}
+
+	if newTodo.ParentID != nil {
+		if err := s.checkParentAccess(ctx, subject, *newTodo.ParentID); err != nil {
+			return uuid.Nil, fmt.Errorf("[CreateToDo] check parent:%w", err)
+		}
+	}
+
 	todoID, err := s.todoServiceClient.CreateToDo(ctx, newTodo)
 	if err != nil {
-		return 0, fmt.Errorf("[CreateToDo] store user:%w", err)
+		return uuid.Nil, fmt.Errorf("[CreateToDo] store todo:%w", err)
 	}
 
 	return todoID, nil
 }
 
-func (s *GatewayService) UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (int, error) {
+func (s *GatewayService) UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (uuid.UUID, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateTodo")
 	defer span.Finish()
 
-	todoID, err := s.todoServiceClient.UpdateToDo(ctx, updateTodo)
+	subject, err := policy.SubjectFromContext(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("[UpdateToDo] update todo:%w", err)
+		return uuid.Nil, err
 	}
 
-	if err = s.UpdateTodo(ctx, updateTodo, time.Minute*30); err != nil {
-		return 0, fmt.Errorf("[UpdateToDo] update todo:%w", err)
+	if err := s.validator.Validate(updateTodo); err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] validate:%w", err)
+	}
+
+	existingTodo, err := s.todoServiceClient.GetToDo(ctx, updateTodo.ID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] get todo:%w", err)
+	}
+
+	role, err := s.workspaceRole(ctx, subject, existingTodo.WorkspaceID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] %w", err)
+	}
+
+	if !subject.CanUpdateTodo(existingTodo, role) {
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] %w", app_errors.ErrForbidden)
+	}
+
+	// клиент менял устаревшую версию задачи, сервис задач проверит версию еще раз при сохранении
+	if existingTodo.Version != updateTodo.Version {
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] %w", app_errors.ErrVersionConflict)
+	}
+
+	// исполнитель может менять задачу, но не может передать ее другому пользователю
+	if updateTodo.Assignee != existingTodo.Assignee {
+		if !subject.CanReassignTodo(existingTodo, role) {
+			return uuid.Nil, fmt.Errorf("[UpdateToDo] reassign: %w", app_errors.ErrForbidden)
+		}
+
+		if err := s.checkAssigneeExists(ctx, updateTodo.Assignee); err != nil {
+			return uuid.Nil, fmt.Errorf("[UpdateToDo] check assignee:%w", err)
+		}
+
+		if existingTodo.WorkspaceID != nil {
+			if err := s.checkAssigneeIsMember(ctx, *existingTodo.WorkspaceID, updateTodo.Assignee); err != nil {
+				return uuid.Nil, fmt.Errorf("[UpdateToDo] check assignee:%w", err)
+			}
+		}
+	}
+
+	if updateTodo.ParentID != nil && (existingTodo.ParentID == nil || *existingTodo.ParentID != *updateTodo.ParentID) {
+		if err := s.checkParentAccess(ctx, subject, *updateTodo.ParentID); err != nil {
+			return uuid.Nil, fmt.Errorf("[UpdateToDo] check parent:%w", err)
+		}
+	}
+
+	updateTodo.UpdatedBy = subject.UserID
+
+	todoID, err := s.todoServiceClient.UpdateToDo(ctx, updateTodo)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] update todo:%w", err)
 	}
 
 	return todoID, nil
 }
 
-func (s *GatewayService) GetToDos(ctx context.Context, todoID int) (*models.TodoDTO, error) {
+func (s *GatewayService) GetToDos(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetToDos")
 	defer span.Finish()
 
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	todo, err := s.todoServiceClient.GetToDos(ctx, todoID)
 	if err != nil {
-		return nil, fmt.Errorf("[GetToDo] get todo:%w", err)
+		return nil, fmt.Errorf("[GetToDos] get todos:%w", err)
 	}
 
-	if err := s.GetTodo(ctx, todoID); err != nil {
-		return nil, fmt.Errorf("[GetToDos] get todo:%w", err)
+	role, err := s.workspaceRole(ctx, subject, todo.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetToDos] %w", err)
+	}
+
+	if !subject.CanReadTodo(todo, role) {
+		return nil, fmt.Errorf("[GetToDos] %w", app_errors.ErrForbidden)
 	}
 
 	return todo, nil
 }
 
-func (s *GatewayService) GetToDo(ctx context.Context, todoID int) (*models.TodoDTO, error) {
+func (s *GatewayService) GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetTodo")
 	defer span.Finish()
 
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	todo, err := s.todoServiceClient.GetToDo(ctx, todoID)
 	if err != nil {
 		return nil, fmt.Errorf("[GetToDo] get todo:%w", err)
 	}
 
-	if err := s.GetTodo(ctx, todoID); err != nil {
-		return nil, fmt.Errorf("[GetToDo] get todo:%w", err)
+	role, err := s.workspaceRole(ctx, subject, todo.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetToDo] %w", err)
+	}
+
+	if !subject.CanReadTodo(todo, role) {
+		return nil, fmt.Errorf("[GetToDo] %w", app_errors.ErrForbidden)
 	}
 
 	return todo, nil
 }
 
-func (s *GatewayService) DeleteToDo(ctx context.Context, todoID int) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteToDo")
+// ListToDos возвращает личные задачи отправителя запроса: созданные им и назначенные на него,
+// или задачи рабочего пространства из фильтра, если отправитель в нем состоит
+func (s *GatewayService) ListToDos(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListToDos")
 	defer span.Finish()
 
-	err := s.todoServiceClient.DeleteToDo(ctx, todoID)
+	subject, err := policy.SubjectFromContext(ctx)
 	if err != nil {
-		return fmt.Errorf("[DeleteToDo] delete todo:%w", err)
+		return nil, err
 	}
 
-	if err = s.DeleteTodo(ctx, todoID); err != nil {
-		return fmt.Errorf("[DeleteToDo] delete todo:%w", err)
+	if err := s.validator.Validate(filter); err != nil {
+		return nil, fmt.Errorf("[ListToDos] validate:%w", err)
 	}
 
-	return nil
-}
+	if err := s.checkWorkspaceRead(ctx, subject, filter.WorkspaceID); err != nil {
+		return nil, fmt.Errorf("[ListToDos] %w", err)
+	}
 
-func (s *GatewayService) SetTodo(ctx context.Context, todo *models.TodoDTO, ttl time.Duration) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateToDo")
-	defer span.Finish()
+	filter.UserID = subject.UserID
 
-	if _, err := s.todoServiceClient.CreateToDo(ctx, (*models.CreateTodoDTO)(todo)); err != nil {
-		return fmt.Errorf("[CreateToDo] store user:%w", err)
-	} else {
-		return nil
+	todos, err := s.todoServiceClient.ListToDos(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("[ListToDos] list todos:%w", err)
 	}
+
+	return todos, nil
 }
 
-func (s *GatewayService) GetTodo(ctx context.Context, todoID int) *models.TodoDTO {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetTodo")
+// DeleteToDo удаляет задачу, если ее версия совпадает с версией из If-Match
+func (s *GatewayService) DeleteToDo(ctx context.Context, todoID uuid.UUID, version int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteToDo")
 	defer span.Finish()
 
-	if todo, err := s.todoServiceClient.GetToDo(ctx, todoID); err != nil {
-		return nil
-	} else {
-		return todo
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	todo, err := s.todoServiceClient.GetToDo(ctx, todoID)
+	if err != nil {
+		return fmt.Errorf("[DeleteToDo] get todo:%w", err)
 	}
-}
 
-func (s *GatewayService) DeleteTodo(ctx context.Context, todoID int) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteToDo")
-	defer span.Finish()
+	role, err := s.workspaceRole(ctx, subject, todo.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("[DeleteToDo] %w", err)
+	}
+
+	if !subject.CanDeleteTodo(todo, role) {
+		return fmt.Errorf("[DeleteToDo] %w", app_errors.ErrForbidden)
+	}
 
-	if err := s.todoServiceClient.DeleteToDo(ctx, todoID); err != nil {
+	if todo.Version != version {
+		return fmt.Errorf("[DeleteToDo] %w", app_errors.ErrVersionConflict)
+	}
+
+	err = s.todoServiceClient.DeleteToDo(ctx, todoID, version)
+	if err != nil {
 		return fmt.Errorf("[DeleteToDo] delete todo:%w", err)
-	} else {
-		return nil
 	}
+
+	return nil
 }
 
-func (s *GatewayService) UpdateTodo(ctx context.Context, updateTodo *models.UpdateTodoDTO, ttl time.Duration) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateTodo")
-	defer span.Finish()
+// checkAssigneeExists проверяет, что назначенный на задачу пользователь существует
+func (s *GatewayService) checkAssigneeExists(ctx context.Context, assignee int) error {
+	_, err := s.usersServiceClient.GetUserByID(ctx, assignee)
+	if err != nil {
+		if errors.Is(err, app_errors.ErrNotFound) {
+			return validator.NewValidationError(validator.FieldViolation{
+				Field:   "assignee",
+				Message: "user does not exist",
+			})
+		}
 
-	if _, err := s.todoServiceClient.UpdateToDo(ctx, updateTodo); err != nil {
-		return fmt.Errorf("[UpdateToDo] update todo:%w", err)
-	} else {
-		return nil
+		return fmt.Errorf("[checkAssigneeExists] get user:%w", err)
 	}
+
+	return nil
 }
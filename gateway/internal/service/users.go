@@ -2,17 +2,26 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"gateway/internal/app_errors"
 	"gateway/internal/models"
+	"gateway/internal/policy"
 	"gateway/pkg/ctxutil"
+	"gateway/pkg/jwtutil"
+	"gateway/pkg/redis"
 	"github.com/opentracing/opentracing-go"
+	"time"
 )
 
 func (s *GatewayService) RegisterUser(ctx context.Context, newUser *models.CreateUserDTO) (int, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RegisterUser")
 	defer span.Finish()
 
+	if err := s.validator.Validate(newUser); err != nil {
+		return 0, fmt.Errorf("[RegisterUser] validate:%w", err)
+	}
+
 	// Передаем данные в слой репозитория для сохранения пользователя.
 	userID, err := s.usersServiceClient.CreateUser(ctx, newUser)
 	if err != nil {
@@ -27,23 +36,28 @@ func (s *GatewayService) UpdateUser(ctx context.Context, updatedUser *models.Use
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateUser")
 	defer span.Finish()
 
-	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
-	if !ok {
-		return nil, app_errors.ErrNoUserInContext
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// изменять данные может сам пользователь или администратор
+	if !subject.CanManageUser(updatedUser.ID) {
+		return nil, app_errors.NewUserIDMismatchError("UpdateUser", subject.UserID, updatedUser.ID)
 	}
 
-	if senderID != updatedUser.ID {
-		return nil, app_errors.NewUserIDMismatchError("UpdateUser", senderID, updatedUser.ID)
+	if err := s.validator.Validate(updatedUser); err != nil {
+		return nil, fmt.Errorf("[UpdateUser] validate:%w", err)
 	}
 
 	// Передаем данные в слой репозитория
-	err := s.usersServiceClient.UpdateUser(ctx, updatedUser)
+	storedUser, err := s.usersServiceClient.UpdateUser(ctx, updatedUser)
 	if err != nil {
 		return nil, fmt.Errorf("[UpdateUser] update user:%w", err)
 	}
 
 	// возвращаем данные в слой хэндлера
-	return updatedUser, nil
+	return storedUser, nil
 }
 
 func (s *GatewayService) UpdatePassword(ctx context.Context, request *models.UpdateUserPasswordDTO) error {
@@ -59,6 +73,10 @@ func (s *GatewayService) UpdatePassword(ctx context.Context, request *models.Upd
 		return app_errors.NewUserIDMismatchError("UpdatePassword", senderID, request.ID)
 	}
 
+	if err := s.validator.Validate(request); err != nil {
+		return fmt.Errorf("[UpdatePassword] validate:%w", err)
+	}
+
 	// Обновление пароля в базе данных
 	err := s.usersServiceClient.UpdatePassword(ctx, request)
 	if err != nil {
@@ -69,26 +87,33 @@ func (s *GatewayService) UpdatePassword(ctx context.Context, request *models.Upd
 	return nil
 }
 
-func (s *GatewayService) DeleteUser(ctx context.Context, userID int) error {
+func (s *GatewayService) DeleteUser(ctx context.Context, userID, version int) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteUser")
 	defer span.Finish()
 
-	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
-	if !ok {
-		return app_errors.ErrNoUserInContext
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if senderID != userID {
-		return app_errors.NewUserIDMismatchError("DeleteUser", senderID, userID)
+	// удалить пользователя может он сам или администратор
+	if !subject.CanManageUser(userID) {
+		return nil, app_errors.NewUserIDMismatchError("DeleteUser", subject.UserID, userID)
 	}
 
-	// Удаление пользователя.
-	err := s.usersServiceClient.DeleteUser(ctx, userID)
+	// Пользователь помечается на удаление, данные обезличиваются после льготного периода.
+	deleted, err := s.usersServiceClient.DeleteUser(ctx, userID, version)
 	if err != nil {
-		return fmt.Errorf("[DeleteUser] delete user:%w", err)
+		return nil, fmt.Errorf("[DeleteUser] delete user:%w", err)
 	}
 
-	return nil
+	// до входа в учетную запись заново все сессии завершаются
+	err = s.redisManager.InvalidateTokensForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteUser] invalidate tokens:%w", err)
+	}
+
+	return deleted, nil
 }
 
 func (s *GatewayService) GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error) {
@@ -109,114 +134,262 @@ func (s *GatewayService) Login(ctx context.Context, login *models.UserLoginDTO)
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.Login")
 	defer span.Finish()
 
+	if err := s.validator.Validate(login); err != nil {
+		return nil, fmt.Errorf("[Login] validate:%w", err)
+	}
+
+	// при переборе паролей вход блокируется еще до дорогой проверки пароля в сервисе пользователей
+	client, _ := ctxutil.GetClientInfoFromContext(ctx)
+	retryAfter, err := s.loginGuard.Check(ctx, loginName(login), client.IP)
+	if err != nil {
+		return nil, fmt.Errorf("[Login] check lock:%w", err)
+	}
+	if retryAfter > 0 {
+		return nil, fmt.Errorf("[Login] %w", &app_errors.LoginLockedError{RetryAfter: retryAfter})
+	}
+
 	// Проверка наличия пользователя.
 	existingUser, err := s.usersServiceClient.UserLogin(ctx, login)
 	if err != nil {
+		if errors.Is(err, app_errors.ErrWrongCredentials) {
+			if guardErr := s.registerLoginFailure(ctx, login, client.IP); guardErr != nil {
+				return nil, fmt.Errorf("[Login] %s: %w", guardErr, err)
+			}
+		}
 		return nil, fmt.Errorf("[Login] log in: %w", err)
 	}
 
-	// Генерируем токены и возвращаем
-	accessToken, accessExp, err := s.jwtUtil.GenerateAccessToken(existingUser.ID)
+	// при включенной MFA токены выдаются только после проверки второго фактора,
+	// и неудачные попытки сбросит LoginMFA
+	if existingUser.MFAEnabled {
+		challenge, err := s.issueMFAChallenge(ctx, existingUser.ID, login)
+		if err != nil {
+			return nil, fmt.Errorf("[Login] %w", err)
+		}
+
+		return challenge, nil
+	}
+
+	err = s.loginGuard.RegisterSuccess(ctx, loginName(login))
 	if err != nil {
-		return nil, fmt.Errorf("[Login] generate access token:%w", err)
+		return nil, fmt.Errorf("[Login] reset failures:%w", err)
 	}
 
-	refreshToken, refreshExp, err := s.jwtUtil.GenerateRefreshToken(existingUser.ID)
+	tokens, err := s.startSession(ctx, existingUser)
 	if err != nil {
-		return nil, fmt.Errorf("[Login] generate refresh token:%w", err)
+		return nil, fmt.Errorf("[Login] %w", err)
 	}
 
-	err = s.redisManager.StoreToken(ctx, existingUser.ID, accessToken, accessExp, s.jwtUtil.AccessTokenExp)
+	return tokens, nil
+}
+
+// Refresh обменивает refresh токен на новую пару токенов того же семейства.
+// Повторное предъявление уже обменянного refresh токена отзывает все семейство.
+func (s *GatewayService) Refresh(ctx context.Context, refresh string) (*models.UserTokens, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.Refresh")
+	defer span.Finish()
+
+	refreshClaims, err := s.jwtUtil.VerifyRefreshToken(refresh)
 	if err != nil {
-		return nil, fmt.Errorf("[Login] cache access token:%w", err)
+		return nil, fmt.Errorf("[Refresh] verify refresh token:%s: %w", err, app_errors.ErrInvalidToken)
 	}
 
-	err = s.redisManager.StoreToken(ctx, existingUser.ID, refreshToken, refreshExp, s.jwtUtil.RefreshTokenExp)
+	// роль могла измениться с момента логина, поэтому берем актуальную
+	user, err := s.usersServiceClient.GetUserByID(ctx, refreshClaims.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("[Login] cache refresh token:%w", err)
+		return nil, fmt.Errorf("[Refresh] get user:%w", err)
 	}
 
-	return &models.UserTokens{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	tokens, err := s.generateTokens(user, refreshClaims.FamilyID)
+	if err != nil {
+		return nil, fmt.Errorf("[Refresh] %w", err)
+	}
+
+	err = s.redisManager.RotateRefreshToken(ctx, refreshClaims.FamilyID, refreshClaims.ID, tokens.refresh.ID, tokens.refresh.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, redis.ErrTokenReused) {
+			return nil, fmt.Errorf("[Refresh] family %s: %w", refreshClaims.FamilyID, app_errors.ErrTokenReused)
+		}
+		if errors.Is(err, redis.ErrTokenRevoked) {
+			return nil, fmt.Errorf("[Refresh] %s: %w", err, app_errors.ErrInvalidToken)
+		}
+		return nil, fmt.Errorf("[Refresh] rotate refresh token:%w", err)
+	}
+
+	if err := s.storeTokens(ctx, tokens); err != nil {
+		return nil, fmt.Errorf("[Refresh] %w", err)
+	}
+
+	return tokens.UserTokens, nil
 }
 
-func (s *GatewayService) Refresh(ctx context.Context, refresh, access string) (*models.UserTokens, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "service.Refresh")
+func (s *GatewayService) InvalidateTokensForUser(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.InvalidateTokensForUser")
 	defer span.Finish()
 
-	userID, oldRefreshExp, err := s.jwtUtil.VerifyToken(refresh)
+	subject, err := policy.SubjectFromContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] verify token:%w", err)
+		return err
+	}
+
+	// отозвать все токены может сам пользователь или администратор
+	if !subject.CanManageUser(userID) {
+		return app_errors.NewUserIDMismatchError("InvalidateTokensForUser", subject.UserID, userID)
 	}
 
-	// Генерируем токены и возвращаем
-	accessToken, accessExp, err := s.jwtUtil.GenerateAccessToken(userID)
+	return s.redisManager.InvalidateTokensForUser(ctx, userID)
+}
+
+func (s *GatewayService) InvalidateToken(ctx context.Context, userID int, access, refresh string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.InvalidateToken")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] generate access token:%w", err)
+		return err
 	}
 
-	refreshToken, refreshExp, err := s.jwtUtil.GenerateRefreshToken(userID)
+	if !subject.CanManageUser(userID) {
+		return app_errors.NewUserIDMismatchError("InvalidateToken", subject.UserID, userID)
+	}
+
+	accessClaims, err := s.jwtUtil.VerifyAccessToken(access)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] generate refresh token:%w", err)
+		return fmt.Errorf("[InvalidateToken] verify access:%s: %w", err, app_errors.ErrInvalidToken)
 	}
 
-	err = s.redisManager.StoreToken(ctx, userID, accessToken, accessExp, s.jwtUtil.AccessTokenExp)
+	refreshClaims, err := s.jwtUtil.VerifyRefreshToken(refresh)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] cache access token:%w", err)
+		return fmt.Errorf("[InvalidateToken] verify refresh:%s: %w", err, app_errors.ErrInvalidToken)
 	}
 
-	err = s.redisManager.StoreToken(ctx, userID, refreshToken, refreshExp, s.jwtUtil.RefreshTokenExp)
+	// токены должны принадлежать пользователю из запроса
+	if accessClaims.UserID != userID || refreshClaims.UserID != userID {
+		return app_errors.NewUserIDMismatchError("InvalidateToken", userID, accessClaims.UserID)
+	}
+
+	err = s.redisManager.InvalidateToken(ctx, userID, accessClaims.ID)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] cache refresh token:%w", err)
+		return fmt.Errorf("[InvalidateToken] invalidate access: %w", err)
 	}
 
-	userID, oldAccessExp, err := s.jwtUtil.VerifyToken(access)
+	// вместе с refresh токеном отзываем всю сессию, чтобы ее нельзя было обновить
+	err = s.redisManager.RevokeSession(ctx, refreshClaims.FamilyID)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] verify access token:%w", err)
+		return fmt.Errorf("[InvalidateToken] revoke session: %w", err)
 	}
 
-	err = s.redisManager.InvalidateToken(ctx, userID, oldAccessExp)
+	return nil
+}
+
+// GetJWKS возвращает публичные ключи для проверки токенов другими сервисами
+func (s *GatewayService) GetJWKS(ctx context.Context) jwtutil.JSONWebKeySet {
+	span, _ := opentracing.StartSpanFromContext(ctx, "service.GetJWKS")
+	defer span.Finish()
+
+	return s.jwtUtil.JWKS()
+}
+
+// issuedTokens - выпущенная пара токенов вместе с их claims
+type issuedTokens struct {
+	*models.UserTokens
+	access  *jwtutil.Claims
+	refresh *jwtutil.Claims
+}
+
+// generateTokens выпускает access и refresh токены семейства familyID
+func (s *GatewayService) generateTokens(user *models.UserDTO, familyID string) (*issuedTokens, error) {
+	accessToken, accessClaims, err := s.jwtUtil.GenerateAccessToken(user.ID, userRole(user), familyID)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] invalidate access token:%w", err)
+		return nil, fmt.Errorf("generate access token:%w", err)
 	}
 
-	err = s.redisManager.InvalidateToken(ctx, userID, oldRefreshExp)
+	refreshToken, refreshClaims, err := s.jwtUtil.GenerateRefreshToken(user.ID, familyID)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] invalidate refresh token:%w", err)
+		return nil, fmt.Errorf("generate refresh token:%w", err)
 	}
 
-	return &models.UserTokens{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	return &issuedTokens{
+		UserTokens: &models.UserTokens{
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		},
+		access:  accessClaims,
+		refresh: refreshClaims,
 	}, nil
 }
 
-func (s *GatewayService) InvalidateTokensForUser(ctx context.Context, userID int) error {
-	return s.redisManager.InvalidateTokensForUser(ctx, userID)
-}
+// startSession создает сессию с новым семейством токенов и выдает первую пару токенов
+func (s *GatewayService) startSession(ctx context.Context, user *models.UserDTO) (*models.UserTokens, error) {
+	familyID := jwtutil.NewFamilyID()
 
-func (s *GatewayService) InvalidateToken(ctx context.Context, userID int, access, refresh string) error {
-	userID, accessExp, err := s.jwtUtil.VerifyToken(access)
+	tokens, err := s.generateTokens(user, familyID)
 	if err != nil {
-		return fmt.Errorf("[InvalidateToken] verify access: %w", err)
+		return nil, err
 	}
 
-	userID, refreshExp, err := s.jwtUtil.VerifyToken(refresh)
+	err = s.redisManager.CreateSession(ctx, newSession(ctx, user.ID, familyID), tokens.refresh.ID, tokens.refresh.ExpiresAt)
 	if err != nil {
-		return fmt.Errorf("[InvalidateToken] verify refresh: %w", err)
+		return nil, fmt.Errorf("create session:%w", err)
+	}
+
+	if err := s.storeTokens(ctx, tokens); err != nil {
+		return nil, err
 	}
 
-	err = s.redisManager.InvalidateToken(ctx, userID, accessExp)
+	return tokens.UserTokens, nil
+}
+
+// storeTokens сохраняет выпущенные токены, после этого они проходят проверку в middleware
+func (s *GatewayService) storeTokens(ctx context.Context, tokens *issuedTokens) error {
+	for _, claims := range []*jwtutil.Claims{tokens.access, tokens.refresh} {
+		err := s.redisManager.StoreToken(ctx, claims.UserID, claims.ID, claims.FamilyID, claims.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("cache %s token:%w", claims.Type, err)
+		}
+	}
+
+	return nil
+}
+
+// registerLoginFailure учитывает неудачный вход и сообщает пользователю о блокировке аккаунта
+func (s *GatewayService) registerLoginFailure(ctx context.Context, login *models.UserLoginDTO, ip string) error {
+	failure, err := s.loginGuard.RegisterFailure(ctx, loginName(login), ip)
 	if err != nil {
-		return fmt.Errorf("[InvalidateToken] invalidate access: %w", err)
+		return fmt.Errorf("register failure:%w", err)
+	}
+
+	if !failure.AccountLocked {
+		return nil
 	}
 
-	err = s.redisManager.InvalidateToken(ctx, userID, refreshExp)
+	err = s.usersServiceClient.ReportAccountLocked(ctx, &models.AccountLockedDTO{
+		Username:    login.Username,
+		Email:       login.Email,
+		IP:          ip,
+		LockedUntil: time.Now().Add(failure.RetryAfter),
+	})
 	if err != nil {
-		return fmt.Errorf("[InvalidateToken] invalidate refresh: %w", err)
+		return fmt.Errorf("report account locked:%w", err)
 	}
 
 	return nil
 }
+
+// loginName - имя или email, по которому выполняется вход
+func loginName(login *models.UserLoginDTO) string {
+	if login.Username != "" {
+		return login.Username
+	}
+
+	return login.Email
+}
+
+// userRole возвращает роль пользователя; у пользователей без роли она считается обычной
+func userRole(user *models.UserDTO) string {
+	if user.Role == "" {
+		return models.RoleUser
+	}
+
+	return user.Role
+}
@@ -86,7 +86,7 @@ func BenchmarkDeleteUser(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = service.DeleteUser(context.Background(), userID)
+		_, _ = service.DeleteUser(context.Background(), userID, 1)
 	}
 }
 
@@ -149,10 +149,9 @@ func BenchmarkRefresh(b *testing.B) {
 
 	// Тестовый refresh token.
 	refreshToken := "example_refresh_token"
-	accessToken := "example_refresh_token"
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = service.Refresh(context.Background(), refreshToken, accessToken)
+		_, _ = service.Refresh(context.Background(), refreshToken)
 	}
 }
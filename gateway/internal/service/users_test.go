@@ -1,16 +1,37 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	appErrors "gateway/internal/app_errors"
 	"gateway/internal/models"
 	"gateway/pkg/ctxutil"
+	"gateway/pkg/jwtutil"
+	"gateway/pkg/oidcauth/oidctest"
+	"gateway/pkg/validator"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/png"
 	"testing"
+	"time"
 )
 
 func TestRegisterUser(t *testing.T) {
 	userID := 1
-	userToCreate := models.CreateUserDTO{}
+	userToCreate := models.CreateUserDTO{
+		Username:             "username",
+		Password:             "Passw0rd",
+		PasswordConfirmation: "Passw0rd",
+		Email:                "user@example.com",
+	}
+	weakPassword := models.CreateUserDTO{
+		Username:             "username",
+		Password:             "password",
+		PasswordConfirmation: "password",
+		Email:                "user@example.com",
+	}
 	tests := []struct {
 		name     string
 		userData *models.CreateUserDTO
@@ -24,6 +45,11 @@ func TestRegisterUser(t *testing.T) {
 			},
 			nil,
 		},
+		{"weak password",
+			&weakPassword,
+			func(m *Mocks) {},
+			&validator.ValidationError{},
+		},
 	}
 
 	for _, test := range tests {
@@ -35,12 +61,16 @@ func TestRegisterUser(t *testing.T) {
 			_, err := svc.RegisterUser(context.Background(), test.userData)
 
 			requireEqualError(t, err, test.wantErr)
+			if test.wantErr != nil {
+				validationErr := requireValidationError(t, err)
+				require.Equal(t, "password", validationErr.Violations[0].Field)
+			}
 		})
 	}
 }
 
 func TestUpdateUser(t *testing.T) {
-	updatedUser := &models.UserDTO{ID: 1}
+	updatedUser := &models.UserDTO{ID: 1, Username: "username"}
 	tests := []struct {
 		name     string
 		userData *models.UserDTO
@@ -51,10 +81,22 @@ func TestUpdateUser(t *testing.T) {
 			"success",
 			updatedUser,
 			func(m *Mocks) {
-				m.UsersServiceClient.EXPECT().UpdateUser(gomock.Any(), updatedUser).Return(nil)
+				m.UsersServiceClient.EXPECT().UpdateUser(gomock.Any(), updatedUser).Return(updatedUser, nil)
 			},
 			nil,
 		},
+		{
+			"invalid timezone",
+			&models.UserDTO{ID: 1, Username: "username", Timezone: "Mars/Olympus"},
+			func(m *Mocks) {},
+			&validator.ValidationError{},
+		},
+		{
+			"invalid locale",
+			&models.UserDTO{ID: 1, Username: "username", Locale: "not a locale"},
+			func(m *Mocks) {},
+			&validator.ValidationError{},
+		},
 	}
 
 	for _, test := range tests {
@@ -73,7 +115,12 @@ func TestUpdateUser(t *testing.T) {
 }
 
 func TestUpdatePassword(t *testing.T) {
-	request := &models.UpdateUserPasswordDTO{ID: 1}
+	request := &models.UpdateUserPasswordDTO{
+		ID:                   1,
+		OldPassword:          "password",
+		Password:             "Passw0rd",
+		PasswordConfirmation: "Passw0rd",
+	}
 	tests := []struct {
 		name    string
 		request *models.UpdateUserPasswordDTO
@@ -117,10 +164,26 @@ func TestDeleteUser(t *testing.T) {
 			"success",
 			userID,
 			func(m *Mocks) {
-				m.UsersServiceClient.EXPECT().DeleteUser(gomock.Any(), userID).Return(nil)
+				deleteAfter := time.Now().Add(720 * time.Hour)
+				m.UsersServiceClient.EXPECT().DeleteUser(gomock.Any(), userID, 2).
+					Return(&models.UserDTO{ID: userID, Status: "pending_deletion", DeleteAfter: &deleteAfter, Version: 3}, nil)
 			},
 			nil,
 		},
+		{
+			"version conflict",
+			userID,
+			func(m *Mocks) {
+				m.UsersServiceClient.EXPECT().DeleteUser(gomock.Any(), userID, 2).Return(nil, appErrors.ErrVersionConflict)
+			},
+			appErrors.ErrVersionConflict,
+		},
+		{
+			"another user",
+			2,
+			func(m *Mocks) {},
+			appErrors.ErrForbidden,
+		},
 	}
 
 	for _, test := range tests {
@@ -131,9 +194,562 @@ func TestDeleteUser(t *testing.T) {
 			ctx := ctxutil.SetUserIDToContext(context.Background(), 1)
 
 			test.setup(svcMocks)
-			err := svc.DeleteUser(ctx, test.userID)
+			_, err := svc.DeleteUser(ctx, test.userID, 2)
 
 			requireEqualError(t, err, test.wantErr)
 		})
 	}
 }
+
+func TestRefresh(t *testing.T) {
+	user := &models.UserDTO{ID: 1, Username: "username", Role: models.RoleUser}
+
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+	ctx := context.Background()
+
+	svcMocks.UsersServiceClient.EXPECT().UserLogin(gomock.Any(), gomock.Any()).Return(user, nil)
+	svcMocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil).AnyTimes()
+
+	loginTokens, err := svc.Login(ctx, &models.UserLoginDTO{Username: "username", Password: "Passw0rd"})
+	require.NoError(t, err)
+
+	// access токен нельзя использовать вместо refresh
+	_, err = svc.Refresh(ctx, loginTokens.AccessToken)
+	requireEqualError(t, err, appErrors.ErrInvalidToken)
+
+	refreshedTokens, err := svc.Refresh(ctx, loginTokens.RefreshToken)
+	require.NoError(t, err)
+
+	// после обновления старый access токен больше не действует, новый - действует
+	oldAccess, err := svc.jwtUtil.VerifyAccessToken(loginTokens.AccessToken)
+	require.NoError(t, err)
+	require.False(t, svc.redisManager.IsTokenValid(ctx, oldAccess.ID))
+
+	newAccess, err := svc.jwtUtil.VerifyAccessToken(refreshedTokens.AccessToken)
+	require.NoError(t, err)
+	require.True(t, svc.redisManager.IsTokenValid(ctx, newAccess.ID))
+
+	// повторное использование обменянного refresh токена отзывает все семейство
+	_, err = svc.Refresh(ctx, loginTokens.RefreshToken)
+	requireEqualError(t, err, appErrors.ErrTokenReused)
+	require.False(t, svc.redisManager.IsTokenValid(ctx, newAccess.ID))
+
+	_, err = svc.Refresh(ctx, refreshedTokens.RefreshToken)
+	requireEqualError(t, err, appErrors.ErrInvalidToken)
+}
+
+func TestSessions(t *testing.T) {
+	user := &models.UserDTO{ID: 1, Username: "username", Role: models.RoleUser}
+
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+
+	svcMocks.UsersServiceClient.EXPECT().UserLogin(gomock.Any(), gomock.Any()).Return(user, nil).Times(2)
+
+	login := func(device string) *jwtutil.Claims {
+		ctx := ctxutil.SetClientInfoToContext(context.Background(), ctxutil.ClientInfo{Device: device, IP: "203.0.113.10"})
+		tokens, err := svc.Login(ctx, &models.UserLoginDTO{Username: "username", Password: "Passw0rd"})
+		require.NoError(t, err)
+
+		claims, err := svc.jwtUtil.VerifyAccessToken(tokens.AccessToken)
+		require.NoError(t, err)
+
+		return claims
+	}
+
+	phone := login("phone")
+	laptop := login("laptop")
+
+	ctx := ctxutil.SetSessionIDToContext(contextWithUser(user.ID, models.RoleUser), laptop.FamilyID)
+
+	sessions, err := svc.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	for _, session := range sessions {
+		require.Equal(t, session.ID == laptop.FamilyID, session.Current)
+		require.Equal(t, "203.0.113.10", session.IP)
+	}
+
+	// чужую сессию отозвать нельзя
+	err = svc.RevokeSession(contextWithUser(2, models.RoleUser), phone.FamilyID)
+	requireEqualError(t, err, appErrors.ErrNotFound)
+
+	err = svc.RevokeSession(ctx, phone.FamilyID)
+	require.NoError(t, err)
+	require.False(t, svc.redisManager.IsTokenValid(ctx, phone.ID))
+	require.True(t, svc.redisManager.IsTokenValid(ctx, laptop.ID))
+
+	sessions, err = svc.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, "laptop", sessions[0].Device)
+
+	// выход на всех устройствах удаляет и токены, и сессии
+	err = svc.InvalidateTokensForUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.False(t, svc.redisManager.IsTokenValid(ctx, laptop.ID))
+
+	sessions, err = svc.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
+
+func TestLoginMFA(t *testing.T) {
+	user := &models.UserDTO{ID: 1, Username: "username", Role: models.RoleUser, MFAEnabled: true}
+	login := &models.UserLoginDTO{Username: "username", Password: "Passw0rd"}
+
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+	ctx := context.Background()
+
+	svcMocks.UsersServiceClient.EXPECT().UserLogin(gomock.Any(), gomock.Any()).Return(user, nil).Times(2)
+
+	// при включенной MFA логин возвращает только challenge токен
+	challenge, err := svc.Login(ctx, login)
+	require.NoError(t, err)
+	require.True(t, challenge.MFARequired)
+	require.NotEmpty(t, challenge.MFAToken)
+	require.Empty(t, challenge.AccessToken)
+	require.Empty(t, challenge.RefreshToken)
+
+	// challenge токен не дает доступа к API
+	_, err = svc.jwtUtil.VerifyAccessToken(challenge.MFAToken)
+	require.ErrorIs(t, err, jwtutil.ErrWrongTokenType)
+
+	wrongCode := &models.MFACodeDTO{ID: user.ID, Code: "000000"}
+	rightCode := &models.MFACodeDTO{ID: user.ID, Code: "123456"}
+	svcMocks.UsersServiceClient.EXPECT().VerifyMFA(gomock.Any(), wrongCode).Return(nil, appErrors.ErrInvalidMFACode).AnyTimes()
+	svcMocks.UsersServiceClient.EXPECT().VerifyMFA(gomock.Any(), rightCode).Return(user, nil).AnyTimes()
+
+	_, err = svc.LoginMFA(ctx, &models.MFALoginDTO{MFAToken: challenge.MFAToken, Code: wrongCode.Code})
+	requireEqualError(t, err, appErrors.ErrInvalidMFACode)
+
+	tokens, err := svc.LoginMFA(ctx, &models.MFALoginDTO{MFAToken: challenge.MFAToken, Code: rightCode.Code})
+	require.NoError(t, err)
+
+	access, err := svc.jwtUtil.VerifyAccessToken(tokens.AccessToken)
+	require.NoError(t, err)
+	require.True(t, svc.redisManager.IsTokenValid(ctx, access.ID))
+
+	// challenge одноразовый
+	_, err = svc.LoginMFA(ctx, &models.MFALoginDTO{MFAToken: challenge.MFAToken, Code: rightCode.Code})
+	requireEqualError(t, err, appErrors.ErrInvalidToken)
+
+	// после исчерпания попыток challenge отзывается даже для верного кода
+	challenge, err = svc.Login(ctx, login)
+	require.NoError(t, err)
+
+	for i := 0; i < maxMFAAttempts; i++ {
+		_, err = svc.LoginMFA(ctx, &models.MFALoginDTO{MFAToken: challenge.MFAToken, Code: wrongCode.Code})
+		requireEqualError(t, err, appErrors.ErrInvalidMFACode)
+		// блокировка аккаунта проверяется в TestLoginMFALockout, здесь - только лимит попыток challenge
+		require.NoError(t, svc.loginGuard.Unlock(ctx, login.Username))
+	}
+
+	_, err = svc.LoginMFA(ctx, &models.MFALoginDTO{MFAToken: challenge.MFAToken, Code: rightCode.Code})
+	requireEqualError(t, err, appErrors.ErrInvalidToken)
+}
+
+func TestLoginMFALockout(t *testing.T) {
+	user := &models.UserDTO{ID: 1, Username: "username", Role: models.RoleUser, MFAEnabled: true}
+	login := &models.UserLoginDTO{Username: "username", Password: "Passw0rd"}
+
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+	ctx := ctxutil.SetClientInfoToContext(context.Background(), ctxutil.ClientInfo{IP: "203.0.113.10"})
+
+	wrongCode := &models.MFACodeDTO{ID: user.ID, Code: "000000"}
+	rightCode := &models.MFACodeDTO{ID: user.ID, Code: "123456"}
+	svcMocks.UsersServiceClient.EXPECT().UserLogin(gomock.Any(), login).Return(user, nil).AnyTimes()
+	svcMocks.UsersServiceClient.EXPECT().VerifyMFA(gomock.Any(), wrongCode).Return(nil, appErrors.ErrInvalidMFACode).AnyTimes()
+	svcMocks.UsersServiceClient.EXPECT().VerifyMFA(gomock.Any(), rightCode).Return(user, nil).AnyTimes()
+
+	// верный пароль не сбрасывает неудачные попытки, каждый раз можно получить новый challenge
+	failCode := func() {
+		challenge, err := svc.Login(ctx, login)
+		require.NoError(t, err)
+		require.True(t, challenge.MFARequired)
+
+		_, err = svc.LoginMFA(ctx, &models.MFALoginDTO{MFAToken: challenge.MFAToken, Code: wrongCode.Code})
+		requireEqualError(t, err, appErrors.ErrInvalidMFACode)
+	}
+
+	failCode()
+	failCode()
+	failCode()
+
+	// неверные коды откладывают и вход по паролю
+	_, err := svc.Login(ctx, login)
+	require.ErrorIs(t, err, appErrors.ErrLoginLocked)
+	svcMocks.Redis.FastForward(time.Second)
+
+	failCode()
+	svcMocks.Redis.FastForward(2 * time.Second)
+
+	challenge, err := svc.Login(ctx, login)
+	require.NoError(t, err)
+
+	svcMocks.UsersServiceClient.EXPECT().ReportAccountLocked(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *models.AccountLockedDTO) error {
+			require.Equal(t, login.Username, event.Username)
+			return nil
+		})
+	_, err = svc.LoginMFA(ctx, &models.MFALoginDTO{MFAToken: challenge.MFAToken, Code: wrongCode.Code})
+	requireEqualError(t, err, appErrors.ErrInvalidMFACode)
+	svcMocks.Redis.FastForward(time.Minute)
+
+	// заблокированный аккаунт не пускают ни по паролю, ни с верным кодом по уже выданному challenge
+	_, err = svc.Login(ctx, login)
+	require.ErrorIs(t, err, appErrors.ErrLoginLocked)
+
+	_, err = svc.LoginMFA(ctx, &models.MFALoginDTO{MFAToken: challenge.MFAToken, Code: rightCode.Code})
+	require.ErrorIs(t, err, appErrors.ErrLoginLocked)
+}
+
+func TestLoginLockout(t *testing.T) {
+	user := &models.UserDTO{ID: 1, Username: "username", Email: "user@example.com", Role: models.RoleUser}
+	login := &models.UserLoginDTO{Username: "Username", Password: "wrong-password"}
+
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+	ctx := ctxutil.SetClientInfoToContext(context.Background(), ctxutil.ClientInfo{IP: "203.0.113.10"})
+
+	svcMocks.UsersServiceClient.EXPECT().UserLogin(gomock.Any(), login).Return(nil, appErrors.ErrWrongCredentials).Times(5)
+
+	failLogin := func() {
+		_, err := svc.Login(ctx, login)
+		requireEqualError(t, err, appErrors.ErrWrongCredentials)
+	}
+	requireLocked := func() time.Duration {
+		_, err := svc.Login(ctx, login)
+		var lockedErr *appErrors.LoginLockedError
+		require.ErrorAs(t, err, &lockedErr)
+		return lockedErr.RetryAfter
+	}
+
+	failLogin()
+	failLogin()
+	failLogin()
+
+	// после нескольких ошибок каждая следующая попытка откладывается, до сервиса пользователей запрос не доходит
+	require.LessOrEqual(t, requireLocked(), time.Second)
+	svcMocks.Redis.FastForward(time.Second)
+
+	failLogin()
+	require.LessOrEqual(t, requireLocked(), 2*time.Second)
+	svcMocks.Redis.FastForward(2 * time.Second)
+
+	// на последней попытке аккаунт блокируется и пользователь получает письмо
+	svcMocks.UsersServiceClient.EXPECT().ReportAccountLocked(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *models.AccountLockedDTO) error {
+			require.Equal(t, login.Username, event.Username)
+			require.Equal(t, "203.0.113.10", event.IP)
+			return nil
+		})
+	failLogin()
+	require.Greater(t, requireLocked(), time.Minute)
+
+	// логин не зависит от регистра
+	_, err := svc.Login(ctx, &models.UserLoginDTO{Username: "username", Password: "Passw0rd"})
+	require.ErrorIs(t, err, appErrors.ErrLoginLocked)
+
+	// снять блокировку может только администратор
+	err = svc.UnlockUser(contextWithUser(user.ID, models.RoleUser), user.ID)
+	requireEqualError(t, err, appErrors.ErrForbidden)
+
+	svcMocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	err = svc.UnlockUser(contextWithUser(2, models.RoleAdmin), user.ID)
+	require.NoError(t, err)
+
+	svcMocks.UsersServiceClient.EXPECT().UserLogin(gomock.Any(), gomock.Any()).Return(user, nil)
+	_, err = svc.Login(ctx, &models.UserLoginDTO{Username: "username", Password: "Passw0rd"})
+	require.NoError(t, err)
+}
+
+func TestOIDCLogin(t *testing.T) {
+	user := &models.UserDTO{ID: 1, Username: "username", Email: "user@example.com", Role: models.RoleUser}
+	idpUser := oidctest.User{Subject: "idp-1", Email: user.Email, EmailVerified: true, PreferredUsername: "username"}
+	identity := &models.ExternalIdentityDTO{
+		Provider:      "test",
+		Subject:       idpUser.Subject,
+		Email:         idpUser.Email,
+		EmailVerified: true,
+		Username:      idpUser.PreferredUsername,
+	}
+
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+	ctx := context.Background()
+	svcMocks.IdP.SetUser(idpUser)
+
+	authorize := func() (code, state string) {
+		login, err := svc.StartOIDCLogin(ctx, "test")
+		require.NoError(t, err)
+
+		code, state, err = svcMocks.IdP.Authorize(login.AuthURL)
+		require.NoError(t, err)
+		return code, state
+	}
+
+	_, err := svc.StartOIDCLogin(ctx, "unknown")
+	requireEqualError(t, err, appErrors.ErrNotFound)
+
+	// при первом входе учетная запись провайдера привязывается к пользователю
+	svcMocks.UsersServiceClient.EXPECT().GetUserByExternalIdentity(gomock.Any(), identity).Return(nil, appErrors.ErrNotFound)
+	svcMocks.UsersServiceClient.EXPECT().LinkExternalIdentity(gomock.Any(), identity).Return(user, nil)
+
+	code, state := authorize()
+	tokens, err := svc.CompleteOIDCLogin(ctx, "test", code, state)
+	require.NoError(t, err)
+
+	access, err := svc.jwtUtil.VerifyAccessToken(tokens.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, access.UserID)
+
+	// state одноразовый
+	_, err = svc.CompleteOIDCLogin(ctx, "test", code, state)
+	requireEqualError(t, err, appErrors.ErrInvalidToken)
+
+	// подделанный state не принимается
+	code, _ = authorize()
+	_, err = svc.CompleteOIDCLogin(ctx, "test", code, "forged-state")
+	requireEqualError(t, err, appErrors.ErrInvalidToken)
+
+	// пользователю с MFA нужен второй фактор
+	mfaUser := *user
+	mfaUser.MFAEnabled = true
+	svcMocks.UsersServiceClient.EXPECT().GetUserByExternalIdentity(gomock.Any(), identity).Return(&mfaUser, nil)
+
+	code, state = authorize()
+	challenge, err := svc.CompleteOIDCLogin(ctx, "test", code, state)
+	require.NoError(t, err)
+	require.True(t, challenge.MFARequired)
+	require.Empty(t, challenge.AccessToken)
+}
+
+func TestAPIKeys(t *testing.T) {
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+
+	ctx := contextWithUser(1, models.RoleUser)
+	apiKeyCtx := ctxutil.SetAPIKeyScopesToContext(ctx, []string{models.ScopeTodosRead})
+
+	// пользователь создает ключ для себя, ID берется из токена
+	request := &models.CreateAPIKeyDTO{UserID: 2, Name: "ci", Scopes: []string{models.ScopeTodosRead, models.ScopeTodosWrite}}
+	svcMocks.UsersServiceClient.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *models.CreateAPIKeyDTO) (*models.APIKeyDTO, error) {
+			require.Equal(t, 1, request.UserID)
+			return &models.APIKeyDTO{ID: 1, Name: request.Name, Scopes: request.Scopes, Key: "tdk_secret"}, nil
+		})
+
+	key, err := svc.CreateAPIKey(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "tdk_secret", key.Key)
+
+	_, err = svc.CreateAPIKey(ctx, &models.CreateAPIKeyDTO{Name: "ci", Scopes: []string{"admin"}})
+	requireValidationError(t, err)
+
+	past := time.Now().Add(-time.Hour)
+	_, err = svc.CreateAPIKey(ctx, &models.CreateAPIKeyDTO{Name: "ci", Scopes: []string{models.ScopeTodosRead}, ExpiresAt: &past})
+	requireValidationError(t, err)
+
+	// утекший ключ не может выпускать новые ключи и управлять существующими
+	_, err = svc.CreateAPIKey(apiKeyCtx, &models.CreateAPIKeyDTO{Name: "ci", Scopes: []string{models.ScopeTodosRead}})
+	requireEqualError(t, err, appErrors.ErrForbidden)
+
+	_, err = svc.ListAPIKeys(apiKeyCtx)
+	requireEqualError(t, err, appErrors.ErrForbidden)
+
+	err = svc.RevokeAPIKey(apiKeyCtx, 1)
+	requireEqualError(t, err, appErrors.ErrForbidden)
+
+	svcMocks.UsersServiceClient.EXPECT().RevokeAPIKey(gomock.Any(), 5, 1).Return(appErrors.ErrNotFound)
+	err = svc.RevokeAPIKey(ctx, 5)
+	requireEqualError(t, err, appErrors.ErrNotFound)
+}
+
+func TestEmailChange(t *testing.T) {
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+
+	ctx := contextWithUser(1, models.RoleUser)
+	apiKeyCtx := ctxutil.SetAPIKeyScopesToContext(ctx, []string{models.ScopeUsersRead})
+
+	// адрес меняется только у текущего пользователя, ID из тела запроса не используется
+	svcMocks.UsersServiceClient.EXPECT().RequestEmailChange(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *models.EmailChangeRequestDTO) error {
+			require.Equal(t, 1, request.UserID)
+			require.Equal(t, "new@example.com", request.NewEmail)
+			return nil
+		})
+	err := svc.RequestEmailChange(ctx, &models.EmailChangeRequestDTO{UserID: 2, NewEmail: "new@example.com"})
+	require.NoError(t, err)
+
+	err = svc.RequestEmailChange(ctx, &models.EmailChangeRequestDTO{NewEmail: "not-an-email"})
+	requireValidationError(t, err)
+
+	// API ключ не может перевести учетную запись на чужой адрес
+	err = svc.RequestEmailChange(apiKeyCtx, &models.EmailChangeRequestDTO{NewEmail: "new@example.com"})
+	requireEqualError(t, err, appErrors.ErrForbidden)
+
+	svcMocks.UsersServiceClient.EXPECT().RequestEmailChange(gomock.Any(), gomock.Any()).Return(appErrors.ErrUsernameOrEmailIsUsed)
+	err = svc.RequestEmailChange(ctx, &models.EmailChangeRequestDTO{NewEmail: "taken@example.com"})
+	requireEqualError(t, err, appErrors.ErrUsernameOrEmailIsUsed)
+
+	// подтверждение работает без входа в систему
+	svcMocks.UsersServiceClient.EXPECT().ConfirmEmailChange(gomock.Any(), "token").
+		Return(&models.UserDTO{ID: 1, Username: "user", Email: "new@example.com"}, nil)
+	user, err := svc.ConfirmEmailChange(context.Background(), "token")
+	require.NoError(t, err)
+	require.Equal(t, "new@example.com", user.Email)
+
+	svcMocks.UsersServiceClient.EXPECT().ConfirmEmailChange(gomock.Any(), "expired").Return(nil, appErrors.ErrNotFound)
+	_, err = svc.ConfirmEmailChange(context.Background(), "expired")
+	requireEqualError(t, err, appErrors.ErrNotFound)
+}
+
+func TestAccountLifecycle(t *testing.T) {
+	user := &models.UserDTO{ID: 1, Username: "username", Email: "user@example.com", Role: models.RoleUser}
+
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+
+	svcMocks.UsersServiceClient.EXPECT().UserLogin(gomock.Any(), gomock.Any()).Return(user, nil).Times(2)
+
+	login := func() *jwtutil.Claims {
+		tokens, err := svc.Login(context.Background(), &models.UserLoginDTO{Username: "username", Password: "Passw0rd"})
+		require.NoError(t, err)
+
+		claims, err := svc.jwtUtil.VerifyAccessToken(tokens.AccessToken)
+		require.NoError(t, err)
+
+		return claims
+	}
+
+	claims := login()
+	ctx := ctxutil.SetSessionIDToContext(contextWithUser(user.ID, models.RoleUser), claims.FamilyID)
+	apiKeyCtx := ctxutil.SetAPIKeyScopesToContext(ctx, []string{models.ScopeUsersRead})
+
+	// выгрузка собирает данные из обоих сервисов и сессии из redis
+	svcMocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	svcMocks.TodoServiceClient.EXPECT().GetUserToDos(gomock.Any(), user.ID).
+		Return([]*models.TodoDTO{{CreatedBy: user.ID, Assignee: user.ID, Description: "todo"}}, nil)
+	svcMocks.UsersServiceClient.EXPECT().ListAPIKeys(gomock.Any(), user.ID).Return([]*models.APIKeyDTO{{ID: 1, Name: "ci"}}, nil)
+
+	export, err := svc.ExportAccount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, user.Email, export.Profile.Email)
+	require.Len(t, export.Todos, 1)
+	require.Len(t, export.Sessions, 1)
+	require.True(t, export.Sessions[0].Current)
+	require.Len(t, export.APIKeys, 1)
+
+	// по API ключу выгрузить данные нельзя
+	_, err = svc.ExportAccount(apiKeyCtx)
+	requireEqualError(t, err, appErrors.ErrForbidden)
+
+	// чужую учетную запись отключить нельзя
+	err = svc.DeactivateUser(ctx, 2)
+	requireEqualError(t, err, appErrors.ErrForbidden)
+
+	// отключение завершает все сессии пользователя
+	svcMocks.UsersServiceClient.EXPECT().DeactivateUser(gomock.Any(), user.ID).Return(nil)
+	err = svc.DeactivateUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.False(t, svc.redisManager.IsTokenValid(ctx, claims.ID))
+
+	// удаление тоже завершает сессии, открытые после повторного входа
+	claims = login()
+	deleteAfter := time.Now().Add(720 * time.Hour)
+	svcMocks.UsersServiceClient.EXPECT().DeleteUser(gomock.Any(), user.ID, 1).
+		Return(&models.UserDTO{ID: user.ID, Status: "pending_deletion", DeleteAfter: &deleteAfter}, nil)
+
+	deleted, err := svc.DeleteUser(ctx, user.ID, 1)
+	require.NoError(t, err)
+	require.Equal(t, "pending_deletion", deleted.Status)
+	require.False(t, svc.redisManager.IsTokenValid(ctx, claims.ID))
+}
+
+func testImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestAvatar(t *testing.T) {
+	svcMocks := getMocks(gomock.NewController(t))
+	svc := buildTestService(svcMocks)
+
+	ctx := contextWithUser(1, models.RoleUser)
+
+	// сервис пользователей хранит только ключ аватара
+	var storedKey string
+	svcMocks.UsersServiceClient.EXPECT().UpdateAvatar(gomock.Any(), 1, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, avatarKey string) (string, error) {
+			previous := storedKey
+			storedKey = avatarKey
+			return previous, nil
+		}).AnyTimes()
+	svcMocks.UsersServiceClient.EXPECT().GetUserByID(gomock.Any(), 1).
+		DoAndReturn(func(_ context.Context, _ int) (*models.UserDTO, error) {
+			return models.NewEmptyUserDTO().FromGRPC(
+				(&models.UserDTO{ID: 1, Username: "username", AvatarKey: storedKey}).ToGRPC(),
+			), nil
+		}).AnyTimes()
+
+	user, err := svc.UploadAvatar(ctx, 1, bytes.NewReader(testImage(t, 120, 80)))
+	require.NoError(t, err)
+	require.NotEmpty(t, user.AvatarURL)
+	firstKey := storedKey
+
+	// миниатюры квадратные и нужного размера
+	for size, side := range map[string]int{"large": 32, "small": 8} {
+		object, err := svc.GetAvatar(ctx, 1, size)
+		require.NoError(t, err)
+		require.Equal(t, "image/png", object.ContentType)
+
+		thumbnail, err := png.Decode(object)
+		object.Close()
+		require.NoError(t, err)
+		require.Equal(t, image.Rect(0, 0, side, side), thumbnail.Bounds())
+	}
+
+	_, err = svc.GetAvatar(ctx, 1, "huge")
+	requireValidationError(t, err)
+
+	// новый аватар заменяет старый, файлы старого удаляются
+	_, err = svc.UploadAvatar(ctx, 1, bytes.NewReader(testImage(t, 40, 40)))
+	require.NoError(t, err)
+	require.NotEqual(t, firstKey, storedKey)
+	_, err = svcMocks.BlobStore.Get(ctx, firstKey+"/large.png")
+	require.Error(t, err)
+
+	// тип определяется по содержимому, размеры и объем файла ограничены
+	_, err = svc.UploadAvatar(ctx, 1, bytes.NewReader([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>")))
+	requireValidationError(t, err)
+
+	_, err = svc.UploadAvatar(ctx, 1, bytes.NewReader(testImage(t, 2000, 10)))
+	requireValidationError(t, err)
+
+	_, err = svc.UploadAvatar(ctx, 1, bytes.NewReader(make([]byte, 65*1024)))
+	requireValidationError(t, err)
+
+	// чужой аватар менять нельзя
+	_, err = svc.UploadAvatar(ctx, 2, bytes.NewReader(testImage(t, 40, 40)))
+	requireEqualError(t, err, appErrors.ErrForbidden)
+
+	err = svc.DeleteAvatar(ctx, 2)
+	requireEqualError(t, err, appErrors.ErrForbidden)
+
+	require.NoError(t, svc.DeleteAvatar(ctx, 1))
+	_, err = svc.GetAvatar(ctx, 1, "")
+	requireEqualError(t, err, appErrors.ErrNotFound)
+}
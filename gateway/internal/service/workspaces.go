@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/internal/policy"
+	"gateway/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// CreateWorkspace создает рабочее пространство, владельцем которого становится отправитель запроса
+func (s *GatewayService) CreateWorkspace(ctx context.Context, workspace *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateWorkspace")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(workspace); err != nil {
+		return nil, fmt.Errorf("[CreateWorkspace] validate:%w", err)
+	}
+
+	created, err := s.todoServiceClient.CreateWorkspace(ctx, subject.UserID, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateWorkspace] create workspace:%w", err)
+	}
+
+	return created, nil
+}
+
+func (s *GatewayService) UpdateWorkspace(ctx context.Context, workspaceID uuid.UUID, workspace *models.WorkspaceRequestDTO) (*models.WorkspaceDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateWorkspace")
+	defer span.Finish()
+
+	if err := s.validator.Validate(workspace); err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspace] validate:%w", err)
+	}
+
+	role, err := s.managedWorkspaceRole(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspace] %w", err)
+	}
+
+	updated, err := s.todoServiceClient.UpdateWorkspace(ctx, workspaceID, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspace] update workspace:%w", err)
+	}
+	updated.Role = role
+
+	return updated, nil
+}
+
+// DeleteWorkspace удаляет пространство вместе со всеми его задачами
+func (s *GatewayService) DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteWorkspace")
+	defer span.Finish()
+
+	if _, err := s.managedWorkspaceRole(ctx, workspaceID); err != nil {
+		return fmt.Errorf("[DeleteWorkspace] %w", err)
+	}
+
+	if err := s.todoServiceClient.DeleteWorkspace(ctx, workspaceID); err != nil {
+		return fmt.Errorf("[DeleteWorkspace] delete workspace:%w", err)
+	}
+
+	return nil
+}
+
+// ListWorkspaces возвращает пространства, в которых состоит отправитель запроса
+func (s *GatewayService) ListWorkspaces(ctx context.Context) ([]*models.WorkspaceDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListWorkspaces")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := s.todoServiceClient.ListWorkspaces(ctx, subject.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListWorkspaces] list workspaces:%w", err)
+	}
+
+	return workspaces, nil
+}
+
+func (s *GatewayService) ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListWorkspaceMembers")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkWorkspaceRead(ctx, subject, &workspaceID); err != nil {
+		return nil, fmt.Errorf("[ListWorkspaceMembers] %w", err)
+	}
+
+	members, err := s.todoServiceClient.ListWorkspaceMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListWorkspaceMembers] list members:%w", err)
+	}
+
+	return members, nil
+}
+
+// UpdateWorkspaceMember меняет роль участника. Последнего владельца сервис задач понизить не даст
+func (s *GatewayService) UpdateWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int, member *models.UpdateWorkspaceMemberRequestDTO) (*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateWorkspaceMember")
+	defer span.Finish()
+
+	if err := s.validator.Validate(member); err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspaceMember] validate:%w", err)
+	}
+
+	if _, err := s.managedWorkspaceRole(ctx, workspaceID); err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspaceMember] %w", err)
+	}
+
+	updated, err := s.todoServiceClient.UpdateWorkspaceMember(ctx, workspaceID, userID, member.Role)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspaceMember] update member:%w", err)
+	}
+
+	return updated, nil
+}
+
+// RemoveWorkspaceMember исключает участника. Выйти из пространства участник может сам,
+// исключить другого - только владелец
+func (s *GatewayService) RemoveWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveWorkspaceMember")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userID != subject.UserID {
+		if _, err := s.managedWorkspaceRole(ctx, workspaceID); err != nil {
+			return fmt.Errorf("[RemoveWorkspaceMember] %w", err)
+		}
+	}
+
+	if err := s.todoServiceClient.RemoveWorkspaceMember(ctx, workspaceID, userID); err != nil {
+		return fmt.Errorf("[RemoveWorkspaceMember] remove member:%w", err)
+	}
+
+	return nil
+}
+
+// InviteWorkspaceMember отправляет приглашение в пространство на email
+func (s *GatewayService) InviteWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, invitation *models.InviteWorkspaceMemberRequestDTO) (*models.WorkspaceInvitationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.InviteWorkspaceMember")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(invitation); err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] validate:%w", err)
+	}
+
+	if _, err := s.managedWorkspaceRole(ctx, workspaceID); err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] %w", err)
+	}
+
+	created, err := s.todoServiceClient.InviteWorkspaceMember(ctx, workspaceID, subject.UserID, invitation)
+	if err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] invite member:%w", err)
+	}
+
+	return created, nil
+}
+
+// AcceptWorkspaceInvitation добавляет отправителя запроса в пространство по токену из письма
+func (s *GatewayService) AcceptWorkspaceInvitation(ctx context.Context, accept *models.AcceptWorkspaceInvitationRequestDTO) (*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AcceptWorkspaceInvitation")
+	defer span.Finish()
+
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validator.Validate(accept); err != nil {
+		return nil, fmt.Errorf("[AcceptWorkspaceInvitation] validate:%w", err)
+	}
+
+	member, err := s.todoServiceClient.AcceptWorkspaceInvitation(ctx, accept.Token, subject.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[AcceptWorkspaceInvitation] accept invitation:%w", err)
+	}
+
+	return member, nil
+}
+
+// workspaceRole возвращает роль отправителя запроса в пространстве. Для личных задач
+// и пользователей, которые не состоят в пространстве, роль пустая
+func (s *GatewayService) workspaceRole(ctx context.Context, subject policy.Subject, workspaceID *uuid.UUID) (string, error) {
+	if workspaceID == nil {
+		return "", nil
+	}
+
+	member, err := s.todoServiceClient.GetWorkspaceMember(ctx, *workspaceID, subject.UserID)
+	if err != nil {
+		if errors.Is(err, app_errors.ErrNotFound) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("get workspace member:%w", err)
+	}
+
+	return member.Role, nil
+}
+
+// managedWorkspaceRole возвращает роль отправителя запроса, если он может управлять пространством
+func (s *GatewayService) managedWorkspaceRole(ctx context.Context, workspaceID uuid.UUID) (string, error) {
+	subject, err := policy.SubjectFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	role, err := s.workspaceRole(ctx, subject, &workspaceID)
+	if err != nil {
+		return "", err
+	}
+
+	if !subject.CanManageWorkspace(role) {
+		return "", app_errors.ErrForbidden
+	}
+
+	return role, nil
+}
+
+// checkWorkspaceRead проверяет, что отправитель запроса видит задачи пространства.
+// Пустой workspaceID означает личные задачи, которые видны всегда
+func (s *GatewayService) checkWorkspaceRead(ctx context.Context, subject policy.Subject, workspaceID *uuid.UUID) error {
+	role, err := s.workspaceRole(ctx, subject, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if workspaceID != nil && !subject.CanReadWorkspace(role) {
+		return app_errors.ErrForbidden
+	}
+
+	return nil
+}
+
+// checkWorkspaceTodoCreate - задачу в пространстве создают его владельцы и редакторы,
+// а назначить ее можно только на участника пространства
+func (s *GatewayService) checkWorkspaceTodoCreate(ctx context.Context, subject policy.Subject, newTodo *models.CreateTodoDTO) error {
+	role, err := s.workspaceRole(ctx, subject, newTodo.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	if !subject.CanCreateTodoIn(role) {
+		return app_errors.ErrForbidden
+	}
+
+	if err := s.checkAssigneeIsMember(ctx, *newTodo.WorkspaceID, newTodo.Assignee); err != nil {
+		return fmt.Errorf("check assignee:%w", err)
+	}
+
+	return nil
+}
+
+// checkAssigneeIsMember проверяет, что исполнитель задачи пространства состоит в нем
+func (s *GatewayService) checkAssigneeIsMember(ctx context.Context, workspaceID uuid.UUID, assignee int) error {
+	_, err := s.todoServiceClient.GetWorkspaceMember(ctx, workspaceID, assignee)
+	if err != nil {
+		if errors.Is(err, app_errors.ErrNotFound) {
+			return validator.NewValidationError(validator.FieldViolation{
+				Field:   "assignee",
+				Message: "user is not a member of the workspace",
+			})
+		}
+
+		return fmt.Errorf("get workspace member:%w", err)
+	}
+
+	return nil
+}
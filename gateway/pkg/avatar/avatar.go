@@ -0,0 +1,149 @@
+// Package avatar проверяет загруженные изображения и готовит из них квадратные аватары нужных размеров
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+
+	// декодеры поддерживаемых форматов регистрируются в image при импорте
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"golang.org/x/image/draw"
+)
+
+// размеры аватара
+const (
+	SizeLarge = "large"
+	SizeSmall = "small"
+)
+
+// ContentType - аватары всех размеров хранятся в PNG
+const ContentType = "image/png"
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooLarge        = errors.New("image is too large")
+)
+
+// supportedTypes - форматы, которые принимаются при загрузке
+var supportedTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+}
+
+type Config struct {
+	// MaxSize - максимальный размер загружаемого файла в байтах
+	MaxSize int64 `envconfig:"MAX_SIZE" default:"5242880"`
+	// MaxDimension - максимальная ширина и высота исходного изображения, защищает от декомпрессионных бомб
+	MaxDimension int `envconfig:"MAX_DIMENSION" default:"4096"`
+	LargeSize    int `envconfig:"LARGE_SIZE" default:"256"`
+	SmallSize    int `envconfig:"SMALL_SIZE" default:"64"`
+}
+
+// Image - готовый аватар одного размера
+type Image struct {
+	Size string
+	Data []byte
+}
+
+type Processor struct {
+	cfg *Config
+}
+
+func New(cfg *Config) *Processor {
+	return &Processor{cfg: cfg}
+}
+
+// MaxSize - лимит размера загружаемого файла
+func (p *Processor) MaxSize() int64 {
+	return p.cfg.MaxSize
+}
+
+// Sizes - размеры аватара в порядке от большего к меньшему
+func (p *Processor) Sizes() []string {
+	return []string{SizeLarge, SizeSmall}
+}
+
+// Process проверяет тип и размер изображения, обрезает его до квадрата по центру
+// и масштабирует до каждого размера аватара
+func (p *Processor) Process(r io.Reader) ([]Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, p.cfg.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read image: %w", err)
+	}
+	if int64(len(data)) > p.cfg.MaxSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, p.cfg.MaxSize)
+	}
+
+	// тип определяется по содержимому, заголовкам и расширению клиента не доверяем
+	contentType := http.DetectContentType(data)
+	if !supportedTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	// размеры читаются из заголовка до декодирования всего изображения
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, err)
+	}
+	if config.Width > p.cfg.MaxDimension || config.Height > p.cfg.MaxDimension {
+		return nil, fmt.Errorf("%w: %dx%d, max %dx%d", ErrTooLarge,
+			config.Width, config.Height, p.cfg.MaxDimension, p.cfg.MaxDimension)
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, err)
+	}
+
+	square := cropSquare(source)
+
+	pixels := map[string]int{SizeLarge: p.cfg.LargeSize, SizeSmall: p.cfg.SmallSize}
+	result := make([]Image, 0, len(pixels))
+	for _, size := range p.Sizes() {
+		encoded, err := encodePNG(resize(source, square, pixels[size]))
+		if err != nil {
+			return nil, fmt.Errorf("encode %s: %w", size, err)
+		}
+
+		result = append(result, Image{Size: size, Data: encoded})
+	}
+
+	return result, nil
+}
+
+// cropSquare - квадратная область по центру изображения, которая станет аватаром
+func cropSquare(src image.Image) image.Rectangle {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	return image.Rect(x, y, x+side, y+side)
+}
+
+func resize(src image.Image, rect image.Rectangle, side int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, side, side))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, rect, draw.Src, nil)
+	return dst
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,84 @@
+// Package blobstore - хранилище файлов пользователей (аватары и т.п.) с локальной и S3 реализацией
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// драйверы хранилища
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+type Config struct {
+	// Driver - local или s3
+	Driver string `envconfig:"DRIVER" default:"local"`
+	// Dir - каталог для драйвера local
+	Dir string   `envconfig:"DIR" default:"./data/blobs"`
+	S3  S3Config `envconfig:"S3"`
+}
+
+type S3Config struct {
+	// Endpoint - адрес без схемы, например s3.amazonaws.com или minio:9000
+	Endpoint  string `envconfig:"ENDPOINT"`
+	Bucket    string `envconfig:"BUCKET"`
+	AccessKey string `envconfig:"ACCESS_KEY"`
+	SecretKey string `envconfig:"SECRET_KEY"`
+	// Region задан явно, чтобы клиент не запрашивал расположение бакета
+	Region string `envconfig:"REGION" default:"us-east-1"`
+	UseSSL bool   `envconfig:"USE_SSL" default:"true"`
+}
+
+// Object - содержимое файла, читающий обязан закрыть его
+type Object struct {
+	io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// Store - хранилище файлов по ключам вида "avatars/1/abc/large.png"
+type Store interface {
+	// Put сохраняет файл, существующий файл с тем же ключом перезаписывается
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get возвращает файл или ErrNotFound
+	Get(ctx context.Context, key string) (*Object, error)
+	// Delete удаляет файл, отсутствие файла ошибкой не считается
+	Delete(ctx context.Context, key string) error
+}
+
+// New создает хранилище выбранного в конфиге драйвера
+func New(cfg *Config) (Store, error) {
+	switch cfg.Driver {
+	case DriverLocal:
+		return NewLocalStore(cfg.Dir)
+	case DriverS3:
+		return NewS3Store(&cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store driver %q", cfg.Driver)
+	}
+}
+
+// cleanKey проверяет ключ: ключи собирает gateway, но выйти за пределы хранилища через ".." нельзя в любом случае
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || strings.HasPrefix(cleaned, "../") || cleaned == ".." {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,86 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"gateway/pkg/blobstore/s3test"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore проверяет одинаковое поведение всех реализаций хранилища
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	data := []byte("\x89PNG fake image")
+
+	require.NoError(t, store.Put(ctx, "avatars/1/abc/large.png", bytes.NewReader(data), int64(len(data)), "image/png"))
+
+	object, err := store.Get(ctx, "avatars/1/abc/large.png")
+	require.NoError(t, err)
+	stored, err := io.ReadAll(object)
+	require.NoError(t, err)
+	require.NoError(t, object.Close())
+	require.Equal(t, data, stored)
+	require.Equal(t, "image/png", object.ContentType)
+	require.Equal(t, int64(len(data)), object.Size)
+
+	// перезапись по тому же ключу
+	updated := []byte("updated")
+	require.NoError(t, store.Put(ctx, "avatars/1/abc/large.png", bytes.NewReader(updated), int64(len(updated)), "image/png"))
+	object, err = store.Get(ctx, "avatars/1/abc/large.png")
+	require.NoError(t, err)
+	stored, _ = io.ReadAll(object)
+	object.Close()
+	require.Equal(t, updated, stored)
+
+	_, err = store.Get(ctx, "avatars/1/missing.png")
+	require.True(t, errors.Is(err, ErrNotFound), "expected not found, got: %v", err)
+
+	require.NoError(t, store.Delete(ctx, "avatars/1/abc/large.png"))
+	require.NoError(t, store.Delete(ctx, "avatars/1/abc/large.png"))
+	_, err = store.Get(ctx, "avatars/1/abc/large.png")
+	require.True(t, errors.Is(err, ErrNotFound), "expected not found, got: %v", err)
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "avatars/../../secret", "avatars//1", "avatars\\1"} {
+		err := store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "image/png")
+		require.True(t, errors.Is(err, ErrInvalidKey), "key %q: expected invalid key, got: %v", key, err)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	testStore(t, store)
+}
+
+func TestS3Store(t *testing.T) {
+	server := s3test.NewServer()
+	defer server.Close()
+
+	store, err := NewS3Store(&S3Config{
+		Endpoint:  server.Endpoint(),
+		Bucket:    "avatars",
+		AccessKey: s3test.AccessKey,
+		SecretKey: s3test.SecretKey,
+		Region:    s3test.Region,
+	})
+	require.NoError(t, err)
+
+	testStore(t, store)
+}
+
+func TestNew(t *testing.T) {
+	store, err := New(&Config{Driver: DriverLocal, Dir: t.TempDir()})
+	require.NoError(t, err)
+	require.IsType(t, &LocalStore{}, store)
+
+	_, err = New(&Config{Driver: DriverS3})
+	require.Error(t, err)
+
+	_, err = New(&Config{Driver: "ftp"})
+	require.Error(t, err)
+}
@@ -0,0 +1,151 @@
+// Package s3test - минимальное S3 совместимое хранилище в памяти для тестов: PUT, GET, HEAD и DELETE объектов
+// с path-style адресами. Подписи запросов не проверяются
+package s3test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	AccessKey = "test-access-key"
+	SecretKey = "test-secret-key"
+	Region    = "us-east-1"
+)
+
+type object struct {
+	data        []byte
+	contentType string
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	objects map[string]object
+}
+
+func NewServer() *Server {
+	s := &Server{objects: make(map[string]object)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Endpoint - адрес сервера без схемы, как его ожидает S3 клиент
+func (s *Server) Endpoint() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// Keys - ключи всех сохраненных объектов в виде "bucket/key"
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if !strings.Contains(key, "/") {
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "only object operations are supported")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readBody(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+
+		s.mu.Lock()
+		s.objects[key] = object{data: data, contentType: r.Header.Get("Content-Type")}
+		s.mu.Unlock()
+
+		w.Header().Set("ETag", `"`+strconv.Itoa(len(data))+`"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		s.mu.Lock()
+		obj, ok := s.objects[key]
+		s.mu.Unlock()
+
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey", "the specified key does not exist")
+			return
+		}
+
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Header().Set("ETag", `"`+strconv.Itoa(len(obj.data))+`"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(obj.data)
+		}
+	case http.MethodDelete:
+		s.mu.Lock()
+		delete(s.objects, key)
+		s.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method)
+	}
+}
+
+// readBody читает тело запроса. Клиенты по http без TLS отправляют его в формате aws-chunked
+func readBody(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var data bytes.Buffer
+	reader := bufio.NewReader(r.Body)
+	for {
+		// заголовок чанка: "<размер в hex>;chunk-signature=<подпись>\r\n"
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("chunk header: %w", err)
+		}
+
+		sizeHex := strings.TrimSpace(strings.SplitN(header, ";", 2)[0])
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("chunk size: %w", err)
+		}
+		if size == 0 {
+			return data.Bytes(), nil
+		}
+
+		if _, err := io.CopyN(&data, reader, size); err != nil {
+			return nil, fmt.Errorf("chunk data: %w", err)
+		}
+		if _, err := reader.Discard(2); err != nil {
+			return nil, fmt.Errorf("chunk end: %w", err)
+		}
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: code, Message: message})
+}
@@ -0,0 +1,101 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type Config struct {
+	// TrustedProxies - адреса и подсети балансировщиков через запятую, например 10.0.0.0/8,192.168.1.10.
+	// X-Forwarded-For учитывается только в запросах от них
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+}
+
+// Resolver определяет адрес клиента. Заголовок X-Forwarded-For может прислать кто угодно,
+// поэтому ему верим, только если запрос пришел от доверенного прокси
+type Resolver struct {
+	trusted []*net.IPNet
+}
+
+func New(cfg *Config) (*Resolver, error) {
+	resolver := &Resolver{trusted: make([]*net.IPNet, 0, len(cfg.TrustedProxies))}
+
+	for _, proxy := range cfg.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("[New] invalid trusted proxy %q", proxy)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			resolver.trusted = append(resolver.trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("[New] invalid trusted proxy %q: %w", proxy, err)
+		}
+		resolver.trusted = append(resolver.trusted, network)
+	}
+
+	return resolver, nil
+}
+
+// ClientIP возвращает адрес клиента. Если запрос пришел от доверенного прокси, X-Forwarded-For
+// разбирается справа налево: адреса доверенных прокси пропускаются, первый недоверенный - клиент.
+// Адреса левее него клиент мог подставить сам
+func (r *Resolver) ClientIP(req *http.Request) string {
+	ip := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		ip = host
+	}
+
+	if !r.isTrusted(net.ParseIP(ip)) {
+		return ip
+	}
+
+	hops := make([]string, 0)
+	for _, header := range req.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// дальше цепочка не проверяется, клиентом считаем ближайший разобранный адрес
+			break
+		}
+
+		ip = hop.String()
+		if !r.isTrusted(hop) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func (r *Resolver) isTrusted(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range r.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
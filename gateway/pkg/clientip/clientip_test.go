@@ -0,0 +1,80 @@
+package clientip
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_ClientIP(t *testing.T) {
+	resolver, err := New(&Config{TrustedProxies: []string{"10.0.0.0/8", "192.168.1.10"}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		expected   string
+	}{
+		{
+			name:       "NoProxy",
+			remoteAddr: "203.0.113.10:52000",
+			expected:   "203.0.113.10",
+		},
+		{
+			name:       "ForwardedFromUntrustedClient",
+			remoteAddr: "203.0.113.10:52000",
+			forwarded:  []string{"198.51.100.1"},
+			expected:   "203.0.113.10",
+		},
+		{
+			name:       "TrustedProxy",
+			remoteAddr: "10.0.0.5:52000",
+			forwarded:  []string{"203.0.113.10"},
+			expected:   "203.0.113.10",
+		},
+		{
+			name:       "SpoofedLeftmostHop",
+			remoteAddr: "10.0.0.5:52000",
+			forwarded:  []string{"198.51.100.1, 203.0.113.10"},
+			expected:   "203.0.113.10",
+		},
+		{
+			name:       "ChainOfTrustedProxies",
+			remoteAddr: "10.0.0.5:52000",
+			forwarded:  []string{"198.51.100.1, 203.0.113.10", "192.168.1.10, 10.1.2.3"},
+			expected:   "203.0.113.10",
+		},
+		{
+			name:       "InvalidHop",
+			remoteAddr: "192.168.1.10:52000",
+			forwarded:  []string{"203.0.113.10, garbage, 10.1.2.3"},
+			expected:   "10.1.2.3",
+		},
+		{
+			name:       "TrustedProxyWithoutHeader",
+			remoteAddr: "10.0.0.5:52000",
+			expected:   "10.0.0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			require.Equal(t, tt.expected, resolver.ClientIP(req))
+		})
+	}
+}
+
+func TestNew_InvalidProxy(t *testing.T) {
+	for _, invalid := range []string{"10.0.0.0/33", "proxy.local"} {
+		_, err := New(&Config{TrustedProxies: []string{invalid}})
+		require.Error(t, err, invalid)
+	}
+}
@@ -14,6 +14,50 @@ func SetUserIDToContext(ctx context.Context, userID int) context.Context {
 	return context.WithValue(ctx, "UserID", userID)
 }
 
+func GetUserRoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value("UserRole").(string)
+	return role, ok
+}
+
+func SetUserRoleToContext(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, "UserRole", role)
+}
+
+func GetSessionIDFromContext(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value("SessionID").(string)
+	return sessionID, ok
+}
+
+func SetSessionIDToContext(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, "SessionID", sessionID)
+}
+
+// GetAPIKeyScopesFromContext возвращает права API ключа. ok == false, если запрос авторизован JWT токеном
+func GetAPIKeyScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value("APIKeyScopes").([]string)
+	return scopes, ok
+}
+
+func SetAPIKeyScopesToContext(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, "APIKeyScopes", scopes)
+}
+
+// ClientInfo - данные об устройстве, с которого пришел запрос
+type ClientInfo struct {
+	Device    string
+	UserAgent string
+	IP        string
+}
+
+func GetClientInfoFromContext(ctx context.Context) (ClientInfo, bool) {
+	info, ok := ctx.Value("ClientInfo").(ClientInfo)
+	return info, ok
+}
+
+func SetClientInfoToContext(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, "ClientInfo", info)
+}
+
 func GetRequestIDFromContext(ctx context.Context) (string, bool) {
 	requestID, ok := ctx.Value("RequestID").(string)
 	return requestID, ok
@@ -29,14 +29,46 @@ type UserServiceClient interface {
 	UpdateUser(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error)
 	// Метод RPC для обновления пароля пользователя. Принимает UpdateUserPasswordDTO и возвращает пустой ответ
 	UpdatePassword(ctx context.Context, in *UpdateUserPasswordDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	// Метод RPC для удаления пользователя. Принимает UserID и возвращает пустой ответ
-	DeleteUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для удаления пользователя. Планирует обезличивание и возвращает UserDTO со сроком удаления
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для деактивации пользователя. Принимает UserID и возвращает пустой ответ
+	DeactivateUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Метод RPC для получения данных пользователя по ID. Принимает UserID и возвращает UserDTO
 	GetUserByID(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDTO, error)
 	// Метод RPC для получения данных пользователя по имени или email. Принимает UserDTO и возвращает UserDTO
 	GetUserByUsernameOrEmail(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error)
 	// Метод RPC для входа пользователя. Принимает UserLoginDTO и возвращает UserDTO
 	Login(ctx context.Context, in *UserLoginDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для получения списка пользователей. Принимает ListUsersRequest и возвращает UsersList
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*UsersList, error)
+	// Метод RPC для смены роли пользователя. Принимает UpdateUserRoleDTO и возвращает пустой ответ
+	UpdateUserRole(ctx context.Context, in *UpdateUserRoleDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для начала подключения TOTP. Принимает UserID и возвращает TOTPEnrollment
+	EnrollTOTP(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*TOTPEnrollment, error)
+	// Метод RPC для подтверждения TOTP первым кодом. Принимает MFACodeDTO и возвращает RecoveryCodes
+	ConfirmTOTP(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*RecoveryCodes, error)
+	// Метод RPC для отключения TOTP. Принимает MFACodeDTO и возвращает пустой ответ
+	DisableTOTP(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для проверки второго фактора при входе. Принимает MFACodeDTO и возвращает UserDTO
+	VerifyMFA(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для уведомления пользователя о блокировке входа. Принимает AccountLockedDTO и возвращает пустой ответ
+	ReportAccountLocked(ctx context.Context, in *AccountLockedDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для поиска пользователя по внешней учетной записи. Принимает ExternalIdentityDTO и возвращает UserDTO
+	GetUserByExternalIdentity(ctx context.Context, in *ExternalIdentityDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для привязки внешней учетной записи к пользователю или создания нового пользователя. Принимает ExternalIdentityDTO и возвращает UserDTO
+	LinkExternalIdentity(ctx context.Context, in *ExternalIdentityDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для создания API ключа. Принимает CreateAPIKeyDTO и возвращает APIKeyDTO с ключом
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyDTO, opts ...grpc.CallOption) (*APIKeyDTO, error)
+	// Метод RPC для получения API ключей пользователя. Принимает UserID и возвращает APIKeysList
+	ListAPIKeys(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*APIKeysList, error)
+	// Метод RPC для отзыва API ключа. Принимает APIKeyID и возвращает пустой ответ
+	RevokeAPIKey(ctx context.Context, in *APIKeyID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для проверки API ключа. Принимает APIKeySecret и возвращает APIKeyPrincipal
+	AuthenticateAPIKey(ctx context.Context, in *APIKeySecret, opts ...grpc.CallOption) (*APIKeyPrincipal, error)
+	// Метод RPC для смены аватара. Принимает AvatarDTO с новым ключом и возвращает AvatarDTO с прежним ключом
+	UpdateAvatar(ctx context.Context, in *AvatarDTO, opts ...grpc.CallOption) (*AvatarDTO, error)
+	RequestEmailChange(ctx context.Context, in *EmailChangeRequestDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ConfirmEmailChange(ctx context.Context, in *EmailChangeTokenDTO, opts ...grpc.CallOption) (*UserDTO, error)
 }
 
 type userServiceClient struct {
@@ -74,8 +106,8 @@ func (c *userServiceClient) UpdatePassword(ctx context.Context, in *UpdateUserPa
 	return out, nil
 }
 
-func (c *userServiceClient) DeleteUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
-	out := new(emptypb.Empty)
+func (c *userServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
 	err := c.cc.Invoke(ctx, "/userservice.UserService/DeleteUser", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -83,6 +115,15 @@ func (c *userServiceClient) DeleteUser(ctx context.Context, in *UserID, opts ...
 	return out, nil
 }
 
+func (c *userServiceClient) DeactivateUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/DeactivateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *userServiceClient) GetUserByID(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDTO, error) {
 	out := new(UserDTO)
 	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUserByID", in, out, opts...)
@@ -110,6 +151,150 @@ func (c *userServiceClient) Login(ctx context.Context, in *UserLoginDTO, opts ..
 	return out, nil
 }
 
+func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*UsersList, error) {
+	out := new(UsersList)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateUserRole(ctx context.Context, in *UpdateUserRoleDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UpdateUserRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) EnrollTOTP(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*TOTPEnrollment, error) {
+	out := new(TOTPEnrollment)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/EnrollTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmTOTP(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*RecoveryCodes, error) {
+	out := new(RecoveryCodes)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ConfirmTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DisableTOTP(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/DisableTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) VerifyMFA(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/VerifyMFA", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ReportAccountLocked(ctx context.Context, in *AccountLockedDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ReportAccountLocked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserByExternalIdentity(ctx context.Context, in *ExternalIdentityDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUserByExternalIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) LinkExternalIdentity(ctx context.Context, in *ExternalIdentityDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/LinkExternalIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyDTO, opts ...grpc.CallOption) (*APIKeyDTO, error) {
+	out := new(APIKeyDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/CreateAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListAPIKeys(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*APIKeysList, error) {
+	out := new(APIKeysList)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ListAPIKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeAPIKey(ctx context.Context, in *APIKeyID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RevokeAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) AuthenticateAPIKey(ctx context.Context, in *APIKeySecret, opts ...grpc.CallOption) (*APIKeyPrincipal, error) {
+	out := new(APIKeyPrincipal)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/AuthenticateAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateAvatar(ctx context.Context, in *AvatarDTO, opts ...grpc.CallOption) (*AvatarDTO, error) {
+	out := new(AvatarDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UpdateAvatar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestEmailChange(ctx context.Context, in *EmailChangeRequestDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RequestEmailChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmEmailChange(ctx context.Context, in *EmailChangeTokenDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ConfirmEmailChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility
@@ -120,14 +305,46 @@ type UserServiceServer interface {
 	UpdateUser(context.Context, *UserDTO) (*UserDTO, error)
 	// Метод RPC для обновления пароля пользователя. Принимает UpdateUserPasswordDTO и возвращает пустой ответ
 	UpdatePassword(context.Context, *UpdateUserPasswordDTO) (*emptypb.Empty, error)
-	// Метод RPC для удаления пользователя. Принимает UserID и возвращает пустой ответ
-	DeleteUser(context.Context, *UserID) (*emptypb.Empty, error)
+	// Метод RPC для удаления пользователя. Планирует обезличивание и возвращает UserDTO со сроком удаления
+	DeleteUser(context.Context, *DeleteUserRequest) (*UserDTO, error)
+	// Метод RPC для деактивации пользователя. Принимает UserID и возвращает пустой ответ
+	DeactivateUser(context.Context, *UserID) (*emptypb.Empty, error)
 	// Метод RPC для получения данных пользователя по ID. Принимает UserID и возвращает UserDTO
 	GetUserByID(context.Context, *UserID) (*UserDTO, error)
 	// Метод RPC для получения данных пользователя по имени или email. Принимает UserDTO и возвращает UserDTO
 	GetUserByUsernameOrEmail(context.Context, *UserDTO) (*UserDTO, error)
 	// Метод RPC для входа пользователя. Принимает UserLoginDTO и возвращает UserDTO
 	Login(context.Context, *UserLoginDTO) (*UserDTO, error)
+	// Метод RPC для получения списка пользователей. Принимает ListUsersRequest и возвращает UsersList
+	ListUsers(context.Context, *ListUsersRequest) (*UsersList, error)
+	// Метод RPC для смены роли пользователя. Принимает UpdateUserRoleDTO и возвращает пустой ответ
+	UpdateUserRole(context.Context, *UpdateUserRoleDTO) (*emptypb.Empty, error)
+	// Метод RPC для начала подключения TOTP. Принимает UserID и возвращает TOTPEnrollment
+	EnrollTOTP(context.Context, *UserID) (*TOTPEnrollment, error)
+	// Метод RPC для подтверждения TOTP первым кодом. Принимает MFACodeDTO и возвращает RecoveryCodes
+	ConfirmTOTP(context.Context, *MFACodeDTO) (*RecoveryCodes, error)
+	// Метод RPC для отключения TOTP. Принимает MFACodeDTO и возвращает пустой ответ
+	DisableTOTP(context.Context, *MFACodeDTO) (*emptypb.Empty, error)
+	// Метод RPC для проверки второго фактора при входе. Принимает MFACodeDTO и возвращает UserDTO
+	VerifyMFA(context.Context, *MFACodeDTO) (*UserDTO, error)
+	// Метод RPC для уведомления пользователя о блокировке входа. Принимает AccountLockedDTO и возвращает пустой ответ
+	ReportAccountLocked(context.Context, *AccountLockedDTO) (*emptypb.Empty, error)
+	// Метод RPC для поиска пользователя по внешней учетной записи. Принимает ExternalIdentityDTO и возвращает UserDTO
+	GetUserByExternalIdentity(context.Context, *ExternalIdentityDTO) (*UserDTO, error)
+	// Метод RPC для привязки внешней учетной записи к пользователю или создания нового пользователя. Принимает ExternalIdentityDTO и возвращает UserDTO
+	LinkExternalIdentity(context.Context, *ExternalIdentityDTO) (*UserDTO, error)
+	// Метод RPC для создания API ключа. Принимает CreateAPIKeyDTO и возвращает APIKeyDTO с ключом
+	CreateAPIKey(context.Context, *CreateAPIKeyDTO) (*APIKeyDTO, error)
+	// Метод RPC для получения API ключей пользователя. Принимает UserID и возвращает APIKeysList
+	ListAPIKeys(context.Context, *UserID) (*APIKeysList, error)
+	// Метод RPC для отзыва API ключа. Принимает APIKeyID и возвращает пустой ответ
+	RevokeAPIKey(context.Context, *APIKeyID) (*emptypb.Empty, error)
+	// Метод RPC для проверки API ключа. Принимает APIKeySecret и возвращает APIKeyPrincipal
+	AuthenticateAPIKey(context.Context, *APIKeySecret) (*APIKeyPrincipal, error)
+	// Метод RPC для смены аватара. Принимает AvatarDTO с новым ключом и возвращает AvatarDTO с прежним ключом
+	UpdateAvatar(context.Context, *AvatarDTO) (*AvatarDTO, error)
+	RequestEmailChange(context.Context, *EmailChangeRequestDTO) (*emptypb.Empty, error)
+	ConfirmEmailChange(context.Context, *EmailChangeTokenDTO) (*UserDTO, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -144,9 +361,12 @@ func (UnimplementedUserServiceServer) UpdateUser(context.Context, *UserDTO) (*Us
 func (UnimplementedUserServiceServer) UpdatePassword(context.Context, *UpdateUserPasswordDTO) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdatePassword not implemented")
 }
-func (UnimplementedUserServiceServer) DeleteUser(context.Context, *UserID) (*emptypb.Empty, error) {
+func (UnimplementedUserServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*UserDTO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
 }
+func (UnimplementedUserServiceServer) DeactivateUser(context.Context, *UserID) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateUser not implemented")
+}
 func (UnimplementedUserServiceServer) GetUserByID(context.Context, *UserID) (*UserDTO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserByID not implemented")
 }
@@ -156,6 +376,54 @@ func (UnimplementedUserServiceServer) GetUserByUsernameOrEmail(context.Context,
 func (UnimplementedUserServiceServer) Login(context.Context, *UserLoginDTO) (*UserDTO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
 }
+func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersRequest) (*UsersList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateUserRole(context.Context, *UpdateUserRoleDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserRole not implemented")
+}
+func (UnimplementedUserServiceServer) EnrollTOTP(context.Context, *UserID) (*TOTPEnrollment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTOTP not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmTOTP(context.Context, *MFACodeDTO) (*RecoveryCodes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTOTP not implemented")
+}
+func (UnimplementedUserServiceServer) DisableTOTP(context.Context, *MFACodeDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTOTP not implemented")
+}
+func (UnimplementedUserServiceServer) VerifyMFA(context.Context, *MFACodeDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMFA not implemented")
+}
+func (UnimplementedUserServiceServer) ReportAccountLocked(context.Context, *AccountLockedDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportAccountLocked not implemented")
+}
+func (UnimplementedUserServiceServer) GetUserByExternalIdentity(context.Context, *ExternalIdentityDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByExternalIdentity not implemented")
+}
+func (UnimplementedUserServiceServer) LinkExternalIdentity(context.Context, *ExternalIdentityDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkExternalIdentity not implemented")
+}
+func (UnimplementedUserServiceServer) CreateAPIKey(context.Context, *CreateAPIKeyDTO) (*APIKeyDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIKey not implemented")
+}
+func (UnimplementedUserServiceServer) ListAPIKeys(context.Context, *UserID) (*APIKeysList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPIKeys not implemented")
+}
+func (UnimplementedUserServiceServer) RevokeAPIKey(context.Context, *APIKeyID) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (UnimplementedUserServiceServer) AuthenticateAPIKey(context.Context, *APIKeySecret) (*APIKeyPrincipal, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticateAPIKey not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateAvatar(context.Context, *AvatarDTO) (*AvatarDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAvatar not implemented")
+}
+func (UnimplementedUserServiceServer) RequestEmailChange(context.Context, *EmailChangeRequestDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestEmailChange not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmEmailChange(context.Context, *EmailChangeTokenDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmEmailChange not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 
 // UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -224,7 +492,7 @@ func _UserService_UpdatePassword_Handler(srv interface{}, ctx context.Context, d
 }
 
 func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserID)
+	in := new(DeleteUserRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -236,7 +504,25 @@ func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec f
 		FullMethod: "/userservice.UserService/DeleteUser",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServiceServer).DeleteUser(ctx, req.(*UserID))
+		return srv.(UserServiceServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeactivateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeactivateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/DeactivateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeactivateUser(ctx, req.(*UserID))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -295,6 +581,294 @@ func _UserService_Login_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRoleDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UpdateUserRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateUserRole(ctx, req.(*UpdateUserRoleDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/EnrollTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).EnrollTOTP(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MFACodeDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ConfirmTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmTOTP(ctx, req.(*MFACodeDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DisableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MFACodeDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DisableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/DisableTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DisableTOTP(ctx, req.(*MFACodeDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_VerifyMFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MFACodeDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).VerifyMFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/VerifyMFA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).VerifyMFA(ctx, req.(*MFACodeDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ReportAccountLocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountLockedDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ReportAccountLocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ReportAccountLocked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ReportAccountLocked(ctx, req.(*AccountLockedDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUserByExternalIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExternalIdentityDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserByExternalIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUserByExternalIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserByExternalIdentity(ctx, req.(*ExternalIdentityDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_LinkExternalIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExternalIdentityDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).LinkExternalIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/LinkExternalIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).LinkExternalIdentity(ctx, req.(*ExternalIdentityDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/CreateAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateAPIKey(ctx, req.(*CreateAPIKeyDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ListAPIKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListAPIKeys(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(APIKeyID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RevokeAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeAPIKey(ctx, req.(*APIKeyID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_AuthenticateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(APIKeySecret)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AuthenticateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/AuthenticateAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AuthenticateAPIKey(ctx, req.(*APIKeySecret))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AvatarDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateAvatar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UpdateAvatar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateAvatar(ctx, req.(*AvatarDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailChangeRequestDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RequestEmailChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestEmailChange(ctx, req.(*EmailChangeRequestDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailChangeTokenDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ConfirmEmailChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmEmailChange(ctx, req.(*EmailChangeTokenDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -318,6 +892,10 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUser",
 			Handler:    _UserService_DeleteUser_Handler,
 		},
+		{
+			MethodName: "DeactivateUser",
+			Handler:    _UserService_DeactivateUser_Handler,
+		},
 		{
 			MethodName: "GetUserByID",
 			Handler:    _UserService_GetUserByID_Handler,
@@ -330,6 +908,70 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Login",
 			Handler:    _UserService_Login_Handler,
 		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _UserService_ListUsers_Handler,
+		},
+		{
+			MethodName: "UpdateUserRole",
+			Handler:    _UserService_UpdateUserRole_Handler,
+		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _UserService_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "ConfirmTOTP",
+			Handler:    _UserService_ConfirmTOTP_Handler,
+		},
+		{
+			MethodName: "DisableTOTP",
+			Handler:    _UserService_DisableTOTP_Handler,
+		},
+		{
+			MethodName: "VerifyMFA",
+			Handler:    _UserService_VerifyMFA_Handler,
+		},
+		{
+			MethodName: "ReportAccountLocked",
+			Handler:    _UserService_ReportAccountLocked_Handler,
+		},
+		{
+			MethodName: "GetUserByExternalIdentity",
+			Handler:    _UserService_GetUserByExternalIdentity_Handler,
+		},
+		{
+			MethodName: "LinkExternalIdentity",
+			Handler:    _UserService_LinkExternalIdentity_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _UserService_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _UserService_ListAPIKeys_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _UserService_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "AuthenticateAPIKey",
+			Handler:    _UserService_AuthenticateAPIKey_Handler,
+		},
+		{
+			MethodName: "UpdateAvatar",
+			Handler:    _UserService_UpdateAvatar_Handler,
+		},
+		{
+			MethodName: "RequestEmailChange",
+			Handler:    _UserService_RequestEmailChange_Handler,
+		},
+		{
+			MethodName: "ConfirmEmailChange",
+			Handler:    _UserService_ConfirmEmailChange_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "users.proto",
@@ -3,67 +3,249 @@ package jwtutil
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// поддерживаемые алгоритмы подписи
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// типы токенов
+const (
+	TokenTypeAccess       = "access"
+	TokenTypeRefresh      = "refresh"
+	TokenTypeMFAChallenge = "mfa_challenge"
+)
+
+// defaultMFAChallengeExp - время жизни MFA challenge токена, если оно не задано в конфиге
+const defaultMFAChallengeExp = 5 * time.Minute
+
+var (
+	ErrWrongTokenType = errors.New("wrong token type")
+)
+
+type Config struct {
+	Algorithm          string        `envconfig:"ALGORITHM" required:"true" default:"EdDSA"`
+	KeysDir            string        `envconfig:"KEYS_DIR" required:"true" default:"/var/lib/gateway/jwt-keys"`
+	KeysReloadInterval time.Duration `envconfig:"KEYS_RELOAD_INTERVAL" default:"1m"`
+	Issuer             string        `envconfig:"ISSUER" required:"true" default:"todo-gateway"`
+	Audience           string        `envconfig:"AUDIENCE" required:"true" default:"todo-api"`
+	AccessTokenExp     time.Duration `envconfig:"ACCESS_TOKEN_EXP" required:"true" default:"15m"`
+	RefreshTokenExp    time.Duration `envconfig:"REFRESH_TOKEN_EXP" required:"true" default:"24h"`
+	MFAChallengeExp    time.Duration `envconfig:"MFA_CHALLENGE_EXP" default:"5m"`
+	RotationInterval   time.Duration `envconfig:"ROTATION_INTERVAL" default:"168h"`
+}
+
 type JWTUtil struct {
-	SecretKey       string        `envconfig:"SECRET_KEY" required:"true" default:"superSecretKey"`
-	AccessTokenExp  time.Duration `envconfig:"ACCESS_TOKEN_EXP" required:"true" default:"15m"`
-	RefreshTokenExp time.Duration `envconfig:"REFRESH_TOKEN_EXP" required:"true" default:"24h"`
+	AccessTokenExp  time.Duration
+	RefreshTokenExp time.Duration
+	MFAChallengeExp time.Duration
+
+	cfg  *Config
+	keys *keySet
 }
 
-func (ju *JWTUtil) GenerateAccessToken(userID int) (string, int64, error) {
-	return ju.generateToken(userID, ju.SecretKey, ju.AccessTokenExp)
+// Claims - данные, которые gateway кладет в токен и достает из него при проверке.
+// FamilyID объединяет токены, выпущенные при одном логине и всех последующих обновлениях
+type Claims struct {
+	ID        string
+	Type      string
+	FamilyID  string
+	UserID    int
+	Role      string
+	IssuedAt  int64
+	ExpiresAt int64
 }
 
-func (ju *JWTUtil) GenerateRefreshToken(userID int) (string, int64, error) {
-	return ju.generateToken(userID, ju.SecretKey, ju.RefreshTokenExp)
+// tokenClaims - содержимое токена: стандартные claims (iss, sub, aud, exp, iat, jti), тип, семейство и роль
+type tokenClaims struct {
+	Type     string `json:"token_type"`
+	FamilyID string `json:"fid"`
+	Role     string `json:"role,omitempty"`
+	jwt.RegisteredClaims
 }
 
-func (ju *JWTUtil) VerifyToken(token string) (int, int64, error) {
-	return ju.verifyToken(token)
+// New создает JWTUtil. Ключи загружаются из KeysDir, если каталог пуст или не задан - генерируется новый ключ.
+// KeysDir может быть общим для всех реплик gateway. Пустой KeysDir годится только для тестов:
+// ключи живут в памяти, и после рестарта выпущенные токены перестают проходить проверку
+func New(cfg *Config) (*JWTUtil, error) {
+	method, err := signingMethod(cfg.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("[New] %w", err)
+	}
+
+	keys, err := newKeySet(method, cfg.KeysDir, maxDuration(cfg.AccessTokenExp, cfg.RefreshTokenExp))
+	if err != nil {
+		return nil, fmt.Errorf("[New] load keys:%w", err)
+	}
+
+	mfaChallengeExp := cfg.MFAChallengeExp
+	if mfaChallengeExp <= 0 {
+		mfaChallengeExp = defaultMFAChallengeExp
+	}
+
+	return &JWTUtil{
+		AccessTokenExp:  cfg.AccessTokenExp,
+		RefreshTokenExp: cfg.RefreshTokenExp,
+		MFAChallengeExp: mfaChallengeExp,
+		cfg:             cfg,
+		keys:            keys,
+	}, nil
 }
 
-func (ju *JWTUtil) generateToken(userID int, secret string, duration time.Duration) (string, int64, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-	exp := time.Now().Add(duration).Unix()
-	claims["user_id"] = userID
-	claims["exp"] = exp
+// NewFamilyID создает идентификатор семейства токенов для нового логина
+func NewFamilyID() string {
+	return uuid.New().String()
+}
+
+func (ju *JWTUtil) GenerateAccessToken(userID int, role, familyID string) (string, *Claims, error) {
+	return ju.generateToken(userID, role, familyID, TokenTypeAccess, ju.AccessTokenExp)
+}
+
+// GenerateRefreshToken создает refresh токен. Роль в него не кладется:
+// при обновлении токенов она заново запрашивается у сервиса пользователей.
+func (ju *JWTUtil) GenerateRefreshToken(userID int, familyID string) (string, *Claims, error) {
+	return ju.generateToken(userID, "", familyID, TokenTypeRefresh, ju.RefreshTokenExp)
+}
+
+// VerifyAccessToken проверяет токен и то, что это access токен
+func (ju *JWTUtil) VerifyAccessToken(token string) (*Claims, error) {
+	return ju.verifyToken(token, TokenTypeAccess)
+}
+
+// VerifyRefreshToken проверяет токен и то, что это refresh токен
+func (ju *JWTUtil) VerifyRefreshToken(token string) (*Claims, error) {
+	return ju.verifyToken(token, TokenTypeRefresh)
+}
 
-	tokenString, err := token.SignedString([]byte(secret))
+// GenerateMFAChallengeToken создает короткоживущий токен между проверкой пароля и второго фактора.
+// Доступа к API он не дает: middleware принимает только access токены
+func (ju *JWTUtil) GenerateMFAChallengeToken(userID int) (string, *Claims, error) {
+	return ju.generateToken(userID, "", "", TokenTypeMFAChallenge, ju.MFAChallengeExp)
+}
+
+// VerifyMFAChallengeToken проверяет токен и то, что это MFA challenge токен
+func (ju *JWTUtil) VerifyMFAChallengeToken(token string) (*Claims, error) {
+	return ju.verifyToken(token, TokenTypeMFAChallenge)
+}
+
+// JWKS возвращает публичные ключи, которыми можно проверить выпущенные токены
+func (ju *JWTUtil) JWKS() JSONWebKeySet {
+	return ju.keys.jwks()
+}
+
+func (ju *JWTUtil) generateToken(userID int, role, familyID, tokenType string, duration time.Duration) (string, *Claims, error) {
+	now := time.Now()
+	exp := now.Add(duration)
+
+	claims := tokenClaims{
+		Type:     tokenType,
+		FamilyID: familyID,
+		Role:     role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    ju.cfg.Issuer,
+			Subject:   strconv.Itoa(userID),
+			Audience:  jwt.ClaimStrings{ju.cfg.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	}
+
+	key := ju.keys.active()
+
+	token := jwt.NewWithClaims(ju.keys.method, claims)
+	token.Header["kid"] = key.id
+
+	tokenString, err := token.SignedString(key.private)
 	if err != nil {
-		return "", 0, err
+		return "", nil, err
 	}
 
-	return tokenString, exp, nil
+	return tokenString, claims.toClaims(userID), nil
 }
 
-func (ju *JWTUtil) verifyToken(tokenString string) (int, int64, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if token.Method != jwt.SigningMethodHS256 {
-			return nil, fmt.Errorf("Unexpected signing method")
-		}
-		return []byte(ju.SecretKey), nil
-	})
+func (ju *JWTUtil) verifyToken(tokenString, tokenType string) (*Claims, error) {
+	claims := new(tokenClaims)
 
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			key, ok := ju.keys.findOrReload(kid)
+			if !ok {
+				return nil, fmt.Errorf("unknown key id %q", kid)
+			}
+			return key.public, nil
+		},
+		jwt.WithValidMethods([]string{ju.keys.method.Alg()}),
+		jwt.WithIssuer(ju.cfg.Issuer),
+		jwt.WithAudience(ju.cfg.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, errors.New("token is not valid")
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		return 0, 0, fmt.Errorf("token is not valid")
+	// access токен нельзя использовать для обновления и наоборот
+	if claims.Type != tokenType {
+		return nil, ErrWrongTokenType
 	}
 
-	exp := int64(claims["exp"].(float64))
-	if time.Unix(exp, 0).Before(time.Now()) {
-		return 0, 0, errors.New("token has expired")
+	if claims.ID == "" {
+		return nil, errors.New("token has no id")
 	}
 
-	userID := int(claims["user_id"].(float64))
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject: %w", err)
+	}
+
+	return claims.toClaims(userID), nil
+}
+
+func (c *tokenClaims) toClaims(userID int) *Claims {
+	result := &Claims{
+		ID:        c.ID,
+		Type:      c.Type,
+		FamilyID:  c.FamilyID,
+		UserID:    userID,
+		Role:      c.Role,
+		ExpiresAt: c.ExpiresAt.Unix(),
+	}
+	if c.IssuedAt != nil {
+		result.IssuedAt = c.IssuedAt.Unix()
+	}
 
-	return userID, exp, nil
+	return result
+}
+
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
 }
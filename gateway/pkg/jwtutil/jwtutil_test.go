@@ -0,0 +1,149 @@
+package jwtutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUtil(t *testing.T, algorithm, dir string) *JWTUtil {
+	ju, err := New(&Config{
+		Algorithm:       algorithm,
+		KeysDir:         dir,
+		Issuer:          "test-gateway",
+		Audience:        "test-api",
+		AccessTokenExp:  time.Minute,
+		RefreshTokenExp: time.Hour,
+	})
+	require.NoError(t, err)
+
+	return ju
+}
+
+func TestJWTUtil_GenerateAndVerify(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmEdDSA, AlgorithmRS256} {
+		t.Run(algorithm, func(t *testing.T) {
+			ju := newTestUtil(t, algorithm, "")
+
+			familyID := NewFamilyID()
+
+			token, issued, err := ju.GenerateAccessToken(7, "admin", familyID)
+			require.NoError(t, err)
+
+			claims, err := ju.VerifyAccessToken(token)
+			require.NoError(t, err)
+			require.Equal(t, issued, claims)
+			require.Equal(t, 7, claims.UserID)
+			require.Equal(t, "admin", claims.Role)
+			require.Equal(t, familyID, claims.FamilyID)
+			require.NotEmpty(t, claims.ID)
+		})
+	}
+}
+
+func TestJWTUtil_VerifyChecksTokenType(t *testing.T) {
+	ju := newTestUtil(t, AlgorithmEdDSA, "")
+
+	access, _, err := ju.GenerateAccessToken(1, "user", NewFamilyID())
+	require.NoError(t, err)
+	refresh, _, err := ju.GenerateRefreshToken(1, NewFamilyID())
+	require.NoError(t, err)
+
+	_, err = ju.VerifyRefreshToken(access)
+	require.ErrorIs(t, err, ErrWrongTokenType)
+
+	_, err = ju.VerifyAccessToken(refresh)
+	require.ErrorIs(t, err, ErrWrongTokenType)
+
+	challenge, _, err := ju.GenerateMFAChallengeToken(1)
+	require.NoError(t, err)
+
+	_, err = ju.VerifyAccessToken(challenge)
+	require.ErrorIs(t, err, ErrWrongTokenType)
+
+	_, err = ju.VerifyMFAChallengeToken(access)
+	require.ErrorIs(t, err, ErrWrongTokenType)
+
+	claims, err := ju.VerifyMFAChallengeToken(challenge)
+	require.NoError(t, err)
+	require.Equal(t, 1, claims.UserID)
+}
+
+func TestJWTUtil_VerifyRejectsForeignTokens(t *testing.T) {
+	ju := newTestUtil(t, AlgorithmEdDSA, "")
+	other := newTestUtil(t, AlgorithmEdDSA, "")
+
+	token, _, err := other.GenerateAccessToken(1, "user", NewFamilyID())
+	require.NoError(t, err)
+
+	_, err = ju.VerifyAccessToken(token)
+	require.Error(t, err)
+
+	other.cfg.Audience = "another-api"
+	other.keys = ju.keys
+	token, _, err = other.GenerateAccessToken(1, "user", NewFamilyID())
+	require.NoError(t, err)
+
+	_, err = ju.VerifyAccessToken(token)
+	require.Error(t, err)
+}
+
+func TestJWTUtil_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	ju := newTestUtil(t, AlgorithmEdDSA, dir)
+
+	oldToken, _, err := ju.GenerateRefreshToken(1, NewFamilyID())
+	require.NoError(t, err)
+	oldKid := ju.keys.active().id
+
+	require.NoError(t, ju.keys.rotate())
+	require.NotEqual(t, oldKid, ju.keys.active().id)
+
+	// токены, подписанные предыдущим ключом, остаются валидными
+	_, err = ju.VerifyRefreshToken(oldToken)
+	require.NoError(t, err)
+	require.Len(t, ju.JWKS().Keys, 2)
+
+	// после рестарта ключи читаются из каталога
+	restarted := newTestUtil(t, AlgorithmEdDSA, dir)
+	_, err = restarted.VerifyRefreshToken(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, ju.keys.active().id, restarted.keys.active().id)
+
+	// после истечения всех токенов старый ключ удаляется
+	ju.keys.mu.Lock()
+	ju.keys.prune(time.Now().Add(2 * time.Hour))
+	ju.keys.mu.Unlock()
+
+	_, err = ju.VerifyRefreshToken(oldToken)
+	require.Error(t, err)
+	require.Len(t, ju.JWKS().Keys, 1)
+}
+
+func TestJWTUtil_SharedKeysDir(t *testing.T) {
+	dir := t.TempDir()
+	first := newTestUtil(t, AlgorithmEdDSA, dir)
+	second := newTestUtil(t, AlgorithmEdDSA, dir)
+	require.Equal(t, first.keys.active().id, second.keys.active().id)
+
+	require.NoError(t, first.keys.rotate())
+	token, _, err := first.GenerateAccessToken(1, "user", NewFamilyID())
+	require.NoError(t, err)
+
+	// вторая реплика находит ключ, выпущенный первой, перечитав каталог
+	second.keys.lastReload = time.Time{}
+	_, err = second.VerifyAccessToken(token)
+	require.NoError(t, err)
+	require.Equal(t, first.keys.active().id, second.keys.active().id)
+
+	// время создания берется из файла ключа, а не из времени изменения файла
+	created := first.keys.active().createdAt
+	path := filepath.Join(dir, first.keys.active().id+keyFileExt)
+	require.NoError(t, os.Chtimes(path, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+
+	restarted := newTestUtil(t, AlgorithmEdDSA, dir)
+	require.True(t, created.Equal(restarted.keys.active().createdAt))
+}
@@ -0,0 +1,362 @@
+package jwtutil
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	rsaKeyBits   = 2048
+	keyFileExt   = ".pem"
+	tmpFileExt   = ".tmp"
+	pemBlockType = "PRIVATE KEY"
+	// createdAtHeader - заголовок PEM блока с временем создания ключа
+	createdAtHeader = "Created-At"
+	// missReloadInterval - не чаще этого каталог перечитывается из-за токена с неизвестным kid
+	missReloadInterval = 5 * time.Second
+)
+
+// signingKey - ключ подписи. id попадает в заголовок токена как kid
+type signingKey struct {
+	id        string
+	private   crypto.Signer
+	public    crypto.PublicKey
+	createdAt time.Time
+}
+
+// keySet хранит активный ключ и ключи, которыми еще могут быть подписаны не истекшие токены.
+// Каталог с ключами может быть общим для нескольких реплик gateway, поэтому он периодически перечитывается
+type keySet struct {
+	mu sync.RWMutex
+	// syncMu не дает перечитыванию каталога потерять ключ, выпущенный одновременно с ним
+	syncMu     sync.Mutex
+	method     jwt.SigningMethod
+	dir        string
+	retention  time.Duration
+	keys       []*signingKey // отсортированы по времени создания, последний - активный
+	lastReload time.Time
+}
+
+func newKeySet(method jwt.SigningMethod, dir string, retention time.Duration) (*keySet, error) {
+	ks := &keySet{
+		method:    method,
+		dir:       dir,
+		retention: retention,
+	}
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, err
+		}
+		if err := ks.reload(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ks.keys) == 0 {
+		if err := ks.rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+// RunRotation по расписанию выпускает новый ключ подписи и удаляет ключи,
+// все токены которых уже истекли. При нулевом интервале ротация выключена.
+// Каталог ключей перечитывается каждые KeysReloadInterval, и ключ выпускается, только если
+// ни одна реплика еще не заменила активный ключ, поэтому реплики с общим каталогом подписывают одним ключом
+func (ju *JWTUtil) RunRotation(ctx context.Context, logger *zerolog.Logger) error {
+	interval := ju.cfg.RotationInterval
+	reloadInterval := ju.cfg.KeysReloadInterval
+	if ju.keys.dir == "" {
+		reloadInterval = 0
+	}
+	if interval <= 0 && reloadInterval <= 0 {
+		return nil
+	}
+
+	for {
+		// при рестарте отсчитываем интервал от создания текущего ключа
+		wait := reloadInterval
+		if interval > 0 {
+			untilRotation := time.Until(ju.keys.active().createdAt.Add(interval))
+			if wait <= 0 || untilRotation < wait {
+				wait = untilRotation
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		if reloadInterval > 0 {
+			if err := ju.keys.reload(); err != nil {
+				logger.Error().Msgf("[RunRotation] reload signing keys: %s", err)
+			}
+		}
+
+		if interval <= 0 || time.Since(ju.keys.active().createdAt) < interval {
+			continue
+		}
+
+		if err := ju.keys.rotate(); err != nil {
+			logger.Error().Msgf("[RunRotation] rotate signing key: %s", err)
+		} else {
+			logger.Info().Msgf("[RunRotation] signing key rotated, kid: %s", ju.keys.active().id)
+		}
+	}
+}
+
+func (ks *keySet) active() *signingKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return ks.keys[len(ks.keys)-1]
+}
+
+func (ks *keySet) find(id string) (*signingKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, key := range ks.keys {
+		if key.id == id {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+// findOrReload ищет ключ, а если его нет - перечитывает каталог: ключ могла выпустить другая реплика.
+// Каталог перечитывается не чаще missReloadInterval, чтобы токены с чужим kid не нагружали диск
+func (ks *keySet) findOrReload(id string) (*signingKey, bool) {
+	if key, ok := ks.find(id); ok || ks.dir == "" {
+		return key, ok
+	}
+
+	ks.mu.RLock()
+	recent := time.Since(ks.lastReload) < missReloadInterval
+	ks.mu.RUnlock()
+	if recent {
+		return nil, false
+	}
+
+	if err := ks.reload(); err != nil {
+		return nil, false
+	}
+
+	return ks.find(id)
+}
+
+// rotate делает активным новый ключ и удаляет вышедшие из оборота
+func (ks *keySet) rotate() error {
+	ks.syncMu.Lock()
+	defer ks.syncMu.Unlock()
+
+	key, err := ks.generate()
+	if err != nil {
+		return fmt.Errorf("generate key: %w", err)
+	}
+
+	if ks.dir != "" {
+		if err := ks.save(key); err != nil {
+			return fmt.Errorf("save key: %w", err)
+		}
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.keys = append(ks.keys, key)
+	sortKeys(ks.keys)
+	ks.prune(time.Now())
+
+	return nil
+}
+
+// prune удаляет ключи, замененные раньше, чем истек бы самый долгоживущий токен
+func (ks *keySet) prune(now time.Time) {
+	kept := ks.keys[:0]
+	for i, key := range ks.keys {
+		if i < len(ks.keys)-1 && ks.keys[i+1].createdAt.Add(ks.retention).Before(now) {
+			if ks.dir != "" {
+				_ = os.Remove(filepath.Join(ks.dir, key.id+keyFileExt))
+			}
+			continue
+		}
+		kept = append(kept, key)
+	}
+	ks.keys = kept
+}
+
+func (ks *keySet) generate() (*signingKey, error) {
+	var private crypto.Signer
+	var err error
+
+	switch ks.method.Alg() {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("unsupported signing algorithm %q", ks.method.Alg())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &signingKey{
+		id:        uuid.New().String(),
+		private:   private,
+		public:    private.Public(),
+		createdAt: time.Now(),
+	}, nil
+}
+
+// reload заменяет ключи набора ключами из каталога. Ключи в формате PKCS#8 PEM,
+// имя файла без расширения используется как kid, время создания хранится в заголовке Created-At
+func (ks *keySet) reload() error {
+	ks.syncMu.Lock()
+	defer ks.syncMu.Unlock()
+
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.RLock()
+	known := make(map[string]*signingKey, len(ks.keys))
+	for _, key := range ks.keys {
+		known[key.id] = key
+	}
+	ks.mu.RUnlock()
+
+	keys := make([]*signingKey, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExt {
+			continue
+		}
+
+		// файлы ключей не меняются, поэтому уже прочитанные ключи не разбираются заново
+		if key, ok := known[strings.TrimSuffix(entry.Name(), keyFileExt)]; ok {
+			keys = append(keys, key)
+			continue
+		}
+
+		key, err := ks.read(entry.Name())
+		if err != nil {
+			return fmt.Errorf("read key %s: %w", entry.Name(), err)
+		}
+
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.lastReload = time.Now()
+	// пустой каталог не оставляет набор без активного ключа
+	if len(keys) == 0 && len(ks.keys) > 0 {
+		return nil
+	}
+
+	ks.keys = keys
+	ks.prune(time.Now())
+
+	return nil
+}
+
+func (ks *keySet) read(name string) (*signingKey, error) {
+	raw, err := os.ReadFile(filepath.Join(ks.dir, name))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil || block.Type != pemBlockType {
+		return nil, errors.New("no PKCS#8 private key found")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, block.Headers[createdAtHeader])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s header: %w", createdAtHeader, err)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok || !ks.matchesMethod(private) {
+		return nil, fmt.Errorf("key type does not match algorithm %s", ks.method.Alg())
+	}
+
+	return &signingKey{
+		id:        strings.TrimSuffix(name, keyFileExt),
+		private:   private,
+		public:    private.Public(),
+		createdAt: createdAt,
+	}, nil
+}
+
+// save пишет ключ во временный файл и переименовывает его, чтобы другие реплики не прочитали ключ наполовину
+func (ks *keySet) save(key *signingKey) error {
+	raw, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return err
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:    pemBlockType,
+		Headers: map[string]string{createdAtHeader: key.createdAt.UTC().Format(time.RFC3339Nano)},
+		Bytes:   raw,
+	})
+
+	path := filepath.Join(ks.dir, key.id+keyFileExt)
+	if err := os.WriteFile(path+tmpFileExt, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(path+tmpFileExt, path)
+}
+
+func sortKeys(keys []*signingKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].createdAt.Before(keys[j].createdAt)
+	})
+}
+
+func (ks *keySet) matchesMethod(key crypto.Signer) bool {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return ks.method.Alg() == AlgorithmRS256
+	case ed25519.PrivateKey:
+		return ks.method.Alg() == AlgorithmEdDSA
+	default:
+		return false
+	}
+}
@@ -0,0 +1,154 @@
+package loginguard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gateway/pkg/redis"
+)
+
+type Config struct {
+	// после DelayAfterFailures неудачных попыток каждая следующая откладывает вход на BaseDelay * 2^n, но не больше MaxDelay
+	DelayAfterFailures int           `envconfig:"DELAY_AFTER_FAILURES" default:"3"`
+	BaseDelay          time.Duration `envconfig:"BASE_DELAY" default:"1s"`
+	MaxDelay           time.Duration `envconfig:"MAX_DELAY" default:"1m"`
+	MaxAccountFailures int           `envconfig:"MAX_ACCOUNT_FAILURES" default:"10"`
+	MaxIPFailures      int           `envconfig:"MAX_IP_FAILURES" default:"100"`
+	FailureWindow      time.Duration `envconfig:"FAILURE_WINDOW" default:"15m"`
+	LockoutDuration    time.Duration `envconfig:"LOCKOUT_DURATION" default:"15m"`
+}
+
+// Guard считает неудачные попытки входа по аккаунту и по IP и блокирует вход при переборе паролей
+type Guard struct {
+	cfg   *Config
+	redis *redis.RedisManager
+}
+
+// Failure - результат регистрации неудачной попытки
+type Failure struct {
+	// RetryAfter - через сколько можно повторить попытку, ноль если сразу
+	RetryAfter time.Duration
+	// AccountLocked - эта попытка заблокировала аккаунт на LockoutDuration
+	AccountLocked bool
+}
+
+func New(cfg *Config, redisManager *redis.RedisManager) *Guard {
+	return &Guard{
+		cfg:   cfg,
+		redis: redisManager,
+	}
+}
+
+// Check возвращает, сколько еще заблокирован вход для логина или IP. Ноль - вход разрешен
+func (g *Guard) Check(ctx context.Context, login, ip string) (time.Duration, error) {
+	var retryAfter time.Duration
+	for _, subject := range subjects(login, ip) {
+		ttl, err := g.redis.LoginLockTTL(ctx, subject)
+		if err != nil {
+			return 0, err
+		}
+		if ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+
+	return retryAfter, nil
+}
+
+// RegisterFailure учитывает неудачную попытку и при необходимости блокирует вход
+func (g *Guard) RegisterFailure(ctx context.Context, login, ip string) (*Failure, error) {
+	result := new(Failure)
+
+	accountFailures, err := g.redis.RegisterLoginFailure(ctx, accountSubject(login), g.cfg.FailureWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case accountFailures >= int64(g.cfg.MaxAccountFailures):
+		result.RetryAfter = g.cfg.LockoutDuration
+		result.AccountLocked = true
+	case accountFailures >= int64(g.cfg.DelayAfterFailures):
+		result.RetryAfter = g.delay(accountFailures)
+	}
+
+	if result.RetryAfter > 0 {
+		if err := g.redis.LockLogin(ctx, accountSubject(login), result.RetryAfter); err != nil {
+			return nil, err
+		}
+	}
+
+	if ip == "" {
+		return result, nil
+	}
+
+	// с одного IP перебирают пароли к разным аккаунтам, поэтому IP блокируется отдельно
+	ipFailures, err := g.redis.RegisterLoginFailure(ctx, ipSubject(ip), g.cfg.FailureWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	if ipFailures >= int64(g.cfg.MaxIPFailures) {
+		if err := g.redis.LockLogin(ctx, ipSubject(ip), g.cfg.LockoutDuration); err != nil {
+			return nil, err
+		}
+		if g.cfg.LockoutDuration > result.RetryAfter {
+			result.RetryAfter = g.cfg.LockoutDuration
+		}
+	}
+
+	return result, nil
+}
+
+// RegisterSuccess сбрасывает неудачные попытки аккаунта. Счетчик IP не сбрасывается
+func (g *Guard) RegisterSuccess(ctx context.Context, login string) error {
+	return g.redis.ResetLoginFailures(ctx, accountSubject(login))
+}
+
+// Unlock снимает блокировку с аккаунта, логинов может быть несколько: имя и email
+func (g *Guard) Unlock(ctx context.Context, logins ...string) error {
+	result := make([]string, 0, len(logins))
+	for _, login := range logins {
+		if login != "" {
+			result = append(result, accountSubject(login))
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+
+	return g.redis.ResetLoginFailures(ctx, result...)
+}
+
+func (g *Guard) delay(failures int64) time.Duration {
+	delay := g.cfg.BaseDelay
+	for i := int64(g.cfg.DelayAfterFailures); i < failures && delay < g.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > g.cfg.MaxDelay {
+		return g.cfg.MaxDelay
+	}
+
+	return delay
+}
+
+func subjects(login, ip string) []string {
+	result := []string{accountSubject(login)}
+	if ip != "" {
+		result = append(result, ipSubject(ip))
+	}
+
+	return result
+}
+
+// accountSubject - логин без учета регистра, чтобы User и user считались одним аккаунтом
+func accountSubject(login string) string {
+	return fmt.Sprintf("account:%s", strings.ToLower(strings.TrimSpace(login)))
+}
+
+func ipSubject(ip string) string {
+	return fmt.Sprintf("ip:%s", ip)
+}
@@ -0,0 +1,199 @@
+package oidcauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown oidc provider")
+	ErrInvalidIDToken  = errors.New("invalid id token")
+)
+
+type Config struct {
+	// Providers - JSON массив провайдеров, например
+	// [{"name":"google","issuer":"https://accounts.google.com","client_id":"...","client_secret":"...","redirect_url":"..."}]
+	Providers Providers `envconfig:"PROVIDERS" default:"[]"`
+	// StateTTL - сколько живет state между редиректом на провайдера и возвратом на callback
+	StateTTL time.Duration `envconfig:"STATE_TTL" default:"10m"`
+}
+
+type ProviderConfig struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	RedirectURL  string   `json:"redirect_url"`
+	Scopes       []string `json:"scopes"`
+}
+
+type Providers []ProviderConfig
+
+// Decode разбирает список провайдеров из переменной окружения
+func (ps *Providers) Decode(value string) error {
+	var providers Providers
+	if err := json.Unmarshal([]byte(value), &providers); err != nil {
+		return fmt.Errorf("oidc providers: %w", err)
+	}
+
+	for _, p := range providers {
+		if p.Name == "" || p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+			return fmt.Errorf("oidc provider %q: name, issuer, client_id and redirect_url are required", p.Name)
+		}
+	}
+
+	*ps = providers
+	return nil
+}
+
+// Identity - учетная запись пользователя у провайдера из проверенного ID токена
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+// Registry хранит настроенных провайдеров. Discovery выполняется при первом обращении к провайдеру,
+// чтобы недоступный провайдер не мешал запуску gateway
+type Registry struct {
+	cfg       *Config
+	mu        sync.Mutex
+	providers map[string]*Provider
+}
+
+func New(cfg *Config) *Registry {
+	return &Registry{
+		cfg:       cfg,
+		providers: make(map[string]*Provider),
+	}
+}
+
+// StateTTL - время жизни state и PKCE verifier
+func (r *Registry) StateTTL() time.Duration {
+	return r.cfg.StateTTL
+}
+
+// Provider возвращает провайдера по имени, при необходимости загружая его discovery документ
+func (r *Registry) Provider(ctx context.Context, name string) (*Provider, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if provider, ok := r.providers[name]; ok {
+		return provider, nil
+	}
+
+	for _, cfg := range r.cfg.Providers {
+		if cfg.Name != name {
+			continue
+		}
+
+		provider, err := newProvider(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		r.providers[name] = provider
+		return provider, nil
+	}
+
+	return nil, ErrUnknownProvider
+}
+
+type Provider struct {
+	name     string
+	oauth    oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func newProvider(ctx context.Context, cfg ProviderConfig) (*Provider, error) {
+	discoveryCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	issuer, err := oidc.NewProvider(discoveryCtx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery %s: %w", cfg.Name, err)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"email", "profile"}
+	}
+
+	return &Provider{
+		name: cfg.Name,
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     issuer.Endpoint(),
+			Scopes:       append([]string{oidc.ScopeOpenID}, scopes...),
+		},
+		// ключи провайдера подгружаются с фоновым контекстом, а не с контекстом запроса, в котором прошел discovery
+		verifier: issuer.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+	}, nil
+}
+
+// NewVerifier генерирует PKCE code verifier
+func NewVerifier() string {
+	return oauth2.GenerateVerifier()
+}
+
+// AuthCodeURL - адрес, на который нужно отправить пользователя для входа у провайдера
+func (p *Provider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return p.oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier))
+}
+
+// Exchange обменивает код авторизации на токены и возвращает учетную запись из проверенного ID токена
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("no id_token in token response: %w", ErrInvalidIDToken)
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("verify id token: %s: %w", err, ErrInvalidIDToken)
+	}
+
+	// nonce связывает ID токен с нашим запросом и защищает от его повторного использования
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("nonce mismatch: %w", ErrInvalidIDToken)
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+		Name              string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("parse claims: %s: %w", err, ErrInvalidIDToken)
+	}
+
+	username := claims.PreferredUsername
+	if username == "" {
+		username = claims.Name
+	}
+
+	return &Identity{
+		Provider:      p.name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Username:      username,
+	}, nil
+}
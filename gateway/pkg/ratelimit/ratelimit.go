@@ -0,0 +1,167 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gateway/pkg/redis"
+)
+
+// defaultRuleName - имя правила для маршрутов, которые не попали ни под одно правило из конфига
+const defaultRuleName = "default"
+
+type Config struct {
+	Enabled       bool          `envconfig:"ENABLED" default:"true"`
+	DefaultLimit  int           `envconfig:"DEFAULT_LIMIT" default:"300"`
+	DefaultWindow time.Duration `envconfig:"DEFAULT_WINDOW" default:"1m"`
+	// Rules - правила для отдельных маршрутов, см. Rules.Decode
+	Rules Rules `envconfig:"RULES" default:"POST /api/v1/users/login=10/1m;POST /api/v1/users/login/mfa=10/1m;POST /api/v1/users/register=5/1m;POST /api/v1/users/refresh=30/1m;GET /api/v1/auth/oidc/*=20/1m;POST /api/v1/users/me/email=5/1m;GET /api/v1/users/email/confirm=20/1m;GET /api/v1/users/me/export=5/1h;GET /api/v1/todos/search=60/1m"`
+}
+
+// Rule - лимит для маршрута. Pattern - шаблон маршрута mux (например /api/v1/todos/{id}),
+// шаблон с * на конце совпадает со всеми маршрутами с этим префиксом. Пустой Method - любой метод
+type Rule struct {
+	Method  string
+	Pattern string
+	Limit   int
+	Window  time.Duration
+}
+
+// Name - имя правила, по нему разделяются счетчики разных правил
+func (r Rule) Name() string {
+	if r.Method == "" {
+		return r.Pattern
+	}
+
+	return r.Method + " " + r.Pattern
+}
+
+func (r Rule) matches(method, pathTemplate string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+
+	if strings.HasSuffix(r.Pattern, "*") {
+		return strings.HasPrefix(pathTemplate, strings.TrimSuffix(r.Pattern, "*"))
+	}
+
+	return r.Pattern == pathTemplate
+}
+
+type Rules []Rule
+
+// Decode разбирает правила из переменной окружения в формате
+// "[METHOD ]PATTERN=LIMIT/WINDOW;..." например "POST /api/v1/users/login=10/1m;/api/v1/todos/*=100/1m".
+// Лимит 0 отключает ограничение для маршрута
+func (rs *Rules) Decode(value string) error {
+	result := Rules{}
+
+	for _, raw := range strings.Split(value, ";") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		route, limits, ok := strings.Cut(raw, "=")
+		if !ok {
+			return fmt.Errorf("rate limit rule %q: expected ROUTE=LIMIT/WINDOW", raw)
+		}
+
+		rule := Rule{}
+		fields := strings.Fields(route)
+		switch len(fields) {
+		case 1:
+			rule.Pattern = fields[0]
+		case 2:
+			rule.Method, rule.Pattern = strings.ToUpper(fields[0]), fields[1]
+		default:
+			return fmt.Errorf("rate limit rule %q: invalid route", raw)
+		}
+
+		rawLimit, rawWindow, ok := strings.Cut(limits, "/")
+		if !ok {
+			return fmt.Errorf("rate limit rule %q: expected LIMIT/WINDOW", raw)
+		}
+
+		limit, err := strconv.Atoi(strings.TrimSpace(rawLimit))
+		if err != nil || limit < 0 {
+			return fmt.Errorf("rate limit rule %q: invalid limit", raw)
+		}
+
+		window, err := time.ParseDuration(strings.TrimSpace(rawWindow))
+		if err != nil || window <= 0 {
+			return fmt.Errorf("rate limit rule %q: invalid window", raw)
+		}
+
+		rule.Limit, rule.Window = limit, window
+		result = append(result, rule)
+	}
+
+	*rs = result
+	return nil
+}
+
+// Result - результат проверки лимита, из него заполняются заголовки X-RateLimit-*
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset - через сколько в окне освободится место
+	Reset time.Duration
+}
+
+// Limiter ограничивает число запросов скользящим окном в Redis, поэтому лимиты общие для всех реплик gateway
+type Limiter struct {
+	cfg   *Config
+	redis *redis.RedisManager
+}
+
+func New(cfg *Config, redisManager *redis.RedisManager) *Limiter {
+	return &Limiter{
+		cfg:   cfg,
+		redis: redisManager,
+	}
+}
+
+// Rule возвращает первое подходящее правило для маршрута или правило по умолчанию
+func (l *Limiter) Rule(method, pathTemplate string) Rule {
+	for _, rule := range l.cfg.Rules {
+		if rule.matches(method, pathTemplate) {
+			return rule
+		}
+	}
+
+	return Rule{
+		Pattern: defaultRuleName,
+		Limit:   l.cfg.DefaultLimit,
+		Window:  l.cfg.DefaultWindow,
+	}
+}
+
+// Allow учитывает запрос клиента client по правилу rule. Nil результат - ограничение отключено
+func (l *Limiter) Allow(ctx context.Context, rule Rule, client, requestID string) (*Result, error) {
+	if !l.cfg.Enabled || rule.Limit == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	window, err := l.redis.HitRateLimit(ctx, rule.Name()+":"+client, requestID, rule.Limit, rule.Window, now)
+	if err != nil {
+		return nil, err
+	}
+
+	reset := window.Oldest.Add(rule.Window).Sub(now)
+	if reset < 0 {
+		reset = 0
+	}
+
+	return &Result{
+		Allowed:   window.Allowed,
+		Limit:     rule.Limit,
+		Remaining: rule.Limit - window.Count,
+		Reset:     reset,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gateway/pkg/redis"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(t *testing.T, cfg *Config) *Limiter {
+	server := miniredis.RunT(t)
+
+	redisManager, err := redis.NewRedisManager(redis.Config{Address: server.Addr()})
+	require.NoError(t, err)
+
+	return New(cfg, redisManager)
+}
+
+func TestRules_Decode(t *testing.T) {
+	var rules Rules
+	err := rules.Decode("post /api/v1/users/login=10/1m; /api/v1/todos/*=100/30s;/.well-known/jwks.json=0/1s")
+	require.NoError(t, err)
+	require.Equal(t, Rules{
+		{Method: "POST", Pattern: "/api/v1/users/login", Limit: 10, Window: time.Minute},
+		{Pattern: "/api/v1/todos/*", Limit: 100, Window: 30 * time.Second},
+		{Pattern: "/.well-known/jwks.json", Limit: 0, Window: time.Second},
+	}, rules)
+
+	for _, invalid := range []string{"/login", "/login=10", "/login=x/1m", "/login=10/x", "GET POST /login=1/1m"} {
+		require.Error(t, rules.Decode(invalid), invalid)
+	}
+}
+
+func TestLimiter_Rule(t *testing.T) {
+	limiter := newTestLimiter(t, &Config{
+		DefaultLimit:  300,
+		DefaultWindow: time.Minute,
+		Rules: Rules{
+			{Method: "POST", Pattern: "/api/v1/users/login", Limit: 10, Window: time.Minute},
+			{Pattern: "/api/v1/todos/*", Limit: 100, Window: time.Minute},
+		},
+	})
+
+	require.Equal(t, 10, limiter.Rule("POST", "/api/v1/users/login").Limit)
+	require.Equal(t, 300, limiter.Rule("GET", "/api/v1/users/login").Limit)
+	require.Equal(t, 100, limiter.Rule("DELETE", "/api/v1/todos/{id}").Limit)
+	require.Equal(t, defaultRuleName, limiter.Rule("GET", "/api/v1/users/{id:[0-9]+}").Name())
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	limiter := newTestLimiter(t, &Config{Enabled: true})
+	ctx := context.Background()
+	rule := Rule{Pattern: "/test", Limit: 2, Window: 200 * time.Millisecond}
+
+	result, err := limiter.Allow(ctx, rule, "ip:203.0.113.10", "1")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 1, result.Remaining)
+
+	result, err = limiter.Allow(ctx, rule, "ip:203.0.113.10", "2")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+
+	result, err = limiter.Allow(ctx, rule, "ip:203.0.113.10", "3")
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Greater(t, result.Reset, time.Duration(0))
+
+	// лимиты разных клиентов независимы
+	result, err = limiter.Allow(ctx, rule, "user:1", "4")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// после окна запросы снова разрешены
+	time.Sleep(rule.Window)
+	result, err = limiter.Allow(ctx, rule, "ip:203.0.113.10", "5")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// лимит 0 отключает ограничение
+	result, err = limiter.Allow(ctx, Rule{Pattern: "/free", Window: time.Second}, "ip:203.0.113.10", "6")
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
@@ -2,14 +2,55 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	ErrTokenRevoked = errors.New("token revoked")
+	ErrTokenReused  = errors.New("refresh token reused")
 )
 
-// IsTokenValid gets token from Redis by user ID and expiration time combined as a key.
-// if not found in redis - token is invalid
-func (rm *RedisManager) IsTokenValid(ctx context.Context, userID int, expiration int64) bool {
-	result, err := rm.client.Exists(ctx, fmt.Sprintf("%d:%d", userID, expiration)).Result()
+// rotateRefreshScript атомарно сверяет предъявленный refresh токен с текущим токеном семейства.
+// 1 - токен текущий, он заменен новым; 0 - семейство или токен отозваны; -1 - токен уже был обменян
+var rotateRefreshScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'current')
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	return -1
+end
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'current', ARGV[2])
+redis.call('EXPIREAT', KEYS[1], ARGV[3])
+return 1
+`)
+
+func tokenKey(tokenID string) string {
+	return fmt.Sprintf("token:%s", tokenID)
+}
+
+func userTokensKey(userID int) string {
+	return fmt.Sprintf("user:%d:tokens", userID)
+}
+
+func familyKey(familyID string) string {
+	return fmt.Sprintf("token_family:%s", familyID)
+}
+
+func familyTokensKey(familyID string) string {
+	return fmt.Sprintf("token_family:%s:tokens", familyID)
+}
+
+// IsTokenValid checks that the token with given jti was issued and not revoked.
+func (rm *RedisManager) IsTokenValid(ctx context.Context, tokenID string) bool {
+	result, err := rm.client.Exists(ctx, tokenKey(tokenID)).Result()
 	if err != nil {
 		return false
 	}
@@ -17,9 +58,25 @@ func (rm *RedisManager) IsTokenValid(ctx context.Context, userID int, expiration
 	return result == 1
 }
 
-// StoreToken stores a token for a user in Redis with TTL.
-func (rm *RedisManager) StoreToken(ctx context.Context, userID int, token string, expiration int64, ttlDuration time.Duration) error {
-	err := rm.client.Set(ctx, fmt.Sprintf("%d:%d", userID, expiration), token, ttlDuration).Err()
+// StoreToken stores a token by its jti until it expires and links it to the user and the token family.
+func (rm *RedisManager) StoreToken(ctx context.Context, userID int, tokenID, familyID string, expiresAt int64) error {
+	expiration := time.Unix(expiresAt, 0)
+	userTokensExp := rm.extendExpiration(ctx, userTokensKey(userID), expiration)
+	familyTokensExp := rm.extendExpiration(ctx, familyTokensKey(familyID), expiration)
+
+	_, err := rm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, tokenKey(tokenID), userID, time.Until(expiration))
+
+		pipe.SAdd(ctx, userTokensKey(userID), tokenID)
+		pipe.ExpireAt(ctx, userTokensKey(userID), userTokensExp)
+
+		if familyID != "" {
+			pipe.SAdd(ctx, familyTokensKey(familyID), tokenID)
+			pipe.ExpireAt(ctx, familyTokensKey(familyID), familyTokensExp)
+		}
+
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("failed to store token in Redis: %v", err)
 	}
@@ -27,29 +84,149 @@ func (rm *RedisManager) StoreToken(ctx context.Context, userID int, token string
 	return nil
 }
 
-// InvalidateTokensForUser invalidates all tokens for a user.
-func (rm *RedisManager) InvalidateTokensForUser(ctx context.Context, userID int) error {
-	keys, err := rm.client.Keys(ctx, fmt.Sprintf("%d:*", userID)).Result()
+// RotateRefreshToken replaces the current refresh token of the family and revokes all tokens issued before.
+// Presenting an already rotated refresh token revokes the whole family and returns ErrTokenReused.
+func (rm *RedisManager) RotateRefreshToken(ctx context.Context, familyID, refreshTokenID, newRefreshTokenID string, expiresAt int64) error {
+	result, err := rotateRefreshScript.Run(
+		ctx,
+		rm.client,
+		[]string{familyKey(familyID), tokenKey(refreshTokenID)},
+		refreshTokenID,
+		newRefreshTokenID,
+		expiresAt,
+	).Int()
 	if err != nil {
-		return fmt.Errorf("failed to get keys from Redis: %v", err)
+		return fmt.Errorf("failed to rotate refresh token in Redis: %v", err)
+	}
+
+	switch result {
+	case 0:
+		return ErrTokenRevoked
+	case -1:
+		// старый refresh токен мог быть украден: отзываем все токены семейства
+		if err := rm.RevokeSession(ctx, familyID); err != nil {
+			return err
+		}
+		return ErrTokenReused
+	}
+
+	// токены, выпущенные до обновления, больше не нужны
+	if err := rm.revokeFamilyTokens(ctx, familyID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeSession revokes all tokens of the session (token family), so it can't be refreshed anymore.
+func (rm *RedisManager) RevokeSession(ctx context.Context, sessionID string) error {
+	userID, err := rm.client.HGet(ctx, familyKey(sessionID), "user_id").Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to get session from Redis: %v", err)
+	}
+
+	if err := rm.revokeFamilyTokens(ctx, sessionID); err != nil {
+		return err
 	}
 
-	for _, key := range keys {
-		err := rm.client.Del(ctx, key).Err()
-		if err != nil {
-			return fmt.Errorf("failed to invalidate token in Redis: %v", err)
+	_, err = rm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, familyKey(sessionID))
+		if userID != 0 {
+			pipe.SRem(ctx, userSessionsKey(userID), sessionID)
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke session in Redis: %v", err)
+	}
+
+	return nil
+}
+
+// InvalidateTokensForUser invalidates all tokens and sessions for a user.
+// Tokens and sessions are looked up in per-user sets, without scanning the keyspace.
+func (rm *RedisManager) InvalidateTokensForUser(ctx context.Context, userID int) error {
+	tokenIDs, err := rm.client.SMembers(ctx, userTokensKey(userID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get user tokens from Redis: %v", err)
+	}
+
+	sessionIDs, err := rm.client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get user sessions from Redis: %v", err)
+	}
+
+	keys := make([]string, 0, len(tokenIDs)+2*len(sessionIDs)+2)
+	for _, tokenID := range tokenIDs {
+		keys = append(keys, tokenKey(tokenID))
+	}
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, familyKey(sessionID), familyTokensKey(sessionID))
+	}
+	keys = append(keys, userTokensKey(userID), userSessionsKey(userID))
+
+	err = rm.client.Del(ctx, keys...).Err()
+	if err != nil {
+		return fmt.Errorf("failed to invalidate tokens in Redis: %v", err)
 	}
 
 	return nil
 }
 
 // InvalidateToken invalidates a specific token for a user.
-func (rm *RedisManager) InvalidateToken(ctx context.Context, userID int, expiration int64) error {
-	err := rm.client.Del(ctx, fmt.Sprintf("%d:%d", userID, expiration)).Err()
+func (rm *RedisManager) InvalidateToken(ctx context.Context, userID int, tokenID string) error {
+	_, err := rm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, tokenKey(tokenID))
+		pipe.SRem(ctx, userTokensKey(userID), tokenID)
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("failed to invalidate token in Redis: %v", err)
 	}
 
 	return nil
 }
+
+func (rm *RedisManager) revokeFamilyTokens(ctx context.Context, familyID string) error {
+	tokenIDs, err := rm.client.SMembers(ctx, familyTokensKey(familyID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get family tokens from Redis: %v", err)
+	}
+
+	userID, err := rm.client.HGet(ctx, familyKey(familyID), "user_id").Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to get token family from Redis: %v", err)
+	}
+
+	_, err = rm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tokenID := range tokenIDs {
+			pipe.Del(ctx, tokenKey(tokenID))
+			if userID != 0 {
+				pipe.SRem(ctx, userTokensKey(userID), tokenID)
+			}
+		}
+		pipe.Del(ctx, familyTokensKey(familyID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke family tokens in Redis: %v", err)
+	}
+
+	return nil
+}
+
+// extendExpiration возвращает более позднее из текущего времени жизни ключа и expiration,
+// чтобы добавление короткоживущего токена не сокращало жизнь множества
+func (rm *RedisManager) extendExpiration(ctx context.Context, key string, expiration time.Time) time.Time {
+	ttl, err := rm.client.TTL(ctx, key).Result()
+	if err != nil || ttl <= 0 {
+		return expiration
+	}
+
+	current := time.Now().Add(ttl)
+	if current.After(expiration) {
+		return current
+	}
+
+	return expiration
+}
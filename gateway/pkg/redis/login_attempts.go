@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// incrWithWindowScript увеличивает счетчик; окно отсчитывается от первой попытки
+var incrWithWindowScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+func loginFailuresKey(subject string) string {
+	return fmt.Sprintf("login_failures:%s", subject)
+}
+
+func loginLockKey(subject string) string {
+	return fmt.Sprintf("login_lock:%s", subject)
+}
+
+// LoginLockTTL returns how long login is still locked for the subject, zero if it is not locked.
+func (rm *RedisManager) LoginLockTTL(ctx context.Context, subject string) (time.Duration, error) {
+	ttl, err := rm.client.PTTL(ctx, loginLockKey(subject)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get login lock from Redis: %v", err)
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+// RegisterLoginFailure increments failed login attempts of the subject within the window and returns their number.
+func (rm *RedisManager) RegisterLoginFailure(ctx context.Context, subject string, window time.Duration) (int64, error) {
+	count, err := incrWithWindowScript.Run(ctx, rm.client, []string{loginFailuresKey(subject)}, window.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to register login failure in Redis: %v", err)
+	}
+
+	return count, nil
+}
+
+// LockLogin locks login for the subject for the given duration.
+func (rm *RedisManager) LockLogin(ctx context.Context, subject string, duration time.Duration) error {
+	err := rm.client.Set(ctx, loginLockKey(subject), 1, duration).Err()
+	if err != nil {
+		return fmt.Errorf("failed to lock login in Redis: %v", err)
+	}
+
+	return nil
+}
+
+// ResetLoginFailures removes failed attempts and locks of the subjects.
+func (rm *RedisManager) ResetLoginFailures(ctx context.Context, subjects ...string) error {
+	keys := make([]string, 0, len(subjects)*2)
+	for _, subject := range subjects {
+		keys = append(keys, loginFailuresKey(subject), loginLockKey(subject))
+	}
+
+	err := rm.client.Del(ctx, keys...).Err()
+	if err != nil {
+		return fmt.Errorf("failed to reset login failures in Redis: %v", err)
+	}
+
+	return nil
+}
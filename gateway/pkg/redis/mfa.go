@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// MFAChallenge - вход, ожидающий второй фактор. Username и Email - логин, по которому начат вход,
+// по нему считаются неудачные попытки
+type MFAChallenge struct {
+	UserID   int    `json:"user_id"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+func mfaChallengeKey(challengeID string) string {
+	return fmt.Sprintf("mfa_challenge:%s", challengeID)
+}
+
+func mfaAttemptsKey(challengeID string) string {
+	return fmt.Sprintf("mfa_challenge:%s:attempts", challengeID)
+}
+
+// StoreMFAChallenge stores an issued MFA challenge by its jti until it expires.
+func (rm *RedisManager) StoreMFAChallenge(ctx context.Context, challengeID string, data *MFAChallenge, expiresAt int64) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mfa challenge: %v", err)
+	}
+
+	err = rm.client.Set(ctx, mfaChallengeKey(challengeID), value, time.Until(time.Unix(expiresAt, 0))).Err()
+	if err != nil {
+		return fmt.Errorf("failed to store mfa challenge in Redis: %v", err)
+	}
+
+	return nil
+}
+
+// GetMFAChallenge returns the pending login of the challenge.
+// Returns ErrTokenRevoked if the challenge was already used or expired.
+func (rm *RedisManager) GetMFAChallenge(ctx context.Context, challengeID string) (*MFAChallenge, error) {
+	value, err := rm.client.Get(ctx, mfaChallengeKey(challengeID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrTokenRevoked
+		}
+		return nil, fmt.Errorf("failed to get mfa challenge from Redis: %v", err)
+	}
+
+	var data MFAChallenge
+	if err := json.Unmarshal(value, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mfa challenge: %v", err)
+	}
+
+	return &data, nil
+}
+
+// RegisterMFAAttempt increments the number of codes checked for the challenge and returns it.
+// Returns ErrTokenRevoked if the challenge was already used or expired.
+func (rm *RedisManager) RegisterMFAAttempt(ctx context.Context, challengeID string) (int64, error) {
+	ttl, err := rm.client.PTTL(ctx, mfaChallengeKey(challengeID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mfa challenge ttl from Redis: %v", err)
+	}
+	if ttl <= 0 {
+		return 0, ErrTokenRevoked
+	}
+
+	var attempts *redis.IntCmd
+	_, err = rm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		attempts = pipe.Incr(ctx, mfaAttemptsKey(challengeID))
+		pipe.PExpire(ctx, mfaAttemptsKey(challengeID), ttl)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to register mfa attempt in Redis: %v", err)
+	}
+
+	return attempts.Val(), nil
+}
+
+// ConsumeMFAChallenge removes the challenge. Returns false if it was already used or expired,
+// so a challenge can be exchanged for tokens only once.
+func (rm *RedisManager) ConsumeMFAChallenge(ctx context.Context, challengeID string) (bool, error) {
+	var deleted *redis.IntCmd
+	_, err := rm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		deleted = pipe.Del(ctx, mfaChallengeKey(challengeID))
+		pipe.Del(ctx, mfaAttemptsKey(challengeID))
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to consume mfa challenge in Redis: %v", err)
+	}
+
+	return deleted.Val() == 1, nil
+}
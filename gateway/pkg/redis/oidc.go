@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var ErrOIDCStateNotFound = errors.New("oidc state not found")
+
+// OIDCState - данные входа через провайдера, которые нужны на callback
+type OIDCState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+func oidcStateKey(state string) string {
+	return fmt.Sprintf("oidc_state:%s", state)
+}
+
+// StoreOIDCState saves the login attempt under its state parameter until the provider redirects back.
+func (rm *RedisManager) StoreOIDCState(ctx context.Context, state string, data *OIDCState, ttl time.Duration) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal oidc state: %v", err)
+	}
+
+	err = rm.client.Set(ctx, oidcStateKey(state), value, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("failed to store oidc state in Redis: %v", err)
+	}
+
+	return nil
+}
+
+// ConsumeOIDCState returns the login attempt and removes it, so a state can be used only once.
+// Returns ErrOIDCStateNotFound if the state is unknown, expired or already used.
+func (rm *RedisManager) ConsumeOIDCState(ctx context.Context, state string) (*OIDCState, error) {
+	value, err := rm.client.GetDel(ctx, oidcStateKey(state)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrOIDCStateNotFound
+		}
+		return nil, fmt.Errorf("failed to consume oidc state in Redis: %v", err)
+	}
+
+	var data OIDCState
+	if err := json.Unmarshal(value, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal oidc state: %v", err)
+	}
+
+	return &data, nil
+}
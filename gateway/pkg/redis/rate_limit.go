@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// slidingWindowScript - скользящее окно на sorted set: в нем хранятся времена запросов за последние window мс.
+// Возвращает {1 - запрос разрешен / 0 - нет, число запросов в окне, время самого старого запроса в окне}
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local oldestScore = now
+if oldest[2] then
+	oldestScore = tonumber(oldest[2])
+end
+
+return {allowed, count, oldestScore}
+`)
+
+// RateLimitWindow - состояние окна после учета запроса
+type RateLimitWindow struct {
+	Allowed bool
+	Count   int
+	// Oldest - время самого старого запроса в окне, после Oldest+window освободится место
+	Oldest time.Time
+}
+
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("rate_limit:%s", key)
+}
+
+// HitRateLimit records a request in the sliding window of the key if the limit allows it.
+func (rm *RedisManager) HitRateLimit(ctx context.Context, key, requestID string, limit int, window time.Duration, now time.Time) (*RateLimitWindow, error) {
+	nowMs := now.UnixMilli()
+
+	// одинаковые метки времени не должны схлопываться в sorted set, поэтому член - время и id запроса
+	member := strconv.FormatInt(nowMs, 10) + ":" + requestID
+
+	result, err := slidingWindowScript.Run(ctx, rm.client, []string{rateLimitKey(key)}, nowMs, window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hit rate limit in Redis: %v", err)
+	}
+
+	return &RateLimitWindow{
+		Allowed: result[0] == 1,
+		Count:   int(result[1]),
+		Oldest:  time.UnixMilli(result[2]),
+	}, nil
+}
@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session - сессия пользователя на одном устройстве. Совпадает с семейством токенов:
+// создается при логине и живет, пока обновляется refresh токен
+type Session struct {
+	ID        string
+	UserID    int
+	Device    string
+	UserAgent string
+	IP        string
+	CreatedAt time.Time
+	LastSeen  time.Time
+	TokenIDs  []string
+}
+
+func userSessionsKey(userID int) string {
+	return fmt.Sprintf("user:%d:sessions", userID)
+}
+
+// CreateSession starts a new session (refresh token family) with the given refresh token as the current one.
+func (rm *RedisManager) CreateSession(ctx context.Context, session *Session, refreshTokenID string, expiresAt int64) error {
+	expiration := time.Unix(expiresAt, 0)
+	userSessionsExp := rm.extendExpiration(ctx, userSessionsKey(session.UserID), expiration)
+
+	_, err := rm.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, familyKey(session.ID),
+			"user_id", session.UserID,
+			"current", refreshTokenID,
+			"device", session.Device,
+			"user_agent", session.UserAgent,
+			"ip", session.IP,
+			"created_at", session.CreatedAt.Unix(),
+			"last_seen", session.LastSeen.Unix(),
+		)
+		pipe.ExpireAt(ctx, familyKey(session.ID), expiration)
+
+		pipe.SAdd(ctx, userSessionsKey(session.UserID), session.ID)
+		pipe.ExpireAt(ctx, userSessionsKey(session.UserID), userSessionsExp)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create session in Redis: %v", err)
+	}
+
+	return nil
+}
+
+// TouchSession updates the last activity time of the session.
+func (rm *RedisManager) TouchSession(ctx context.Context, sessionID string, lastSeen time.Time) error {
+	// HSET на несуществующем ключе создал бы пустую сессию без TTL, поэтому проверяем наличие
+	exists, err := rm.client.Exists(ctx, familyKey(sessionID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get session from Redis: %v", err)
+	}
+	if exists == 0 {
+		return ErrSessionNotFound
+	}
+
+	err = rm.client.HSet(ctx, familyKey(sessionID), "last_seen", lastSeen.Unix()).Err()
+	if err != nil {
+		return fmt.Errorf("failed to update session in Redis: %v", err)
+	}
+
+	return nil
+}
+
+// GetSession returns the session by ID.
+func (rm *RedisManager) GetSession(ctx context.Context, sessionID string) (*Session, error) {
+	fields, err := rm.client.HGetAll(ctx, familyKey(sessionID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session from Redis: %v", err)
+	}
+	if len(fields) == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	tokenIDs, err := rm.client.SMembers(ctx, familyTokensKey(sessionID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session tokens from Redis: %v", err)
+	}
+
+	return sessionFromHash(sessionID, fields, tokenIDs), nil
+}
+
+// ListSessions returns all active sessions of the user.
+func (rm *RedisManager) ListSessions(ctx context.Context, userID int) ([]*Session, error) {
+	sessionIDs, err := rm.client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user sessions from Redis: %v", err)
+	}
+
+	sessions := make([]*Session, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		session, err := rm.GetSession(ctx, sessionID)
+		if err != nil {
+			if errors.Is(err, ErrSessionNotFound) {
+				// сессия истекла вместе с refresh токеном, убираем ее из множества
+				rm.client.SRem(ctx, userSessionsKey(userID), sessionID)
+				continue
+			}
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+func sessionFromHash(sessionID string, fields map[string]string, tokenIDs []string) *Session {
+	userID, _ := strconv.Atoi(fields["user_id"])
+	createdAt, _ := strconv.ParseInt(fields["created_at"], 10, 64)
+	lastSeen, _ := strconv.ParseInt(fields["last_seen"], 10, 64)
+
+	return &Session{
+		ID:        sessionID,
+		UserID:    userID,
+		Device:    fields["device"],
+		UserAgent: fields["user_agent"],
+		IP:        fields["ip"],
+		CreatedAt: time.Unix(createdAt, 0),
+		LastSeen:  time.Unix(lastSeen, 0),
+		TokenIDs:  tokenIDs,
+	}
+}
@@ -1,7 +1,78 @@
 package models
 
+const (
+	// новый комментарий к задаче
+	TodoEventTypeCommentAdded = "todo_comment_added"
+	// приглашение в рабочее пространство
+	TodoEventTypeWorkspaceInvitation = "todo_workspace_invitation"
+	// массовая операция над задачами исполнителя
+	TodoEventTypeBulkUpdated = "todo_bulk_updated"
+)
+
+const (
+	EmailSubjectCommentAdded        = "New comment on your todo"
+	EmailSubjectWorkspaceInvitation = "You are invited to a workspace"
+	EmailSubjectBulkUpdated         = "Your todos were updated"
+)
+
+const (
+	EmailBodyCommentAdded = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+
+			<p>%s left a comment on the todo "%s":</p>
+			<blockquote>%s</blockquote>
+
+		</body>
+		</html>
+	`
+
+	EmailBodyWorkspaceInvitation = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+
+			<p>%s invited you to the workspace "%s" as %s.</p>
+			<p>Sign in with this email address and follow the link below to join.</p>
+
+			<a href="%s">Accept invitation</a>
+
+			<p>If you don't know the sender, just ignore this letter.</p>
+
+		</body>
+		</html>
+	`
+
+	EmailBodyBulkUpdated = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+
+			<p>%s applied "%s" to %d of your todos:</p>
+			<ul>%s</ul>
+
+		</body>
+		</html>
+	`
+)
+
 type TodoMailItem struct {
-	//UserEventType string   `json:"user_event_type"`
-	Receivers []string `json:"receivers"`
-	//Link          string   `json:"link"`
+	TodoEventType   string     `json:"todo_event_type"`
+	Receivers       []string   `json:"receivers"`
+	Link            string     `json:"link"`
+	TodoID          string     `json:"todo_id,omitempty"`
+	TodoDescription string     `json:"todo_description,omitempty"`
+	Author          string     `json:"author,omitempty"`
+	CommentBody     string     `json:"comment_body,omitempty"`
+	WorkspaceName   string     `json:"workspace_name,omitempty"`
+	Role            string     `json:"role,omitempty"`
+	Operation       string     `json:"operation,omitempty"`
+	Todos           []MailTodo `json:"todos,omitempty"`
+}
+
+// MailTodo - задача в письме о массовой операции
+type MailTodo struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
 }
@@ -2,10 +2,17 @@ package models
 
 const (
 	UserEventTypeEmailVerification = "user_verify_email"
+	UserEventTypeAccountLocked     = "user_account_locked"
+	// смена email: ссылка на новый адрес и предупреждение на прежний
+	UserEventTypeEmailChangeConfirm = "user_email_change_confirm"
+	UserEventTypeEmailChangeNotice  = "user_email_change_notice"
 )
 
 const (
-	EmailSubjectEmailVerification = "Verify email"
+	EmailSubjectEmailVerification  = "Verify email"
+	EmailSubjectAccountLocked      = "Sign-in temporarily locked"
+	EmailSubjectEmailChangeConfirm = "Confirm your new email"
+	EmailSubjectEmailChangeNotice  = "Your email is being changed"
 )
 
 const (
@@ -41,8 +48,59 @@ const (
 	`
 )
 
+const (
+	EmailBodyAccountLocked = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+
+			<p>We noticed too many failed sign-in attempts to your account from IP address %s.</p>
+			<p>Sign-in is temporarily locked until %s.</p>
+			<p>If it wasn't you, we recommend changing your password and enabling two-factor authentication.</p>
+
+		</body>
+		</html>
+	`
+)
+
+const (
+	EmailBodyEmailChangeConfirm = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+
+			<p>We received a request to use this address for your account.</p>
+			<p>Please confirm it by following the link below. Until then your current address stays in use.</p>
+
+			<a href="%s">Confirm email</a>
+
+			<p>If you didn't request this change, just ignore this letter.</p>
+
+		</body>
+		</html>
+	`
+)
+
+const (
+	EmailBodyEmailChangeNotice = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+
+			<p>We received a request to change the email of your account to %s.</p>
+			<p>The change takes effect only after it is confirmed from the new address.</p>
+			<p>If it wasn't you, we recommend changing your password and enabling two-factor authentication.</p>
+
+		</body>
+		</html>
+	`
+)
+
 type UserMailItem struct {
 	UserEventType string   `json:"user_event_type"`
 	Receivers     []string `json:"receivers"`
 	Link          string   `json:"link"`
+	IP            string   `json:"ip,omitempty"`
+	LockedUntil   string   `json:"locked_until,omitempty"`
+	NewEmail      string   `json:"new_email,omitempty"`
 }
@@ -3,7 +3,10 @@ package service
 import (
 	"fmt"
 	"github.com/rs/zerolog"
+	"html"
+	"notifications/internal/app_errors"
 	"notifications/internal/models"
+	"strings"
 )
 
 type TodoService struct {
@@ -25,10 +28,30 @@ func (s *TodoService) GetUserMessage(item *models.TodoMailItem) error {
 
 	var messageBody, subject string
 
-	//switch {  TODO
-	//default:
-	//	return app_errors.ErrIncorrectUserEventType
-	//}
+	switch item.TodoEventType {
+	case models.TodoEventTypeCommentAdded:
+		messageBody = fmt.Sprintf(models.EmailBodyCommentAdded,
+			html.EscapeString(item.Author), html.EscapeString(item.TodoDescription), html.EscapeString(item.CommentBody))
+		subject = models.EmailSubjectCommentAdded
+
+	case models.TodoEventTypeWorkspaceInvitation:
+		messageBody = fmt.Sprintf(models.EmailBodyWorkspaceInvitation,
+			html.EscapeString(item.Author), html.EscapeString(item.WorkspaceName), html.EscapeString(item.Role),
+			html.EscapeString(item.Link))
+		subject = models.EmailSubjectWorkspaceInvitation
+
+	case models.TodoEventTypeBulkUpdated:
+		var todos strings.Builder
+		for _, todo := range item.Todos {
+			todos.WriteString("<li>" + html.EscapeString(todo.Description) + "</li>")
+		}
+		messageBody = fmt.Sprintf(models.EmailBodyBulkUpdated,
+			html.EscapeString(item.Author), html.EscapeString(item.Operation), len(item.Todos), todos.String())
+		subject = models.EmailSubjectBulkUpdated
+
+	default:
+		return app_errors.ErrIncorrectUserEventType
+	}
 
 	s.logger.Info().Msgf("[GetUserMessage] getting %s message", subject)
 	err := s.smtpClient.Send(item.Receivers, subject, messageBody)
@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"github.com/rs/zerolog"
+	"html"
 	"notifications/internal/app_errors"
 	"notifications/internal/models"
 )
@@ -31,6 +32,18 @@ func (s *UsersService) SendUserMessage(item *models.UserMailItem) error {
 		messageBody = fmt.Sprintf(models.EmailBodyEmailVerification, item.Link)
 		subject = models.EmailSubjectEmailVerification
 
+	case models.UserEventTypeAccountLocked:
+		messageBody = fmt.Sprintf(models.EmailBodyAccountLocked, html.EscapeString(item.IP), html.EscapeString(item.LockedUntil))
+		subject = models.EmailSubjectAccountLocked
+
+	case models.UserEventTypeEmailChangeConfirm:
+		messageBody = fmt.Sprintf(models.EmailBodyEmailChangeConfirm, html.EscapeString(item.Link))
+		subject = models.EmailSubjectEmailChangeConfirm
+
+	case models.UserEventTypeEmailChangeNotice:
+		messageBody = fmt.Sprintf(models.EmailBodyEmailChangeNotice, html.EscapeString(item.NewEmail))
+		subject = models.EmailSubjectEmailChangeNotice
+
 	default:
 		return app_errors.ErrIncorrectUserEventType
 	}
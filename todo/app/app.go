@@ -1,23 +1,29 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
+	"time"
 	"todo/cache"
 	"todo/config"
 	"todo/internal/api"
 	"todo/internal/api/grpc"
+	"todo/internal/api/rabbitmq"
 	"todo/internal/api/rest"
+	"todo/internal/clients/users"
 	"todo/internal/repository"
 	"todo/internal/service"
 	"todo/pkg/jaeger"
 	"todo/pkg/logging"
 	"todo/pkg/postgresql"
+	rabbitConsumer "todo/pkg/rabbitmq/consumer"
 	"todo/pkg/rabbitmq/producer"
 	"todo/pkg/redis"
+	"todo/pkg/validator"
 )
 
 type App struct {
@@ -26,6 +32,7 @@ type App struct {
 	logger           *zerolog.Logger
 	todoService      api.TodoService
 	rabbitmqProducer *producer.Producer
+	rabbitmqConsumer *rabbitConsumer.RabbitMQ
 }
 
 func NewApp(
@@ -38,7 +45,7 @@ func NewApp(
 		return nil, fmt.Errorf("connect to database: %w", err)
 	}
 
-	todoRepo := repository.NewTodoRepository(databaseConn)
+	todoRepo := repository.NewTodoRepository(databaseConn, cfg.Search.Language)
 
 	todosProducer, err := producer.New(
 		&cfg.RabbitConfig,
@@ -56,12 +63,42 @@ func NewApp(
 	}
 
 	todoRedis := cache.NewTodoRedisManager(redisManager, cfg.App.AppCacheTTL)
-	todoService := service.NewTodoService(todoRepo, todosProducer, todoRedis)
+
+	usersClient, err := users.NewUsersClient(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("start users client: %w", err)
+	}
+
+	todoService := service.NewTodoService(
+		todoRepo,
+		todosProducer,
+		todoRedis,
+		usersClient,
+		validator.New(&cfg.Validation),
+		&cfg.Recurrence,
+		&cfg.Workspaces,
+	)
+
+	usersConsumer, err := rabbitConsumer.New(&cfg.RabbitConsumerConfig, logger)
+	if err != nil {
+		return nil, fmt.Errorf("create rabbit consumer: %w", err)
+	}
+
+	err = usersConsumer.AddBatchConsumer(
+		&cfg.RabbitConsumerConfig,
+		cfg.UserEventsQueue,
+		cfg.UsersExchange,
+		rabbitmq.NewUserEventsHandler(logger, todoService),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("start user events consumer: %w", err)
+	}
 
 	return &App{
-		cfg:         cfg,
-		logger:      logger,
-		todoService: todoService,
+		cfg:              cfg,
+		logger:           logger,
+		todoService:      todoService,
+		rabbitmqConsumer: usersConsumer,
 	}, nil
 }
 
@@ -76,6 +113,9 @@ func (a *App) RunApp() error {
 	opentracing.SetGlobalTracer(tracer)
 	defer closer.Close()
 
+	a.rabbitmqConsumer.Run(context.Background())
+	defer a.rabbitmqConsumer.Stop()
+
 	group := new(errgroup.Group)
 	group.Go(func() error {
 		err := rest.NewRestApi(a.cfg, a.logger, a.todoService)
@@ -87,9 +127,43 @@ func (a *App) RunApp() error {
 		return fmt.Errorf("[RunApp] run GRPC: %w", err)
 	})
 
+	group.Go(func() error {
+		a.generateDueOccurrences(context.Background())
+		return nil
+	})
+
 	if err := group.Wait(); err != nil {
 		return fmt.Errorf("[RunApp] run: %w", err)
 	}
 
 	return nil
 }
+
+// generateDueOccurrences периодически создает следующие повторения задач, срок которых наступил
+func (a *App) generateDueOccurrences(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.Recurrence.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// пачки обрабатываются, пока не останется задач с наступившим сроком
+		for {
+			generated, err := a.todoService.GenerateDueOccurrences(ctx)
+			if err != nil {
+				a.logger.Error().Msgf("[generateDueOccurrences] generate: %s", err)
+				break
+			}
+			if generated > 0 {
+				a.logger.Info().Msgf("[generateDueOccurrences] processed %d recurring todos", generated)
+			}
+			if generated < a.cfg.Recurrence.BatchSize {
+				break
+			}
+		}
+	}
+}
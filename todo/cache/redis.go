@@ -11,7 +11,11 @@ import (
 )
 
 const (
-	GetTodoByIdCacheKey = "get todo by id %s"
+	// GetTodoByIdCacheKey - задача в конкретной версии. Версия меняется при каждом изменении задачи,
+	// поэтому запись одной версии не устаревает и может быть прочитана по версии из If-Match
+	GetTodoByIdCacheKey = "get todo by id %s version %d"
+	// TodoVersionCacheKey - последняя сохраненная в кэше версия задачи
+	TodoVersionCacheKey = "todo version %s"
 )
 
 type TodoRedisManager struct {
@@ -23,10 +27,21 @@ func NewTodoRedisManager(redisManager *redis.RedisManager, cacheTtl time.Duratio
 	return &TodoRedisManager{redisManager, cacheTtl}
 }
 
+// GetCacheByTodoID возвращает последнюю версию задачи из кэша
 func (m *TodoRedisManager) GetCacheByTodoID(ctx context.Context, todoID uuid.UUID) (*models.TodoDAO, error) {
+	version, err := m.redisManager.Client.Get(ctx, fmt.Sprintf(TodoVersionCacheKey, todoID.String())).Int()
+	if err != nil {
+		return nil, err
+	}
+
+	return m.GetCacheByTodoVersion(ctx, todoID, version)
+}
+
+// GetCacheByTodoVersion возвращает задачу в указанной версии, даже если она уже не последняя
+func (m *TodoRedisManager) GetCacheByTodoVersion(ctx context.Context, todoID uuid.UUID, version int) (*models.TodoDAO, error) {
 	var todo *models.TodoDAO
 
-	redisTodo, err := m.redisManager.Client.Get(ctx, fmt.Sprintf(GetTodoByIdCacheKey, todoID.String())).Bytes()
+	redisTodo, err := m.redisManager.Client.Get(ctx, fmt.Sprintf(GetTodoByIdCacheKey, todoID.String(), version)).Bytes()
 	if err != nil {
 		return nil, err
 	}
@@ -41,21 +56,36 @@ func (m *TodoRedisManager) GetCacheByTodoID(ctx context.Context, todoID uuid.UUI
 	return todo, nil
 }
 
+// StoreCache сохраняет задачу под ее версией и делает эту версию последней
 func (m *TodoRedisManager) StoreCache(ctx context.Context, todo *models.TodoDAO) {
 	redisTodo, err := json.Marshal(todo)
 	if err == nil {
-		m.redisManager.Client.Set(
+		pipe := m.redisManager.Client.TxPipeline()
+		pipe.Set(
 			ctx,
-			fmt.Sprintf(GetTodoByIdCacheKey, todo.ID.String()),
+			fmt.Sprintf(GetTodoByIdCacheKey, todo.ID.String(), todo.Version),
 			redisTodo,
 			m.cacheTtl,
 		)
+		pipe.Set(
+			ctx,
+			fmt.Sprintf(TodoVersionCacheKey, todo.ID.String()),
+			todo.Version,
+			m.cacheTtl,
+		)
+		pipe.Exec(ctx)
 	}
 }
 
+// FlushCache удаляет последнюю версию задачи. Поля, которые считаются при чтении (метки, подзадачи),
+// меняются без смены версии, поэтому запись этой версии удаляется вместе с указателем на нее
 func (m *TodoRedisManager) FlushCache(ctx context.Context, todoID uuid.UUID) {
-	m.redisManager.Client.Del(
-		ctx,
-		fmt.Sprintf(GetTodoByIdCacheKey, todoID.String()),
-	)
+	versionKey := fmt.Sprintf(TodoVersionCacheKey, todoID.String())
+
+	keys := []string{versionKey}
+	if version, err := m.redisManager.Client.Get(ctx, versionKey).Int(); err == nil {
+		keys = append(keys, fmt.Sprintf(GetTodoByIdCacheKey, todoID.String(), version))
+	}
+
+	m.redisManager.Client.Del(ctx, keys...)
 }
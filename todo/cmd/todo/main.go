@@ -1,6 +1,8 @@
 package main
 
 import (
+	// база часовых поясов для повторений задач, в образе ее может не быть
+	_ "time/tzdata"
 	"todo/app"
 	"todo/config"
 )
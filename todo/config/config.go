@@ -8,6 +8,7 @@ import (
 	"todo/pkg/postgresql"
 	"todo/pkg/rabbitmq"
 	"todo/pkg/redis"
+	"todo/pkg/validator"
 )
 
 type Config struct {
@@ -20,6 +21,17 @@ type Config struct {
 	TodoExchange string                        `envconfig:"RABBITMQ_TODO_EXCHANGE" default:"todo.exchange"`
 	TodoQueue    string                        `envconfig:"RABBITMQ_TODO_QUEUE" default:"todo.queue"`
 	RedisConfig  redis.Config                  `envconfig:"REDIS"`
+	Validation   validator.Config              `envconfig:"VALIDATION"`
+
+	// события пользователей (user.deleted) из сервиса users
+	RabbitConsumerConfig rabbitmq.RabbitConsumerConfig `envconfig:"RABBITMQ"`
+	UsersExchange        string                        `envconfig:"RABBITMQ_USERS_EXCHANGE" default:"users.exchange"`
+	UserEventsQueue      string                        `envconfig:"RABBITMQ_USER_EVENTS_QUEUE" default:"users.events.queue"`
+
+	UsersClient UsersClient `envconfig:"USERS"`
+	Search      Search      `envconfig:"SEARCH"`
+	Recurrence  Recurrence  `envconfig:"RECURRENCE"`
+	Workspaces  Workspaces  `envconfig:"WORKSPACES"`
 }
 
 type MigrationsConfig struct {
@@ -37,6 +49,31 @@ type Grpc struct {
 	AppPort string `envconfig:"GRPC_PORT" required:"true" default:"50000"`
 }
 
+type UsersClient struct {
+	AppHost     string `envconfig:"USERS_HOST" required:"true" default:"0.0.0.0"`
+	AppGrpcPort string `envconfig:"USERS_GRPC_PORT" required:"true" default:"50000"`
+}
+
+type Search struct {
+	// Language - конфигурация текстового поиска postgres (english, russian, simple...).
+	// После смены уже сохраненные поисковые векторы нужно пересчитать
+	Language string `envconfig:"SEARCH_LANGUAGE" required:"true" default:"english"`
+}
+
+// Recurrence - настройки создания повторений задач по расписанию
+type Recurrence struct {
+	// Interval - как часто ищутся повторяющиеся задачи, срок которых наступил
+	Interval  time.Duration `envconfig:"RECURRENCE_INTERVAL" default:"1m"`
+	BatchSize int           `envconfig:"RECURRENCE_BATCH_SIZE" default:"100"`
+}
+
+// Workspaces - настройки приглашений в рабочие пространства
+type Workspaces struct {
+	// InvitationURL - страница принятия приглашения, токен передается в параметре token
+	InvitationURL string        `envconfig:"WORKSPACES_INVITATION_URL" default:"http://localhost:3000/workspaces/invitations/accept"`
+	InvitationTTL time.Duration `envconfig:"WORKSPACES_INVITATION_TTL" default:"168h"`
+}
+
 func NewFromEnv() *Config {
 	c := Config{}
 	envconfig.MustProcess("", &c)
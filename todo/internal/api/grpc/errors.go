@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"todo/internal/app_errors"
+)
+
+var (
+	errInvalidTodoID      = status.Error(codes.InvalidArgument, "invalid todo id")
+	errInvalidLabelID     = status.Error(codes.InvalidArgument, "invalid label id")
+	errInvalidWorkspaceID = status.Error(codes.InvalidArgument, "invalid workspace id")
+)
+
+// convertError переводит ошибки сервиса в grpc-статусы, чтобы клиент мог их различить.
+// Ошибки валидации сами реализуют GRPCStatus и возвращаются как есть.
+func convertError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, app_errors.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, app_errors.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, app_errors.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, app_errors.ErrTodoBlocked):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, app_errors.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
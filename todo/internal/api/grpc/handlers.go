@@ -2,113 +2,759 @@ package grpc
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"google.golang.org/protobuf/types/known/emptypb"
-	"strconv"
 	"todo/internal/models"
 	"todo/pkg/ctxutil"
 	"todo/pkg/grpc_stubs/todo"
 )
 
 func (s *server) CreateToDo(ctx context.Context, newTodo *todo.CreateTodoDTO) (*todo.TodoID, error) {
-	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 	newT := models.NewEmptyCreateTodoDTO().FromGRPC(newTodo)
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateToDo")
 	defer span.Finish()
 
 	id, err := s.todoService.CreateToDo(ctx, newT)
-
-	requestId, _ := ctxutil.GetUserIDFromContext(ctx)
-	s.logger.Info().Msgf("request id %s", requestId)
-
 	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
-			Str("requestId", strconv.Itoa(requestId)).
-			Msgf("[CreateToDo]: %w", err)
-		return nil, err
+			Str("requestId", requestId).
+			Msgf("[CreateToDo]: %s", err)
+		return nil, convertError(err)
 	}
 
-	return &todo.TodoID{Id: int32(id)}, nil
+	return &todo.TodoID{Id: id.String()}, nil
 }
 
 func (s *server) UpdateToDo(ctx context.Context, updateTodo *todo.UpdateTodoDTO) (*todo.TodoID, error) {
-	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 	upd := models.NewEmptyUpdateTodoDTO().FromGRPC(updateTodo)
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateToDo")
 	defer span.Finish()
 
-	requestId, _ := ctxutil.GetUserIDFromContext(ctx)
-	s.logger.Info().Msgf("request id %s", requestId)
-
 	result, err := s.todoService.UpdateToDo(ctx, upd)
 	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
-			Str("requestId", strconv.Itoa(requestId)).
-			Msgf("[UpdateToDo]: %w", err)
-		return nil, err
+			Str("requestId", requestId).
+			Msgf("[UpdateToDo]: %s", err)
+		return nil, convertError(err)
 	}
 
-	return &todo.TodoID{Id: int32(result)}, nil
+	return &todo.TodoID{Id: result.String()}, nil
 }
 
 func (s *server) GetToDos(ctx context.Context, getTodos *todo.TodoID) (*todo.TodoDTO, error) {
-	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 
-	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateToDo")
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetToDos")
 	defer span.Finish()
 
-	requestId, _ := ctxutil.GetUserIDFromContext(ctx)
-	s.logger.Info().Msgf("request id %s", requestId)
+	todoID, err := uuid.Parse(getTodos.Id)
+	if err != nil {
+		return nil, errInvalidTodoID
+	}
 
-	_, err := s.todoService.GetToDo(ctx, int(getTodos.Id))
+	_, err = s.todoService.GetToDos(ctx, todoID)
 	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
-			Str("requestId", strconv.Itoa(requestId)).
-			Msgf("[GetToDos]: %w", err)
-		return nil, err
+			Str("requestId", requestId).
+			Msgf("[GetToDos]: %s", err)
+		return nil, convertError(err)
 	}
 
 	return nil, nil //TODO fix
 }
 
 func (s *server) GetToDo(ctx context.Context, getTodo *todo.TodoID) (*todo.TodoDTO, error) {
-	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 
-	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateToDo")
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetToDo")
 	defer span.Finish()
 
-	requestId, _ := ctxutil.GetUserIDFromContext(ctx)
-	s.logger.Info().Msgf("request id %s", requestId)
+	todoID, err := uuid.Parse(getTodo.Id)
+	if err != nil {
+		return nil, errInvalidTodoID
+	}
 
-	_, err := s.todoService.GetToDo(ctx, int(getTodo.Id))
+	result, err := s.todoService.GetToDo(ctx, todoID)
 	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
-			Str("requestId", strconv.Itoa(requestId)).
-			Msgf("[GetToDo]: %w", err)
-		return nil, err
+			Str("requestId", requestId).
+			Msgf("[GetToDo]: %s", err)
+		return nil, convertError(err)
 	}
 
-	return nil, nil //TODO fix
+	return result.ToGRPC(), nil
 }
 
-func (s *server) DeleteToDo(ctx context.Context, todoID *todo.TodoID) (*emptypb.Empty, error) {
-	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+func (s *server) DeleteToDo(ctx context.Context, deleteTodo *todo.DeleteTodoRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DeleteToDo")
 	defer span.Finish()
 
-	requestId, _ := ctxutil.GetUserIDFromContext(ctx)
-	s.logger.Info().Msgf("request id %s", requestId)
+	todoID, err := uuid.Parse(deleteTodo.Id)
+	if err != nil {
+		return nil, errInvalidTodoID
+	}
+
+	err = s.todoService.DeleteToDo(ctx, todoID, int(deleteTodo.Version))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteToDo]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) GetUserToDos(ctx context.Context, userID *todo.UserID) (*todo.TodoList, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetUserToDos")
+	defer span.Finish()
+
+	todos, err := s.todoService.GetUserToDos(ctx, int(userID.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetUserToDos]: %s", err)
+		return nil, convertError(err)
+	}
+
+	res := &todo.TodoList{Todos: make([]*todo.TodoDTO, 0, len(todos))}
+	for _, t := range todos {
+		res.Todos = append(res.Todos, t.ToGRPC())
+	}
+
+	return res, nil
+}
+
+func (s *server) AddComment(ctx context.Context, comment *todo.CreateCommentDTO) (*todo.CommentDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	newComment := models.NewEmptyCreateCommentDTO().FromGRPC(comment)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.AddComment")
+	defer span.Finish()
+
+	result, err := s.todoService.AddComment(ctx, newComment)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AddComment]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) EditComment(ctx context.Context, comment *todo.UpdateCommentDTO) (*todo.CommentDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	upd := models.NewEmptyUpdateCommentDTO().FromGRPC(comment)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.EditComment")
+	defer span.Finish()
+
+	result, err := s.todoService.EditComment(ctx, upd)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[EditComment]: %s", err)
+		return nil, convertError(err)
+	}
 
-	err := s.todoService.DeleteToDo(ctx, int(todoID.Id))
+	return result.ToGRPC(), nil
+}
+
+func (s *server) DeleteComment(ctx context.Context, comment *todo.DeleteCommentDTO) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	del := models.NewEmptyDeleteCommentDTO().FromGRPC(comment)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DeleteComment")
+	defer span.Finish()
+
+	err := s.todoService.DeleteComment(ctx, del)
 	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
-			Str("requestId", strconv.Itoa(requestId)).
-			Msgf("[DeleteToDo]: %w", err)
-		return nil, err
+			Str("requestId", requestId).
+			Msgf("[DeleteComment]: %s", err)
+		return nil, convertError(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
+
+func (s *server) ListComments(ctx context.Context, request *todo.ListCommentsRequest) (*todo.CommentList, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	list := models.NewEmptyListCommentsDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListComments")
+	defer span.Finish()
+
+	result, err := s.todoService.ListComments(ctx, list)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListComments]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) ListTodoHistory(ctx context.Context, request *todo.ListTodoHistoryRequest) (*todo.TodoEventList, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	list := models.NewEmptyListTodoHistoryDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListTodoHistory")
+	defer span.Finish()
+
+	result, err := s.todoService.ListTodoHistory(ctx, list)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListTodoHistory]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) BulkUpdateTodos(ctx context.Context, request *todo.BulkUpdateTodosRequest) (*todo.BulkUpdateTodosResult, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	bulk := models.NewEmptyBulkUpdateTodosDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.BulkUpdateTodos")
+	defer span.Finish()
+
+	result, err := s.todoService.BulkUpdateTodos(ctx, bulk)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[BulkUpdateTodos]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) ExportTodos(request *todo.ListTodosRequest, stream todo.TodoService_ExportTodosServer) error {
+	ctx := ctxutil.SetRequestIdFromGrpcToContext(stream.Context())
+	filter := models.NewEmptyListTodosDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ExportTodos")
+	defer span.Finish()
+
+	err := s.todoService.ExportTodos(ctx, filter, func(t *models.TodoDTO) error {
+		return stream.Send(t.ToGRPC())
+	})
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ExportTodos]: %s", err)
+		return convertError(err)
+	}
+
+	return nil
+}
+
+func (s *server) ImportTodos(ctx context.Context, request *todo.ImportTodosRequest) (*todo.ImportTodosResult, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	importTodos := models.NewEmptyImportTodosDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ImportTodos")
+	defer span.Finish()
+
+	result, err := s.todoService.ImportTodos(ctx, importTodos)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ImportTodos]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) ListToDos(ctx context.Context, request *todo.ListTodosRequest) (*todo.TodoList, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	filter := models.NewEmptyListTodosDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListToDos")
+	defer span.Finish()
+
+	todos, err := s.todoService.ListToDos(ctx, filter)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListToDos]: %s", err)
+		return nil, convertError(err)
+	}
+
+	res := &todo.TodoList{Todos: make([]*todo.TodoDTO, 0, len(todos))}
+	for _, t := range todos {
+		res.Todos = append(res.Todos, t.ToGRPC())
+	}
+
+	return res, nil
+}
+
+func (s *server) CreateLabel(ctx context.Context, label *todo.CreateLabelDTO) (*todo.LabelDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	newLabel := models.NewEmptyCreateLabelDTO().FromGRPC(label)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateLabel")
+	defer span.Finish()
+
+	result, err := s.todoService.CreateLabel(ctx, newLabel)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateLabel]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) UpdateLabel(ctx context.Context, label *todo.UpdateLabelDTO) (*todo.LabelDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	upd := models.NewEmptyUpdateLabelDTO().FromGRPC(label)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateLabel")
+	defer span.Finish()
+
+	result, err := s.todoService.UpdateLabel(ctx, upd)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateLabel]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) DeleteLabel(ctx context.Context, label *todo.LabelRef) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DeleteLabel")
+	defer span.Finish()
+
+	labelID, err := uuid.Parse(label.Id)
+	if err != nil {
+		return nil, errInvalidLabelID
+	}
+
+	err = s.todoService.DeleteLabel(ctx, labelID, int(label.Owner))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteLabel]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) GetLabel(ctx context.Context, label *todo.LabelID) (*todo.LabelDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetLabel")
+	defer span.Finish()
+
+	labelID, err := uuid.Parse(label.Id)
+	if err != nil {
+		return nil, errInvalidLabelID
+	}
+
+	result, err := s.todoService.GetLabel(ctx, labelID)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetLabel]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) ListLabels(ctx context.Context, request *todo.ListLabelsRequest) (*todo.LabelList, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	list := models.NewEmptyListLabelsDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListLabels")
+	defer span.Finish()
+
+	labels, err := s.todoService.ListLabels(ctx, list)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListLabels]: %s", err)
+		return nil, convertError(err)
+	}
+
+	res := &todo.LabelList{Labels: make([]*todo.LabelDTO, 0, len(labels))}
+	for _, l := range labels {
+		res.Labels = append(res.Labels, l.ToGRPC())
+	}
+
+	return res, nil
+}
+
+func (s *server) AddTodoLabel(ctx context.Context, request *todo.TodoLabelRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	add := models.NewEmptyTodoLabelDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.AddTodoLabel")
+	defer span.Finish()
+
+	if err := s.todoService.AddTodoLabel(ctx, add); err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AddTodoLabel]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) RemoveTodoLabel(ctx context.Context, request *todo.TodoLabelRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	remove := models.NewEmptyTodoLabelDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RemoveTodoLabel")
+	defer span.Finish()
+
+	if err := s.todoService.RemoveTodoLabel(ctx, remove); err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RemoveTodoLabel]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) SearchTodos(ctx context.Context, request *todo.SearchTodosRequest) (*todo.SearchResults, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	search := models.NewEmptySearchTodosDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.SearchTodos")
+	defer span.Finish()
+
+	results, err := s.todoService.SearchTodos(ctx, search)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SearchTodos]: %s", err)
+		return nil, convertError(err)
+	}
+
+	res := &todo.SearchResults{Results: make([]*todo.SearchResult, 0, len(results))}
+	for _, r := range results {
+		res.Results = append(res.Results, r.ToGRPC())
+	}
+
+	return res, nil
+}
+
+func (s *server) ListTodoTree(ctx context.Context, request *todo.ListTodosRequest) (*todo.TodoTree, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	filter := models.NewEmptyListTodosDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListTodoTree")
+	defer span.Finish()
+
+	nodes, err := s.todoService.ListTodoTree(ctx, filter)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListTodoTree]: %s", err)
+		return nil, convertError(err)
+	}
+
+	res := &todo.TodoTree{Nodes: make([]*todo.TodoNode, 0, len(nodes))}
+	for _, n := range nodes {
+		res.Nodes = append(res.Nodes, n.ToGRPC())
+	}
+
+	return res, nil
+}
+
+func (s *server) AddTodoDependency(ctx context.Context, request *todo.TodoDependencyRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	dependency := models.NewEmptyTodoDependencyDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.AddTodoDependency")
+	defer span.Finish()
+
+	if err := s.todoService.AddTodoDependency(ctx, dependency); err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AddTodoDependency]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) RemoveTodoDependency(ctx context.Context, request *todo.TodoDependencyRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	dependency := models.NewEmptyTodoDependencyDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RemoveTodoDependency")
+	defer span.Finish()
+
+	if err := s.todoService.RemoveTodoDependency(ctx, dependency); err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RemoveTodoDependency]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) CreateWorkspace(ctx context.Context, workspace *todo.CreateWorkspaceDTO) (*todo.WorkspaceDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	newWorkspace := models.NewEmptyCreateWorkspaceDTO().FromGRPC(workspace)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateWorkspace")
+	defer span.Finish()
+
+	result, err := s.todoService.CreateWorkspace(ctx, newWorkspace)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateWorkspace]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) UpdateWorkspace(ctx context.Context, workspace *todo.UpdateWorkspaceDTO) (*todo.WorkspaceDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	upd := models.NewEmptyUpdateWorkspaceDTO().FromGRPC(workspace)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateWorkspace")
+	defer span.Finish()
+
+	result, err := s.todoService.UpdateWorkspace(ctx, upd)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateWorkspace]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) DeleteWorkspace(ctx context.Context, workspace *todo.WorkspaceRef) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DeleteWorkspace")
+	defer span.Finish()
+
+	workspaceID, err := uuid.Parse(workspace.Id)
+	if err != nil {
+		return nil, errInvalidWorkspaceID
+	}
+
+	if err := s.todoService.DeleteWorkspace(ctx, workspaceID); err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteWorkspace]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) ListWorkspaces(ctx context.Context, userID *todo.UserID) (*todo.WorkspaceList, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListWorkspaces")
+	defer span.Finish()
+
+	workspaces, err := s.todoService.ListWorkspaces(ctx, int(userID.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListWorkspaces]: %s", err)
+		return nil, convertError(err)
+	}
+
+	res := &todo.WorkspaceList{Workspaces: make([]*todo.WorkspaceDTO, 0, len(workspaces))}
+	for _, w := range workspaces {
+		res.Workspaces = append(res.Workspaces, w.ToGRPC())
+	}
+
+	return res, nil
+}
+
+func (s *server) GetWorkspaceMember(ctx context.Context, ref *todo.WorkspaceMemberRef) (*todo.WorkspaceMemberDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetWorkspaceMember")
+	defer span.Finish()
+
+	workspaceID, err := uuid.Parse(ref.WorkspaceId)
+	if err != nil {
+		return nil, errInvalidWorkspaceID
+	}
+
+	member, err := s.todoService.GetWorkspaceMember(ctx, workspaceID, int(ref.UserId))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetWorkspaceMember]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return member.ToGRPC(), nil
+}
+
+func (s *server) ListWorkspaceMembers(ctx context.Context, workspace *todo.WorkspaceRef) (*todo.WorkspaceMemberList, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListWorkspaceMembers")
+	defer span.Finish()
+
+	workspaceID, err := uuid.Parse(workspace.Id)
+	if err != nil {
+		return nil, errInvalidWorkspaceID
+	}
+
+	members, err := s.todoService.ListWorkspaceMembers(ctx, workspaceID)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListWorkspaceMembers]: %s", err)
+		return nil, convertError(err)
+	}
+
+	res := &todo.WorkspaceMemberList{Members: make([]*todo.WorkspaceMemberDTO, 0, len(members))}
+	for _, m := range members {
+		res.Members = append(res.Members, m.ToGRPC())
+	}
+
+	return res, nil
+}
+
+func (s *server) UpdateWorkspaceMember(ctx context.Context, member *todo.WorkspaceMemberDTO) (*todo.WorkspaceMemberDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	upd := models.NewEmptyWorkspaceMemberDTO().FromGRPC(member)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateWorkspaceMember")
+	defer span.Finish()
+
+	result, err := s.todoService.UpdateWorkspaceMember(ctx, upd)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateWorkspaceMember]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) RemoveWorkspaceMember(ctx context.Context, ref *todo.WorkspaceMemberRef) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RemoveWorkspaceMember")
+	defer span.Finish()
+
+	workspaceID, err := uuid.Parse(ref.WorkspaceId)
+	if err != nil {
+		return nil, errInvalidWorkspaceID
+	}
+
+	if err := s.todoService.RemoveWorkspaceMember(ctx, workspaceID, int(ref.UserId)); err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RemoveWorkspaceMember]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) InviteWorkspaceMember(ctx context.Context, invitation *todo.InviteWorkspaceMemberDTO) (*todo.WorkspaceInvitationDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	invite := models.NewEmptyInviteWorkspaceMemberDTO().FromGRPC(invitation)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.InviteWorkspaceMember")
+	defer span.Finish()
+
+	result, err := s.todoService.InviteWorkspaceMember(ctx, invite)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[InviteWorkspaceMember]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return result.ToGRPC(), nil
+}
+
+func (s *server) AcceptWorkspaceInvitation(ctx context.Context, request *todo.AcceptWorkspaceInvitationDTO) (*todo.WorkspaceMemberDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	accept := models.NewEmptyAcceptWorkspaceInvitationDTO().FromGRPC(request)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.AcceptWorkspaceInvitation")
+	defer span.Finish()
+
+	member, err := s.todoService.AcceptWorkspaceInvitation(ctx, accept)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AcceptWorkspaceInvitation]: %s", err)
+		return nil, convertError(err)
+	}
+
+	return member.ToGRPC(), nil
+}
@@ -2,13 +2,47 @@ package api
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"todo/internal/models"
 )
 
 type TodoService interface {
-	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (int, error)
-	UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (int, error)
-	GetToDos(ctx context.Context, todoID int) (*models.TodoDTO, error)
-	GetToDo(ctx context.Context, todoID int) (*models.TodoDTO, error)
-	DeleteToDo(ctx context.Context, todoID int) error
+	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (uuid.UUID, error)
+	UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (uuid.UUID, error)
+	GetToDos(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error)
+	GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error)
+	DeleteToDo(ctx context.Context, todoID uuid.UUID, version int) error
+	GetUserToDos(ctx context.Context, userID int) ([]*models.TodoDTO, error)
+	HandleUserDeleted(ctx context.Context, userID int) error
+	AddComment(ctx context.Context, comment *models.CreateCommentDTO) (*models.CommentDTO, error)
+	EditComment(ctx context.Context, comment *models.UpdateCommentDTO) (*models.CommentDTO, error)
+	DeleteComment(ctx context.Context, comment *models.DeleteCommentDTO) error
+	ListComments(ctx context.Context, request *models.ListCommentsDTO) (*models.CommentListDTO, error)
+	ListTodoHistory(ctx context.Context, request *models.ListTodoHistoryDTO) (*models.TodoEventListDTO, error)
+	BulkUpdateTodos(ctx context.Context, request *models.BulkUpdateTodosDTO) (*models.BulkUpdateTodosResultDTO, error)
+	ExportTodos(ctx context.Context, filter *models.ListTodosDTO, send func(todo *models.TodoDTO) error) error
+	ImportTodos(ctx context.Context, request *models.ImportTodosDTO) (*models.ImportTodosResultDTO, error)
+	ListToDos(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoDTO, error)
+	CreateLabel(ctx context.Context, label *models.CreateLabelDTO) (*models.LabelDTO, error)
+	UpdateLabel(ctx context.Context, label *models.UpdateLabelDTO) (*models.LabelDTO, error)
+	DeleteLabel(ctx context.Context, labelID uuid.UUID, owner int) error
+	GetLabel(ctx context.Context, labelID uuid.UUID) (*models.LabelDTO, error)
+	ListLabels(ctx context.Context, request *models.ListLabelsDTO) ([]*models.LabelDTO, error)
+	AddTodoLabel(ctx context.Context, request *models.TodoLabelDTO) error
+	RemoveTodoLabel(ctx context.Context, request *models.TodoLabelDTO) error
+	SearchTodos(ctx context.Context, request *models.SearchTodosDTO) ([]*models.SearchResultDTO, error)
+	ListTodoTree(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoNodeDTO, error)
+	AddTodoDependency(ctx context.Context, dependency *models.TodoDependencyDTO) error
+	RemoveTodoDependency(ctx context.Context, dependency *models.TodoDependencyDTO) error
+	GenerateDueOccurrences(ctx context.Context) (int, error)
+	CreateWorkspace(ctx context.Context, workspace *models.CreateWorkspaceDTO) (*models.WorkspaceDTO, error)
+	UpdateWorkspace(ctx context.Context, workspace *models.UpdateWorkspaceDTO) (*models.WorkspaceDTO, error)
+	DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) error
+	ListWorkspaces(ctx context.Context, userID int) ([]*models.WorkspaceDTO, error)
+	GetWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) (*models.WorkspaceMemberDTO, error)
+	ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMemberDTO, error)
+	UpdateWorkspaceMember(ctx context.Context, member *models.WorkspaceMemberDTO) (*models.WorkspaceMemberDTO, error)
+	RemoveWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) error
+	InviteWorkspaceMember(ctx context.Context, invitation *models.InviteWorkspaceMemberDTO) (*models.WorkspaceInvitationDTO, error)
+	AcceptWorkspaceInvitation(ctx context.Context, accept *models.AcceptWorkspaceInvitationDTO) (*models.WorkspaceMemberDTO, error)
 }
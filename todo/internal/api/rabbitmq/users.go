@@ -0,0 +1,68 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"todo/internal/api"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+	rabbitConsumer "todo/pkg/rabbitmq/consumer"
+)
+
+type UserEventsHandler struct {
+	logger      *zerolog.Logger
+	todoService api.TodoService
+}
+
+func NewUserEventsHandler(
+	logger *zerolog.Logger,
+	todoService api.TodoService,
+) *UserEventsHandler {
+	return &UserEventsHandler{
+		logger:      logger,
+		todoService: todoService,
+	}
+}
+
+// Handle обрабатывает события пользователей по порядку.
+// Битые сообщения отклоняются, ошибка базы возвращает всю пачку в очередь.
+func (h *UserEventsHandler) Handle(ctx context.Context, messages []amqp.Delivery) (uint64, []rabbitConsumer.Error, error) {
+	var lastSuccessTag uint64
+	handleErrors := make([]rabbitConsumer.Error, 0)
+
+	for _, msg := range messages {
+		var event models.UserEvent
+		if err := json.Unmarshal(msg.Body, &event); err != nil {
+			handleErrors = append(handleErrors, rabbitConsumer.Error{
+				Msg: msg,
+				Err: fmt.Errorf("[UserEventsHandler] unmarshal:%w", err),
+			})
+			continue
+		}
+
+		requestID, _ := msg.Headers["requestId"].(string)
+		msgCtx := ctxutil.SetRequestIDToContext(ctx, requestID)
+
+		switch event.EventType {
+		case models.UserEventTypeDeleted:
+			if err := h.todoService.HandleUserDeleted(msgCtx, event.UserID); err != nil {
+				return 0, nil, fmt.Errorf("[UserEventsHandler] user %d deleted:%w", event.UserID, err)
+			}
+
+			h.logger.Info().
+				Str("requestId", requestID).
+				Msgf("todos of deleted user %d detached", event.UserID)
+		default:
+			h.logger.Debug().
+				Str("requestId", requestID).
+				Msgf("skip user event %q", event.EventType)
+		}
+
+		lastSuccessTag = msg.DeliveryTag
+	}
+
+	return lastSuccessTag, handleErrors, nil
+}
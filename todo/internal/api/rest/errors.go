@@ -1,5 +1,7 @@
 package rest
 
+import "todo/pkg/validator"
+
 type ErrorCode string
 
 const (
@@ -8,11 +10,13 @@ const (
 	ErrCodeBadRequest             ErrorCode = "BAD_REQUEST"
 	ErrCodeNotFound               ErrorCode = "NOT_FOUND"
 	ErrCodeInvalidJsonFormat      ErrorCode = "JSON_FORMAT_ERROR"
+	ErrCodePreconditionFailed     ErrorCode = "PRECONDITION_FAILED"
 )
 
 type ApiError struct {
-	Message string
-	ErrCode ErrorCode
+	Message    string
+	ErrCode    ErrorCode
+	Violations []validator.FieldViolation
 }
 
 func NewApiError(message string, errCode ErrorCode) *ApiError {
@@ -43,6 +47,7 @@ var (
 	ErrUsernameOrEmailAlreadyUsed = NewApiError("username or email already used", ErrCodeBadRequest)
 	ErrWrongCredentials           = NewApiError("wrong credentials", ErrCodeBadRequest)
 	ErrNotFound                   = NewApiError("not found", ErrCodeNotFound)
+	ErrVersionConflict            = NewApiError("todo was modified by another request", ErrCodePreconditionFailed)
 )
 
 func (e *ApiError) IsRequestValidationError() bool {
@@ -2,12 +2,16 @@ package rest
 
 import (
 	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
 	"net/http"
 	"strconv"
 	"todo/internal/api"
+	"todo/internal/app_errors"
 	"todo/internal/models"
+	"todo/pkg/validator"
 )
 
 type TodoHandler struct {
@@ -37,6 +41,12 @@ func (h *TodoHandler) CreateToDoHandler(w http.ResponseWriter, r *http.Request)
 
 	todoID, err := h.todoService.CreateToDo(ctx, newTodo)
 	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
 		h.logger.Err(err).Msg("[CreateToDoHandler] error create")
 		h.ErrorInternalApi(w)
 		return
@@ -48,7 +58,7 @@ func (h *TodoHandler) CreateToDoHandler(w http.ResponseWriter, r *http.Request)
 func (h *TodoHandler) GetToDoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
+	todoID, err := uuid.Parse(mux.Vars(r)["id"])
 	if err != nil {
 		h.logger.Err(err).Msg("[GetToDoHandler] error parse uuid")
 		h.ErrorBadRequest(w)
@@ -57,6 +67,11 @@ func (h *TodoHandler) GetToDoHandler(w http.ResponseWriter, r *http.Request) {
 
 	todo, err := h.todoService.GetToDo(ctx, todoID)
 	if err != nil {
+		if errors.Is(err, app_errors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
 		h.logger.Err(err).Msg("[GetToDoHandler] error get todo")
 		h.ErrorInternalApi(w)
 		return
@@ -68,7 +83,7 @@ func (h *TodoHandler) GetToDoHandler(w http.ResponseWriter, r *http.Request) {
 func (h *TodoHandler) GetToDosHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
+	todoID, err := uuid.Parse(mux.Vars(r)["id"])
 	if err != nil {
 		h.logger.Err(err).Msg("[GetToDosHandler] error parse uuid")
 		h.ErrorBadRequest(w)
@@ -88,15 +103,39 @@ func (h *TodoHandler) GetToDosHandler(w http.ResponseWriter, r *http.Request) {
 func (h *TodoHandler) UpdateToDoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	todoID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Err(err).Msg("[UpdateToDoHandler] error parse uuid")
+		h.ErrorBadRequest(w)
+		return
+	}
+
 	var updTodo = new(models.UpdateTodoDTO)
 	if err := json.NewDecoder(r.Body).Decode(&updTodo); err != nil {
 		h.logger.Err(err).Msg("[UpdateToDoHandler] error unmarshal")
 		h.ErrorBadRequest(w)
 		return
 	}
+	updTodo.ID = todoID
 
-	todoID, err := h.todoService.UpdateToDo(ctx, updTodo)
+	todoID, err = h.todoService.UpdateToDo(ctx, updTodo)
 	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, app_errors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		if errors.Is(err, app_errors.ErrVersionConflict) {
+			h.ErrorVersionConflict(w)
+			return
+		}
+
 		h.logger.Err(err).Msg("[UpdateToDoHandler] error update todo")
 		h.ErrorInternalApi(w)
 		return
@@ -108,14 +147,31 @@ func (h *TodoHandler) UpdateToDoHandler(w http.ResponseWriter, r *http.Request)
 func (h *TodoHandler) DeleteToDoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	todoID, err := strconv.Atoi(mux.Vars(r)["id"])
+	todoID, err := uuid.Parse(mux.Vars(r)["id"])
 	if err != nil {
 		h.logger.Err(err).Msg("[GetToDosHandler] error parse uuid")
 		h.ErrorBadRequest(w)
 		return
 	}
 
-	if err := h.todoService.DeleteToDo(ctx, todoID); err != nil {
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil {
+		h.logger.Err(err).Msg("[DeleteToDoHandler] error parse version")
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	if err := h.todoService.DeleteToDo(ctx, todoID, version); err != nil {
+		if errors.Is(err, app_errors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		if errors.Is(err, app_errors.ErrVersionConflict) {
+			h.ErrorVersionConflict(w)
+			return
+		}
+
 		h.logger.Err(err).Msg("[DeleteToDoHandler] error delete todo")
 		h.ErrorInternalApi(w)
 		return
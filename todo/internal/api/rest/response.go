@@ -3,11 +3,13 @@ package rest
 import (
 	"encoding/json"
 	"net/http"
+	"todo/pkg/validator"
 )
 
 type ErrorResponse struct {
-	ErrorMessage string `json:"errorMessage"`
-	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string                     `json:"errorMessage"`
+	ErrorCode    string                     `json:"errorCode"`
+	Violations   []validator.FieldViolation `json:"violations,omitempty"`
 }
 
 func (h *TodoHandler) ErrorBadRequest(w http.ResponseWriter) {
@@ -18,6 +20,17 @@ func (h *TodoHandler) ErrorNotFound(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusNotFound, ErrNotFound)
 }
 
+func (h *TodoHandler) ErrorVersionConflict(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusPreconditionFailed, ErrVersionConflict)
+}
+
+func (h *TodoHandler) ErrorValidation(w http.ResponseWriter, validationErr *validator.ValidationError) {
+	err := NewApiError("request validation failed", ErrCodeRequestValidationError)
+	err.Violations = validationErr.Violations
+
+	h.JSONErrorRespond(w, http.StatusBadRequest, err)
+}
+
 func (h *TodoHandler) ErrorInternalApi(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusInternalServerError, ErrInternalApi)
 }
@@ -33,6 +46,7 @@ func (h *TodoHandler) JSONErrorRespond(w http.ResponseWriter, httpCode int, err
 	data := ErrorResponse{
 		ErrorCode:    string(err.ErrCode),
 		ErrorMessage: err.Error(),
+		Violations:   err.Violations,
 	}
 
 	rawData, marshalErr := json.Marshal(data)
@@ -5,6 +5,12 @@ import "errors"
 var (
 	ErrUsernameOrEmailIsUsed           = errors.New("username or email already used")
 	ErrNotFound                        = errors.New("not found")
+	ErrForbidden                       = errors.New("forbidden")
+	ErrAlreadyExists                   = errors.New("already exists")
+	ErrTodoBlocked                     = errors.New("todo has open blockers")
+	ErrLastWorkspaceOwner              = errors.New("workspace must keep at least one owner")
+	ErrVersionConflict                 = errors.New("todo was modified by another request")
+	ErrCycle                           = errors.New("change would create a cycle")
 	ErrWrongCredentials                = errors.New("wrong credentials")
 	ErrIncorrectOldPassword            = errors.New("incorrect old password")
 	ErrPassAndConfirmationDoesNotMatch = errors.New("password and confirmation does not match")
@@ -0,0 +1,62 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"todo/config"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+	"todo/pkg/grpc_stubs/users"
+)
+
+type UsersClient struct {
+	client users.UserServiceClient
+}
+
+func NewUsersClient(cfg *config.Config, logger *zerolog.Logger) (*UsersClient, error) {
+	appAddr := fmt.Sprintf("%s:%s", cfg.UsersClient.AppHost, cfg.UsersClient.AppGrpcPort)
+
+	logger.Info().Msgf("[NewUsersClient] connecting via GRPC to users at %s", appAddr)
+
+	conn, err := grpc.Dial(
+		appAddr,
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("[NewUsersClient] connect to users GRPC service: %w", err)
+	}
+
+	return &UsersClient{
+		client: users.NewUserServiceClient(conn),
+	}, nil
+}
+
+func (c *UsersClient) GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetUserByID")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	user, err := c.client.GetUserByID(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+// convertError переводит статусы grpc-ответа сервиса пользователей в ошибки приложения
+func convertError(err error) error {
+	if status.Code(err) == codes.NotFound {
+		return app_errors.ErrNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"todo/pkg/grpc_stubs/todo"
+)
+
+// операции над несколькими задачами
+const (
+	BulkOperationReassign = "reassign"
+	BulkOperationRelabel  = "relabel"
+	BulkOperationComplete = "complete"
+	BulkOperationDelete   = "delete"
+)
+
+const (
+	// BulkMaxItems - сколько задач можно изменить одним запросом
+	BulkMaxItems = 500
+	// BulkChunkSize - сколько задач сохраняется одной транзакцией
+	BulkChunkSize = 100
+)
+
+// причины, по которым операция не применилась к задаче
+const (
+	BulkErrorNotFound        = "not_found"
+	BulkErrorVersionConflict = "version_conflict"
+	BulkErrorBlocked         = "blocked"
+	// BulkErrorLabelNotInWorkspace - добавляемая метка из другого рабочего пространства, чем задача
+	BulkErrorLabelNotInWorkspace = "label_not_in_workspace"
+)
+
+type BulkUpdateTodosDTO struct {
+	Actor     int         `json:"actor" validate:"required,min=1"`
+	IDs       []uuid.UUID `json:"ids" validate:"required,min=1,max=500"`
+	Operation string      `json:"operation" validate:"required,oneof=reassign relabel complete delete"`
+	Assignee  int         `json:"assignee" validate:"required_if=Operation reassign,min=0"`
+	// AddLabelIDs и RemoveLabelIDs - личные метки отправителя или метки рабочего пространства для relabel
+	AddLabelIDs    []uuid.UUID `json:"add_label_ids" validate:"max=50"`
+	RemoveLabelIDs []uuid.UUID `json:"remove_label_ids" validate:"max=50"`
+}
+
+func NewEmptyBulkUpdateTodosDTO() *BulkUpdateTodosDTO {
+	return &BulkUpdateTodosDTO{}
+}
+
+func (b *BulkUpdateTodosDTO) FromGRPC(in *todo.BulkUpdateTodosRequest) *BulkUpdateTodosDTO {
+	b.Actor = int(in.Actor)
+	b.IDs = stringsToUUIDs(in.Ids)
+	b.Operation = in.Operation
+	b.Assignee = int(in.Assignee)
+	b.AddLabelIDs = stringsToUUIDs(in.AddLabelIds)
+	b.RemoveLabelIDs = stringsToUUIDs(in.RemoveLabelIds)
+	return b
+}
+
+// BulkItemResultDTO - результат операции над одной задачей, Error пустой при успехе
+type BulkItemResultDTO struct {
+	ID    uuid.UUID `json:"id"`
+	Error string    `json:"error,omitempty"`
+}
+
+type BulkUpdateTodosResultDTO struct {
+	Results   []BulkItemResultDTO `json:"results"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
+
+// add добавляет результат по задаче и обновляет счетчики
+func (r *BulkUpdateTodosResultDTO) add(id uuid.UUID, errCode string) {
+	r.Results = append(r.Results, BulkItemResultDTO{ID: id, Error: errCode})
+	if errCode == "" {
+		r.Succeeded++
+	} else {
+		r.Failed++
+	}
+}
+
+// Succeed отмечает задачу как измененную
+func (r *BulkUpdateTodosResultDTO) Succeed(id uuid.UUID) {
+	r.add(id, "")
+}
+
+// Fail отмечает задачу, к которой операция не применилась
+func (r *BulkUpdateTodosResultDTO) Fail(id uuid.UUID, errCode string) {
+	r.add(id, errCode)
+}
+
+func (r *BulkUpdateTodosResultDTO) ToGRPC() *todo.BulkUpdateTodosResult {
+	res := &todo.BulkUpdateTodosResult{
+		Results:   make([]*todo.BulkItemResult, 0, len(r.Results)),
+		Succeeded: int32(r.Succeeded),
+		Failed:    int32(r.Failed),
+	}
+	for _, item := range r.Results {
+		res.Results = append(res.Results, &todo.BulkItemResult{Id: item.ID.String(), Error: item.Error})
+	}
+
+	return res
+}
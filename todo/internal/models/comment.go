@@ -0,0 +1,137 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"todo/pkg/grpc_stubs/todo"
+)
+
+// лимиты постраничного вывода комментариев
+const (
+	CommentsDefaultLimit = 50
+	CommentsMaxLimit     = 200
+)
+
+type CommentDAO struct {
+	ID        uuid.UUID  `db:"id"`
+	TodoID    uuid.UUID  `db:"todo_id"`
+	Author    int        `db:"author"`
+	Body      string     `db:"body"`
+	CreatedAt time.Time  `db:"created_at"`
+	EditedAt  *time.Time `db:"edited_at"`
+}
+
+type CommentDTO struct {
+	ID        uuid.UUID  `json:"id" example:"0b6f1c9e-3c2a-4f5e-9d7b-8a1e2f3c4d5e"`
+	TodoID    uuid.UUID  `json:"todo_id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	Author    int        `json:"author" example:"1"`
+	Body      string     `json:"body" example:"comment text"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+}
+
+func (c *CommentDTO) ToGRPC() *todo.CommentDTO {
+	res := &todo.CommentDTO{
+		Id:        c.ID.String(),
+		TodoId:    c.TodoID.String(),
+		Author:    int32(c.Author),
+		Body:      c.Body,
+		CreatedAt: timestamppb.New(c.CreatedAt),
+	}
+	if c.EditedAt != nil {
+		res.EditedAt = timestamppb.New(*c.EditedAt)
+	}
+
+	return res
+}
+
+type CreateCommentDTO struct {
+	TodoID uuid.UUID `json:"todo_id" validate:"required"`
+	Author int       `json:"author" validate:"required,min=1"`
+	Body   string    `json:"body" validate:"comment"`
+}
+
+func NewEmptyCreateCommentDTO() *CreateCommentDTO {
+	return &CreateCommentDTO{}
+}
+
+func (c *CreateCommentDTO) FromGRPC(in *todo.CreateCommentDTO) *CreateCommentDTO {
+	c.TodoID, _ = uuid.Parse(in.TodoId)
+	c.Author = int(in.Author)
+	c.Body = in.Body
+	return c
+}
+
+type UpdateCommentDTO struct {
+	ID     uuid.UUID `json:"id" validate:"required"`
+	TodoID uuid.UUID `json:"todo_id" validate:"required"`
+	Author int       `json:"author" validate:"required,min=1"`
+	Body   string    `json:"body" validate:"comment"`
+}
+
+func NewEmptyUpdateCommentDTO() *UpdateCommentDTO {
+	return &UpdateCommentDTO{}
+}
+
+func (c *UpdateCommentDTO) FromGRPC(in *todo.UpdateCommentDTO) *UpdateCommentDTO {
+	c.ID, _ = uuid.Parse(in.Id)
+	c.TodoID, _ = uuid.Parse(in.TodoId)
+	c.Author = int(in.Author)
+	c.Body = in.Body
+	return c
+}
+
+type DeleteCommentDTO struct {
+	ID        uuid.UUID
+	TodoID    uuid.UUID
+	DeletedBy int
+	// Moderator - удалить можно и чужой комментарий
+	Moderator bool
+}
+
+func NewEmptyDeleteCommentDTO() *DeleteCommentDTO {
+	return &DeleteCommentDTO{}
+}
+
+func (c *DeleteCommentDTO) FromGRPC(in *todo.DeleteCommentDTO) *DeleteCommentDTO {
+	c.ID, _ = uuid.Parse(in.Id)
+	c.TodoID, _ = uuid.Parse(in.TodoId)
+	c.DeletedBy = int(in.DeletedBy)
+	c.Moderator = in.Moderator
+	return c
+}
+
+type ListCommentsDTO struct {
+	TodoID uuid.UUID `json:"todo_id" validate:"required"`
+	Limit  int       `json:"limit" validate:"min=0,max=200"`
+	Offset int       `json:"offset" validate:"min=0"`
+}
+
+func NewEmptyListCommentsDTO() *ListCommentsDTO {
+	return &ListCommentsDTO{}
+}
+
+func (c *ListCommentsDTO) FromGRPC(in *todo.ListCommentsRequest) *ListCommentsDTO {
+	c.TodoID, _ = uuid.Parse(in.TodoId)
+	c.Limit = int(in.Limit)
+	c.Offset = int(in.Offset)
+	return c
+}
+
+type CommentListDTO struct {
+	Comments []*CommentDTO `json:"comments"`
+	Total    int           `json:"total" example:"1"`
+}
+
+func (c *CommentListDTO) ToGRPC() *todo.CommentList {
+	res := &todo.CommentList{
+		Comments: make([]*todo.CommentDTO, 0, len(c.Comments)),
+		Total:    int32(c.Total),
+	}
+	for _, comment := range c.Comments {
+		res.Comments = append(res.Comments, comment.ToGRPC())
+	}
+
+	return res
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"todo/pkg/grpc_stubs/todo"
+)
+
+// лимиты постраничного вывода истории задачи
+const (
+	TodoHistoryDefaultLimit = 50
+	TodoHistoryMaxLimit     = 200
+)
+
+// поля задачи, изменения которых попадают в историю
+const (
+	TodoFieldAssignee    = "assignee"
+	TodoFieldDescription = "description"
+	TodoFieldParentID    = "parent_id"
+	TodoFieldDone        = "done"
+	TodoFieldDueAt       = "due_at"
+	TodoFieldRecurrence  = "recurrence"
+	TodoFieldTimezone    = "timezone"
+)
+
+// TodoEventDAO - изменение одного поля задачи. Значения хранятся строками,
+// пустая строка означает, что поле не было задано
+type TodoEventDAO struct {
+	ID        uuid.UUID `db:"id"`
+	TodoID    uuid.UUID `db:"todo_id"`
+	Actor     int       `db:"actor"`
+	Field     string    `db:"field"`
+	OldValue  string    `db:"old_value"`
+	NewValue  string    `db:"new_value"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type TodoEventDTO struct {
+	ID        uuid.UUID `json:"id" example:"3f1e2d4c-5b6a-4978-8a9b-0c1d2e3f4a5b"`
+	TodoID    uuid.UUID `json:"todo_id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	Actor     int       `json:"actor" example:"1"`
+	Field     string    `json:"field" example:"assignee"`
+	OldValue  string    `json:"old_value" example:"2"`
+	NewValue  string    `json:"new_value" example:"3"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (e *TodoEventDTO) ToGRPC() *todo.TodoEventDTO {
+	return &todo.TodoEventDTO{
+		Id:        e.ID.String(),
+		TodoId:    e.TodoID.String(),
+		Actor:     int32(e.Actor),
+		Field:     e.Field,
+		OldValue:  e.OldValue,
+		NewValue:  e.NewValue,
+		CreatedAt: timestamppb.New(e.CreatedAt),
+	}
+}
+
+type ListTodoHistoryDTO struct {
+	TodoID uuid.UUID `json:"todo_id" validate:"required"`
+	Limit  int       `json:"limit" validate:"min=0,max=200"`
+	Offset int       `json:"offset" validate:"min=0"`
+}
+
+func NewEmptyListTodoHistoryDTO() *ListTodoHistoryDTO {
+	return &ListTodoHistoryDTO{}
+}
+
+func (h *ListTodoHistoryDTO) FromGRPC(in *todo.ListTodoHistoryRequest) *ListTodoHistoryDTO {
+	h.TodoID, _ = uuid.Parse(in.TodoId)
+	h.Limit = int(in.Limit)
+	h.Offset = int(in.Offset)
+	return h
+}
+
+type TodoEventListDTO struct {
+	Events []*TodoEventDTO `json:"events"`
+	Total  int             `json:"total" example:"1"`
+}
+
+func (l *TodoEventListDTO) ToGRPC() *todo.TodoEventList {
+	res := &todo.TodoEventList{
+		Events: make([]*todo.TodoEventDTO, 0, len(l.Events)),
+		Total:  int32(l.Total),
+	}
+	for _, event := range l.Events {
+		res.Events = append(res.Events, event.ToGRPC())
+	}
+
+	return res
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"todo/pkg/grpc_stubs/todo"
+	"todo/pkg/validator"
+)
+
+const (
+	// ExportPageSize - по сколько задач выгрузка читает из базы
+	ExportPageSize = 200
+	// ImportMaxRows - сколько задач можно импортировать одним запросом
+	ImportMaxRows = 1000
+)
+
+// ImportTodoRowDTO - задача из строки импортируемого файла
+type ImportTodoRowDTO struct {
+	Row  int            `json:"row"`
+	Todo *CreateTodoDTO `json:"todo"`
+}
+
+type ImportTodosDTO struct {
+	CreatedBy   int        `json:"created_by" validate:"required,min=1"`
+	WorkspaceID *uuid.UUID `json:"workspace_id"`
+	// DryRun - только проверить строки, ничего не сохраняя
+	DryRun bool                `json:"dry_run"`
+	Rows   []*ImportTodoRowDTO `json:"rows" validate:"max=1000"`
+}
+
+func NewEmptyImportTodosDTO() *ImportTodosDTO {
+	return &ImportTodosDTO{}
+}
+
+// FromGRPC - все задачи создаются отправителем запроса в указанном рабочем пространстве
+func (i *ImportTodosDTO) FromGRPC(in *todo.ImportTodosRequest) *ImportTodosDTO {
+	i.CreatedBy = int(in.CreatedBy)
+	i.WorkspaceID = stringToOptionalUUID(in.WorkspaceId)
+	i.DryRun = in.DryRun
+	i.Rows = make([]*ImportTodoRowDTO, 0, len(in.Rows))
+	for _, row := range in.Rows {
+		rowTodo := row.GetTodo()
+		if rowTodo == nil {
+			rowTodo = &todo.CreateTodoDTO{}
+		}
+
+		newTodo := NewEmptyCreateTodoDTO().FromGRPC(rowTodo)
+		newTodo.CreatedBy = i.CreatedBy
+		newTodo.WorkspaceID = i.WorkspaceID
+		newTodo.Done = row.Done
+
+		i.Rows = append(i.Rows, &ImportTodoRowDTO{Row: int(row.Row), Todo: newTodo})
+	}
+	return i
+}
+
+// ImportRowResultDTO - результат импорта строки: созданная задача или ошибки в ее полях
+type ImportRowResultDTO struct {
+	Row    int                        `json:"row"`
+	ID     *uuid.UUID                 `json:"id,omitempty"`
+	Errors []validator.FieldViolation `json:"errors,omitempty"`
+}
+
+type ImportTodosResultDTO struct {
+	Rows     []ImportRowResultDTO `json:"rows"`
+	Imported int                  `json:"imported"`
+	Failed   int                  `json:"failed"`
+}
+
+func (r *ImportTodosResultDTO) ToGRPC() *todo.ImportTodosResult {
+	res := &todo.ImportTodosResult{
+		Rows:     make([]*todo.ImportRowResult, 0, len(r.Rows)),
+		Imported: int32(r.Imported),
+		Failed:   int32(r.Failed),
+	}
+	for _, row := range r.Rows {
+		item := &todo.ImportRowResult{Row: int32(row.Row), Id: optionalUUIDToString(row.ID)}
+		for _, v := range row.Errors {
+			item.Errors = append(item.Errors, &todo.ImportFieldError{Field: v.Field, Message: v.Message})
+		}
+		res.Rows = append(res.Rows, item)
+	}
+
+	return res
+}
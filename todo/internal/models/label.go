@@ -0,0 +1,168 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"todo/pkg/grpc_stubs/todo"
+)
+
+// режимы фильтрации задач по меткам
+const (
+	LabelMatchAny = "any"
+	LabelMatchAll = "all"
+)
+
+// лимиты постраничного вывода списка задач
+const (
+	TodosDefaultLimit = 50
+	TodosMaxLimit     = 200
+)
+
+type LabelDAO struct {
+	ID        uuid.UUID `db:"id"`
+	Owner     int       `db:"owner"`
+	Name      string    `db:"name"`
+	Color     string    `db:"color"`
+	CreatedAt time.Time `db:"created_at"`
+	// WorkspaceID - метка рабочего пространства, nil у личной метки владельца
+	WorkspaceID *uuid.UUID `db:"workspace_id"`
+}
+
+type LabelDTO struct {
+	ID          uuid.UUID  `json:"id" example:"5d1c3b0e-8f4a-4b6e-9c2d-7a8b9c0d1e2f"`
+	Owner       int        `json:"owner" example:"1"`
+	Name        string     `json:"name" example:"urgent"`
+	Color       string     `json:"color" example:"#ff0000"`
+	CreatedAt   time.Time  `json:"created_at"`
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty"`
+}
+
+func (l *LabelDTO) ToGRPC() *todo.LabelDTO {
+	return &todo.LabelDTO{
+		Id:          l.ID.String(),
+		Owner:       int32(l.Owner),
+		Name:        l.Name,
+		Color:       l.Color,
+		CreatedAt:   timestamppb.New(l.CreatedAt),
+		WorkspaceId: optionalUUIDToString(l.WorkspaceID),
+	}
+}
+
+type CreateLabelDTO struct {
+	Owner int    `json:"owner" validate:"required,min=1"`
+	Name  string `json:"name" validate:"label_name"`
+	Color string `json:"color" validate:"required,hexcolor"`
+	// WorkspaceID - создать метку рабочего пространства, право на это проверяет gateway
+	WorkspaceID *uuid.UUID `json:"workspace_id"`
+}
+
+func NewEmptyCreateLabelDTO() *CreateLabelDTO {
+	return &CreateLabelDTO{}
+}
+
+func (l *CreateLabelDTO) FromGRPC(in *todo.CreateLabelDTO) *CreateLabelDTO {
+	l.Owner = int(in.Owner)
+	l.Name = in.Name
+	l.Color = in.Color
+	l.WorkspaceID = stringToOptionalUUID(in.WorkspaceId)
+	return l
+}
+
+type UpdateLabelDTO struct {
+	ID    uuid.UUID `json:"id" validate:"required"`
+	Owner int       `json:"owner" validate:"required,min=1"`
+	Name  string    `json:"name" validate:"label_name"`
+	Color string    `json:"color" validate:"required,hexcolor"`
+}
+
+func NewEmptyUpdateLabelDTO() *UpdateLabelDTO {
+	return &UpdateLabelDTO{}
+}
+
+func (l *UpdateLabelDTO) FromGRPC(in *todo.UpdateLabelDTO) *UpdateLabelDTO {
+	l.ID, _ = uuid.Parse(in.Id)
+	l.Owner = int(in.Owner)
+	l.Name = in.Name
+	l.Color = in.Color
+	return l
+}
+
+type TodoLabelDTO struct {
+	TodoID  uuid.UUID
+	LabelID uuid.UUID
+	Owner   int
+}
+
+func NewEmptyTodoLabelDTO() *TodoLabelDTO {
+	return &TodoLabelDTO{}
+}
+
+// ListLabelsDTO - личные метки пользователя или метки рабочего пространства
+type ListLabelsDTO struct {
+	UserID      int
+	WorkspaceID *uuid.UUID
+}
+
+func NewEmptyListLabelsDTO() *ListLabelsDTO {
+	return &ListLabelsDTO{}
+}
+
+func (l *ListLabelsDTO) FromGRPC(in *todo.ListLabelsRequest) *ListLabelsDTO {
+	l.UserID = int(in.UserId)
+	l.WorkspaceID = stringToOptionalUUID(in.WorkspaceId)
+	return l
+}
+
+func (l *TodoLabelDTO) FromGRPC(in *todo.TodoLabelRequest) *TodoLabelDTO {
+	l.TodoID, _ = uuid.Parse(in.TodoId)
+	l.LabelID, _ = uuid.Parse(in.LabelId)
+	l.Owner = int(in.Owner)
+	return l
+}
+
+// ListTodosDTO - фильтр списка задач пользователя
+type ListTodosDTO struct {
+	UserID     int         `json:"user_id" validate:"required,min=1"`
+	LabelIDs   []uuid.UUID `json:"label_ids"`
+	LabelMatch string      `json:"label_match" validate:"omitempty,oneof=any all"`
+	Limit      int         `json:"limit" validate:"min=0,max=200"`
+	Offset     int         `json:"offset" validate:"min=0"`
+	// WorkspaceID - задачи рабочего пространства вместо задач, созданных пользователем или назначенных на него
+	WorkspaceID *uuid.UUID `json:"workspace_id"`
+	// RootsOnly оставляет задачи, родитель которых не виден пользователю, - корни дерева задач
+	RootsOnly bool `json:"-"`
+}
+
+func NewEmptyListTodosDTO() *ListTodosDTO {
+	return &ListTodosDTO{}
+}
+
+func (l *ListTodosDTO) FromGRPC(in *todo.ListTodosRequest) *ListTodosDTO {
+	l.UserID = int(in.UserId)
+	l.LabelIDs = stringsToUUIDs(in.LabelIds)
+	l.LabelMatch = in.LabelMatch
+	l.Limit = int(in.Limit)
+	l.Offset = int(in.Offset)
+	l.WorkspaceID = stringToOptionalUUID(in.WorkspaceId)
+	return l
+}
+
+func uuidsToStrings(ids []uuid.UUID) []string {
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, id.String())
+	}
+	return res
+}
+
+// stringsToUUIDs пропускает строки, которые не являются uuid
+func stringsToUUIDs(ids []string) []uuid.UUID {
+	res := make([]uuid.UUID, 0, len(ids))
+	for _, raw := range ids {
+		if id, err := uuid.Parse(raw); err == nil {
+			res = append(res, id)
+		}
+	}
+	return res
+}
@@ -1,11 +1,33 @@
 package models
 
+import "github.com/google/uuid"
+
 const (
 	TodoEventTypeEmailVerification = "todo_verify_email"
+	// новый комментарий: письмо автору и исполнителю задачи, кроме автора комментария
+	TodoEventTypeCommentAdded = "todo_comment_added"
+	// приглашение в рабочее пространство: письмо со ссылкой на приглашенный адрес
+	TodoEventTypeWorkspaceInvitation = "todo_workspace_invitation"
+	// массовая операция: одно письмо исполнителю со всеми затронутыми его задачами
+	TodoEventTypeBulkUpdated = "todo_bulk_updated"
 )
 
 type TodoMailItem struct {
-	TodoEventType string   `json:"todo_event_type"`
-	Receivers     []string `json:"receivers"`
-	Link          string   `json:"link"`
+	TodoEventType   string     `json:"todo_event_type"`
+	Receivers       []string   `json:"receivers"`
+	Link            string     `json:"link"`
+	TodoID          uuid.UUID  `json:"todo_id,omitempty"`
+	TodoDescription string     `json:"todo_description,omitempty"`
+	Author          string     `json:"author,omitempty"`
+	CommentBody     string     `json:"comment_body,omitempty"`
+	WorkspaceName   string     `json:"workspace_name,omitempty"`
+	Role            string     `json:"role,omitempty"`
+	Operation       string     `json:"operation,omitempty"`
+	Todos           []MailTodo `json:"todos,omitempty"`
+}
+
+// MailTodo - задача в письме о массовой операции
+type MailTodo struct {
+	ID          uuid.UUID `json:"id"`
+	Description string    `json:"description"`
 }
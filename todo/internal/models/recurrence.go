@@ -0,0 +1,21 @@
+package models
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+func optionalTimeToGRPC(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func grpcToOptionalTime(in *timestamppb.Timestamp) *time.Time {
+	if in == nil {
+		return nil
+	}
+	t := in.AsTime()
+	return &t
+}
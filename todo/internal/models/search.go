@@ -0,0 +1,77 @@
+package models
+
+import (
+	"html"
+	"strings"
+	"todo/pkg/grpc_stubs/todo"
+)
+
+// границы найденных слов во фрагментах из базы. В ответе текст экранируется,
+// а границы заменяются тегом <mark>, чтобы фрагмент можно было безопасно вывести как html
+const (
+	SearchHighlightStart = "[[["
+	SearchHighlightStop  = "]]]"
+)
+
+// лимиты постраничного вывода результатов поиска
+const (
+	SearchDefaultLimit = 20
+	SearchMaxLimit     = 100
+)
+
+type SearchTodosDTO struct {
+	UserID int    `json:"user_id" validate:"required,min=1"`
+	Query  string `json:"query" validate:"required,max=200"`
+	Limit  int    `json:"limit" validate:"min=0,max=100"`
+	Offset int    `json:"offset" validate:"min=0"`
+}
+
+func NewEmptySearchTodosDTO() *SearchTodosDTO {
+	return &SearchTodosDTO{}
+}
+
+func (s *SearchTodosDTO) FromGRPC(in *todo.SearchTodosRequest) *SearchTodosDTO {
+	s.UserID = int(in.UserId)
+	s.Query = in.Query
+	s.Limit = int(in.Limit)
+	s.Offset = int(in.Offset)
+	return s
+}
+
+type SearchResultDAO struct {
+	Todo               TodoDAO
+	Rank               float32
+	DescriptionSnippet string
+	CommentSnippet     string
+}
+
+type SearchResultDTO struct {
+	Todo               *TodoDTO `json:"todo"`
+	Rank               float32  `json:"rank"`
+	DescriptionSnippet string   `json:"description_snippet,omitempty"`
+	CommentSnippet     string   `json:"comment_snippet,omitempty"`
+}
+
+func (r *SearchResultDAO) ToDTO() *SearchResultDTO {
+	return &SearchResultDTO{
+		Todo:               (*TodoDTO)(&r.Todo),
+		Rank:               r.Rank,
+		DescriptionSnippet: highlight(r.DescriptionSnippet),
+		CommentSnippet:     highlight(r.CommentSnippet),
+	}
+}
+
+func (r *SearchResultDTO) ToGRPC() *todo.SearchResult {
+	return &todo.SearchResult{
+		Todo:               r.Todo.ToGRPC(),
+		Rank:               r.Rank,
+		DescriptionSnippet: r.DescriptionSnippet,
+		CommentSnippet:     r.CommentSnippet,
+	}
+}
+
+func highlight(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, SearchHighlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, SearchHighlightStop, "</mark>")
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"todo/pkg/grpc_stubs/todo"
+)
+
+// TodoNodeDTO - задача вместе с подзадачами, которые видит пользователь
+type TodoNodeDTO struct {
+	TodoDTO
+	Subtasks []*TodoNodeDTO `json:"subtasks"`
+}
+
+func (n *TodoNodeDTO) ToGRPC() *todo.TodoNode {
+	res := &todo.TodoNode{
+		Todo:     n.TodoDTO.ToGRPC(),
+		Subtasks: make([]*todo.TodoNode, 0, len(n.Subtasks)),
+	}
+	for _, sub := range n.Subtasks {
+		res.Subtasks = append(res.Subtasks, sub.ToGRPC())
+	}
+	return res
+}
+
+// TodoDependencyDTO - задача TodoID не может быть выполнена раньше задачи BlockedBy
+type TodoDependencyDTO struct {
+	TodoID    uuid.UUID `json:"todo_id"`
+	BlockedBy uuid.UUID `json:"blocked_by"`
+}
+
+func NewEmptyTodoDependencyDTO() *TodoDependencyDTO {
+	return &TodoDependencyDTO{}
+}
+
+func (d *TodoDependencyDTO) FromGRPC(in *todo.TodoDependencyRequest) *TodoDependencyDTO {
+	d.TodoID, _ = uuid.Parse(in.TodoId)
+	d.BlockedBy, _ = uuid.Parse(in.BlockedBy)
+	return d
+}
+
+func optionalUUIDToString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// stringToOptionalUUID возвращает nil для пустой строки и строки, которая не является uuid
+func stringToOptionalUUID(raw string) *uuid.UUID {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"time"
 	"todo/pkg/grpc_stubs/todo"
 )
@@ -13,15 +14,50 @@ type TodoDAO struct {
 	Description string    `db:"description"`
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
+	Labels      []uuid.UUID
+	ParentID    *uuid.UUID `db:"parent_id"`
+	Done        bool       `db:"done"`
+	// SubtasksTotal и SubtasksDone считаются по подзадачам при чтении
+	SubtasksTotal int
+	SubtasksDone  int
+	BlockedBy     []uuid.UUID
+	DueAt         *time.Time `db:"due_at"`
+	Recurrence    string     `db:"recurrence"`
+	Timezone      string     `db:"timezone"`
+	// SeriesID - первая задача серии повторений, Occurrence - номер повторения в серии,
+	// SeriesStart - срок первой задачи серии, от него повторения берут время суток
+	SeriesID    *uuid.UUID `db:"series_id"`
+	Occurrence  int        `db:"occurrence"`
+	SeriesStart *time.Time `db:"series_start"`
+	WorkspaceID *uuid.UUID `db:"workspace_id"`
+	// Version растет при каждом изменении задачи
+	Version int `db:"version"`
 }
 
 type TodoDTO struct {
-	ID          uuid.UUID `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
-	CreatedBy   int       `json:"created_by" example:"1"`
-	Assignee    int       `json:"assignee" example:"2"`
-	Description string    `json:"description" example:"todo description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID   `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	CreatedBy   int         `json:"created_by" example:"1"`
+	Assignee    int         `json:"assignee" example:"2"`
+	Description string      `json:"description" example:"todo description"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Labels      []uuid.UUID `json:"labels"`
+	ParentID    *uuid.UUID  `json:"parent_id,omitempty" example:"5f0b5b52-3c0e-4c5e-9a43-6c1f0f0a2f55"`
+	Done        bool        `json:"done" example:"false"`
+	// выполнено SubtasksDone из SubtasksTotal подзадач
+	SubtasksTotal int         `json:"subtasks_total" example:"3"`
+	SubtasksDone  int         `json:"subtasks_done" example:"1"`
+	BlockedBy     []uuid.UUID `json:"blocked_by"`
+	DueAt         *time.Time  `json:"due_at,omitempty" example:"2024-03-04T09:00:00Z"`
+	// Recurrence - правило повторения RRULE, которое считается в часовом поясе Timezone
+	Recurrence  string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	Timezone    string     `json:"timezone,omitempty" example:"Europe/Berlin"`
+	SeriesID    *uuid.UUID `json:"series_id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	Occurrence  int        `json:"occurrence,omitempty" example:"1"`
+	SeriesStart *time.Time `json:"-"`
+	// WorkspaceID - рабочее пространство задачи, у личных задач не задано
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty" example:"0b9d7c1e-4f3a-4c2b-9e8d-1a2b3c4d5e6f"`
+	Version     int        `json:"version" example:"1"`
 }
 
 func NewEmptyTodoDTO() *TodoDTO {
@@ -30,28 +66,74 @@ func NewEmptyTodoDTO() *TodoDTO {
 
 func (t *TodoDTO) ToGRPC() *todo.TodoDTO {
 	return &todo.TodoDTO{
-		Id:          int32(uuid.UUID.ID(t.ID)),
-		CreatedBy:   int32(t.CreatedBy),
-		Assignee:    int32(t.Assignee),
-		Description: t.Description,
+		Id:            t.ID.String(),
+		CreatedBy:     int32(t.CreatedBy),
+		Assignee:      int32(t.Assignee),
+		Description:   t.Description,
+		CreatedAt:     &todo.CreatedAt{CreatedAt: timestamppb.New(t.CreatedAt)},
+		UpdatedAt:     &todo.UpdatedAt{UpdatedAt: timestamppb.New(t.UpdatedAt)},
+		Labels:        uuidsToStrings(t.Labels),
+		ParentId:      optionalUUIDToString(t.ParentID),
+		Done:          t.Done,
+		SubtasksTotal: int32(t.SubtasksTotal),
+		SubtasksDone:  int32(t.SubtasksDone),
+		BlockedBy:     uuidsToStrings(t.BlockedBy),
+		DueAt:         optionalTimeToGRPC(t.DueAt),
+		Recurrence:    t.Recurrence,
+		Timezone:      t.Timezone,
+		SeriesId:      optionalUUIDToString(t.SeriesID),
+		Occurrence:    int32(t.Occurrence),
+		WorkspaceId:   optionalUUIDToString(t.WorkspaceID),
+		Version:       int32(t.Version),
 	}
 }
 
 func (t *TodoDTO) FromGRPC(in *todo.TodoDTO) *TodoDTO {
-	t.ID = uuid.New()
+	t.ID, _ = uuid.Parse(in.Id)
 	t.CreatedBy = int(in.CreatedBy)
 	t.Assignee = int(in.Assignee)
 	t.Description = in.Description
+	t.CreatedAt = in.GetCreatedAt().GetCreatedAt().AsTime()
+	t.UpdatedAt = in.GetUpdatedAt().GetUpdatedAt().AsTime()
+	t.Labels = stringsToUUIDs(in.Labels)
+	t.ParentID = stringToOptionalUUID(in.ParentId)
+	t.Done = in.Done
+	t.SubtasksTotal = int(in.SubtasksTotal)
+	t.SubtasksDone = int(in.SubtasksDone)
+	t.BlockedBy = stringsToUUIDs(in.BlockedBy)
+	t.DueAt = grpcToOptionalTime(in.DueAt)
+	t.Recurrence = in.Recurrence
+	t.Timezone = in.Timezone
+	t.SeriesID = stringToOptionalUUID(in.SeriesId)
+	t.Occurrence = int(in.Occurrence)
+	t.WorkspaceID = stringToOptionalUUID(in.WorkspaceId)
+	t.Version = int(in.Version)
 	return t
 }
 
 type CreateTodoDTO struct {
 	ID          uuid.UUID `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
-	CreatedBy   int       `json:"created_by" example:"1"`
-	Assignee    int       `json:"assignee" example:"2"`
-	Description string    `json:"description" example:"todo description"`
+	CreatedBy   int       `json:"created_by" example:"1" validate:"required,min=1"`
+	Assignee    int       `json:"assignee" example:"2" validate:"required,min=1"`
+	Description string    `json:"description" example:"todo description" validate:"description"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// ParentID - задача, подзадачей которой становится новая задача
+	ParentID *uuid.UUID `json:"parent_id,omitempty" example:"5f0b5b52-3c0e-4c5e-9a43-6c1f0f0a2f55"`
+	DueAt    *time.Time `json:"due_at,omitempty" example:"2024-03-04T09:00:00Z" validate:"required_with=Recurrence"`
+	// Recurrence - правило повторения RRULE: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, BYMONTHDAY, COUNT, UNTIL.
+	// Следующее повторение создается после выполнения задачи или при наступлении срока
+	Recurrence string `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO" validate:"omitempty,max=200"`
+	// Timezone - часовой пояс IANA, в котором считаются повторения, по умолчанию UTC
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin" validate:"omitempty,timezone"`
+	// WorkspaceID - рабочее пространство, в котором создается задача. После создания не меняется
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty" example:"0b9d7c1e-4f3a-4c2b-9e8d-1a2b3c4d5e6f"`
+	// SeriesID, Occurrence и SeriesStart заполняет сервис для повторяющихся задач
+	SeriesID    *uuid.UUID `json:"-"`
+	Occurrence  int        `json:"-"`
+	SeriesStart *time.Time `json:"-"`
+	// Done - задача сразу создается выполненной, так переносятся задачи при импорте
+	Done bool `json:"-"`
 }
 
 func NewEmptyCreateTodoDTO() *CreateTodoDTO {
@@ -60,10 +142,37 @@ func NewEmptyCreateTodoDTO() *CreateTodoDTO {
 
 func (t *CreateTodoDTO) ToGRPC() *todo.CreateTodoDTO {
 	return &todo.CreateTodoDTO{
-		Id:          int32(uuid.UUID.ID(t.ID)),
+		Id:          t.ID.String(),
 		CreatedBy:   int32(t.CreatedBy),
 		Assignee:    int32(t.Assignee),
 		Description: t.Description,
+		ParentId:    optionalUUIDToString(t.ParentID),
+		DueAt:       optionalTimeToGRPC(t.DueAt),
+		Recurrence:  t.Recurrence,
+		Timezone:    t.Timezone,
+	}
+}
+
+// ToDAO - новая задача без меток в том виде, в каком она хранится в кэше
+func (t *CreateTodoDTO) ToDAO() *TodoDAO {
+	return &TodoDAO{
+		ID:          t.ID,
+		CreatedBy:   t.CreatedBy,
+		Assignee:    t.Assignee,
+		Description: t.Description,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		ParentID:    t.ParentID,
+		DueAt:       t.DueAt,
+		Recurrence:  t.Recurrence,
+		Timezone:    t.Timezone,
+		SeriesID:    t.SeriesID,
+		Occurrence:  t.Occurrence,
+		SeriesStart: t.SeriesStart,
+		WorkspaceID: t.WorkspaceID,
+		Done:        t.Done,
+		// новая задача сохраняется в первой версии
+		Version: 1,
 	}
 }
 
@@ -72,15 +181,32 @@ func (t *CreateTodoDTO) FromGRPC(in *todo.CreateTodoDTO) *CreateTodoDTO {
 	t.CreatedBy = int(in.CreatedBy)
 	t.Assignee = int(in.Assignee)
 	t.Description = in.Description
+	t.ParentID = stringToOptionalUUID(in.ParentId)
+	t.DueAt = grpcToOptionalTime(in.DueAt)
+	t.Recurrence = in.Recurrence
+	t.Timezone = in.Timezone
+	t.WorkspaceID = stringToOptionalUUID(in.WorkspaceId)
 	return t
 }
 
 type UpdateTodoDTO struct {
-	ID          uuid.UUID `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
-	UpdatedBy   int       `json:"updated_by" example:"1"`
-	Assignee    int       `json:"assignee" example:"2"`
-	Description string    `json:"description" example:"todo description"`
+	ID uuid.UUID `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	// UpdatedBy - автор изменения, попадает в историю задачи
+	UpdatedBy   int       `json:"updated_by" example:"1" validate:"required,min=1"`
+	Assignee    int       `json:"assignee" example:"2" validate:"required,min=1"`
+	Description string    `json:"description" example:"todo description" validate:"description"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// ParentID заменяет родителя задачи, пустое значение делает ее задачей верхнего уровня
+	ParentID *uuid.UUID `json:"parent_id,omitempty" example:"5f0b5b52-3c0e-4c5e-9a43-6c1f0f0a2f55"`
+	Done     bool       `json:"done" example:"false"`
+	DueAt    *time.Time `json:"due_at,omitempty" example:"2024-03-04T09:00:00Z" validate:"required_with=Recurrence"`
+	// Recurrence - правило повторения RRULE: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, BYMONTHDAY, COUNT, UNTIL.
+	// Следующее повторение создается после выполнения задачи или при наступлении срока
+	Recurrence string `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO" validate:"omitempty,max=200"`
+	// Timezone - часовой пояс IANA, в котором считаются повторения, по умолчанию UTC
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin" validate:"omitempty,timezone"`
+	// Version - версия задачи, которую видел клиент. Если задачу уже изменили, обновление отклоняется
+	Version int `json:"version" example:"1" validate:"required,min=1"`
 }
 
 func NewEmptyUpdateTodoDTO() *UpdateTodoDTO {
@@ -89,22 +215,36 @@ func NewEmptyUpdateTodoDTO() *UpdateTodoDTO {
 
 func (t *UpdateTodoDTO) ToGRPC() *todo.UpdateTodoDTO {
 	return &todo.UpdateTodoDTO{
-		Id:          int32(uuid.UUID.ID(t.ID)),
+		Id:          t.ID.String(),
+		UpdatedBy:   int32(t.UpdatedBy),
 		Assignee:    int32(t.Assignee),
 		Description: t.Description,
+		ParentId:    optionalUUIDToString(t.ParentID),
+		Done:        t.Done,
+		DueAt:       optionalTimeToGRPC(t.DueAt),
+		Recurrence:  t.Recurrence,
+		Timezone:    t.Timezone,
+		Version:     int32(t.Version),
 	}
 }
 
 func (t *UpdateTodoDTO) FromGRPC(in *todo.UpdateTodoDTO) *UpdateTodoDTO {
-	t.ID = uuid.New()
+	t.ID, _ = uuid.Parse(in.Id)
+	t.UpdatedBy = int(in.UpdatedBy)
 	t.Assignee = int(in.Assignee)
 	t.Description = in.Description
+	t.ParentID = stringToOptionalUUID(in.ParentId)
+	t.Done = in.Done
+	t.DueAt = grpcToOptionalTime(in.DueAt)
+	t.Recurrence = in.Recurrence
+	t.Timezone = in.Timezone
+	t.Version = int(in.Version)
 	return t
 }
 
 type GetTodosDTO struct {
-	CreatedBy int       `json:"created_by" example:"1"`
-	Assignee  int       `json:"assignee" example:"2"`
+	CreatedBy int       `json:"created_by" example:"1" validate:"omitempty,min=1"`
+	Assignee  int       `json:"assignee" example:"2" validate:"omitempty,min=1"`
 	DateFrom  time.Time `json:"date_from"`
-	DateTo    time.Time `json:"date_to"`
+	DateTo    time.Time `json:"date_to" validate:"omitempty,gtefield=DateFrom"`
 }
@@ -0,0 +1,21 @@
+package models
+
+import "todo/pkg/grpc_stubs/users"
+
+// UserDTO - данные пользователя из сервиса users, нужные для уведомлений
+type UserDTO struct {
+	ID       int
+	Username string
+	Email    string
+}
+
+func NewEmptyUserDTO() *UserDTO {
+	return &UserDTO{}
+}
+
+func (u *UserDTO) FromGRPC(in *users.UserDTO) *UserDTO {
+	u.ID = int(in.Id)
+	u.Username = in.Username
+	u.Email = in.Email
+	return u
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+const UserEventTypeDeleted = "user.deleted"
+
+// UserEvent событие жизненного цикла пользователя из сервиса users
+type UserEvent struct {
+	EventType  string    `json:"event_type"`
+	UserID     int       `json:"user_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
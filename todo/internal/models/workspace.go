@@ -0,0 +1,194 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"todo/pkg/grpc_stubs/todo"
+)
+
+// роли участников рабочего пространства
+const (
+	// WorkspaceRoleOwner управляет пространством и его участниками
+	WorkspaceRoleOwner = "owner"
+	// WorkspaceRoleEditor создает и меняет задачи пространства
+	WorkspaceRoleEditor = "editor"
+	// WorkspaceRoleViewer видит и комментирует задачи пространства
+	WorkspaceRoleViewer = "viewer"
+)
+
+type WorkspaceDAO struct {
+	ID        uuid.UUID `db:"id"`
+	Name      string    `db:"name"`
+	CreatedBy int       `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	// Role - роль пользователя, для которого получен список пространств
+	Role string `db:"role"`
+}
+
+type WorkspaceDTO struct {
+	ID        uuid.UUID `json:"id" example:"0b9d7c1e-4f3a-4c2b-9e8d-1a2b3c4d5e6f"`
+	Name      string    `json:"name" example:"household"`
+	CreatedBy int       `json:"created_by" example:"1"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Role      string    `json:"role,omitempty" example:"owner"`
+}
+
+func (w *WorkspaceDTO) ToGRPC() *todo.WorkspaceDTO {
+	return &todo.WorkspaceDTO{
+		Id:        w.ID.String(),
+		Name:      w.Name,
+		CreatedBy: int32(w.CreatedBy),
+		CreatedAt: timestamppb.New(w.CreatedAt),
+		UpdatedAt: timestamppb.New(w.UpdatedAt),
+		Role:      w.Role,
+	}
+}
+
+type CreateWorkspaceDTO struct {
+	CreatedBy int    `json:"created_by" validate:"required,min=1"`
+	Name      string `json:"name" validate:"workspace_name"`
+}
+
+func NewEmptyCreateWorkspaceDTO() *CreateWorkspaceDTO {
+	return &CreateWorkspaceDTO{}
+}
+
+func (w *CreateWorkspaceDTO) FromGRPC(in *todo.CreateWorkspaceDTO) *CreateWorkspaceDTO {
+	w.CreatedBy = int(in.CreatedBy)
+	w.Name = in.Name
+	return w
+}
+
+type UpdateWorkspaceDTO struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Name string    `json:"name" validate:"workspace_name"`
+}
+
+func NewEmptyUpdateWorkspaceDTO() *UpdateWorkspaceDTO {
+	return &UpdateWorkspaceDTO{}
+}
+
+func (w *UpdateWorkspaceDTO) FromGRPC(in *todo.UpdateWorkspaceDTO) *UpdateWorkspaceDTO {
+	w.ID, _ = uuid.Parse(in.Id)
+	w.Name = in.Name
+	return w
+}
+
+type WorkspaceMemberDAO struct {
+	WorkspaceID uuid.UUID `db:"workspace_id"`
+	UserID      int       `db:"user_id"`
+	Role        string    `db:"role"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type WorkspaceMemberDTO struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" validate:"required"`
+	UserID      int       `json:"user_id" validate:"required,min=1"`
+	Role        string    `json:"role" validate:"required,oneof=owner editor viewer"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewEmptyWorkspaceMemberDTO() *WorkspaceMemberDTO {
+	return &WorkspaceMemberDTO{}
+}
+
+func (m *WorkspaceMemberDTO) ToGRPC() *todo.WorkspaceMemberDTO {
+	return &todo.WorkspaceMemberDTO{
+		WorkspaceId: m.WorkspaceID.String(),
+		UserId:      int32(m.UserID),
+		Role:        m.Role,
+		CreatedAt:   timestamppb.New(m.CreatedAt),
+	}
+}
+
+func (m *WorkspaceMemberDTO) FromGRPC(in *todo.WorkspaceMemberDTO) *WorkspaceMemberDTO {
+	m.WorkspaceID, _ = uuid.Parse(in.WorkspaceId)
+	m.UserID = int(in.UserId)
+	m.Role = in.Role
+	return m
+}
+
+// WorkspaceInvitationDAO - приглашение в пространство. В базе хранится sha256 токена из письма
+type WorkspaceInvitationDAO struct {
+	ID          uuid.UUID `db:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id"`
+	Email       string    `db:"email"`
+	Role        string    `db:"role"`
+	InvitedBy   int       `db:"invited_by"`
+	TokenHash   string    `db:"token_hash"`
+	CreatedAt   time.Time `db:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
+
+type WorkspaceInvitationDTO struct {
+	ID          uuid.UUID `json:"id" example:"6a0f0b52-3c0e-4c5e-9a43-6c1f0f0a2f55"`
+	WorkspaceID uuid.UUID `json:"workspace_id" example:"0b9d7c1e-4f3a-4c2b-9e8d-1a2b3c4d5e6f"`
+	Email       string    `json:"email" example:"friend@example.com"`
+	Role        string    `json:"role" example:"editor"`
+	InvitedBy   int       `json:"invited_by" example:"1"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func NewWorkspaceInvitationDTO(dao *WorkspaceInvitationDAO) *WorkspaceInvitationDTO {
+	return &WorkspaceInvitationDTO{
+		ID:          dao.ID,
+		WorkspaceID: dao.WorkspaceID,
+		Email:       dao.Email,
+		Role:        dao.Role,
+		InvitedBy:   dao.InvitedBy,
+		CreatedAt:   dao.CreatedAt,
+		ExpiresAt:   dao.ExpiresAt,
+	}
+}
+
+func (i *WorkspaceInvitationDTO) ToGRPC() *todo.WorkspaceInvitationDTO {
+	return &todo.WorkspaceInvitationDTO{
+		Id:          i.ID.String(),
+		WorkspaceId: i.WorkspaceID.String(),
+		Email:       i.Email,
+		Role:        i.Role,
+		InvitedBy:   int32(i.InvitedBy),
+		CreatedAt:   timestamppb.New(i.CreatedAt),
+		ExpiresAt:   timestamppb.New(i.ExpiresAt),
+	}
+}
+
+// InviteWorkspaceMemberDTO - владельцем пространства можно стать только по решению другого владельца,
+// поэтому пригласить можно редактором или наблюдателем
+type InviteWorkspaceMemberDTO struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" validate:"required"`
+	Email       string    `json:"email" validate:"email_address"`
+	Role        string    `json:"role" validate:"required,oneof=editor viewer"`
+	InvitedBy   int       `json:"invited_by" validate:"required,min=1"`
+}
+
+func NewEmptyInviteWorkspaceMemberDTO() *InviteWorkspaceMemberDTO {
+	return &InviteWorkspaceMemberDTO{}
+}
+
+func (i *InviteWorkspaceMemberDTO) FromGRPC(in *todo.InviteWorkspaceMemberDTO) *InviteWorkspaceMemberDTO {
+	i.WorkspaceID, _ = uuid.Parse(in.WorkspaceId)
+	i.Email = in.Email
+	i.Role = in.Role
+	i.InvitedBy = int(in.InvitedBy)
+	return i
+}
+
+type AcceptWorkspaceInvitationDTO struct {
+	Token  string `json:"token" validate:"required"`
+	UserID int    `json:"user_id" validate:"required,min=1"`
+}
+
+func NewEmptyAcceptWorkspaceInvitationDTO() *AcceptWorkspaceInvitationDTO {
+	return &AcceptWorkspaceInvitationDTO{}
+}
+
+func (a *AcceptWorkspaceInvitationDTO) FromGRPC(in *todo.AcceptWorkspaceInvitationDTO) *AcceptWorkspaceInvitationDTO {
+	a.Token = in.Token
+	a.UserID = int(in.UserId)
+	return a
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+)
+
+// UpdateToDos сохраняет задачи одной транзакцией. Задачи, которые после чтения успели удалить или изменить,
+// пропускаются и возвращаются с причиной, события истории пишутся только для сохраненных.
+// У сохраненных задач Version указывает на новую версию
+func (r *TodoRepository) UpdateToDos(ctx context.Context, todos []*models.TodoDAO, events []models.TodoEventDAO) (map[uuid.UUID]error, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateToDos] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rejected := make(map[uuid.UUID]error)
+	for _, todo := range todos {
+		err := r.updateTodoTx(ctx, tx, todo)
+		if isStaleTodo(err) {
+			rejected[todo.ID] = err
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[UpdateToDos] %w", err)
+		}
+	}
+
+	saved := make([]models.TodoEventDAO, 0, len(events))
+	for _, event := range events {
+		if _, ok := rejected[event.TodoID]; !ok {
+			saved = append(saved, event)
+		}
+	}
+
+	if err := insertTodoEvents(ctx, tx, saved); err != nil {
+		return nil, fmt.Errorf("[UpdateToDos] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[UpdateToDos] commit: %w", err)
+	}
+
+	for _, todo := range todos {
+		if _, ok := rejected[todo.ID]; !ok {
+			todo.Version++
+		}
+	}
+
+	return rejected, nil
+}
+
+// DeleteToDos удаляет задачи одной транзакцией так же, как DeleteToDo. Задачи, которые после чтения
+// успели удалить или изменить, пропускаются и возвращаются с причиной.
+// Вторым значением возвращаются подзадачи и зависимые задачи удаленных
+func (r *TodoRepository) DeleteToDos(ctx context.Context, todos []*models.TodoDAO) (map[uuid.UUID]error, []uuid.UUID, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("[DeleteToDos] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rejected := make(map[uuid.UUID]error)
+	affected := make([]uuid.UUID, 0)
+	// удаление родителя поднимает версию его подзадач. Подзадача из той же пачки сравнивается
+	// с версией, прочитанной клиентом, плюс эти изменения, а чужие изменения по-прежнему дают конфликт
+	bumped := make(map[uuid.UUID]int)
+	for _, todo := range todos {
+		children, dependents, err := deleteTodoTx(ctx, tx, todo.ID, todo.Version+bumped[todo.ID])
+		if isStaleTodo(err) {
+			rejected[todo.ID] = err
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("[DeleteToDos] %w", err)
+		}
+
+		for _, id := range children {
+			bumped[id]++
+		}
+		affected = append(append(affected, children...), dependents...)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("[DeleteToDos] commit: %w", err)
+	}
+
+	return rejected, affected, nil
+}
+
+// RelabelToDos одной транзакцией вешает метки add на задачи и снимает с них метки remove.
+// Задачи, удаленные после чтения, пропускаются
+func (r *TodoRepository) RelabelToDos(ctx context.Context, todoIDs, add, remove []uuid.UUID) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[RelabelToDos] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if len(add) > 0 {
+		sql := `INSERT INTO todo_labels (todo_id, label_id)
+					SELECT todo.id, label_id FROM todo CROSS JOIN unnest($2::uuid[]) AS label_id
+					WHERE todo.id = ANY($1)
+				ON CONFLICT DO NOTHING`
+
+		if _, err := tx.Exec(ctx, sql, todoIDs, add); err != nil {
+			return fmt.Errorf("[RelabelToDos] add labels: %w", err)
+		}
+	}
+
+	if len(remove) > 0 {
+		sql := `DELETE FROM todo_labels WHERE todo_id = ANY($1) AND label_id = ANY($2)`
+
+		if _, err := tx.Exec(ctx, sql, todoIDs, remove); err != nil {
+			return fmt.Errorf("[RelabelToDos] remove labels: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[RelabelToDos] commit: %w", err)
+	}
+
+	return nil
+}
+
+// isStaleTodo - задачу удалили или изменили после чтения, остальные задачи пачки можно сохранять
+func isStaleTodo(err error) bool {
+	return errors.Is(err, app_errors.ErrNotFound) || errors.Is(err, app_errors.ErrVersionConflict)
+}
+
+// CreateToDos добавляет задачи одной транзакцией: сохраняются либо все задачи, либо ни одна
+func (r *TodoRepository) CreateToDos(ctx context.Context, todos []*models.CreateTodoDTO) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[CreateToDos] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, todo := range todos {
+		batch.Queue(insertTodoSQL, r.insertTodoArgs(todo)...)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range todos {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("[CreateToDos] create todo: %w", err)
+		}
+	}
+
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("[CreateToDos] create todos: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[CreateToDos] commit: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"time"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+)
+
+func (r *TodoRepository) CreateComment(ctx context.Context, comment *models.CommentDAO) error {
+	sql := `INSERT INTO
+						todo_comments (id, todo_id, author, body, created_at, search_vector)
+				VALUES ($1, $2, $3, $4, $5, to_tsvector($6::regconfig, $4))`
+
+	_, err := r.conn.Exec(ctx, sql,
+		comment.ID, comment.TodoID, comment.Author, comment.Body, comment.CreatedAt, r.searchLanguage)
+	if err != nil {
+		return fmt.Errorf("[CreateComment] create comment: %w", err)
+	}
+
+	return nil
+}
+
+// GetComment возвращает комментарий, если он не удален
+func (r *TodoRepository) GetComment(ctx context.Context, commentID uuid.UUID) (*models.CommentDAO, error) {
+	var dao models.CommentDAO
+
+	sql := `SELECT
+					id, todo_id, author, body, created_at, edited_at
+				FROM
+					todo_comments
+				WHERE
+				    id = $1 AND deleted_at IS NULL`
+
+	err := r.conn.QueryRow(ctx, sql, commentID).
+		Scan(
+			&dao.ID,
+			&dao.TodoID,
+			&dao.Author,
+			&dao.Body,
+			&dao.CreatedAt,
+			&dao.EditedAt,
+		)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[GetComment] get comment: %w", app_errors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("[GetComment] get comment: %w", err)
+	}
+
+	return &dao, nil
+}
+
+func (r *TodoRepository) UpdateComment(ctx context.Context, comment *models.CommentDAO) error {
+	sql := `UPDATE todo_comments
+				SET body = $1, edited_at = $2, search_vector = to_tsvector($4::regconfig, $1)
+				WHERE id = $3 AND deleted_at IS NULL`
+
+	tag, err := r.conn.Exec(ctx, sql, comment.Body, comment.EditedAt, comment.ID, r.searchLanguage)
+	if err != nil {
+		return fmt.Errorf("[UpdateComment] update comment: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("[UpdateComment] update comment: %w", app_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteComment помечает комментарий удаленным, сама запись остается в базе
+func (r *TodoRepository) DeleteComment(ctx context.Context, commentID uuid.UUID, deletedAt time.Time) error {
+	sql := `UPDATE todo_comments SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	tag, err := r.conn.Exec(ctx, sql, deletedAt, commentID)
+	if err != nil {
+		return fmt.Errorf("[DeleteComment] delete comment: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("[DeleteComment] delete comment: %w", app_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ListComments возвращает страницу комментариев задачи от старых к новым и общее их количество
+func (r *TodoRepository) ListComments(ctx context.Context, request *models.ListCommentsDTO) ([]models.CommentDAO, int, error) {
+	res := make([]models.CommentDAO, 0)
+
+	var total int
+	countSQL := `SELECT count(*) FROM todo_comments WHERE todo_id = $1 AND deleted_at IS NULL`
+	if err := r.conn.QueryRow(ctx, countSQL, request.TodoID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("[ListComments] count comments: %w", err)
+	}
+
+	sql := `SELECT
+					id, todo_id, author, body, created_at, edited_at
+				FROM
+					todo_comments
+				WHERE
+				    todo_id = $1 AND deleted_at IS NULL
+				ORDER BY created_at, id
+				LIMIT $2 OFFSET $3`
+
+	rows, err := r.conn.Query(ctx, sql, request.TodoID, request.Limit, request.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("[ListComments] get comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dao models.CommentDAO
+
+		err = rows.Scan(
+			&dao.ID,
+			&dao.TodoID,
+			&dao.Author,
+			&dao.Body,
+			&dao.CreatedAt,
+			&dao.EditedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("[ListComments] scan comment: %w", err)
+		}
+
+		res = append(res, dao)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("[ListComments] rows: %w", err)
+	}
+
+	return res, total, nil
+}
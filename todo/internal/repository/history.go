@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"todo/internal/models"
+)
+
+// insertTodoEvents дописывает события в историю одним запросом
+func insertTodoEvents(ctx context.Context, tx pgx.Tx, events []models.TodoEventDAO) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		batch.Queue(`INSERT INTO todo_events (id, todo_id, actor, field, old_value, new_value, created_at)
+						VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			e.ID, e.TodoID, e.Actor, e.Field, e.OldValue, e.NewValue, e.CreatedAt)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range events {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("insert todo event: %w", err)
+		}
+	}
+
+	if err := results.Close(); err != nil {
+		return fmt.Errorf("insert todo events: %w", err)
+	}
+
+	return nil
+}
+
+// ListTodoHistory возвращает страницу истории задачи от новых событий к старым и общее их количество
+func (r *TodoRepository) ListTodoHistory(ctx context.Context, request *models.ListTodoHistoryDTO) ([]models.TodoEventDAO, int, error) {
+	res := make([]models.TodoEventDAO, 0)
+
+	var total int
+	countSQL := `SELECT count(*) FROM todo_events WHERE todo_id = $1`
+	if err := r.conn.QueryRow(ctx, countSQL, request.TodoID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("[ListTodoHistory] count events: %w", err)
+	}
+
+	sql := `SELECT
+					id, todo_id, actor, field, old_value, new_value, created_at
+				FROM
+					todo_events
+				WHERE
+				    todo_id = $1
+				ORDER BY created_at DESC, field
+				LIMIT $2 OFFSET $3`
+
+	rows, err := r.conn.Query(ctx, sql, request.TodoID, request.Limit, request.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("[ListTodoHistory] get events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dao models.TodoEventDAO
+
+		err = rows.Scan(
+			&dao.ID,
+			&dao.TodoID,
+			&dao.Actor,
+			&dao.Field,
+			&dao.OldValue,
+			&dao.NewValue,
+			&dao.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("[ListTodoHistory] scan event: %w", err)
+		}
+
+		res = append(res, dao)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("[ListTodoHistory] rows: %w", err)
+	}
+
+	return res, total, nil
+}
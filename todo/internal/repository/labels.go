@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+)
+
+const pgUniqueViolation = "23505"
+
+// todoLabelsColumn - идентификаторы меток задачи одним массивом, пустым если меток нет
+const todoLabelsColumn = `ARRAY(SELECT tl.label_id::text FROM todo_labels tl WHERE tl.todo_id = todo.id ORDER BY tl.label_id) AS labels`
+
+// labelColumns - колонки метки в порядке scanLabel
+const labelColumns = `id, owner, name, color, created_at, workspace_id`
+
+// mapUniqueViolation переводит повтор имени метки у владельца или в пространстве в ErrAlreadyExists
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return fmt.Errorf("%s: %w", pgErr.ConstraintName, app_errors.ErrAlreadyExists)
+	}
+
+	return err
+}
+
+func (r *TodoRepository) CreateLabel(ctx context.Context, label *models.LabelDAO) error {
+	sql := `INSERT INTO
+						labels (id, owner, name, color, created_at, workspace_id)
+				VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := r.conn.Exec(ctx, sql, label.ID, label.Owner, label.Name, label.Color, label.CreatedAt, label.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("[CreateLabel] create label: %w", mapUniqueViolation(err))
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) GetLabel(ctx context.Context, labelID uuid.UUID) (*models.LabelDAO, error) {
+	var dao models.LabelDAO
+
+	sql := `SELECT ` + labelColumns + ` FROM labels WHERE id = $1`
+
+	err := scanLabel(r.conn.QueryRow(ctx, sql, labelID), &dao)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[GetLabel] get label: %w", app_errors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("[GetLabel] get label: %w", err)
+	}
+
+	return &dao, nil
+}
+
+func (r *TodoRepository) UpdateLabel(ctx context.Context, label *models.LabelDAO) error {
+	sql := `UPDATE labels SET name = $1, color = $2 WHERE id = $3`
+
+	tag, err := r.conn.Exec(ctx, sql, label.Name, label.Color, label.ID)
+	if err != nil {
+		return fmt.Errorf("[UpdateLabel] update label: %w", mapUniqueViolation(err))
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("[UpdateLabel] update label: %w", app_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteLabel удаляет метку и снимает ее со всех задач.
+// Возвращает идентификаторы задач, с которых метка была снята
+func (r *TodoRepository) DeleteLabel(ctx context.Context, labelID uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteLabel] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `DELETE FROM todo_labels WHERE label_id = $1 RETURNING todo_id`, labelID)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteLabel] detach label: %w", err)
+	}
+
+	affected, err := scanIDs(rows)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteLabel] %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM labels WHERE id = $1`, labelID); err != nil {
+		return nil, fmt.Errorf("[DeleteLabel] delete label: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[DeleteLabel] commit: %w", err)
+	}
+
+	return affected, nil
+}
+
+// ListLabels возвращает метки рабочего пространства, если оно задано, иначе личные метки владельца
+func (r *TodoRepository) ListLabels(ctx context.Context, owner int, workspaceID *uuid.UUID) ([]models.LabelDAO, error) {
+	res := make([]models.LabelDAO, 0)
+
+	sql := `SELECT ` + labelColumns + ` FROM labels WHERE owner = $1 AND workspace_id IS NULL ORDER BY lower(name)`
+	args := []interface{}{owner}
+	if workspaceID != nil {
+		sql = `SELECT ` + labelColumns + ` FROM labels WHERE workspace_id = $1 ORDER BY lower(name)`
+		args = []interface{}{*workspaceID}
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[ListLabels] get labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dao models.LabelDAO
+		if err := scanLabel(rows, &dao); err != nil {
+			return nil, fmt.Errorf("[ListLabels] scan label: %w", err)
+		}
+
+		res = append(res, dao)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[ListLabels] rows: %w", err)
+	}
+
+	return res, nil
+}
+
+// scanLabel читает метку, выбранную колонками labelColumns
+func scanLabel(row pgx.Row, dao *models.LabelDAO) error {
+	return row.Scan(&dao.ID, &dao.Owner, &dao.Name, &dao.Color, &dao.CreatedAt, &dao.WorkspaceID)
+}
+
+// AddTodoLabel вешает метку на задачу, повторное добавление ничего не меняет
+func (r *TodoRepository) AddTodoLabel(ctx context.Context, todoID, labelID uuid.UUID) error {
+	sql := `INSERT INTO todo_labels (todo_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	if _, err := r.conn.Exec(ctx, sql, todoID, labelID); err != nil {
+		return fmt.Errorf("[AddTodoLabel] add label: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) RemoveTodoLabel(ctx context.Context, todoID, labelID uuid.UUID) error {
+	sql := `DELETE FROM todo_labels WHERE todo_id = $1 AND label_id = $2`
+
+	if _, err := r.conn.Exec(ctx, sql, todoID, labelID); err != nil {
+		return fmt.Errorf("[RemoveTodoLabel] remove label: %w", err)
+	}
+
+	return nil
+}
+
+// withLabelFilter оставляет в выборке задачи с метками из фильтра: при any подходит задача
+// хотя бы с одной из меток, при all - со всеми
+func withLabelFilter(queryBuilder squirrel.SelectBuilder, filter *models.ListTodosDTO) squirrel.SelectBuilder {
+	if len(filter.LabelIDs) == 0 {
+		return queryBuilder
+	}
+
+	queryBuilder = queryBuilder.
+		Join("todo_labels filter_labels ON filter_labels.todo_id = todo.id").
+		Where(squirrel.Eq{"filter_labels.label_id": filter.LabelIDs}).
+		GroupBy("todo.id")
+
+	if filter.LabelMatch == models.LabelMatchAll {
+		queryBuilder = queryBuilder.Having("count(DISTINCT filter_labels.label_id) = ?", len(filter.LabelIDs))
+	}
+
+	return queryBuilder
+}
+
+// scanIDs читает колонку идентификаторов и закрывает rows
+func scanIDs(rows pgx.Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
+
+	res := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan id: %w", err)
+		}
+		res = append(res, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return res, nil
+}
+
+func parseIDs(raw []string) ([]uuid.UUID, error) {
+	res := make([]uuid.UUID, 0, len(raw))
+	for _, s := range raw {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, id)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+	"todo/internal/models"
+)
+
+// CreateOccurrence создает следующее повторение задачи prevID и копирует ее метки. Повторение
+// создается не больше одного раза: если оно уже создано, возвращает false
+func (r *TodoRepository) CreateOccurrence(ctx context.Context, prevID uuid.UUID, next *models.TodoDAO) (bool, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("[CreateOccurrence] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE todo SET spawned_next = true WHERE id = $1 AND NOT spawned_next`, prevID)
+	if err != nil {
+		return false, fmt.Errorf("[CreateOccurrence] mark spawned: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	sql := `INSERT INTO
+	   					todo (id, created_by, assignee, description, created_at, updated_at, search_vector, parent_id,
+	   					      due_at, recurrence, timezone, series_id, occurrence, workspace_id, series_start)
+				VALUES ($1, $2, $3, $4, $5, $6, to_tsvector($7::regconfig, $4), $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	_, err = tx.Exec(ctx, sql,
+		next.ID, next.CreatedBy, next.Assignee, next.Description, next.CreatedAt, next.UpdatedAt,
+		r.searchLanguage, next.ParentID, next.DueAt, next.Recurrence, next.Timezone, next.SeriesID, next.Occurrence,
+		next.WorkspaceID, next.SeriesStart)
+	if err != nil {
+		return false, fmt.Errorf("[CreateOccurrence] create todo: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO todo_labels (todo_id, label_id) SELECT $1, label_id FROM todo_labels WHERE todo_id = $2`,
+		next.ID, prevID)
+	if err != nil {
+		return false, fmt.Errorf("[CreateOccurrence] copy labels: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("[CreateOccurrence] commit: %w", err)
+	}
+
+	return true, nil
+}
+
+// FinishSeries отмечает, что после задачи повторений больше не будет
+func (r *TodoRepository) FinishSeries(ctx context.Context, todoID uuid.UUID) error {
+	if _, err := r.conn.Exec(ctx, `UPDATE todo SET spawned_next = true WHERE id = $1`, todoID); err != nil {
+		return fmt.Errorf("[FinishSeries] mark spawned: %w", err)
+	}
+
+	return nil
+}
+
+// ListDueRecurring возвращает повторяющиеся задачи, срок которых наступил к now,
+// а следующее повторение еще не создано
+func (r *TodoRepository) ListDueRecurring(ctx context.Context, now time.Time, limit int) ([]models.TodoDAO, error) {
+	sql := `SELECT ` + todoColumns + `
+				FROM
+					todo
+				WHERE
+					recurrence <> '' AND NOT spawned_next AND due_at <= $1
+				ORDER BY due_at
+				LIMIT $2`
+
+	rows, err := r.conn.Query(ctx, sql, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[ListDueRecurring] get todos: %w", err)
+	}
+
+	res, err := scanTodos(rows)
+	if err != nil {
+		return nil, fmt.Errorf("[ListDueRecurring] %w", err)
+	}
+
+	return res, nil
+}
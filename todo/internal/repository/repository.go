@@ -2,30 +2,30 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"todo/internal/app_errors"
 	"todo/internal/models"
 )
 
 type TodoRepository struct {
 	conn *pgxpool.Pool
+	// searchLanguage - конфигурация текстового поиска postgres для поисковых векторов
+	searchLanguage string
 }
 
-func NewTodoRepository(conn *pgxpool.Pool) *TodoRepository {
-	return &TodoRepository{conn: conn}
+func NewTodoRepository(conn *pgxpool.Pool, searchLanguage string) *TodoRepository {
+	return &TodoRepository{conn: conn, searchLanguage: searchLanguage}
 }
 
 func (r *TodoRepository) CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (uuid.UUID, error) {
 	var resID uuid.UUID
 
-	sql := `INSERT INTO
-	   					todo (created_by, assignee, description, created_at, updated_at)
-				VALUES ($1, $2, $3, now(), now()) RETURNING id`
-
-	err := r.conn.QueryRow(ctx, sql,
-		newTodo.CreatedBy, newTodo.Assignee, newTodo.Description, newTodo.CreatedAt, newTodo.UpdatedAt).Scan(&resID)
+	err := r.conn.QueryRow(ctx, insertTodoSQL+` RETURNING id`, r.insertTodoArgs(newTodo)...).Scan(&resID)
 
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("[CreateToDO] create todo: %w\n", err)
@@ -34,17 +34,96 @@ func (r *TodoRepository) CreateToDo(ctx context.Context, newTodo *models.CreateT
 	return resID, nil
 }
 
-func (r *TodoRepository) UpdateToDo(ctx context.Context, updateTodo *models.TodoDAO) (uuid.UUID, error) {
-	sql := `UPDATE todo SET created_by = $1, assignee = $2, description = $3, updated_at = now() WHERE id = $5`
+// insertTodoSQL - добавление новой задачи с аргументами из insertTodoArgs
+const insertTodoSQL = `INSERT INTO
+	   					todo (id, created_by, assignee, description, created_at, updated_at, search_vector, parent_id,
+	   					      due_at, recurrence, timezone, series_id, occurrence, workspace_id, done, series_start)
+				VALUES ($1, $2, $3, $4, $5, $6, to_tsvector($7::regconfig, $4), $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+func (r *TodoRepository) insertTodoArgs(newTodo *models.CreateTodoDTO) []interface{} {
+	return []interface{}{
+		newTodo.ID, newTodo.CreatedBy, newTodo.Assignee, newTodo.Description, newTodo.CreatedAt, newTodo.UpdatedAt,
+		r.searchLanguage, newTodo.ParentID, newTodo.DueAt, newTodo.Recurrence, newTodo.Timezone, newTodo.SeriesID,
+		newTodo.Occurrence, newTodo.WorkspaceID, newTodo.Done, newTodo.SeriesStart,
+	}
+}
+
+// UpdateToDo сохраняет задачу и в той же транзакции дописывает в историю события об изменении ее полей.
+// Задача сохраняется, только если ее версия в базе совпадает с updateTodo.Version, иначе возвращается
+// ErrVersionConflict. При успехе updateTodo.Version указывает на новую версию
+func (r *TodoRepository) UpdateToDo(ctx context.Context, updateTodo *models.TodoDAO, events []models.TodoEventDAO) (uuid.UUID, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDO] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.updateTodoTx(ctx, tx, updateTodo); err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDO] %w", err)
+	}
+
+	if err := insertTodoEvents(ctx, tx, events); err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDO] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDO] commit: %w", err)
+	}
+
+	updateTodo.Version++
+	return updateTodo.ID, nil
+}
+
+// updateTodoTx сохраняет задачу в транзакции tx, если ее версия не изменилась
+func (r *TodoRepository) updateTodoTx(ctx context.Context, tx pgx.Tx, updateTodo *models.TodoDAO) error {
+	if err := checkTodoVersion(ctx, tx, updateTodo.ID, updateTodo.Version); err != nil {
+		return err
+	}
+
+	if err := checkParentTx(ctx, tx, updateTodo.ID, updateTodo.ParentID); err != nil {
+		return err
+	}
+
+	sql := `UPDATE todo
+				SET assignee = $1, description = $2, updated_at = $3, search_vector = to_tsvector($5::regconfig, $2),
+					parent_id = $6, done = $7, due_at = $8, recurrence = $9, timezone = $10, series_id = $11,
+					occurrence = $12, series_start = $14, version = version + 1
+				WHERE id = $4 AND version = $13`
+
+	tag, err := tx.Exec(ctx, sql,
+		updateTodo.Assignee, updateTodo.Description, updateTodo.UpdatedAt, updateTodo.ID, r.searchLanguage,
+		updateTodo.ParentID, updateTodo.Done, updateTodo.DueAt, updateTodo.Recurrence, updateTodo.Timezone,
+		updateTodo.SeriesID, updateTodo.Occurrence, updateTodo.Version, updateTodo.SeriesStart)
 
-	_, err := r.conn.Exec(ctx, sql,
-		updateTodo.CreatedBy, updateTodo.Assignee, updateTodo.Description, updateTodo.UpdatedAt, updateTodo.ID)
+	if err != nil {
+		return fmt.Errorf("update todo: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("update todo: %w", app_errors.ErrNotFound)
+	}
 
+	return nil
+}
+
+// checkTodoVersion блокирует задачу до конца транзакции и проверяет ее версию.
+// Возвращает ErrNotFound, если задачи нет, и ErrVersionConflict, если версия другая
+func checkTodoVersion(ctx context.Context, tx pgx.Tx, todoID uuid.UUID, version int) error {
+	var stored int
+	err := tx.QueryRow(ctx, `SELECT version FROM todo WHERE id = $1 FOR UPDATE`, todoID).Scan(&stored)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("[UpdateToDO] update todo: %w\n", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return app_errors.ErrNotFound
+		}
+
+		return fmt.Errorf("get version: %w", err)
+	}
+
+	if stored != version {
+		return app_errors.ErrVersionConflict
 	}
 
-	return uuid.Nil, nil
+	return nil
 }
 
 func (r *TodoRepository) GetToDos(ctx context.Context, todoID uuid.UUID) ([]models.TodoDAO, error) {
@@ -92,34 +171,335 @@ func (r *TodoRepository) GetToDos(ctx context.Context, todoID uuid.UUID) ([]mode
 func (r *TodoRepository) GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDAO, error) {
 	var dao models.TodoDAO
 
-	sql := `SELECT
-					id, created_by, assignee, description, created_at, updated_at
+	sql := `SELECT ` + todoColumns + `
 				FROM
 					todo
 				WHERE
 				    id = $1`
 
-	err := r.conn.QueryRow(ctx, sql, todoID).
-		Scan(
-			&dao.ID,
-			&dao.CreatedBy,
-			&dao.Assignee,
-			&dao.Description,
-			&dao.CreatedAt,
-			&dao.UpdatedAt,
-		)
+	err := scanTodo(r.conn.QueryRow(ctx, sql, todoID), &dao)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[GetToDo] get todo: %w", app_errors.ErrNotFound)
+		}
+
 		return nil, fmt.Errorf("[GetToDo] get todo -  %w\n", err)
 	}
 
 	return &dao, nil
 }
 
-func (r *TodoRepository) DeleteToDo(ctx context.Context, todoID uuid.UUID) error {
-	sql := `DELETE FROM todo WHERE id = $1`
-	if _, err := r.conn.Exec(ctx, sql, todoID); err != nil {
-		return fmt.Errorf("[DeleteTodo] delete todo: %w\n", err)
+// DeleteToDo удаляет задачу, если ее версия совпадает с version. Ее подзадачи становятся задачами
+// верхнего уровня, а зависимые задачи перестают ее ждать. Возвращает идентификаторы подзадач и зависимых задач
+func (r *TodoRepository) DeleteToDo(ctx context.Context, todoID uuid.UUID, version int) ([]uuid.UUID, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteTodo] begin tx: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	return nil
+	children, dependents, err := deleteTodoTx(ctx, tx, todoID, version)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteTodo] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[DeleteTodo] commit: %w", err)
+	}
+
+	return append(children, dependents...), nil
+}
+
+// deleteTodoTx удаляет задачу в транзакции tx, если ее версия не изменилась.
+// Возвращает подзадачи, у которых сброшен родитель и поэтому выросла версия, и зависимые задачи
+func deleteTodoTx(ctx context.Context, tx pgx.Tx, todoID uuid.UUID, version int) ([]uuid.UUID, []uuid.UUID, error) {
+	if err := checkTodoVersion(ctx, tx, todoID, version); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(ctx,
+		`UPDATE todo SET parent_id = NULL, updated_at = now(), version = version + 1 WHERE parent_id = $1 RETURNING id`,
+		todoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unlink subtasks: %w", err)
+	}
+
+	children, err := scanIDs(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err = tx.Query(ctx, `DELETE FROM todo_dependencies WHERE blocked_by = $1 RETURNING todo_id`, todoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("delete dependencies: %w", err)
+	}
+
+	dependents, err := scanIDs(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM todo WHERE id = $1`, todoID); err != nil {
+		return nil, nil, fmt.Errorf("delete todo: %w", err)
+	}
+
+	return children, dependents, nil
+}
+
+// ListToDos возвращает задачи рабочего пространства, если оно задано в фильтре, иначе личные задачи,
+// созданные пользователем или назначенные на него
+func (r *TodoRepository) ListToDos(ctx context.Context, filter *models.ListTodosDTO) ([]models.TodoDAO, error) {
+	visible, args := visibleCondition("todo", filter)
+
+	queryBuilder := squirrel.
+		Select(todoColumns).
+		From("todo").
+		Where(visible, args...).
+		OrderBy("todo.created_at DESC", "todo.id").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	if filter.RootsOnly {
+		parentVisible, args := visibleCondition("parent", filter)
+		queryBuilder = queryBuilder.Where(`(todo.parent_id IS NULL OR NOT EXISTS (
+			SELECT 1 FROM todo parent
+			WHERE parent.id = todo.parent_id AND `+parentVisible+`))`, args...)
+	}
+
+	queryBuilder = withLabelFilter(queryBuilder, filter)
+
+	sql, args, err := queryBuilder.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("[ListToDos] build query: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[ListToDos] get todos: %w", err)
+	}
+
+	res, err := scanTodos(rows)
+	if err != nil {
+		return nil, fmt.Errorf("[ListToDos] %w", err)
+	}
+
+	return res, nil
+}
+
+// scanTodos читает задачи, выбранные колонками todoColumns, и закрывает rows
+func scanTodos(rows pgx.Rows) ([]models.TodoDAO, error) {
+	defer rows.Close()
+
+	res := make([]models.TodoDAO, 0)
+	for rows.Next() {
+		var dao models.TodoDAO
+		if err := scanTodo(rows, &dao); err != nil {
+			return nil, err
+		}
+
+		res = append(res, dao)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return res, nil
+}
+
+func (r *TodoRepository) GetUserToDos(ctx context.Context, userID int) ([]models.TodoDAO, error) {
+	res := make([]models.TodoDAO, 0)
+
+	sql := `SELECT ` + todoColumns + `
+				FROM
+					todo
+				WHERE
+				    (created_by = $1 OR assignee = $1) AND workspace_id IS NULL
+				ORDER BY created_at`
+
+	rows, err := r.conn.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserToDos] get todos: %w", err)
+	}
+	defer rows.Close()
+
+	res, err = scanTodos(rows)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserToDos] %w", err)
+	}
+
+	return res, nil
+}
+
+// DetachUser удаляет личные задачи пользователя (создал и назначил на себя), его личные метки и участие
+// в рабочих пространствах, а личные задачи, назначенные на него другими, возвращает их авторам.
+// Задачи пространств остаются в пространствах и переходят к их участникам. Пространство, где пользователь
+// единственный владелец, передается другому участнику, а пространство без других участников удаляется.
+// Возвращает идентификаторы всех затронутых задач, включая родителей, подзадачи
+// и зависимые задачи удаленных.
+func (r *TodoRepository) DetachUser(ctx context.Context, userID int) ([]uuid.UUID, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[DetachUser] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	owned, affected, err := handOverWorkspaces(ctx, tx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[DetachUser] %w", err)
+	}
+
+	queries := []string{
+		`SELECT parent_id FROM todo
+			WHERE created_by = $1 AND assignee = $1 AND workspace_id IS NULL AND parent_id IS NOT NULL`,
+		`UPDATE todo SET parent_id = NULL, updated_at = now(), version = version + 1
+			WHERE parent_id IN (SELECT id FROM todo WHERE created_by = $1 AND assignee = $1 AND workspace_id IS NULL)
+			RETURNING id`,
+		`DELETE FROM todo_dependencies
+			WHERE blocked_by IN (SELECT id FROM todo WHERE created_by = $1 AND assignee = $1 AND workspace_id IS NULL)
+			RETURNING todo_id`,
+		`DELETE FROM todo WHERE created_by = $1 AND assignee = $1 AND workspace_id IS NULL RETURNING id`,
+		`UPDATE todo SET assignee = created_by, updated_at = now(), version = version + 1
+			WHERE assignee = $1 AND workspace_id IS NULL RETURNING id`,
+		// задача пространства переходит к автору, если он еще может ее выполнять, иначе к владельцу пространства
+		`UPDATE todo SET assignee = COALESCE(
+				(SELECT m.user_id FROM workspace_members m
+					WHERE m.workspace_id = todo.workspace_id AND m.user_id = todo.created_by AND m.user_id <> $1
+						AND m.role <> '` + models.WorkspaceRoleViewer + `'),
+				(SELECT m.user_id FROM workspace_members m
+					WHERE m.workspace_id = todo.workspace_id AND m.user_id <> $1 AND m.role = '` + models.WorkspaceRoleOwner + `'
+					ORDER BY m.created_at, m.user_id LIMIT 1)),
+				updated_at = now(), version = version + 1
+			WHERE assignee = $1 AND workspace_id IS NOT NULL RETURNING id`,
+		`DELETE FROM todo_labels
+			WHERE label_id IN (SELECT id FROM labels WHERE owner = $1 AND workspace_id IS NULL) RETURNING todo_id`,
+	}
+
+	for _, sql := range queries {
+		rows, err := tx.Query(ctx, sql, userID)
+		if err != nil {
+			return nil, fmt.Errorf("[DetachUser] exec: %w", err)
+		}
+
+		ids, err := scanIDs(rows)
+		if err != nil {
+			return nil, fmt.Errorf("[DetachUser] %w", err)
+		}
+		affected = append(affected, ids...)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM labels WHERE owner = $1 AND workspace_id IS NULL`, userID); err != nil {
+		return nil, fmt.Errorf("[DetachUser] delete labels: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM workspace_members WHERE user_id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("[DetachUser] delete memberships: %w", err)
+	}
+
+	// у пространств, которыми владел пользователь, должен остаться владелец
+	var orphaned bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (
+				SELECT 1 FROM workspaces w
+				WHERE w.id = ANY($1) AND NOT EXISTS (
+					SELECT 1 FROM workspace_members m WHERE m.workspace_id = w.id AND m.role = $2))`,
+		owned, models.WorkspaceRoleOwner).Scan(&orphaned)
+	if err != nil {
+		return nil, fmt.Errorf("[DetachUser] count owners: %w", err)
+	}
+
+	if orphaned {
+		return nil, fmt.Errorf("[DetachUser] %w", app_errors.ErrLastWorkspaceOwner)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[DetachUser] commit: %w", err)
+	}
+
+	return affected, nil
+}
+
+// handOverWorkspaces передает пространства, где пользователь единственный владелец, участнику с самой
+// высокой ролью, а пространства без других участников удаляет вместе с задачами. Строки пространств
+// блокируются, как в changeMembers. Возвращает пространства, которыми владел пользователь,
+// и задачи, затронутые удалением пространств
+func handOverWorkspaces(ctx context.Context, tx pgx.Tx, userID int) ([]uuid.UUID, []uuid.UUID, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT w.id
+			FROM
+				workspaces w
+				JOIN workspace_members m ON m.workspace_id = w.id
+			WHERE
+				m.user_id = $1 AND m.role = $2
+			ORDER BY w.id
+			FOR UPDATE OF w`,
+		userID, models.WorkspaceRoleOwner)
+	if err != nil {
+		return nil, nil, fmt.Errorf("lock workspaces: %w", err)
+	}
+
+	owned, err := scanIDs(rows)
+	if err != nil {
+		return nil, nil, fmt.Errorf("lock workspaces: %w", err)
+	}
+
+	affected := make([]uuid.UUID, 0)
+	for _, workspaceID := range owned {
+		var successor int
+		var hasOwner bool
+
+		err := tx.QueryRow(ctx,
+			`SELECT user_id, role = $3
+				FROM workspace_members
+				WHERE workspace_id = $1 AND user_id <> $2
+				ORDER BY CASE role WHEN $3 THEN 0 WHEN $4 THEN 1 ELSE 2 END, created_at, user_id
+				LIMIT 1`,
+			workspaceID, userID, models.WorkspaceRoleOwner, models.WorkspaceRoleEditor).Scan(&successor, &hasOwner)
+
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			ids, err := deleteWorkspaceTx(ctx, tx, workspaceID)
+			if err != nil {
+				return nil, nil, err
+			}
+			affected = append(affected, ids...)
+		case err != nil:
+			return nil, nil, fmt.Errorf("get successor: %w", err)
+		case !hasOwner:
+			_, err := tx.Exec(ctx,
+				`UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2`,
+				workspaceID, successor, models.WorkspaceRoleOwner)
+			if err != nil {
+				return nil, nil, fmt.Errorf("hand over workspace: %w", err)
+			}
+		}
+	}
+
+	return owned, affected, nil
+}
+
+// deleteWorkspaceTx удаляет пространство, его задачи удаляются каскадно. Возвращает удаленные задачи
+// и задачи, которые зависели от них или были их подзадачами
+func deleteWorkspaceTx(ctx context.Context, tx pgx.Tx, workspaceID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id FROM todo WHERE workspace_id = $1
+			UNION
+			SELECT td.todo_id FROM todo_dependencies td JOIN todo ON todo.id = td.blocked_by WHERE todo.workspace_id = $1
+			UNION
+			SELECT sub.id FROM todo sub JOIN todo ON todo.id = sub.parent_id WHERE todo.workspace_id = $1`,
+		workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("get workspace todos: %w", err)
+	}
+
+	ids, err := scanIDs(rows)
+	if err != nil {
+		return nil, fmt.Errorf("get workspace todos: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM workspaces WHERE id = $1`, workspaceID); err != nil {
+		return nil, fmt.Errorf("delete workspace: %w", err)
+	}
+
+	return ids, nil
 }
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"todo/internal/models"
+)
+
+// headlineOptions - параметры ts_headline для фрагментов с найденными словами
+var headlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=2, MaxWords=20, MinWords=5",
+	models.SearchHighlightStart, models.SearchHighlightStop)
+
+// SearchTodos ищет по описаниям личных задач пользователя и комментариям к ним.
+// Ранг задачи - сумма рангов описания и самого подходящего комментария
+func (r *TodoRepository) SearchTodos(ctx context.Context, request *models.SearchTodosDTO) ([]models.SearchResultDAO, error) {
+	res := make([]models.SearchResultDAO, 0)
+
+	sql := `SELECT
+					` + todoColumns + `,
+					ts_rank(coalesce(todo.search_vector, ''), q.query) + coalesce(comment.rank, 0) AS rank,
+					CASE WHEN todo.search_vector @@ q.query
+						THEN ts_headline($1::regconfig, todo.description, q.query, $3)
+						ELSE ''
+					END AS description_snippet,
+					coalesce(ts_headline($1::regconfig, comment.body, q.query, $3), '') AS comment_snippet
+				FROM
+					todo
+					CROSS JOIN websearch_to_tsquery($1::regconfig, $2) AS q(query)
+					LEFT JOIN LATERAL (
+						SELECT body, ts_rank(search_vector, q.query) AS rank
+						FROM todo_comments
+						WHERE todo_id = todo.id AND deleted_at IS NULL AND search_vector @@ q.query
+						ORDER BY rank DESC
+						LIMIT 1
+					) comment ON true
+				WHERE
+					(todo.created_by = $4 OR todo.assignee = $4) AND todo.workspace_id IS NULL
+					AND (todo.search_vector @@ q.query OR comment.body IS NOT NULL)
+				ORDER BY rank DESC, todo.created_at DESC
+				LIMIT $5 OFFSET $6`
+
+	rows, err := r.conn.Query(ctx, sql,
+		r.searchLanguage, request.Query, headlineOptions, request.UserID, request.Limit, request.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("[SearchTodos] search todos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result models.SearchResultDAO
+
+		err = scanTodo(rows, &result.Todo, &result.Rank, &result.DescriptionSnippet, &result.CommentSnippet)
+		if err != nil {
+			return nil, fmt.Errorf("[SearchTodos] %w", err)
+		}
+
+		res = append(res, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[SearchTodos] rows: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+)
+
+// todoColumns - колонки задачи в порядке, который читает scanTodo. Подзапросы ссылаются на todo.id,
+// поэтому таблица задач в запросе должна называться todo
+const todoColumns = `todo.id, todo.created_by, todo.assignee, todo.description, todo.created_at, todo.updated_at, ` +
+	todoLabelsColumn + `, todo.parent_id, todo.done,
+	(SELECT count(*) FROM todo sub WHERE sub.parent_id = todo.id) AS subtasks_total,
+	(SELECT count(*) FROM todo sub WHERE sub.parent_id = todo.id AND sub.done) AS subtasks_done,
+	ARRAY(SELECT td.blocked_by::text FROM todo_dependencies td WHERE td.todo_id = todo.id ORDER BY td.blocked_by) AS blocked_by,
+	todo.due_at, todo.recurrence, todo.timezone, todo.series_id, todo.occurrence, todo.series_start,
+	todo.workspace_id, todo.version`
+
+// scanTodo читает строку с колонками todoColumns, extra - колонки, выбранные после них
+func scanTodo(row pgx.Row, dao *models.TodoDAO, extra ...interface{}) error {
+	var labels, blockedBy []string
+
+	dest := []interface{}{
+		&dao.ID,
+		&dao.CreatedBy,
+		&dao.Assignee,
+		&dao.Description,
+		&dao.CreatedAt,
+		&dao.UpdatedAt,
+		&labels,
+		&dao.ParentID,
+		&dao.Done,
+		&dao.SubtasksTotal,
+		&dao.SubtasksDone,
+		&blockedBy,
+		&dao.DueAt,
+		&dao.Recurrence,
+		&dao.Timezone,
+		&dao.SeriesID,
+		&dao.Occurrence,
+		&dao.SeriesStart,
+		&dao.WorkspaceID,
+		&dao.Version,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return fmt.Errorf("scan todo: %w", err)
+	}
+
+	var err error
+	if dao.Labels, err = parseIDs(labels); err != nil {
+		return fmt.Errorf("parse labels: %w", err)
+	}
+
+	if dao.BlockedBy, err = parseIDs(blockedBy); err != nil {
+		return fmt.Errorf("parse blockers: %w", err)
+	}
+
+	return nil
+}
+
+// ListSubtasks возвращает всех потомков задач rootIDs, видимых по фильтру: задачи пространства
+// или задачи пользователя. Ветка обрывается на подзадаче, которая не видна
+func (r *TodoRepository) ListSubtasks(ctx context.Context, rootIDs []uuid.UUID, filter *models.ListTodosDTO) ([]models.TodoDAO, error) {
+	rootVisible, rootArgs := visibleCondition("todo", filter)
+	childVisible, childArgs := visibleCondition("child", filter)
+
+	sql, err := squirrel.Dollar.ReplacePlaceholders(`WITH RECURSIVE subtree AS (
+					SELECT id FROM todo
+					WHERE parent_id = ANY(?) AND ` + rootVisible + `
+					UNION
+					SELECT child.id FROM todo child
+						JOIN subtree ON child.parent_id = subtree.id
+					WHERE ` + childVisible + `
+				)
+				SELECT ` + todoColumns + `
+				FROM
+					todo
+				WHERE
+					id IN (SELECT id FROM subtree)
+				ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("[ListSubtasks] build query: %w", err)
+	}
+
+	args := append(append([]interface{}{rootIDs}, rootArgs...), childArgs...)
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[ListSubtasks] get subtasks: %w", err)
+	}
+
+	res, err := scanTodos(rows)
+	if err != nil {
+		return nil, fmt.Errorf("[ListSubtasks] %w", err)
+	}
+
+	return res, nil
+}
+
+// CountOpenBlockers считает невыполненные задачи, которые блокируют задачу
+func (r *TodoRepository) CountOpenBlockers(ctx context.Context, todoID uuid.UUID) (int, error) {
+	var count int
+
+	sql := `SELECT count(*)
+				FROM
+					todo_dependencies td
+					JOIN todo blocker ON blocker.id = td.blocked_by
+				WHERE
+					td.todo_id = $1 AND NOT blocker.done`
+
+	if err := r.conn.QueryRow(ctx, sql, todoID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("[CountOpenBlockers] count blockers: %w", err)
+	}
+
+	return count, nil
+}
+
+// AddTodoDependency добавляет зависимость, если она не замыкает цикл, иначе возвращает ErrCycle.
+// Проверка и вставка идут в одной транзакции под общей блокировкой графа зависимостей,
+// чтобы две встречные зависимости не прошли проверку одновременно
+func (r *TodoRepository) AddTodoDependency(ctx context.Context, dependency *models.TodoDependencyDTO) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[AddTodoDependency] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('todo_dependencies'))`); err != nil {
+		return fmt.Errorf("[AddTodoDependency] lock dependencies: %w", err)
+	}
+
+	// задача не должна быть среди блокирующих задач blocked_by, включая транзитивные
+	sql := `WITH RECURSIVE blockers (id) AS (
+				SELECT $2::uuid
+				UNION
+				SELECT td.blocked_by FROM todo_dependencies td JOIN blockers b ON td.todo_id = b.id
+			)
+			SELECT EXISTS (SELECT 1 FROM blockers WHERE id = $1)`
+
+	var cycle bool
+	if err := tx.QueryRow(ctx, sql, dependency.TodoID, dependency.BlockedBy).Scan(&cycle); err != nil {
+		return fmt.Errorf("[AddTodoDependency] check cycle: %w", err)
+	}
+
+	if cycle {
+		return fmt.Errorf("[AddTodoDependency] %w", app_errors.ErrCycle)
+	}
+
+	sql = `INSERT INTO todo_dependencies (todo_id, blocked_by) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	if _, err := tx.Exec(ctx, sql, dependency.TodoID, dependency.BlockedBy); err != nil {
+		return fmt.Errorf("[AddTodoDependency] add dependency: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[AddTodoDependency] commit: %w", err)
+	}
+
+	return nil
+}
+
+// checkParentTx при смене родителя проверяет, что задача не становится подзадачей своего потомка,
+// иначе возвращает ErrCycle. Смены родителей выполняются по очереди под общей блокировкой,
+// чтобы две встречные смены не прошли проверку одновременно
+func checkParentTx(ctx context.Context, tx pgx.Tx, todoID uuid.UUID, parentID *uuid.UUID) error {
+	if parentID == nil {
+		return nil
+	}
+
+	var changed bool
+	err := tx.QueryRow(ctx, `SELECT parent_id IS DISTINCT FROM $2 FROM todo WHERE id = $1`, todoID, *parentID).
+		Scan(&changed)
+	if err != nil {
+		return fmt.Errorf("get parent: %w", err)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('todo_parents'))`); err != nil {
+		return fmt.Errorf("lock parents: %w", err)
+	}
+
+	sql := `WITH RECURSIVE ancestors (id) AS (
+				SELECT $2::uuid
+				UNION
+				SELECT todo.parent_id FROM todo JOIN ancestors a ON todo.id = a.id WHERE todo.parent_id IS NOT NULL
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $1)`
+
+	var cycle bool
+	if err := tx.QueryRow(ctx, sql, todoID, *parentID).Scan(&cycle); err != nil {
+		return fmt.Errorf("check parent cycle: %w", err)
+	}
+
+	if cycle {
+		return app_errors.ErrCycle
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) RemoveTodoDependency(ctx context.Context, dependency *models.TodoDependencyDTO) error {
+	sql := `DELETE FROM todo_dependencies WHERE todo_id = $1 AND blocked_by = $2`
+
+	if _, err := r.conn.Exec(ctx, sql, dependency.TodoID, dependency.BlockedBy); err != nil {
+		return fmt.Errorf("[RemoveTodoDependency] remove dependency: %w", err)
+	}
+
+	return nil
+}
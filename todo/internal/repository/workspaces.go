@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+)
+
+// visibleCondition возвращает условие видимости задачи alias: задачи рабочего пространства из фильтра
+// или личные задачи, созданные пользователем или назначенные на него. Задачи пространств видны только
+// через пространство, чтобы исключенный участник их больше не видел. Параметры обозначены ?
+func visibleCondition(alias string, filter *models.ListTodosDTO) (string, []interface{}) {
+	if filter.WorkspaceID != nil {
+		return alias + ".workspace_id = ?", []interface{}{*filter.WorkspaceID}
+	}
+
+	return fmt.Sprintf("(%[1]s.created_by = ? OR %[1]s.assignee = ?) AND %[1]s.workspace_id IS NULL", alias),
+		[]interface{}{filter.UserID, filter.UserID}
+}
+
+// CreateWorkspace создает пространство, создатель становится его владельцем
+func (r *TodoRepository) CreateWorkspace(ctx context.Context, workspace *models.WorkspaceDAO) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[CreateWorkspace] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO workspaces (id, name, created_by, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`,
+		workspace.ID, workspace.Name, workspace.CreatedBy, workspace.CreatedAt, workspace.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("[CreateWorkspace] create workspace: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)`,
+		workspace.ID, workspace.CreatedBy, models.WorkspaceRoleOwner, workspace.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("[CreateWorkspace] add owner: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[CreateWorkspace] commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) GetWorkspace(ctx context.Context, workspaceID uuid.UUID) (*models.WorkspaceDAO, error) {
+	var dao models.WorkspaceDAO
+
+	sql := `SELECT id, name, created_by, created_at, updated_at FROM workspaces WHERE id = $1`
+
+	err := r.conn.QueryRow(ctx, sql, workspaceID).
+		Scan(&dao.ID, &dao.Name, &dao.CreatedBy, &dao.CreatedAt, &dao.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[GetWorkspace] get workspace: %w", app_errors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("[GetWorkspace] get workspace: %w", err)
+	}
+
+	return &dao, nil
+}
+
+func (r *TodoRepository) UpdateWorkspace(ctx context.Context, workspace *models.WorkspaceDAO) error {
+	sql := `UPDATE workspaces SET name = $2, updated_at = $3 WHERE id = $1`
+
+	tag, err := r.conn.Exec(ctx, sql, workspace.ID, workspace.Name, workspace.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("[UpdateWorkspace] update workspace: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("[UpdateWorkspace] update workspace: %w", app_errors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteWorkspace удаляет пространство вместе с его задачами. Возвращает идентификаторы удаленных задач
+// и задач, которые ждали удаленные
+func (r *TodoRepository) DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteWorkspace] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	affected := make([]uuid.UUID, 0)
+
+	queries := []string{
+		`DELETE FROM todo_dependencies
+			WHERE blocked_by IN (SELECT id FROM todo WHERE workspace_id = $1) RETURNING todo_id`,
+		`DELETE FROM todo WHERE workspace_id = $1 RETURNING id`,
+	}
+
+	for _, sql := range queries {
+		rows, err := tx.Query(ctx, sql, workspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("[DeleteWorkspace] exec: %w", err)
+		}
+
+		ids, err := scanIDs(rows)
+		if err != nil {
+			return nil, fmt.Errorf("[DeleteWorkspace] %w", err)
+		}
+		affected = append(affected, ids...)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM workspaces WHERE id = $1`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteWorkspace] delete workspace: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil, fmt.Errorf("[DeleteWorkspace] delete workspace: %w", app_errors.ErrNotFound)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[DeleteWorkspace] commit: %w", err)
+	}
+
+	return affected, nil
+}
+
+// ListWorkspaces возвращает пространства, в которых состоит пользователь, вместе с его ролью
+func (r *TodoRepository) ListWorkspaces(ctx context.Context, userID int) ([]models.WorkspaceDAO, error) {
+	res := make([]models.WorkspaceDAO, 0)
+
+	sql := `SELECT w.id, w.name, w.created_by, w.created_at, w.updated_at, m.role
+				FROM
+					workspaces w
+					JOIN workspace_members m ON m.workspace_id = w.id
+				WHERE
+					m.user_id = $1
+				ORDER BY lower(w.name), w.id`
+
+	rows, err := r.conn.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListWorkspaces] get workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dao models.WorkspaceDAO
+		if err := rows.Scan(&dao.ID, &dao.Name, &dao.CreatedBy, &dao.CreatedAt, &dao.UpdatedAt, &dao.Role); err != nil {
+			return nil, fmt.Errorf("[ListWorkspaces] scan workspace: %w", err)
+		}
+
+		res = append(res, dao)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[ListWorkspaces] rows: %w", err)
+	}
+
+	return res, nil
+}
+
+func (r *TodoRepository) GetWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) (*models.WorkspaceMemberDAO, error) {
+	var dao models.WorkspaceMemberDAO
+
+	sql := `SELECT workspace_id, user_id, role, created_at FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`
+
+	err := r.conn.QueryRow(ctx, sql, workspaceID, userID).
+		Scan(&dao.WorkspaceID, &dao.UserID, &dao.Role, &dao.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[GetWorkspaceMember] get member: %w", app_errors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("[GetWorkspaceMember] get member: %w", err)
+	}
+
+	return &dao, nil
+}
+
+func (r *TodoRepository) ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]models.WorkspaceMemberDAO, error) {
+	res := make([]models.WorkspaceMemberDAO, 0)
+
+	sql := `SELECT workspace_id, user_id, role, created_at
+				FROM workspace_members
+				WHERE workspace_id = $1
+				ORDER BY created_at, user_id`
+
+	rows, err := r.conn.Query(ctx, sql, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListWorkspaceMembers] get members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dao models.WorkspaceMemberDAO
+		if err := rows.Scan(&dao.WorkspaceID, &dao.UserID, &dao.Role, &dao.CreatedAt); err != nil {
+			return nil, fmt.Errorf("[ListWorkspaceMembers] scan member: %w", err)
+		}
+
+		res = append(res, dao)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[ListWorkspaceMembers] rows: %w", err)
+	}
+
+	return res, nil
+}
+
+// UpdateWorkspaceMember меняет роль участника. Изменение, после которого в пространстве
+// не остается владельцев, отклоняется с ErrLastWorkspaceOwner
+func (r *TodoRepository) UpdateWorkspaceMember(ctx context.Context, member *models.WorkspaceMemberDAO) error {
+	err := r.changeMembers(ctx, member.WorkspaceID,
+		`UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2`,
+		member.WorkspaceID, member.UserID, member.Role)
+	if err != nil {
+		return fmt.Errorf("[UpdateWorkspaceMember] %w", err)
+	}
+
+	return nil
+}
+
+// RemoveWorkspaceMember исключает участника. Последнего владельца исключить нельзя
+func (r *TodoRepository) RemoveWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) error {
+	err := r.changeMembers(ctx, workspaceID,
+		`DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID, userID)
+	if err != nil {
+		return fmt.Errorf("[RemoveWorkspaceMember] %w", err)
+	}
+
+	return nil
+}
+
+// changeMembers выполняет изменение участника и проверяет, что у пространства остался владелец.
+// Строка пространства блокируется, чтобы два владельца не могли одновременно снять друг друга
+func (r *TodoRepository) changeMembers(ctx context.Context, workspaceID uuid.UUID, sql string, args ...interface{}) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT id FROM workspaces WHERE id = $1 FOR UPDATE`, workspaceID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("lock workspace: %w", app_errors.ErrNotFound)
+		}
+		return fmt.Errorf("lock workspace: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("change member: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("change member: %w", app_errors.ErrNotFound)
+	}
+
+	var owners int
+	err = tx.QueryRow(ctx,
+		`SELECT count(*) FROM workspace_members WHERE workspace_id = $1 AND role = $2`,
+		workspaceID, models.WorkspaceRoleOwner).Scan(&owners)
+	if err != nil {
+		return fmt.Errorf("count owners: %w", err)
+	}
+
+	if owners == 0 {
+		return app_errors.ErrLastWorkspaceOwner
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// CreateWorkspaceInvitation сохраняет приглашение. Прежнее приглашение на тот же адрес заменяется,
+// и ссылка из старого письма перестает работать
+func (r *TodoRepository) CreateWorkspaceInvitation(ctx context.Context, invitation *models.WorkspaceInvitationDAO) error {
+	sql := `INSERT INTO
+						workspace_invitations (id, workspace_id, email, role, invited_by, token_hash, created_at, expires_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				ON CONFLICT (workspace_id, lower(email)) DO UPDATE
+				SET id = EXCLUDED.id, email = EXCLUDED.email, role = EXCLUDED.role, invited_by = EXCLUDED.invited_by,
+					token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at`
+
+	_, err := r.conn.Exec(ctx, sql,
+		invitation.ID, invitation.WorkspaceID, invitation.Email, invitation.Role, invitation.InvitedBy,
+		invitation.TokenHash, invitation.CreatedAt, invitation.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("[CreateWorkspaceInvitation] create invitation: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorkspaceInvitation ищет действующее приглашение по sha256 токена
+func (r *TodoRepository) GetWorkspaceInvitation(ctx context.Context, tokenHash string) (*models.WorkspaceInvitationDAO, error) {
+	var dao models.WorkspaceInvitationDAO
+
+	sql := `SELECT id, workspace_id, email, role, invited_by, token_hash, created_at, expires_at
+				FROM workspace_invitations
+				WHERE token_hash = $1 AND expires_at > now()`
+
+	err := r.conn.QueryRow(ctx, sql, tokenHash).Scan(
+		&dao.ID, &dao.WorkspaceID, &dao.Email, &dao.Role, &dao.InvitedBy, &dao.TokenHash, &dao.CreatedAt, &dao.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[GetWorkspaceInvitation] get invitation: %w", app_errors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("[GetWorkspaceInvitation] get invitation: %w", err)
+	}
+
+	return &dao, nil
+}
+
+// AcceptWorkspaceInvitation удаляет приглашение и добавляет участника. Если пользователь уже
+// состоит в пространстве, его роль не меняется
+func (r *TodoRepository) AcceptWorkspaceInvitation(ctx context.Context, invitationID uuid.UUID, member *models.WorkspaceMemberDAO) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[AcceptWorkspaceInvitation] begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM workspace_invitations WHERE id = $1`, invitationID)
+	if err != nil {
+		return fmt.Errorf("[AcceptWorkspaceInvitation] delete invitation: %w", err)
+	}
+
+	// приглашение уже принято параллельным запросом
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("[AcceptWorkspaceInvitation] delete invitation: %w", app_errors.ErrNotFound)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO workspace_members (workspace_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING`,
+		member.WorkspaceID, member.UserID, member.Role, member.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("[AcceptWorkspaceInvitation] add member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[AcceptWorkspaceInvitation] commit: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"todo/internal/models"
+)
+
+func TestVisibleCondition(t *testing.T) {
+	workspaceID := uuid.New()
+
+	tests := []struct {
+		name         string
+		filter       *models.ListTodosDTO
+		expectedSQL  string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "WorkspaceTodos",
+			filter:       &models.ListTodosDTO{UserID: 1, WorkspaceID: &workspaceID},
+			expectedSQL:  "todo.workspace_id = ?",
+			expectedArgs: []interface{}{workspaceID},
+		},
+		{
+			// исключенный участник остается автором или исполнителем задач пространства,
+			// но без фильтра по пространству видит только личные задачи
+			name:         "RemovedMemberSeesOnlyPersonalTodos",
+			filter:       &models.ListTodosDTO{UserID: 1},
+			expectedSQL:  "(todo.created_by = ? OR todo.assignee = ?) AND todo.workspace_id IS NULL",
+			expectedArgs: []interface{}{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := visibleCondition("todo", tt.filter)
+
+			require.Equal(t, tt.expectedSQL, sql)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
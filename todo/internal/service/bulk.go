@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+	"todo/pkg/validator"
+)
+
+// BulkUpdateTodos применяет операцию к нескольким задачам, доступ к которым уже проверил gateway.
+// Задачи сохраняются транзакциями по BulkChunkSize штук. Задача, к которой операция не применилась,
+// не мешает остальным и попадает в результат с причиной. Каждый исполнитель получает одно письмо
+// со всеми своими измененными задачами
+func (t *TodoService) BulkUpdateTodos(ctx context.Context, request *models.BulkUpdateTodosDTO) (*models.BulkUpdateTodosResultDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.BulkUpdateTodos")
+	defer span.Finish()
+
+	if err := t.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[BulkUpdateTodos] validate:%w", err)
+	}
+
+	request.IDs = uniqueIDs(request.IDs)
+	request.AddLabelIDs = uniqueIDs(request.AddLabelIDs)
+	request.RemoveLabelIDs = uniqueIDs(request.RemoveLabelIDs)
+
+	var addLabels []*models.LabelDAO
+	if request.Operation == models.BulkOperationRelabel {
+		labels, err := t.checkBulkLabels(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("[BulkUpdateTodos] %w", err)
+		}
+		addLabels = labels
+	}
+
+	failed := make(map[uuid.UUID]string)
+	changed := make([]*models.TodoDAO, 0, len(request.IDs))
+
+	for _, chunk := range chunkIDs(request.IDs, models.BulkChunkSize) {
+		todos := make([]*models.TodoDAO, 0, len(chunk))
+		for _, id := range chunk {
+			todo, err := t.getTodo(ctx, id)
+			if err != nil {
+				if errors.Is(err, app_errors.ErrNotFound) {
+					failed[id] = models.BulkErrorNotFound
+					continue
+				}
+				return nil, fmt.Errorf("[BulkUpdateTodos] get todo:%w", err)
+			}
+
+			todos = append(todos, todo)
+		}
+
+		applied, err := t.applyBulkChunk(ctx, request, todos, addLabels, failed)
+		if err != nil {
+			return nil, fmt.Errorf("[BulkUpdateTodos] %s:%w", request.Operation, err)
+		}
+
+		changed = append(changed, applied...)
+	}
+
+	if err := t.notifyBulk(ctx, request, changed); err != nil {
+		return nil, fmt.Errorf("[BulkUpdateTodos] notify:%w", err)
+	}
+
+	result := &models.BulkUpdateTodosResultDTO{Results: make([]models.BulkItemResultDTO, 0, len(request.IDs))}
+	for _, id := range request.IDs {
+		if errCode, ok := failed[id]; ok {
+			result.Fail(id, errCode)
+		} else {
+			result.Succeed(id)
+		}
+	}
+
+	return result, nil
+}
+
+// applyBulkChunk применяет операцию к задачам одной транзакцией. Причины отказа по задачам
+// записываются в failed. Возвращает задачи, которые действительно изменились
+func (t *TodoService) applyBulkChunk(
+	ctx context.Context,
+	request *models.BulkUpdateTodosDTO,
+	todos []*models.TodoDAO,
+	addLabels []*models.LabelDAO,
+	failed map[uuid.UUID]string,
+) ([]*models.TodoDAO, error) {
+	if len(todos) == 0 {
+		return nil, nil
+	}
+
+	switch request.Operation {
+	case models.BulkOperationRelabel:
+		ids := make([]uuid.UUID, 0, len(todos))
+		relabeled := make([]*models.TodoDAO, 0, len(todos))
+		for _, todo := range todos {
+			if !labelsFitTodo(addLabels, todo) {
+				failed[todo.ID] = models.BulkErrorLabelNotInWorkspace
+				continue
+			}
+
+			ids = append(ids, todo.ID)
+			relabeled = append(relabeled, todo)
+		}
+
+		if len(ids) == 0 {
+			return nil, nil
+		}
+
+		if err := t.todoRepo.RelabelToDos(ctx, ids, request.AddLabelIDs, request.RemoveLabelIDs); err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			t.todoRedis.FlushCache(ctx, id)
+		}
+
+		return relabeled, nil
+
+	case models.BulkOperationDelete:
+		rejected, affected, err := t.todoRepo.DeleteToDos(ctx, todos)
+		if err != nil {
+			return nil, err
+		}
+
+		deleted := make([]*models.TodoDAO, 0, len(todos))
+		for _, todo := range todos {
+			if err, ok := rejected[todo.ID]; ok {
+				failed[todo.ID] = bulkErrorCode(err)
+				continue
+			}
+
+			t.todoRedis.FlushCache(ctx, todo.ID)
+			t.flushParents(ctx, todo.ParentID)
+			deleted = append(deleted, todo)
+		}
+		for _, id := range affected {
+			t.todoRedis.FlushCache(ctx, id)
+		}
+
+		return deleted, nil
+
+	default:
+		return t.updateBulkChunk(ctx, request, todos, failed)
+	}
+}
+
+// updateBulkChunk назначает задачи на другого исполнителя или отмечает их выполненными.
+// Задачи, которые уже в нужном состоянии, считаются успешно обработанными, но не сохраняются
+func (t *TodoService) updateBulkChunk(
+	ctx context.Context,
+	request *models.BulkUpdateTodosDTO,
+	todos []*models.TodoDAO,
+	failed map[uuid.UUID]string,
+) ([]*models.TodoDAO, error) {
+	now := time.Now().UTC()
+
+	updated := make([]*models.TodoDAO, 0, len(todos))
+	events := make([]models.TodoEventDAO, 0, len(todos))
+
+	for _, todo := range todos {
+		before := *todo
+
+		switch request.Operation {
+		case models.BulkOperationReassign:
+			if todo.Assignee == request.Assignee {
+				continue
+			}
+			todo.Assignee = request.Assignee
+
+		case models.BulkOperationComplete:
+			if todo.Done {
+				continue
+			}
+
+			if err := t.checkNotBlocked(ctx, todo.ID); err != nil {
+				if errors.Is(err, app_errors.ErrTodoBlocked) {
+					failed[todo.ID] = models.BulkErrorBlocked
+					continue
+				}
+				return nil, err
+			}
+			todo.Done = true
+		}
+
+		todo.UpdatedAt = now
+		updated = append(updated, todo)
+		events = append(events, todoChanges(&before, todo, request.Actor, now)...)
+	}
+
+	if len(updated) == 0 {
+		return nil, nil
+	}
+
+	rejected, err := t.todoRepo.UpdateToDos(ctx, updated, events)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := make([]*models.TodoDAO, 0, len(updated))
+	for _, todo := range updated {
+		if err, ok := rejected[todo.ID]; ok {
+			failed[todo.ID] = bulkErrorCode(err)
+			continue
+		}
+
+		t.todoRedis.StoreCache(ctx, todo)
+		saved = append(saved, todo)
+	}
+
+	if request.Operation == models.BulkOperationComplete {
+		for _, todo := range saved {
+			t.flushParents(ctx, todo.ParentID)
+
+			if todo.Recurrence != "" {
+				if err := t.spawnNext(ctx, todo, now); err != nil {
+					return nil, fmt.Errorf("spawn next occurrence:%w", err)
+				}
+			}
+		}
+	}
+
+	return saved, nil
+}
+
+// checkBulkLabels проверяет, что relabel меняет хотя бы одну метку и все метки доступны отправителю.
+// Возвращает добавляемые метки
+func (t *TodoService) checkBulkLabels(ctx context.Context, request *models.BulkUpdateTodosDTO) ([]*models.LabelDAO, error) {
+	if len(request.AddLabelIDs) == 0 && len(request.RemoveLabelIDs) == 0 {
+		return nil, validator.NewValidationError(validator.FieldViolation{
+			Field:   "add_label_ids",
+			Message: "relabel needs labels to add or remove",
+		})
+	}
+
+	add := make([]*models.LabelDAO, 0, len(request.AddLabelIDs))
+	for _, labelID := range request.AddLabelIDs {
+		label, err := t.getUsableLabel(ctx, labelID, request.Actor)
+		if err != nil {
+			return nil, fmt.Errorf("get label:%w", err)
+		}
+		add = append(add, label)
+	}
+
+	for _, labelID := range request.RemoveLabelIDs {
+		if _, err := t.getUsableLabel(ctx, labelID, request.Actor); err != nil {
+			return nil, fmt.Errorf("get label:%w", err)
+		}
+	}
+
+	return add, nil
+}
+
+// labelsFitTodo - все добавляемые метки из того же рабочего пространства, что и задача
+func labelsFitTodo(labels []*models.LabelDAO, todo *models.TodoDAO) bool {
+	for _, label := range labels {
+		if !labelFitsTodo(label, todo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// notifyBulk отправляет каждому исполнителю измененных задач одно письмо со списком этих задач.
+// Отправителю запроса письмо не отправляется, удаленные пользователи пропускаются
+func (t *TodoService) notifyBulk(ctx context.Context, request *models.BulkUpdateTodosDTO, changed []*models.TodoDAO) error {
+	byAssignee := make(map[int][]models.MailTodo)
+	assignees := make([]int, 0)
+	for _, todo := range changed {
+		if todo.Assignee == request.Actor {
+			continue
+		}
+
+		if _, ok := byAssignee[todo.Assignee]; !ok {
+			assignees = append(assignees, todo.Assignee)
+		}
+		byAssignee[todo.Assignee] = append(byAssignee[todo.Assignee], models.MailTodo{
+			ID:          todo.ID,
+			Description: todo.Description,
+		})
+	}
+
+	if len(assignees) == 0 {
+		return nil
+	}
+
+	author, err := t.usersClient.GetUserByID(ctx, request.Actor)
+	if err != nil {
+		return fmt.Errorf("[notifyBulk] get author:%w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	for _, userID := range assignees {
+		user, err := t.usersClient.GetUserByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, app_errors.ErrNotFound) {
+				continue
+			}
+			return fmt.Errorf("[notifyBulk] get receiver:%w", err)
+		}
+
+		if user.Email == "" {
+			continue
+		}
+
+		data, err := json.Marshal(models.TodoMailItem{
+			TodoEventType: models.TodoEventTypeBulkUpdated,
+			Receivers:     []string{user.Email},
+			Author:        author.Username,
+			Operation:     request.Operation,
+			Todos:         byAssignee[userID],
+		})
+		if err != nil {
+			return fmt.Errorf("[notifyBulk] marshal mssg:%w", err)
+		}
+
+		if err := t.todoRabbitProducer.Publish(data, requestID); err != nil {
+			return fmt.Errorf("[notifyBulk] publish mssg:%w", err)
+		}
+	}
+
+	return nil
+}
+
+// bulkErrorCode - причина, по которой задача не сохранилась в транзакции пачки
+func bulkErrorCode(err error) string {
+	if errors.Is(err, app_errors.ErrVersionConflict) {
+		return models.BulkErrorVersionConflict
+	}
+
+	return models.BulkErrorNotFound
+}
+
+// chunkIDs делит идентификаторы на части не больше size
+func chunkIDs(ids []uuid.UUID, size int) [][]uuid.UUID {
+	chunks := make([][]uuid.UUID, 0, (len(ids)+size-1)/size)
+	for size < len(ids) {
+		chunks = append(chunks, ids[:size])
+		ids = ids[size:]
+	}
+
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+
+	return chunks
+}
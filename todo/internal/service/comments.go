@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+func (t *TodoService) AddComment(ctx context.Context, comment *models.CreateCommentDTO) (*models.CommentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AddComment")
+	defer span.Finish()
+
+	if err := t.validator.Validate(comment); err != nil {
+		return nil, fmt.Errorf("[AddComment] validate:%w", err)
+	}
+
+	todo, err := t.getTodo(ctx, comment.TodoID)
+	if err != nil {
+		return nil, fmt.Errorf("[AddComment] get todo:%w", err)
+	}
+
+	newComment := &models.CommentDAO{
+		ID:        uuid.New(),
+		TodoID:    comment.TodoID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := t.todoRepo.CreateComment(ctx, newComment); err != nil {
+		return nil, fmt.Errorf("[AddComment] store comment:%w", err)
+	}
+
+	if err := t.notifyComment(ctx, todo, newComment); err != nil {
+		return nil, fmt.Errorf("[AddComment] notify:%w", err)
+	}
+
+	return (*models.CommentDTO)(newComment), nil
+}
+
+// EditComment меняет текст комментария. Редактировать может только автор
+func (t *TodoService) EditComment(ctx context.Context, comment *models.UpdateCommentDTO) (*models.CommentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.EditComment")
+	defer span.Finish()
+
+	if err := t.validator.Validate(comment); err != nil {
+		return nil, fmt.Errorf("[EditComment] validate:%w", err)
+	}
+
+	existing, err := t.getComment(ctx, comment.TodoID, comment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[EditComment] get comment:%w", err)
+	}
+
+	if existing.Author != comment.Author {
+		return nil, fmt.Errorf("[EditComment] not an author:%w", app_errors.ErrForbidden)
+	}
+
+	editedAt := time.Now().UTC()
+	existing.Body = comment.Body
+	existing.EditedAt = &editedAt
+
+	if err := t.todoRepo.UpdateComment(ctx, existing); err != nil {
+		return nil, fmt.Errorf("[EditComment] update comment:%w", err)
+	}
+
+	return (*models.CommentDTO)(existing), nil
+}
+
+// DeleteComment удаляет комментарий. Автор может удалить свой комментарий,
+// модератор - любой комментарий задачи
+func (t *TodoService) DeleteComment(ctx context.Context, comment *models.DeleteCommentDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteComment")
+	defer span.Finish()
+
+	existing, err := t.getComment(ctx, comment.TodoID, comment.ID)
+	if err != nil {
+		return fmt.Errorf("[DeleteComment] get comment:%w", err)
+	}
+
+	if !comment.Moderator && existing.Author != comment.DeletedBy {
+		return fmt.Errorf("[DeleteComment] not an author:%w", app_errors.ErrForbidden)
+	}
+
+	if err := t.todoRepo.DeleteComment(ctx, existing.ID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("[DeleteComment] delete comment:%w", err)
+	}
+
+	return nil
+}
+
+func (t *TodoService) ListComments(ctx context.Context, request *models.ListCommentsDTO) (*models.CommentListDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListComments")
+	defer span.Finish()
+
+	if err := t.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[ListComments] validate:%w", err)
+	}
+
+	if request.Limit == 0 {
+		request.Limit = models.CommentsDefaultLimit
+	}
+
+	if _, err := t.getTodo(ctx, request.TodoID); err != nil {
+		return nil, fmt.Errorf("[ListComments] get todo:%w", err)
+	}
+
+	comments, total, err := t.todoRepo.ListComments(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("[ListComments] get comments:%w", err)
+	}
+
+	res := &models.CommentListDTO{
+		Comments: make([]*models.CommentDTO, 0, len(comments)),
+		Total:    total,
+	}
+	for i := range comments {
+		res.Comments = append(res.Comments, (*models.CommentDTO)(&comments[i]))
+	}
+
+	return res, nil
+}
+
+// getComment ищет комментарий и проверяет, что он относится к указанной задаче
+func (t *TodoService) getComment(ctx context.Context, todoID, commentID uuid.UUID) (*models.CommentDAO, error) {
+	comment, err := t.todoRepo.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.TodoID != todoID {
+		return nil, app_errors.ErrNotFound
+	}
+
+	return comment, nil
+}
+
+// notifyComment отправляет письмо автору и исполнителю задачи.
+// Автору комментария письмо не отправляется, удаленные пользователи пропускаются
+func (t *TodoService) notifyComment(ctx context.Context, todo *models.TodoDAO, comment *models.CommentDAO) error {
+	receiverIDs := make([]int, 0, 2)
+	for _, userID := range []int{todo.CreatedBy, todo.Assignee} {
+		if userID == comment.Author || (len(receiverIDs) > 0 && receiverIDs[0] == userID) {
+			continue
+		}
+		receiverIDs = append(receiverIDs, userID)
+	}
+
+	if len(receiverIDs) == 0 {
+		return nil
+	}
+
+	author, err := t.usersClient.GetUserByID(ctx, comment.Author)
+	if err != nil {
+		return fmt.Errorf("[notifyComment] get author:%w", err)
+	}
+
+	receivers := make([]string, 0, len(receiverIDs))
+	for _, userID := range receiverIDs {
+		user, err := t.usersClient.GetUserByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, app_errors.ErrNotFound) {
+				continue
+			}
+			return fmt.Errorf("[notifyComment] get receiver:%w", err)
+		}
+
+		if user.Email != "" {
+			receivers = append(receivers, user.Email)
+		}
+	}
+
+	if len(receivers) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(models.TodoMailItem{
+		TodoEventType:   models.TodoEventTypeCommentAdded,
+		Receivers:       receivers,
+		TodoID:          todo.ID,
+		TodoDescription: todo.Description,
+		Author:          author.Username,
+		CommentBody:     comment.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("[notifyComment] marshal mssg:%w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	if err := t.todoRabbitProducer.Publish(data, requestID); err != nil {
+		return fmt.Errorf("[notifyComment] publish mssg:%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"strconv"
+	"time"
+	"todo/internal/models"
+)
+
+// ListTodoHistory возвращает изменения задачи от новых к старым
+func (t *TodoService) ListTodoHistory(ctx context.Context, request *models.ListTodoHistoryDTO) (*models.TodoEventListDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListTodoHistory")
+	defer span.Finish()
+
+	if err := t.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[ListTodoHistory] validate:%w", err)
+	}
+
+	if request.Limit == 0 {
+		request.Limit = models.TodoHistoryDefaultLimit
+	}
+
+	if _, err := t.getTodo(ctx, request.TodoID); err != nil {
+		return nil, fmt.Errorf("[ListTodoHistory] get todo:%w", err)
+	}
+
+	events, total, err := t.todoRepo.ListTodoHistory(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("[ListTodoHistory] get events:%w", err)
+	}
+
+	res := &models.TodoEventListDTO{
+		Events: make([]*models.TodoEventDTO, 0, len(events)),
+		Total:  total,
+	}
+	for i := range events {
+		res.Events = append(res.Events, (*models.TodoEventDTO)(&events[i]))
+	}
+
+	return res, nil
+}
+
+// todoChanges сравнивает задачу до и после изменения и возвращает по событию на каждое измененное поле
+func todoChanges(before, after *models.TodoDAO, actor int, at time.Time) []models.TodoEventDAO {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{models.TodoFieldAssignee, strconv.Itoa(before.Assignee), strconv.Itoa(after.Assignee)},
+		{models.TodoFieldDescription, before.Description, after.Description},
+		{models.TodoFieldParentID, optionalUUIDValue(before.ParentID), optionalUUIDValue(after.ParentID)},
+		{models.TodoFieldDone, strconv.FormatBool(before.Done), strconv.FormatBool(after.Done)},
+		{models.TodoFieldDueAt, optionalTimeValue(before.DueAt), optionalTimeValue(after.DueAt)},
+		{models.TodoFieldRecurrence, before.Recurrence, after.Recurrence},
+		{models.TodoFieldTimezone, before.Timezone, after.Timezone},
+	}
+
+	var events []models.TodoEventDAO
+	for _, f := range fields {
+		if f.old == f.new {
+			continue
+		}
+
+		events = append(events, models.TodoEventDAO{
+			ID:        uuid.New(),
+			TodoID:    after.ID,
+			Actor:     actor,
+			Field:     f.name,
+			OldValue:  f.old,
+			NewValue:  f.new,
+			CreatedAt: at,
+		})
+	}
+
+	return events
+}
+
+func optionalUUIDValue(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func optionalTimeValue(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
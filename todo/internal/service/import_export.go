@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	"todo/internal/models"
+	"todo/pkg/validator"
+)
+
+// ExportTodos передает в send все задачи, подходящие под фильтр, читая их из базы по ExportPageSize штук
+func (t *TodoService) ExportTodos(ctx context.Context, filter *models.ListTodosDTO, send func(todo *models.TodoDTO) error) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ExportTodos")
+	defer span.Finish()
+
+	filter.Limit = models.ExportPageSize
+	filter.Offset = 0
+	if err := t.prepareListFilter(filter); err != nil {
+		return fmt.Errorf("[ExportTodos] validate:%w", err)
+	}
+
+	for {
+		todos, err := t.todoRepo.ListToDos(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("[ExportTodos] get todos:%w", err)
+		}
+
+		for i := range todos {
+			if err := send((*models.TodoDTO)(&todos[i])); err != nil {
+				return fmt.Errorf("[ExportTodos] send:%w", err)
+			}
+		}
+
+		if len(todos) < filter.Limit {
+			return nil
+		}
+		filter.Offset += filter.Limit
+	}
+}
+
+// ImportTodos проверяет каждую строку импорта и создает задачи одной транзакцией.
+// Если хотя бы одна строка с ошибкой или задан DryRun, ничего не сохраняется
+func (t *TodoService) ImportTodos(ctx context.Context, request *models.ImportTodosDTO) (*models.ImportTodosResultDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ImportTodos")
+	defer span.Finish()
+
+	if err := t.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[ImportTodos] validate:%w", err)
+	}
+
+	now := time.Now().UTC()
+	result := &models.ImportTodosResultDTO{Rows: make([]models.ImportRowResultDTO, 0, len(request.Rows))}
+	for _, row := range request.Rows {
+		violations, err := t.prepareImportTodo(row.Todo, now)
+		if err != nil {
+			return nil, fmt.Errorf("[ImportTodos] row %d:%w", row.Row, err)
+		}
+
+		if len(violations) > 0 {
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, models.ImportRowResultDTO{Row: row.Row, Errors: violations})
+	}
+
+	if request.DryRun || result.Failed > 0 || len(request.Rows) == 0 {
+		return result, nil
+	}
+
+	todos := make([]*models.CreateTodoDTO, 0, len(request.Rows))
+	for _, row := range request.Rows {
+		todos = append(todos, row.Todo)
+	}
+
+	if err := t.todoRepo.CreateToDos(ctx, todos); err != nil {
+		return nil, fmt.Errorf("[ImportTodos] store todos:%w", err)
+	}
+
+	for i, newTodo := range todos {
+		t.todoRedis.StoreCache(ctx, newTodo.ToDAO())
+		result.Rows[i].ID = &newTodo.ID
+	}
+	result.Imported = len(todos)
+
+	return result, nil
+}
+
+// prepareImportTodo проверяет задачу из строки импорта и заполняет поля, которые при создании задает сервис.
+// Ошибки в полях возвращаются списком, а не ошибкой, чтобы проверить все строки
+func (t *TodoService) prepareImportTodo(newTodo *models.CreateTodoDTO, now time.Time) ([]validator.FieldViolation, error) {
+	var validationErr *validator.ValidationError
+	if err := t.validator.Validate(newTodo); err != nil {
+		if !errors.As(err, &validationErr) {
+			return nil, err
+		}
+		return validationErr.Violations, nil
+	}
+
+	if err := prepareRecurrence(&newTodo.Recurrence, &newTodo.Timezone); err != nil {
+		if !errors.As(err, &validationErr) {
+			return nil, err
+		}
+		return validationErr.Violations, nil
+	}
+
+	if newTodo.Recurrence != "" {
+		newTodo.SeriesID = &newTodo.ID
+		newTodo.Occurrence = 1
+		newTodo.SeriesStart = newTodo.DueAt
+	}
+
+	newTodo.CreatedAt = now
+	newTodo.UpdatedAt = now
+
+	return nil, nil
+}
@@ -3,16 +3,57 @@ package service
 import (
 	"context"
 	"github.com/google/uuid"
+	"time"
 	"todo/internal/models"
 )
 
 //go:generate mockgen --build_flags=-mod=mod -destination=./mocks/todo_repository.go -package=mocks todo/internal/service TodoRepository
 type TodoRepository interface {
 	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (uuid.UUID, error)
-	UpdateToDo(ctx context.Context, updateTodo *models.TodoDAO) (uuid.UUID, error)
+	CreateToDos(ctx context.Context, todos []*models.CreateTodoDTO) error
+	UpdateToDo(ctx context.Context, updateTodo *models.TodoDAO, events []models.TodoEventDAO) (uuid.UUID, error)
 	GetToDos(ctx context.Context, todoID uuid.UUID) ([]models.TodoDAO, error)
 	GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDAO, error)
-	DeleteToDo(ctx context.Context, todoID uuid.UUID) error
+	DeleteToDo(ctx context.Context, todoID uuid.UUID, version int) ([]uuid.UUID, error)
+	UpdateToDos(ctx context.Context, todos []*models.TodoDAO, events []models.TodoEventDAO) (map[uuid.UUID]error, error)
+	DeleteToDos(ctx context.Context, todos []*models.TodoDAO) (map[uuid.UUID]error, []uuid.UUID, error)
+	RelabelToDos(ctx context.Context, todoIDs, add, remove []uuid.UUID) error
+	GetUserToDos(ctx context.Context, userID int) ([]models.TodoDAO, error)
+	DetachUser(ctx context.Context, userID int) ([]uuid.UUID, error)
+	CreateComment(ctx context.Context, comment *models.CommentDAO) error
+	GetComment(ctx context.Context, commentID uuid.UUID) (*models.CommentDAO, error)
+	UpdateComment(ctx context.Context, comment *models.CommentDAO) error
+	DeleteComment(ctx context.Context, commentID uuid.UUID, deletedAt time.Time) error
+	ListComments(ctx context.Context, request *models.ListCommentsDTO) ([]models.CommentDAO, int, error)
+	ListToDos(ctx context.Context, filter *models.ListTodosDTO) ([]models.TodoDAO, error)
+	CreateLabel(ctx context.Context, label *models.LabelDAO) error
+	GetLabel(ctx context.Context, labelID uuid.UUID) (*models.LabelDAO, error)
+	UpdateLabel(ctx context.Context, label *models.LabelDAO) error
+	DeleteLabel(ctx context.Context, labelID uuid.UUID) ([]uuid.UUID, error)
+	ListLabels(ctx context.Context, owner int, workspaceID *uuid.UUID) ([]models.LabelDAO, error)
+	AddTodoLabel(ctx context.Context, todoID, labelID uuid.UUID) error
+	RemoveTodoLabel(ctx context.Context, todoID, labelID uuid.UUID) error
+	SearchTodos(ctx context.Context, request *models.SearchTodosDTO) ([]models.SearchResultDAO, error)
+	ListSubtasks(ctx context.Context, rootIDs []uuid.UUID, filter *models.ListTodosDTO) ([]models.TodoDAO, error)
+	CountOpenBlockers(ctx context.Context, todoID uuid.UUID) (int, error)
+	AddTodoDependency(ctx context.Context, dependency *models.TodoDependencyDTO) error
+	RemoveTodoDependency(ctx context.Context, dependency *models.TodoDependencyDTO) error
+	CreateOccurrence(ctx context.Context, prevID uuid.UUID, next *models.TodoDAO) (bool, error)
+	FinishSeries(ctx context.Context, todoID uuid.UUID) error
+	ListDueRecurring(ctx context.Context, now time.Time, limit int) ([]models.TodoDAO, error)
+	ListTodoHistory(ctx context.Context, request *models.ListTodoHistoryDTO) ([]models.TodoEventDAO, int, error)
+	CreateWorkspace(ctx context.Context, workspace *models.WorkspaceDAO) error
+	GetWorkspace(ctx context.Context, workspaceID uuid.UUID) (*models.WorkspaceDAO, error)
+	UpdateWorkspace(ctx context.Context, workspace *models.WorkspaceDAO) error
+	DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]uuid.UUID, error)
+	ListWorkspaces(ctx context.Context, userID int) ([]models.WorkspaceDAO, error)
+	GetWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) (*models.WorkspaceMemberDAO, error)
+	ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]models.WorkspaceMemberDAO, error)
+	UpdateWorkspaceMember(ctx context.Context, member *models.WorkspaceMemberDAO) error
+	RemoveWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) error
+	CreateWorkspaceInvitation(ctx context.Context, invitation *models.WorkspaceInvitationDAO) error
+	GetWorkspaceInvitation(ctx context.Context, tokenHash string) (*models.WorkspaceInvitationDAO, error)
+	AcceptWorkspaceInvitation(ctx context.Context, invitationID uuid.UUID, member *models.WorkspaceMemberDAO) error
 }
 
 //go:generate mockgen --build_flags=-mod=mod -destination=./mocks/rabbit_producer.go -package=mocks todo/internal/service RabbitProducer
@@ -23,6 +64,12 @@ type RabbitProducer interface {
 //go:generate mockgen --build_flags=-mod=mod -destination=./mocks/todo_redis_manager.go -package=mocks todo/internal/service TodoRedisManager
 type TodoRedisManager interface {
 	GetCacheByTodoID(ctx context.Context, todoID uuid.UUID) (*models.TodoDAO, error)
+	GetCacheByTodoVersion(ctx context.Context, todoID uuid.UUID, version int) (*models.TodoDAO, error)
 	StoreCache(ctx context.Context, todo *models.TodoDAO)
 	FlushCache(ctx context.Context, todoID uuid.UUID)
 }
+
+//go:generate mockgen --build_flags=-mod=mod -destination=./mocks/users_client.go -package=mocks todo/internal/service UsersServiceClient
+type UsersServiceClient interface {
+	GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error)
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/pkg/validator"
+)
+
+func (t *TodoService) CreateLabel(ctx context.Context, label *models.CreateLabelDTO) (*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateLabel")
+	defer span.Finish()
+
+	if err := t.validator.Validate(label); err != nil {
+		return nil, fmt.Errorf("[CreateLabel] validate:%w", err)
+	}
+
+	newLabel := &models.LabelDAO{
+		ID:          uuid.New(),
+		Owner:       label.Owner,
+		Name:        label.Name,
+		Color:       label.Color,
+		CreatedAt:   time.Now().UTC(),
+		WorkspaceID: label.WorkspaceID,
+	}
+
+	if err := t.todoRepo.CreateLabel(ctx, newLabel); err != nil {
+		return nil, fmt.Errorf("[CreateLabel] store label:%w", err)
+	}
+
+	return (*models.LabelDTO)(newLabel), nil
+}
+
+func (t *TodoService) UpdateLabel(ctx context.Context, label *models.UpdateLabelDTO) (*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateLabel")
+	defer span.Finish()
+
+	if err := t.validator.Validate(label); err != nil {
+		return nil, fmt.Errorf("[UpdateLabel] validate:%w", err)
+	}
+
+	existing, err := t.getUsableLabel(ctx, label.ID, label.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateLabel] get label:%w", err)
+	}
+
+	existing.Name = label.Name
+	existing.Color = label.Color
+
+	if err := t.todoRepo.UpdateLabel(ctx, existing); err != nil {
+		return nil, fmt.Errorf("[UpdateLabel] update label:%w", err)
+	}
+
+	return (*models.LabelDTO)(existing), nil
+}
+
+// DeleteLabel удаляет метку и сбрасывает кэш задач, с которых она была снята
+func (t *TodoService) DeleteLabel(ctx context.Context, labelID uuid.UUID, owner int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteLabel")
+	defer span.Finish()
+
+	if _, err := t.getUsableLabel(ctx, labelID, owner); err != nil {
+		return fmt.Errorf("[DeleteLabel] get label:%w", err)
+	}
+
+	affected, err := t.todoRepo.DeleteLabel(ctx, labelID)
+	if err != nil {
+		return fmt.Errorf("[DeleteLabel] delete label:%w", err)
+	}
+
+	for _, todoID := range affected {
+		t.todoRedis.FlushCache(ctx, todoID)
+	}
+
+	return nil
+}
+
+// GetLabel возвращает метку без проверки доступа, ее выполняет gateway
+func (t *TodoService) GetLabel(ctx context.Context, labelID uuid.UUID) (*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetLabel")
+	defer span.Finish()
+
+	label, err := t.todoRepo.GetLabel(ctx, labelID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetLabel] get label:%w", err)
+	}
+
+	return (*models.LabelDTO)(label), nil
+}
+
+// ListLabels возвращает метки рабочего пространства, если оно задано, иначе личные метки пользователя
+func (t *TodoService) ListLabels(ctx context.Context, request *models.ListLabelsDTO) ([]*models.LabelDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListLabels")
+	defer span.Finish()
+
+	labels, err := t.todoRepo.ListLabels(ctx, request.UserID, request.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListLabels] get labels:%w", err)
+	}
+
+	res := make([]*models.LabelDTO, 0, len(labels))
+	for i := range labels {
+		res = append(res, (*models.LabelDTO)(&labels[i]))
+	}
+
+	return res, nil
+}
+
+func (t *TodoService) AddTodoLabel(ctx context.Context, request *models.TodoLabelDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AddTodoLabel")
+	defer span.Finish()
+
+	todo, label, err := t.checkTodoLabel(ctx, request)
+	if err != nil {
+		return fmt.Errorf("[AddTodoLabel] %w", err)
+	}
+
+	if !labelFitsTodo(label, todo) {
+		return fmt.Errorf("[AddTodoLabel] validate:%w", validator.NewValidationError(validator.FieldViolation{
+			Field:   "label_id",
+			Message: "label belongs to another workspace",
+		}))
+	}
+
+	if err := t.todoRepo.AddTodoLabel(ctx, request.TodoID, request.LabelID); err != nil {
+		return fmt.Errorf("[AddTodoLabel] add label:%w", err)
+	}
+
+	t.todoRedis.FlushCache(ctx, request.TodoID)
+
+	return nil
+}
+
+func (t *TodoService) RemoveTodoLabel(ctx context.Context, request *models.TodoLabelDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveTodoLabel")
+	defer span.Finish()
+
+	if _, _, err := t.checkTodoLabel(ctx, request); err != nil {
+		return fmt.Errorf("[RemoveTodoLabel] %w", err)
+	}
+
+	if err := t.todoRepo.RemoveTodoLabel(ctx, request.TodoID, request.LabelID); err != nil {
+		return fmt.Errorf("[RemoveTodoLabel] remove label:%w", err)
+	}
+
+	t.todoRedis.FlushCache(ctx, request.TodoID)
+
+	return nil
+}
+
+// prepareLabelFilter подставляет режим фильтра по меткам по умолчанию
+func prepareLabelFilter(filter *models.ListTodosDTO) {
+	if filter.LabelMatch == "" {
+		filter.LabelMatch = models.LabelMatchAny
+	}
+	// повтор метки в фильтре all не должен отсекать подходящие задачи
+	filter.LabelIDs = uniqueIDs(filter.LabelIDs)
+}
+
+// checkTodoLabel проверяет, что задача существует, а метка доступна отправителю запроса
+func (t *TodoService) checkTodoLabel(ctx context.Context, request *models.TodoLabelDTO) (*models.TodoDAO, *models.LabelDAO, error) {
+	todo, err := t.getTodo(ctx, request.TodoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get todo:%w", err)
+	}
+
+	label, err := t.getUsableLabel(ctx, request.LabelID, request.Owner)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get label:%w", err)
+	}
+
+	return todo, label, nil
+}
+
+// getUsableLabel возвращает личную метку отправителя или метку рабочего пространства,
+// право на которую проверил gateway. Чужая личная метка неотличима от несуществующей
+func (t *TodoService) getUsableLabel(ctx context.Context, labelID uuid.UUID, actor int) (*models.LabelDAO, error) {
+	label, err := t.todoRepo.GetLabel(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if label.WorkspaceID == nil && label.Owner != actor {
+		return nil, app_errors.ErrNotFound
+	}
+
+	return label, nil
+}
+
+// labelFitsTodo - личные метки вешаются на личные задачи, метки пространства - на задачи этого пространства
+func labelFitsTodo(label *models.LabelDAO, todo *models.TodoDAO) bool {
+	if label.WorkspaceID == nil || todo.WorkspaceID == nil {
+		return label.WorkspaceID == nil && todo.WorkspaceID == nil
+	}
+
+	return *label.WorkspaceID == *todo.WorkspaceID
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	res := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		res = append(res, id)
+	}
+
+	return res
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo/internal/service (interfaces: TodoRedisManager)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	models "todo/internal/models"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockTodoRedisManager is a mock of TodoRedisManager interface.
+type MockTodoRedisManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoRedisManagerMockRecorder
+}
+
+// MockTodoRedisManagerMockRecorder is the mock recorder for MockTodoRedisManager.
+type MockTodoRedisManagerMockRecorder struct {
+	mock *MockTodoRedisManager
+}
+
+// NewMockTodoRedisManager creates a new mock instance.
+func NewMockTodoRedisManager(ctrl *gomock.Controller) *MockTodoRedisManager {
+	mock := &MockTodoRedisManager{ctrl: ctrl}
+	mock.recorder = &MockTodoRedisManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoRedisManager) EXPECT() *MockTodoRedisManagerMockRecorder {
+	return m.recorder
+}
+
+// FlushCache mocks base method.
+func (m *MockTodoRedisManager) FlushCache(arg0 context.Context, arg1 uuid.UUID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FlushCache", arg0, arg1)
+}
+
+// FlushCache indicates an expected call of FlushCache.
+func (mr *MockTodoRedisManagerMockRecorder) FlushCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushCache", reflect.TypeOf((*MockTodoRedisManager)(nil).FlushCache), arg0, arg1)
+}
+
+// GetCacheByTodoID mocks base method.
+func (m *MockTodoRedisManager) GetCacheByTodoID(arg0 context.Context, arg1 uuid.UUID) (*models.TodoDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCacheByTodoID", arg0, arg1)
+	ret0, _ := ret[0].(*models.TodoDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCacheByTodoID indicates an expected call of GetCacheByTodoID.
+func (mr *MockTodoRedisManagerMockRecorder) GetCacheByTodoID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCacheByTodoID", reflect.TypeOf((*MockTodoRedisManager)(nil).GetCacheByTodoID), arg0, arg1)
+}
+
+// GetCacheByTodoVersion mocks base method.
+func (m *MockTodoRedisManager) GetCacheByTodoVersion(arg0 context.Context, arg1 uuid.UUID, arg2 int) (*models.TodoDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCacheByTodoVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.TodoDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCacheByTodoVersion indicates an expected call of GetCacheByTodoVersion.
+func (mr *MockTodoRedisManagerMockRecorder) GetCacheByTodoVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCacheByTodoVersion", reflect.TypeOf((*MockTodoRedisManager)(nil).GetCacheByTodoVersion), arg0, arg1, arg2)
+}
+
+// StoreCache mocks base method.
+func (m *MockTodoRedisManager) StoreCache(arg0 context.Context, arg1 *models.TodoDAO) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StoreCache", arg0, arg1)
+}
+
+// StoreCache indicates an expected call of StoreCache.
+func (mr *MockTodoRedisManagerMockRecorder) StoreCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreCache", reflect.TypeOf((*MockTodoRedisManager)(nil).StoreCache), arg0, arg1)
+}
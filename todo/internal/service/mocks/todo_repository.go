@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 	models "todo/internal/models"
 
 	gomock "github.com/golang/mock/gomock"
@@ -36,6 +37,106 @@ func (m *MockTodoRepository) EXPECT() *MockTodoRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AcceptWorkspaceInvitation mocks base method.
+func (m *MockTodoRepository) AcceptWorkspaceInvitation(arg0 context.Context, arg1 uuid.UUID, arg2 *models.WorkspaceMemberDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptWorkspaceInvitation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptWorkspaceInvitation indicates an expected call of AcceptWorkspaceInvitation.
+func (mr *MockTodoRepositoryMockRecorder) AcceptWorkspaceInvitation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptWorkspaceInvitation", reflect.TypeOf((*MockTodoRepository)(nil).AcceptWorkspaceInvitation), arg0, arg1, arg2)
+}
+
+// AddTodoDependency mocks base method.
+func (m *MockTodoRepository) AddTodoDependency(arg0 context.Context, arg1 *models.TodoDependencyDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTodoDependency", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTodoDependency indicates an expected call of AddTodoDependency.
+func (mr *MockTodoRepositoryMockRecorder) AddTodoDependency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTodoDependency", reflect.TypeOf((*MockTodoRepository)(nil).AddTodoDependency), arg0, arg1)
+}
+
+// AddTodoLabel mocks base method.
+func (m *MockTodoRepository) AddTodoLabel(arg0 context.Context, arg1, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTodoLabel", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTodoLabel indicates an expected call of AddTodoLabel.
+func (mr *MockTodoRepositoryMockRecorder) AddTodoLabel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTodoLabel", reflect.TypeOf((*MockTodoRepository)(nil).AddTodoLabel), arg0, arg1, arg2)
+}
+
+// CountOpenBlockers mocks base method.
+func (m *MockTodoRepository) CountOpenBlockers(arg0 context.Context, arg1 uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenBlockers", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenBlockers indicates an expected call of CountOpenBlockers.
+func (mr *MockTodoRepositoryMockRecorder) CountOpenBlockers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenBlockers", reflect.TypeOf((*MockTodoRepository)(nil).CountOpenBlockers), arg0, arg1)
+}
+
+// CreateComment mocks base method.
+func (m *MockTodoRepository) CreateComment(arg0 context.Context, arg1 *models.CommentDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockTodoRepositoryMockRecorder) CreateComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockTodoRepository)(nil).CreateComment), arg0, arg1)
+}
+
+// CreateLabel mocks base method.
+func (m *MockTodoRepository) CreateLabel(arg0 context.Context, arg1 *models.LabelDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLabel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockTodoRepositoryMockRecorder) CreateLabel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockTodoRepository)(nil).CreateLabel), arg0, arg1)
+}
+
+// CreateOccurrence mocks base method.
+func (m *MockTodoRepository) CreateOccurrence(arg0 context.Context, arg1 uuid.UUID, arg2 *models.TodoDAO) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOccurrence", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOccurrence indicates an expected call of CreateOccurrence.
+func (mr *MockTodoRepositoryMockRecorder) CreateOccurrence(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOccurrence", reflect.TypeOf((*MockTodoRepository)(nil).CreateOccurrence), arg0, arg1, arg2)
+}
+
 // CreateToDo mocks base method.
 func (m *MockTodoRepository) CreateToDo(arg0 context.Context, arg1 *models.CreateTodoDTO) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -51,18 +152,180 @@ func (mr *MockTodoRepositoryMockRecorder) CreateToDo(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToDo", reflect.TypeOf((*MockTodoRepository)(nil).CreateToDo), arg0, arg1)
 }
 
-// DeleteToDo mocks base method.
-func (m *MockTodoRepository) DeleteToDo(arg0 context.Context, arg1 uuid.UUID) error {
+// CreateToDos mocks base method.
+func (m *MockTodoRepository) CreateToDos(arg0 context.Context, arg1 []*models.CreateTodoDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToDos", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateToDos indicates an expected call of CreateToDos.
+func (mr *MockTodoRepositoryMockRecorder) CreateToDos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToDos", reflect.TypeOf((*MockTodoRepository)(nil).CreateToDos), arg0, arg1)
+}
+
+// CreateWorkspace mocks base method.
+func (m *MockTodoRepository) CreateWorkspace(arg0 context.Context, arg1 *models.WorkspaceDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWorkspace indicates an expected call of CreateWorkspace.
+func (mr *MockTodoRepositoryMockRecorder) CreateWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspace", reflect.TypeOf((*MockTodoRepository)(nil).CreateWorkspace), arg0, arg1)
+}
+
+// CreateWorkspaceInvitation mocks base method.
+func (m *MockTodoRepository) CreateWorkspaceInvitation(arg0 context.Context, arg1 *models.WorkspaceInvitationDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspaceInvitation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWorkspaceInvitation indicates an expected call of CreateWorkspaceInvitation.
+func (mr *MockTodoRepositoryMockRecorder) CreateWorkspaceInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspaceInvitation", reflect.TypeOf((*MockTodoRepository)(nil).CreateWorkspaceInvitation), arg0, arg1)
+}
+
+// DeleteComment mocks base method.
+func (m *MockTodoRepository) DeleteComment(arg0 context.Context, arg1 uuid.UUID, arg2 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteToDo", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteComment", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockTodoRepositoryMockRecorder) DeleteComment(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockTodoRepository)(nil).DeleteComment), arg0, arg1, arg2)
+}
+
+// DeleteLabel mocks base method.
+func (m *MockTodoRepository) DeleteLabel(arg0 context.Context, arg1 uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabel", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockTodoRepositoryMockRecorder) DeleteLabel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockTodoRepository)(nil).DeleteLabel), arg0, arg1)
+}
+
+// DeleteToDo mocks base method.
+func (m *MockTodoRepository) DeleteToDo(arg0 context.Context, arg1 uuid.UUID, arg2 int) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteToDo", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteToDo indicates an expected call of DeleteToDo.
-func (mr *MockTodoRepositoryMockRecorder) DeleteToDo(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockTodoRepositoryMockRecorder) DeleteToDo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToDo", reflect.TypeOf((*MockTodoRepository)(nil).DeleteToDo), arg0, arg1, arg2)
+}
+
+// DeleteToDos mocks base method.
+func (m *MockTodoRepository) DeleteToDos(arg0 context.Context, arg1 []*models.TodoDAO) (map[uuid.UUID]error, []uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteToDos", arg0, arg1)
+	ret0, _ := ret[0].(map[uuid.UUID]error)
+	ret1, _ := ret[1].([]uuid.UUID)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DeleteToDos indicates an expected call of DeleteToDos.
+func (mr *MockTodoRepositoryMockRecorder) DeleteToDos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToDos", reflect.TypeOf((*MockTodoRepository)(nil).DeleteToDos), arg0, arg1)
+}
+
+// DeleteWorkspace mocks base method.
+func (m *MockTodoRepository) DeleteWorkspace(arg0 context.Context, arg1 uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspace", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWorkspace indicates an expected call of DeleteWorkspace.
+func (mr *MockTodoRepositoryMockRecorder) DeleteWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspace", reflect.TypeOf((*MockTodoRepository)(nil).DeleteWorkspace), arg0, arg1)
+}
+
+// DetachUser mocks base method.
+func (m *MockTodoRepository) DetachUser(arg0 context.Context, arg1 int) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachUser", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachUser indicates an expected call of DetachUser.
+func (mr *MockTodoRepositoryMockRecorder) DetachUser(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToDo", reflect.TypeOf((*MockTodoRepository)(nil).DeleteToDo), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachUser", reflect.TypeOf((*MockTodoRepository)(nil).DetachUser), arg0, arg1)
+}
+
+// FinishSeries mocks base method.
+func (m *MockTodoRepository) FinishSeries(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishSeries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishSeries indicates an expected call of FinishSeries.
+func (mr *MockTodoRepositoryMockRecorder) FinishSeries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishSeries", reflect.TypeOf((*MockTodoRepository)(nil).FinishSeries), arg0, arg1)
+}
+
+// GetComment mocks base method.
+func (m *MockTodoRepository) GetComment(arg0 context.Context, arg1 uuid.UUID) (*models.CommentDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComment", arg0, arg1)
+	ret0, _ := ret[0].(*models.CommentDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComment indicates an expected call of GetComment.
+func (mr *MockTodoRepositoryMockRecorder) GetComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComment", reflect.TypeOf((*MockTodoRepository)(nil).GetComment), arg0, arg1)
+}
+
+// GetLabel mocks base method.
+func (m *MockTodoRepository) GetLabel(arg0 context.Context, arg1 uuid.UUID) (*models.LabelDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabel", arg0, arg1)
+	ret0, _ := ret[0].(*models.LabelDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabel indicates an expected call of GetLabel.
+func (mr *MockTodoRepositoryMockRecorder) GetLabel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabel", reflect.TypeOf((*MockTodoRepository)(nil).GetLabel), arg0, arg1)
 }
 
 // GetToDo mocks base method.
@@ -95,17 +358,341 @@ func (mr *MockTodoRepositoryMockRecorder) GetToDos(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToDos", reflect.TypeOf((*MockTodoRepository)(nil).GetToDos), arg0, arg1)
 }
 
+// GetUserToDos mocks base method.
+func (m *MockTodoRepository) GetUserToDos(arg0 context.Context, arg1 int) ([]models.TodoDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserToDos", arg0, arg1)
+	ret0, _ := ret[0].([]models.TodoDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserToDos indicates an expected call of GetUserToDos.
+func (mr *MockTodoRepositoryMockRecorder) GetUserToDos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserToDos", reflect.TypeOf((*MockTodoRepository)(nil).GetUserToDos), arg0, arg1)
+}
+
+// GetWorkspace mocks base method.
+func (m *MockTodoRepository) GetWorkspace(arg0 context.Context, arg1 uuid.UUID) (*models.WorkspaceDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(*models.WorkspaceDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspace indicates an expected call of GetWorkspace.
+func (mr *MockTodoRepositoryMockRecorder) GetWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspace", reflect.TypeOf((*MockTodoRepository)(nil).GetWorkspace), arg0, arg1)
+}
+
+// GetWorkspaceInvitation mocks base method.
+func (m *MockTodoRepository) GetWorkspaceInvitation(arg0 context.Context, arg1 string) (*models.WorkspaceInvitationDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceInvitation", arg0, arg1)
+	ret0, _ := ret[0].(*models.WorkspaceInvitationDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceInvitation indicates an expected call of GetWorkspaceInvitation.
+func (mr *MockTodoRepositoryMockRecorder) GetWorkspaceInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceInvitation", reflect.TypeOf((*MockTodoRepository)(nil).GetWorkspaceInvitation), arg0, arg1)
+}
+
+// GetWorkspaceMember mocks base method.
+func (m *MockTodoRepository) GetWorkspaceMember(arg0 context.Context, arg1 uuid.UUID, arg2 int) (*models.WorkspaceMemberDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.WorkspaceMemberDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceMember indicates an expected call of GetWorkspaceMember.
+func (mr *MockTodoRepositoryMockRecorder) GetWorkspaceMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceMember", reflect.TypeOf((*MockTodoRepository)(nil).GetWorkspaceMember), arg0, arg1, arg2)
+}
+
+// ListComments mocks base method.
+func (m *MockTodoRepository) ListComments(arg0 context.Context, arg1 *models.ListCommentsDTO) ([]models.CommentDAO, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComments", arg0, arg1)
+	ret0, _ := ret[0].([]models.CommentDAO)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListComments indicates an expected call of ListComments.
+func (mr *MockTodoRepositoryMockRecorder) ListComments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockTodoRepository)(nil).ListComments), arg0, arg1)
+}
+
+// ListDueRecurring mocks base method.
+func (m *MockTodoRepository) ListDueRecurring(arg0 context.Context, arg1 time.Time, arg2 int) ([]models.TodoDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueRecurring", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.TodoDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueRecurring indicates an expected call of ListDueRecurring.
+func (mr *MockTodoRepositoryMockRecorder) ListDueRecurring(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueRecurring", reflect.TypeOf((*MockTodoRepository)(nil).ListDueRecurring), arg0, arg1, arg2)
+}
+
+// ListLabels mocks base method.
+func (m *MockTodoRepository) ListLabels(arg0 context.Context, arg1 int, arg2 *uuid.UUID) ([]models.LabelDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLabels", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.LabelDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLabels indicates an expected call of ListLabels.
+func (mr *MockTodoRepositoryMockRecorder) ListLabels(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLabels", reflect.TypeOf((*MockTodoRepository)(nil).ListLabels), arg0, arg1, arg2)
+}
+
+// ListSubtasks mocks base method.
+func (m *MockTodoRepository) ListSubtasks(arg0 context.Context, arg1 []uuid.UUID, arg2 *models.ListTodosDTO) ([]models.TodoDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtasks", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.TodoDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtasks indicates an expected call of ListSubtasks.
+func (mr *MockTodoRepositoryMockRecorder) ListSubtasks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtasks", reflect.TypeOf((*MockTodoRepository)(nil).ListSubtasks), arg0, arg1, arg2)
+}
+
+// ListToDos mocks base method.
+func (m *MockTodoRepository) ListToDos(arg0 context.Context, arg1 *models.ListTodosDTO) ([]models.TodoDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListToDos", arg0, arg1)
+	ret0, _ := ret[0].([]models.TodoDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListToDos indicates an expected call of ListToDos.
+func (mr *MockTodoRepositoryMockRecorder) ListToDos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListToDos", reflect.TypeOf((*MockTodoRepository)(nil).ListToDos), arg0, arg1)
+}
+
+// ListTodoHistory mocks base method.
+func (m *MockTodoRepository) ListTodoHistory(arg0 context.Context, arg1 *models.ListTodoHistoryDTO) ([]models.TodoEventDAO, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoHistory", arg0, arg1)
+	ret0, _ := ret[0].([]models.TodoEventDAO)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListTodoHistory indicates an expected call of ListTodoHistory.
+func (mr *MockTodoRepositoryMockRecorder) ListTodoHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoHistory", reflect.TypeOf((*MockTodoRepository)(nil).ListTodoHistory), arg0, arg1)
+}
+
+// ListWorkspaceMembers mocks base method.
+func (m *MockTodoRepository) ListWorkspaceMembers(arg0 context.Context, arg1 uuid.UUID) ([]models.WorkspaceMemberDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaceMembers", arg0, arg1)
+	ret0, _ := ret[0].([]models.WorkspaceMemberDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaceMembers indicates an expected call of ListWorkspaceMembers.
+func (mr *MockTodoRepositoryMockRecorder) ListWorkspaceMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaceMembers", reflect.TypeOf((*MockTodoRepository)(nil).ListWorkspaceMembers), arg0, arg1)
+}
+
+// ListWorkspaces mocks base method.
+func (m *MockTodoRepository) ListWorkspaces(arg0 context.Context, arg1 int) ([]models.WorkspaceDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaces", arg0, arg1)
+	ret0, _ := ret[0].([]models.WorkspaceDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaces indicates an expected call of ListWorkspaces.
+func (mr *MockTodoRepositoryMockRecorder) ListWorkspaces(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaces", reflect.TypeOf((*MockTodoRepository)(nil).ListWorkspaces), arg0, arg1)
+}
+
+// RelabelToDos mocks base method.
+func (m *MockTodoRepository) RelabelToDos(arg0 context.Context, arg1, arg2, arg3 []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelabelToDos", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RelabelToDos indicates an expected call of RelabelToDos.
+func (mr *MockTodoRepositoryMockRecorder) RelabelToDos(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelabelToDos", reflect.TypeOf((*MockTodoRepository)(nil).RelabelToDos), arg0, arg1, arg2, arg3)
+}
+
+// RemoveTodoDependency mocks base method.
+func (m *MockTodoRepository) RemoveTodoDependency(arg0 context.Context, arg1 *models.TodoDependencyDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTodoDependency", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTodoDependency indicates an expected call of RemoveTodoDependency.
+func (mr *MockTodoRepositoryMockRecorder) RemoveTodoDependency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTodoDependency", reflect.TypeOf((*MockTodoRepository)(nil).RemoveTodoDependency), arg0, arg1)
+}
+
+// RemoveTodoLabel mocks base method.
+func (m *MockTodoRepository) RemoveTodoLabel(arg0 context.Context, arg1, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTodoLabel", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTodoLabel indicates an expected call of RemoveTodoLabel.
+func (mr *MockTodoRepositoryMockRecorder) RemoveTodoLabel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTodoLabel", reflect.TypeOf((*MockTodoRepository)(nil).RemoveTodoLabel), arg0, arg1, arg2)
+}
+
+// RemoveWorkspaceMember mocks base method.
+func (m *MockTodoRepository) RemoveWorkspaceMember(arg0 context.Context, arg1 uuid.UUID, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWorkspaceMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWorkspaceMember indicates an expected call of RemoveWorkspaceMember.
+func (mr *MockTodoRepositoryMockRecorder) RemoveWorkspaceMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWorkspaceMember", reflect.TypeOf((*MockTodoRepository)(nil).RemoveWorkspaceMember), arg0, arg1, arg2)
+}
+
+// SearchTodos mocks base method.
+func (m *MockTodoRepository) SearchTodos(arg0 context.Context, arg1 *models.SearchTodosDTO) ([]models.SearchResultDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTodos", arg0, arg1)
+	ret0, _ := ret[0].([]models.SearchResultDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTodos indicates an expected call of SearchTodos.
+func (mr *MockTodoRepositoryMockRecorder) SearchTodos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTodos", reflect.TypeOf((*MockTodoRepository)(nil).SearchTodos), arg0, arg1)
+}
+
+// UpdateComment mocks base method.
+func (m *MockTodoRepository) UpdateComment(arg0 context.Context, arg1 *models.CommentDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockTodoRepositoryMockRecorder) UpdateComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockTodoRepository)(nil).UpdateComment), arg0, arg1)
+}
+
+// UpdateLabel mocks base method.
+func (m *MockTodoRepository) UpdateLabel(arg0 context.Context, arg1 *models.LabelDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLabel indicates an expected call of UpdateLabel.
+func (mr *MockTodoRepositoryMockRecorder) UpdateLabel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabel", reflect.TypeOf((*MockTodoRepository)(nil).UpdateLabel), arg0, arg1)
+}
+
 // UpdateToDo mocks base method.
-func (m *MockTodoRepository) UpdateToDo(arg0 context.Context, arg1 *models.TodoDAO) (uuid.UUID, error) {
+func (m *MockTodoRepository) UpdateToDo(arg0 context.Context, arg1 *models.TodoDAO, arg2 []models.TodoEventDAO) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateToDo", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateToDo", arg0, arg1, arg2)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateToDo indicates an expected call of UpdateToDo.
-func (mr *MockTodoRepositoryMockRecorder) UpdateToDo(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockTodoRepositoryMockRecorder) UpdateToDo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToDo", reflect.TypeOf((*MockTodoRepository)(nil).UpdateToDo), arg0, arg1, arg2)
+}
+
+// UpdateToDos mocks base method.
+func (m *MockTodoRepository) UpdateToDos(arg0 context.Context, arg1 []*models.TodoDAO, arg2 []models.TodoEventDAO) (map[uuid.UUID]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateToDos", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[uuid.UUID]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateToDos indicates an expected call of UpdateToDos.
+func (mr *MockTodoRepositoryMockRecorder) UpdateToDos(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToDos", reflect.TypeOf((*MockTodoRepository)(nil).UpdateToDos), arg0, arg1, arg2)
+}
+
+// UpdateWorkspace mocks base method.
+func (m *MockTodoRepository) UpdateWorkspace(arg0 context.Context, arg1 *models.WorkspaceDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspace indicates an expected call of UpdateWorkspace.
+func (mr *MockTodoRepositoryMockRecorder) UpdateWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockTodoRepository)(nil).UpdateWorkspace), arg0, arg1)
+}
+
+// UpdateWorkspaceMember mocks base method.
+func (m *MockTodoRepository) UpdateWorkspaceMember(arg0 context.Context, arg1 *models.WorkspaceMemberDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceMember indicates an expected call of UpdateWorkspaceMember.
+func (mr *MockTodoRepositoryMockRecorder) UpdateWorkspaceMember(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToDo", reflect.TypeOf((*MockTodoRepository)(nil).UpdateToDo), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceMember", reflect.TypeOf((*MockTodoRepository)(nil).UpdateWorkspaceMember), arg0, arg1)
 }
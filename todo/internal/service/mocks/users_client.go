@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo/internal/service (interfaces: UsersServiceClient)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	models "todo/internal/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUsersServiceClient is a mock of UsersServiceClient interface.
+type MockUsersServiceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersServiceClientMockRecorder
+}
+
+// MockUsersServiceClientMockRecorder is the mock recorder for MockUsersServiceClient.
+type MockUsersServiceClientMockRecorder struct {
+	mock *MockUsersServiceClient
+}
+
+// NewMockUsersServiceClient creates a new mock instance.
+func NewMockUsersServiceClient(ctrl *gomock.Controller) *MockUsersServiceClient {
+	mock := &MockUsersServiceClient{ctrl: ctrl}
+	mock.recorder = &MockUsersServiceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsersServiceClient) EXPECT() *MockUsersServiceClientMockRecorder {
+	return m.recorder
+}
+
+// GetUserByID mocks base method.
+func (m *MockUsersServiceClient) GetUserByID(arg0 context.Context, arg1 int) (*models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUsersServiceClientMockRecorder) GetUserByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUsersServiceClient)(nil).GetUserByID), arg0, arg1)
+}
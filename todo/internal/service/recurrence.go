@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	"todo/internal/models"
+	"todo/pkg/rrule"
+	"todo/pkg/validator"
+)
+
+// defaultTimezone - часовой пояс повторений, если он не указан
+const defaultTimezone = "UTC"
+
+// GenerateDueOccurrences создает следующие повторения задач, срок которых наступил, даже если они
+// еще не выполнены. Возвращает число обработанных задач, чтобы вызывающий мог повторить, пока они не закончатся
+func (t *TodoService) GenerateDueOccurrences(ctx context.Context) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GenerateDueOccurrences")
+	defer span.Finish()
+
+	now := time.Now().UTC()
+
+	due, err := t.todoRepo.ListDueRecurring(ctx, now, t.recurrence.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("[GenerateDueOccurrences] get todos:%w", err)
+	}
+
+	for i := range due {
+		if err := t.spawnNext(ctx, &due[i], now); err != nil {
+			return i, fmt.Errorf("[GenerateDueOccurrences] spawn next occurrence:%w", err)
+		}
+	}
+
+	return len(due), nil
+}
+
+// spawnNext создает следующее повторение задачи с тем же исполнителем, описанием и метками.
+// Если задача выполнена до срока, следующее повторение идет за ее сроком, а пропущенные повторения
+// не создаются. Когда серия заканчивается по COUNT или UNTIL, задача отмечается последней
+func (t *TodoService) spawnNext(ctx context.Context, todo *models.TodoDAO, now time.Time) error {
+	rule, err := rrule.Parse(todo.Recurrence)
+	if err != nil || todo.DueAt == nil {
+		// правило проверяется при сохранении, серию с испорченным правилом продолжить нельзя
+		return t.todoRepo.FinishSeries(ctx, todo.ID)
+	}
+
+	loc, err := time.LoadLocation(todo.Timezone)
+	if err != nil {
+		return t.todoRepo.FinishSeries(ctx, todo.ID)
+	}
+
+	after := now
+	if todo.DueAt.After(after) {
+		after = *todo.DueAt
+	}
+
+	// время суток берется от начала серии: срок, сдвинутый переходом на летнее время, не сдвигает следующие
+	start := *todo.DueAt
+	if todo.SeriesStart != nil {
+		start = *todo.SeriesStart
+	}
+
+	dueAt, occurrence, ok := rule.Next(start, *todo.DueAt, todo.Occurrence, after, loc)
+	if !ok {
+		return t.todoRepo.FinishSeries(ctx, todo.ID)
+	}
+	dueAt = dueAt.UTC()
+
+	next := &models.TodoDAO{
+		ID:          uuid.New(),
+		CreatedBy:   todo.CreatedBy,
+		Assignee:    todo.Assignee,
+		Description: todo.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Labels:      todo.Labels,
+		ParentID:    todo.ParentID,
+		DueAt:       &dueAt,
+		Recurrence:  todo.Recurrence,
+		Timezone:    todo.Timezone,
+		SeriesID:    todo.SeriesID,
+		Occurrence:  occurrence,
+		SeriesStart: todo.SeriesStart,
+		WorkspaceID: todo.WorkspaceID,
+		Version:     1,
+	}
+
+	created, err := t.todoRepo.CreateOccurrence(ctx, todo.ID, next)
+	if err != nil {
+		return err
+	}
+
+	// повторение уже создано раньше, например по расписанию
+	if !created {
+		return nil
+	}
+
+	t.todoRedis.StoreCache(ctx, next)
+	t.flushParents(ctx, next.ParentID)
+
+	return nil
+}
+
+// prepareRecurrence проверяет правило повторения и приводит его к каноническому виду
+func prepareRecurrence(recurrence, timezone *string) error {
+	if *recurrence == "" {
+		return nil
+	}
+
+	rule, err := rrule.Parse(*recurrence)
+	if err != nil {
+		return validator.NewValidationError(validator.FieldViolation{Field: "recurrence", Message: err.Error()})
+	}
+
+	*recurrence = rule.String()
+	if *timezone == "" {
+		*timezone = defaultTimezone
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	"todo/internal/models"
+)
+
+// SearchTodos ищет задачи пользователя по описанию и комментариям, лучшие совпадения первыми
+func (t *TodoService) SearchTodos(ctx context.Context, request *models.SearchTodosDTO) ([]*models.SearchResultDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.SearchTodos")
+	defer span.Finish()
+
+	request.Query = strings.TrimSpace(request.Query)
+	if err := t.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[SearchTodos] validate:%w", err)
+	}
+
+	if request.Limit == 0 {
+		request.Limit = models.SearchDefaultLimit
+	}
+
+	found, err := t.todoRepo.SearchTodos(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("[SearchTodos] search:%w", err)
+	}
+
+	res := make([]*models.SearchResultDTO, 0, len(found))
+	for i := range found {
+		res = append(res, found[i].ToDTO())
+	}
+
+	return res, nil
+}
@@ -2,76 +2,172 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
-	"strconv"
+	"time"
+	"todo/config"
+	"todo/internal/app_errors"
 	"todo/internal/models"
+	"todo/pkg/validator"
 )
 
 type TodoService struct {
 	todoRepo           TodoRepository
 	todoRabbitProducer RabbitProducer
 	todoRedis          TodoRedisManager
+	usersClient        UsersServiceClient
+	validator          *validator.Validator
+	recurrence         *config.Recurrence
+	workspaces         *config.Workspaces
 }
 
 func NewTodoService(
 	todoRepo TodoRepository,
 	todoRabbitProducer RabbitProducer,
 	todoRedis TodoRedisManager,
+	usersClient UsersServiceClient,
+	validator *validator.Validator,
+	recurrence *config.Recurrence,
+	workspaces *config.Workspaces,
 ) *TodoService {
 	return &TodoService{
 		todoRepo:           todoRepo,
 		todoRabbitProducer: todoRabbitProducer,
 		todoRedis:          todoRedis,
+		usersClient:        usersClient,
+		validator:          validator,
+		recurrence:         recurrence,
+		workspaces:         workspaces,
 	}
 }
 
-func (t *TodoService) CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (int, error) {
+func (t *TodoService) CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (uuid.UUID, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateToDo")
 	defer span.Finish()
 
+	if err := t.validator.Validate(newTodo); err != nil {
+		return uuid.Nil, fmt.Errorf("[CreateToDo] validate:%w", err)
+	}
+
+	// идентификатор задачи генерируется на стороне сервиса
+	newTodo.ID = uuid.New()
+
+	if newTodo.ParentID != nil {
+		if err := t.checkParent(ctx, newTodo.ID, *newTodo.ParentID, newTodo.WorkspaceID); err != nil {
+			return uuid.Nil, fmt.Errorf("[CreateToDo] check parent:%w", err)
+		}
+	}
+
+	if err := prepareRecurrence(&newTodo.Recurrence, &newTodo.Timezone); err != nil {
+		return uuid.Nil, fmt.Errorf("[CreateToDo] %w", err)
+	}
+	if newTodo.Recurrence != "" {
+		// первая задача серии
+		newTodo.SeriesID = &newTodo.ID
+		newTodo.Occurrence = 1
+		newTodo.SeriesStart = newTodo.DueAt
+	}
+
+	newTodo.CreatedAt = time.Now().UTC()
+	newTodo.UpdatedAt = newTodo.CreatedAt
+
 	todoID, err := t.todoRepo.CreateToDo(ctx, newTodo)
 	if err != nil {
-		return 0, fmt.Errorf("[CreateToDo] store user:%w", err)
+		return uuid.Nil, fmt.Errorf("[CreateToDo] store todo:%w", err)
 	}
 
-	t.todoRedis.StoreCache(ctx, (*models.TodoDAO)(newTodo))
+	t.todoRedis.StoreCache(ctx, newTodo.ToDAO())
+	// у родителя изменился прогресс по подзадачам
+	t.flushParents(ctx, newTodo.ParentID)
 
-	return int(todoID.ID()), nil
+	return todoID, nil
 }
 
-func (t *TodoService) UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (int, error) {
+func (t *TodoService) UpdateToDo(ctx context.Context, updateTodo *models.UpdateTodoDTO) (uuid.UUID, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateTodo")
 	defer span.Finish()
 
-	upd := &models.TodoDAO{
-		ID:          updateTodo.ID,
-		Assignee:    updateTodo.Assignee,
-		Description: updateTodo.Description,
-		UpdatedAt:   updateTodo.UpdatedAt,
+	if err := t.validator.Validate(updateTodo); err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] validate:%w", err)
 	}
 
-	extTodo, err := t.todoRedis.GetCacheByTodoID(ctx, updateTodo.ID)
+	existingTodo, err := t.getTodoVersion(ctx, updateTodo.ID, updateTodo.Version)
 	if err != nil {
-		return 0, err
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] get todo:%w", err)
 	}
 
-	todoID, err := t.todoRepo.UpdateToDo(ctx, upd)
+	parentChanged := !sameID(existingTodo.ParentID, updateTodo.ParentID)
+	if parentChanged && updateTodo.ParentID != nil {
+		if err := t.checkParent(ctx, existingTodo.ID, *updateTodo.ParentID, existingTodo.WorkspaceID); err != nil {
+			return uuid.Nil, fmt.Errorf("[UpdateToDo] check parent:%w", err)
+		}
+	}
+
+	if updateTodo.Done && !existingTodo.Done {
+		if err := t.checkNotBlocked(ctx, existingTodo.ID); err != nil {
+			return uuid.Nil, fmt.Errorf("[UpdateToDo] %w", err)
+		}
+	}
+
+	if err := prepareRecurrence(&updateTodo.Recurrence, &updateTodo.Timezone); err != nil {
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] %w", err)
+	}
+
+	doneChanged := existingTodo.Done != updateTodo.Done
+	oldParentID := existingTodo.ParentID
+	before := *existingTodo
+
+	existingTodo.Assignee = updateTodo.Assignee
+	existingTodo.Description = updateTodo.Description
+	existingTodo.ParentID = updateTodo.ParentID
+	existingTodo.Done = updateTodo.Done
+	existingTodo.DueAt = updateTodo.DueAt
+	existingTodo.Recurrence = updateTodo.Recurrence
+	existingTodo.Timezone = updateTodo.Timezone
+	existingTodo.UpdatedAt = time.Now().UTC()
+
+	if existingTodo.Recurrence != "" && existingTodo.SeriesID == nil {
+		// задача становится первой в новой серии
+		existingTodo.SeriesID = &existingTodo.ID
+		existingTodo.Occurrence = 1
+		existingTodo.SeriesStart = existingTodo.DueAt
+	} else if existingTodo.Recurrence != "" && optionalTimeValue(before.DueAt) != optionalTimeValue(existingTodo.DueAt) {
+		// перенесенный срок задает новое время суток для следующих повторений
+		existingTodo.SeriesStart = existingTodo.DueAt
+	}
+
+	events := todoChanges(&before, existingTodo, updateTodo.UpdatedBy, existingTodo.UpdatedAt)
+
+	todoID, err := t.todoRepo.UpdateToDo(ctx, existingTodo, events)
 	if err != nil {
-		return 0, fmt.Errorf("[UpdateToDo] update todo:%w", err)
+		if errors.Is(err, app_errors.ErrCycle) {
+			return uuid.Nil, fmt.Errorf("[UpdateToDo] check parent:%w", errParentCycle)
+		}
+		return uuid.Nil, fmt.Errorf("[UpdateToDo] update todo:%w", err)
+	}
+
+	t.todoRedis.StoreCache(ctx, existingTodo)
+
+	if parentChanged || doneChanged {
+		t.flushParents(ctx, oldParentID, existingTodo.ParentID)
 	}
 
-	t.todoRedis.StoreCache(ctx, extTodo)
+	if doneChanged && existingTodo.Done && existingTodo.Recurrence != "" {
+		if err := t.spawnNext(ctx, existingTodo, existingTodo.UpdatedAt); err != nil {
+			return uuid.Nil, fmt.Errorf("[UpdateToDo] spawn next occurrence:%w", err)
+		}
+	}
 
-	return int(todoID.ID()), nil
+	return todoID, nil
 }
 
-func (t *TodoService) GetToDos(ctx context.Context, todoID int) (*models.TodoDTO, error) {
+func (t *TodoService) GetToDos(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetToDos")
 	defer span.Finish()
 
-	_, err := t.todoRepo.GetToDos(ctx, uuid.New())
+	_, err := t.todoRepo.GetToDos(ctx, todoID)
 	if err != nil {
 		return nil, fmt.Errorf("[GetToDo] get todo:%w", err)
 	}
@@ -79,34 +175,148 @@ func (t *TodoService) GetToDos(ctx context.Context, todoID int) (*models.TodoDTO
 	return nil, nil //TODO fix
 }
 
-func (t *TodoService) GetToDo(ctx context.Context, todoID int) (*models.TodoDTO, error) {
+func (t *TodoService) GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetTodo")
 	defer span.Finish()
 
-	getTodo, err := t.todoRedis.GetCacheByTodoID(ctx, uuid.MustParse(strconv.Itoa(todoID)))
+	getTodo, err := t.getTodo(ctx, todoID)
 	if err != nil {
-		getTodo, err = t.todoRepo.GetToDo(ctx, uuid.New())
-		if err != nil {
-			return nil, fmt.Errorf("[GetToDo] get todo:%w", err)
-		}
+		return nil, fmt.Errorf("[GetToDo] get todo:%w", err)
 	}
 
 	return (*models.TodoDTO)(getTodo), nil
 }
 
-func (t *TodoService) DeleteToDo(ctx context.Context, todoID int) error {
+// DeleteToDo удаляет задачу, если с момента чтения клиентом ее версия не изменилась
+func (t *TodoService) DeleteToDo(ctx context.Context, todoID uuid.UUID, version int) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteToDo")
 	defer span.Finish()
 
-	_, err := t.todoRedis.GetCacheByTodoID(ctx, uuid.MustParse(strconv.Itoa(todoID)))
+	existingTodo, err := t.getTodoVersion(ctx, todoID, version)
 	if err != nil {
-		err = t.todoRepo.DeleteToDo(ctx, uuid.New()) // TODO fix
-		if err != nil {
-			return fmt.Errorf("[DeleteToDo] delete todo:%w", err)
-		}
+		return fmt.Errorf("[DeleteToDo] get todo:%w", err)
 	}
 
-	t.todoRedis.FlushCache(ctx, uuid.MustParse(strconv.Itoa(todoID)))
+	// подзадачи и зависимые задачи меняются вместе с удалением
+	affected, err := t.todoRepo.DeleteToDo(ctx, todoID, version)
+	if err != nil {
+		return fmt.Errorf("[DeleteToDo] delete todo:%w", err)
+	}
+
+	t.todoRedis.FlushCache(ctx, todoID)
+	t.flushParents(ctx, existingTodo.ParentID)
+	for _, id := range affected {
+		t.todoRedis.FlushCache(ctx, id)
+	}
 
 	return nil
 }
+
+func (t *TodoService) GetUserToDos(ctx context.Context, userID int) ([]*models.TodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUserToDos")
+	defer span.Finish()
+
+	todos, err := t.todoRepo.GetUserToDos(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserToDos] get todos:%w", err)
+	}
+
+	res := make([]*models.TodoDTO, 0, len(todos))
+	for i := range todos {
+		res = append(res, (*models.TodoDTO)(&todos[i]))
+	}
+
+	return res, nil
+}
+
+func (t *TodoService) ListToDos(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListToDos")
+	defer span.Finish()
+
+	if err := t.prepareListFilter(filter); err != nil {
+		return nil, fmt.Errorf("[ListToDos] validate:%w", err)
+	}
+
+	todos, err := t.todoRepo.ListToDos(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("[ListToDos] get todos:%w", err)
+	}
+
+	res := make([]*models.TodoDTO, 0, len(todos))
+	for i := range todos {
+		res = append(res, (*models.TodoDTO)(&todos[i]))
+	}
+
+	return res, nil
+}
+
+// prepareListFilter проверяет фильтр списка задач и подставляет значения по умолчанию
+func (t *TodoService) prepareListFilter(filter *models.ListTodosDTO) error {
+	if err := t.validator.Validate(filter); err != nil {
+		return err
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = models.TodosDefaultLimit
+	}
+	prepareLabelFilter(filter)
+
+	return nil
+}
+
+// HandleUserDeleted отвязывает задачи от удаленного пользователя.
+// Повторная обработка того же события ничего не меняет.
+func (t *TodoService) HandleUserDeleted(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.HandleUserDeleted")
+	defer span.Finish()
+
+	affected, err := t.todoRepo.DetachUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[HandleUserDeleted] detach user:%w", err)
+	}
+
+	for _, todoID := range affected {
+		t.todoRedis.FlushCache(ctx, todoID)
+	}
+
+	return nil
+}
+
+// getTodo ищет задачу сначала в кэше, затем в базе данных, и кладет найденную задачу в кэш
+func (t *TodoService) getTodo(ctx context.Context, todoID uuid.UUID) (*models.TodoDAO, error) {
+	cached, err := t.todoRedis.GetCacheByTodoID(ctx, todoID)
+	if err == nil {
+		return cached, nil
+	}
+
+	stored, err := t.todoRepo.GetToDo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.todoRedis.StoreCache(ctx, stored)
+
+	return stored, nil
+}
+
+// getTodoVersion ищет задачу в версии, которую видел клиент. Если в базе уже другая версия,
+// возвращает ErrVersionConflict. Окончательно версию проверяет репозиторий при сохранении
+func (t *TodoService) getTodoVersion(ctx context.Context, todoID uuid.UUID, version int) (*models.TodoDAO, error) {
+	cached, err := t.todoRedis.GetCacheByTodoVersion(ctx, todoID, version)
+	if err == nil {
+		return cached, nil
+	}
+
+	stored, err := t.todoRepo.GetToDo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.todoRedis.StoreCache(ctx, stored)
+
+	if stored.Version != version {
+		return nil, app_errors.ErrVersionConflict
+	}
+
+	return stored, nil
+}
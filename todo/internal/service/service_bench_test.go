@@ -15,9 +15,14 @@ func BenchmarkTodoService_CreateToDo(b *testing.B) {
 	mocks := getMocks(ctrl)
 	service := buildTestService(mocks)
 	ctx := context.Background()
-	newTodo := &models.CreateTodoDTO{}
+	newTodo := &models.CreateTodoDTO{
+		CreatedBy:   1,
+		Assignee:    2,
+		Description: "example desc",
+	}
 
 	mocks.TodoRepository.EXPECT().CreateToDo(gomock.Any(), gomock.Any()).Return(uuid.New(), nil).AnyTimes()
+	mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any()).AnyTimes()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
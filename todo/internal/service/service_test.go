@@ -2,12 +2,18 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"net/url"
 	"testing"
 	"time"
 	appErrors "todo/internal/app_errors"
 	"todo/internal/models"
+	"todo/pkg/validator"
 )
 
 func TestTodoService_CreateToDo(t *testing.T) {
@@ -26,40 +32,1784 @@ func TestTodoService_CreateToDo(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 
-	existingTodo := &models.TodoDAO{
-		ID: uuid.MustParse("c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"),
-	}
+	errDb := errors.New("db error")
 
 	tests := []struct {
 		name        string
+		todo        *models.CreateTodoDTO
 		setup       func()
 		wantErr     bool
 		expectedErr error
 	}{
 		{
 			name: "Success",
+			todo: newTodo,
 			setup: func() {
-				mocks.TodoRepository.EXPECT().CreateToDo(gomock.Any(), newTodo.ID).Return(nil, nil)
+				mocks.TodoRepository.EXPECT().CreateToDo(gomock.Any(), newTodo).Return(newTodo.ID, nil)
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any())
 			},
 			wantErr: false,
 		},
 		{
-			name: "TodoExistsError",
+			name:        "ValidationError",
+			todo:        &models.CreateTodoDTO{CreatedBy: 1, Assignee: 2, Description: "   "},
+			setup:       func() {},
+			wantErr:     true,
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name: "DBError",
+			todo: newTodo,
 			setup: func() {
-				mocks.TodoRepository.EXPECT().CreateToDo(gomock.Any(), newTodo).Return(existingTodo, nil)
+				mocks.TodoRepository.EXPECT().CreateToDo(gomock.Any(), newTodo).Return(uuid.Nil, errDb)
 			},
 			wantErr:     true,
-			expectedErr: appErrors.ErrUsernameOrEmailIsUsed,
+			expectedErr: errDb,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.CreateToDo(context.Background(), tt.todo)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+
+}
+
+func TestTodoService_GetToDo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	storedTodo := &models.TodoDAO{
+		ID:          uuid.New(),
+		CreatedBy:   1,
+		Assignee:    2,
+		Description: "example desc",
+	}
+	errCache := errors.New("cache miss")
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "FromCache",
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+			},
+		},
+		{
+			name: "FromDB",
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(nil, errCache)
+				mocks.TodoRepository.EXPECT().GetToDo(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), storedTodo)
+			},
+		},
+		{
+			name: "NotFound",
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(nil, errCache)
+				mocks.TodoRepository.EXPECT().GetToDo(gomock.Any(), storedTodo.ID).Return(nil, appErrors.ErrNotFound)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			got, err := svc.GetToDo(context.Background(), storedTodo.ID)
+
+			requireEqualError(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				require.Equal(t, storedTodo.ID, got.ID)
+			}
+		})
+	}
+}
+
+func TestTodoService_UpdateToDo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	todoID := uuid.New()
+	update := &models.UpdateTodoDTO{
+		ID:          todoID,
+		Version:     1,
+		UpdatedBy:   5,
+		Assignee:    3,
+		Description: "new desc",
+	}
+	errDb := errors.New("db error")
+	errCache := errors.New("cache miss")
+
+	tests := []struct {
+		name        string
+		update      *models.UpdateTodoDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:   "Success",
+			update: update,
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(&models.TodoDAO{ID: todoID, CreatedBy: 1, Assignee: 2, Description: "old desc"}, nil)
+				mocks.TodoRepository.EXPECT().UpdateToDo(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *models.TodoDAO, events []models.TodoEventDAO) (uuid.UUID, error) {
+						require.Equal(t, 1, todo.CreatedBy)
+						require.Equal(t, update.Assignee, todo.Assignee)
+						require.Equal(t, update.Description, todo.Description)
+
+						// в историю попадают только измененные поля
+						require.Len(t, events, 2)
+						require.Equal(t, models.TodoFieldAssignee, events[0].Field)
+						require.Equal(t, "2", events[0].OldValue)
+						require.Equal(t, "3", events[0].NewValue)
+						require.Equal(t, models.TodoFieldDescription, events[1].Field)
+						require.Equal(t, "old desc", events[1].OldValue)
+						require.Equal(t, "new desc", events[1].NewValue)
+						for _, e := range events {
+							require.Equal(t, todoID, e.TodoID)
+							require.Equal(t, 5, e.Actor)
+							require.Equal(t, todo.UpdatedAt, e.CreatedAt)
+						}
+						return todo.ID, nil
+					})
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:        "MissingActor",
+			update:      &models.UpdateTodoDTO{ID: todoID, Version: 1, Assignee: 3, Description: "new desc"},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:   "VersionConflict",
+			update: update,
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).Return(nil, errCache)
+				mocks.TodoRepository.EXPECT().GetToDo(gomock.Any(), todoID).
+					Return(&models.TodoDAO{ID: todoID, CreatedBy: 1, Assignee: 2, Version: 2}, nil)
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any())
+			},
+			expectedErr: appErrors.ErrVersionConflict,
+		},
+		{
+			name:   "DBError",
+			update: update,
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(&models.TodoDAO{ID: todoID, CreatedBy: 1, Assignee: 2}, nil)
+				mocks.TodoRepository.EXPECT().UpdateToDo(gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.Nil, errDb)
+			},
+			expectedErr: errDb,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.UpdateToDo(context.Background(), tt.update)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_DeleteToDo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	todoID, parentID, subtaskID := uuid.New(), uuid.New(), uuid.New()
+	errCache := errors.New("cache miss")
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 2).Return(&models.TodoDAO{ID: todoID}, nil)
+				mocks.TodoRepository.EXPECT().DeleteToDo(gomock.Any(), todoID, 2).Return([]uuid.UUID{}, nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), todoID)
+			},
+		},
+		{
+			name: "FlushesParentAndSubtasks",
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 2).
+					Return(&models.TodoDAO{ID: todoID, ParentID: &parentID}, nil)
+				mocks.TodoRepository.EXPECT().DeleteToDo(gomock.Any(), todoID, 2).Return([]uuid.UUID{subtaskID}, nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), todoID)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), parentID)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), subtaskID)
+			},
+		},
+		{
+			name: "VersionConflict",
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 2).Return(nil, errCache)
+				mocks.TodoRepository.EXPECT().GetToDo(gomock.Any(), todoID).Return(&models.TodoDAO{ID: todoID, Version: 3}, nil)
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any())
+			},
+			expectedErr: appErrors.ErrVersionConflict,
+		},
+		{
+			name: "NotFound",
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 2).Return(nil, errCache)
+				mocks.TodoRepository.EXPECT().GetToDo(gomock.Any(), todoID).Return(nil, appErrors.ErrNotFound)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := svc.DeleteToDo(context.Background(), todoID, 2)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_GetUserToDos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	userID := 1
+	errDB := errors.New("db error")
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expectedLen int
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetUserToDos(gomock.Any(), userID).Return([]models.TodoDAO{
+					{ID: uuid.New(), CreatedBy: userID, Assignee: 2},
+					{ID: uuid.New(), CreatedBy: 2, Assignee: userID},
+				}, nil)
+			},
+			expectedLen: 2,
+		},
+		{
+			name: "RepositoryError",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetUserToDos(gomock.Any(), userID).Return(nil, errDB)
+			},
+			expectedErr: errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			todos, err := svc.GetUserToDos(context.Background(), userID)
+
+			requireEqualError(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				require.Len(t, todos, tt.expectedLen)
+			}
+		})
+	}
+}
+
+func TestTodoService_HandleUserDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	userID := 1
+	deletedID := uuid.New()
+	reassignedID := uuid.New()
+	errDB := errors.New("db error")
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().DetachUser(gomock.Any(), userID).Return([]uuid.UUID{deletedID, reassignedID}, nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), deletedID)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), reassignedID)
+			},
+		},
+		{
+			name: "AlreadyHandled",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().DetachUser(gomock.Any(), userID).Return([]uuid.UUID{}, nil)
+			},
+		},
+		{
+			name: "RepositoryError",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().DetachUser(gomock.Any(), userID).Return(nil, errDB)
+			},
+			expectedErr: errDB,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			_, err := svc.CreateToDo(context.Background(), newTodo)
+			err := svc.HandleUserDeleted(context.Background(), userID)
 
 			requireEqualError(t, err, tt.expectedErr)
 		})
 	}
+}
+
+func TestTodoService_AddComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	storedTodo := &models.TodoDAO{
+		ID:          uuid.New(),
+		CreatedBy:   1,
+		Assignee:    2,
+		Description: "example desc",
+	}
+	errPublish := errors.New("publish error")
+
+	tests := []struct {
+		name        string
+		comment     *models.CreateCommentDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:    "NotifiesCreatorAndAssignee",
+			comment: &models.CreateCommentDTO{TodoID: storedTodo.ID, Author: 3, Body: "comment"},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+				mocks.TodoRepository.EXPECT().CreateComment(gomock.Any(), gomock.Any()).Return(nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 3).Return(&models.UserDTO{ID: 3, Username: "author"}, nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 1).Return(&models.UserDTO{ID: 1, Email: "creator@mail.com"}, nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.UserDTO{ID: 2, Email: "assignee@mail.com"}, nil)
+				mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(data []byte, _ string) error {
+					require.Contains(t, string(data), models.TodoEventTypeCommentAdded)
+					require.Contains(t, string(data), "creator@mail.com")
+					require.Contains(t, string(data), "assignee@mail.com")
+					return nil
+				})
+			},
+		},
+		{
+			name:    "AuthorIsNotNotified",
+			comment: &models.CreateCommentDTO{TodoID: storedTodo.ID, Author: 1, Body: "comment"},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+				mocks.TodoRepository.EXPECT().CreateComment(gomock.Any(), gomock.Any()).Return(nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 1).Return(&models.UserDTO{ID: 1, Username: "author"}, nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.UserDTO{ID: 2, Email: "assignee@mail.com"}, nil)
+				mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(data []byte, _ string) error {
+					require.NotContains(t, string(data), "creator@mail.com")
+					return nil
+				})
+			},
+		},
+		{
+			name:    "DeletedReceiverSkipped",
+			comment: &models.CreateCommentDTO{TodoID: storedTodo.ID, Author: 1, Body: "comment"},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+				mocks.TodoRepository.EXPECT().CreateComment(gomock.Any(), gomock.Any()).Return(nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 1).Return(&models.UserDTO{ID: 1, Username: "author"}, nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 2).Return(nil, appErrors.ErrNotFound)
+			},
+		},
+		{
+			name:        "ValidationError",
+			comment:     &models.CreateCommentDTO{TodoID: storedTodo.ID, Author: 3, Body: "   "},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:    "TodoNotFound",
+			comment: &models.CreateCommentDTO{TodoID: storedTodo.ID, Author: 3, Body: "comment"},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(nil, errors.New("cache miss"))
+				mocks.TodoRepository.EXPECT().GetToDo(gomock.Any(), storedTodo.ID).Return(nil, appErrors.ErrNotFound)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+		{
+			name:    "PublishError",
+			comment: &models.CreateCommentDTO{TodoID: storedTodo.ID, Author: 1, Body: "comment"},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+				mocks.TodoRepository.EXPECT().CreateComment(gomock.Any(), gomock.Any()).Return(nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 1).Return(&models.UserDTO{ID: 1, Username: "author"}, nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.UserDTO{ID: 2, Email: "assignee@mail.com"}, nil)
+				mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errPublish)
+			},
+			expectedErr: errPublish,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.AddComment(context.Background(), tt.comment)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_EditComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	todoID := uuid.New()
+	stored := func() *models.CommentDAO {
+		return &models.CommentDAO{ID: uuid.New(), TodoID: todoID, Author: 1, Body: "comment"}
+	}
 
+	tests := []struct {
+		name        string
+		comment     func(c *models.CommentDAO) *models.UpdateCommentDTO
+		setup       func(c *models.CommentDAO)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			comment: func(c *models.CommentDAO) *models.UpdateCommentDTO {
+				return &models.UpdateCommentDTO{ID: c.ID, TodoID: todoID, Author: 1, Body: "edited"}
+			},
+			setup: func(c *models.CommentDAO) {
+				mocks.TodoRepository.EXPECT().GetComment(gomock.Any(), c.ID).Return(c, nil)
+				mocks.TodoRepository.EXPECT().UpdateComment(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, upd *models.CommentDAO) error {
+					require.Equal(t, "edited", upd.Body)
+					require.NotNil(t, upd.EditedAt)
+					return nil
+				})
+			},
+		},
+		{
+			name: "NotAuthor",
+			comment: func(c *models.CommentDAO) *models.UpdateCommentDTO {
+				return &models.UpdateCommentDTO{ID: c.ID, TodoID: todoID, Author: 2, Body: "edited"}
+			},
+			setup: func(c *models.CommentDAO) {
+				mocks.TodoRepository.EXPECT().GetComment(gomock.Any(), c.ID).Return(c, nil)
+			},
+			expectedErr: appErrors.ErrForbidden,
+		},
+		{
+			name: "OtherTodo",
+			comment: func(c *models.CommentDAO) *models.UpdateCommentDTO {
+				return &models.UpdateCommentDTO{ID: c.ID, TodoID: uuid.New(), Author: 1, Body: "edited"}
+			},
+			setup: func(c *models.CommentDAO) {
+				mocks.TodoRepository.EXPECT().GetComment(gomock.Any(), c.ID).Return(c, nil)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := stored()
+			tt.setup(c)
+			_, err := svc.EditComment(context.Background(), tt.comment(c))
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_DeleteComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	comment := &models.CommentDAO{ID: uuid.New(), TodoID: uuid.New(), Author: 1, Body: "comment"}
+
+	tests := []struct {
+		name        string
+		request     *models.DeleteCommentDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:    "Author",
+			request: &models.DeleteCommentDTO{ID: comment.ID, TodoID: comment.TodoID, DeletedBy: 1},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetComment(gomock.Any(), comment.ID).Return(comment, nil)
+				mocks.TodoRepository.EXPECT().DeleteComment(gomock.Any(), comment.ID, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "Moderator",
+			request: &models.DeleteCommentDTO{ID: comment.ID, TodoID: comment.TodoID, DeletedBy: 2, Moderator: true},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetComment(gomock.Any(), comment.ID).Return(comment, nil)
+				mocks.TodoRepository.EXPECT().DeleteComment(gomock.Any(), comment.ID, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "Forbidden",
+			request: &models.DeleteCommentDTO{ID: comment.ID, TodoID: comment.TodoID, DeletedBy: 2},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetComment(gomock.Any(), comment.ID).Return(comment, nil)
+			},
+			expectedErr: appErrors.ErrForbidden,
+		},
+		{
+			name:    "NotFound",
+			request: &models.DeleteCommentDTO{ID: comment.ID, TodoID: comment.TodoID, DeletedBy: 1},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetComment(gomock.Any(), comment.ID).Return(nil, appErrors.ErrNotFound)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := svc.DeleteComment(context.Background(), tt.request)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_ListComments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	storedTodo := &models.TodoDAO{ID: uuid.New(), CreatedBy: 1, Assignee: 2}
+	comments := []models.CommentDAO{{ID: uuid.New(), TodoID: storedTodo.ID, Author: 1, Body: "comment"}}
+
+	tests := []struct {
+		name        string
+		request     *models.ListCommentsDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:    "DefaultLimit",
+			request: &models.ListCommentsDTO{TodoID: storedTodo.ID},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+				mocks.TodoRepository.EXPECT().ListComments(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r *models.ListCommentsDTO) ([]models.CommentDAO, int, error) {
+					require.Equal(t, models.CommentsDefaultLimit, r.Limit)
+					return comments, 1, nil
+				})
+			},
+		},
+		{
+			name:        "LimitTooLarge",
+			request:     &models.ListCommentsDTO{TodoID: storedTodo.ID, Limit: models.CommentsMaxLimit + 1},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.ListComments(context.Background(), tt.request)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_CreateLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	tests := []struct {
+		name        string
+		label       *models.CreateLabelDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:  "Success",
+			label: &models.CreateLabelDTO{Owner: 1, Name: "urgent", Color: "#ff0000"},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().CreateLabel(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:        "InvalidColor",
+			label:       &models.CreateLabelDTO{Owner: 1, Name: "urgent", Color: "red"},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:  "Duplicate",
+			label: &models.CreateLabelDTO{Owner: 1, Name: "urgent", Color: "#ff0000"},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().CreateLabel(gomock.Any(), gomock.Any()).Return(appErrors.ErrAlreadyExists)
+			},
+			expectedErr: appErrors.ErrAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.CreateLabel(context.Background(), tt.label)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_DeleteLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	label := &models.LabelDAO{ID: uuid.New(), Owner: 1, Name: "urgent", Color: "#ff0000"}
+	labeledTodo := uuid.New()
+
+	tests := []struct {
+		name        string
+		owner       int
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:  "FlushesLabeledTodos",
+			owner: 1,
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), label.ID).Return(label, nil)
+				mocks.TodoRepository.EXPECT().DeleteLabel(gomock.Any(), label.ID).Return([]uuid.UUID{labeledTodo}, nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), labeledTodo)
+			},
+		},
+		{
+			name:  "OtherOwner",
+			owner: 2,
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), label.ID).Return(label, nil)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := svc.DeleteLabel(context.Background(), label.ID, tt.owner)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_AddTodoLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	storedTodo := &models.TodoDAO{ID: uuid.New(), CreatedBy: 1, Assignee: 2}
+	label := &models.LabelDAO{ID: uuid.New(), Owner: 1}
+	workspaceID := uuid.New()
+	workspaceTodo := &models.TodoDAO{ID: uuid.New(), CreatedBy: 1, Assignee: 2, WorkspaceID: &workspaceID}
+	workspaceLabel := &models.LabelDAO{ID: uuid.New(), Owner: 3, WorkspaceID: &workspaceID}
+
+	tests := []struct {
+		name        string
+		request     *models.TodoLabelDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:    "Success",
+			request: &models.TodoLabelDTO{TodoID: storedTodo.ID, LabelID: label.ID, Owner: 1},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), label.ID).Return(label, nil)
+				mocks.TodoRepository.EXPECT().AddTodoLabel(gomock.Any(), storedTodo.ID, label.ID).Return(nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), storedTodo.ID)
+			},
+		},
+		{
+			name:    "ForeignLabel",
+			request: &models.TodoLabelDTO{TodoID: storedTodo.ID, LabelID: label.ID, Owner: 2},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), label.ID).Return(label, nil)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+		{
+			name:    "WorkspaceLabel",
+			request: &models.TodoLabelDTO{TodoID: workspaceTodo.ID, LabelID: workspaceLabel.ID, Owner: 2},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), workspaceTodo.ID).Return(workspaceTodo, nil)
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), workspaceLabel.ID).Return(workspaceLabel, nil)
+				mocks.TodoRepository.EXPECT().AddTodoLabel(gomock.Any(), workspaceTodo.ID, workspaceLabel.ID).Return(nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), workspaceTodo.ID)
+			},
+		},
+		{
+			name:    "WorkspaceLabelOnPersonalTodo",
+			request: &models.TodoLabelDTO{TodoID: storedTodo.ID, LabelID: workspaceLabel.ID, Owner: 1},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), storedTodo.ID).Return(storedTodo, nil)
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), workspaceLabel.ID).Return(workspaceLabel, nil)
+			},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:    "PersonalLabelOnWorkspaceTodo",
+			request: &models.TodoLabelDTO{TodoID: workspaceTodo.ID, LabelID: label.ID, Owner: 1},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), workspaceTodo.ID).Return(workspaceTodo, nil)
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), label.ID).Return(label, nil)
+			},
+			expectedErr: &validator.ValidationError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := svc.AddTodoLabel(context.Background(), tt.request)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_ListToDos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	labelID := uuid.New()
+
+	tests := []struct {
+		name        string
+		filter      *models.ListTodosDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:   "DefaultsAndDuplicateLabels",
+			filter: &models.ListTodosDTO{UserID: 1, LabelIDs: []uuid.UUID{labelID, labelID}, LabelMatch: models.LabelMatchAll},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().ListToDos(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f *models.ListTodosDTO) ([]models.TodoDAO, error) {
+					require.Equal(t, models.TodosDefaultLimit, f.Limit)
+					require.Equal(t, []uuid.UUID{labelID}, f.LabelIDs)
+					return []models.TodoDAO{{ID: uuid.New(), Labels: []uuid.UUID{labelID}}}, nil
+				})
+			},
+		},
+		{
+			name:        "InvalidMatch",
+			filter:      &models.ListTodosDTO{UserID: 1, LabelMatch: "some"},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.ListToDos(context.Background(), tt.filter)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_SearchTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	errDB := errors.New("db error")
+
+	tests := []struct {
+		name        string
+		request     *models.SearchTodosDTO
+		setup       func()
+		check       func(t *testing.T, res []*models.SearchResultDTO)
+		expectedErr error
+	}{
+		{
+			name:    "HighlightsEscapedSnippets",
+			request: &models.SearchTodosDTO{UserID: 1, Query: " deploy "},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().SearchTodos(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r *models.SearchTodosDTO) ([]models.SearchResultDAO, error) {
+					require.Equal(t, "deploy", r.Query)
+					require.Equal(t, models.SearchDefaultLimit, r.Limit)
+					return []models.SearchResultDAO{{
+						Todo:               models.TodoDAO{ID: uuid.New(), CreatedBy: 1, Assignee: 1},
+						Rank:               0.5,
+						DescriptionSnippet: "<script> [[[deploy]]] to prod",
+					}}, nil
+				})
+			},
+			check: func(t *testing.T, res []*models.SearchResultDTO) {
+				require.Len(t, res, 1)
+				require.Equal(t, "&lt;script&gt; <mark>deploy</mark> to prod", res[0].DescriptionSnippet)
+				require.Empty(t, res[0].CommentSnippet)
+			},
+		},
+		{
+			name:        "BlankQuery",
+			request:     &models.SearchTodosDTO{UserID: 1, Query: "   "},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:    "RepositoryError",
+			request: &models.SearchTodosDTO{UserID: 1, Query: "deploy"},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().SearchTodos(gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+			expectedErr: errDB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			res, err := svc.SearchTodos(context.Background(), tt.request)
+
+			requireEqualError(t, err, tt.expectedErr)
+			if tt.check != nil {
+				tt.check(t, res)
+			}
+		})
+	}
+}
+
+func TestTodoService_UpdateToDoSubtasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	todoID, parentID, childID, workspaceID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	errCache := errors.New("cache miss")
+
+	tests := []struct {
+		name        string
+		update      *models.UpdateTodoDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:   "DoneWithOpenBlockers",
+			update: &models.UpdateTodoDTO{ID: todoID, Version: 1, UpdatedBy: 1, Assignee: 2, Description: "desc", Done: true},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(&models.TodoDAO{ID: todoID, CreatedBy: 1, Assignee: 2}, nil)
+				mocks.TodoRepository.EXPECT().CountOpenBlockers(gomock.Any(), todoID).Return(1, nil)
+			},
+			expectedErr: appErrors.ErrTodoBlocked,
+		},
+		{
+			name:   "DoneFlushesParent",
+			update: &models.UpdateTodoDTO{ID: todoID, Version: 1, UpdatedBy: 1, Assignee: 2, Description: "desc", ParentID: &parentID, Done: true},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(&models.TodoDAO{ID: todoID, CreatedBy: 1, Assignee: 2, ParentID: &parentID}, nil)
+				mocks.TodoRepository.EXPECT().CountOpenBlockers(gomock.Any(), todoID).Return(0, nil)
+				mocks.TodoRepository.EXPECT().UpdateToDo(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *models.TodoDAO, _ []models.TodoEventDAO) (uuid.UUID, error) {
+						require.True(t, todo.Done)
+						return todo.ID, nil
+					})
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any())
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), parentID)
+			},
+		},
+		{
+			name:   "ParentIsOwnSubtask",
+			update: &models.UpdateTodoDTO{ID: todoID, Version: 1, UpdatedBy: 1, Assignee: 2, Description: "desc", ParentID: &childID},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(&models.TodoDAO{ID: todoID, CreatedBy: 1, Assignee: 2}, nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), childID).
+					Return(&models.TodoDAO{ID: childID, ParentID: &todoID}, nil)
+				mocks.TodoRepository.EXPECT().UpdateToDo(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(uuid.Nil, fmt.Errorf("[UpdateToDO] %w", appErrors.ErrCycle))
+			},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:   "ParentIsSelf",
+			update: &models.UpdateTodoDTO{ID: todoID, Version: 1, UpdatedBy: 1, Assignee: 2, Description: "desc", ParentID: &todoID},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(&models.TodoDAO{ID: todoID, CreatedBy: 1, Assignee: 2}, nil)
+			},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:   "ParentNotFound",
+			update: &models.UpdateTodoDTO{ID: todoID, Version: 1, UpdatedBy: 1, Assignee: 2, Description: "desc", ParentID: &parentID},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(&models.TodoDAO{ID: todoID, CreatedBy: 1, Assignee: 2}, nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), parentID).Return(nil, errCache)
+				mocks.TodoRepository.EXPECT().GetToDo(gomock.Any(), parentID).Return(nil, appErrors.ErrNotFound)
+			},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:   "ParentInAnotherWorkspace",
+			update: &models.UpdateTodoDTO{ID: todoID, Version: 1, UpdatedBy: 1, Assignee: 2, Description: "desc", ParentID: &parentID},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(&models.TodoDAO{ID: todoID, CreatedBy: 1, Assignee: 2, WorkspaceID: &workspaceID}, nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), parentID).
+					Return(&models.TodoDAO{ID: parentID, CreatedBy: 1, Assignee: 2}, nil)
+			},
+			expectedErr: &validator.ValidationError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.UpdateToDo(context.Background(), tt.update)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_AddTodoDependency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	a, b := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name        string
+		dependency  *models.TodoDependencyDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:        "SelfDependency",
+			dependency:  &models.TodoDependencyDTO{TodoID: a, BlockedBy: a},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:       "TransitiveCycle",
+			dependency: &models.TodoDependencyDTO{TodoID: a, BlockedBy: b},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), a).Return(&models.TodoDAO{ID: a}, nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), b).Return(&models.TodoDAO{ID: b}, nil)
+				// b заблокирована c, а c заблокирована a: цикл находит репозиторий в транзакции вставки
+				mocks.TodoRepository.EXPECT().AddTodoDependency(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("[AddTodoDependency] %w", appErrors.ErrCycle))
+			},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:       "Success",
+			dependency: &models.TodoDependencyDTO{TodoID: a, BlockedBy: b},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), a).Return(&models.TodoDAO{ID: a}, nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), b).Return(&models.TodoDAO{ID: b}, nil)
+				mocks.TodoRepository.EXPECT().AddTodoDependency(gomock.Any(), gomock.Any()).Return(nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), a)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := svc.AddTodoDependency(context.Background(), tt.dependency)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_ListTodoTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	rootID, childID, grandchildID := uuid.New(), uuid.New(), uuid.New()
+
+	mocks.TodoRepository.EXPECT().ListToDos(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter *models.ListTodosDTO) ([]models.TodoDAO, error) {
+			require.True(t, filter.RootsOnly)
+			require.Equal(t, models.TodosDefaultLimit, filter.Limit)
+			return []models.TodoDAO{{ID: rootID, SubtasksTotal: 1}}, nil
+		})
+	mocks.TodoRepository.EXPECT().ListSubtasks(gomock.Any(), []uuid.UUID{rootID}, gomock.Any()).
+		Return([]models.TodoDAO{
+			{ID: childID, ParentID: &rootID},
+			{ID: grandchildID, ParentID: &childID, Done: true},
+		}, nil)
+
+	tree, err := svc.ListTodoTree(context.Background(), &models.ListTodosDTO{UserID: 1})
+	require.NoError(t, err)
+	require.Len(t, tree, 1)
+	require.Equal(t, rootID, tree[0].ID)
+	require.Len(t, tree[0].Subtasks, 1)
+	require.Equal(t, childID, tree[0].Subtasks[0].ID)
+	require.Len(t, tree[0].Subtasks[0].Subtasks, 1)
+	require.True(t, tree[0].Subtasks[0].Subtasks[0].Done)
+}
+
+func TestTodoService_CreateRecurringToDo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	dueAt := time.Date(2099, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		newTodo     *models.CreateTodoDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "CanonicalRuleStartsSeries",
+			newTodo: &models.CreateTodoDTO{
+				CreatedBy: 1, Assignee: 2, Description: "desc", DueAt: &dueAt, Recurrence: "rrule:freq=weekly;byday=mo",
+			},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().CreateToDo(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todo *models.CreateTodoDTO) (uuid.UUID, error) {
+						require.Equal(t, "FREQ=WEEKLY;BYDAY=MO", todo.Recurrence)
+						require.Equal(t, "UTC", todo.Timezone)
+						require.Equal(t, &todo.ID, todo.SeriesID)
+						require.Equal(t, 1, todo.Occurrence)
+						require.Equal(t, todo.DueAt, todo.SeriesStart)
+						return todo.ID, nil
+					})
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name: "InvalidRule",
+			newTodo: &models.CreateTodoDTO{
+				CreatedBy: 1, Assignee: 2, Description: "desc", DueAt: &dueAt, Recurrence: "FREQ=YEARLY",
+			},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:        "RuleWithoutDueDate",
+			newTodo:     &models.CreateTodoDTO{CreatedBy: 1, Assignee: 2, Description: "desc", Recurrence: "FREQ=DAILY"},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name: "UnknownTimezone",
+			newTodo: &models.CreateTodoDTO{
+				CreatedBy: 1, Assignee: 2, Description: "desc", DueAt: &dueAt, Recurrence: "FREQ=DAILY",
+				Timezone: "Mars/Olympus",
+			},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.CreateToDo(context.Background(), tt.newTodo)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_CompleteRecurringToDo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	todoID, seriesID, labelID := uuid.New(), uuid.New(), uuid.New()
+	// 2099-01-05 - понедельник
+	dueAt := time.Date(2099, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// 2099-03-08 в Нью-Йорке нет времени с 02:00 до 03:00
+	seriesStart := time.Date(2099, 3, 7, 2, 30, 0, 0, newYork)
+	gapDueAt := time.Date(2099, 3, 8, 3, 30, 0, 0, newYork)
+
+	existing := func(recurrence string, occurrence int) *models.TodoDAO {
+		return &models.TodoDAO{
+			ID: todoID, CreatedBy: 1, Assignee: 2, Description: "take out trash", Labels: []uuid.UUID{labelID},
+			DueAt: &dueAt, Recurrence: recurrence, Timezone: "Europe/Berlin", SeriesID: &seriesID, Occurrence: occurrence,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		update *models.UpdateTodoDTO
+		setup  func()
+	}{
+		{
+			name: "SpawnsNextOccurrence",
+			update: &models.UpdateTodoDTO{
+				ID: todoID, Version: 1, UpdatedBy: 1, Assignee: 2, Description: "take out trash", Done: true, DueAt: &dueAt,
+				Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH", Timezone: "Europe/Berlin",
+			},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(existing("FREQ=WEEKLY;BYDAY=MO,TH", 3), nil)
+				mocks.TodoRepository.EXPECT().CountOpenBlockers(gomock.Any(), todoID).Return(0, nil)
+				mocks.TodoRepository.EXPECT().UpdateToDo(gomock.Any(), gomock.Any(), gomock.Any()).Return(todoID, nil)
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any()).Times(2)
+				mocks.TodoRepository.EXPECT().CreateOccurrence(gomock.Any(), todoID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, next *models.TodoDAO) (bool, error) {
+						require.NotEqual(t, todoID, next.ID)
+						require.Equal(t, 2, next.Assignee)
+						require.Equal(t, "take out trash", next.Description)
+						require.Equal(t, []uuid.UUID{labelID}, next.Labels)
+						require.Equal(t, &seriesID, next.SeriesID)
+						require.Equal(t, 4, next.Occurrence)
+						require.False(t, next.Done)
+						// четверг в то же местное время
+						require.True(t, time.Date(2099, 1, 8, 9, 0, 0, 0, time.UTC).Equal(*next.DueAt))
+						return true, nil
+					})
+			},
+		},
+		{
+			name: "ReturnsToSeriesTimeAfterDSTGap",
+			update: &models.UpdateTodoDTO{
+				ID: todoID, Version: 1, UpdatedBy: 1, Assignee: 2, Description: "take out trash", Done: true,
+				DueAt: &gapDueAt, Recurrence: "FREQ=DAILY", Timezone: "America/New_York",
+			},
+			setup: func() {
+				// серия начата в 02:30, повторение 8 марта сдвинуто переходом на летнее время на 03:30
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(&models.TodoDAO{
+						ID: todoID, CreatedBy: 1, Assignee: 2, Description: "take out trash", DueAt: &gapDueAt,
+						Recurrence: "FREQ=DAILY", Timezone: "America/New_York", SeriesID: &seriesID, Occurrence: 2,
+						SeriesStart: &seriesStart,
+					}, nil)
+				mocks.TodoRepository.EXPECT().CountOpenBlockers(gomock.Any(), todoID).Return(0, nil)
+				mocks.TodoRepository.EXPECT().UpdateToDo(gomock.Any(), gomock.Any(), gomock.Any()).Return(todoID, nil)
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any()).Times(2)
+				mocks.TodoRepository.EXPECT().CreateOccurrence(gomock.Any(), todoID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, next *models.TodoDAO) (bool, error) {
+						require.True(t, time.Date(2099, 3, 9, 2, 30, 0, 0, newYork).Equal(*next.DueAt),
+							"next occurrence at %s", next.DueAt.In(newYork))
+						require.Equal(t, &seriesStart, next.SeriesStart)
+						return true, nil
+					})
+			},
+		},
+		{
+			name: "CountExhaustedFinishesSeries",
+			update: &models.UpdateTodoDTO{
+				ID: todoID, Version: 1, UpdatedBy: 1, Assignee: 2, Description: "take out trash", Done: true, DueAt: &dueAt,
+				Recurrence: "FREQ=DAILY;COUNT=3", Timezone: "Europe/Berlin",
+			},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoVersion(gomock.Any(), todoID, 1).
+					Return(existing("FREQ=DAILY;COUNT=3", 3), nil)
+				mocks.TodoRepository.EXPECT().CountOpenBlockers(gomock.Any(), todoID).Return(0, nil)
+				mocks.TodoRepository.EXPECT().UpdateToDo(gomock.Any(), gomock.Any(), gomock.Any()).Return(todoID, nil)
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any())
+				mocks.TodoRepository.EXPECT().FinishSeries(gomock.Any(), todoID).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.UpdateToDo(context.Background(), tt.update)
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTodoService_GenerateDueOccurrences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	todoID := uuid.New()
+	dueAt := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	mocks.TodoRepository.EXPECT().ListDueRecurring(gomock.Any(), gomock.Any(), 100).
+		Return([]models.TodoDAO{{
+			ID: todoID, CreatedBy: 1, Assignee: 2, Description: "desc", DueAt: &dueAt,
+			Recurrence: "FREQ=DAILY", Timezone: "UTC", SeriesID: &todoID, Occurrence: 1,
+		}}, nil)
+	// пропущенные повторения не создаются, следующее - первое после текущего момента
+	mocks.TodoRepository.EXPECT().CreateOccurrence(gomock.Any(), todoID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, next *models.TodoDAO) (bool, error) {
+			require.True(t, next.DueAt.After(now))
+			require.True(t, next.DueAt.Before(now.Add(24*time.Hour)))
+			require.Equal(t, 9, next.DueAt.Hour())
+			return false, nil
+		})
+
+	generated, err := svc.GenerateDueOccurrences(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, generated)
+}
+
+func TestTodoService_CreateWorkspace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	tests := []struct {
+		name        string
+		workspace   *models.CreateWorkspaceDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:      "Success",
+			workspace: &models.CreateWorkspaceDTO{CreatedBy: 1, Name: " household "},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().CreateWorkspace(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, workspace *models.WorkspaceDAO) error {
+						require.Equal(t, "household", workspace.Name)
+						require.Equal(t, 1, workspace.CreatedBy)
+						return nil
+					})
+			},
+		},
+		{
+			name:        "BlankName",
+			workspace:   &models.CreateWorkspaceDTO{CreatedBy: 1, Name: "   "},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			workspace, err := svc.CreateWorkspace(context.Background(), tt.workspace)
+
+			requireEqualError(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				require.Equal(t, models.WorkspaceRoleOwner, workspace.Role)
+			}
+		})
+	}
+}
+
+func TestTodoService_RemoveWorkspaceMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	workspaceID := uuid.New()
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().RemoveWorkspaceMember(gomock.Any(), workspaceID, 2).Return(nil)
+			},
+		},
+		{
+			name: "LastOwner",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().RemoveWorkspaceMember(gomock.Any(), workspaceID, 2).
+					Return(appErrors.ErrLastWorkspaceOwner)
+			},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name: "NotMember",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().RemoveWorkspaceMember(gomock.Any(), workspaceID, 2).
+					Return(appErrors.ErrNotFound)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := svc.RemoveWorkspaceMember(context.Background(), workspaceID, 2)
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_InviteWorkspaceMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	workspaceID := uuid.New()
+	var storedHash string
+
+	mocks.TodoRepository.EXPECT().GetWorkspace(gomock.Any(), workspaceID).
+		Return(&models.WorkspaceDAO{ID: workspaceID, Name: "household", CreatedBy: 1}, nil)
+	mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 1).
+		Return(&models.UserDTO{ID: 1, Username: "owner", Email: "owner@example.com"}, nil)
+	mocks.TodoRepository.EXPECT().CreateWorkspaceInvitation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, invitation *models.WorkspaceInvitationDAO) error {
+			require.Equal(t, models.WorkspaceRoleEditor, invitation.Role)
+			require.True(t, invitation.ExpiresAt.After(invitation.CreatedAt))
+			storedHash = invitation.TokenHash
+			return nil
+		})
+	mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(data []byte, _ string) error {
+			var item models.TodoMailItem
+			require.NoError(t, json.Unmarshal(data, &item))
+			require.Equal(t, models.TodoEventTypeWorkspaceInvitation, item.TodoEventType)
+			require.Equal(t, []string{"friend@example.com"}, item.Receivers)
+			require.Equal(t, "household", item.WorkspaceName)
+
+			link, err := url.Parse(item.Link)
+			require.NoError(t, err)
+			// в письмо уходит сам токен, а в базе остается только его хэш
+			require.Equal(t, storedHash, hashInvitationToken(link.Query().Get("token")))
+			return nil
+		})
+
+	invitation, err := svc.InviteWorkspaceMember(context.Background(), &models.InviteWorkspaceMemberDTO{
+		WorkspaceID: workspaceID,
+		Email:       "friend@example.com",
+		Role:        models.WorkspaceRoleEditor,
+		InvitedBy:   1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "friend@example.com", invitation.Email)
+}
+
+func TestTodoService_AcceptWorkspaceInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	workspaceID, invitationID := uuid.New(), uuid.New()
+	invitation := &models.WorkspaceInvitationDAO{
+		ID:          invitationID,
+		WorkspaceID: workspaceID,
+		Email:       "Friend@example.com",
+		Role:        models.WorkspaceRoleViewer,
+	}
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetWorkspaceInvitation(gomock.Any(), hashInvitationToken("token")).
+					Return(invitation, nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 2).
+					Return(&models.UserDTO{ID: 2, Email: "friend@example.com"}, nil)
+				mocks.TodoRepository.EXPECT().AcceptWorkspaceInvitation(gomock.Any(), invitationID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, member *models.WorkspaceMemberDAO) error {
+						require.Equal(t, models.WorkspaceRoleViewer, member.Role)
+						return nil
+					})
+				mocks.TodoRepository.EXPECT().GetWorkspaceMember(gomock.Any(), workspaceID, 2).
+					Return(&models.WorkspaceMemberDAO{WorkspaceID: workspaceID, UserID: 2, Role: models.WorkspaceRoleViewer}, nil)
+			},
+		},
+		{
+			name: "AnotherEmail",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetWorkspaceInvitation(gomock.Any(), hashInvitationToken("token")).
+					Return(invitation, nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 2).
+					Return(&models.UserDTO{ID: 2, Email: "stranger@example.com"}, nil)
+			},
+			expectedErr: appErrors.ErrForbidden,
+		},
+		{
+			name: "UnknownToken",
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetWorkspaceInvitation(gomock.Any(), hashInvitationToken("token")).
+					Return(nil, appErrors.ErrNotFound)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			_, err := svc.AcceptWorkspaceInvitation(context.Background(),
+				&models.AcceptWorkspaceInvitationDTO{Token: "token", UserID: 2})
+
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestTodoService_BulkUpdateTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	a, b, c, labelID, workspaceID := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	errCache := errors.New("cache miss")
+
+	stored := func(id uuid.UUID, assignee int) *models.TodoDAO {
+		return &models.TodoDAO{ID: id, CreatedBy: 1, Assignee: assignee, Description: "desc " + id.String()[:4], Version: 1}
+	}
+
+	tests := []struct {
+		name        string
+		request     *models.BulkUpdateTodosDTO
+		setup       func()
+		expected    map[uuid.UUID]string
+		expectedErr error
+	}{
+		{
+			name:    "ReassignSendsOneMailPerAssignee",
+			request: &models.BulkUpdateTodosDTO{Actor: 1, IDs: []uuid.UUID{a, b, c, a}, Operation: models.BulkOperationReassign, Assignee: 3},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), a).Return(stored(a, 2), nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), b).Return(stored(b, 2), nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), c).Return(nil, errCache)
+				mocks.TodoRepository.EXPECT().GetToDo(gomock.Any(), c).Return(nil, appErrors.ErrNotFound)
+				mocks.TodoRepository.EXPECT().UpdateToDos(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todos []*models.TodoDAO, events []models.TodoEventDAO) (map[uuid.UUID]error, error) {
+						require.Len(t, todos, 2)
+						require.Len(t, events, 2)
+						for i, todo := range todos {
+							require.Equal(t, 3, todo.Assignee)
+							require.Equal(t, models.TodoFieldAssignee, events[i].Field)
+							require.Equal(t, 1, events[i].Actor)
+						}
+						return map[uuid.UUID]error{}, nil
+					})
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any()).Times(2)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 1).Return(&models.UserDTO{ID: 1, Username: "author"}, nil)
+				mocks.UsersClient.EXPECT().GetUserByID(gomock.Any(), 3).Return(&models.UserDTO{ID: 3, Email: "assignee@mail.com"}, nil)
+				mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(data []byte, _ string) error {
+					var item models.TodoMailItem
+					require.NoError(t, json.Unmarshal(data, &item))
+					require.Equal(t, models.TodoEventTypeBulkUpdated, item.TodoEventType)
+					require.Equal(t, []string{"assignee@mail.com"}, item.Receivers)
+					require.Equal(t, models.BulkOperationReassign, item.Operation)
+					require.Len(t, item.Todos, 2)
+					return nil
+				})
+			},
+			expected: map[uuid.UUID]string{a: "", b: "", c: models.BulkErrorNotFound},
+		},
+		{
+			name:    "CompleteSkipsBlockedAndStale",
+			request: &models.BulkUpdateTodosDTO{Actor: 1, IDs: []uuid.UUID{a, b, c}, Operation: models.BulkOperationComplete},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), a).Return(stored(a, 1), nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), b).Return(stored(b, 1), nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), c).Return(stored(c, 1), nil)
+				mocks.TodoRepository.EXPECT().CountOpenBlockers(gomock.Any(), a).Return(1, nil)
+				mocks.TodoRepository.EXPECT().CountOpenBlockers(gomock.Any(), b).Return(0, nil)
+				mocks.TodoRepository.EXPECT().CountOpenBlockers(gomock.Any(), c).Return(0, nil)
+				mocks.TodoRepository.EXPECT().UpdateToDos(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, todos []*models.TodoDAO, _ []models.TodoEventDAO) (map[uuid.UUID]error, error) {
+						require.Len(t, todos, 2)
+						for _, todo := range todos {
+							require.True(t, todo.Done)
+						}
+						return map[uuid.UUID]error{c: appErrors.ErrVersionConflict}, nil
+					})
+				mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any())
+			},
+			expected: map[uuid.UUID]string{a: models.BulkErrorBlocked, b: "", c: models.BulkErrorVersionConflict},
+		},
+		{
+			name:    "DeleteFlushesAffected",
+			request: &models.BulkUpdateTodosDTO{Actor: 1, IDs: []uuid.UUID{a, b}, Operation: models.BulkOperationDelete},
+			setup: func() {
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), a).Return(stored(a, 1), nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), b).Return(stored(b, 1), nil)
+				mocks.TodoRepository.EXPECT().DeleteToDos(gomock.Any(), gomock.Len(2)).
+					Return(map[uuid.UUID]error{b: appErrors.ErrNotFound}, []uuid.UUID{c}, nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), a)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), c)
+			},
+			expected: map[uuid.UUID]string{a: "", b: models.BulkErrorNotFound},
+		},
+		{
+			name: "Relabel",
+			request: &models.BulkUpdateTodosDTO{
+				Actor: 1, IDs: []uuid.UUID{a}, Operation: models.BulkOperationRelabel, AddLabelIDs: []uuid.UUID{labelID},
+			},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), labelID).Return(&models.LabelDAO{ID: labelID, Owner: 1}, nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), a).Return(stored(a, 1), nil)
+				mocks.TodoRepository.EXPECT().RelabelToDos(gomock.Any(), []uuid.UUID{a}, []uuid.UUID{labelID}, []uuid.UUID{}).Return(nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), a)
+			},
+			expected: map[uuid.UUID]string{a: ""},
+		},
+		{
+			name: "RelabelWorkspaceLabel",
+			request: &models.BulkUpdateTodosDTO{
+				Actor: 1, IDs: []uuid.UUID{a, b}, Operation: models.BulkOperationRelabel, AddLabelIDs: []uuid.UUID{labelID},
+			},
+			setup: func() {
+				workspaceTodo := stored(b, 1)
+				workspaceTodo.WorkspaceID = &workspaceID
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), labelID).
+					Return(&models.LabelDAO{ID: labelID, Owner: 2, WorkspaceID: &workspaceID}, nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), a).Return(stored(a, 1), nil)
+				mocks.TodoRedisManager.EXPECT().GetCacheByTodoID(gomock.Any(), b).Return(workspaceTodo, nil)
+				mocks.TodoRepository.EXPECT().RelabelToDos(gomock.Any(), []uuid.UUID{b}, []uuid.UUID{labelID}, []uuid.UUID{}).Return(nil)
+				mocks.TodoRedisManager.EXPECT().FlushCache(gomock.Any(), b)
+			},
+			expected: map[uuid.UUID]string{a: models.BulkErrorLabelNotInWorkspace, b: ""},
+		},
+		{
+			name: "RelabelForeignLabel",
+			request: &models.BulkUpdateTodosDTO{
+				Actor: 1, IDs: []uuid.UUID{a}, Operation: models.BulkOperationRelabel, RemoveLabelIDs: []uuid.UUID{labelID},
+			},
+			setup: func() {
+				mocks.TodoRepository.EXPECT().GetLabel(gomock.Any(), labelID).Return(&models.LabelDAO{ID: labelID, Owner: 2}, nil)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+		{
+			name:        "RelabelWithoutLabels",
+			request:     &models.BulkUpdateTodosDTO{Actor: 1, IDs: []uuid.UUID{a}, Operation: models.BulkOperationRelabel},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:        "ReassignWithoutAssignee",
+			request:     &models.BulkUpdateTodosDTO{Actor: 1, IDs: []uuid.UUID{a}, Operation: models.BulkOperationReassign},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			result, err := svc.BulkUpdateTodos(context.Background(), tt.request)
+
+			requireEqualError(t, err, tt.expectedErr)
+			if tt.expectedErr != nil {
+				return
+			}
+
+			require.Len(t, result.Results, len(tt.expected))
+			failed := 0
+			for _, item := range result.Results {
+				require.Equal(t, tt.expected[item.ID], item.Error, item.ID)
+				if item.Error != "" {
+					failed++
+				}
+			}
+			require.Equal(t, failed, result.Failed)
+			require.Equal(t, len(tt.expected)-failed, result.Succeeded)
+		})
+	}
+}
+
+func TestChunkIDs(t *testing.T) {
+	ids := make([]uuid.UUID, 5)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	require.Equal(t, [][]uuid.UUID{ids[:2], ids[2:4], ids[4:]}, chunkIDs(ids, 2))
+	require.Equal(t, [][]uuid.UUID{ids}, chunkIDs(ids, 5))
+	require.Empty(t, chunkIDs(nil, 5))
+}
+
+func TestTodoService_ExportTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	page := make([]models.TodoDAO, models.ExportPageSize)
+	for i := range page {
+		page[i] = models.TodoDAO{ID: uuid.New(), CreatedBy: 1, Assignee: 1}
+	}
+	last := models.TodoDAO{ID: uuid.New(), CreatedBy: 1, Assignee: 1}
+
+	gomock.InOrder(
+		mocks.TodoRepository.EXPECT().ListToDos(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, filter *models.ListTodosDTO) ([]models.TodoDAO, error) {
+				require.Equal(t, 0, filter.Offset)
+				return page, nil
+			}),
+		mocks.TodoRepository.EXPECT().ListToDos(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, filter *models.ListTodosDTO) ([]models.TodoDAO, error) {
+				require.Equal(t, models.ExportPageSize, filter.Offset)
+				return []models.TodoDAO{last}, nil
+			}),
+	)
+
+	sent := 0
+	err := svc.ExportTodos(context.Background(), &models.ListTodosDTO{UserID: 1, Limit: 5, Offset: 10}, func(todo *models.TodoDTO) error {
+		sent++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.ExportPageSize+1, sent)
+}
+
+func TestTodoService_ImportTodos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	dueAt := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	rows := func() []*models.ImportTodoRowDTO {
+		return []*models.ImportTodoRowDTO{
+			{Row: 2, Todo: &models.CreateTodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 2, Description: "weekly report", DueAt: &dueAt, Recurrence: "FREQ=WEEKLY;BYDAY=MO"}},
+			{Row: 3, Todo: &models.CreateTodoDTO{ID: uuid.New(), CreatedBy: 1, Assignee: 1, Description: "done already", Done: true}},
+		}
+	}
+
+	t.Run("CreatesAllRows", func(t *testing.T) {
+		mocks.TodoRepository.EXPECT().CreateToDos(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, todos []*models.CreateTodoDTO) error {
+				require.Len(t, todos, 2)
+				require.Equal(t, &todos[0].ID, todos[0].SeriesID)
+				require.Equal(t, 1, todos[0].Occurrence)
+				require.Equal(t, "UTC", todos[0].Timezone)
+				require.True(t, todos[1].Done)
+				return nil
+			})
+		mocks.TodoRedisManager.EXPECT().StoreCache(gomock.Any(), gomock.Any()).Times(2)
+
+		result, err := svc.ImportTodos(context.Background(), &models.ImportTodosDTO{CreatedBy: 1, Rows: rows()})
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Imported)
+		require.Equal(t, 0, result.Failed)
+		require.NotNil(t, result.Rows[0].ID)
+		require.Equal(t, 3, result.Rows[1].Row)
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		result, err := svc.ImportTodos(context.Background(), &models.ImportTodosDTO{CreatedBy: 1, DryRun: true, Rows: rows()})
+		require.NoError(t, err)
+		require.Equal(t, 0, result.Imported)
+		require.Nil(t, result.Rows[0].ID)
+	})
+
+	t.Run("InvalidRowCancelsImport", func(t *testing.T) {
+		invalid := rows()
+		invalid[0].Todo.Recurrence = "FREQ=HOURLY"
+		invalid[1].Todo.Description = ""
+
+		result, err := svc.ImportTodos(context.Background(), &models.ImportTodosDTO{CreatedBy: 1, Rows: invalid})
+		require.NoError(t, err)
+		require.Equal(t, 0, result.Imported)
+		require.Equal(t, 2, result.Failed)
+		require.Equal(t, "recurrence", result.Rows[0].Errors[0].Field)
+		require.Equal(t, "description", result.Rows[1].Errors[0].Field)
+	})
+
+	t.Run("TooManyRows", func(t *testing.T) {
+		_, err := svc.ImportTodos(context.Background(), &models.ImportTodosDTO{
+			CreatedBy: 1,
+			Rows:      make([]*models.ImportTodoRowDTO, models.ImportMaxRows+1),
+		})
+		requireEqualError(t, err, validator.NewValidationError())
+	})
 }
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/pkg/validator"
+)
+
+// ListTodoTree возвращает страницу корневых задач пользователя или пространства вместе с видимыми подзадачами.
+// Фильтр по меткам и пагинация применяются только к корням
+func (t *TodoService) ListTodoTree(ctx context.Context, filter *models.ListTodosDTO) ([]*models.TodoNodeDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListTodoTree")
+	defer span.Finish()
+
+	if err := t.prepareListFilter(filter); err != nil {
+		return nil, fmt.Errorf("[ListTodoTree] validate:%w", err)
+	}
+	filter.RootsOnly = true
+
+	roots, err := t.todoRepo.ListToDos(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("[ListTodoTree] get roots:%w", err)
+	}
+
+	res := make([]*models.TodoNodeDTO, 0, len(roots))
+	if len(roots) == 0 {
+		return res, nil
+	}
+
+	nodes := make(map[uuid.UUID]*models.TodoNodeDTO, len(roots))
+	rootIDs := make([]uuid.UUID, 0, len(roots))
+	for i := range roots {
+		node := newTodoNode(&roots[i])
+		nodes[node.ID] = node
+		rootIDs = append(rootIDs, node.ID)
+		res = append(res, node)
+	}
+
+	subtasks, err := t.todoRepo.ListSubtasks(ctx, rootIDs, filter)
+	if err != nil {
+		return nil, fmt.Errorf("[ListTodoTree] get subtasks:%w", err)
+	}
+
+	for i := range subtasks {
+		node := newTodoNode(&subtasks[i])
+		nodes[node.ID] = node
+	}
+
+	// подзадачи отсортированы по времени создания, поэтому порядок детей сохраняется
+	for i := range subtasks {
+		parent, ok := nodes[*subtasks[i].ParentID]
+		if !ok {
+			continue
+		}
+		parent.Subtasks = append(parent.Subtasks, nodes[subtasks[i].ID])
+	}
+
+	return res, nil
+}
+
+// AddTodoDependency отмечает, что задача не может быть выполнена раньше блокирующей.
+// Зависимость, замыкающая цикл, отклоняется репозиторием в транзакции вставки
+func (t *TodoService) AddTodoDependency(ctx context.Context, dependency *models.TodoDependencyDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AddTodoDependency")
+	defer span.Finish()
+
+	if dependency.TodoID == dependency.BlockedBy {
+		return fmt.Errorf("[AddTodoDependency] %w", blockedByViolation("a todo cannot block itself"))
+	}
+
+	if _, err := t.getTodo(ctx, dependency.TodoID); err != nil {
+		return fmt.Errorf("[AddTodoDependency] get todo:%w", err)
+	}
+
+	if _, err := t.getTodo(ctx, dependency.BlockedBy); err != nil {
+		return fmt.Errorf("[AddTodoDependency] get blocker:%w", err)
+	}
+
+	if err := t.todoRepo.AddTodoDependency(ctx, dependency); err != nil {
+		if errors.Is(err, app_errors.ErrCycle) {
+			return fmt.Errorf("[AddTodoDependency] %w", blockedByViolation("dependency would create a cycle"))
+		}
+		return fmt.Errorf("[AddTodoDependency] add dependency:%w", err)
+	}
+
+	t.todoRedis.FlushCache(ctx, dependency.TodoID)
+
+	return nil
+}
+
+func (t *TodoService) RemoveTodoDependency(ctx context.Context, dependency *models.TodoDependencyDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveTodoDependency")
+	defer span.Finish()
+
+	if _, err := t.getTodo(ctx, dependency.TodoID); err != nil {
+		return fmt.Errorf("[RemoveTodoDependency] get todo:%w", err)
+	}
+
+	if err := t.todoRepo.RemoveTodoDependency(ctx, dependency); err != nil {
+		return fmt.Errorf("[RemoveTodoDependency] remove dependency:%w", err)
+	}
+
+	t.todoRedis.FlushCache(ctx, dependency.TodoID)
+
+	return nil
+}
+
+// checkParent проверяет, что родитель существует и находится в том же рабочем пространстве.
+// Что задача не становится подзадачей своего потомка, проверяет репозиторий в транзакции сохранения
+func (t *TodoService) checkParent(ctx context.Context, todoID, parentID uuid.UUID, workspaceID *uuid.UUID) error {
+	if parentID == todoID {
+		return errParentCycle
+	}
+
+	parent, err := t.getTodo(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, app_errors.ErrNotFound) {
+			return parentViolation("todo does not exist")
+		}
+		return fmt.Errorf("get parent:%w", err)
+	}
+
+	if !sameID(parent.WorkspaceID, workspaceID) {
+		return parentViolation("todo belongs to another workspace")
+	}
+
+	return nil
+}
+
+// checkNotBlocked не дает выполнить задачу, пока не выполнены блокирующие ее задачи
+func (t *TodoService) checkNotBlocked(ctx context.Context, todoID uuid.UUID) error {
+	open, err := t.todoRepo.CountOpenBlockers(ctx, todoID)
+	if err != nil {
+		return fmt.Errorf("count blockers:%w", err)
+	}
+
+	if open > 0 {
+		return fmt.Errorf("%d open: %w", open, app_errors.ErrTodoBlocked)
+	}
+
+	return nil
+}
+
+// flushParents сбрасывает кэш родителей, у которых изменился прогресс по подзадачам
+func (t *TodoService) flushParents(ctx context.Context, parentIDs ...*uuid.UUID) {
+	ids := make([]uuid.UUID, 0, len(parentIDs))
+	for _, id := range parentIDs {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+
+	for _, id := range uniqueIDs(ids) {
+		t.todoRedis.FlushCache(ctx, id)
+	}
+}
+
+func newTodoNode(dao *models.TodoDAO) *models.TodoNodeDTO {
+	return &models.TodoNodeDTO{
+		TodoDTO:  models.TodoDTO(*dao),
+		Subtasks: make([]*models.TodoNodeDTO, 0),
+	}
+}
+
+// sameID сравнивает необязательные идентификаторы, например родителей или пространства задач
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// errParentCycle - задача не может быть подзадачей самой себя или своих подзадач
+var errParentCycle = parentViolation("a todo cannot be a subtask of itself or of its subtasks")
+
+func parentViolation(message string) error {
+	return validator.NewValidationError(validator.FieldViolation{Field: "parent_id", Message: message})
+}
+
+func blockedByViolation(message string) error {
+	return validator.NewValidationError(validator.FieldViolation{Field: "blocked_by", Message: message})
+}
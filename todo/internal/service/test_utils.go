@@ -5,18 +5,25 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
+	"todo/config"
 	"todo/internal/service/mocks"
+	"todo/pkg/validator"
 )
 
 type Mocks struct {
-	TodoRepository *mocks.MockTodoRepository
-	RabbitProducer *mocks.MockRabbitProducer
+	TodoRepository   *mocks.MockTodoRepository
+	RabbitProducer   *mocks.MockRabbitProducer
+	TodoRedisManager *mocks.MockTodoRedisManager
+	UsersClient      *mocks.MockUsersServiceClient
 }
 
 func getMocks(ctrl *gomock.Controller) *Mocks {
 	return &Mocks{
-		TodoRepository: mocks.NewMockTodoRepository(ctrl),
-		RabbitProducer: mocks.NewMockRabbitProducer(ctrl),
+		TodoRepository:   mocks.NewMockTodoRepository(ctrl),
+		RabbitProducer:   mocks.NewMockRabbitProducer(ctrl),
+		TodoRedisManager: mocks.NewMockTodoRedisManager(ctrl),
+		UsersClient:      mocks.NewMockUsersServiceClient(ctrl),
 	}
 }
 
@@ -24,10 +31,34 @@ func buildTestService(m *Mocks) *TodoService {
 	return NewTodoService(
 		m.TodoRepository,
 		m.RabbitProducer,
+		m.TodoRedisManager,
+		m.UsersClient,
+		validator.New(&validator.Config{
+			UsernameMinLen:       3,
+			UsernameMaxLen:       32,
+			EmailMaxLen:          254,
+			PasswordMinLen:       8,
+			PasswordMaxLen:       72,
+			PasswordRequireUpper: true,
+			PasswordRequireLower: true,
+			PasswordRequireDigit: true,
+			DescriptionMaxLen:    1000,
+			CommentMaxLen:        4000,
+			LabelNameMaxLen:      50,
+			WorkspaceNameMaxLen:  100,
+		}),
+		&config.Recurrence{Interval: time.Minute, BatchSize: 100},
+		&config.Workspaces{InvitationURL: "http://localhost:3000/workspaces/invitations/accept", InvitationTTL: time.Hour},
 	)
 }
 
 func requireEqualError(t *testing.T, actualErr, expectedErr error) {
+	if _, ok := expectedErr.(*validator.ValidationError); ok {
+		var validationErr *validator.ValidationError
+		require.True(t, errors.As(actualErr, &validationErr), "expected validation error, got: %v", actualErr)
+		return
+	}
+
 	if expectedErr == nil {
 		require.NoError(t, actualErr)
 	} else {
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"net/url"
+	"strings"
+	"time"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+	"todo/pkg/validator"
+)
+
+// CreateWorkspace создает пространство, создатель становится его владельцем
+func (t *TodoService) CreateWorkspace(ctx context.Context, workspace *models.CreateWorkspaceDTO) (*models.WorkspaceDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateWorkspace")
+	defer span.Finish()
+
+	if err := t.validator.Validate(workspace); err != nil {
+		return nil, fmt.Errorf("[CreateWorkspace] validate:%w", err)
+	}
+
+	now := time.Now().UTC()
+	newWorkspace := &models.WorkspaceDAO{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(workspace.Name),
+		CreatedBy: workspace.CreatedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Role:      models.WorkspaceRoleOwner,
+	}
+
+	if err := t.todoRepo.CreateWorkspace(ctx, newWorkspace); err != nil {
+		return nil, fmt.Errorf("[CreateWorkspace] store workspace:%w", err)
+	}
+
+	return (*models.WorkspaceDTO)(newWorkspace), nil
+}
+
+func (t *TodoService) UpdateWorkspace(ctx context.Context, workspace *models.UpdateWorkspaceDTO) (*models.WorkspaceDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateWorkspace")
+	defer span.Finish()
+
+	if err := t.validator.Validate(workspace); err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspace] validate:%w", err)
+	}
+
+	existing, err := t.todoRepo.GetWorkspace(ctx, workspace.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspace] get workspace:%w", err)
+	}
+
+	existing.Name = strings.TrimSpace(workspace.Name)
+	existing.UpdatedAt = time.Now().UTC()
+
+	if err := t.todoRepo.UpdateWorkspace(ctx, existing); err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspace] update workspace:%w", err)
+	}
+
+	return (*models.WorkspaceDTO)(existing), nil
+}
+
+// DeleteWorkspace удаляет пространство вместе с задачами и сбрасывает их кэш
+func (t *TodoService) DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteWorkspace")
+	defer span.Finish()
+
+	affected, err := t.todoRepo.DeleteWorkspace(ctx, workspaceID)
+	if err != nil {
+		return fmt.Errorf("[DeleteWorkspace] delete workspace:%w", err)
+	}
+
+	for _, todoID := range affected {
+		t.todoRedis.FlushCache(ctx, todoID)
+	}
+
+	return nil
+}
+
+func (t *TodoService) ListWorkspaces(ctx context.Context, userID int) ([]*models.WorkspaceDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListWorkspaces")
+	defer span.Finish()
+
+	workspaces, err := t.todoRepo.ListWorkspaces(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListWorkspaces] get workspaces:%w", err)
+	}
+
+	res := make([]*models.WorkspaceDTO, 0, len(workspaces))
+	for i := range workspaces {
+		res = append(res, (*models.WorkspaceDTO)(&workspaces[i]))
+	}
+
+	return res, nil
+}
+
+func (t *TodoService) GetWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) (*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetWorkspaceMember")
+	defer span.Finish()
+
+	member, err := t.todoRepo.GetWorkspaceMember(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetWorkspaceMember] get member:%w", err)
+	}
+
+	return (*models.WorkspaceMemberDTO)(member), nil
+}
+
+func (t *TodoService) ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListWorkspaceMembers")
+	defer span.Finish()
+
+	// пустой список не должен скрывать несуществующее пространство
+	if _, err := t.todoRepo.GetWorkspace(ctx, workspaceID); err != nil {
+		return nil, fmt.Errorf("[ListWorkspaceMembers] get workspace:%w", err)
+	}
+
+	members, err := t.todoRepo.ListWorkspaceMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListWorkspaceMembers] get members:%w", err)
+	}
+
+	res := make([]*models.WorkspaceMemberDTO, 0, len(members))
+	for i := range members {
+		res = append(res, (*models.WorkspaceMemberDTO)(&members[i]))
+	}
+
+	return res, nil
+}
+
+// UpdateWorkspaceMember меняет роль участника. У пространства всегда остается хотя бы один владелец
+func (t *TodoService) UpdateWorkspaceMember(ctx context.Context, member *models.WorkspaceMemberDTO) (*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateWorkspaceMember")
+	defer span.Finish()
+
+	if err := t.validator.Validate(member); err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspaceMember] validate:%w", err)
+	}
+
+	err := t.todoRepo.UpdateWorkspaceMember(ctx, &models.WorkspaceMemberDAO{
+		WorkspaceID: member.WorkspaceID,
+		UserID:      member.UserID,
+		Role:        member.Role,
+	})
+	if err != nil {
+		if errors.Is(err, app_errors.ErrLastWorkspaceOwner) {
+			return nil, fmt.Errorf("[UpdateWorkspaceMember] %w", lastOwnerViolation("role"))
+		}
+		return nil, fmt.Errorf("[UpdateWorkspaceMember] update member:%w", err)
+	}
+
+	updated, err := t.todoRepo.GetWorkspaceMember(ctx, member.WorkspaceID, member.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateWorkspaceMember] get member:%w", err)
+	}
+
+	return (*models.WorkspaceMemberDTO)(updated), nil
+}
+
+// RemoveWorkspaceMember исключает участника. Последний владелец выйти из пространства не может,
+// его задачи остаются в пространстве
+func (t *TodoService) RemoveWorkspaceMember(ctx context.Context, workspaceID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveWorkspaceMember")
+	defer span.Finish()
+
+	if err := t.todoRepo.RemoveWorkspaceMember(ctx, workspaceID, userID); err != nil {
+		if errors.Is(err, app_errors.ErrLastWorkspaceOwner) {
+			return fmt.Errorf("[RemoveWorkspaceMember] %w", lastOwnerViolation("user_id"))
+		}
+		return fmt.Errorf("[RemoveWorkspaceMember] remove member:%w", err)
+	}
+
+	return nil
+}
+
+// InviteWorkspaceMember сохраняет приглашение и отправляет на адрес письмо со ссылкой для принятия.
+// Повторное приглашение на тот же адрес заменяет прежнее вместе с токеном
+func (t *TodoService) InviteWorkspaceMember(ctx context.Context, invitation *models.InviteWorkspaceMemberDTO) (*models.WorkspaceInvitationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.InviteWorkspaceMember")
+	defer span.Finish()
+
+	if err := t.validator.Validate(invitation); err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] validate:%w", err)
+	}
+
+	workspace, err := t.todoRepo.GetWorkspace(ctx, invitation.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] get workspace:%w", err)
+	}
+
+	inviter, err := t.usersClient.GetUserByID(ctx, invitation.InvitedBy)
+	if err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] get inviter:%w", err)
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] generate token:%w", err)
+	}
+
+	now := time.Now().UTC()
+	newInvitation := &models.WorkspaceInvitationDAO{
+		ID:          uuid.New(),
+		WorkspaceID: workspace.ID,
+		Email:       invitation.Email,
+		Role:        invitation.Role,
+		InvitedBy:   invitation.InvitedBy,
+		TokenHash:   hashInvitationToken(token),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(t.workspaces.InvitationTTL),
+	}
+
+	if err := t.todoRepo.CreateWorkspaceInvitation(ctx, newInvitation); err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] store invitation:%w", err)
+	}
+
+	data, err := json.Marshal(models.TodoMailItem{
+		TodoEventType: models.TodoEventTypeWorkspaceInvitation,
+		Receivers:     []string{invitation.Email},
+		Link:          t.workspaces.InvitationURL + "?token=" + url.QueryEscape(token),
+		Author:        inviter.Username,
+		WorkspaceName: workspace.Name,
+		Role:          invitation.Role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] marshal mssg:%w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	if err := t.todoRabbitProducer.Publish(data, requestID); err != nil {
+		return nil, fmt.Errorf("[InviteWorkspaceMember] publish mssg:%w", err)
+	}
+
+	return models.NewWorkspaceInvitationDTO(newInvitation), nil
+}
+
+// AcceptWorkspaceInvitation добавляет пользователя в пространство по токену из письма.
+// Принять приглашение может только владелец адреса, на который оно отправлено
+func (t *TodoService) AcceptWorkspaceInvitation(ctx context.Context, accept *models.AcceptWorkspaceInvitationDTO) (*models.WorkspaceMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AcceptWorkspaceInvitation")
+	defer span.Finish()
+
+	if err := t.validator.Validate(accept); err != nil {
+		return nil, fmt.Errorf("[AcceptWorkspaceInvitation] validate:%w", err)
+	}
+
+	invitation, err := t.todoRepo.GetWorkspaceInvitation(ctx, hashInvitationToken(accept.Token))
+	if err != nil {
+		return nil, fmt.Errorf("[AcceptWorkspaceInvitation] get invitation:%w", err)
+	}
+
+	user, err := t.usersClient.GetUserByID(ctx, accept.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[AcceptWorkspaceInvitation] get user:%w", err)
+	}
+
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, fmt.Errorf("[AcceptWorkspaceInvitation] invitation sent to another email:%w", app_errors.ErrForbidden)
+	}
+
+	err = t.todoRepo.AcceptWorkspaceInvitation(ctx, invitation.ID, &models.WorkspaceMemberDAO{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      accept.UserID,
+		Role:        invitation.Role,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[AcceptWorkspaceInvitation] add member:%w", err)
+	}
+
+	// пользователь мог уже состоять в пространстве, тогда его роль не меняется
+	member, err := t.todoRepo.GetWorkspaceMember(ctx, invitation.WorkspaceID, accept.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[AcceptWorkspaceInvitation] get member:%w", err)
+	}
+
+	return (*models.WorkspaceMemberDTO)(member), nil
+}
+
+func lastOwnerViolation(field string) error {
+	return validator.NewValidationError(validator.FieldViolation{
+		Field:   field,
+		Message: "workspace must keep at least one owner",
+	})
+}
+
+// generateInvitationToken - токен уходит только в письмо, в базе хранится его sha256
+func generateInvitationToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashInvitationToken - у токена 256 бит энтропии, медленный хэш не нужен
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- выборки задач фильтруют по автору или исполнителю и по периоду создания
+CREATE INDEX IF NOT EXISTS todo_created_by_created_at_idx ON todo (created_by, created_at);
+CREATE INDEX IF NOT EXISTS todo_assignee_created_at_idx ON todo (assignee, created_at);
+CREATE INDEX IF NOT EXISTS todo_created_at_idx ON todo (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS todo_created_at_idx;
+DROP INDEX IF EXISTS todo_assignee_created_at_idx;
+DROP INDEX IF EXISTS todo_created_by_created_at_idx;
+-- +goose StatementEnd
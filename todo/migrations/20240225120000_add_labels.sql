@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS labels (
+    id UUID PRIMARY KEY,
+    owner INT NOT NULL,
+    name TEXT NOT NULL,
+    color VARCHAR(9) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS labels_owner_name_key ON labels (owner, lower(name));
+
+CREATE TABLE IF NOT EXISTS todo_labels (
+    todo_id UUID NOT NULL REFERENCES todo (id) ON DELETE CASCADE,
+    label_id UUID NOT NULL REFERENCES labels (id) ON DELETE CASCADE,
+    PRIMARY KEY (todo_id, label_id)
+);
+CREATE INDEX IF NOT EXISTS todo_labels_label_id_idx ON todo_labels (label_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS todo_labels;
+DROP TABLE IF EXISTS labels;
+-- +goose StatementEnd
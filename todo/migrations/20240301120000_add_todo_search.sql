@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- поисковые векторы заполняет сервис с языком из SEARCH_LANGUAGE,
+-- здесь они пересчитываются для существующих записей с языком по умолчанию
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+UPDATE todo SET search_vector = to_tsvector('english', description);
+CREATE INDEX IF NOT EXISTS todo_search_vector_idx ON todo USING GIN (search_vector);
+
+ALTER TABLE todo_comments ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+UPDATE todo_comments SET search_vector = to_tsvector('english', body);
+CREATE INDEX IF NOT EXISTS todo_comments_search_vector_idx ON todo_comments USING GIN (search_vector) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS todo_comments_search_vector_idx;
+ALTER TABLE todo_comments DROP COLUMN IF EXISTS search_vector;
+DROP INDEX IF EXISTS todo_search_vector_idx;
+ALTER TABLE todo DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES todo (id) ON DELETE SET NULL;
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS done BOOLEAN NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS todo_parent_id_idx ON todo (parent_id) WHERE parent_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS todo_dependencies (
+    todo_id UUID NOT NULL REFERENCES todo (id) ON DELETE CASCADE,
+    blocked_by UUID NOT NULL REFERENCES todo (id) ON DELETE CASCADE,
+    PRIMARY KEY (todo_id, blocked_by),
+    CHECK (todo_id <> blocked_by)
+);
+CREATE INDEX IF NOT EXISTS todo_dependencies_blocked_by_idx ON todo_dependencies (blocked_by);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS todo_dependencies;
+DROP INDEX IF EXISTS todo_parent_id_idx;
+ALTER TABLE todo DROP COLUMN IF EXISTS done;
+ALTER TABLE todo DROP COLUMN IF EXISTS parent_id;
+-- +goose StatementEnd
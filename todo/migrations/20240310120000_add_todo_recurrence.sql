@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS recurrence TEXT NOT NULL DEFAULT '';
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS series_id UUID;
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS occurrence INT NOT NULL DEFAULT 0;
+-- spawned_next - следующее повторение уже создано или серия закончилась
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS spawned_next BOOLEAN NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS todo_recurrence_due_at_idx ON todo (due_at) WHERE recurrence <> '' AND NOT spawned_next;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS todo_recurrence_due_at_idx;
+ALTER TABLE todo DROP COLUMN IF EXISTS spawned_next;
+ALTER TABLE todo DROP COLUMN IF EXISTS occurrence;
+ALTER TABLE todo DROP COLUMN IF EXISTS series_id;
+ALTER TABLE todo DROP COLUMN IF EXISTS timezone;
+ALTER TABLE todo DROP COLUMN IF EXISTS recurrence;
+ALTER TABLE todo DROP COLUMN IF EXISTS due_at;
+-- +goose StatementEnd
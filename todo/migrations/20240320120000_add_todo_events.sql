@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- история изменений задач только дополняется, пустое значение означает, что поле не было задано
+CREATE TABLE IF NOT EXISTS todo_events (
+    id UUID PRIMARY KEY,
+    todo_id UUID NOT NULL REFERENCES todo (id) ON DELETE CASCADE,
+    actor INT NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT NOT NULL,
+    new_value TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS todo_events_todo_id_created_at_idx ON todo_events (todo_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS todo_events;
+-- +goose StatementEnd
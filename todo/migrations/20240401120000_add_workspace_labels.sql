@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- метка рабочего пространства общая для его участников, owner у нее - создатель
+ALTER TABLE labels ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces (id) ON DELETE CASCADE;
+
+-- имена личных меток уникальны у владельца, меток пространства - в пространстве
+DROP INDEX IF EXISTS labels_owner_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS labels_owner_name_key ON labels (owner, lower(name)) WHERE workspace_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS labels_workspace_name_key ON labels (workspace_id, lower(name)) WHERE workspace_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM labels WHERE workspace_id IS NOT NULL;
+DROP INDEX IF EXISTS labels_workspace_name_key;
+DROP INDEX IF EXISTS labels_owner_name_key;
+ALTER TABLE labels DROP COLUMN IF EXISTS workspace_id;
+CREATE UNIQUE INDEX IF NOT EXISTS labels_owner_name_key ON labels (owner, lower(name));
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- series_start - срок первой задачи серии, от него повторения берут время суток.
+-- Первая задача может быть удалена, поэтому срок хранится в каждой задаче серии
+ALTER TABLE todo ADD COLUMN IF NOT EXISTS series_start TIMESTAMP WITH TIME ZONE;
+
+UPDATE todo t
+SET series_start = COALESCE((SELECT s.due_at FROM todo s WHERE s.id = t.series_id), t.due_at)
+WHERE t.recurrence <> '' AND t.series_start IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todo DROP COLUMN IF EXISTS series_start;
+-- +goose StatementEnd
@@ -27,7 +27,39 @@ type TodoServiceClient interface {
 	UpdateToDo(ctx context.Context, in *UpdateTodoDTO, opts ...grpc.CallOption) (*TodoID, error)
 	GetToDos(ctx context.Context, in *TodoID, opts ...grpc.CallOption) (*TodoDTO, error)
 	GetToDo(ctx context.Context, in *TodoID, opts ...grpc.CallOption) (*TodoDTO, error)
-	DeleteToDo(ctx context.Context, in *TodoID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteToDo(ctx context.Context, in *DeleteTodoRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetUserToDos(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*TodoList, error)
+	AddComment(ctx context.Context, in *CreateCommentDTO, opts ...grpc.CallOption) (*CommentDTO, error)
+	EditComment(ctx context.Context, in *UpdateCommentDTO, opts ...grpc.CallOption) (*CommentDTO, error)
+	DeleteComment(ctx context.Context, in *DeleteCommentDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListComments(ctx context.Context, in *ListCommentsRequest, opts ...grpc.CallOption) (*CommentList, error)
+	ListToDos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*TodoList, error)
+	CreateLabel(ctx context.Context, in *CreateLabelDTO, opts ...grpc.CallOption) (*LabelDTO, error)
+	UpdateLabel(ctx context.Context, in *UpdateLabelDTO, opts ...grpc.CallOption) (*LabelDTO, error)
+	DeleteLabel(ctx context.Context, in *LabelRef, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetLabel(ctx context.Context, in *LabelID, opts ...grpc.CallOption) (*LabelDTO, error)
+	ListLabels(ctx context.Context, in *ListLabelsRequest, opts ...grpc.CallOption) (*LabelList, error)
+	AddTodoLabel(ctx context.Context, in *TodoLabelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveTodoLabel(ctx context.Context, in *TodoLabelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SearchTodos(ctx context.Context, in *SearchTodosRequest, opts ...grpc.CallOption) (*SearchResults, error)
+	ListTodoTree(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*TodoTree, error)
+	AddTodoDependency(ctx context.Context, in *TodoDependencyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveTodoDependency(ctx context.Context, in *TodoDependencyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CreateWorkspace(ctx context.Context, in *CreateWorkspaceDTO, opts ...grpc.CallOption) (*WorkspaceDTO, error)
+	UpdateWorkspace(ctx context.Context, in *UpdateWorkspaceDTO, opts ...grpc.CallOption) (*WorkspaceDTO, error)
+	DeleteWorkspace(ctx context.Context, in *WorkspaceRef, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListWorkspaces(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*WorkspaceList, error)
+	GetWorkspaceMember(ctx context.Context, in *WorkspaceMemberRef, opts ...grpc.CallOption) (*WorkspaceMemberDTO, error)
+	ListWorkspaceMembers(ctx context.Context, in *WorkspaceRef, opts ...grpc.CallOption) (*WorkspaceMemberList, error)
+	UpdateWorkspaceMember(ctx context.Context, in *WorkspaceMemberDTO, opts ...grpc.CallOption) (*WorkspaceMemberDTO, error)
+	RemoveWorkspaceMember(ctx context.Context, in *WorkspaceMemberRef, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	InviteWorkspaceMember(ctx context.Context, in *InviteWorkspaceMemberDTO, opts ...grpc.CallOption) (*WorkspaceInvitationDTO, error)
+	AcceptWorkspaceInvitation(ctx context.Context, in *AcceptWorkspaceInvitationDTO, opts ...grpc.CallOption) (*WorkspaceMemberDTO, error)
+	ListTodoHistory(ctx context.Context, in *ListTodoHistoryRequest, opts ...grpc.CallOption) (*TodoEventList, error)
+	BulkUpdateTodos(ctx context.Context, in *BulkUpdateTodosRequest, opts ...grpc.CallOption) (*BulkUpdateTodosResult, error)
+	// ExportTodos отдает все задачи, подходящие под фильтр, по одной. limit и offset не учитываются
+	ExportTodos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (TodoService_ExportTodosClient, error)
+	ImportTodos(ctx context.Context, in *ImportTodosRequest, opts ...grpc.CallOption) (*ImportTodosResult, error)
 }
 
 type todoServiceClient struct {
@@ -74,7 +106,7 @@ func (c *todoServiceClient) GetToDo(ctx context.Context, in *TodoID, opts ...grp
 	return out, nil
 }
 
-func (c *todoServiceClient) DeleteToDo(ctx context.Context, in *TodoID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *todoServiceClient) DeleteToDo(ctx context.Context, in *DeleteTodoRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/todoservice.TodoService/DeleteToDo", in, out, opts...)
 	if err != nil {
@@ -83,6 +115,308 @@ func (c *todoServiceClient) DeleteToDo(ctx context.Context, in *TodoID, opts ...
 	return out, nil
 }
 
+func (c *todoServiceClient) GetUserToDos(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*TodoList, error) {
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/GetUserToDos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) AddComment(ctx context.Context, in *CreateCommentDTO, opts ...grpc.CallOption) (*CommentDTO, error) {
+	out := new(CommentDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/AddComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) EditComment(ctx context.Context, in *UpdateCommentDTO, opts ...grpc.CallOption) (*CommentDTO, error) {
+	out := new(CommentDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/EditComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteComment(ctx context.Context, in *DeleteCommentDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/DeleteComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListComments(ctx context.Context, in *ListCommentsRequest, opts ...grpc.CallOption) (*CommentList, error) {
+	out := new(CommentList)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/ListComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListToDos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*TodoList, error) {
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/ListToDos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateLabel(ctx context.Context, in *CreateLabelDTO, opts ...grpc.CallOption) (*LabelDTO, error) {
+	out := new(LabelDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/CreateLabel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UpdateLabel(ctx context.Context, in *UpdateLabelDTO, opts ...grpc.CallOption) (*LabelDTO, error) {
+	out := new(LabelDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/UpdateLabel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteLabel(ctx context.Context, in *LabelRef, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/DeleteLabel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetLabel(ctx context.Context, in *LabelID, opts ...grpc.CallOption) (*LabelDTO, error) {
+	out := new(LabelDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/GetLabel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListLabels(ctx context.Context, in *ListLabelsRequest, opts ...grpc.CallOption) (*LabelList, error) {
+	out := new(LabelList)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/ListLabels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) AddTodoLabel(ctx context.Context, in *TodoLabelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/AddTodoLabel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RemoveTodoLabel(ctx context.Context, in *TodoLabelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/RemoveTodoLabel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) SearchTodos(ctx context.Context, in *SearchTodosRequest, opts ...grpc.CallOption) (*SearchResults, error) {
+	out := new(SearchResults)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/SearchTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTodoTree(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*TodoTree, error) {
+	out := new(TodoTree)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/ListTodoTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) AddTodoDependency(ctx context.Context, in *TodoDependencyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/AddTodoDependency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RemoveTodoDependency(ctx context.Context, in *TodoDependencyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/RemoveTodoDependency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateWorkspace(ctx context.Context, in *CreateWorkspaceDTO, opts ...grpc.CallOption) (*WorkspaceDTO, error) {
+	out := new(WorkspaceDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/CreateWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UpdateWorkspace(ctx context.Context, in *UpdateWorkspaceDTO, opts ...grpc.CallOption) (*WorkspaceDTO, error) {
+	out := new(WorkspaceDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/UpdateWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteWorkspace(ctx context.Context, in *WorkspaceRef, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/DeleteWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListWorkspaces(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*WorkspaceList, error) {
+	out := new(WorkspaceList)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/ListWorkspaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetWorkspaceMember(ctx context.Context, in *WorkspaceMemberRef, opts ...grpc.CallOption) (*WorkspaceMemberDTO, error) {
+	out := new(WorkspaceMemberDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/GetWorkspaceMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListWorkspaceMembers(ctx context.Context, in *WorkspaceRef, opts ...grpc.CallOption) (*WorkspaceMemberList, error) {
+	out := new(WorkspaceMemberList)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/ListWorkspaceMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UpdateWorkspaceMember(ctx context.Context, in *WorkspaceMemberDTO, opts ...grpc.CallOption) (*WorkspaceMemberDTO, error) {
+	out := new(WorkspaceMemberDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/UpdateWorkspaceMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RemoveWorkspaceMember(ctx context.Context, in *WorkspaceMemberRef, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/RemoveWorkspaceMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) InviteWorkspaceMember(ctx context.Context, in *InviteWorkspaceMemberDTO, opts ...grpc.CallOption) (*WorkspaceInvitationDTO, error) {
+	out := new(WorkspaceInvitationDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/InviteWorkspaceMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) AcceptWorkspaceInvitation(ctx context.Context, in *AcceptWorkspaceInvitationDTO, opts ...grpc.CallOption) (*WorkspaceMemberDTO, error) {
+	out := new(WorkspaceMemberDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/AcceptWorkspaceInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTodoHistory(ctx context.Context, in *ListTodoHistoryRequest, opts ...grpc.CallOption) (*TodoEventList, error) {
+	out := new(TodoEventList)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/ListTodoHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) BulkUpdateTodos(ctx context.Context, in *BulkUpdateTodosRequest, opts ...grpc.CallOption) (*BulkUpdateTodosResult, error) {
+	out := new(BulkUpdateTodosResult)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/BulkUpdateTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ExportTodos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (TodoService_ExportTodosClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TodoService_ServiceDesc.Streams[0], "/todoservice.TodoService/ExportTodos", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &todoServiceExportTodosClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TodoService_ExportTodosClient interface {
+	Recv() (*TodoDTO, error)
+	grpc.ClientStream
+}
+
+type todoServiceExportTodosClient struct {
+	grpc.ClientStream
+}
+
+func (x *todoServiceExportTodosClient) Recv() (*TodoDTO, error) {
+	m := new(TodoDTO)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *todoServiceClient) ImportTodos(ctx context.Context, in *ImportTodosRequest, opts ...grpc.CallOption) (*ImportTodosResult, error) {
+	out := new(ImportTodosResult)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/ImportTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TodoServiceServer is the server API for TodoService service.
 // All implementations must embed UnimplementedTodoServiceServer
 // for forward compatibility
@@ -91,7 +425,39 @@ type TodoServiceServer interface {
 	UpdateToDo(context.Context, *UpdateTodoDTO) (*TodoID, error)
 	GetToDos(context.Context, *TodoID) (*TodoDTO, error)
 	GetToDo(context.Context, *TodoID) (*TodoDTO, error)
-	DeleteToDo(context.Context, *TodoID) (*emptypb.Empty, error)
+	DeleteToDo(context.Context, *DeleteTodoRequest) (*emptypb.Empty, error)
+	GetUserToDos(context.Context, *UserID) (*TodoList, error)
+	AddComment(context.Context, *CreateCommentDTO) (*CommentDTO, error)
+	EditComment(context.Context, *UpdateCommentDTO) (*CommentDTO, error)
+	DeleteComment(context.Context, *DeleteCommentDTO) (*emptypb.Empty, error)
+	ListComments(context.Context, *ListCommentsRequest) (*CommentList, error)
+	ListToDos(context.Context, *ListTodosRequest) (*TodoList, error)
+	CreateLabel(context.Context, *CreateLabelDTO) (*LabelDTO, error)
+	UpdateLabel(context.Context, *UpdateLabelDTO) (*LabelDTO, error)
+	DeleteLabel(context.Context, *LabelRef) (*emptypb.Empty, error)
+	GetLabel(context.Context, *LabelID) (*LabelDTO, error)
+	ListLabels(context.Context, *ListLabelsRequest) (*LabelList, error)
+	AddTodoLabel(context.Context, *TodoLabelRequest) (*emptypb.Empty, error)
+	RemoveTodoLabel(context.Context, *TodoLabelRequest) (*emptypb.Empty, error)
+	SearchTodos(context.Context, *SearchTodosRequest) (*SearchResults, error)
+	ListTodoTree(context.Context, *ListTodosRequest) (*TodoTree, error)
+	AddTodoDependency(context.Context, *TodoDependencyRequest) (*emptypb.Empty, error)
+	RemoveTodoDependency(context.Context, *TodoDependencyRequest) (*emptypb.Empty, error)
+	CreateWorkspace(context.Context, *CreateWorkspaceDTO) (*WorkspaceDTO, error)
+	UpdateWorkspace(context.Context, *UpdateWorkspaceDTO) (*WorkspaceDTO, error)
+	DeleteWorkspace(context.Context, *WorkspaceRef) (*emptypb.Empty, error)
+	ListWorkspaces(context.Context, *UserID) (*WorkspaceList, error)
+	GetWorkspaceMember(context.Context, *WorkspaceMemberRef) (*WorkspaceMemberDTO, error)
+	ListWorkspaceMembers(context.Context, *WorkspaceRef) (*WorkspaceMemberList, error)
+	UpdateWorkspaceMember(context.Context, *WorkspaceMemberDTO) (*WorkspaceMemberDTO, error)
+	RemoveWorkspaceMember(context.Context, *WorkspaceMemberRef) (*emptypb.Empty, error)
+	InviteWorkspaceMember(context.Context, *InviteWorkspaceMemberDTO) (*WorkspaceInvitationDTO, error)
+	AcceptWorkspaceInvitation(context.Context, *AcceptWorkspaceInvitationDTO) (*WorkspaceMemberDTO, error)
+	ListTodoHistory(context.Context, *ListTodoHistoryRequest) (*TodoEventList, error)
+	BulkUpdateTodos(context.Context, *BulkUpdateTodosRequest) (*BulkUpdateTodosResult, error)
+	// ExportTodos отдает все задачи, подходящие под фильтр, по одной. limit и offset не учитываются
+	ExportTodos(*ListTodosRequest, TodoService_ExportTodosServer) error
+	ImportTodos(context.Context, *ImportTodosRequest) (*ImportTodosResult, error)
 	mustEmbedUnimplementedTodoServiceServer()
 }
 
@@ -111,9 +477,102 @@ func (UnimplementedTodoServiceServer) GetToDos(context.Context, *TodoID) (*TodoD
 func (UnimplementedTodoServiceServer) GetToDo(context.Context, *TodoID) (*TodoDTO, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetToDo not implemented")
 }
-func (UnimplementedTodoServiceServer) DeleteToDo(context.Context, *TodoID) (*emptypb.Empty, error) {
+func (UnimplementedTodoServiceServer) DeleteToDo(context.Context, *DeleteTodoRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteToDo not implemented")
 }
+func (UnimplementedTodoServiceServer) GetUserToDos(context.Context, *UserID) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserToDos not implemented")
+}
+func (UnimplementedTodoServiceServer) AddComment(context.Context, *CreateCommentDTO) (*CommentDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddComment not implemented")
+}
+func (UnimplementedTodoServiceServer) EditComment(context.Context, *UpdateCommentDTO) (*CommentDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditComment not implemented")
+}
+func (UnimplementedTodoServiceServer) DeleteComment(context.Context, *DeleteCommentDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteComment not implemented")
+}
+func (UnimplementedTodoServiceServer) ListComments(context.Context, *ListCommentsRequest) (*CommentList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComments not implemented")
+}
+func (UnimplementedTodoServiceServer) ListToDos(context.Context, *ListTodosRequest) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListToDos not implemented")
+}
+func (UnimplementedTodoServiceServer) CreateLabel(context.Context, *CreateLabelDTO) (*LabelDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLabel not implemented")
+}
+func (UnimplementedTodoServiceServer) UpdateLabel(context.Context, *UpdateLabelDTO) (*LabelDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLabel not implemented")
+}
+func (UnimplementedTodoServiceServer) DeleteLabel(context.Context, *LabelRef) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLabel not implemented")
+}
+func (UnimplementedTodoServiceServer) GetLabel(context.Context, *LabelID) (*LabelDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLabel not implemented")
+}
+func (UnimplementedTodoServiceServer) ListLabels(context.Context, *ListLabelsRequest) (*LabelList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLabels not implemented")
+}
+func (UnimplementedTodoServiceServer) AddTodoLabel(context.Context, *TodoLabelRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTodoLabel not implemented")
+}
+func (UnimplementedTodoServiceServer) RemoveTodoLabel(context.Context, *TodoLabelRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveTodoLabel not implemented")
+}
+func (UnimplementedTodoServiceServer) SearchTodos(context.Context, *SearchTodosRequest) (*SearchResults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTodos not implemented")
+}
+func (UnimplementedTodoServiceServer) ListTodoTree(context.Context, *ListTodosRequest) (*TodoTree, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoTree not implemented")
+}
+func (UnimplementedTodoServiceServer) AddTodoDependency(context.Context, *TodoDependencyRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTodoDependency not implemented")
+}
+func (UnimplementedTodoServiceServer) RemoveTodoDependency(context.Context, *TodoDependencyRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveTodoDependency not implemented")
+}
+func (UnimplementedTodoServiceServer) CreateWorkspace(context.Context, *CreateWorkspaceDTO) (*WorkspaceDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorkspace not implemented")
+}
+func (UnimplementedTodoServiceServer) UpdateWorkspace(context.Context, *UpdateWorkspaceDTO) (*WorkspaceDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWorkspace not implemented")
+}
+func (UnimplementedTodoServiceServer) DeleteWorkspace(context.Context, *WorkspaceRef) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWorkspace not implemented")
+}
+func (UnimplementedTodoServiceServer) ListWorkspaces(context.Context, *UserID) (*WorkspaceList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkspaces not implemented")
+}
+func (UnimplementedTodoServiceServer) GetWorkspaceMember(context.Context, *WorkspaceMemberRef) (*WorkspaceMemberDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaceMember not implemented")
+}
+func (UnimplementedTodoServiceServer) ListWorkspaceMembers(context.Context, *WorkspaceRef) (*WorkspaceMemberList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkspaceMembers not implemented")
+}
+func (UnimplementedTodoServiceServer) UpdateWorkspaceMember(context.Context, *WorkspaceMemberDTO) (*WorkspaceMemberDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWorkspaceMember not implemented")
+}
+func (UnimplementedTodoServiceServer) RemoveWorkspaceMember(context.Context, *WorkspaceMemberRef) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWorkspaceMember not implemented")
+}
+func (UnimplementedTodoServiceServer) InviteWorkspaceMember(context.Context, *InviteWorkspaceMemberDTO) (*WorkspaceInvitationDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteWorkspaceMember not implemented")
+}
+func (UnimplementedTodoServiceServer) AcceptWorkspaceInvitation(context.Context, *AcceptWorkspaceInvitationDTO) (*WorkspaceMemberDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptWorkspaceInvitation not implemented")
+}
+func (UnimplementedTodoServiceServer) ListTodoHistory(context.Context, *ListTodoHistoryRequest) (*TodoEventList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoHistory not implemented")
+}
+func (UnimplementedTodoServiceServer) BulkUpdateTodos(context.Context, *BulkUpdateTodosRequest) (*BulkUpdateTodosResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkUpdateTodos not implemented")
+}
+func (UnimplementedTodoServiceServer) ExportTodos(*ListTodosRequest, TodoService_ExportTodosServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportTodos not implemented")
+}
+func (UnimplementedTodoServiceServer) ImportTodos(context.Context, *ImportTodosRequest) (*ImportTodosResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportTodos not implemented")
+}
 func (UnimplementedTodoServiceServer) mustEmbedUnimplementedTodoServiceServer() {}
 
 // UnsafeTodoServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -200,7 +659,7 @@ func _TodoService_GetToDo_Handler(srv interface{}, ctx context.Context, dec func
 }
 
 func _TodoService_DeleteToDo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TodoID)
+	in := new(DeleteTodoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -212,30 +671,591 @@ func _TodoService_DeleteToDo_Handler(srv interface{}, ctx context.Context, dec f
 		FullMethod: "/todoservice.TodoService/DeleteToDo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TodoServiceServer).DeleteToDo(ctx, req.(*TodoID))
+		return srv.(TodoServiceServer).DeleteToDo(ctx, req.(*DeleteTodoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// TodoService_ServiceDesc is the grpc.ServiceDesc for TodoService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var TodoService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "todoservice.TodoService",
-	HandlerType: (*TodoServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateToDo",
-			Handler:    _TodoService_CreateToDo_Handler,
-		},
-		{
-			MethodName: "UpdateToDo",
-			Handler:    _TodoService_UpdateToDo_Handler,
-		},
-		{
-			MethodName: "GetToDos",
-			Handler:    _TodoService_GetToDos_Handler,
-		},
+func _TodoService_GetUserToDos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetUserToDos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/GetUserToDos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetUserToDos(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_AddComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCommentDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).AddComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/AddComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).AddComment(ctx, req.(*CreateCommentDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_EditComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCommentDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).EditComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/EditComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).EditComment(ctx, req.(*UpdateCommentDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCommentDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/DeleteComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteComment(ctx, req.(*DeleteCommentDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/ListComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListComments(ctx, req.(*ListCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListToDos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListToDos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/ListToDos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListToDos(ctx, req.(*ListTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLabelDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/CreateLabel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateLabel(ctx, req.(*CreateLabelDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLabelDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/UpdateLabel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateLabel(ctx, req.(*UpdateLabelDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/DeleteLabel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteLabel(ctx, req.(*LabelRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/GetLabel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetLabel(ctx, req.(*LabelID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/ListLabels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListLabels(ctx, req.(*ListLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_AddTodoLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).AddTodoLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/AddTodoLabel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).AddTodoLabel(ctx, req.(*TodoLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RemoveTodoLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RemoveTodoLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/RemoveTodoLabel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RemoveTodoLabel(ctx, req.(*TodoLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_SearchTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).SearchTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/SearchTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).SearchTodos(ctx, req.(*SearchTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTodoTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTodoTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/ListTodoTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTodoTree(ctx, req.(*ListTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_AddTodoDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoDependencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).AddTodoDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/AddTodoDependency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).AddTodoDependency(ctx, req.(*TodoDependencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RemoveTodoDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoDependencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RemoveTodoDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/RemoveTodoDependency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RemoveTodoDependency(ctx, req.(*TodoDependencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWorkspaceDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/CreateWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateWorkspace(ctx, req.(*CreateWorkspaceDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateWorkspaceDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/UpdateWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateWorkspace(ctx, req.(*UpdateWorkspaceDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkspaceRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/DeleteWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteWorkspace(ctx, req.(*WorkspaceRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListWorkspaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/ListWorkspaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListWorkspaces(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetWorkspaceMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkspaceMemberRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetWorkspaceMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/GetWorkspaceMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetWorkspaceMember(ctx, req.(*WorkspaceMemberRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListWorkspaceMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkspaceRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListWorkspaceMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/ListWorkspaceMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListWorkspaceMembers(ctx, req.(*WorkspaceRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateWorkspaceMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkspaceMemberDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateWorkspaceMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/UpdateWorkspaceMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateWorkspaceMember(ctx, req.(*WorkspaceMemberDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RemoveWorkspaceMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkspaceMemberRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RemoveWorkspaceMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/RemoveWorkspaceMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RemoveWorkspaceMember(ctx, req.(*WorkspaceMemberRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_InviteWorkspaceMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteWorkspaceMemberDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).InviteWorkspaceMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/InviteWorkspaceMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).InviteWorkspaceMember(ctx, req.(*InviteWorkspaceMemberDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_AcceptWorkspaceInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptWorkspaceInvitationDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).AcceptWorkspaceInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/AcceptWorkspaceInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).AcceptWorkspaceInvitation(ctx, req.(*AcceptWorkspaceInvitationDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTodoHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTodoHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/ListTodoHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTodoHistory(ctx, req.(*ListTodoHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_BulkUpdateTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkUpdateTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).BulkUpdateTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/BulkUpdateTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).BulkUpdateTodos(ctx, req.(*BulkUpdateTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ExportTodos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListTodosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoServiceServer).ExportTodos(m, &todoServiceExportTodosServer{stream})
+}
+
+type TodoService_ExportTodosServer interface {
+	Send(*TodoDTO) error
+	grpc.ServerStream
+}
+
+type todoServiceExportTodosServer struct {
+	grpc.ServerStream
+}
+
+func (x *todoServiceExportTodosServer) Send(m *TodoDTO) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TodoService_ImportTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ImportTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/ImportTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ImportTodos(ctx, req.(*ImportTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TodoService_ServiceDesc is the grpc.ServiceDesc for TodoService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TodoService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todoservice.TodoService",
+	HandlerType: (*TodoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateToDo",
+			Handler:    _TodoService_CreateToDo_Handler,
+		},
+		{
+			MethodName: "UpdateToDo",
+			Handler:    _TodoService_UpdateToDo_Handler,
+		},
+		{
+			MethodName: "GetToDos",
+			Handler:    _TodoService_GetToDos_Handler,
+		},
 		{
 			MethodName: "GetToDo",
 			Handler:    _TodoService_GetToDo_Handler,
@@ -244,7 +1264,133 @@ var TodoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteToDo",
 			Handler:    _TodoService_DeleteToDo_Handler,
 		},
+		{
+			MethodName: "GetUserToDos",
+			Handler:    _TodoService_GetUserToDos_Handler,
+		},
+		{
+			MethodName: "AddComment",
+			Handler:    _TodoService_AddComment_Handler,
+		},
+		{
+			MethodName: "EditComment",
+			Handler:    _TodoService_EditComment_Handler,
+		},
+		{
+			MethodName: "DeleteComment",
+			Handler:    _TodoService_DeleteComment_Handler,
+		},
+		{
+			MethodName: "ListComments",
+			Handler:    _TodoService_ListComments_Handler,
+		},
+		{
+			MethodName: "ListToDos",
+			Handler:    _TodoService_ListToDos_Handler,
+		},
+		{
+			MethodName: "CreateLabel",
+			Handler:    _TodoService_CreateLabel_Handler,
+		},
+		{
+			MethodName: "UpdateLabel",
+			Handler:    _TodoService_UpdateLabel_Handler,
+		},
+		{
+			MethodName: "DeleteLabel",
+			Handler:    _TodoService_DeleteLabel_Handler,
+		},
+		{
+			MethodName: "GetLabel",
+			Handler:    _TodoService_GetLabel_Handler,
+		},
+		{
+			MethodName: "ListLabels",
+			Handler:    _TodoService_ListLabels_Handler,
+		},
+		{
+			MethodName: "AddTodoLabel",
+			Handler:    _TodoService_AddTodoLabel_Handler,
+		},
+		{
+			MethodName: "RemoveTodoLabel",
+			Handler:    _TodoService_RemoveTodoLabel_Handler,
+		},
+		{
+			MethodName: "SearchTodos",
+			Handler:    _TodoService_SearchTodos_Handler,
+		},
+		{
+			MethodName: "ListTodoTree",
+			Handler:    _TodoService_ListTodoTree_Handler,
+		},
+		{
+			MethodName: "AddTodoDependency",
+			Handler:    _TodoService_AddTodoDependency_Handler,
+		},
+		{
+			MethodName: "RemoveTodoDependency",
+			Handler:    _TodoService_RemoveTodoDependency_Handler,
+		},
+		{
+			MethodName: "CreateWorkspace",
+			Handler:    _TodoService_CreateWorkspace_Handler,
+		},
+		{
+			MethodName: "UpdateWorkspace",
+			Handler:    _TodoService_UpdateWorkspace_Handler,
+		},
+		{
+			MethodName: "DeleteWorkspace",
+			Handler:    _TodoService_DeleteWorkspace_Handler,
+		},
+		{
+			MethodName: "ListWorkspaces",
+			Handler:    _TodoService_ListWorkspaces_Handler,
+		},
+		{
+			MethodName: "GetWorkspaceMember",
+			Handler:    _TodoService_GetWorkspaceMember_Handler,
+		},
+		{
+			MethodName: "ListWorkspaceMembers",
+			Handler:    _TodoService_ListWorkspaceMembers_Handler,
+		},
+		{
+			MethodName: "UpdateWorkspaceMember",
+			Handler:    _TodoService_UpdateWorkspaceMember_Handler,
+		},
+		{
+			MethodName: "RemoveWorkspaceMember",
+			Handler:    _TodoService_RemoveWorkspaceMember_Handler,
+		},
+		{
+			MethodName: "InviteWorkspaceMember",
+			Handler:    _TodoService_InviteWorkspaceMember_Handler,
+		},
+		{
+			MethodName: "AcceptWorkspaceInvitation",
+			Handler:    _TodoService_AcceptWorkspaceInvitation_Handler,
+		},
+		{
+			MethodName: "ListTodoHistory",
+			Handler:    _TodoService_ListTodoHistory_Handler,
+		},
+		{
+			MethodName: "BulkUpdateTodos",
+			Handler:    _TodoService_BulkUpdateTodos_Handler,
+		},
+		{
+			MethodName: "ImportTodos",
+			Handler:    _TodoService_ImportTodos_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportTodos",
+			Handler:       _TodoService_ExportTodos_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "todo.proto",
 }
@@ -0,0 +1,978 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: users.proto
+
+package users
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UserServiceClient interface {
+	// Метод RPC для регистрации пользователя. Принимает CreateUserDTO и возвращает UserID
+	RegisterUser(ctx context.Context, in *CreateUserDTO, opts ...grpc.CallOption) (*UserID, error)
+	// Метод RPC для обновления данных пользователя. Принимает и возвращает UserDTO
+	UpdateUser(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для обновления пароля пользователя. Принимает UpdateUserPasswordDTO и возвращает пустой ответ
+	UpdatePassword(ctx context.Context, in *UpdateUserPasswordDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для удаления пользователя. Планирует обезличивание и возвращает UserDTO со сроком удаления
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для деактивации пользователя. Принимает UserID и возвращает пустой ответ
+	DeactivateUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для получения данных пользователя по ID. Принимает UserID и возвращает UserDTO
+	GetUserByID(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для получения данных пользователя по имени или email. Принимает UserDTO и возвращает UserDTO
+	GetUserByUsernameOrEmail(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для входа пользователя. Принимает UserLoginDTO и возвращает UserDTO
+	Login(ctx context.Context, in *UserLoginDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для получения списка пользователей. Принимает ListUsersRequest и возвращает UsersList
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*UsersList, error)
+	// Метод RPC для смены роли пользователя. Принимает UpdateUserRoleDTO и возвращает пустой ответ
+	UpdateUserRole(ctx context.Context, in *UpdateUserRoleDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для начала подключения TOTP. Принимает UserID и возвращает TOTPEnrollment
+	EnrollTOTP(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*TOTPEnrollment, error)
+	// Метод RPC для подтверждения TOTP первым кодом. Принимает MFACodeDTO и возвращает RecoveryCodes
+	ConfirmTOTP(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*RecoveryCodes, error)
+	// Метод RPC для отключения TOTP. Принимает MFACodeDTO и возвращает пустой ответ
+	DisableTOTP(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для проверки второго фактора при входе. Принимает MFACodeDTO и возвращает UserDTO
+	VerifyMFA(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для уведомления пользователя о блокировке входа. Принимает AccountLockedDTO и возвращает пустой ответ
+	ReportAccountLocked(ctx context.Context, in *AccountLockedDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для поиска пользователя по внешней учетной записи. Принимает ExternalIdentityDTO и возвращает UserDTO
+	GetUserByExternalIdentity(ctx context.Context, in *ExternalIdentityDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для привязки внешней учетной записи к пользователю или создания нового пользователя. Принимает ExternalIdentityDTO и возвращает UserDTO
+	LinkExternalIdentity(ctx context.Context, in *ExternalIdentityDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для создания API ключа. Принимает CreateAPIKeyDTO и возвращает APIKeyDTO с ключом
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyDTO, opts ...grpc.CallOption) (*APIKeyDTO, error)
+	// Метод RPC для получения API ключей пользователя. Принимает UserID и возвращает APIKeysList
+	ListAPIKeys(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*APIKeysList, error)
+	// Метод RPC для отзыва API ключа. Принимает APIKeyID и возвращает пустой ответ
+	RevokeAPIKey(ctx context.Context, in *APIKeyID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для проверки API ключа. Принимает APIKeySecret и возвращает APIKeyPrincipal
+	AuthenticateAPIKey(ctx context.Context, in *APIKeySecret, opts ...grpc.CallOption) (*APIKeyPrincipal, error)
+	// Метод RPC для смены аватара. Принимает AvatarDTO с новым ключом и возвращает AvatarDTO с прежним ключом
+	UpdateAvatar(ctx context.Context, in *AvatarDTO, opts ...grpc.CallOption) (*AvatarDTO, error)
+	RequestEmailChange(ctx context.Context, in *EmailChangeRequestDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ConfirmEmailChange(ctx context.Context, in *EmailChangeTokenDTO, opts ...grpc.CallOption) (*UserDTO, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) RegisterUser(ctx context.Context, in *CreateUserDTO, opts ...grpc.CallOption) (*UserID, error) {
+	out := new(UserID)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RegisterUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateUser(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UpdateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdatePassword(ctx context.Context, in *UpdateUserPasswordDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UpdatePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/DeleteUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeactivateUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/DeactivateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserByID(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUserByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserByUsernameOrEmail(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUserByUsernameOrEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) Login(ctx context.Context, in *UserLoginDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/Login", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*UsersList, error) {
+	out := new(UsersList)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateUserRole(ctx context.Context, in *UpdateUserRoleDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UpdateUserRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) EnrollTOTP(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*TOTPEnrollment, error) {
+	out := new(TOTPEnrollment)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/EnrollTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmTOTP(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*RecoveryCodes, error) {
+	out := new(RecoveryCodes)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ConfirmTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DisableTOTP(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/DisableTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) VerifyMFA(ctx context.Context, in *MFACodeDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/VerifyMFA", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ReportAccountLocked(ctx context.Context, in *AccountLockedDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ReportAccountLocked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserByExternalIdentity(ctx context.Context, in *ExternalIdentityDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUserByExternalIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) LinkExternalIdentity(ctx context.Context, in *ExternalIdentityDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/LinkExternalIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyDTO, opts ...grpc.CallOption) (*APIKeyDTO, error) {
+	out := new(APIKeyDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/CreateAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListAPIKeys(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*APIKeysList, error) {
+	out := new(APIKeysList)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ListAPIKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeAPIKey(ctx context.Context, in *APIKeyID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RevokeAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) AuthenticateAPIKey(ctx context.Context, in *APIKeySecret, opts ...grpc.CallOption) (*APIKeyPrincipal, error) {
+	out := new(APIKeyPrincipal)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/AuthenticateAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateAvatar(ctx context.Context, in *AvatarDTO, opts ...grpc.CallOption) (*AvatarDTO, error) {
+	out := new(AvatarDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UpdateAvatar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestEmailChange(ctx context.Context, in *EmailChangeRequestDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RequestEmailChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmEmailChange(ctx context.Context, in *EmailChangeTokenDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ConfirmEmailChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+// All implementations must embed UnimplementedUserServiceServer
+// for forward compatibility
+type UserServiceServer interface {
+	// Метод RPC для регистрации пользователя. Принимает CreateUserDTO и возвращает UserID
+	RegisterUser(context.Context, *CreateUserDTO) (*UserID, error)
+	// Метод RPC для обновления данных пользователя. Принимает и возвращает UserDTO
+	UpdateUser(context.Context, *UserDTO) (*UserDTO, error)
+	// Метод RPC для обновления пароля пользователя. Принимает UpdateUserPasswordDTO и возвращает пустой ответ
+	UpdatePassword(context.Context, *UpdateUserPasswordDTO) (*emptypb.Empty, error)
+	// Метод RPC для удаления пользователя. Планирует обезличивание и возвращает UserDTO со сроком удаления
+	DeleteUser(context.Context, *DeleteUserRequest) (*UserDTO, error)
+	// Метод RPC для деактивации пользователя. Принимает UserID и возвращает пустой ответ
+	DeactivateUser(context.Context, *UserID) (*emptypb.Empty, error)
+	// Метод RPC для получения данных пользователя по ID. Принимает UserID и возвращает UserDTO
+	GetUserByID(context.Context, *UserID) (*UserDTO, error)
+	// Метод RPC для получения данных пользователя по имени или email. Принимает UserDTO и возвращает UserDTO
+	GetUserByUsernameOrEmail(context.Context, *UserDTO) (*UserDTO, error)
+	// Метод RPC для входа пользователя. Принимает UserLoginDTO и возвращает UserDTO
+	Login(context.Context, *UserLoginDTO) (*UserDTO, error)
+	// Метод RPC для получения списка пользователей. Принимает ListUsersRequest и возвращает UsersList
+	ListUsers(context.Context, *ListUsersRequest) (*UsersList, error)
+	// Метод RPC для смены роли пользователя. Принимает UpdateUserRoleDTO и возвращает пустой ответ
+	UpdateUserRole(context.Context, *UpdateUserRoleDTO) (*emptypb.Empty, error)
+	// Метод RPC для начала подключения TOTP. Принимает UserID и возвращает TOTPEnrollment
+	EnrollTOTP(context.Context, *UserID) (*TOTPEnrollment, error)
+	// Метод RPC для подтверждения TOTP первым кодом. Принимает MFACodeDTO и возвращает RecoveryCodes
+	ConfirmTOTP(context.Context, *MFACodeDTO) (*RecoveryCodes, error)
+	// Метод RPC для отключения TOTP. Принимает MFACodeDTO и возвращает пустой ответ
+	DisableTOTP(context.Context, *MFACodeDTO) (*emptypb.Empty, error)
+	// Метод RPC для проверки второго фактора при входе. Принимает MFACodeDTO и возвращает UserDTO
+	VerifyMFA(context.Context, *MFACodeDTO) (*UserDTO, error)
+	// Метод RPC для уведомления пользователя о блокировке входа. Принимает AccountLockedDTO и возвращает пустой ответ
+	ReportAccountLocked(context.Context, *AccountLockedDTO) (*emptypb.Empty, error)
+	// Метод RPC для поиска пользователя по внешней учетной записи. Принимает ExternalIdentityDTO и возвращает UserDTO
+	GetUserByExternalIdentity(context.Context, *ExternalIdentityDTO) (*UserDTO, error)
+	// Метод RPC для привязки внешней учетной записи к пользователю или создания нового пользователя. Принимает ExternalIdentityDTO и возвращает UserDTO
+	LinkExternalIdentity(context.Context, *ExternalIdentityDTO) (*UserDTO, error)
+	// Метод RPC для создания API ключа. Принимает CreateAPIKeyDTO и возвращает APIKeyDTO с ключом
+	CreateAPIKey(context.Context, *CreateAPIKeyDTO) (*APIKeyDTO, error)
+	// Метод RPC для получения API ключей пользователя. Принимает UserID и возвращает APIKeysList
+	ListAPIKeys(context.Context, *UserID) (*APIKeysList, error)
+	// Метод RPC для отзыва API ключа. Принимает APIKeyID и возвращает пустой ответ
+	RevokeAPIKey(context.Context, *APIKeyID) (*emptypb.Empty, error)
+	// Метод RPC для проверки API ключа. Принимает APIKeySecret и возвращает APIKeyPrincipal
+	AuthenticateAPIKey(context.Context, *APIKeySecret) (*APIKeyPrincipal, error)
+	// Метод RPC для смены аватара. Принимает AvatarDTO с новым ключом и возвращает AvatarDTO с прежним ключом
+	UpdateAvatar(context.Context, *AvatarDTO) (*AvatarDTO, error)
+	RequestEmailChange(context.Context, *EmailChangeRequestDTO) (*emptypb.Empty, error)
+	ConfirmEmailChange(context.Context, *EmailChangeTokenDTO) (*UserDTO, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+// UnimplementedUserServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedUserServiceServer struct {
+}
+
+func (UnimplementedUserServiceServer) RegisterUser(context.Context, *CreateUserDTO) (*UserID, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterUser not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateUser(context.Context, *UserDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedUserServiceServer) UpdatePassword(context.Context, *UpdateUserPasswordDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePassword not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedUserServiceServer) DeactivateUser(context.Context, *UserID) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateUser not implemented")
+}
+func (UnimplementedUserServiceServer) GetUserByID(context.Context, *UserID) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByID not implemented")
+}
+func (UnimplementedUserServiceServer) GetUserByUsernameOrEmail(context.Context, *UserDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByUsernameOrEmail not implemented")
+}
+func (UnimplementedUserServiceServer) Login(context.Context, *UserLoginDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersRequest) (*UsersList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateUserRole(context.Context, *UpdateUserRoleDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserRole not implemented")
+}
+func (UnimplementedUserServiceServer) EnrollTOTP(context.Context, *UserID) (*TOTPEnrollment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTOTP not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmTOTP(context.Context, *MFACodeDTO) (*RecoveryCodes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTOTP not implemented")
+}
+func (UnimplementedUserServiceServer) DisableTOTP(context.Context, *MFACodeDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTOTP not implemented")
+}
+func (UnimplementedUserServiceServer) VerifyMFA(context.Context, *MFACodeDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMFA not implemented")
+}
+func (UnimplementedUserServiceServer) ReportAccountLocked(context.Context, *AccountLockedDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportAccountLocked not implemented")
+}
+func (UnimplementedUserServiceServer) GetUserByExternalIdentity(context.Context, *ExternalIdentityDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByExternalIdentity not implemented")
+}
+func (UnimplementedUserServiceServer) LinkExternalIdentity(context.Context, *ExternalIdentityDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkExternalIdentity not implemented")
+}
+func (UnimplementedUserServiceServer) CreateAPIKey(context.Context, *CreateAPIKeyDTO) (*APIKeyDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIKey not implemented")
+}
+func (UnimplementedUserServiceServer) ListAPIKeys(context.Context, *UserID) (*APIKeysList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPIKeys not implemented")
+}
+func (UnimplementedUserServiceServer) RevokeAPIKey(context.Context, *APIKeyID) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (UnimplementedUserServiceServer) AuthenticateAPIKey(context.Context, *APIKeySecret) (*APIKeyPrincipal, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticateAPIKey not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateAvatar(context.Context, *AvatarDTO) (*AvatarDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAvatar not implemented")
+}
+func (UnimplementedUserServiceServer) RequestEmailChange(context.Context, *EmailChangeRequestDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestEmailChange not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmEmailChange(context.Context, *EmailChangeTokenDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmEmailChange not implemented")
+}
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+
+// UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceServer will
+// result in compilation errors.
+type UnsafeUserServiceServer interface {
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+func _UserService_RegisterUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RegisterUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RegisterUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RegisterUser(ctx, req.(*CreateUserDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UpdateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateUser(ctx, req.(*UserDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdatePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserPasswordDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdatePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UpdatePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdatePassword(ctx, req.(*UpdateUserPasswordDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/DeleteUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeactivateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeactivateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/DeactivateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeactivateUser(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUserByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUserByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserByID(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUserByUsernameOrEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserByUsernameOrEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUserByUsernameOrEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserByUsernameOrEmail(ctx, req.(*UserDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserLoginDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Login(ctx, req.(*UserLoginDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRoleDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UpdateUserRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateUserRole(ctx, req.(*UpdateUserRoleDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/EnrollTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).EnrollTOTP(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MFACodeDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ConfirmTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmTOTP(ctx, req.(*MFACodeDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DisableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MFACodeDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DisableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/DisableTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DisableTOTP(ctx, req.(*MFACodeDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_VerifyMFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MFACodeDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).VerifyMFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/VerifyMFA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).VerifyMFA(ctx, req.(*MFACodeDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ReportAccountLocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountLockedDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ReportAccountLocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ReportAccountLocked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ReportAccountLocked(ctx, req.(*AccountLockedDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUserByExternalIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExternalIdentityDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserByExternalIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUserByExternalIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserByExternalIdentity(ctx, req.(*ExternalIdentityDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_LinkExternalIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExternalIdentityDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).LinkExternalIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/LinkExternalIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).LinkExternalIdentity(ctx, req.(*ExternalIdentityDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/CreateAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateAPIKey(ctx, req.(*CreateAPIKeyDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ListAPIKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListAPIKeys(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(APIKeyID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RevokeAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeAPIKey(ctx, req.(*APIKeyID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_AuthenticateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(APIKeySecret)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AuthenticateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/AuthenticateAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AuthenticateAPIKey(ctx, req.(*APIKeySecret))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AvatarDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateAvatar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UpdateAvatar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateAvatar(ctx, req.(*AvatarDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailChangeRequestDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RequestEmailChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestEmailChange(ctx, req.(*EmailChangeRequestDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailChangeTokenDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ConfirmEmailChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmEmailChange(ctx, req.(*EmailChangeTokenDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "userservice.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterUser",
+			Handler:    _UserService_RegisterUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _UserService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "UpdatePassword",
+			Handler:    _UserService_UpdatePassword_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _UserService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "DeactivateUser",
+			Handler:    _UserService_DeactivateUser_Handler,
+		},
+		{
+			MethodName: "GetUserByID",
+			Handler:    _UserService_GetUserByID_Handler,
+		},
+		{
+			MethodName: "GetUserByUsernameOrEmail",
+			Handler:    _UserService_GetUserByUsernameOrEmail_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _UserService_Login_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _UserService_ListUsers_Handler,
+		},
+		{
+			MethodName: "UpdateUserRole",
+			Handler:    _UserService_UpdateUserRole_Handler,
+		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _UserService_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "ConfirmTOTP",
+			Handler:    _UserService_ConfirmTOTP_Handler,
+		},
+		{
+			MethodName: "DisableTOTP",
+			Handler:    _UserService_DisableTOTP_Handler,
+		},
+		{
+			MethodName: "VerifyMFA",
+			Handler:    _UserService_VerifyMFA_Handler,
+		},
+		{
+			MethodName: "ReportAccountLocked",
+			Handler:    _UserService_ReportAccountLocked_Handler,
+		},
+		{
+			MethodName: "GetUserByExternalIdentity",
+			Handler:    _UserService_GetUserByExternalIdentity_Handler,
+		},
+		{
+			MethodName: "LinkExternalIdentity",
+			Handler:    _UserService_LinkExternalIdentity_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _UserService_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _UserService_ListAPIKeys_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _UserService_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "AuthenticateAPIKey",
+			Handler:    _UserService_AuthenticateAPIKey_Handler,
+		},
+		{
+			MethodName: "UpdateAvatar",
+			Handler:    _UserService_UpdateAvatar_Handler,
+		},
+		{
+			MethodName: "RequestEmailChange",
+			Handler:    _UserService_RequestEmailChange_Handler,
+		},
+		{
+			MethodName: "ConfirmEmailChange",
+			Handler:    _UserService_ConfirmEmailChange_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "users.proto",
+}
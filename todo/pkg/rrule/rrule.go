@@ -0,0 +1,336 @@
+// Package rrule реализует подмножество правил повторения RFC 5545:
+// FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY (только для WEEKLY, без порядковых номеров),
+// BYMONTHDAY (только для MONTHLY, в том числе отрицательные дни от конца месяца), COUNT и UNTIL.
+//
+// Повторения считаются по местному времени часового пояса задачи, поэтому задача на 09:00
+// остается на 09:00 и после перехода на летнее время. Время суток берется из начала серии (DTSTART),
+// а не из предыдущего повторения: повторение, попавшее в пропущенный при переходе час, сдвигается,
+// но следующие повторения возвращаются к исходному времени.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// maxIterations ограничивает поиск следующего повторения, например для BYMONTHDAY=31 с INTERVAL=2,
+// который может не найти подходящий месяц
+const maxIterations = 10000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	// Until - последний допустимый момент повторения. Если UNTIL задан датой без времени,
+	// UntilDate указывает, что граница - конец этого дня в часовом поясе задачи
+	Until     time.Time
+	UntilDate bool
+}
+
+// Parse разбирает правило вида FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10, префикс RRULE: допускается
+func Parse(raw string) (*Rule, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) >= len("RRULE:") && strings.EqualFold(raw[:len("RRULE:")], "RRULE:") {
+		raw = raw[len("RRULE:"):]
+	}
+	if raw == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(raw, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+
+		key = strings.ToUpper(key)
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate %s", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(value))
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly {
+				err = fmt.Errorf("unsupported FREQ %s", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(value)
+		case "COUNT":
+			rule.Count, err = parsePositive(value)
+		case "UNTIL":
+			rule.Until, rule.UntilDate, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseByMonthDay(value)
+		case "WKST":
+			// недели всегда начинаются с понедельника, как WKST по умолчанию
+			if strings.ToUpper(value) != "MO" {
+				err = fmt.Errorf("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRule, err)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case rule.Count > 0 && !rule.Until.IsZero():
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	case len(rule.ByDay) > 0 && rule.Freq != Weekly:
+		return nil, fmt.Errorf("%w: BYDAY is supported only with FREQ=WEEKLY", ErrInvalidRule)
+	case len(rule.ByMonthDay) > 0 && rule.Freq != Monthly:
+		return nil, fmt.Errorf("%w: BYMONTHDAY is supported only with FREQ=MONTHLY", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+// String возвращает правило в каноническом виде
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			days = append(days, strings.ToUpper(d.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, d := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(d))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.UntilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next возвращает первое повторение позже after, следующее за повторением prev с порядковым номером
+// prevIndex (первое повторение серии имеет номер 1), и его номер. Время суток повторений берется
+// из начала серии start, нулевой start означает начало серии в prev. Пропущенные повторения
+// учитываются в COUNT. ok=false, если серия закончилась по COUNT или UNTIL
+func (r *Rule) Next(start, prev time.Time, prevIndex int, after time.Time, loc *time.Location) (next time.Time, index int, ok bool) {
+	until := r.until(loc)
+	cur := prev.In(loc)
+	index = prevIndex
+
+	if start.IsZero() {
+		start = prev
+	}
+	hh, mm, ss := start.In(loc).Clock()
+
+	for i := 0; i < maxIterations; i++ {
+		cur, ok = r.step(cur, hh, mm, ss, loc)
+		if !ok {
+			return time.Time{}, 0, false
+		}
+		index++
+
+		if r.Count > 0 && index > r.Count {
+			return time.Time{}, 0, false
+		}
+		if !until.IsZero() && cur.After(until) {
+			return time.Time{}, 0, false
+		}
+		if cur.After(after) {
+			return cur, index, true
+		}
+	}
+
+	return time.Time{}, 0, false
+}
+
+// step возвращает следующее за cur повторение без учета COUNT и UNTIL во время суток hh:mm:ss.
+// Время задается по местным часам, а не прибавлением длительности, чтобы переход на летнее время не сдвигал его
+func (r *Rule) step(cur time.Time, hh, mm, ss int, loc *time.Location) (time.Time, bool) {
+	y, m, d := cur.Date()
+
+	switch r.Freq {
+	case Daily:
+		return localTime(y, m, d+r.Interval, hh, mm, ss, loc), true
+
+	case Weekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{cur.Weekday()}
+		}
+		offsets := make([]int, 0, len(days))
+		for _, day := range days {
+			offsets = append(offsets, mondayOffset(day))
+		}
+		sort.Ints(offsets)
+
+		curOffset := mondayOffset(cur.Weekday())
+		for _, offset := range offsets {
+			if offset > curOffset {
+				return localTime(y, m, d+offset-curOffset, hh, mm, ss, loc), true
+			}
+		}
+
+		// первый подходящий день недели через INTERVAL недель
+		monday := d - curOffset + 7*r.Interval
+		return localTime(y, m, monday+offsets[0], hh, mm, ss, loc), true
+
+	case Monthly:
+		days := r.ByMonthDay
+		if len(days) == 0 {
+			days = []int{d}
+		}
+
+		for k := 0; k < maxIterations; k += r.Interval {
+			first := time.Date(y, m+time.Month(k), 1, hh, mm, ss, 0, loc)
+			for _, day := range resolveMonthDays(days, first) {
+				if k == 0 && day <= d {
+					continue
+				}
+				return localTime(first.Year(), first.Month(), day, hh, mm, ss, loc), true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// localTime возвращает момент с местным временем hh:mm:ss. Если такого времени в этот день нет
+// из-за перехода на летнее время, оно считается по смещению до перехода, как требует RFC 5545:
+// 02:30 в пропущенный час становится 03:30
+func localTime(y int, m time.Month, d, hh, mm, ss int, loc *time.Location) time.Time {
+	t := time.Date(y, m, d, hh, mm, ss, 0, loc)
+	if gotH, gotM, gotS := t.Clock(); gotH == hh && gotM == mm && gotS == ss {
+		return t
+	}
+
+	// переходы не бывают чаще раза в сутки, поэтому за 12 часов до него действует прежнее смещение
+	_, before := t.Add(-12 * time.Hour).Zone()
+	return time.Date(y, m, d, hh, mm, ss, 0, time.FixedZone("", before)).In(loc)
+}
+
+func (r *Rule) until(loc *time.Location) time.Time {
+	if r.Until.IsZero() || !r.UntilDate {
+		return r.Until
+	}
+
+	y, m, d := r.Until.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+}
+
+// resolveMonthDays переводит BYMONTHDAY в дни месяца по возрастанию. Дни, которых нет в месяце,
+// пропускаются, как требует RFC 5545
+func resolveMonthDays(days []int, first time.Time) []int {
+	daysInMonth := time.Date(first.Year(), first.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	res := make([]int, 0, len(days))
+	for _, day := range days {
+		if day < 0 {
+			day = daysInMonth + day + 1
+		}
+		if day >= 1 && day <= daysInMonth {
+			res = append(res, day)
+		}
+	}
+	sort.Ints(res)
+
+	return res
+}
+
+// mondayOffset - номер дня в неделе, начинающейся с понедельника
+func mondayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func parsePositive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q must be a positive integer", value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("UNTIL %q must be YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
+
+func parseByDay(value string) ([]time.Weekday, error) {
+	res := make([]time.Weekday, 0)
+	seen := make(map[time.Weekday]bool)
+	for _, code := range strings.Split(value, ",") {
+		day, ok := weekdays[strings.ToUpper(code)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported BYDAY value %q", code)
+		}
+		if !seen[day] {
+			seen[day] = true
+			res = append(res, day)
+		}
+	}
+	return res, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	res := make([]int, 0)
+	for _, raw := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(raw)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("BYMONTHDAY %q must be between 1 and 31 or -31 and -1", raw)
+		}
+		res = append(res, day)
+	}
+	return res, nil
+}
@@ -0,0 +1,208 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,th;COUNT=10")
+	require.NoError(t, err)
+	require.Equal(t, &Rule{Freq: Weekly, Interval: 2, ByDay: []time.Weekday{time.Monday, time.Thursday}, Count: 10}, rule)
+	require.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10", rule.String())
+
+	rule, err = Parse("FREQ=MONTHLY;BYMONTHDAY=-1;UNTIL=20241231")
+	require.NoError(t, err)
+	require.True(t, rule.UntilDate)
+	require.Equal(t, "FREQ=MONTHLY;BYMONTHDAY=-1;UNTIL=20241231", rule.String())
+
+	for _, invalid := range []string{
+		"",
+		"FREQ=YEARLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;UNTIL=2024-01-01",
+	} {
+		_, err := Parse(invalid)
+		require.ErrorIs(t, err, ErrInvalidRule, invalid)
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	newYork := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name      string
+		rule      string
+		loc       *time.Location
+		start     time.Time
+		prev      time.Time
+		prevIndex int
+		after     time.Time
+		want      time.Time
+		wantIndex int
+		wantOK    bool
+	}{
+		{
+			name:      "DailyKeepsLocalTimeAcrossDST",
+			rule:      "FREQ=DAILY",
+			loc:       berlin,
+			prev:      time.Date(2024, 3, 30, 9, 0, 0, 0, berlin),
+			prevIndex: 1,
+			want:      time.Date(2024, 3, 31, 9, 0, 0, 0, berlin),
+			wantIndex: 2,
+			wantOK:    true,
+		},
+		{
+			name:      "DailyReturnsToStartTimeAfterDSTGap",
+			rule:      "FREQ=DAILY",
+			loc:       newYork,
+			start:     time.Date(2024, 3, 9, 2, 30, 0, 0, newYork),
+			prev:      time.Date(2024, 3, 10, 3, 30, 0, 0, newYork), // 02:30 в этот день пропущено
+			prevIndex: 2,
+			want:      time.Date(2024, 3, 11, 2, 30, 0, 0, newYork),
+			wantIndex: 3,
+			wantOK:    true,
+		},
+		{
+			name:      "WeeklySameWeek",
+			rule:      "FREQ=WEEKLY;BYDAY=MO,TH",
+			loc:       berlin,
+			prev:      time.Date(2024, 3, 4, 18, 0, 0, 0, berlin), // понедельник
+			prevIndex: 1,
+			want:      time.Date(2024, 3, 7, 18, 0, 0, 0, berlin),
+			wantIndex: 2,
+			wantOK:    true,
+		},
+		{
+			name:      "WeeklyIntervalJumpsToNextPeriod",
+			rule:      "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			loc:       berlin,
+			prev:      time.Date(2024, 3, 7, 18, 0, 0, 0, berlin), // четверг
+			prevIndex: 2,
+			want:      time.Date(2024, 3, 18, 18, 0, 0, 0, berlin),
+			wantIndex: 3,
+			wantOK:    true,
+		},
+		{
+			name:      "WeeklyDefaultsToStartWeekday",
+			rule:      "FREQ=WEEKLY",
+			loc:       time.UTC,
+			prev:      time.Date(2024, 3, 6, 8, 0, 0, 0, time.UTC),
+			prevIndex: 1,
+			want:      time.Date(2024, 3, 13, 8, 0, 0, 0, time.UTC),
+			wantIndex: 2,
+			wantOK:    true,
+		},
+		{
+			name:      "MonthlySkipsMonthsWithoutDay",
+			rule:      "FREQ=MONTHLY;BYMONTHDAY=31",
+			loc:       time.UTC,
+			prev:      time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+			prevIndex: 1,
+			want:      time.Date(2024, 3, 31, 10, 0, 0, 0, time.UTC),
+			wantIndex: 2,
+			wantOK:    true,
+		},
+		{
+			name:      "MonthlyLastDay",
+			rule:      "FREQ=MONTHLY;BYMONTHDAY=-1",
+			loc:       time.UTC,
+			prev:      time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+			prevIndex: 1,
+			want:      time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC),
+			wantIndex: 2,
+			wantOK:    true,
+		},
+		{
+			name:      "SkipsMissedOccurrencesButCountsThem",
+			rule:      "FREQ=DAILY;COUNT=10",
+			loc:       time.UTC,
+			prev:      time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+			prevIndex: 1,
+			after:     time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC),
+			want:      time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+			wantIndex: 5,
+			wantOK:    true,
+		},
+		{
+			name:      "CountExhausted",
+			rule:      "FREQ=DAILY;COUNT=3",
+			loc:       time.UTC,
+			prev:      time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC),
+			prevIndex: 3,
+		},
+		{
+			name:      "UntilDateIsInclusiveInLocalTime",
+			rule:      "FREQ=DAILY;UNTIL=20240310",
+			loc:       berlin,
+			prev:      time.Date(2024, 3, 9, 23, 30, 0, 0, berlin),
+			prevIndex: 1,
+			want:      time.Date(2024, 3, 10, 23, 30, 0, 0, berlin),
+			wantIndex: 2,
+			wantOK:    true,
+		},
+		{
+			name:      "UntilPassed",
+			rule:      "FREQ=DAILY;UNTIL=20240310T000000Z",
+			loc:       time.UTC,
+			prev:      time.Date(2024, 3, 9, 9, 0, 0, 0, time.UTC),
+			prevIndex: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+
+			got, index, ok := rule.Next(tt.start, tt.prev, tt.prevIndex, tt.after, tt.loc)
+			require.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				require.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+				require.Equal(t, tt.wantIndex, index)
+			}
+		})
+	}
+}
+
+func TestRule_NextAcrossDSTGap(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+
+	rule, err := Parse("FREQ=DAILY")
+	require.NoError(t, err)
+
+	start := time.Date(2024, 3, 9, 2, 30, 0, 0, newYork)
+	cur, index := start, 1
+	for day := 10; day <= 13; day++ {
+		next, nextIndex, ok := rule.Next(start, cur, index, cur, newYork)
+		require.True(t, ok)
+		require.Equal(t, day, next.Day())
+
+		hh, mm, _ := next.In(newYork).Clock()
+		if day == 10 {
+			// 02:30 10 марта не существует, повторение попадает в следующий час
+			require.Equal(t, 3, hh)
+		} else {
+			require.Equal(t, 2, hh, "day %d drifted to %s", day, next)
+		}
+		require.Equal(t, 30, mm)
+
+		cur, index = next, nextIndex
+	}
+}
@@ -0,0 +1,279 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	playground "github.com/go-playground/validator/v10"
+)
+
+type Config struct {
+	UsernameMinLen         int  `envconfig:"USERNAME_MIN_LEN" required:"true" default:"3"`
+	UsernameMaxLen         int  `envconfig:"USERNAME_MAX_LEN" required:"true" default:"32"`
+	EmailMaxLen            int  `envconfig:"EMAIL_MAX_LEN" required:"true" default:"254"`
+	PasswordMinLen         int  `envconfig:"PASSWORD_MIN_LEN" required:"true" default:"8"`
+	PasswordMaxLen         int  `envconfig:"PASSWORD_MAX_LEN" required:"true" default:"72"`
+	PasswordRequireUpper   bool `envconfig:"PASSWORD_REQUIRE_UPPER" default:"true"`
+	PasswordRequireLower   bool `envconfig:"PASSWORD_REQUIRE_LOWER" default:"true"`
+	PasswordRequireDigit   bool `envconfig:"PASSWORD_REQUIRE_DIGIT" default:"true"`
+	PasswordRequireSpecial bool `envconfig:"PASSWORD_REQUIRE_SPECIAL" default:"false"`
+	DescriptionMaxLen      int  `envconfig:"DESCRIPTION_MAX_LEN" required:"true" default:"1000"`
+	CommentMaxLen          int  `envconfig:"COMMENT_MAX_LEN" required:"true" default:"4000"`
+	LabelNameMaxLen        int  `envconfig:"LABEL_NAME_MAX_LEN" required:"true" default:"50"`
+	WorkspaceNameMaxLen    int  `envconfig:"WORKSPACE_NAME_MAX_LEN" required:"true" default:"100"`
+}
+
+// FieldViolation описывает одно нарушенное правило валидации для конкретного поля
+type FieldViolation struct {
+	Field   string `json:"field" example:"email"`
+	Message string `json:"message" example:"must be a valid email address"`
+}
+
+// ValidationError содержит список всех нарушений, найденных в DTO
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func NewValidationError(violations ...FieldViolation) *ValidationError {
+	return &ValidationError{Violations: violations}
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("%s: %s", v.Field, v.Message))
+	}
+
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Validator проверяет DTO по тегам `validate`. Кроме стандартных правил
+// go-playground/validator доступны правила username, password, email_address,
+// description, comment, label_name и workspace_name, лимиты которых берутся из Config.
+type Validator struct {
+	cfg      *Config
+	validate *playground.Validate
+}
+
+func New(cfg *Config) *Validator {
+	v := &Validator{
+		cfg:      cfg,
+		validate: playground.New(),
+	}
+
+	// в нарушениях используем имена полей из json, чтобы клиенту было понятно, какое поле исправить
+	v.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return strings.ToLower(field.Name)
+		}
+		return name
+	})
+
+	_ = v.validate.RegisterValidation("username", v.isUsername)
+	_ = v.validate.RegisterValidation("password", v.isPassword)
+	_ = v.validate.RegisterValidation("email_address", v.isEmailAddress)
+	_ = v.validate.RegisterValidation("description", v.isDescription)
+	_ = v.validate.RegisterValidation("comment", v.isComment)
+	_ = v.validate.RegisterValidation("label_name", v.isLabelName)
+	_ = v.validate.RegisterValidation("workspace_name", v.isWorkspaceName)
+
+	return v
+}
+
+// Validate возвращает *ValidationError, если структура не прошла проверку
+func (v *Validator) Validate(s interface{}) error {
+	err := v.validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors playground.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return fmt.Errorf("[Validate] validate struct: %w", err)
+	}
+
+	violations := make([]FieldViolation, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		violations = append(violations, FieldViolation{
+			Field:   fe.Field(),
+			Message: v.message(fe),
+		})
+	}
+
+	return NewValidationError(violations...)
+}
+
+func (v *Validator) message(fe playground.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without", "required_with":
+		return "is required"
+	case "username":
+		return fmt.Sprintf(
+			"must be %d-%d characters long and contain only letters, digits, '.', '_' or '-'",
+			v.cfg.UsernameMinLen, v.cfg.UsernameMaxLen,
+		)
+	case "password":
+		return v.passwordPolicy()
+	case "email_address":
+		return fmt.Sprintf("must be a valid email address not longer than %d characters", v.cfg.EmailMaxLen)
+	case "description":
+		return fmt.Sprintf("must not be blank or longer than %d characters", v.cfg.DescriptionMaxLen)
+	case "comment":
+		return fmt.Sprintf("must not be blank or longer than %d characters", v.cfg.CommentMaxLen)
+	case "label_name":
+		return fmt.Sprintf("must not be blank or longer than %d characters", v.cfg.LabelNameMaxLen)
+	case "workspace_name":
+		return fmt.Sprintf("must not be blank or longer than %d characters", v.cfg.WorkspaceNameMaxLen)
+	case "hexcolor":
+		return "must be a hex color like #1e90ff"
+	case "eqfield":
+		return fmt.Sprintf("must match %s", strings.ToLower(fe.Param()))
+	case "min", "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "gt":
+		// gt без параметра для времени - момент в будущем
+		if fe.Param() == "" {
+			return "must be in the future"
+		}
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "gtefield":
+		return fmt.Sprintf("must not be before %s", strings.ToLower(fe.Param()))
+	case "timezone":
+		return "must be an IANA time zone, for example Europe/Moscow"
+	case "bcp47_language_tag":
+		return "must be a BCP 47 language tag, for example en or ru-RU"
+	default:
+		return fmt.Sprintf("failed on the '%s' rule", fe.Tag())
+	}
+}
+
+func (v *Validator) passwordPolicy() string {
+	policy := fmt.Sprintf("must be %d-%d characters long", v.cfg.PasswordMinLen, v.cfg.PasswordMaxLen)
+
+	var rules []string
+	if v.cfg.PasswordRequireUpper {
+		rules = append(rules, "an uppercase letter")
+	}
+	if v.cfg.PasswordRequireLower {
+		rules = append(rules, "a lowercase letter")
+	}
+	if v.cfg.PasswordRequireDigit {
+		rules = append(rules, "a digit")
+	}
+	if v.cfg.PasswordRequireSpecial {
+		rules = append(rules, "a special character")
+	}
+
+	if len(rules) > 0 {
+		policy += " and contain " + strings.Join(rules, ", ")
+	}
+
+	return policy
+}
+
+func (v *Validator) isUsername(fl playground.FieldLevel) bool {
+	username := fl.Field().String()
+
+	length := utf8.RuneCountInString(username)
+	if length < v.cfg.UsernameMinLen || length > v.cfg.UsernameMaxLen {
+		return false
+	}
+
+	for _, r := range username {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("._-", r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v *Validator) isPassword(fl playground.FieldLevel) bool {
+	password := fl.Field().String()
+
+	length := utf8.RuneCountInString(password)
+	if length < v.cfg.PasswordMinLen || length > v.cfg.PasswordMaxLen {
+		return false
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSpecial = true
+		}
+	}
+
+	return (hasUpper || !v.cfg.PasswordRequireUpper) &&
+		(hasLower || !v.cfg.PasswordRequireLower) &&
+		(hasDigit || !v.cfg.PasswordRequireDigit) &&
+		(hasSpecial || !v.cfg.PasswordRequireSpecial)
+}
+
+func (v *Validator) isEmailAddress(fl playground.FieldLevel) bool {
+	email := fl.Field().String()
+
+	if utf8.RuneCountInString(email) > v.cfg.EmailMaxLen {
+		return false
+	}
+
+	return v.validate.Var(email, "email") == nil
+}
+
+func (v *Validator) isDescription(fl playground.FieldLevel) bool {
+	description := fl.Field().String()
+
+	if strings.TrimSpace(description) == "" {
+		return false
+	}
+
+	return utf8.RuneCountInString(description) <= v.cfg.DescriptionMaxLen
+}
+
+func (v *Validator) isComment(fl playground.FieldLevel) bool {
+	comment := fl.Field().String()
+
+	if strings.TrimSpace(comment) == "" {
+		return false
+	}
+
+	return utf8.RuneCountInString(comment) <= v.cfg.CommentMaxLen
+}
+
+func (v *Validator) isLabelName(fl playground.FieldLevel) bool {
+	name := fl.Field().String()
+
+	if strings.TrimSpace(name) == "" {
+		return false
+	}
+
+	return utf8.RuneCountInString(name) <= v.cfg.LabelNameMaxLen
+}
+
+func (v *Validator) isWorkspaceName(fl playground.FieldLevel) bool {
+	name := fl.Field().String()
+
+	if strings.TrimSpace(name) == "" {
+		return false
+	}
+
+	return utf8.RuneCountInString(name) <= v.cfg.WorkspaceNameMaxLen
+}
@@ -1,11 +1,13 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
+	"time"
 	"users/config"
 	"users/internal/api"
 	"users/internal/api/grpc"
@@ -14,9 +16,11 @@ import (
 	"users/internal/service"
 	"users/pkg/jaeger"
 	"users/pkg/logging"
+	"users/pkg/mfa"
 	"users/pkg/pass_utils"
 	"users/pkg/postgresql"
 	"users/pkg/rabbitmq/producer"
+	"users/pkg/validator"
 )
 
 type App struct {
@@ -52,10 +56,38 @@ func NewApp(
 		return nil, fmt.Errorf("start rabbit producer: %w", err)
 	}
 
-	passUtils := pass_utils.NewPasswordUtils(&cfg.Password)
+	// события об учетных записях читают другие сервисы, поэтому они идут в отдельную от писем очередь
+	userEventsProducer, err := producer.New(
+		&cfg.RabbitConfig,
+		cfg.UsersExchange,
+		cfg.UserEventsQueue,
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("start rabbit user events producer: %w", err)
+	}
+
+	passUtils, err := pass_utils.NewPasswordUtils(&cfg.Password)
+	if err != nil {
+		return nil, fmt.Errorf("init password utils: %w", err)
+	}
+
+	userMFA, err := mfa.New(&cfg.MFA)
+	if err != nil {
+		return nil, fmt.Errorf("init mfa: %w", err)
+	}
 
 	// передадим реализацию репозитория и продьюсера rabbit mq конструктору сервиса
-	userService := service.NewUserService(userRepo, usersProducer, passUtils)
+	userService := service.NewUserService(
+		userRepo,
+		usersProducer,
+		passUtils,
+		validator.New(&cfg.Validation),
+		userMFA,
+		&cfg.EmailChange,
+		&cfg.Deletion,
+		userEventsProducer,
+	)
 
 	return &App{
 		cfg:         cfg,
@@ -86,9 +118,43 @@ func (a *App) RunApp() error {
 		return fmt.Errorf("[RunApp] run GRPC: %w", err)
 	})
 
+	group.Go(func() error {
+		a.purgeDeletedUsers(context.Background())
+		return nil
+	})
+
 	if err := group.Wait(); err != nil {
 		return fmt.Errorf("[RunApp] run: %w", err)
 	}
 
 	return nil
 }
+
+// purgeDeletedUsers периодически обезличивает учетные записи, срок ожидания удаления которых истек
+func (a *App) purgeDeletedUsers(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.Deletion.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// пачки обрабатываются, пока очередь на удаление не опустеет
+		for {
+			purged, err := a.userService.PurgeDeletedUsers(ctx)
+			if err != nil {
+				a.logger.Error().Msgf("[purgeDeletedUsers] purge: %s", err)
+				break
+			}
+			if purged > 0 {
+				a.logger.Info().Msgf("[purgeDeletedUsers] purged %d users", purged)
+			}
+			if purged < a.cfg.Deletion.BatchSize {
+				break
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	// база часовых поясов для проверки timezone в профиле, в образе ее может не быть
+	_ "time/tzdata"
 	"users/app"
 	"users/config"
 )
@@ -2,11 +2,14 @@ package config
 
 import (
 	"github.com/kelseyhightower/envconfig"
+	"time"
 	"users/pkg/jaeger"
 	"users/pkg/logging"
+	"users/pkg/mfa"
 	"users/pkg/pass_utils"
 	"users/pkg/postgresql"
 	"users/pkg/rabbitmq"
+	"users/pkg/validator"
 )
 
 type Config struct {
@@ -19,6 +22,12 @@ type Config struct {
 	RabbitConfig  rabbitmq.RabbitProducerConfig `envconfig:"RABBITMQ"`
 	UsersExchange string                        `envconfig:"RABBITMQ_USERS_EXCHANGE" default:"users.exchange"`
 	UsersQueue    string                        `envconfig:"RABBITMQ_USERS_QUEUE" default:"users.queue"`
+	// UserEventsQueue - события об учетных записях для других сервисов, письма уходят в UsersQueue
+	UserEventsQueue string           `envconfig:"RABBITMQ_USER_EVENTS_QUEUE" default:"users.events.queue"`
+	Validation      validator.Config `envconfig:"VALIDATION"`
+	MFA             mfa.Config       `envconfig:"MFA"`
+	EmailChange     EmailChange      `envconfig:"EMAIL_CHANGE"`
+	Deletion        Deletion         `envconfig:"DELETION"`
 }
 
 type MigrationsConfig struct {
@@ -35,6 +44,22 @@ type Grpc struct {
 	AppPort string `envconfig:"GRPC_PORT" required:"true" default:"50000"`
 }
 
+// EmailChange - настройки подтверждения нового email
+type EmailChange struct {
+	// ConfirmURL - адрес подтверждения, токен передается в параметре token
+	ConfirmURL string        `envconfig:"CONFIRM_URL" default:"http://localhost:3000/api/v1/users/email/confirm"`
+	TTL        time.Duration `envconfig:"TTL" default:"24h"`
+}
+
+// Deletion - настройки удаления учетных записей
+type Deletion struct {
+	// GracePeriod - сколько учетная запись ждет удаления, вход в систему в это время отменяет удаление
+	GracePeriod time.Duration `envconfig:"GRACE_PERIOD" default:"720h"`
+	// PurgeInterval - как часто ищутся учетные записи с истекшим сроком
+	PurgeInterval time.Duration `envconfig:"PURGE_INTERVAL" default:"1h"`
+	BatchSize     int           `envconfig:"BATCH_SIZE" default:"100"`
+}
+
 func NewFromEnv() *Config {
 	c := Config{}
 	envconfig.MustProcess("", &c)
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	appErrors "users/internal/app_errors"
+)
+
+// convertError переводит ошибки сервиса в grpc-статусы, чтобы клиент мог их различить.
+// Ошибки валидации сами реализуют GRPCStatus и возвращаются как есть.
+func convertError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, appErrors.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, appErrors.ErrWrongCredentials),
+		errors.Is(err, appErrors.ErrIncorrectOldPassword),
+		errors.Is(err, appErrors.ErrInvalidMFACode):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, appErrors.ErrPassAndConfirmationDoesNotMatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, appErrors.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, appErrors.ErrMFANotEnrolled),
+		errors.Is(err, appErrors.ErrMFAAlreadyEnabled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -26,8 +26,8 @@ func (s *server) RegisterUser(ctx context.Context, req *users.CreateUserDTO) (*u
 		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
 			Str("requestId", requestId).
-			Msgf("[RegisterUser]: %w", err)
-		return nil, err
+			Msgf("[RegisterUser]: %s", err)
+		return nil, convertError(err)
 	}
 
 	return &users.UserID{Id: int32(id)}, nil
@@ -46,9 +46,9 @@ func (s *server) UpdateUser(ctx context.Context, req *users.UserDTO) (*users.Use
 		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
 			Str("requestId", requestId).
-			Msgf("[UpdateUser]: %w", err)
+			Msgf("[UpdateUser]: %s", err)
 
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return result.ToGRPC(), nil
@@ -67,29 +67,49 @@ func (s *server) UpdatePassword(ctx context.Context, req *users.UpdateUserPasswo
 		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
 			Str("requestId", requestId).
-			Msgf("[UpdatePassword]: %w", err)
+			Msgf("[UpdatePassword]: %s", err)
 
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
 // DeleteUser - Handles user deletion
-func (s *server) DeleteUser(ctx context.Context, req *users.UserID) (*emptypb.Empty, error) {
+func (s *server) DeleteUser(ctx context.Context, req *users.DeleteUserRequest) (*users.UserDTO, error) {
 	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DeleteUser")
 	defer span.Finish()
 
-	err := s.userService.DeleteUser(ctx, int(req.Id))
+	user, err := s.userService.DeleteUser(ctx, int(req.Id), int(req.Version))
 	if err != nil {
 		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
 			Str("requestId", requestId).
-			Msgf("[DeleteUser]: %w", err)
+			Msgf("[DeleteUser]: %s", err)
 
-		return nil, err
+		return nil, convertError(err)
+	}
+
+	return user.ToGRPC(), nil
+}
+
+// DeactivateUser - Handles user deactivation
+func (s *server) DeactivateUser(ctx context.Context, req *users.UserID) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DeactivateUser")
+	defer span.Finish()
+
+	err := s.userService.DeactivateUser(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeactivateUser]: %s", err)
+
+		return nil, convertError(err)
 	}
 
 	return &emptypb.Empty{}, nil
@@ -107,9 +127,9 @@ func (s *server) GetUserByID(ctx context.Context, req *users.UserID) (*users.Use
 		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
 			Str("requestId", requestId).
-			Msgf("[GetUserByID]: %w", err)
+			Msgf("[GetUserByID]: %s", err)
 
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return user.ToGRPC(), nil
@@ -127,9 +147,9 @@ func (s *server) GetUserByUsernameOrEmail(ctx context.Context, req *users.UserDT
 		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
 			Str("requestId", requestId).
-			Msgf("[GetUserByUsernameOrEmail]: %w", err)
+			Msgf("[GetUserByUsernameOrEmail]: %s", err)
 
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return user.ToGRPC(), nil
@@ -148,9 +168,348 @@ func (s *server) Login(ctx context.Context, req *users.UserLoginDTO) (*users.Use
 		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
 		s.logger.Error().
 			Str("requestId", requestId).
-			Msgf("[Login]: %w", err)
+			Msgf("[Login]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return user.ToGRPC(), nil
+}
+
+// ListUsers - Retrieves a page of users
+func (s *server) ListUsers(ctx context.Context, req *users.ListUsersRequest) (*users.UsersList, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListUsers")
+	defer span.Finish()
+
+	result, err := s.userService.ListUsers(ctx, int(req.Limit), int(req.Offset))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListUsers]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	response := &users.UsersList{Users: make([]*users.UserDTO, 0, len(result))}
+	for _, user := range result {
+		response.Users = append(response.Users, user.ToGRPC())
+	}
+
+	return response, nil
+}
+
+// UpdateUserRole - Handles user role changes
+func (s *server) UpdateUserRole(ctx context.Context, req *users.UpdateUserRoleDTO) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	request := models.NewEmptyUpdateUserRoleDTO().FromGRPC(req)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateUserRole")
+	defer span.Finish()
+
+	err := s.userService.UpdateUserRole(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateUserRole]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// EnrollTOTP - Starts TOTP enrollment
+func (s *server) EnrollTOTP(ctx context.Context, req *users.UserID) (*users.TOTPEnrollment, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.EnrollTOTP")
+	defer span.Finish()
+
+	enrollment, err := s.userService.EnrollTOTP(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[EnrollTOTP]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return enrollment.ToGRPC(), nil
+}
+
+// ConfirmTOTP - Confirms TOTP enrollment with the first code
+func (s *server) ConfirmTOTP(ctx context.Context, req *users.MFACodeDTO) (*users.RecoveryCodes, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	request := models.NewEmptyMFACodeDTO().FromGRPC(req)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ConfirmTOTP")
+	defer span.Finish()
+
+	codes, err := s.userService.ConfirmTOTP(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmTOTP]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return &users.RecoveryCodes{Codes: codes}, nil
+}
+
+// DisableTOTP - Disables TOTP
+func (s *server) DisableTOTP(ctx context.Context, req *users.MFACodeDTO) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	request := models.NewEmptyMFACodeDTO().FromGRPC(req)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DisableTOTP")
+	defer span.Finish()
+
+	err := s.userService.DisableTOTP(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DisableTOTP]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// VerifyMFA - Verifies the second factor during login
+func (s *server) VerifyMFA(ctx context.Context, req *users.MFACodeDTO) (*users.UserDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	request := models.NewEmptyMFACodeDTO().FromGRPC(req)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.VerifyMFA")
+	defer span.Finish()
+
+	user, err := s.userService.VerifyMFA(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[VerifyMFA]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return user.ToGRPC(), nil
+}
+
+// ReportAccountLocked - Notifies the user that login was locked after failed attempts
+func (s *server) ReportAccountLocked(ctx context.Context, req *users.AccountLockedDTO) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	event := models.NewEmptyAccountLockedDTO().FromGRPC(req)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ReportAccountLocked")
+	defer span.Finish()
+
+	err := s.userService.ReportAccountLocked(ctx, event)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ReportAccountLocked]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// GetUserByExternalIdentity - Returns the user linked to an external provider account
+func (s *server) GetUserByExternalIdentity(ctx context.Context, req *users.ExternalIdentityDTO) (*users.UserDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	identity := models.NewEmptyExternalIdentityDTO().FromGRPC(req)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetUserByExternalIdentity")
+	defer span.Finish()
+
+	user, err := s.userService.GetUserByExternalIdentity(ctx, identity)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetUserByExternalIdentity]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return user.ToGRPC(), nil
+}
+
+// LinkExternalIdentity - Links an external provider account to a user, creating the user if needed
+func (s *server) LinkExternalIdentity(ctx context.Context, req *users.ExternalIdentityDTO) (*users.UserDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	identity := models.NewEmptyExternalIdentityDTO().FromGRPC(req)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.LinkExternalIdentity")
+	defer span.Finish()
+
+	user, err := s.userService.LinkExternalIdentity(ctx, identity)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[LinkExternalIdentity]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return user.ToGRPC(), nil
+}
+
+// CreateAPIKey - Creates an API key. The key itself is returned only once
+func (s *server) CreateAPIKey(ctx context.Context, req *users.CreateAPIKeyDTO) (*users.APIKeyDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	request := models.NewEmptyCreateAPIKeyDTO().FromGRPC(req)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateAPIKey")
+	defer span.Finish()
+
+	key, err := s.userService.CreateAPIKey(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateAPIKey]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return key.ToGRPC(), nil
+}
+
+// ListAPIKeys - Returns the user's API keys without the keys themselves
+func (s *server) ListAPIKeys(ctx context.Context, req *users.UserID) (*users.APIKeysList, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListAPIKeys")
+	defer span.Finish()
+
+	keys, err := s.userService.ListAPIKeys(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListAPIKeys]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	result := &users.APIKeysList{Keys: make([]*users.APIKeyDTO, 0, len(keys))}
+	for _, key := range keys {
+		result.Keys = append(result.Keys, key.ToGRPC())
+	}
+
+	return result, nil
+}
+
+// RevokeAPIKey - Deletes the user's API key
+func (s *server) RevokeAPIKey(ctx context.Context, req *users.APIKeyID) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RevokeAPIKey")
+	defer span.Finish()
+
+	err := s.userService.RevokeAPIKey(ctx, int(req.Id), int(req.UserId))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RevokeAPIKey]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// AuthenticateAPIKey - Checks an API key and returns its owner and scopes
+func (s *server) AuthenticateAPIKey(ctx context.Context, req *users.APIKeySecret) (*users.APIKeyPrincipal, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.AuthenticateAPIKey")
+	defer span.Finish()
+
+	principal, err := s.userService.AuthenticateAPIKey(ctx, req.Key)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Warn().
+			Str("requestId", requestId).
+			Msgf("[AuthenticateAPIKey]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return principal.ToGRPC(), nil
+}
+
+// UpdateAvatar - Handles avatar changes and returns the previous avatar key
+func (s *server) UpdateAvatar(ctx context.Context, req *users.AvatarDTO) (*users.AvatarDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateAvatar")
+	defer span.Finish()
+
+	previous, err := s.userService.UpdateAvatar(ctx, int(req.Id), req.AvatarKey)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateAvatar]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return &users.AvatarDTO{Id: req.Id, AvatarKey: previous}, nil
+}
+
+// RequestEmailChange - Handles email change request
+func (s *server) RequestEmailChange(ctx context.Context, req *users.EmailChangeRequestDTO) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+	request := models.NewEmptyEmailChangeRequestDTO().FromGRPC(req)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RequestEmailChange")
+	defer span.Finish()
+
+	err := s.userService.RequestEmailChange(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestEmailChange]: %s", err)
+
+		return nil, convertError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ConfirmEmailChange - Handles email change confirmation
+func (s *server) ConfirmEmailChange(ctx context.Context, req *users.EmailChangeTokenDTO) (*users.UserDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ConfirmEmailChange")
+	defer span.Finish()
+
+	user, err := s.userService.ConfirmEmailChange(ctx, req.Token)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmEmailChange]: %s", err)
 
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return user.ToGRPC(), nil
@@ -9,8 +9,26 @@ type UserService interface {
 	RegisterUser(ctx context.Context, newUser *models.CreateUserDTO) (int, error)
 	UpdateUser(ctx context.Context, updatedUser *models.UserDTO) (*models.UserDTO, error)
 	UpdatePassword(ctx context.Context, updatePassword *models.UpdateUserPasswordDTO) error
-	DeleteUser(ctx context.Context, userID int) error
+	DeleteUser(ctx context.Context, userID, version int) (*models.UserDTO, error)
+	DeactivateUser(ctx context.Context, userID int) error
+	PurgeDeletedUsers(ctx context.Context) (int, error)
 	GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error)
 	GetUserByUsernameOrEmail(ctx context.Context, name, email string) (*models.UserDTO, error)
 	Login(ctx context.Context, login *models.UserLoginDTO) (*models.UserDTO, error)
+	ListUsers(ctx context.Context, limit, offset int) ([]*models.UserDTO, error)
+	UpdateUserRole(ctx context.Context, request *models.UpdateUserRoleDTO) error
+	EnrollTOTP(ctx context.Context, userID int) (*models.TOTPEnrollmentDTO, error)
+	ConfirmTOTP(ctx context.Context, request *models.MFACodeDTO) ([]string, error)
+	DisableTOTP(ctx context.Context, request *models.MFACodeDTO) error
+	VerifyMFA(ctx context.Context, request *models.MFACodeDTO) (*models.UserDTO, error)
+	ReportAccountLocked(ctx context.Context, event *models.AccountLockedDTO) error
+	GetUserByExternalIdentity(ctx context.Context, identity *models.ExternalIdentityDTO) (*models.UserDTO, error)
+	LinkExternalIdentity(ctx context.Context, identity *models.ExternalIdentityDTO) (*models.UserDTO, error)
+	CreateAPIKey(ctx context.Context, request *models.CreateAPIKeyDTO) (*models.APIKeyDTO, error)
+	ListAPIKeys(ctx context.Context, userID int) ([]*models.APIKeyDTO, error)
+	RevokeAPIKey(ctx context.Context, keyID, userID int) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKeyPrincipalDTO, error)
+	UpdateAvatar(ctx context.Context, userID int, avatarKey string) (string, error)
+	RequestEmailChange(ctx context.Context, request *models.EmailChangeRequestDTO) error
+	ConfirmEmailChange(ctx context.Context, token string) (*models.UserDTO, error)
 }
@@ -1,5 +1,7 @@
 package rest
 
+import "users/pkg/validator"
+
 type ErrorCode string
 
 const (
@@ -7,12 +9,14 @@ const (
 	ErrCodeRequestValidationError ErrorCode = "USER_REQUEST_VALIDATION_ERROR"
 	ErrCodeBadRequest             ErrorCode = "BAD_REQUEST"
 	ErrCodeNotFound               ErrorCode = "NOT_FOUND"
+	ErrCodePreconditionFailed     ErrorCode = "PRECONDITION_FAILED"
 	ErrCodeInvalidJsonFormat      ErrorCode = "JSON_FORMAT_ERROR"
 )
 
 type ApiError struct {
-	Message string
-	ErrCode ErrorCode
+	Message    string
+	ErrCode    ErrorCode
+	Violations []validator.FieldViolation
 }
 
 func NewApiError(message string, errCode ErrorCode) *ApiError {
@@ -43,6 +47,7 @@ var (
 	ErrUsernameOrEmailAlreadyUsed = NewApiError("username or email already used", ErrCodeBadRequest)
 	ErrWrongCredentials           = NewApiError("wrong credentials", ErrCodeBadRequest)
 	ErrNotFound                   = NewApiError("not found", ErrCodeNotFound)
+	ErrVersionConflict            = NewApiError("user was modified by another request", ErrCodePreconditionFailed)
 )
 
 func (e *ApiError) IsRequestValidationError() bool {
@@ -10,6 +10,7 @@ import (
 	"users/internal/api"
 	appErrors "users/internal/app_errors"
 	"users/internal/models"
+	"users/pkg/validator"
 )
 
 type UserHandler struct {
@@ -40,7 +41,13 @@ func (h *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	// передаем данные в слой сервиса
 	userID, err := h.userService.RegisterUser(ctx, newUser)
 	if err != nil {
-		if errors.As(err, &appErrors.ErrUsernameOrEmailIsUsed) {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed) {
 			h.ErrorUsernameOrEmailAlreadyUsed(w)
 			return
 		}
@@ -72,7 +79,7 @@ func (h *UserHandler) GetGetUserById(w http.ResponseWriter, r *http.Request) {
 	// передаем данные в слой сервиса
 	user, err := h.userService.GetUserByID(ctx, userID)
 	if err != nil {
-		if errors.As(err, &appErrors.ErrNotFound) {
+		if errors.Is(err, appErrors.ErrNotFound) {
 			h.ErrorNotFound(w)
 			return
 		}
@@ -100,6 +107,22 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// передаем данные в слой сервиса
 	response, err := h.userService.UpdateUser(ctx, updatedUser)
 	if err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed) {
+			h.ErrorUsernameOrEmailAlreadyUsed(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrVersionConflict) {
+			h.ErrorVersionConflict(w)
+			return
+		}
+
 		h.logger.Error().Msgf("[UpdateUser] update user: %s", err)
 		h.ErrorInternalApi(w)
 		return
@@ -122,6 +145,12 @@ func (h *UserHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 
 	// передаем данные в слой сервиса
 	if err := h.userService.UpdatePassword(ctx, passwordRequest); err != nil {
+		var validationErr *validator.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorValidation(w, validationErr)
+			return
+		}
+
 		h.logger.Error().Msgf("[UpdatePassword] update password: %s", err)
 		h.ErrorInternalApi(w)
 		return
@@ -142,13 +171,32 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// версия, которую видел клиент, передается в параметре version
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+	if err != nil {
+		h.logger.Error().Msgf("[DeleteUser] get version from query: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
 	// передаем данные в слой сервиса
-	if err := h.userService.DeleteUser(ctx, userID); err != nil {
+	user, err := h.userService.DeleteUser(ctx, userID, version)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrVersionConflict) {
+			h.ErrorVersionConflict(w)
+			return
+		}
+
 		h.logger.Error().Msgf("[DeleteUser] delete user: %s", err)
 		h.ErrorInternalApi(w)
 		return
 	}
 
-	// возвращаем пользователю ответ - в данном случе просто status 200
-	h.JSONSuccessRespond(w, nil)
+	// в ответе дата, после которой учетная запись будет обезличена
+	h.JSONSuccessRespond(w, user)
 }
@@ -3,11 +3,13 @@ package rest
 import (
 	"encoding/json"
 	"net/http"
+	"users/pkg/validator"
 )
 
 type ErrorResponse struct {
-	ErrorMessage string `json:"errorMessage"`
-	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string                     `json:"errorMessage"`
+	ErrorCode    string                     `json:"errorCode"`
+	Violations   []validator.FieldViolation `json:"violations,omitempty"`
 }
 
 func (h *UserHandler) ErrorBadRequest(w http.ResponseWriter) {
@@ -26,6 +28,18 @@ func (h *UserHandler) ErrorNotFound(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusNotFound, ErrNotFound)
 }
 
+// ErrorVersionConflict отвечает 412, если пользователя изменили после того, как клиент его прочитал
+func (h *UserHandler) ErrorVersionConflict(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusPreconditionFailed, ErrVersionConflict)
+}
+
+func (h *UserHandler) ErrorValidation(w http.ResponseWriter, validationErr *validator.ValidationError) {
+	err := NewApiError("request validation failed", ErrCodeRequestValidationError)
+	err.Violations = validationErr.Violations
+
+	h.JSONErrorRespond(w, http.StatusBadRequest, err)
+}
+
 func (h *UserHandler) ErrorInternalApi(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusInternalServerError, ErrInternalApi)
 }
@@ -42,6 +56,7 @@ func (h *UserHandler) JSONErrorRespond(w http.ResponseWriter, httpCode int, err
 	data := ErrorResponse{
 		ErrorCode:    string(err.ErrCode),
 		ErrorMessage: err.Error(),
+		Violations:   err.Violations,
 	}
 
 	rawData, marshalErr := json.Marshal(data)
@@ -8,4 +8,8 @@ var (
 	ErrWrongCredentials                = errors.New("wrong credentials")
 	ErrIncorrectOldPassword            = errors.New("incorrect old password")
 	ErrPassAndConfirmationDoesNotMatch = errors.New("password and confirmation does not match")
+	ErrInvalidMFACode                  = errors.New("invalid mfa code")
+	ErrMFANotEnrolled                  = errors.New("mfa is not enrolled")
+	ErrMFAAlreadyEnabled               = errors.New("mfa is already enabled")
+	ErrVersionConflict                 = errors.New("user was modified by another request")
 )
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// права API ключей
+const (
+	ScopeTodosRead  = "todos:read"
+	ScopeTodosWrite = "todos:write"
+	ScopeUsersRead  = "users:read"
+)
+
+// APIKeyDAO - data access object - API ключ в базе данных. Хранится только хэш ключа
+type APIKeyDAO struct {
+	ID         int        `db:"id"`
+	UserID     int        `db:"user_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	KeyHash    string     `db:"key_hash"`
+	Scopes     []string   `db:"scopes"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+	// UserRole - роль владельца, заполняется при проверке ключа
+	UserRole string `db:"role"`
+}
+
+// CreateAPIKeyDTO - data transfer object - струтктура для создания API ключа
+type CreateAPIKeyDTO struct {
+	UserID    int        `validate:"required,min=1"`
+	Name      string     `validate:"required,max=100"`
+	Scopes    []string   `validate:"required,min=1,dive,oneof=todos:read todos:write users:read"`
+	ExpiresAt *time.Time `validate:"omitempty,gt"`
+}
+
+func NewEmptyCreateAPIKeyDTO() *CreateAPIKeyDTO {
+	return &CreateAPIKeyDTO{}
+}
+
+func (d *CreateAPIKeyDTO) FromGRPC(in *users.CreateAPIKeyDTO) *CreateAPIKeyDTO {
+	d.UserID = int(in.UserId)
+	d.Name = in.Name
+	d.Scopes = in.Scopes
+	if in.ExpiresAt != 0 {
+		expiresAt := time.Unix(in.ExpiresAt, 0)
+		d.ExpiresAt = &expiresAt
+	}
+	return d
+}
+
+// APIKeyDTO - data transfer object - API ключ пользователя. Key заполняется только при создании
+type APIKeyDTO struct {
+	ID         int
+	UserID     int
+	Name       string
+	Prefix     string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	Key        string
+}
+
+func (d *APIKeyDTO) FromDAO(in *APIKeyDAO) *APIKeyDTO {
+	d.ID = in.ID
+	d.UserID = in.UserID
+	d.Name = in.Name
+	d.Prefix = in.Prefix
+	d.Scopes = in.Scopes
+	d.ExpiresAt = in.ExpiresAt
+	d.LastUsedAt = in.LastUsedAt
+	d.CreatedAt = in.CreatedAt
+	return d
+}
+
+func (d *APIKeyDTO) ToGRPC() *users.APIKeyDTO {
+	return &users.APIKeyDTO{
+		Id:         int32(d.ID),
+		UserId:     int32(d.UserID),
+		Name:       d.Name,
+		Prefix:     d.Prefix,
+		Scopes:     d.Scopes,
+		ExpiresAt:  unixOrZero(d.ExpiresAt),
+		LastUsedAt: unixOrZero(d.LastUsedAt),
+		CreatedAt:  d.CreatedAt.Unix(),
+		Key:        d.Key,
+	}
+}
+
+// APIKeyPrincipalDTO - data transfer object - пользователь и права, которые дает предъявленный API ключ
+type APIKeyPrincipalDTO struct {
+	KeyID  int
+	UserID int
+	Role   string
+	Scopes []string
+}
+
+func (d *APIKeyPrincipalDTO) ToGRPC() *users.APIKeyPrincipal {
+	return &users.APIKeyPrincipal{
+		KeyId:  int32(d.KeyID),
+		UserId: int32(d.UserID),
+		Role:   d.Role,
+		Scopes: d.Scopes,
+	}
+}
+
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+
+	return t.Unix()
+}
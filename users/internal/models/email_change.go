@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// EmailChangeRequestDTO - data transfer object - струтктура для запроса смены email
+type EmailChangeRequestDTO struct {
+	UserID   int    `validate:"required,min=1"`
+	NewEmail string `validate:"required,email_address"`
+}
+
+func NewEmptyEmailChangeRequestDTO() *EmailChangeRequestDTO {
+	return &EmailChangeRequestDTO{}
+}
+
+func (d *EmailChangeRequestDTO) FromGRPC(in *users.EmailChangeRequestDTO) *EmailChangeRequestDTO {
+	d.UserID = int(in.UserId)
+	d.NewEmail = in.NewEmail
+	return d
+}
+
+// PendingEmailDAO - data access object - новый email, ожидающий подтверждения. Хранится только хэш токена
+type PendingEmailDAO struct {
+	UserID    int       `db:"id"`
+	Email     string    `db:"pending_email"`
+	TokenHash string    `db:"email_change_token_hash"`
+	ExpiresAt time.Time `db:"email_change_expires_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "users/pkg/grpc_stubs/users"
+
+// ExternalIdentityDTO - data transfer object - учетная запись пользователя у внешнего OIDC провайдера
+type ExternalIdentityDTO struct {
+	Provider      string `validate:"required,max=64"`
+	Subject       string `validate:"required,max=255"`
+	Email         string `validate:"omitempty,email_address"`
+	EmailVerified bool
+	// Username - предпочитаемое имя у провайдера, используется как основа имени нового пользователя
+	Username string
+}
+
+func NewEmptyExternalIdentityDTO() *ExternalIdentityDTO {
+	return &ExternalIdentityDTO{}
+}
+
+func (d *ExternalIdentityDTO) FromGRPC(in *users.ExternalIdentityDTO) *ExternalIdentityDTO {
+	d.Provider = in.Provider
+	d.Subject = in.Subject
+	d.Email = in.Email
+	d.EmailVerified = in.EmailVerified
+	d.Username = in.Username
+	return d
+}
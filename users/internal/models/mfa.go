@@ -0,0 +1,32 @@
+package models
+
+import "users/pkg/grpc_stubs/users"
+
+// MFACodeDTO - data transfer object - струтктура для передачи кода второго фактора: TOTP или кода восстановления
+type MFACodeDTO struct {
+	ID   int    `json:"id" example:"1" validate:"required,min=1"`
+	Code string `json:"code" example:"123456" validate:"required,min=6,max=32"`
+}
+
+func NewEmptyMFACodeDTO() *MFACodeDTO {
+	return &MFACodeDTO{}
+}
+
+func (d *MFACodeDTO) FromGRPC(in *users.MFACodeDTO) *MFACodeDTO {
+	d.ID = int(in.Id)
+	d.Code = in.Code
+	return d
+}
+
+// TOTPEnrollmentDTO - data transfer object - струтктура с секретом для подключения приложения-аутентификатора
+type TOTPEnrollmentDTO struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+func (d *TOTPEnrollmentDTO) ToGRPC() *users.TOTPEnrollment {
+	return &users.TOTPEnrollment{
+		Secret:     d.Secret,
+		OtpauthUri: d.OtpauthURI,
+	}
+}
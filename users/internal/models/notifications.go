@@ -2,10 +2,20 @@ package models
 
 const (
 	UserEventTypeEmailVerification = "user_verify_email"
+	UserEventTypeAccountLocked     = "user_account_locked"
+	// UserEventTypeEmailChangeConfirm - ссылка для подтверждения уходит на новый адрес
+	UserEventTypeEmailChangeConfirm = "user_email_change_confirm"
+	// UserEventTypeEmailChangeNotice - предупреждение о смене уходит на прежний адрес
+	UserEventTypeEmailChangeNotice = "user_email_change_notice"
 )
 
 type UserMailItem struct {
 	UserEventType string   `json:"user_event_type"`
 	Receivers     []string `json:"receivers"`
 	Link          string   `json:"link"`
+	// IP и LockedUntil заполняются для событий безопасности
+	IP          string `json:"ip,omitempty"`
+	LockedUntil string `json:"locked_until,omitempty"`
+	// NewEmail заполняется в уведомлении о смене адреса
+	NewEmail string `json:"new_email,omitempty"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// AccountLockedDTO - data transfer object - событие блокировки входа после серии неудачных попыток
+type AccountLockedDTO struct {
+	Username    string
+	Email       string
+	IP          string
+	LockedUntil time.Time
+}
+
+func NewEmptyAccountLockedDTO() *AccountLockedDTO {
+	return &AccountLockedDTO{}
+}
+
+func (d *AccountLockedDTO) FromGRPC(in *users.AccountLockedDTO) *AccountLockedDTO {
+	d.Username = in.Username
+	d.Email = in.Email
+	d.IP = in.Ip
+	d.LockedUntil = time.Unix(in.LockedUntil, 0)
+	return d
+}
@@ -1,6 +1,26 @@
 package models
 
-import "users/pkg/grpc_stubs/users"
+import (
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// роли пользователей
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// состояния учетной записи
+const (
+	UserStatusActive = "active"
+	// UserStatusDeactivated - пользователь скрыт и не может работать по API ключам, вход в систему возвращает учетную запись
+	UserStatusDeactivated = "deactivated"
+	// UserStatusPendingDeletion - учетная запись будет обезличена после delete_after, до этого вход отменяет удаление
+	UserStatusPendingDeletion = "pending_deletion"
+	// UserStatusDeleted - учетная запись обезличена, строка оставлена для ссылок из других сервисов
+	UserStatusDeleted = "deleted"
+)
 
 // UserDAO - data access object - струтктура для работы с базой данных
 type UserDAO struct {
@@ -8,14 +28,39 @@ type UserDAO struct {
 	Username string `db:"username"`
 	Password string `db:"password"`
 	Email    string `db:"email"`
+	Role     string `db:"role"`
+	// TOTPSecret - зашифрованный TOTP секрет, nil если MFA не подключалась
+	TOTPSecret  *string `db:"totp_secret"`
+	TOTPEnabled bool    `db:"totp_enabled"`
+	DisplayName string  `db:"display_name"`
+	Timezone    string  `db:"timezone"`
+	Locale      string  `db:"locale"`
+	// AvatarKey - префикс файлов аватара в хранилище gateway, nil если аватар не загружен
+	AvatarKey   *string    `db:"avatar_key"`
+	Status      string     `db:"status"`
+	DeleteAfter *time.Time `db:"delete_after"`
+	// Version растет при каждом изменении пользователя
+	Version int `db:"version"`
 }
 
 // UserDTO - data transfer object - общая струтктура для передачи данных пользователя
 type UserDTO struct {
-	ID       int    `json:"id,omitempty" example:"1"`
-	Username string `json:"username" example:"username"`
+	ID       int    `json:"id,omitempty" example:"1" validate:"required,min=1"`
+	Username string `json:"username" example:"username" validate:"required,username"`
 	Password string `json:"password,omitempty" example:"password"`
-	Email    string `json:"email,omitempty" example:"user@example.com"`
+	Email    string `json:"email,omitempty" example:"user@example.com" validate:"omitempty,email_address"`
+	Role     string `json:"role,omitempty" example:"user"`
+	// MFAEnabled - при входе нужен второй фактор
+	MFAEnabled  bool   `json:"mfa_enabled,omitempty" example:"false"`
+	DisplayName string `json:"display_name,omitempty" example:"John Doe" validate:"omitempty,max=64"`
+	Timezone    string `json:"timezone,omitempty" example:"Europe/Moscow" validate:"omitempty,timezone"`
+	Locale      string `json:"locale,omitempty" example:"ru-RU" validate:"omitempty,bcp47_language_tag"`
+	AvatarKey   string `json:"avatar_key,omitempty" example:"avatars/1/3f2a9c"`
+	Status      string `json:"status,omitempty" example:"active"`
+	// DeleteAfter - когда учетная запись будет обезличена, заполняется для запланированного удаления
+	DeleteAfter *time.Time `json:"delete_after,omitempty"`
+	// Version - текущая версия пользователя. При обновлении должна совпадать с сохраненной
+	Version int `json:"version,omitempty" example:"1"`
 }
 
 func NewEmptyUserDTO() *UserDTO {
@@ -24,10 +69,19 @@ func NewEmptyUserDTO() *UserDTO {
 
 func (d *UserDTO) ToGRPC() *users.UserDTO {
 	return &users.UserDTO{
-		Id:       int32(d.ID),
-		Username: d.Username,
-		Password: d.Password,
-		Email:    d.Email,
+		Id:          int32(d.ID),
+		Username:    d.Username,
+		Password:    d.Password,
+		Email:       d.Email,
+		Role:        d.Role,
+		MfaEnabled:  d.MFAEnabled,
+		DisplayName: d.DisplayName,
+		Timezone:    d.Timezone,
+		Locale:      d.Locale,
+		AvatarKey:   d.AvatarKey,
+		Status:      d.Status,
+		DeleteAfter: unixOrZero(d.DeleteAfter),
+		Version:     int32(d.Version),
 	}
 }
 
@@ -36,16 +90,28 @@ func (d *UserDTO) FromGRPC(in *users.UserDTO) *UserDTO {
 	d.Email = in.Email
 	d.Username = in.Username
 	d.Password = in.Password
+	d.Role = in.Role
+	d.MFAEnabled = in.MfaEnabled
+	d.DisplayName = in.DisplayName
+	d.Timezone = in.Timezone
+	d.Locale = in.Locale
+	d.AvatarKey = in.AvatarKey
+	d.Status = in.Status
+	if in.DeleteAfter != 0 {
+		deleteAfter := time.Unix(in.DeleteAfter, 0)
+		d.DeleteAfter = &deleteAfter
+	}
+	d.Version = int(in.Version)
 	return d
 }
 
 // CreateUserDTO - data transfer object - струтктура для передачи данных пользователя при создании
 type CreateUserDTO struct {
 	ID                   int    `json:"id,omitempty" example:"1"`
-	Username             string `json:"username" example:"username"`
-	Password             string `json:"password,omitempty" example:"password"`
-	PasswordConfirmation string `json:"password_confirmation,omitempty" example:"password"`
-	Email                string `json:"email,omitempty" example:"user@example.com"`
+	Username             string `json:"username" example:"username" validate:"required,username"`
+	Password             string `json:"password,omitempty" example:"Passw0rd" validate:"required,password"`
+	PasswordConfirmation string `json:"password_confirmation,omitempty" example:"Passw0rd" validate:"required,eqfield=Password"`
+	Email                string `json:"email,omitempty" example:"user@example.com" validate:"required,email_address"`
 }
 
 func NewEmptyCreateUserDTO() *CreateUserDTO {
@@ -73,10 +139,10 @@ func (d *CreateUserDTO) FromGRPC(in *users.CreateUserDTO) *CreateUserDTO {
 
 // UpdateUserPasswordDTO - data transfer object - струтктура для передачи данных пользователя при обновлении пароля
 type UpdateUserPasswordDTO struct {
-	ID                   int    `json:"id,omitempty" example:"1"`
-	OldPassword          string `json:"old_password" example:"password"`
-	Password             string `json:"password" example:"password"`
-	PasswordConfirmation string `json:"password_confirmation" example:"password"`
+	ID                   int    `json:"id,omitempty" example:"1" validate:"required,min=1"`
+	OldPassword          string `json:"old_password" example:"password" validate:"required"`
+	Password             string `json:"password" example:"Passw0rd" validate:"required,password"`
+	PasswordConfirmation string `json:"password_confirmation" example:"Passw0rd" validate:"required,eqfield=Password"`
 }
 
 func NewEmptyUpdateUserPasswordDTO() *UpdateUserPasswordDTO {
@@ -102,9 +168,9 @@ func (d *UpdateUserPasswordDTO) FromGRPC(in *users.UpdateUserPasswordDTO) *Updat
 
 // UserLoginDTO - data transfer object - струтктура для передачи данных пользователя при логине
 type UserLoginDTO struct {
-	Username string `db:"username,omitempty"`
-	Password string `db:"password"`
-	Email    string `db:"email,omitempty"`
+	Username string `db:"username,omitempty" validate:"required_without=Email"`
+	Password string `db:"password" validate:"required"`
+	Email    string `db:"email,omitempty" validate:"required_without=Username,omitempty,email_address"`
 }
 
 func NewEmptyUserLoginDTO() *UserLoginDTO {
@@ -126,6 +192,22 @@ func (d *UserLoginDTO) FromGRPC(in *users.UserLoginDTO) *UserLoginDTO {
 	return d
 }
 
+// UpdateUserRoleDTO - data transfer object - струтктура для смены роли пользователя
+type UpdateUserRoleDTO struct {
+	ID   int    `json:"id" example:"1" validate:"required,min=1"`
+	Role string `json:"role" example:"admin" validate:"required,oneof=user admin"`
+}
+
+func NewEmptyUpdateUserRoleDTO() *UpdateUserRoleDTO {
+	return &UpdateUserRoleDTO{}
+}
+
+func (d *UpdateUserRoleDTO) FromGRPC(in *users.UpdateUserRoleDTO) *UpdateUserRoleDTO {
+	d.ID = int(in.Id)
+	d.Role = in.Role
+	return d
+}
+
 // UserTokens - струтктура для передачи токенов пользователя
 type UserTokens struct {
 	AccessToken  string `json:"access_token,omitempty"`
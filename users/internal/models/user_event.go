@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserEventTypeDeleted - учетная запись обезличена, другие сервисы удаляют или переназначают данные пользователя
+const UserEventTypeDeleted = "user.deleted"
+
+// UserEvent - событие об изменении учетной записи для других сервисов.
+// В отличие от UserMailItem не превращается в письмо и уходит в отдельную очередь
+type UserEvent struct {
+	EventType  string    `json:"event_type"`
+	UserID     int       `json:"user_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
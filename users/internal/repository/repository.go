@@ -4,12 +4,37 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
 	"users/internal/models"
 )
 
+// pgUniqueViolation - SQLSTATE нарушения уникального индекса
+const pgUniqueViolation = "23505"
+
+// userUniqueIndexes - индексы, нарушение которых означает занятое имя или email
+var userUniqueIndexes = map[string]bool{
+	"users_username_lower_key":      true,
+	"users_email_lower_key":         true,
+	"users_pending_email_lower_key": true,
+}
+
+// mapUniqueViolation переводит нарушение уникальности имени или email в ErrUsernameOrEmailIsUsed
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation && userUniqueIndexes[pgErr.ConstraintName] {
+		return fmt.Errorf("%s: %w", pgErr.ConstraintName, appErrors.ErrUsernameOrEmailIsUsed)
+	}
+
+	return err
+}
+
 type UserRepository struct {
 	conn *pgxpool.Pool
 }
@@ -36,11 +61,13 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.CreateUser
 	err := r.conn.QueryRow(ctx, sql, user.Username, user.Password, user.Email).
 		Scan(&userID)
 	if err != nil {
-		return 0, err
+		return 0, mapUniqueViolation(err)
 	}
 	return userID, nil
 }
 
+// UpdateUser сохраняет профиль, только если версия пользователя не изменилась с момента чтения,
+// иначе возвращает ErrVersionConflict. При успехе user.Version указывает на новую версию
 func (r *UserRepository) UpdateUser(ctx context.Context, user *models.UserDAO) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateUser")
 	defer span.Finish()
@@ -50,12 +77,56 @@ func (r *UserRepository) UpdateUser(ctx context.Context, user *models.UserDAO) e
             users
         SET 
 			username = $2, 
-			email = $3
+			email = $3,
+			display_name = $4,
+			timezone = $5,
+			locale = $6,
+			version = version + 1
         WHERE 
-            id = $1
+            id = $1 AND version = $7
     `
-	_, err := r.conn.Exec(ctx, sql, user.ID, user.Username, user.Email)
-	return err
+	tag, err := r.conn.Exec(ctx, sql, user.ID, user.Username, user.Email, user.DisplayName, user.Timezone, user.Locale, user.Version)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return appErrors.ErrVersionConflict
+	}
+
+	user.Version++
+	return nil
+}
+
+// UpdateAvatar меняет ключ аватара и возвращает прежний, чтобы старые файлы можно было удалить
+func (r *UserRepository) UpdateAvatar(ctx context.Context, userID int, avatarKey *string) (*string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateAvatar")
+	defer span.Finish()
+
+	// прежнее значение читается в том же запросе, иначе два параллельных обновления потеряют файлы
+	query := `
+        UPDATE 
+            users u
+        SET 
+            avatar_key = $2, 
+            version = u.version + 1
+        FROM 
+            (SELECT id, avatar_key FROM users WHERE id = $1 FOR UPDATE) prev
+        WHERE 
+            u.id = prev.id
+        RETURNING 
+            prev.avatar_key
+    `
+	var previous *string
+	err := r.conn.QueryRow(ctx, query, userID, avatarKey).Scan(&previous)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+
+		return nil, err
+	}
+
+	return previous, nil
 }
 
 func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, newPassword string) error {
@@ -74,18 +145,159 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, newPass
 	return err
 }
 
-func (r *UserRepository) DeleteUser(ctx context.Context, userID int) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DeleteUser")
+// SetUserStatus меняет состояние учетной записи. deleteAfter задается только для запланированного удаления
+func (r *UserRepository) SetUserStatus(ctx context.Context, userID int, status string, deleteAfter *time.Time) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetUserStatus")
 	defer span.Finish()
 
-	sql := `
-        DELETE FROM 
-		    users
+	query := `
+        UPDATE 
+            users
+        SET 
+            status = $2, 
+            delete_after = $3, 
+            version = version + 1
         WHERE 
-            id = $1
+            id = $1 AND status <> 'deleted'
     `
-	_, err := r.conn.Exec(ctx, sql, userID)
-	return err
+	tag, err := r.conn.Exec(ctx, query, userID, status, deleteAfter)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ScheduleUserDeletion планирует удаление, только если версия пользователя не изменилась.
+// Возвращает sql.ErrNoRows, если пользователя нет, и ErrVersionConflict, если версия другая
+func (r *UserRepository) ScheduleUserDeletion(ctx context.Context, userID, version int, deleteAfter time.Time) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ScheduleUserDeletion")
+	defer span.Finish()
+
+	query := `
+        UPDATE 
+            users
+        SET 
+            status = $3, 
+            delete_after = $4, 
+            version = version + 1
+        WHERE 
+            id = $1 AND version = $2 AND status <> 'deleted'
+    `
+	tag, err := r.conn.Exec(ctx, query, userID, version, models.UserStatusPendingDeletion, deleteAfter)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	// строка не обновлена: пользователя нет или его уже изменили
+	var exists bool
+	err = r.conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND status <> 'deleted')`, userID).
+		Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	return appErrors.ErrVersionConflict
+}
+
+// PurgeDeletedUsers обезличивает пользователей, у которых истек срок до удаления, и удаляет их ключи и привязки.
+// Строка пользователя остается, чтобы ссылки на него из других сервисов не повисли.
+// beforeCommit вызывается до фиксации транзакции: если событие об удалении не отправлено, пользователи
+// останутся в очереди на удаление и будут обработаны повторно
+func (r *UserRepository) PurgeDeletedUsers(ctx context.Context, limit int, beforeCommit func(userIDs []int) error) ([]int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.PurgeDeletedUsers")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// SKIP LOCKED позволяет нескольким экземплярам сервиса удалять пользователей параллельно
+	rows, err := tx.Query(ctx, `
+        SELECT 
+            id 
+        FROM 
+            users 
+        WHERE 
+            status = 'pending_deletion' AND delete_after <= now()
+        ORDER BY 
+            delete_after
+        LIMIT $1
+        FOR UPDATE SKIP LOCKED
+    `, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int, 0, limit)
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) == 0 {
+		return userIDs, nil
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE 
+            users
+        SET 
+            status = 'deleted', 
+            username = 'deleted_' || id, 
+            email = NULL, 
+            password = '', 
+            display_name = '', 
+            avatar_key = NULL, 
+            totp_secret = NULL, 
+            totp_enabled = false, 
+            pending_email = NULL, 
+            email_change_token_hash = NULL, 
+            email_change_expires_at = NULL, 
+            delete_after = NULL, 
+            deleted_at = now(), 
+            version = version + 1
+        WHERE 
+            id = ANY($1)
+    `, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, query := range []string{
+		`DELETE FROM user_api_keys WHERE user_id = ANY($1)`,
+		`DELETE FROM user_external_identities WHERE user_id = ANY($1)`,
+		`DELETE FROM user_recovery_codes WHERE user_id = ANY($1)`,
+	} {
+		if _, err := tx.Exec(ctx, query, userIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := beforeCommit(userIDs); err != nil {
+		return nil, err
+	}
+
+	return userIDs, tx.Commit(ctx)
 }
 
 func (r *UserRepository) GetUserByID(ctx context.Context, userID int) (*models.UserDAO, error) {
@@ -93,20 +305,35 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID int) (*models.U
 	defer span.Finish()
 
 	var user models.UserDAO
-	sql := `
+	query := `
         SELECT 
             id, 
             username, 
             password, 
-            email
+            email,
+            role,
+            totp_secret,
+            totp_enabled,
+            display_name,
+            timezone,
+            locale,
+            avatar_key,
+            status,
+            delete_after,
+            version
         FROM 
             users
         WHERE 
-            id = $1
+            id = $1 AND status <> 'deleted'
     `
-	err := r.conn.QueryRow(ctx, sql, userID).
-		Scan(&user.ID, &user.Username, &user.Password, &user.Email)
+	err := r.conn.QueryRow(ctx, query, userID).
+		Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Role, &user.TOTPSecret, &user.TOTPEnabled,
+			&user.DisplayName, &user.Timezone, &user.Locale, &user.AvatarKey, &user.Status, &user.DeleteAfter, &user.Version)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+
 		return nil, err
 	}
 	return &user, nil
@@ -119,19 +346,24 @@ func (r *UserRepository) GetUserByUsernameOrEmail(ctx context.Context, username,
 	var user models.UserDAO
 	// создадим конструктор квери. определим, что и откуда забрать
 	queryBuilder := sq.
-		Select("id", "username", "password", "email").
-		From("users")
+		Select("id", "username", "password", "email", "role", "totp_secret", "totp_enabled",
+			"display_name", "timezone", "locale", "avatar_key", "status", "delete_after", "version").
+		From("users").
+		Where(sq.NotEq{"status": models.UserStatusDeleted})
 
 	// убедимся, что username или email не являются пустыми строками перед добавлением их в запрос
 	if username != "" && email == "" {
-		queryBuilder = queryBuilder.Where(sq.Eq{"username": username})
+		queryBuilder = queryBuilder.Where("lower(username) = lower(?)", username)
 	}
 	if email != "" && username == "" {
-		queryBuilder = queryBuilder.Where(sq.Eq{"email": email})
+		queryBuilder = queryBuilder.Where("lower(email) = lower(?)", email)
 	}
 
 	if email != "" && username != "" {
-		queryBuilder = queryBuilder.Where(sq.Or{sq.Eq{"username": username}, sq.Eq{"email": email}})
+		queryBuilder = queryBuilder.Where(sq.Or{
+			sq.Expr("lower(username) = lower(?)", username),
+			sq.Expr("lower(email) = lower(?)", email),
+		})
 	}
 
 	// создадим квери и аргументы для нее, зададим формат плэйсхолдеров в виде доллара
@@ -142,9 +374,10 @@ func (r *UserRepository) GetUserByUsernameOrEmail(ctx context.Context, username,
 
 	// выполним запрос с созданной квери и подготовленными аргументами
 	err = r.conn.QueryRow(ctx, query, args...).
-		Scan(&user.ID, &user.Username, &user.Password, &user.Email)
+		Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Role, &user.TOTPSecret, &user.TOTPEnabled,
+			&user.DisplayName, &user.Timezone, &user.Locale, &user.AvatarKey, &user.Status, &user.DeleteAfter, &user.Version)
 	if err != nil {
-		if errors.As(err, &sql.ErrNoRows) {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, sql.ErrNoRows
 		}
 
@@ -164,16 +397,555 @@ func (r *UserRepository) GetUserByUsername(ctx context.Context, username string)
             id, 
             username, 
             password, 
-            email
+            email,
+            role,
+            totp_secret,
+            totp_enabled,
+            display_name,
+            timezone,
+            locale,
+            avatar_key,
+            status,
+            delete_after,
+            version
         FROM 
             users
         WHERE 
-            username = $1
+            lower(username) = lower($1) AND status <> 'deleted'
     `
 	err := r.conn.QueryRow(ctx, sql, username).
-		Scan(&user.ID, &user.Username, &user.Password, &user.Email)
+		Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Role, &user.TOTPSecret, &user.TOTPEnabled,
+			&user.DisplayName, &user.Timezone, &user.Locale, &user.AvatarKey, &user.Status, &user.DeleteAfter, &user.Version)
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
+
+func (r *UserRepository) ListUsers(ctx context.Context, limit, offset int) ([]*models.UserDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListUsers")
+	defer span.Finish()
+
+	query, args, err := sq.
+		Select("id", "username", "email", "role", "display_name", "avatar_key", "status", "delete_after", "version").
+		From("users").
+		Where(sq.NotEq{"status": models.UserStatusDeleted}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*models.UserDAO, 0, limit)
+	for rows.Next() {
+		var user models.UserDAO
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.Role, &user.DisplayName, &user.AvatarKey, &user.Status, &user.DeleteAfter, &user.Version); err != nil {
+			return nil, err
+		}
+
+		result = append(result, &user)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *UserRepository) UpdateUserRole(ctx context.Context, userID int, role string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateUserRole")
+	defer span.Finish()
+
+	sql := `
+        UPDATE 
+            users
+        SET 
+            role = $2, 
+            version = version + 1
+        WHERE 
+            id = $1
+    `
+	_, err := r.conn.Exec(ctx, sql, userID, role)
+	return err
+}
+
+// SetTOTPSecret сохраняет новый, еще не подтвержденный секрет
+func (r *UserRepository) SetTOTPSecret(ctx context.Context, userID int, encryptedSecret string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetTOTPSecret")
+	defer span.Finish()
+
+	sql := `
+        UPDATE 
+            users
+        SET 
+            totp_secret = $2
+        WHERE 
+            id = $1
+    `
+	_, err := r.conn.Exec(ctx, sql, userID, encryptedSecret)
+	return err
+}
+
+// EnableTOTP включает MFA и заменяет коды восстановления в одной транзакции
+func (r *UserRepository) EnableTOTP(ctx context.Context, userID int, recoveryCodeHashes []string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.EnableTOTP")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE users SET totp_enabled = true, version = version + 1 WHERE id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	queryBuilder := sq.Insert("user_recovery_codes").Columns("user_id", "code_hash")
+	for _, hash := range recoveryCodeHashes {
+		queryBuilder = queryBuilder.Values(userID, hash)
+	}
+
+	query, args, err := queryBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DisableTOTP выключает MFA, удаляет секрет и коды восстановления
+func (r *UserRepository) DisableTOTP(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DisableTOTP")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE users SET totp_enabled = false, totp_secret = NULL, version = version + 1 WHERE id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UseRecoveryCode помечает код восстановления использованным. Возвращает false, если кода нет или он уже использован
+func (r *UserRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UseRecoveryCode")
+	defer span.Finish()
+
+	sql := `
+        UPDATE 
+            user_recovery_codes
+        SET 
+            used_at = now()
+        WHERE 
+            user_id = $1 AND code_hash = $2 AND used_at IS NULL
+    `
+	tag, err := r.conn.Exec(ctx, sql, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// UseTOTPStep запоминает окно принятого TOTP кода. Возвращает false, если код этого или более позднего окна
+// уже принимался: так один код нельзя использовать дважды, пока он еще действителен
+func (r *UserRepository) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UseTOTPStep")
+	defer span.Finish()
+
+	sql := `
+        UPDATE 
+            users
+        SET 
+            totp_last_step = $2
+        WHERE 
+            id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)
+    `
+	tag, err := r.conn.Exec(ctx, sql, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetUserByExternalIdentity ищет пользователя, к которому привязана учетная запись провайдера
+func (r *UserRepository) GetUserByExternalIdentity(ctx context.Context, provider, subject string) (*models.UserDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUserByExternalIdentity")
+	defer span.Finish()
+
+	var user models.UserDAO
+	query := `
+        SELECT 
+            u.id, 
+            u.username, 
+            u.password, 
+            u.email,
+            u.role,
+            u.totp_secret,
+            u.totp_enabled,
+            u.display_name,
+            u.timezone,
+            u.locale,
+            u.avatar_key,
+            u.status,
+            u.delete_after,
+            u.version
+        FROM 
+            users u
+            JOIN user_external_identities ei ON ei.user_id = u.id
+        WHERE 
+            ei.provider = $1 AND ei.subject = $2 AND u.status <> 'deleted'
+    `
+	err := r.conn.QueryRow(ctx, query, provider, subject).
+		Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Role, &user.TOTPSecret, &user.TOTPEnabled,
+			&user.DisplayName, &user.Timezone, &user.Locale, &user.AvatarKey, &user.Status, &user.DeleteAfter, &user.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateExternalIdentity привязывает учетную запись провайдера к существующему пользователю
+func (r *UserRepository) CreateExternalIdentity(ctx context.Context, userID int, identity *models.ExternalIdentityDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateExternalIdentity")
+	defer span.Finish()
+
+	sql := `
+        INSERT INTO 
+            user_external_identities (
+               user_id, 
+               provider, 
+               subject, 
+               email
+            )
+        VALUES 
+            ($1, $2, $3, $4)
+    `
+	_, err := r.conn.Exec(ctx, sql, userID, identity.Provider, identity.Subject, identity.Email)
+	return err
+}
+
+// CreateUserWithExternalIdentity создает пользователя и привязывает к нему учетную запись провайдера в одной транзакции
+func (r *UserRepository) CreateUserWithExternalIdentity(ctx context.Context, user *models.CreateUserDTO, identity *models.ExternalIdentityDTO) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateUserWithExternalIdentity")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	err = tx.QueryRow(ctx, `INSERT INTO users (username, password, email) VALUES ($1, $2, $3) RETURNING id`,
+		user.Username, user.Password, user.Email).
+		Scan(&userID)
+	if err != nil {
+		return 0, mapUniqueViolation(err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO user_external_identities (user_id, provider, subject, email) VALUES ($1, $2, $3, $4)`,
+		userID, identity.Provider, identity.Subject, identity.Email)
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, tx.Commit(ctx)
+}
+
+// CreateAPIKey сохраняет API ключ и возвращает его ID и время создания
+func (r *UserRepository) CreateAPIKey(ctx context.Context, key *models.APIKeyDAO) (int, time.Time, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateAPIKey")
+	defer span.Finish()
+
+	var (
+		keyID     int
+		createdAt time.Time
+	)
+	sql := `
+        INSERT INTO 
+            user_api_keys (
+               user_id, 
+               name, 
+               prefix, 
+               key_hash, 
+               scopes, 
+               expires_at
+            )
+        VALUES 
+            ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at
+    `
+	err := r.conn.QueryRow(ctx, sql, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt).
+		Scan(&keyID, &createdAt)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return keyID, createdAt, nil
+}
+
+// ListAPIKeys возвращает ключи пользователя, новые первыми
+func (r *UserRepository) ListAPIKeys(ctx context.Context, userID int) ([]*models.APIKeyDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListAPIKeys")
+	defer span.Finish()
+
+	sql := `
+        SELECT 
+            id, 
+            user_id, 
+            name, 
+            prefix, 
+            scopes, 
+            expires_at, 
+            last_used_at, 
+            created_at
+        FROM 
+            user_api_keys
+        WHERE 
+            user_id = $1
+        ORDER BY 
+            created_at DESC, id DESC
+    `
+	rows, err := r.conn.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*models.APIKeyDAO, 0)
+	for rows.Next() {
+		var key models.APIKeyDAO
+		err := rows.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Scopes, &key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &key)
+	}
+
+	return result, rows.Err()
+}
+
+// DeleteAPIKey удаляет ключ пользователя. Возвращает false, если такого ключа у пользователя нет
+func (r *UserRepository) DeleteAPIKey(ctx context.Context, keyID, userID int) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DeleteAPIKey")
+	defer span.Finish()
+
+	sql := `
+        DELETE FROM 
+            user_api_keys
+        WHERE 
+            id = $1 AND user_id = $2
+    `
+	tag, err := r.conn.Exec(ctx, sql, keyID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetAPIKeyByHash ищет ключ по хэшу вместе с ролью владельца
+func (r *UserRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKeyDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetAPIKeyByHash")
+	defer span.Finish()
+
+	var key models.APIKeyDAO
+	query := `
+        SELECT 
+            k.id, 
+            k.user_id, 
+            k.name, 
+            k.prefix, 
+            k.scopes, 
+            k.expires_at, 
+            k.last_used_at, 
+            k.created_at,
+            u.role
+        FROM 
+            user_api_keys k
+            JOIN users u ON u.id = k.user_id
+        WHERE 
+            k.key_hash = $1 AND u.status = 'active'
+    `
+	err := r.conn.QueryRow(ctx, query, keyHash).
+		Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Scopes, &key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt, &key.UserRole)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+
+		return nil, err
+	}
+	return &key, nil
+}
+
+// TouchAPIKey обновляет время последнего использования ключа.
+// Время пишется не чаще раза в минуту, чтобы частые запросы CI не нагружали базу
+func (r *UserRepository) TouchAPIKey(ctx context.Context, keyID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.TouchAPIKey")
+	defer span.Finish()
+
+	sql := `
+        UPDATE 
+            user_api_keys
+        SET 
+            last_used_at = now()
+        WHERE 
+            id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+    `
+	_, err := r.conn.Exec(ctx, sql, keyID)
+	return err
+}
+
+// EmailIsUsed проверяет, занят ли email другим пользователем: как текущий адрес или как неподтвержденный новый
+func (r *UserRepository) EmailIsUsed(ctx context.Context, email string, exceptUserID int) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.EmailIsUsed")
+	defer span.Finish()
+
+	query := `
+        SELECT EXISTS (
+            SELECT 
+                1 
+            FROM 
+                users 
+            WHERE 
+                id <> $2 
+                AND (lower(email) = lower($1) OR (lower(pending_email) = lower($1) AND email_change_expires_at > now()))
+        )
+    `
+	var used bool
+	err := r.conn.QueryRow(ctx, query, email, exceptUserID).Scan(&used)
+	return used, err
+}
+
+// SetPendingEmail сохраняет новый email до подтверждения. Прежний неподтвержденный запрос пользователя заменяется
+func (r *UserRepository) SetPendingEmail(ctx context.Context, pending *models.PendingEmailDAO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetPendingEmail")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// просроченный запрос другого пользователя не должен держать адрес в уникальном индексе
+	_, err = tx.Exec(ctx, `
+        UPDATE 
+            users
+        SET 
+            pending_email = NULL, 
+            email_change_token_hash = NULL, 
+            email_change_expires_at = NULL
+        WHERE 
+            lower(pending_email) = lower($1) AND email_change_expires_at <= now()
+    `, pending.Email)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE 
+            users
+        SET 
+            pending_email = $2, 
+            email_change_token_hash = $3, 
+            email_change_expires_at = $4
+        WHERE 
+            id = $1
+    `, pending.UserID, pending.Email, pending.TokenHash, pending.ExpiresAt)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetPendingEmail ищет действующий запрос смены email по хэшу токена
+func (r *UserRepository) GetPendingEmail(ctx context.Context, tokenHash string) (*models.PendingEmailDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetPendingEmail")
+	defer span.Finish()
+
+	var pending models.PendingEmailDAO
+	query := `
+        SELECT 
+            id, 
+            pending_email, 
+            email_change_token_hash, 
+            email_change_expires_at
+        FROM 
+            users
+        WHERE 
+            email_change_token_hash = $1 AND email_change_expires_at > now()
+    `
+	err := r.conn.QueryRow(ctx, query, tokenHash).
+		Scan(&pending.UserID, &pending.Email, &pending.TokenHash, &pending.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// ConfirmEmailChange заменяет email на подтвержденный. Возвращает false, если токен уже использован или истек
+func (r *UserRepository) ConfirmEmailChange(ctx context.Context, userID int, tokenHash string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ConfirmEmailChange")
+	defer span.Finish()
+
+	sql := `
+        UPDATE 
+            users
+        SET 
+            email = pending_email, 
+            pending_email = NULL, 
+            email_change_token_hash = NULL, 
+            email_change_expires_at = NULL, 
+            version = version + 1
+        WHERE 
+            id = $1 AND email_change_token_hash = $2 AND email_change_expires_at > now()
+    `
+	tag, err := r.conn.Exec(ctx, sql, userID, tokenHash)
+	if err != nil {
+		return false, mapUniqueViolation(err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/ctxutil"
+)
+
+// DeleteUser планирует удаление учетной записи. До конца срока ожидания вход в систему отменяет удаление,
+// после него PurgeDeletedUsers обезличивает пользователя. version - версия пользователя, которую видел клиент
+func (s *UserService) DeleteUser(ctx context.Context, userID, version int) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteUser")
+	defer span.Finish()
+
+	deleteAfter := time.Now().Add(s.deletion.GracePeriod).UTC()
+	err := s.userRepo.ScheduleUserDeletion(ctx, userID, version, deleteAfter)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("[DeleteUser] %w", appErrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("[DeleteUser] schedule deletion:%w", err)
+	}
+
+	return &models.UserDTO{
+		ID:          userID,
+		Status:      models.UserStatusPendingDeletion,
+		DeleteAfter: &deleteAfter,
+		Version:     version + 1,
+	}, nil
+}
+
+// DeactivateUser скрывает учетную запись без удаления данных. Вход в систему возвращает ее
+func (s *UserService) DeactivateUser(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeactivateUser")
+	defer span.Finish()
+
+	err := s.userRepo.SetUserStatus(ctx, userID, models.UserStatusDeactivated, nil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("[DeactivateUser] %w", appErrors.ErrNotFound)
+		}
+		return fmt.Errorf("[DeactivateUser] deactivate:%w", err)
+	}
+
+	return nil
+}
+
+// PurgeDeletedUsers обезличивает пользователей с истекшим сроком ожидания и сообщает об этом другим сервисам.
+// Возвращает число обработанных пользователей, чтобы вызывающий мог повторить, пока очередь не опустеет
+func (s *UserService) PurgeDeletedUsers(ctx context.Context) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.PurgeDeletedUsers")
+	defer span.Finish()
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+
+	// события отправляются до фиксации: при ошибке пользователи будут обработаны повторно,
+	// поэтому получатели должны обрабатывать событие идемпотентно
+	purged, err := s.userRepo.PurgeDeletedUsers(ctx, s.deletion.BatchSize, func(userIDs []int) error {
+		for _, userID := range userIDs {
+			data, err := json.Marshal(models.UserEvent{
+				EventType:  models.UserEventTypeDeleted,
+				UserID:     userID,
+				OccurredAt: time.Now().UTC(),
+			})
+			if err != nil {
+				return fmt.Errorf("marshal user deleted event:%w", err)
+			}
+
+			if err := s.userEventsProducer.Publish(data, requestID); err != nil {
+				return fmt.Errorf("publish user deleted event:%w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("[PurgeDeletedUsers] purge:%w", err)
+	}
+
+	return len(purged), nil
+}
+
+// reactivateUser возвращает деактивированную или ожидающую удаления учетную запись после успешного входа
+func (s *UserService) reactivateUser(ctx context.Context, user *models.UserDAO) error {
+	if user.Status != models.UserStatusDeactivated && user.Status != models.UserStatusPendingDeletion {
+		return nil
+	}
+
+	if err := s.userRepo.SetUserStatus(ctx, user.ID, models.UserStatusActive, nil); err != nil {
+		return fmt.Errorf("reactivate user:%w", err)
+	}
+
+	user.Status = models.UserStatusActive
+	user.DeleteAfter = nil
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	"time"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+const (
+	// apiKeyPrefix отличает API ключи от других секретов, например при поиске утечек в логах и репозиториях
+	apiKeyPrefix = "tdk_"
+	// apiKeyDisplayLen - сколько первых символов ключа хранится открыто, чтобы пользователь узнал ключ в списке
+	apiKeyDisplayLen = 12
+)
+
+// CreateAPIKey создает API ключ. Ключ возвращается только здесь, в базе хранится его хэш
+func (s *UserService) CreateAPIKey(ctx context.Context, request *models.CreateAPIKeyDTO) (*models.APIKeyDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateAPIKey")
+	defer span.Finish()
+
+	if err := s.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[CreateAPIKey] validate:%w", err)
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("[CreateAPIKey] generate key:%w", err)
+	}
+
+	dao := &models.APIKeyDAO{
+		UserID:    request.UserID,
+		Name:      request.Name,
+		Prefix:    key[:apiKeyDisplayLen],
+		KeyHash:   hashAPIKey(key),
+		Scopes:    request.Scopes,
+		ExpiresAt: request.ExpiresAt,
+	}
+
+	dao.ID, dao.CreatedAt, err = s.userRepo.CreateAPIKey(ctx, dao)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateAPIKey] store key:%w", err)
+	}
+
+	result := new(models.APIKeyDTO).FromDAO(dao)
+	result.Key = key
+
+	return result, nil
+}
+
+// ListAPIKeys возвращает ключи пользователя без самих ключей
+func (s *UserService) ListAPIKeys(ctx context.Context, userID int) ([]*models.APIKeyDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListAPIKeys")
+	defer span.Finish()
+
+	keys, err := s.userRepo.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListAPIKeys] list keys:%w", err)
+	}
+
+	result := make([]*models.APIKeyDTO, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, new(models.APIKeyDTO).FromDAO(key))
+	}
+
+	return result, nil
+}
+
+// RevokeAPIKey удаляет ключ. Чужой ключ для пользователя не существует
+func (s *UserService) RevokeAPIKey(ctx context.Context, keyID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RevokeAPIKey")
+	defer span.Finish()
+
+	deleted, err := s.userRepo.DeleteAPIKey(ctx, keyID, userID)
+	if err != nil {
+		return fmt.Errorf("[RevokeAPIKey] delete key:%w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("[RevokeAPIKey] %w", appErrors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey проверяет предъявленный ключ и возвращает его владельца и права
+func (s *UserService) AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKeyPrincipalDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AuthenticateAPIKey")
+	defer span.Finish()
+
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, fmt.Errorf("[AuthenticateAPIKey] malformed key: %w", appErrors.ErrWrongCredentials)
+	}
+
+	stored, err := s.userRepo.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("[AuthenticateAPIKey] unknown key: %w", appErrors.ErrWrongCredentials)
+		}
+		return nil, fmt.Errorf("[AuthenticateAPIKey] get key:%w", err)
+	}
+
+	if stored.ExpiresAt != nil && !time.Now().Before(*stored.ExpiresAt) {
+		return nil, fmt.Errorf("[AuthenticateAPIKey] key expired: %w", appErrors.ErrWrongCredentials)
+	}
+
+	// время последнего использования нужно только для списка ключей, ошибку не считаем критичной
+	_ = s.userRepo.TouchAPIKey(ctx, stored.ID)
+
+	return &models.APIKeyPrincipalDTO{
+		KeyID:  stored.ID,
+		UserID: stored.UserID,
+		Role:   stored.UserRole,
+		Scopes: stored.Scopes,
+	}, nil
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashAPIKey - у ключа 256 бит энтропии, поэтому медленный хэш как для паролей не нужен,
+// а поиск по sha256 позволяет найти ключ одним запросом
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"net/url"
+	"time"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/ctxutil"
+	"users/pkg/validator"
+)
+
+// errEmailChangeNeedsConfirmation - email нельзя сменить обычным обновлением профиля
+var errEmailChangeNeedsConfirmation = validator.NewValidationError(validator.FieldViolation{
+	Field:   "email",
+	Message: "can only be changed with an email change request",
+})
+
+// RequestEmailChange сохраняет новый email до подтверждения, отправляет ссылку на новый адрес
+// и предупреждение на прежний. Адрес в профиле меняется только в ConfirmEmailChange
+func (s *UserService) RequestEmailChange(ctx context.Context, request *models.EmailChangeRequestDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestEmailChange")
+	defer span.Finish()
+
+	if err := s.validator.Validate(request); err != nil {
+		return fmt.Errorf("[RequestEmailChange] validate:%w", err)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("[RequestEmailChange] get user:%w", appErrors.ErrNotFound)
+		}
+		return fmt.Errorf("[RequestEmailChange] get user:%w", err)
+	}
+
+	if request.NewEmail == user.Email {
+		return fmt.Errorf("[RequestEmailChange] %w", validator.NewValidationError(validator.FieldViolation{
+			Field:   "new_email",
+			Message: "must differ from the current email",
+		}))
+	}
+
+	used, err := s.userRepo.EmailIsUsed(ctx, request.NewEmail, user.ID)
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] check email:%w", err)
+	}
+	if used {
+		return fmt.Errorf("[RequestEmailChange] check email:%w", appErrors.ErrUsernameOrEmailIsUsed)
+	}
+
+	token, err := generateEmailChangeToken()
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] generate token:%w", err)
+	}
+
+	err = s.userRepo.SetPendingEmail(ctx, &models.PendingEmailDAO{
+		UserID:    user.ID,
+		Email:     request.NewEmail,
+		TokenHash: hashAPIKey(token),
+		ExpiresAt: time.Now().Add(s.emailChange.TTL),
+	})
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] store pending email:%w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+
+	confirm, err := json.Marshal(models.UserMailItem{
+		UserEventType: models.UserEventTypeEmailChangeConfirm,
+		Receivers:     []string{request.NewEmail},
+		Link:          s.emailChangeLink(token),
+	})
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] marshal confirm mssg:%w", err)
+	}
+	if err := s.userRabbitProducer.Publish(confirm, requestID); err != nil {
+		return fmt.Errorf("[RequestEmailChange] publish confirm mssg:%w", err)
+	}
+
+	// владелец прежнего адреса узнает о смене, даже если запрос отправил не он
+	notice, err := json.Marshal(models.UserMailItem{
+		UserEventType: models.UserEventTypeEmailChangeNotice,
+		Receivers:     []string{user.Email},
+		NewEmail:      request.NewEmail,
+	})
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] marshal notice mssg:%w", err)
+	}
+	if err := s.userRabbitProducer.Publish(notice, requestID); err != nil {
+		return fmt.Errorf("[RequestEmailChange] publish notice mssg:%w", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange применяет новый email по токену из письма.
+// Неизвестный, использованный и просроченный токены неотличимы для клиента
+func (s *UserService) ConfirmEmailChange(ctx context.Context, token string) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmEmailChange")
+	defer span.Finish()
+
+	if token == "" {
+		return nil, fmt.Errorf("[ConfirmEmailChange] empty token:%w", appErrors.ErrNotFound)
+	}
+	tokenHash := hashAPIKey(token)
+
+	pending, err := s.userRepo.GetPendingEmail(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("[ConfirmEmailChange] get pending email:%w", appErrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("[ConfirmEmailChange] get pending email:%w", err)
+	}
+
+	// пока письмо шло, адрес мог занять другой пользователь
+	used, err := s.userRepo.EmailIsUsed(ctx, pending.Email, pending.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmEmailChange] check email:%w", err)
+	}
+	if used {
+		return nil, fmt.Errorf("[ConfirmEmailChange] check email:%w", appErrors.ErrUsernameOrEmailIsUsed)
+	}
+
+	changed, err := s.userRepo.ConfirmEmailChange(ctx, pending.UserID, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmEmailChange] change email:%w", err)
+	}
+	if !changed {
+		return nil, fmt.Errorf("[ConfirmEmailChange] change email:%w", appErrors.ErrNotFound)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, pending.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmEmailChange] get user:%w", err)
+	}
+
+	return userDTOFromDAO(user), nil
+}
+
+func (s *UserService) emailChangeLink(token string) string {
+	return s.emailChange.ConfirmURL + "?token=" + url.QueryEscape(token)
+}
+
+// generateEmailChangeToken - токен уходит только в письмо, в базе хранится его sha256 как у API ключей
+func generateEmailChangeToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// ограничения имени, которое подбирается пользователю при первом входе через провайдера
+const (
+	externalUsernameMinLen   = 3
+	externalUsernameMaxLen   = 32
+	externalUsernameAttempts = 5
+)
+
+// GetUserByExternalIdentity возвращает пользователя, к которому привязана учетная запись провайдера
+func (s *UserService) GetUserByExternalIdentity(ctx context.Context, identity *models.ExternalIdentityDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUserByExternalIdentity")
+	defer span.Finish()
+
+	if err := s.validator.Validate(identity); err != nil {
+		return nil, fmt.Errorf("[GetUserByExternalIdentity] validate:%w", err)
+	}
+
+	user, err := s.userRepo.GetUserByExternalIdentity(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("[GetUserByExternalIdentity] %w", appErrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("[GetUserByExternalIdentity] get user:%w", err)
+	}
+
+	// вход через провайдера возвращает учетную запись так же, как вход по паролю
+	if err := s.reactivateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("[GetUserByExternalIdentity] %w", err)
+	}
+
+	return userDTOFromDAO(user), nil
+}
+
+// LinkExternalIdentity привязывает учетную запись провайдера к пользователю.
+// Существующий пользователь находится по email только если провайдер его подтвердил,
+// иначе можно было бы захватить чужой аккаунт, указав у провайдера его почту.
+// Если пользователя нет, он создается со случайным паролем
+func (s *UserService) LinkExternalIdentity(ctx context.Context, identity *models.ExternalIdentityDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.LinkExternalIdentity")
+	defer span.Finish()
+
+	if err := s.validator.Validate(identity); err != nil {
+		return nil, fmt.Errorf("[LinkExternalIdentity] validate:%w", err)
+	}
+
+	// повторная привязка той же учетной записи ничего не меняет
+	linked, err := s.userRepo.GetUserByExternalIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return userDTOFromDAO(linked), nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("[LinkExternalIdentity] get linked user:%w", err)
+	}
+
+	if identity.Email != "" {
+		existing, err := s.userRepo.GetUserByUsernameOrEmail(ctx, "", identity.Email)
+		switch {
+		case err == nil && identity.EmailVerified:
+			err = s.userRepo.CreateExternalIdentity(ctx, existing.ID, identity)
+			if err != nil {
+				return nil, fmt.Errorf("[LinkExternalIdentity] link identity:%w", err)
+			}
+			return userDTOFromDAO(existing), nil
+		case err == nil:
+			return nil, fmt.Errorf("[LinkExternalIdentity] email is not verified by provider: %w", appErrors.ErrUsernameOrEmailIsUsed)
+		case !errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("[LinkExternalIdentity] get user by email:%w", err)
+		}
+	}
+
+	username, err := s.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, fmt.Errorf("[LinkExternalIdentity] %w", err)
+	}
+
+	// пароль никто не знает: войти можно только через провайдера, пока пользователь не сменит пароль
+	password, err := randomPassword()
+	if err != nil {
+		return nil, fmt.Errorf("[LinkExternalIdentity] generate pass:%w", err)
+	}
+
+	hashedPassword, err := s.passUtils.GeneratePassword(ctx, password)
+	if err != nil {
+		return nil, fmt.Errorf("[LinkExternalIdentity] hash pass:%w", err)
+	}
+
+	newUser := &models.CreateUserDTO{
+		Username: username,
+		Password: hashedPassword,
+		Email:    identity.Email,
+	}
+	userID, err := s.userRepo.CreateUserWithExternalIdentity(ctx, newUser, identity)
+	if err != nil {
+		return nil, fmt.Errorf("[LinkExternalIdentity] store user:%w", err)
+	}
+
+	return &models.UserDTO{
+		ID:       userID,
+		Username: username,
+		Email:    identity.Email,
+		Role:     models.RoleUser,
+	}, nil
+}
+
+// availableUsername подбирает свободное имя пользователя на основе данных провайдера
+func (s *UserService) availableUsername(ctx context.Context, identity *models.ExternalIdentityDTO) (string, error) {
+	base := sanitizeUsername(identity.Username)
+	if base == "" {
+		base = sanitizeUsername(strings.Split(identity.Email, "@")[0])
+	}
+	if base == "" {
+		base = "user"
+	}
+	for utf8.RuneCountInString(base) < externalUsernameMinLen {
+		base += "_"
+	}
+
+	candidate := base
+	for i := 0; i < externalUsernameAttempts; i++ {
+		_, err := s.userRepo.GetUserByUsernameOrEmail(ctx, candidate, "")
+		if errors.Is(err, sql.ErrNoRows) {
+			return candidate, nil
+		} else if err != nil {
+			return "", fmt.Errorf("check username:%w", err)
+		}
+
+		suffix, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", fmt.Errorf("generate username suffix:%w", err)
+		}
+		candidate = withSuffix(base, fmt.Sprintf("_%04d", suffix.Int64()))
+	}
+
+	return "", fmt.Errorf("pick username: %w", appErrors.ErrUsernameOrEmailIsUsed)
+}
+
+// sanitizeUsername оставляет символы, разрешенные в имени пользователя, и обрезает его до максимальной длины
+func sanitizeUsername(name string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range name {
+		if length == externalUsernameMaxLen {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("._-", r) {
+			b.WriteRune(r)
+			length++
+		}
+	}
+
+	return b.String()
+}
+
+// withSuffix добавляет суффикс, не выходя за максимальную длину имени
+func withSuffix(base, suffix string) string {
+	runes := []rune(base)
+	if max := externalUsernameMaxLen - len(suffix); len(runes) > max {
+		runes = runes[:max]
+	}
+
+	return string(runes) + suffix
+}
+
+func randomPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func userDTOFromDAO(user *models.UserDAO) *models.UserDTO {
+	dto := &models.UserDTO{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		Role:        user.Role,
+		MFAEnabled:  user.TOTPEnabled,
+		DisplayName: user.DisplayName,
+		Timezone:    user.Timezone,
+		Locale:      user.Locale,
+	}
+	if user.AvatarKey != nil {
+		dto.AvatarKey = *user.AvatarKey
+	}
+	dto.Status = user.Status
+	dto.DeleteAfter = user.DeleteAfter
+	dto.Version = user.Version
+
+	return dto
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 	"users/internal/models"
 )
 
@@ -10,10 +11,32 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.CreateUserDTO) (int, error)
 	UpdateUser(ctx context.Context, user *models.UserDAO) error
 	UpdatePassword(ctx context.Context, userID int, newPassword string) error
-	DeleteUser(ctx context.Context, userID int) error
+	UpdateAvatar(ctx context.Context, userID int, avatarKey *string) (*string, error)
+	SetUserStatus(ctx context.Context, userID int, status string, deleteAfter *time.Time) error
+	ScheduleUserDeletion(ctx context.Context, userID, version int, deleteAfter time.Time) error
+	PurgeDeletedUsers(ctx context.Context, limit int, beforeCommit func(userIDs []int) error) ([]int, error)
 	GetUserByID(ctx context.Context, userID int) (*models.UserDAO, error)
 	GetUserByUsernameOrEmail(ctx context.Context, username, email string) (*models.UserDAO, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.UserDAO, error)
+	ListUsers(ctx context.Context, limit, offset int) ([]*models.UserDAO, error)
+	UpdateUserRole(ctx context.Context, userID int, role string) error
+	SetTOTPSecret(ctx context.Context, userID int, encryptedSecret string) error
+	EnableTOTP(ctx context.Context, userID int, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, userID int) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+	UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+	GetUserByExternalIdentity(ctx context.Context, provider, subject string) (*models.UserDAO, error)
+	CreateExternalIdentity(ctx context.Context, userID int, identity *models.ExternalIdentityDTO) error
+	CreateUserWithExternalIdentity(ctx context.Context, user *models.CreateUserDTO, identity *models.ExternalIdentityDTO) (int, error)
+	CreateAPIKey(ctx context.Context, key *models.APIKeyDAO) (int, time.Time, error)
+	ListAPIKeys(ctx context.Context, userID int) ([]*models.APIKeyDAO, error)
+	DeleteAPIKey(ctx context.Context, keyID, userID int) (bool, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKeyDAO, error)
+	TouchAPIKey(ctx context.Context, keyID int) error
+	EmailIsUsed(ctx context.Context, email string, exceptUserID int) (bool, error)
+	SetPendingEmail(ctx context.Context, pending *models.PendingEmailDAO) error
+	GetPendingEmail(ctx context.Context, tokenHash string) (*models.PendingEmailDAO, error)
+	ConfirmEmailChange(ctx context.Context, userID int, tokenHash string) (bool, error)
 }
 
 //go:generate mockgen --build_flags=-mod=mod -destination=./mocks/rabbit_producer.go -package=mocks users/internal/service RabbitProducer
@@ -25,4 +48,6 @@ type RabbitProducer interface {
 type PasswordUtils interface {
 	GeneratePassword(ctx context.Context, password string) (string, error)
 	ComparePassword(ctx context.Context, password, hash string) (bool, error)
+	NeedsRehash(hash string) bool
+	IsBreached(ctx context.Context, password string) (bool, error)
 }
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/mfa"
+)
+
+// EnrollTOTP создает новый TOTP секрет. MFA включится только после подтверждения первым кодом
+func (s *UserService) EnrollTOTP(ctx context.Context, userID int) (*models.TOTPEnrollmentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.EnrollTOTP")
+	defer span.Finish()
+
+	user, err := s.getUserForMFA(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[EnrollTOTP] %w", err)
+	}
+
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("[EnrollTOTP] %w", appErrors.ErrMFAAlreadyEnabled)
+	}
+
+	secret, uri, err := s.mfa.GenerateSecret(user.Username)
+	if err != nil {
+		return nil, fmt.Errorf("[EnrollTOTP] generate secret:%w", err)
+	}
+
+	encryptedSecret, err := s.mfa.EncryptSecret(secret)
+	if err != nil {
+		return nil, fmt.Errorf("[EnrollTOTP] encrypt secret:%w", err)
+	}
+
+	err = s.userRepo.SetTOTPSecret(ctx, userID, encryptedSecret)
+	if err != nil {
+		return nil, fmt.Errorf("[EnrollTOTP] store secret:%w", err)
+	}
+
+	return &models.TOTPEnrollmentDTO{
+		Secret:     secret,
+		OtpauthURI: uri,
+	}, nil
+}
+
+// ConfirmTOTP включает MFA, если код из приложения совпал, и возвращает коды восстановления.
+// Коды показываются пользователю один раз, в базе хранятся только их хэши
+func (s *UserService) ConfirmTOTP(ctx context.Context, request *models.MFACodeDTO) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmTOTP")
+	defer span.Finish()
+
+	if err := s.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[ConfirmTOTP] validate:%w", err)
+	}
+
+	user, err := s.getUserForMFA(ctx, request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmTOTP] %w", err)
+	}
+
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("[ConfirmTOTP] %w", appErrors.ErrMFAAlreadyEnabled)
+	}
+
+	if user.TOTPSecret == nil {
+		return nil, fmt.Errorf("[ConfirmTOTP] %w", appErrors.ErrMFANotEnrolled)
+	}
+
+	secret, err := s.mfa.DecryptSecret(*user.TOTPSecret)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmTOTP] decrypt secret:%w", err)
+	}
+
+	step, ok := s.mfa.ValidateCode(request.Code, secret)
+	if !ok {
+		return nil, fmt.Errorf("[ConfirmTOTP] %w", appErrors.ErrInvalidMFACode)
+	}
+
+	if err := s.useTOTPStep(ctx, user.ID, step); err != nil {
+		return nil, fmt.Errorf("[ConfirmTOTP] %w", err)
+	}
+
+	codes, hashes, err := s.mfa.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmTOTP] generate recovery codes:%w", err)
+	}
+
+	err = s.userRepo.EnableTOTP(ctx, request.ID, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmTOTP] enable totp:%w", err)
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP выключает MFA. Нужен действующий второй фактор
+func (s *UserService) DisableTOTP(ctx context.Context, request *models.MFACodeDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DisableTOTP")
+	defer span.Finish()
+
+	if err := s.validator.Validate(request); err != nil {
+		return fmt.Errorf("[DisableTOTP] validate:%w", err)
+	}
+
+	user, err := s.getUserForMFA(ctx, request.ID)
+	if err != nil {
+		return fmt.Errorf("[DisableTOTP] %w", err)
+	}
+
+	if err := s.verifySecondFactor(ctx, user, request.Code); err != nil {
+		return fmt.Errorf("[DisableTOTP] %w", err)
+	}
+
+	err = s.userRepo.DisableTOTP(ctx, request.ID)
+	if err != nil {
+		return fmt.Errorf("[DisableTOTP] disable totp:%w", err)
+	}
+
+	return nil
+}
+
+// VerifyMFA проверяет второй фактор при входе: TOTP код или одноразовый код восстановления
+func (s *UserService) VerifyMFA(ctx context.Context, request *models.MFACodeDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.VerifyMFA")
+	defer span.Finish()
+
+	if err := s.validator.Validate(request); err != nil {
+		return nil, fmt.Errorf("[VerifyMFA] validate:%w", err)
+	}
+
+	user, err := s.getUserForMFA(ctx, request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[VerifyMFA] %w", err)
+	}
+
+	if err := s.verifySecondFactor(ctx, user, request.Code); err != nil {
+		return nil, fmt.Errorf("[VerifyMFA] %w", err)
+	}
+
+	if err := s.reactivateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("[VerifyMFA] %w", err)
+	}
+
+	return userDTOFromDAO(user), nil
+}
+
+func (s *UserService) getUserForMFA(ctx context.Context, userID int) (*models.UserDAO, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("get user:%w", appErrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("get user:%w", err)
+	}
+
+	return user, nil
+}
+
+func (s *UserService) verifySecondFactor(ctx context.Context, user *models.UserDAO, code string) error {
+	if !user.TOTPEnabled || user.TOTPSecret == nil {
+		return appErrors.ErrMFANotEnrolled
+	}
+
+	secret, err := s.mfa.DecryptSecret(*user.TOTPSecret)
+	if err != nil {
+		return fmt.Errorf("decrypt secret:%w", err)
+	}
+
+	if step, ok := s.mfa.ValidateCode(code, secret); ok {
+		return s.useTOTPStep(ctx, user.ID, step)
+	}
+
+	// не TOTP код - пробуем как код восстановления, каждый срабатывает один раз
+	used, err := s.userRepo.UseRecoveryCode(ctx, user.ID, mfa.HashRecoveryCode(code))
+	if err != nil {
+		return fmt.Errorf("use recovery code:%w", err)
+	}
+	if !used {
+		return appErrors.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// useTOTPStep принимает TOTP код окна step, только если код этого или более позднего окна еще не принимался
+func (s *UserService) useTOTPStep(ctx context.Context, userID int, step int64) error {
+	used, err := s.userRepo.UseTOTPStep(ctx, userID, step)
+	if err != nil {
+		return fmt.Errorf("use totp step:%w", err)
+	}
+	if !used {
+		return appErrors.ErrInvalidMFACode
+	}
+
+	return nil
+}
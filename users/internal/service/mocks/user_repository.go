@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 	models "users/internal/models"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,51 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ConfirmEmailChange mocks base method.
+func (m *MockUserRepository) ConfirmEmailChange(arg0 context.Context, arg1 int, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEmailChange", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmEmailChange indicates an expected call of ConfirmEmailChange.
+func (mr *MockUserRepositoryMockRecorder) ConfirmEmailChange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEmailChange", reflect.TypeOf((*MockUserRepository)(nil).ConfirmEmailChange), arg0, arg1, arg2)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockUserRepository) CreateAPIKey(arg0 context.Context, arg1 *models.APIKeyDAO) (int, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockUserRepositoryMockRecorder) CreateAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockUserRepository)(nil).CreateAPIKey), arg0, arg1)
+}
+
+// CreateExternalIdentity mocks base method.
+func (m *MockUserRepository) CreateExternalIdentity(arg0 context.Context, arg1 int, arg2 *models.ExternalIdentityDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalIdentity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExternalIdentity indicates an expected call of CreateExternalIdentity.
+func (mr *MockUserRepositoryMockRecorder) CreateExternalIdentity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalIdentity", reflect.TypeOf((*MockUserRepository)(nil).CreateExternalIdentity), arg0, arg1, arg2)
+}
+
 // CreateUser mocks base method.
 func (m *MockUserRepository) CreateUser(arg0 context.Context, arg1 *models.CreateUserDTO) (int, error) {
 	m.ctrl.T.Helper()
@@ -50,18 +96,122 @@ func (mr *MockUserRepositoryMockRecorder) CreateUser(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepository)(nil).CreateUser), arg0, arg1)
 }
 
-// DeleteUser mocks base method.
-func (m *MockUserRepository) DeleteUser(arg0 context.Context, arg1 int) error {
+// CreateUserWithExternalIdentity mocks base method.
+func (m *MockUserRepository) CreateUserWithExternalIdentity(arg0 context.Context, arg1 *models.CreateUserDTO, arg2 *models.ExternalIdentityDTO) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateUserWithExternalIdentity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserWithExternalIdentity indicates an expected call of CreateUserWithExternalIdentity.
+func (mr *MockUserRepositoryMockRecorder) CreateUserWithExternalIdentity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserWithExternalIdentity", reflect.TypeOf((*MockUserRepository)(nil).CreateUserWithExternalIdentity), arg0, arg1, arg2)
+}
+
+// DeleteAPIKey mocks base method.
+func (m *MockUserRepository) DeleteAPIKey(arg0 context.Context, arg1, arg2 int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAPIKey indicates an expected call of DeleteAPIKey.
+func (mr *MockUserRepositoryMockRecorder) DeleteAPIKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKey", reflect.TypeOf((*MockUserRepository)(nil).DeleteAPIKey), arg0, arg1, arg2)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockUserRepository) DisableTOTP(arg0 context.Context, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockUserRepositoryMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockUserRepositoryMockRecorder) DisableTOTP(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserRepository)(nil).DeleteUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockUserRepository)(nil).DisableTOTP), arg0, arg1)
+}
+
+// EmailIsUsed mocks base method.
+func (m *MockUserRepository) EmailIsUsed(arg0 context.Context, arg1 string, arg2 int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailIsUsed", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EmailIsUsed indicates an expected call of EmailIsUsed.
+func (mr *MockUserRepositoryMockRecorder) EmailIsUsed(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailIsUsed", reflect.TypeOf((*MockUserRepository)(nil).EmailIsUsed), arg0, arg1, arg2)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockUserRepository) EnableTOTP(arg0 context.Context, arg1 int, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockUserRepositoryMockRecorder) EnableTOTP(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockUserRepository)(nil).EnableTOTP), arg0, arg1, arg2)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockUserRepository) GetAPIKeyByHash(arg0 context.Context, arg1 string) (*models.APIKeyDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", arg0, arg1)
+	ret0, _ := ret[0].(*models.APIKeyDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockUserRepositoryMockRecorder) GetAPIKeyByHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockUserRepository)(nil).GetAPIKeyByHash), arg0, arg1)
+}
+
+// GetPendingEmail mocks base method.
+func (m *MockUserRepository) GetPendingEmail(arg0 context.Context, arg1 string) (*models.PendingEmailDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingEmail", arg0, arg1)
+	ret0, _ := ret[0].(*models.PendingEmailDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingEmail indicates an expected call of GetPendingEmail.
+func (mr *MockUserRepositoryMockRecorder) GetPendingEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingEmail", reflect.TypeOf((*MockUserRepository)(nil).GetPendingEmail), arg0, arg1)
+}
+
+// GetUserByExternalIdentity mocks base method.
+func (m *MockUserRepository) GetUserByExternalIdentity(arg0 context.Context, arg1, arg2 string) (*models.UserDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByExternalIdentity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.UserDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByExternalIdentity indicates an expected call of GetUserByExternalIdentity.
+func (mr *MockUserRepositoryMockRecorder) GetUserByExternalIdentity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByExternalIdentity", reflect.TypeOf((*MockUserRepository)(nil).GetUserByExternalIdentity), arg0, arg1, arg2)
 }
 
 // GetUserByID mocks base method.
@@ -109,6 +259,136 @@ func (mr *MockUserRepositoryMockRecorder) GetUserByUsernameOrEmail(arg0, arg1, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsernameOrEmail", reflect.TypeOf((*MockUserRepository)(nil).GetUserByUsernameOrEmail), arg0, arg1, arg2)
 }
 
+// ListAPIKeys mocks base method.
+func (m *MockUserRepository) ListAPIKeys(arg0 context.Context, arg1 int) ([]*models.APIKeyDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", arg0, arg1)
+	ret0, _ := ret[0].([]*models.APIKeyDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockUserRepositoryMockRecorder) ListAPIKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockUserRepository)(nil).ListAPIKeys), arg0, arg1)
+}
+
+// ListUsers mocks base method.
+func (m *MockUserRepository) ListUsers(arg0 context.Context, arg1, arg2 int) ([]*models.UserDAO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*models.UserDAO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockUserRepositoryMockRecorder) ListUsers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserRepository)(nil).ListUsers), arg0, arg1, arg2)
+}
+
+// PurgeDeletedUsers mocks base method.
+func (m *MockUserRepository) PurgeDeletedUsers(arg0 context.Context, arg1 int, arg2 func([]int) error) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedUsers", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedUsers indicates an expected call of PurgeDeletedUsers.
+func (mr *MockUserRepositoryMockRecorder) PurgeDeletedUsers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedUsers", reflect.TypeOf((*MockUserRepository)(nil).PurgeDeletedUsers), arg0, arg1, arg2)
+}
+
+// ScheduleUserDeletion mocks base method.
+func (m *MockUserRepository) ScheduleUserDeletion(arg0 context.Context, arg1, arg2 int, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleUserDeletion", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleUserDeletion indicates an expected call of ScheduleUserDeletion.
+func (mr *MockUserRepositoryMockRecorder) ScheduleUserDeletion(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleUserDeletion", reflect.TypeOf((*MockUserRepository)(nil).ScheduleUserDeletion), arg0, arg1, arg2, arg3)
+}
+
+// SetPendingEmail mocks base method.
+func (m *MockUserRepository) SetPendingEmail(arg0 context.Context, arg1 *models.PendingEmailDAO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingEmail", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingEmail indicates an expected call of SetPendingEmail.
+func (mr *MockUserRepositoryMockRecorder) SetPendingEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingEmail", reflect.TypeOf((*MockUserRepository)(nil).SetPendingEmail), arg0, arg1)
+}
+
+// SetTOTPSecret mocks base method.
+func (m *MockUserRepository) SetTOTPSecret(arg0 context.Context, arg1 int, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTOTPSecret", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTOTPSecret indicates an expected call of SetTOTPSecret.
+func (mr *MockUserRepositoryMockRecorder) SetTOTPSecret(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTOTPSecret", reflect.TypeOf((*MockUserRepository)(nil).SetTOTPSecret), arg0, arg1, arg2)
+}
+
+// SetUserStatus mocks base method.
+func (m *MockUserRepository) SetUserStatus(arg0 context.Context, arg1 int, arg2 string, arg3 *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserStatus indicates an expected call of SetUserStatus.
+func (mr *MockUserRepositoryMockRecorder) SetUserStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserStatus", reflect.TypeOf((*MockUserRepository)(nil).SetUserStatus), arg0, arg1, arg2, arg3)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockUserRepository) TouchAPIKey(arg0 context.Context, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockUserRepositoryMockRecorder) TouchAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockUserRepository)(nil).TouchAPIKey), arg0, arg1)
+}
+
+// UpdateAvatar mocks base method.
+func (m *MockUserRepository) UpdateAvatar(arg0 context.Context, arg1 int, arg2 *string) (*string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAvatar", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAvatar indicates an expected call of UpdateAvatar.
+func (mr *MockUserRepositoryMockRecorder) UpdateAvatar(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAvatar", reflect.TypeOf((*MockUserRepository)(nil).UpdateAvatar), arg0, arg1, arg2)
+}
+
 // UpdatePassword mocks base method.
 func (m *MockUserRepository) UpdatePassword(arg0 context.Context, arg1 int, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -136,3 +416,47 @@ func (mr *MockUserRepositoryMockRecorder) UpdateUser(arg0, arg1 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserRepository)(nil).UpdateUser), arg0, arg1)
 }
+
+// UpdateUserRole mocks base method.
+func (m *MockUserRepository) UpdateUserRole(arg0 context.Context, arg1 int, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockUserRepositoryMockRecorder) UpdateUserRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateUserRole), arg0, arg1, arg2)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockUserRepository) UseRecoveryCode(arg0 context.Context, arg1 int, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockUserRepositoryMockRecorder) UseRecoveryCode(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockUserRepository)(nil).UseRecoveryCode), arg0, arg1, arg2)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockUserRepository) UseTOTPStep(arg0 context.Context, arg1 int, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockUserRepositoryMockRecorder) UseTOTPStep(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockUserRepository)(nil).UseTOTPStep), arg0, arg1, arg2)
+}
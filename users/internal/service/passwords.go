@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"users/pkg/validator"
+)
+
+// errBreachedPassword - пароль есть в базе утечек, клиент получает его как ошибку валидации
+var errBreachedPassword = validator.NewValidationError(validator.FieldViolation{
+	Field:   "password",
+	Message: "has appeared in a data breach, choose another password",
+})
+
+// checkBreachedPassword отклоняет пароль из базы утечек так же, как пароль, не прошедший валидацию
+func (s *UserService) checkBreachedPassword(ctx context.Context, password string) error {
+	breached, err := s.passUtils.IsBreached(ctx, password)
+	if err != nil {
+		return fmt.Errorf("check breached pass:%w", err)
+	}
+
+	if breached {
+		return errBreachedPassword
+	}
+
+	return nil
+}
+
+// rehashPassword пересчитывает хэш с текущими параметрами, пока при входе известен пароль.
+// Ошибки не мешают входу: хэш пересчитается при следующем
+func (s *UserService) rehashPassword(ctx context.Context, userID int, password string) {
+	hashedPassword, err := s.passUtils.GeneratePassword(ctx, password)
+	if err != nil {
+		return
+	}
+
+	_ = s.userRepo.UpdatePassword(ctx, userID, hashedPassword)
+}
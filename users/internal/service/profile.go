@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
+)
+
+// UpdateAvatar сохраняет ключ нового аватара и возвращает прежний ключ, пустой ключ удаляет аватар.
+// Сами файлы хранит gateway, сервис пользователей знает только ключ
+func (s *UserService) UpdateAvatar(ctx context.Context, userID int, avatarKey string) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateAvatar")
+	defer span.Finish()
+
+	var key *string
+	if avatarKey != "" {
+		key = &avatarKey
+	}
+
+	previous, err := s.userRepo.UpdateAvatar(ctx, userID, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("[UpdateAvatar] %w", appErrors.ErrNotFound)
+		}
+		return "", fmt.Errorf("[UpdateAvatar] update avatar:%w", err)
+	}
+
+	if previous == nil {
+		return "", nil
+	}
+
+	return *previous, nil
+}
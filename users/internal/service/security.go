@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	"users/internal/models"
+	"users/pkg/ctxutil"
+)
+
+// ReportAccountLocked отправляет пользователю письмо о блокировке входа.
+// Для несуществующего логина ничего не делает: по ответу нельзя понять, есть ли такой пользователь
+func (s *UserService) ReportAccountLocked(ctx context.Context, event *models.AccountLockedDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ReportAccountLocked")
+	defer span.Finish()
+
+	user, err := s.userRepo.GetUserByUsernameOrEmail(ctx, event.Username, event.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("[ReportAccountLocked] get user:%w", err)
+	}
+
+	data, err := json.Marshal(models.UserMailItem{
+		UserEventType: models.UserEventTypeAccountLocked,
+		Receivers:     []string{user.Email},
+		IP:            event.IP,
+		LockedUntil:   event.LockedUntil.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("[ReportAccountLocked] marshal account locked mssg:%w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	err = s.userRabbitProducer.Publish(data, requestID)
+	if err != nil {
+		return fmt.Errorf("[ReportAccountLocked] publish account locked mssg:%w", err)
+	}
+
+	return nil
+}
@@ -7,9 +7,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/opentracing/opentracing-go"
+	"users/config"
 	appErrors "users/internal/app_errors"
 	"users/internal/models"
 	"users/pkg/ctxutil"
+	"users/pkg/mfa"
+	"users/pkg/validator"
 )
 
 type UserService struct {
@@ -17,17 +20,32 @@ type UserService struct {
 	userRepo           UserRepository
 	userRabbitProducer RabbitProducer
 	passUtils          PasswordUtils
+	validator          *validator.Validator
+	mfa                *mfa.MFA
+	emailChange        *config.EmailChange
+	deletion           *config.Deletion
+	userEventsProducer RabbitProducer
 }
 
 func NewUserService(
 	userRepo UserRepository,
 	userRabbitProducer RabbitProducer,
 	utils PasswordUtils,
+	validator *validator.Validator,
+	mfa *mfa.MFA,
+	emailChange *config.EmailChange,
+	deletion *config.Deletion,
+	userEventsProducer RabbitProducer,
 ) *UserService {
 	return &UserService{
 		userRepo:           userRepo,
 		userRabbitProducer: userRabbitProducer,
 		passUtils:          utils,
+		validator:          validator,
+		mfa:                mfa,
+		emailChange:        emailChange,
+		deletion:           deletion,
+		userEventsProducer: userEventsProducer,
 	}
 }
 
@@ -35,12 +53,19 @@ func (s *UserService) RegisterUser(ctx context.Context, newUser *models.CreateUs
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RegisterUser")
 	defer span.Finish()
 
-	// Проверка наличия пользователя с таким же именем или мэйлом.
-	_, err := s.userRepo.GetUserByUsernameOrEmail(ctx, newUser.Username, newUser.Email)
-	if err == nil {
-		return 0, fmt.Errorf("[RegisterUser] get user: %w", appErrors.ErrUsernameOrEmailIsUsed)
-	} else if !errors.Is(err, sql.ErrNoRows) {
-		return 0, err
+	// Проверка входных данных
+	if err := s.validator.Validate(newUser); err != nil {
+		return 0, fmt.Errorf("[RegisterUser] validate:%w", err)
+	}
+
+	// занятые имя и email отсекает уникальный индекс при сохранении,
+	// а адрес, ожидающий подтверждения у другого пользователя, проверяем заранее
+	used, err := s.userRepo.EmailIsUsed(ctx, newUser.Email, 0)
+	if err != nil {
+		return 0, fmt.Errorf("[RegisterUser] check email: %w", err)
+	}
+	if used {
+		return 0, fmt.Errorf("[RegisterUser] check email: %w", appErrors.ErrUsernameOrEmailIsUsed)
 	}
 
 	// проверяем, что пароль совпадает с подтверждением пароля
@@ -48,6 +73,10 @@ func (s *UserService) RegisterUser(ctx context.Context, newUser *models.CreateUs
 		return 0, fmt.Errorf("[RegisterUser] confirm pass: %w", appErrors.ErrPassAndConfirmationDoesNotMatch)
 	}
 
+	if err := s.checkBreachedPassword(ctx, newUser.Password); err != nil {
+		return 0, fmt.Errorf("[RegisterUser] %w", err)
+	}
+
 	// Хеширование пароля - никогда не храните пароль в незашифрованном виде.
 	hashedPassword, err := s.passUtils.GeneratePassword(ctx, newUser.Password)
 	if err != nil {
@@ -86,29 +115,54 @@ func (s *UserService) UpdateUser(ctx context.Context, updatedUser *models.UserDT
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateUser")
 	defer span.Finish()
 
+	if err := s.validator.Validate(updatedUser); err != nil {
+		return nil, fmt.Errorf("[UpdateUser] validate:%w", err)
+	}
+
 	// Проверка наличия пользователя.
 	existingUser, err := s.userRepo.GetUserByID(ctx, updatedUser.ID)
 	if err != nil {
 		return nil, fmt.Errorf("[UpdateUser] get user:%w", err)
 	}
 
-	// Обновление информации о пользователе.
+	// новый email применяется только после подтверждения, см. RequestEmailChange
+	if updatedUser.Email != "" && updatedUser.Email != existingUser.Email {
+		return nil, fmt.Errorf("[UpdateUser] %w", errEmailChangeNeedsConfirmation)
+	}
+
+	// Обновление информации о пользователе. Незаполненные поля профиля остаются прежними
 	existingUser.Username = updatedUser.Username
+	if updatedUser.DisplayName != "" {
+		existingUser.DisplayName = updatedUser.DisplayName
+	}
+	if updatedUser.Timezone != "" {
+		existingUser.Timezone = updatedUser.Timezone
+	}
+	if updatedUser.Locale != "" {
+		existingUser.Locale = updatedUser.Locale
+	}
+
+	// сохраняем только поверх той версии, которую видел клиент, иначе репозиторий вернет ErrVersionConflict
+	existingUser.Version = updatedUser.Version
 
-	// Передаем данные в слой репозитория
+	// Передаем данные в слой репозитория. Занятое другим пользователем имя вернет ErrUsernameOrEmailIsUsed
 	err = s.userRepo.UpdateUser(ctx, existingUser)
 	if err != nil {
 		return nil, fmt.Errorf("[UpdateUser] update user:%w", err)
 	}
 
 	// возвращаем данные в слой хэндлера
-	return updatedUser, nil
+	return userDTOFromDAO(existingUser), nil
 }
 
 func (s *UserService) UpdatePassword(ctx context.Context, request *models.UpdateUserPasswordDTO) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdatePassword")
 	defer span.Finish()
 
+	if err := s.validator.Validate(request); err != nil {
+		return fmt.Errorf("[UpdatePassword] validate:%w", err)
+	}
+
 	// Проверка наличия пользователя.
 	existingUser, err := s.userRepo.GetUserByID(ctx, request.ID)
 	if err != nil {
@@ -129,6 +183,10 @@ func (s *UserService) UpdatePassword(ctx context.Context, request *models.Update
 		return fmt.Errorf("[UpdatePassword] verify pass:%w", appErrors.ErrIncorrectOldPassword)
 	}
 
+	if err := s.checkBreachedPassword(ctx, request.Password); err != nil {
+		return fmt.Errorf("[UpdatePassword] %w", err)
+	}
+
 	// Хеширование пароля.
 	hashedPassword, err := s.passUtils.GeneratePassword(ctx, request.Password)
 	if err != nil {
@@ -145,71 +203,48 @@ func (s *UserService) UpdatePassword(ctx context.Context, request *models.Update
 	return nil
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, userID int) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteUser")
-	defer span.Finish()
-
-	// Удаление пользователя.
-	err := s.userRepo.DeleteUser(ctx, userID)
-	if err != nil {
-		return fmt.Errorf("[DeleteUser] delete user:%w", err)
-	}
-
-	return nil
-}
-
 func (s *UserService) GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUserByID")
 	defer span.Finish()
 
-	// Получение пользователя по его идентификатору.
-	var userResponse = new(models.UserDTO)
 	storedUser, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, appErrors.ErrNotFound
 		}
-		return userResponse, fmt.Errorf("[GetUserByID] get user:%w", err)
+		return nil, fmt.Errorf("[GetUserByID] get user:%w", err)
 	}
 
-	// запись данных из DAO - data access object через которую мы работаем с базой данных
-	// в DTO - data transfer object, который мы возвращаем пользователю
-	userResponse.ID = storedUser.ID
-	userResponse.Username = storedUser.Username
-	userResponse.Email = storedUser.Email
-
-	// возврат данных пользователю
-	return userResponse, nil
+	// DAO - объект, через который работаем с базой данных, DTO - объект, который возвращаем пользователю.
+	// В DTO попадает и версия, по которой gateway проверяет If-Match
+	return userDTOFromDAO(storedUser), nil
 }
 
 func (s *UserService) GetUserByUsernameOrEmail(ctx context.Context, name, email string) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUserByUsernameOrEmail")
 	defer span.Finish()
 
-	// Получение пользователя по его идентификатору.
-	var userResponse = new(models.UserDTO)
 	storedUser, err := s.userRepo.GetUserByUsernameOrEmail(ctx, name, email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, appErrors.ErrNotFound
 		}
-		return userResponse, fmt.Errorf("[GetUserByID] get user:%w", err)
+		return nil, fmt.Errorf("[GetUserByUsernameOrEmail] get user:%w", err)
 	}
 
-	// запись данных из DAO - data access object через которую мы работаем с базой данных
-	// в DTO - data transfer object, который мы возвращаем пользователю
-	userResponse.ID = storedUser.ID
-	userResponse.Username = storedUser.Username
-	userResponse.Email = storedUser.Email
-
-	// возврат данных пользователю
-	return userResponse, nil
+	// DAO - объект, через который работаем с базой данных, DTO - объект, который возвращаем пользователю.
+	// В DTO попадает и версия, по которой gateway проверяет If-Match
+	return userDTOFromDAO(storedUser), nil
 }
 
 func (s *UserService) Login(ctx context.Context, login *models.UserLoginDTO) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.Login")
 	defer span.Finish()
 
+	if err := s.validator.Validate(login); err != nil {
+		return nil, fmt.Errorf("[Login] validate:%w", err)
+	}
+
 	// Проверка наличия пользователя.
 	existingUser, err := s.userRepo.GetUserByUsernameOrEmail(ctx, login.Username, login.Email)
 	if err != nil {
@@ -228,10 +263,61 @@ func (s *UserService) Login(ctx context.Context, login *models.UserLoginDTO) (*m
 		return nil, fmt.Errorf("[Login] verify pass:%w", appErrors.ErrWrongCredentials)
 	}
 
+	// хэш с устаревшими параметрами или другим алгоритмом заменяется на актуальный
+	if s.passUtils.NeedsRehash(existingUser.Password) {
+		s.rehashPassword(ctx, existingUser.ID, login.Password)
+	}
+
+	// при включенной MFA учетная запись возвращается только после проверки второго фактора в VerifyMFA
+	if !existingUser.TOTPEnabled {
+		if err := s.reactivateUser(ctx, existingUser); err != nil {
+			return nil, fmt.Errorf("[Login] %w", err)
+		}
+	}
+
 	// возврат данных пользователю
-	return &models.UserDTO{
-		ID:       existingUser.ID,
-		Username: existingUser.Username,
-		Email:    existingUser.Email,
-	}, nil
+	// при включенной MFA gateway запросит второй фактор перед выдачей токенов
+	return userDTOFromDAO(existingUser), nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context, limit, offset int) ([]*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListUsers")
+	defer span.Finish()
+
+	storedUsers, err := s.userRepo.ListUsers(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("[ListUsers] list users:%w", err)
+	}
+
+	result := make([]*models.UserDTO, 0, len(storedUsers))
+	for _, user := range storedUsers {
+		result = append(result, userDTOFromDAO(user))
+	}
+
+	return result, nil
+}
+
+func (s *UserService) UpdateUserRole(ctx context.Context, request *models.UpdateUserRoleDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateUserRole")
+	defer span.Finish()
+
+	if err := s.validator.Validate(request); err != nil {
+		return fmt.Errorf("[UpdateUserRole] validate:%w", err)
+	}
+
+	// Проверка наличия пользователя.
+	_, err := s.userRepo.GetUserByID(ctx, request.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("[UpdateUserRole] get user:%w", appErrors.ErrNotFound)
+		}
+		return fmt.Errorf("[UpdateUserRole] get user:%w", err)
+	}
+
+	err = s.userRepo.UpdateUserRole(ctx, request.ID, request.Role)
+	if err != nil {
+		return fmt.Errorf("[UpdateUserRole] update role:%w", err)
+	}
+
+	return nil
 }
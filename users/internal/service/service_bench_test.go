@@ -16,7 +16,12 @@ func BenchmarkRegisterUser(b *testing.B) {
 	mocks := getMocks(ctrl)
 	service := buildTestService(mocks)
 	ctx := context.Background()
-	newUser := &models.CreateUserDTO{ /* fill with test data */ }
+	newUser := &models.CreateUserDTO{
+		Username:             "testuser",
+		Password:             "Passw0rd",
+		PasswordConfirmation: "Passw0rd",
+		Email:                "testuser@example.com",
+	}
 
 	// Устанавливаем ожидаемые вызовы моков
 	mocks.UsersRepository.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(1, nil).AnyTimes()
@@ -41,7 +46,7 @@ func BenchmarkUpdateUser(b *testing.B) {
 	mocks := getMocks(ctrl)
 	service := buildTestService(mocks)
 	ctx := context.Background()
-	updatedUser := &models.UserDTO{ /* fill with test data */ }
+	updatedUser := &models.UserDTO{ID: 1, Username: "testuser"}
 
 	// Устанавливаем ожидаемые вызовы моков
 	mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Return(&models.UserDAO{}, nil).AnyTimes()
@@ -65,7 +70,12 @@ func BenchmarkUpdatePassword(b *testing.B) {
 	mocks := getMocks(ctrl)
 	service := buildTestService(mocks)
 	ctx := context.Background()
-	updatePasswordRequest := &models.UpdateUserPasswordDTO{ /* fill with test data */ }
+	updatePasswordRequest := &models.UpdateUserPasswordDTO{
+		ID:                   1,
+		OldPassword:          "oldPassword",
+		Password:             "newPassw0rd",
+		PasswordConfirmation: "newPassw0rd",
+	}
 
 	// Устанавливаем ожидаемые вызовы моков
 	mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), gomock.Any()).Return(&models.UserDAO{Password: "oldHashedPassword"}, nil).AnyTimes()
@@ -94,11 +104,11 @@ func BenchmarkDeleteUser(b *testing.B) {
 	userID := 1 // Пример идентификатора пользователя
 
 	// Устанавливаем ожидаемые вызовы моков
-	mocks.UsersRepository.EXPECT().DeleteUser(gomock.Any(), gomock.Eq(userID)).Return(nil).AnyTimes()
+	mocks.UsersRepository.EXPECT().ScheduleUserDeletion(gomock.Any(), gomock.Eq(userID), 1, gomock.Any()).Return(nil).AnyTimes()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		err := service.DeleteUser(ctx, userID)
+		_, err := service.DeleteUser(ctx, userID, 1)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -161,7 +171,7 @@ func BenchmarkLogin(b *testing.B) {
 	mocks := getMocks(ctrl)
 	service := buildTestService(mocks)
 	ctx := context.Background()
-	loginDTO := &models.UserLoginDTO{ /* fill with test data */ }
+	loginDTO := &models.UserLoginDTO{Username: "testuser", Password: "password"}
 
 	// Устанавливаем ожидаемые вызовы моков
 	mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.UserDAO{}, nil).AnyTimes()
@@ -3,11 +3,19 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
+	"time"
 	appErrors "users/internal/app_errors"
 	"users/internal/models"
+	"users/pkg/mfa"
+	"users/pkg/validator"
 )
 
 func TestUserService_RegisterUser(t *testing.T) {
@@ -21,14 +29,8 @@ func TestUserService_RegisterUser(t *testing.T) {
 	newUser := &models.CreateUserDTO{
 		Username:             "testuser",
 		Email:                "testuser@example.com",
-		Password:             "password",
-		PasswordConfirmation: "password",
-	}
-
-	// Данные существующего пользователя для имитации сценария "Пользователь уже существует".
-	existingUser := &models.UserDAO{
-		Username: "testuser",
-		Email:    "testuser@example.com",
+		Password:             "Passw0rd",
+		PasswordConfirmation: "Passw0rd",
 	}
 
 	// Определение тестовых сценариев.
@@ -42,7 +44,8 @@ func TestUserService_RegisterUser(t *testing.T) {
 			name: "Success", // Сценарий успешной регистрации.
 			setup: func() {
 				// Настройка ожидаемого поведения моков.
-				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), newUser.Username, newUser.Email).Return(nil, sql.ErrNoRows)
+				mocks.PasswordUtils.EXPECT().IsBreached(gomock.Any(), newUser.Password).Return(false, nil)
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), newUser.Email, 0).Return(false, nil)
 				mocks.PasswordUtils.EXPECT().GeneratePassword(gomock.Any(), newUser.Password).Return("hashedPassword", nil)
 				mocks.UsersRepository.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(1, nil)
 				mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
@@ -50,13 +53,34 @@ func TestUserService_RegisterUser(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "UserExistsError", // Сценарий ошибки "Пользователь уже существует".
+			name: "UserExistsError", // Имя или email заняты: сработал уникальный индекс.
+			setup: func() {
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), newUser.Email, 0).Return(false, nil)
+				mocks.PasswordUtils.EXPECT().IsBreached(gomock.Any(), newUser.Password).Return(false, nil)
+				mocks.PasswordUtils.EXPECT().GeneratePassword(gomock.Any(), newUser.Password).Return("hashedPassword", nil)
+				mocks.UsersRepository.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
+					Return(0, fmt.Errorf("users_username_lower_key: %w", appErrors.ErrUsernameOrEmailIsUsed))
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrUsernameOrEmailIsUsed,
+		},
+		{
+			name: "PendingEmailError", // Адрес ждет подтверждения у другого пользователя.
 			setup: func() {
-				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), newUser.Username, newUser.Email).Return(existingUser, nil)
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), newUser.Email, 0).Return(true, nil)
 			},
 			wantErr:     true,
 			expectedErr: appErrors.ErrUsernameOrEmailIsUsed,
 		},
+		{
+			name: "BreachedPasswordError", // Пароль есть в базе утечек.
+			setup: func() {
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), newUser.Email, 0).Return(false, nil)
+				mocks.PasswordUtils.EXPECT().IsBreached(gomock.Any(), newUser.Password).Return(true, nil)
+			},
+			wantErr:     true,
+			expectedErr: errBreachedPassword,
+		},
 		// Добавьте сюда другие сценарии
 	}
 
@@ -64,7 +88,9 @@ func TestUserService_RegisterUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			_, err := svc.RegisterUser(context.Background(), newUser)
+			// сервис подменяет пароль на хэш, поэтому передаем копию
+			user := *newUser
+			_, err := svc.RegisterUser(context.Background(), &user)
 			requireEqualError(t, err, tt.expectedErr)
 		})
 	}
@@ -78,32 +104,79 @@ func TestUserService_UpdateUser(t *testing.T) {
 	svc := buildTestService(mocks)
 
 	updatedUser := &models.UserDTO{
-		ID:       1,
-		Username: "updateduser",
-		Email:    "updateduser@example.com",
+		ID:          1,
+		Username:    "updateduser",
+		Email:       "existinguser@example.com",
+		DisplayName: "Updated User",
+		Timezone:    "Europe/Moscow",
+		Version:     3,
 	}
 
-	existingUser := &models.UserDAO{
-		ID:       1,
-		Username: "existinguser",
-		Email:    "existinguser@example.com",
+	// сервис изменяет полученную запись, поэтому каждый сценарий получает свою копию
+	existingUser := func() *models.UserDAO {
+		return &models.UserDAO{
+			ID:       1,
+			Username: "existinguser",
+			Email:    "existinguser@example.com",
+			Timezone: "UTC",
+			Locale:   "en",
+			Version:  3,
+		}
 	}
 
 	errDb := errors.New("db error")
 
 	tests := []struct {
 		name        string
+		email       string
 		setup       func()
 		wantErr     bool
 		expectedErr error
+		expected    *models.UserDTO
 	}{
 		{
 			name: "Success",
 			setup: func() {
-				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), updatedUser.ID).Return(existingUser, nil)
-				mocks.UsersRepository.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(nil)
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), updatedUser.ID).Return(existingUser(), nil)
+				mocks.UsersRepository.EXPECT().UpdateUser(gomock.Any(), &models.UserDAO{
+					ID:          1,
+					Username:    updatedUser.Username,
+					Email:       updatedUser.Email,
+					DisplayName: updatedUser.DisplayName,
+					Timezone:    updatedUser.Timezone,
+					Locale:      "en",
+					Version:     3,
+				}).DoAndReturn(func(_ context.Context, user *models.UserDAO) error {
+					user.Version++
+					return nil
+				})
 			},
 			wantErr: false,
+			expected: &models.UserDTO{
+				ID:          1,
+				Username:    updatedUser.Username,
+				Email:       updatedUser.Email,
+				DisplayName: updatedUser.DisplayName,
+				Timezone:    updatedUser.Timezone,
+				Locale:      "en",
+				Version:     4,
+			},
+		},
+		{
+			name: "VersionConflictError",
+			setup: func() {
+				// пользователя успели изменить: запись уже новее той, что видел клиент
+				stored := existingUser()
+				stored.Version = 4
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), updatedUser.ID).Return(stored, nil)
+				mocks.UsersRepository.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, user *models.UserDAO) error {
+						require.Equal(t, updatedUser.Version, user.Version)
+						return appErrors.ErrVersionConflict
+					})
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrVersionConflict,
 		},
 		{
 			name: "UserNotFoundError",
@@ -113,22 +186,101 @@ func TestUserService_UpdateUser(t *testing.T) {
 			wantErr:     true,
 			expectedErr: sql.ErrNoRows,
 		},
+		{
+			name: "UsernameTakenError",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), updatedUser.ID).Return(existingUser(), nil)
+				mocks.UsersRepository.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("users_username_lower_key: %w", appErrors.ErrUsernameOrEmailIsUsed))
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrUsernameOrEmailIsUsed,
+		},
 		{
 			name: "UpdateUserDBError",
 			setup: func() {
-				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), updatedUser.ID).Return(existingUser, nil)
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), updatedUser.ID).Return(existingUser(), nil)
 				mocks.UsersRepository.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(errDb)
 			},
 			wantErr:     true,
 			expectedErr: errDb,
 		},
+		{
+			name:  "EmailChangeError",
+			email: "updateduser@example.com",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), updatedUser.ID).Return(existingUser(), nil)
+			},
+			wantErr:     true,
+			expectedErr: errEmailChangeNeedsConfirmation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			request := *updatedUser
+			if tt.email != "" {
+				request.Email = tt.email
+			}
+			result, err := svc.UpdateUser(context.Background(), &request)
+			requireEqualError(t, err, tt.expectedErr)
+			if tt.expected != nil {
+				require.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestUserService_UpdateAvatar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	previousKey := "avatars/1/old"
+	newKey := "avatars/1/new"
+
+	tests := []struct {
+		name        string
+		avatarKey   string
+		setup       func()
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:      "Success",
+			avatarKey: newKey,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().UpdateAvatar(gomock.Any(), 1, &newKey).Return(&previousKey, nil)
+			},
+			expected: previousKey,
+		},
+		{
+			name:      "RemoveAvatar",
+			avatarKey: "",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().UpdateAvatar(gomock.Any(), 1, nil).Return(nil, nil)
+			},
+			expected: "",
+		},
+		{
+			name:      "UserNotFoundError",
+			avatarKey: newKey,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().UpdateAvatar(gomock.Any(), 1, &newKey).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			_, err := svc.UpdateUser(context.Background(), updatedUser)
+			previous, err := svc.UpdateAvatar(context.Background(), 1, tt.avatarKey)
 			requireEqualError(t, err, tt.expectedErr)
+			require.Equal(t, tt.expected, previous)
 		})
 	}
 }
@@ -144,8 +296,8 @@ func TestUserService_UpdatePassword(t *testing.T) {
 	updateReq := &models.UpdateUserPasswordDTO{
 		ID:                   userID,
 		OldPassword:          "oldPassword",
-		Password:             "newPassword",
-		PasswordConfirmation: "newPassword",
+		Password:             "newPassw0rd",
+		PasswordConfirmation: "newPassw0rd",
 	}
 
 	existingUser := &models.UserDAO{
@@ -166,6 +318,7 @@ func TestUserService_UpdatePassword(t *testing.T) {
 			setup: func() {
 				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), userID).Return(existingUser, nil)
 				mocks.PasswordUtils.EXPECT().ComparePassword(gomock.Any(), updateReq.OldPassword, existingUser.Password).Return(true, nil)
+				mocks.PasswordUtils.EXPECT().IsBreached(gomock.Any(), updateReq.Password).Return(false, nil)
 				mocks.PasswordUtils.EXPECT().GeneratePassword(gomock.Any(), updateReq.Password).Return("hashedNewPassword", nil)
 				mocks.UsersRepository.EXPECT().UpdatePassword(gomock.Any(), userID, gomock.Any()).Return(nil)
 			},
@@ -184,12 +337,23 @@ func TestUserService_UpdatePassword(t *testing.T) {
 			setup: func() {
 				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), userID).Return(existingUser, nil)
 				mocks.PasswordUtils.EXPECT().ComparePassword(gomock.Any(), updateReq.OldPassword, existingUser.Password).Return(true, nil)
+				mocks.PasswordUtils.EXPECT().IsBreached(gomock.Any(), updateReq.Password).Return(false, nil)
 				mocks.PasswordUtils.EXPECT().GeneratePassword(gomock.Any(), updateReq.Password).Return("hashedNewPassword", nil)
 				mocks.UsersRepository.EXPECT().UpdatePassword(gomock.Any(), userID, gomock.Any()).Return(errDb)
 			},
 			wantErr:     true,
 			expectedErr: errDb,
 		},
+		{
+			name: "BreachedPasswordError",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), userID).Return(existingUser, nil)
+				mocks.PasswordUtils.EXPECT().ComparePassword(gomock.Any(), updateReq.OldPassword, existingUser.Password).Return(true, nil)
+				mocks.PasswordUtils.EXPECT().IsBreached(gomock.Any(), updateReq.Password).Return(true, nil)
+			},
+			wantErr:     true,
+			expectedErr: errBreachedPassword,
+		},
 	}
 
 	for _, tt := range tests {
@@ -209,6 +373,7 @@ func TestUserService_DeleteUser(t *testing.T) {
 	svc := buildTestService(mocks)
 
 	userID := 1
+	version := 2
 	errDb := errors.New("db error")
 
 	tests := []struct {
@@ -220,22 +385,35 @@ func TestUserService_DeleteUser(t *testing.T) {
 		{
 			name: "Success",
 			setup: func() {
-				mocks.UsersRepository.EXPECT().DeleteUser(gomock.Any(), userID).Return(nil)
+				// удаление откладывается на срок ожидания, данные пока не трогаются
+				mocks.UsersRepository.EXPECT().ScheduleUserDeletion(gomock.Any(), userID, version, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ int, deleteAfter time.Time) error {
+						require.WithinDuration(t, time.Now().Add(720*time.Hour), deleteAfter, time.Minute)
+						return nil
+					})
 			},
 			wantErr: false,
 		},
 		{
 			name: "UserNotFoundError",
 			setup: func() {
-				mocks.UsersRepository.EXPECT().DeleteUser(gomock.Any(), userID).Return(sql.ErrNoRows)
+				mocks.UsersRepository.EXPECT().ScheduleUserDeletion(gomock.Any(), userID, version, gomock.Any()).Return(sql.ErrNoRows)
 			},
 			wantErr:     true,
-			expectedErr: sql.ErrNoRows,
+			expectedErr: appErrors.ErrNotFound,
+		},
+		{
+			name: "VersionConflictError",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().ScheduleUserDeletion(gomock.Any(), userID, version, gomock.Any()).Return(appErrors.ErrVersionConflict)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrVersionConflict,
 		},
 		{
 			name: "DeleteUserDBError",
 			setup: func() {
-				mocks.UsersRepository.EXPECT().DeleteUser(gomock.Any(), userID).Return(errDb)
+				mocks.UsersRepository.EXPECT().ScheduleUserDeletion(gomock.Any(), userID, version, gomock.Any()).Return(errDb)
 			},
 			wantErr:     true,
 			expectedErr: errDb,
@@ -245,8 +423,102 @@ func TestUserService_DeleteUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			err := svc.DeleteUser(context.Background(), userID)
+			user, err := svc.DeleteUser(context.Background(), userID, version)
+			requireEqualError(t, err, tt.expectedErr)
+			if !tt.wantErr {
+				require.Equal(t, models.UserStatusPendingDeletion, user.Status)
+				require.NotNil(t, user.DeleteAfter)
+				require.Equal(t, version+1, user.Version)
+			}
+		})
+	}
+}
+
+func TestUserService_DeactivateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	mocks.UsersRepository.EXPECT().SetUserStatus(gomock.Any(), 1, models.UserStatusDeactivated, nil).Return(nil)
+	require.NoError(t, svc.DeactivateUser(context.Background(), 1))
+
+	mocks.UsersRepository.EXPECT().SetUserStatus(gomock.Any(), 2, models.UserStatusDeactivated, nil).Return(sql.ErrNoRows)
+	requireEqualError(t, svc.DeactivateUser(context.Background(), 2), appErrors.ErrNotFound)
+}
+
+func TestUserService_PurgeDeletedUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	errPublish := errors.New("publish error")
+
+	tests := []struct {
+		name        string
+		setup       func()
+		expected    int
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().PurgeDeletedUsers(gomock.Any(), 100, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, beforeCommit func([]int) error) ([]int, error) {
+						userIDs := []int{3, 7}
+						return userIDs, beforeCommit(userIDs)
+					})
+				// каждый обезличенный пользователь получает событие для других сервисов
+				for _, userID := range []int{3, 7} {
+					userID := userID
+					mocks.UserEventsProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(data []byte, requestID string) error {
+							var event models.UserEvent
+							require.NoError(t, json.Unmarshal(data, &event))
+							require.Equal(t, models.UserEventTypeDeleted, event.EventType)
+							require.Equal(t, userID, event.UserID)
+							return nil
+						})
+				}
+			},
+			expected: 2,
+		},
+		{
+			name: "NothingToPurge",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().PurgeDeletedUsers(gomock.Any(), 100, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, _ func([]int) error) ([]int, error) {
+						return []int{}, nil
+					})
+			},
+			expected: 0,
+		},
+		{
+			// при ошибке отправки транзакция откатывается и пользователи будут обработаны повторно
+			name: "PublishError",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().PurgeDeletedUsers(gomock.Any(), 100, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, beforeCommit func([]int) error) ([]int, error) {
+						if err := beforeCommit([]int{3}); err != nil {
+							return nil, err
+						}
+						return []int{3}, nil
+					})
+				mocks.UserEventsProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errPublish)
+			},
+			expectedErr: errPublish,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			purged, err := svc.PurgeDeletedUsers(context.Background())
 			requireEqualError(t, err, tt.expectedErr)
+			require.Equal(t, tt.expected, purged)
 		})
 	}
 }
@@ -263,6 +535,7 @@ func TestUserService_GetUserByID(t *testing.T) {
 		ID:       userID,
 		Username: "existinguser",
 		Email:    "existinguser@example.com",
+		Version:  3,
 	}
 
 	errDb := errors.New("db error")
@@ -301,8 +574,12 @@ func TestUserService_GetUserByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			_, err := svc.GetUserByID(context.Background(), userID)
+			user, err := svc.GetUserByID(context.Background(), userID)
 			requireEqualError(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				// по версии gateway проверяет If-Match при изменении профиля
+				require.Equal(t, existingUser.Version, user.Version)
+			}
 		})
 	}
 }
@@ -318,6 +595,7 @@ func TestUserService_GetUserByUsernameOrEmail(t *testing.T) {
 	existingUser := &models.UserDAO{
 		Username: username,
 		Email:    email,
+		Version:  3,
 	}
 
 	errDb := errors.New("db error")
@@ -356,8 +634,12 @@ func TestUserService_GetUserByUsernameOrEmail(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			_, err := svc.GetUserByUsernameOrEmail(context.Background(), username, email)
+			user, err := svc.GetUserByUsernameOrEmail(context.Background(), username, email)
 			requireEqualError(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				// по версии gateway проверяет If-Match при изменении профиля
+				require.Equal(t, existingUser.Version, user.Version)
+			}
 		})
 	}
 }
@@ -395,6 +677,32 @@ func TestUserService_Login(t *testing.T) {
 			setup: func() {
 				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), loginReq.Username, loginReq.Email).Return(existingUser, nil)
 				mocks.PasswordUtils.EXPECT().ComparePassword(gomock.Any(), loginReq.Password, existingUser.Password).Return(true, nil)
+				mocks.PasswordUtils.EXPECT().NeedsRehash(existingUser.Password).Return(false)
+			},
+			wantErr: false,
+		},
+		{
+			name: "SuccessWithRehash", // Хэш со старыми параметрами пересчитывается при входе.
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), loginReq.Username, loginReq.Email).Return(existingUser, nil)
+				mocks.PasswordUtils.EXPECT().ComparePassword(gomock.Any(), loginReq.Password, existingUser.Password).Return(true, nil)
+				mocks.PasswordUtils.EXPECT().NeedsRehash(existingUser.Password).Return(true)
+				mocks.PasswordUtils.EXPECT().GeneratePassword(gomock.Any(), loginReq.Password).Return("newHashedPassword", nil)
+				mocks.UsersRepository.EXPECT().UpdatePassword(gomock.Any(), existingUser.ID, "newHashedPassword").Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "ReactivatesPendingDeletion", // Вход отменяет запланированное удаление.
+			setup: func() {
+				deleteAfter := time.Now().Add(time.Hour)
+				pendingUser := *existingUser
+				pendingUser.Status = models.UserStatusPendingDeletion
+				pendingUser.DeleteAfter = &deleteAfter
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), loginReq.Username, loginReq.Email).Return(&pendingUser, nil)
+				mocks.PasswordUtils.EXPECT().ComparePassword(gomock.Any(), loginReq.Password, existingUser.Password).Return(true, nil)
+				mocks.PasswordUtils.EXPECT().NeedsRehash(existingUser.Password).Return(false)
+				mocks.UsersRepository.EXPECT().SetUserStatus(gomock.Any(), existingUser.ID, models.UserStatusActive, nil).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -424,3 +732,724 @@ func TestUserService_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_UpdateUserRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	request := &models.UpdateUserRoleDTO{ID: 1, Role: models.RoleAdmin}
+	errDb := errors.New("db error")
+
+	tests := []struct {
+		name        string
+		request     *models.UpdateUserRoleDTO
+		setup       func()
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:    "Success",
+			request: request,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), request.ID).Return(&models.UserDAO{ID: request.ID}, nil)
+				mocks.UsersRepository.EXPECT().UpdateUserRole(gomock.Any(), request.ID, request.Role).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "UnknownRole",
+			request: &models.UpdateUserRoleDTO{ID: 1, Role: "root"},
+			setup:   func() {},
+			wantErr: true,
+		},
+		{
+			name:    "UserNotFoundError",
+			request: request,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), request.ID).Return(nil, sql.ErrNoRows)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrNotFound,
+		},
+		{
+			name:    "UpdateRoleDBError",
+			request: request,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), request.ID).Return(&models.UserDAO{ID: request.ID}, nil)
+				mocks.UsersRepository.EXPECT().UpdateUserRole(gomock.Any(), request.ID, request.Role).Return(errDb)
+			},
+			wantErr:     true,
+			expectedErr: errDb,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := svc.UpdateUserRole(context.Background(), tt.request)
+			if tt.wantErr && tt.expectedErr == nil {
+				require.Error(t, err)
+				return
+			}
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestUserService_ConfirmTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	secret, _, err := svc.mfa.GenerateSecret("test")
+	require.NoError(t, err)
+	encryptedSecret, err := svc.mfa.EncryptSecret(secret)
+	require.NoError(t, err)
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+
+	enrolledUser := &models.UserDAO{ID: 1, TOTPSecret: &encryptedSecret}
+
+	tests := []struct {
+		name        string
+		request     *models.MFACodeDTO
+		setup       func()
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:    "Success",
+			request: &models.MFACodeDTO{ID: 1, Code: code},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(enrolledUser, nil)
+				mocks.UsersRepository.EXPECT().UseTOTPStep(gomock.Any(), 1, gomock.Any()).Return(true, nil)
+				mocks.UsersRepository.EXPECT().EnableTOTP(gomock.Any(), 1, gomock.Len(10)).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "ReplayedCode",
+			request: &models.MFACodeDTO{ID: 1, Code: code},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(enrolledUser, nil)
+				mocks.UsersRepository.EXPECT().UseTOTPStep(gomock.Any(), 1, gomock.Any()).Return(false, nil)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrInvalidMFACode,
+		},
+		{
+			name:    "InvalidCode",
+			request: &models.MFACodeDTO{ID: 1, Code: "000000000"},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(enrolledUser, nil)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrInvalidMFACode,
+		},
+		{
+			name:    "NotEnrolled",
+			request: &models.MFACodeDTO{ID: 1, Code: code},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(&models.UserDAO{ID: 1}, nil)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrMFANotEnrolled,
+		},
+		{
+			name:    "AlreadyEnabled",
+			request: &models.MFACodeDTO{ID: 1, Code: code},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).
+					Return(&models.UserDAO{ID: 1, TOTPSecret: &encryptedSecret, TOTPEnabled: true}, nil)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrMFAAlreadyEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			codes, err := svc.ConfirmTOTP(context.Background(), tt.request)
+			requireEqualError(t, err, tt.expectedErr)
+			if !tt.wantErr {
+				require.Len(t, codes, 10)
+			}
+		})
+	}
+}
+
+func TestUserService_VerifyMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	secret, _, err := svc.mfa.GenerateSecret("test")
+	require.NoError(t, err)
+	encryptedSecret, err := svc.mfa.EncryptSecret(secret)
+	require.NoError(t, err)
+	codeTime := time.Now()
+	code, err := totp.GenerateCode(secret, codeTime)
+	require.NoError(t, err)
+
+	user := &models.UserDAO{ID: 1, Username: "test", TOTPSecret: &encryptedSecret, TOTPEnabled: true}
+	recoveryCode := "abcdabcd-abcdabcd"
+
+	tests := []struct {
+		name        string
+		request     *models.MFACodeDTO
+		setup       func()
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:    "SuccessTOTP",
+			request: &models.MFACodeDTO{ID: 1, Code: code},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(user, nil)
+				mocks.UsersRepository.EXPECT().UseTOTPStep(gomock.Any(), 1, codeTime.Unix()/30).Return(true, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "ReplayedTOTP",
+			request: &models.MFACodeDTO{ID: 1, Code: code},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(user, nil)
+				mocks.UsersRepository.EXPECT().UseTOTPStep(gomock.Any(), 1, gomock.Any()).Return(false, nil)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrInvalidMFACode,
+		},
+		{
+			name:    "SuccessRecoveryCode",
+			request: &models.MFACodeDTO{ID: 1, Code: recoveryCode},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(user, nil)
+				mocks.UsersRepository.EXPECT().UseRecoveryCode(gomock.Any(), 1, mfa.HashRecoveryCode(recoveryCode)).Return(true, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "UsedRecoveryCode",
+			request: &models.MFACodeDTO{ID: 1, Code: recoveryCode},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(user, nil)
+				mocks.UsersRepository.EXPECT().UseRecoveryCode(gomock.Any(), 1, mfa.HashRecoveryCode(recoveryCode)).Return(false, nil)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrInvalidMFACode,
+		},
+		{
+			name:    "MFANotEnabled",
+			request: &models.MFACodeDTO{ID: 1, Code: code},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(&models.UserDAO{ID: 1}, nil)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrMFANotEnrolled,
+		},
+		{
+			name:    "UserNotFound",
+			request: &models.MFACodeDTO{ID: 1, Code: code},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(nil, sql.ErrNoRows)
+			},
+			wantErr:     true,
+			expectedErr: appErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			result, err := svc.VerifyMFA(context.Background(), tt.request)
+			requireEqualError(t, err, tt.expectedErr)
+			if !tt.wantErr {
+				require.Equal(t, user.ID, result.ID)
+				require.True(t, result.MFAEnabled)
+			}
+		})
+	}
+}
+
+func TestUserService_ReportAccountLocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	event := &models.AccountLockedDTO{Username: "test", IP: "203.0.113.10", LockedUntil: time.Now().Add(time.Minute)}
+	errPublish := errors.New("publish error")
+
+	tests := []struct {
+		name        string
+		setup       func()
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), event.Username, event.Email).
+					Return(&models.UserDAO{ID: 1, Email: "test@example.com"}, nil)
+				mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(data []byte, requestID string) error {
+						var item models.UserMailItem
+						require.NoError(t, json.Unmarshal(data, &item))
+						require.Equal(t, models.UserEventTypeAccountLocked, item.UserEventType)
+						require.Equal(t, []string{"test@example.com"}, item.Receivers)
+						require.Equal(t, event.IP, item.IP)
+						return nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name: "UnknownUser",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), event.Username, event.Email).
+					Return(nil, sql.ErrNoRows)
+			},
+			wantErr: false,
+		},
+		{
+			name: "PublishError",
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), event.Username, event.Email).
+					Return(&models.UserDAO{ID: 1, Email: "test@example.com"}, nil)
+				mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errPublish)
+			},
+			wantErr:     true,
+			expectedErr: errPublish,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := svc.ReportAccountLocked(context.Background(), event)
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestUserService_LinkExternalIdentity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	identity := &models.ExternalIdentityDTO{
+		Provider:      "google",
+		Subject:       "1234567890",
+		Email:         "test@example.com",
+		EmailVerified: true,
+		Username:      "Test User!",
+	}
+	unverified := *identity
+	unverified.EmailVerified = false
+	existing := &models.UserDAO{ID: 1, Username: "test", Email: identity.Email, Role: models.RoleUser}
+
+	tests := []struct {
+		name         string
+		identity     *models.ExternalIdentityDTO
+		setup        func()
+		wantUsername string
+		expectedErr  error
+	}{
+		{
+			name:     "AlreadyLinked",
+			identity: identity,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByExternalIdentity(gomock.Any(), identity.Provider, identity.Subject).
+					Return(existing, nil)
+			},
+			wantUsername: existing.Username,
+		},
+		{
+			name:     "LinkByVerifiedEmail",
+			identity: identity,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByExternalIdentity(gomock.Any(), identity.Provider, identity.Subject).
+					Return(nil, sql.ErrNoRows)
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), "", identity.Email).
+					Return(existing, nil)
+				mocks.UsersRepository.EXPECT().CreateExternalIdentity(gomock.Any(), existing.ID, identity).Return(nil)
+			},
+			wantUsername: existing.Username,
+		},
+		{
+			name:     "UnverifiedEmailIsUsed",
+			identity: &unverified,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByExternalIdentity(gomock.Any(), identity.Provider, identity.Subject).
+					Return(nil, sql.ErrNoRows)
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), "", identity.Email).
+					Return(existing, nil)
+			},
+			expectedErr: appErrors.ErrUsernameOrEmailIsUsed,
+		},
+		{
+			name:     "CreateUser",
+			identity: identity,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByExternalIdentity(gomock.Any(), identity.Provider, identity.Subject).
+					Return(nil, sql.ErrNoRows)
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), "", identity.Email).
+					Return(nil, sql.ErrNoRows)
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), "TestUser", "").
+					Return(existing, nil)
+				mocks.UsersRepository.EXPECT().GetUserByUsernameOrEmail(gomock.Any(), gomock.Any(), "").
+					Return(nil, sql.ErrNoRows)
+				mocks.PasswordUtils.EXPECT().GeneratePassword(gomock.Any(), gomock.Any()).Return("hash", nil)
+				mocks.UsersRepository.EXPECT().CreateUserWithExternalIdentity(gomock.Any(), gomock.Any(), identity).
+					DoAndReturn(func(_ context.Context, user *models.CreateUserDTO, _ *models.ExternalIdentityDTO) (int, error) {
+						require.Regexp(t, `^TestUser_\d{4}$`, user.Username)
+						require.Equal(t, "hash", user.Password)
+						require.Equal(t, identity.Email, user.Email)
+						return 2, nil
+					})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			user, err := svc.LinkExternalIdentity(context.Background(), tt.identity)
+			requireEqualError(t, err, tt.expectedErr)
+			if tt.expectedErr == nil && tt.wantUsername != "" {
+				require.Equal(t, tt.wantUsername, user.Username)
+			}
+		})
+	}
+}
+
+func TestUserService_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		request        *models.CreateAPIKeyDTO
+		setup          func()
+		wantValidation bool
+	}{
+		{
+			name:    "Success",
+			request: &models.CreateAPIKeyDTO{UserID: 1, Name: "ci", Scopes: []string{models.ScopeTodosRead}},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, key *models.APIKeyDAO) (int, time.Time, error) {
+						require.Len(t, key.KeyHash, 64)
+						require.Len(t, key.Prefix, apiKeyDisplayLen)
+						return 1, time.Now(), nil
+					})
+			},
+		},
+		{
+			name:           "UnknownScope",
+			request:        &models.CreateAPIKeyDTO{UserID: 1, Name: "ci", Scopes: []string{"admin"}},
+			setup:          func() {},
+			wantValidation: true,
+		},
+		{
+			name:           "ExpiresInPast",
+			request:        &models.CreateAPIKeyDTO{UserID: 1, Name: "ci", Scopes: []string{models.ScopeTodosRead}, ExpiresAt: &past},
+			setup:          func() {},
+			wantValidation: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			key, err := svc.CreateAPIKey(context.Background(), tt.request)
+			if tt.wantValidation {
+				var validationErr *validator.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+			// ключ показывается один раз, в списке его можно узнать по префиксу
+			require.True(t, strings.HasPrefix(key.Key, apiKeyPrefix))
+			require.True(t, strings.HasPrefix(key.Key, key.Prefix))
+		})
+	}
+}
+
+func TestUserService_AuthenticateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	key, err := generateAPIKey()
+	require.NoError(t, err)
+
+	expired := time.Now().Add(-time.Minute)
+	stored := &models.APIKeyDAO{ID: 1, UserID: 2, Scopes: []string{models.ScopeTodosRead}, UserRole: models.RoleUser}
+
+	tests := []struct {
+		name        string
+		key         string
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			key:  key,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey(key)).Return(stored, nil)
+				mocks.UsersRepository.EXPECT().TouchAPIKey(gomock.Any(), stored.ID).Return(nil)
+			},
+		},
+		{
+			name:        "Malformed",
+			key:         "Bearer something",
+			setup:       func() {},
+			expectedErr: appErrors.ErrWrongCredentials,
+		},
+		{
+			name: "Unknown",
+			key:  key,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey(key)).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: appErrors.ErrWrongCredentials,
+		},
+		{
+			name: "Expired",
+			key:  key,
+			setup: func() {
+				expiredKey := *stored
+				expiredKey.ExpiresAt = &expired
+				mocks.UsersRepository.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey(key)).Return(&expiredKey, nil)
+			},
+			expectedErr: appErrors.ErrWrongCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			principal, err := svc.AuthenticateAPIKey(context.Background(), tt.key)
+			requireEqualError(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				require.Equal(t, stored.UserID, principal.UserID)
+				require.Equal(t, stored.Scopes, principal.Scopes)
+			}
+		})
+	}
+}
+
+func TestUserService_RequestEmailChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	user := &models.UserDAO{ID: 1, Username: "test", Email: "old@example.com"}
+	request := &models.EmailChangeRequestDTO{UserID: 1, NewEmail: "new@example.com"}
+	errPublish := errors.New("publish error")
+
+	tests := []struct {
+		name        string
+		request     *models.EmailChangeRequestDTO
+		setup       func()
+		expectedErr error
+	}{
+		{
+			name:    "Success",
+			request: request,
+			setup: func() {
+				var storedHash string
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(user, nil)
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), request.NewEmail, 1).Return(false, nil)
+				mocks.UsersRepository.EXPECT().SetPendingEmail(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, pending *models.PendingEmailDAO) error {
+						require.Equal(t, 1, pending.UserID)
+						require.Equal(t, request.NewEmail, pending.Email)
+						require.True(t, pending.ExpiresAt.After(time.Now().Add(23*time.Hour)))
+						storedHash = pending.TokenHash
+						return nil
+					})
+				gomock.InOrder(
+					// ссылка уходит на новый адрес, в базе лежит только хэш токена из ссылки
+					mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(data []byte, requestID string) error {
+							var item models.UserMailItem
+							require.NoError(t, json.Unmarshal(data, &item))
+							require.Equal(t, models.UserEventTypeEmailChangeConfirm, item.UserEventType)
+							require.Equal(t, []string{request.NewEmail}, item.Receivers)
+
+							prefix := "http://localhost:3000/api/v1/users/email/confirm?token="
+							require.True(t, strings.HasPrefix(item.Link, prefix), item.Link)
+							require.Equal(t, storedHash, hashAPIKey(strings.TrimPrefix(item.Link, prefix)))
+							return nil
+						}),
+					mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(data []byte, requestID string) error {
+							var item models.UserMailItem
+							require.NoError(t, json.Unmarshal(data, &item))
+							require.Equal(t, models.UserEventTypeEmailChangeNotice, item.UserEventType)
+							require.Equal(t, []string{user.Email}, item.Receivers)
+							require.Equal(t, request.NewEmail, item.NewEmail)
+							require.Empty(t, item.Link)
+							return nil
+						}),
+				)
+			},
+		},
+		{
+			name:        "InvalidEmail",
+			request:     &models.EmailChangeRequestDTO{UserID: 1, NewEmail: "not-an-email"},
+			setup:       func() {},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:    "SameEmail",
+			request: &models.EmailChangeRequestDTO{UserID: 1, NewEmail: user.Email},
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(user, nil)
+			},
+			expectedErr: &validator.ValidationError{},
+		},
+		{
+			name:    "UserNotFound",
+			request: request,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+		{
+			name:    "EmailUsed",
+			request: request,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(user, nil)
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), request.NewEmail, 1).Return(true, nil)
+			},
+			expectedErr: appErrors.ErrUsernameOrEmailIsUsed,
+		},
+		{
+			name:    "PublishError",
+			request: request,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).Return(user, nil)
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), request.NewEmail, 1).Return(false, nil)
+				mocks.UsersRepository.EXPECT().SetPendingEmail(gomock.Any(), gomock.Any()).Return(nil)
+				mocks.RabbitProducer.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errPublish)
+			},
+			expectedErr: errPublish,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			err := svc.RequestEmailChange(context.Background(), tt.request)
+			if _, ok := tt.expectedErr.(*validator.ValidationError); ok {
+				var validationErr *validator.ValidationError
+				require.True(t, errors.As(err, &validationErr), "expected validation error, got: %v", err)
+				return
+			}
+			requireEqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestUserService_ConfirmEmailChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := getMocks(ctrl)
+	svc := buildTestService(mocks)
+
+	token := "confirmation-token"
+	tokenHash := hashAPIKey(token)
+	pending := &models.PendingEmailDAO{UserID: 1, Email: "new@example.com", TokenHash: tokenHash}
+
+	tests := []struct {
+		name        string
+		token       string
+		setup       func()
+		expected    *models.UserDTO
+		expectedErr error
+	}{
+		{
+			name:  "Success",
+			token: token,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetPendingEmail(gomock.Any(), tokenHash).Return(pending, nil)
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), pending.Email, 1).Return(false, nil)
+				mocks.UsersRepository.EXPECT().ConfirmEmailChange(gomock.Any(), 1, tokenHash).Return(true, nil)
+				mocks.UsersRepository.EXPECT().GetUserByID(gomock.Any(), 1).
+					Return(&models.UserDAO{ID: 1, Username: "test", Email: pending.Email}, nil)
+			},
+			expected: &models.UserDTO{ID: 1, Username: "test", Email: "new@example.com"},
+		},
+		{
+			name:        "EmptyToken",
+			token:       "",
+			setup:       func() {},
+			expectedErr: appErrors.ErrNotFound,
+		},
+		{
+			name:  "UnknownOrExpiredToken",
+			token: token,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetPendingEmail(gomock.Any(), tokenHash).Return(nil, sql.ErrNoRows)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+		{
+			name:  "EmailTakenMeanwhile",
+			token: token,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetPendingEmail(gomock.Any(), tokenHash).Return(pending, nil)
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), pending.Email, 1).Return(true, nil)
+			},
+			expectedErr: appErrors.ErrUsernameOrEmailIsUsed,
+		},
+		{
+			name:  "TokenUsedConcurrently",
+			token: token,
+			setup: func() {
+				mocks.UsersRepository.EXPECT().GetPendingEmail(gomock.Any(), tokenHash).Return(pending, nil)
+				mocks.UsersRepository.EXPECT().EmailIsUsed(gomock.Any(), pending.Email, 1).Return(false, nil)
+				mocks.UsersRepository.EXPECT().ConfirmEmailChange(gomock.Any(), 1, tokenHash).Return(false, nil)
+			},
+			expectedErr: appErrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			user, err := svc.ConfirmEmailChange(context.Background(), tt.token)
+			requireEqualError(t, err, tt.expectedErr)
+			if tt.expected != nil {
+				require.Equal(t, tt.expected.Email, user.Email)
+				require.Equal(t, tt.expected.ID, user.ID)
+			}
+		})
+	}
+}
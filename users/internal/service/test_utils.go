@@ -5,28 +5,67 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
+	"users/config"
 	"users/internal/service/mocks"
+	"users/pkg/mfa"
+	"users/pkg/validator"
 )
 
 type Mocks struct {
-	UsersRepository *mocks.MockUserRepository
-	RabbitProducer  *mocks.MockRabbitProducer
-	PasswordUtils   *mocks.MockPasswordUtils
+	UsersRepository    *mocks.MockUserRepository
+	RabbitProducer     *mocks.MockRabbitProducer
+	PasswordUtils      *mocks.MockPasswordUtils
+	UserEventsProducer *mocks.MockRabbitProducer
 }
 
 func getMocks(ctrl *gomock.Controller) *Mocks {
 	return &Mocks{
-		UsersRepository: mocks.NewMockUserRepository(ctrl),
-		RabbitProducer:  mocks.NewMockRabbitProducer(ctrl),
-		PasswordUtils:   mocks.NewMockPasswordUtils(ctrl),
+		UsersRepository:    mocks.NewMockUserRepository(ctrl),
+		RabbitProducer:     mocks.NewMockRabbitProducer(ctrl),
+		PasswordUtils:      mocks.NewMockPasswordUtils(ctrl),
+		UserEventsProducer: mocks.NewMockRabbitProducer(ctrl),
 	}
 }
 
 func buildTestService(m *Mocks) *UserService {
+	userMFA, err := mfa.New(&mfa.Config{
+		Issuer:             "ToDo",
+		EncryptionKey:      "ZGV2LW9ubHktbWZhLWVuY3J5cHRpb24ta2V5LTMyYiE=",
+		RecoveryCodesCount: 10,
+	})
+	if err != nil {
+		panic(err)
+	}
+
 	return NewUserService(
 		m.UsersRepository,
 		m.RabbitProducer,
 		m.PasswordUtils,
+		validator.New(&validator.Config{
+			UsernameMinLen:       3,
+			UsernameMaxLen:       32,
+			EmailMaxLen:          254,
+			PasswordMinLen:       8,
+			PasswordMaxLen:       72,
+			PasswordRequireUpper: true,
+			PasswordRequireLower: true,
+			PasswordRequireDigit: true,
+			DescriptionMaxLen:    1000,
+			CommentMaxLen:        4000,
+			LabelNameMaxLen:      50,
+			WorkspaceNameMaxLen:  100,
+		}),
+		userMFA,
+		&config.EmailChange{
+			ConfirmURL: "http://localhost:3000/api/v1/users/email/confirm",
+			TTL:        24 * time.Hour,
+		},
+		&config.Deletion{
+			GracePeriod: 720 * time.Hour,
+			BatchSize:   100,
+		},
+		m.UserEventsProducer,
 	)
 }
 
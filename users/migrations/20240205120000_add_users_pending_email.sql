@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_change_token_hash TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_change_expires_at TIMESTAMP WITH TIME ZONE;
+CREATE UNIQUE INDEX IF NOT EXISTS users_pending_email_key ON users (pending_email) WHERE pending_email IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_change_token_hash_key ON users (email_change_token_hash) WHERE email_change_token_hash IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_email_change_token_hash_key;
+DROP INDEX IF EXISTS users_pending_email_key;
+ALTER TABLE users DROP COLUMN IF EXISTS email_change_expires_at;
+ALTER TABLE users DROP COLUMN IF EXISTS email_change_token_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS pending_email;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- уникальность без учета регистра; если в таблице уже есть дубликаты, миграция упадет и их нужно разобрать вручную
+ALTER TABLE users ALTER COLUMN username SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT users_email_required CHECK (email IS NOT NULL OR status = 'deleted');
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_lower_key ON users (lower(username));
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (lower(email)) WHERE email IS NOT NULL;
+DROP INDEX IF EXISTS users_pending_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_pending_email_lower_key ON users (lower(pending_email)) WHERE pending_email IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_pending_email_lower_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_pending_email_key ON users (pending_email) WHERE pending_email IS NOT NULL;
+DROP INDEX IF EXISTS users_email_lower_key;
+DROP INDEX IF EXISTS users_username_lower_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_required;
+ALTER TABLE users ALTER COLUMN username DROP NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- version растет при каждом изменении пользователя, по ней gateway отдает ETag и проверяет If-Match
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- totp_last_step - номер 30-секундного окна последнего принятого TOTP кода, код этого или более раннего окна не принимается повторно
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+-- +goose StatementEnd
@@ -0,0 +1,161 @@
+package mfa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	recoveryCodeBytes = 5
+	// сколько соседних 30-секундных окон принимаем из-за расхождения часов
+	totpSkew   = 1
+	totpPeriod = 30
+	// encryptionKeySize - размер ключа AES-256
+	encryptionKeySize = 32
+)
+
+type Config struct {
+	Issuer string `envconfig:"MFA_ISSUER" required:"true" default:"ToDo"`
+	// EncryptionKey - ключ AES-256 в base64, которым шифруются TOTP секреты в базе.
+	// Значения по умолчанию нет: без ключа сервис не запускается
+	EncryptionKey      string `envconfig:"MFA_ENCRYPTION_KEY" required:"true"`
+	RecoveryCodesCount int    `envconfig:"MFA_RECOVERY_CODES_COUNT" required:"true" default:"10"`
+}
+
+// MFA выпускает и проверяет TOTP секреты и коды восстановления
+type MFA struct {
+	cfg  *Config
+	aead cipher.AEAD
+}
+
+func New(cfg *Config) (*MFA, error) {
+	key, err := base64.StdEncoding.DecodeString(cfg.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("[New] decode encryption key: %w", err)
+	}
+
+	if len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("[New] encryption key must be %d bytes, got %d", encryptionKeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("[New] create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("[New] create gcm: %w", err)
+	}
+
+	return &MFA{
+		cfg:  cfg,
+		aead: aead,
+	}, nil
+}
+
+// GenerateSecret создает TOTP секрет и otpauth:// URI для приложения-аутентификатора
+func (m *MFA) GenerateSecret(accountName string) (string, string, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      m.cfg.Issuer,
+		AccountName: accountName,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return key.Secret(), key.URL(), nil
+}
+
+// ValidateCode проверяет одноразовый код из приложения-аутентификатора и возвращает номер
+// 30-секундного окна, которому он соответствует. По номеру окна сервис не дает использовать код повторно
+func (m *MFA) ValidateCode(code, secret string) (int64, bool) {
+	code = strings.TrimSpace(code)
+	now := time.Now().UTC()
+
+	for offset := -totpSkew; offset <= totpSkew; offset++ {
+		at := now.Add(time.Duration(offset*totpPeriod) * time.Second)
+
+		valid, err := totp.ValidateCustom(code, secret, at, totp.ValidateOpts{
+			Period: totpPeriod,
+			Digits: 6,
+		})
+		if err == nil && valid {
+			return at.Unix() / totpPeriod, true
+		}
+	}
+
+	return 0, false
+}
+
+// EncryptSecret шифрует секрет перед сохранением в базу. Nonce хранится перед шифротекстом
+func (m *MFA) EncryptSecret(secret string) (string, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := m.aead.Seal(nonce, nonce, []byte(secret), nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (m *MFA) DecryptSecret(encrypted string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < m.aead.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:m.aead.NonceSize()], sealed[m.aead.NonceSize():]
+	secret, err := m.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret), nil
+}
+
+// GenerateRecoveryCodes создает одноразовые коды восстановления и их хэши для хранения в базе
+func (m *MFA) GenerateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, m.cfg.RecoveryCodesCount)
+	hashes := make([]string, 0, m.cfg.RecoveryCodesCount)
+
+	for i := 0; i < m.cfg.RecoveryCodesCount; i++ {
+		raw := make([]byte, recoveryCodeBytes*2)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+
+		encoded := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw))
+		code := encoded[:8] + "-" + encoded[8:16]
+
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode хэширует код восстановления. Коды случайные и длинные,
+// поэтому медленный хэш как для паролей не нужен
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,118 @@
+package pass_utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+// Пределы параметров из хэша: хэш из базы не должен заставить выделить слишком много памяти
+const (
+	// maxArgon2Memory - 1 ГиБ, память задается в КиБ
+	maxArgon2Memory = 1 << 20
+	maxArgon2KeyLen = 1024
+)
+
+type argon2Params struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+}
+
+// argon2idHasher хранит параметры в хэше: $argon2id$v=19$m=65536,t=1,p=4$<salt>$<hash>
+type argon2idHasher struct {
+	params argon2Params
+}
+
+func newArgon2idHasher(cfg *PasswordConfig) *argon2idHasher {
+	return &argon2idHasher{
+		params: argon2Params{
+			Time:    cfg.Time,
+			Memory:  cfg.Memory,
+			Threads: cfg.Threads,
+			KeyLen:  cfg.KeyLen,
+		},
+	}
+}
+
+// Hash создает хэш на основе библиотеки golang.org/x/crypto/argon2
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	p := h.params
+	hash := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+
+	// Base64 encode the salt and hashed password.
+	b64Salt := base64.RawStdEncoding.EncodeToString(salt)
+	b64Hash := base64.RawStdEncoding.EncodeToString(hash)
+
+	format := "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s"
+	full := fmt.Sprintf(format, argon2.Version, p.Memory, p.Time, p.Threads, b64Salt, b64Hash)
+	return full, nil
+}
+
+func (h *argon2idHasher) Verify(password, hash string) (bool, error) {
+	params, salt, decodedHash, err := decodeArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+
+	comparisonHash := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, params.KeyLen)
+
+	return subtle.ConstantTimeCompare(decodedHash, comparisonHash) == 1, nil
+}
+
+func (h *argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, _, err := decodeArgon2id(hash)
+	if err != nil {
+		return false
+	}
+
+	return params != h.params
+}
+
+// decodeArgon2id разбирает хэш. Хэш приходит из базы, поэтому формат проверяется до обращения к частям
+func decodeArgon2id(hash string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, fmt.Errorf("%w: version: %s", ErrMalformedHash, err)
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("%w: unsupported argon2 version %d", ErrMalformedHash, version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, fmt.Errorf("%w: params: %s", ErrMalformedHash, err)
+	}
+	// при нулевых параметрах argon2.IDKey паникует
+	if params.Time == 0 || params.Threads == 0 || params.Memory == 0 || params.Memory > maxArgon2Memory {
+		return params, nil, nil, fmt.Errorf("%w: params out of range", ErrMalformedHash)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("%w: salt: %s", ErrMalformedHash, err)
+	}
+
+	decodedHash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(decodedHash) == 0 || len(decodedHash) > maxArgon2KeyLen {
+		return params, nil, nil, fmt.Errorf("%w: hash", ErrMalformedHash)
+	}
+	params.KeyLen = uint32(len(decodedHash))
+
+	return params, salt, decodedHash, nil
+}
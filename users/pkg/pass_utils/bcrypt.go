@@ -0,0 +1,46 @@
+package pass_utils
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcryptHasher нужен в первую очередь для пользователей, импортированных из систем на bcrypt
+type bcryptHasher struct {
+	cost int
+}
+
+func newBcryptHasher(cfg *PasswordConfig) *bcryptHasher {
+	return &bcryptHasher{cost: cfg.BcryptCost}
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+func (h *bcryptHasher) Verify(password, hash string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %s", ErrMalformedHash, err)
+	}
+}
+
+func (h *bcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false
+	}
+
+	return cost < h.cost
+}
@@ -0,0 +1,64 @@
+package pass_utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// breachPrefixLen - длина префикса SHA-1, по которому разбиты файлы, как в range API haveibeenpwned
+const breachPrefixLen = 5
+
+// BreachChecker проверяет пароли по локальной копии базы утекших паролей.
+// База разбита на файлы по первым 5 символам SHA-1 (например, каталог <dir>/21BD1),
+// в каждом файле строки "<остальные 35 символов>:<сколько раз встречался>".
+// Для проверки читается только один небольшой файл, сам пароль и полный хэш никуда не передаются
+type BreachChecker struct {
+	dir string
+}
+
+func NewBreachChecker(dir string) *BreachChecker {
+	return &BreachChecker{dir: dir}
+}
+
+// IsBreached возвращает true, если пароль есть в базе. Без настроенного каталога проверка выключена
+func (b *BreachChecker) IsBreached(password string) (bool, error) {
+	if b.dir == "" {
+		return false, nil
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:breachPrefixLen], hash[breachPrefixLen:]
+
+	file, err := os.Open(filepath.Join(b.dir, prefix))
+	if err != nil {
+		// нет файла - нет утекших паролей с таким префиксом
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("open breach range %s: %w", prefix, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineSuffix := strings.SplitN(line, ":", 2)[0]
+		if strings.EqualFold(lineSuffix, suffix) {
+			return true, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("read breach range %s: %w", prefix, err)
+	}
+
+	return false, nil
+}
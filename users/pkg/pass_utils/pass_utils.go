@@ -2,78 +2,133 @@ package pass_utils
 
 import (
 	"context"
-	"crypto/rand"
-	"crypto/subtle"
-	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/opentracing/opentracing-go"
-	"golang.org/x/crypto/argon2"
 	"strings"
 )
 
+// алгоритмы хэширования паролей
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var (
+	ErrMalformedHash    = errors.New("malformed password hash")
+	ErrUnknownAlgorithm = errors.New("unknown password hash algorithm")
+)
+
 type PasswordConfig struct {
-	Time    uint32 `envconfig:"PASS_TIME" required:"true" default:"1"`
-	Memory  uint32 `envconfig:"PASS_MEMORY" required:"true" default:"65536"`
-	Threads uint8  `envconfig:"PASS_THREADS" required:"true" default:"4"`
-	KeyLen  uint32 `envconfig:"PASS_KEY_LEN" required:"true" default:"32"`
+	// Algorithm - алгоритм для новых хэшей. Хэши другими алгоритмами проверяются и заменяются при входе
+	Algorithm  string `envconfig:"PASS_ALGORITHM" required:"true" default:"argon2id"`
+	Time       uint32 `envconfig:"PASS_TIME" required:"true" default:"1"`
+	Memory     uint32 `envconfig:"PASS_MEMORY" required:"true" default:"65536"`
+	Threads    uint8  `envconfig:"PASS_THREADS" required:"true" default:"4"`
+	KeyLen     uint32 `envconfig:"PASS_KEY_LEN" required:"true" default:"32"`
+	BcryptCost int    `envconfig:"PASS_BCRYPT_COST" required:"true" default:"12"`
+	// BreachDir - каталог с файлами утекших паролей, пусто - проверка выключена
+	BreachDir string `envconfig:"PASS_BREACH_DIR"`
 }
 
+// Hasher - один алгоритм хэширования паролей
+type Hasher interface {
+	// Hash хэширует пароль с текущими параметрами
+	Hash(password string) (string, error)
+	// Verify сравнивает пароль с хэшем, параметры берутся из самого хэша
+	Verify(password, hash string) (bool, error)
+	// NeedsRehash - хэш создан с параметрами слабее текущих
+	NeedsRehash(hash string) bool
+}
+
+// PasswordUtils хэширует пароли выбранным алгоритмом и проверяет хэши всех поддерживаемых алгоритмов,
+// например bcrypt хэши пользователей, импортированных из другой системы
 type PasswordUtils struct {
-	cfg *PasswordConfig
+	cfg       *PasswordConfig
+	preferred string
+	hashers   map[string]Hasher
+	breaches  *BreachChecker
 }
 
-func NewPasswordUtils(cfg *PasswordConfig) *PasswordUtils {
-	return &PasswordUtils{
-		cfg: cfg,
+func NewPasswordUtils(cfg *PasswordConfig) (*PasswordUtils, error) {
+	u := &PasswordUtils{
+		cfg:       cfg,
+		preferred: cfg.Algorithm,
+		hashers: map[string]Hasher{
+			AlgorithmArgon2id: newArgon2idHasher(cfg),
+			AlgorithmBcrypt:   newBcryptHasher(cfg),
+		},
+		breaches: NewBreachChecker(cfg.BreachDir),
+	}
+
+	if _, ok := u.hashers[u.preferred]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, u.preferred)
 	}
+
+	return u, nil
 }
 
-// GeneratePassword создает пароль на основе библиотеки golang.org/x/crypto/argon2
+// GeneratePassword хэширует пароль выбранным в конфиге алгоритмом
 func (u *PasswordUtils) GeneratePassword(ctx context.Context, password string) (string, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "GeneratePassword")
 	defer span.Finish()
 
-	salt := make([]byte, 16)
-	if _, err := rand.Read(salt); err != nil {
-		return "", err
-	}
-
-	hash := argon2.IDKey([]byte(password), salt, u.cfg.Time, u.cfg.Memory, u.cfg.Threads, u.cfg.KeyLen)
-
-	// Base64 encode the salt and hashed password.
-	b64Salt := base64.RawStdEncoding.EncodeToString(salt)
-	b64Hash := base64.RawStdEncoding.EncodeToString(hash)
-
-	format := "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s"
-	full := fmt.Sprintf(format, argon2.Version, u.cfg.Memory, u.cfg.Time, u.cfg.Threads, b64Salt, b64Hash)
-	return full, nil
+	return u.hashers[u.preferred].Hash(password)
 }
 
-// ComparePassword сравниваем пароль и переданный хэш пароля на основе библиотеки golang.org/x/crypto/argon2
+// ComparePassword сравнивает пароль с хэшем. Алгоритм определяется по префиксу хэша
 func (u *PasswordUtils) ComparePassword(ctx context.Context, password, hash string) (bool, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ComparePassword")
 	defer span.Finish()
 
-	parts := strings.Split(hash, "$")
-
-	c := &PasswordConfig{}
-	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &c.Memory, &c.Time, &c.Threads)
+	hasher, err := u.hasherFor(hash)
 	if err != nil {
 		return false, err
 	}
 
-	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	return hasher.Verify(password, hash)
+}
+
+// NeedsRehash - хэш создан другим алгоритмом или с устаревшими параметрами и его стоит пересчитать
+func (u *PasswordUtils) NeedsRehash(hash string) bool {
+	algorithm, err := algorithmOf(hash)
 	if err != nil {
-		return false, err
+		return false
+	}
+	if algorithm != u.preferred {
+		return true
 	}
 
-	decodedHash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	return u.hashers[algorithm].NeedsRehash(hash)
+}
+
+// IsBreached проверяет пароль по списку утекших паролей
+func (u *PasswordUtils) IsBreached(ctx context.Context, password string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "IsBreached")
+	defer span.Finish()
+
+	return u.breaches.IsBreached(password)
+}
+
+func (u *PasswordUtils) hasherFor(hash string) (Hasher, error) {
+	algorithm, err := algorithmOf(hash)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	c.KeyLen = uint32(len(decodedHash))
 
-	comparisonHash := argon2.IDKey([]byte(password), salt, c.Time, c.Memory, c.Threads, c.KeyLen)
+	return u.hashers[algorithm], nil
+}
 
-	return subtle.ConstantTimeCompare(decodedHash, comparisonHash) == 1, nil
+// algorithmOf определяет алгоритм по префиксу хэша в формате PHC или modular crypt
+func algorithmOf(hash string) (string, error) {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return AlgorithmArgon2id, nil
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return AlgorithmBcrypt, nil
+	case !strings.HasPrefix(hash, "$"):
+		return "", ErrMalformedHash
+	default:
+		return "", ErrUnknownAlgorithm
+	}
 }
@@ -0,0 +1,153 @@
+package pass_utils
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func testConfig() *PasswordConfig {
+	return &PasswordConfig{
+		Algorithm:  AlgorithmArgon2id,
+		Time:       1,
+		Memory:     1024,
+		Threads:    1,
+		KeyLen:     32,
+		BcryptCost: bcrypt.MinCost,
+	}
+}
+
+func newTestUtils(t *testing.T, cfg *PasswordConfig) *PasswordUtils {
+	utils, err := NewPasswordUtils(cfg)
+	require.NoError(t, err)
+	return utils
+}
+
+func TestNewPasswordUtils_UnknownAlgorithm(t *testing.T) {
+	cfg := testConfig()
+	cfg.Algorithm = "md5"
+
+	_, err := NewPasswordUtils(cfg)
+	require.True(t, errors.Is(err, ErrUnknownAlgorithm))
+}
+
+func TestPasswordUtils_Argon2id(t *testing.T) {
+	ctx := context.Background()
+	utils := newTestUtils(t, testConfig())
+
+	hash, err := utils.GeneratePassword(ctx, "Passw0rd")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hash, "$argon2id$"))
+
+	ok, err := utils.ComparePassword(ctx, "Passw0rd", hash)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = utils.ComparePassword(ctx, "wrong", hash)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.False(t, utils.NeedsRehash(hash))
+
+	// после усиления параметров старый хэш все еще проверяется, но требует пересчета
+	stronger := testConfig()
+	stronger.Time = 2
+	upgraded := newTestUtils(t, stronger)
+
+	ok, err = upgraded.ComparePassword(ctx, "Passw0rd", hash)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, upgraded.NeedsRehash(hash))
+}
+
+func TestPasswordUtils_Bcrypt(t *testing.T) {
+	ctx := context.Background()
+	utils := newTestUtils(t, testConfig())
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("Passw0rd"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	ok, err := utils.ComparePassword(ctx, "Passw0rd", string(legacy))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = utils.ComparePassword(ctx, "wrong", string(legacy))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// выбран argon2id, поэтому bcrypt хэш пересчитывается при входе
+	require.True(t, utils.NeedsRehash(string(legacy)))
+
+	bcryptCfg := testConfig()
+	bcryptCfg.Algorithm = AlgorithmBcrypt
+	require.False(t, newTestUtils(t, bcryptCfg).NeedsRehash(string(legacy)))
+
+	bcryptCfg.BcryptCost = bcrypt.MinCost + 1
+	require.True(t, newTestUtils(t, bcryptCfg).NeedsRehash(string(legacy)))
+}
+
+func TestPasswordUtils_MalformedHash(t *testing.T) {
+	ctx := context.Background()
+	utils := newTestUtils(t, testConfig())
+
+	tests := []struct {
+		name        string
+		hash        string
+		expectedErr error
+	}{
+		{name: "Empty", hash: "", expectedErr: ErrMalformedHash},
+		{name: "Plain", hash: "password", expectedErr: ErrMalformedHash},
+		{name: "Truncated", hash: "$argon2id$v=19", expectedErr: ErrMalformedHash},
+		{name: "BadParams", hash: "$argon2id$v=19$m=x$c2FsdA$aGFzaA", expectedErr: ErrMalformedHash},
+		{name: "BadVersion", hash: "$argon2id$v=1$m=1024,t=1,p=1$c2FsdA$aGFzaA", expectedErr: ErrMalformedHash},
+		{name: "BadBase64", hash: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$!!!", expectedErr: ErrMalformedHash},
+		{name: "ZeroTime", hash: "$argon2id$v=19$m=64,t=0,p=1$c2FsdA$aGFzaA", expectedErr: ErrMalformedHash},
+		{name: "ZeroThreads", hash: "$argon2id$v=19$m=64,t=1,p=0$c2FsdA$aGFzaA", expectedErr: ErrMalformedHash},
+		{name: "ZeroMemory", hash: "$argon2id$v=19$m=0,t=1,p=1$c2FsdA$aGFzaA", expectedErr: ErrMalformedHash},
+		{name: "HugeMemory", hash: "$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdA$aGFzaA", expectedErr: ErrMalformedHash},
+		{name: "HugeKey", hash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$" + strings.Repeat("A", 2000),
+			expectedErr: ErrMalformedHash},
+		{name: "Unknown", hash: "$1$salt$hash", expectedErr: ErrUnknownAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := utils.ComparePassword(ctx, "Passw0rd", tt.hash)
+			require.False(t, ok)
+			require.True(t, errors.Is(err, tt.expectedErr), "expected error: %v, got: %v", tt.expectedErr, err)
+			require.False(t, utils.NeedsRehash(tt.hash))
+		})
+	}
+}
+
+func TestBreachChecker(t *testing.T) {
+	dir := t.TempDir()
+
+	sum := sha1.Sum([]byte("password123"))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	content := "0018A45C4D1DEF81644B54AB7F969B88D65:1\n" + hash[breachPrefixLen:] + ":123\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, hash[:breachPrefixLen]), []byte(content), 0o600))
+
+	checker := NewBreachChecker(dir)
+
+	breached, err := checker.IsBreached("password123")
+	require.NoError(t, err)
+	require.True(t, breached)
+
+	breached, err = checker.IsBreached("Str0ng-and-unique")
+	require.NoError(t, err)
+	require.False(t, breached)
+
+	// без каталога проверка выключена
+	breached, err = NewBreachChecker("").IsBreached("password123")
+	require.NoError(t, err)
+	require.False(t, breached)
+}
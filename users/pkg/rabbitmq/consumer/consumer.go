@@ -7,7 +7,6 @@ import (
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
-	"go.uber.org/zap"
 	"users/pkg/rabbitmq"
 )
 
@@ -147,10 +146,10 @@ func (bc *BatchConsumer) delivery() <-chan amqp.Delivery {
 		bc.QueueName(), "", false, false, false, false, nil,
 	)
 	if err != nil {
-		bc.logger.Error().Msgf(
-			"consume failed", zap.Error(err),
-			zap.String("queue", bc.QueueName()),
-		)
+		bc.logger.Error().
+			Err(err).
+			Str("queue", bc.QueueName()).
+			Msg("consume failed")
 
 		return nil
 	}
@@ -167,30 +166,29 @@ func (bc *BatchConsumer) processBatch(ctx context.Context, messages []amqp.Deliv
 
 	lastSuccessTag, handlerErrList, fatalErr := bc.handler.Handle(ctx, messages)
 	if fatalErr != nil {
-		bc.logger.Error().Msgf("batch handle failed", zap.Error(fatalErr))
+		bc.logger.Error().Err(fatalErr).Msg("batch handle failed")
 
 		lastMessage := messages[len(messages)-1]
 		if err := ch.Nack(lastMessage.DeliveryTag, true, true); err != nil {
-			bc.logger.Error().Msgf("nack failed", zap.Error(err))
+			bc.logger.Error().Err(err).Msg("nack failed")
 		}
 
 		return
 	}
 
 	for _, consumeErr := range handlerErrList {
-		bc.logger.Error().Msgf(
-			fmt.Sprintf("handle failed in queue %s", bc.QueueName()),
-			zap.Error(consumeErr.Err),
-			zap.ByteString("rabbit message", consumeErr.Msg.Body),
-		)
+		bc.logger.Error().
+			Err(consumeErr.Err).
+			Bytes("rabbit message", consumeErr.Msg.Body).
+			Msgf("handle failed in queue %s", bc.QueueName())
 		if err := ch.Reject(consumeErr.Msg.DeliveryTag, false); err != nil {
-			bc.logger.Error().Msgf("reject failed", zap.Error(err))
+			bc.logger.Error().Err(err).Msg("reject failed")
 		}
 	}
 
 	if lastSuccessTag > 0 {
 		if err := ch.Ack(lastSuccessTag, true); err != nil {
-			bc.logger.Error().Msgf("ack failed", zap.Error(err))
+			bc.logger.Error().Err(err).Msg("ack failed")
 		}
 
 		bc.logger.Debug().Msgf("processed batch from queue %s, last success tag %d", bc.QueueName(), lastSuccessTag)
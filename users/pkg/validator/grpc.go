@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCStatus позволяет grpc-серверу вернуть ошибку валидации как InvalidArgument
+// с деталями errdetails.BadRequest, даже если она обернута через fmt.Errorf
+func (e *ValidationError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+
+	details := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Message,
+		})
+	}
+
+	withDetails, err := st.WithDetails(details)
+	if err != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// FromGRPCError восстанавливает ошибку валидации из ответа grpc-сервиса
+func FromGRPCError(err error) (*ValidationError, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		return nil, false
+	}
+
+	validationErr := &ValidationError{}
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+
+		for _, v := range badRequest.GetFieldViolations() {
+			validationErr.Violations = append(validationErr.Violations, FieldViolation{
+				Field:   v.GetField(),
+				Message: v.GetDescription(),
+			})
+		}
+	}
+
+	if len(validationErr.Violations) == 0 {
+		return nil, false
+	}
+
+	return validationErr, true
+}